@@ -2,107 +2,912 @@ package main
 
 import (
 	"context"
-	"log"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"time"
+	// Embed the zone database; project and user time zones must resolve even
+	// on images without /usr/share/zoneinfo
+	_ "time/tzdata"
 
+	"github.com/civic-weave/backend/internal/analytics"
 	"github.com/civic-weave/backend/internal/api"
+	"github.com/civic-weave/backend/internal/apikeys"
+	"github.com/civic-weave/backend/internal/audit"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/auth/oidc"
+	"github.com/civic-weave/backend/internal/availability"
+	"github.com/civic-weave/backend/internal/avatars"
+	"github.com/civic-weave/backend/internal/badges"
+	"github.com/civic-weave/backend/internal/blobstore"
+	"github.com/civic-weave/backend/internal/calendar"
+	"github.com/civic-weave/backend/internal/config"
+	"github.com/civic-weave/backend/internal/connectors"
 	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/digests"
+	"github.com/civic-weave/backend/internal/documents"
+	"github.com/civic-weave/backend/internal/duplicates"
 	"github.com/civic-weave/backend/internal/enrollment"
+	"github.com/civic-weave/backend/internal/events"
+	"github.com/civic-weave/backend/internal/export"
+	"github.com/civic-weave/backend/internal/gallery"
+	"github.com/civic-weave/backend/internal/geo"
+	"github.com/civic-weave/backend/internal/i18n"
+	"github.com/civic-weave/backend/internal/idempotency"
+	"github.com/civic-weave/backend/internal/images"
+	"github.com/civic-weave/backend/internal/impact"
+	"github.com/civic-weave/backend/internal/imports"
+	"github.com/civic-weave/backend/internal/jobs"
+	"github.com/civic-weave/backend/internal/locations"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/matching"
+	"github.com/civic-weave/backend/internal/metrics"
+	"github.com/civic-weave/backend/internal/milestones"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/moderation"
+	"github.com/civic-weave/backend/internal/notifications"
+	"github.com/civic-weave/backend/internal/openapi"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/civic-weave/backend/internal/profiles"
+	"github.com/civic-weave/backend/internal/projects"
+	"github.com/civic-weave/backend/internal/quotas"
+	"github.com/civic-weave/backend/internal/ratelimit"
+	"github.com/civic-weave/backend/internal/ratings"
+	"github.com/civic-weave/backend/internal/regions"
+	"github.com/civic-weave/backend/internal/retention"
+	"github.com/civic-weave/backend/internal/reviews"
+	"github.com/civic-weave/backend/internal/sandbox"
+	"github.com/civic-weave/backend/internal/scanning"
+	"github.com/civic-weave/backend/internal/scheduler"
+	"github.com/civic-weave/backend/internal/search"
+	"github.com/civic-weave/backend/internal/shifts"
+	"github.com/civic-weave/backend/internal/skills"
+	"github.com/civic-weave/backend/internal/snapshot"
+	"github.com/civic-weave/backend/internal/teams"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/civic-weave/backend/internal/tracing"
+	"github.com/civic-weave/backend/internal/waivers"
+	"github.com/civic-weave/backend/internal/warehouse"
+	"github.com/civic-weave/backend/internal/webhooks"
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 )
 
 func main() {
-	// Load configuration from environment
-	dbHost := getEnv("DB_HOST", "localhost")
-	dbPort := getEnv("DB_PORT", "5432")
-	dbUser := getEnv("DB_USER", "postgres")
-	dbPassword := getEnv("DB_PASSWORD", "postgres")
-	dbName := getEnv("DB_NAME", "civic_weave")
-	port := getEnv("PORT", "8080")
+	// Load configuration from the environment and CONFIG_FILE
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		os.Exit(1)
+	}
+	if err := logging.Setup(cfg.Logging.Level, cfg.Logging.Format); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid logging configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(cfg, os.Args[2:]))
+	}
+	s3Settings := blobstore.S3Settings{
+		Region:          cfg.S3.Region,
+		Endpoint:        cfg.S3.Endpoint,
+		AccessKeyID:     cfg.S3.AccessKeyID,
+		SecretAccessKey: cfg.S3.SecretAccessKey,
+		SessionToken:    cfg.S3.SessionToken,
+	}
+	uploadScanner, err := scanning.ParseScanner(cfg.Scanning.Scanner, cfg.Scanning.VirusTotalAPIKey, cfg.Scanning.VirusTotalUpload)
+	if err != nil {
+		logging.Fatal("Invalid UPLOAD_SCANNER", "error", err)
+	}
+	if uploadScanner == nil {
+		slog.Warn("UPLOAD_SCANNER not set; uploads are not scanned for malware")
+	}
+	hourMilestones, err := milestones.ParseThresholds(cfg.Engagement.HourMilestones)
+	if err != nil {
+		logging.Fatal("HOUR_MILESTONES", "error", err)
+	}
+	blobStore, err := blobstore.ParseDestination(cfg.Storage.BlobStore, cfg.Storage.BlobPublicURL, s3Settings)
+	if err != nil {
+		logging.Fatal("Invalid BLOB_STORE", "error", err)
+	}
+	// Documents are private, so their store is never served directly
+	documentStore, err := blobstore.ParseDestination(cfg.Storage.DocumentStore, "", s3Settings)
+	if err != nil {
+		logging.Fatal("Invalid DOCUMENT_STORE", "error", err)
+	}
+	// Quarantined files are never served either
+	quarantineStore, err := blobstore.ParseDestination(cfg.Storage.QuarantineStore, "", s3Settings)
+	if err != nil {
+		logging.Fatal("Invalid QUARANTINE_STORE", "error", err)
+	}
+	documentSecret := []byte(cfg.Storage.DocumentSecret)
+	if len(documentSecret) == 0 {
+		slog.Warn("DOCUMENT_URL_SECRET not set; document links only work on this instance until it restarts")
+		documentSecret = make([]byte, 32)
+		if _, err := rand.Read(documentSecret); err != nil {
+			logging.Fatal("Failed to generate document URL secret", "error", err)
+		}
+	}
+
+	jwtSecret := []byte(cfg.Auth.JWTSecret)
+	if len(jwtSecret) == 0 {
+		slog.Warn("JWT_SECRET not set; sign-ins only last until this instance restarts and only work on it")
+		jwtSecret = make([]byte, 32)
+		if _, err := rand.Read(jwtSecret); err != nil {
+			logging.Fatal("Failed to generate JWT secret", "error", err)
+		}
+	}
+	tokens := auth.NewTokens(jwtSecret, cfg.Auth.TokenTTL, cfg.Auth.RefreshTTL)
+
+	// External sign-in providers, offered once their client IDs are set
+	var oidcConfigs []oidc.Config
+	if cfg.OIDC.GoogleClientID != "" {
+		oidcConfigs = append(oidcConfigs, oidc.Config{
+			Name:         "google",
+			IssuerURL:    "https://accounts.google.com",
+			ClientID:     cfg.OIDC.GoogleClientID,
+			ClientSecret: cfg.OIDC.GoogleClientSecret,
+		})
+	}
+	if cfg.OIDC.MicrosoftClientID != "" {
+		oidcConfigs = append(oidcConfigs, oidc.Config{
+			Name:         "microsoft",
+			IssuerURL:    "https://login.microsoftonline.com/" + cfg.OIDC.MicrosoftTenant + "/v2.0",
+			ClientID:     cfg.OIDC.MicrosoftClientID,
+			ClientSecret: cfg.OIDC.MicrosoftClientSecret,
+			TrustEmails:  cfg.OIDC.MicrosoftTrustEmails,
+		})
+	}
+	if cfg.OIDC.CustomClientID != "" {
+		oidcConfigs = append(oidcConfigs, oidc.Config{
+			Name:         cfg.OIDC.CustomName,
+			IssuerURL:    cfg.OIDC.CustomIssuerURL,
+			ClientID:     cfg.OIDC.CustomClientID,
+			ClientSecret: cfg.OIDC.CustomClientSecret,
+		})
+	}
+	oauthProviders := oidc.NewProviders(cfg.OIDC.CallbackBaseURL, oidcConfigs...)
+
+	// Export spans when a collector is configured; set up before the
+	// database so its queries are traced
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Endpoint:    cfg.Tracing.Endpoint,
+		Headers:     cfg.Tracing.Headers,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logging.Fatal("Failed to set up tracing", "error", err)
+	}
 
 	// Initialize database
-	db, err := database.NewPostgresDB(dbHost, dbPort, dbUser, dbPassword, dbName)
+	db, err := database.NewPostgresDB(cfg.Database.Host, strconv.Itoa(cfg.Database.Port), cfg.Database.User, cfg.Database.Password, cfg.Database.Name)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		logging.Fatal("Failed to connect to database", "error", err)
 	}
 	defer db.Close()
 
-	log.Println("Database connection established")
+	slog.Info("Database connection established")
+	migrateOnStartup(db, cfg.Database.AutoMigrate)
 
 	// Initialize services
-	enrollmentService := enrollment.NewService(db.DB)
+	organizationsService := organizations.NewService(db.DB)
+	projectsService := projects.NewService(db.DB)
+	teamsService := teams.NewService(db.DB)
+	geoService := geo.NewService(db.DB)
+	locationsService := locations.NewService(db.DB)
+	regionsService := regions.NewService(db.DB)
+	exportService := export.NewService(db.DB)
+	analyticsService := analytics.NewService(db.DB)
+	impactService := impact.NewService(db.DB)
+	shiftsService := shifts.NewService(db.DB)
+	calendarService := calendar.NewService(db.DB)
+	availabilityService := availability.NewService(db.DB)
+	badgesService := badges.NewService(db.DB)
+	ratingsService := ratings.NewService(db.DB)
+	waiversService := waivers.NewService(db.DB)
+	profilesService := profiles.NewService(db.DB)
+	authService := auth.NewService(db.DB)
+	moderationService := moderation.NewService(db.DB)
+	auditService := audit.NewService(db.DB)
+	jobsService := jobs.NewService(db.DB)
+	var mailer notifications.Mailer = notifications.LogMailer{}
+	switch cfg.Email.Provider {
+	case "sendgrid":
+		mailer = notifications.NewSendGridMailer(cfg.Email.SendGridAPIKey, cfg.Email.SendGridFrom)
+	case "smtp", "":
+		if cfg.SMTP.Host != "" {
+			mailer = notifications.SMTPMailer{
+				Host:     cfg.SMTP.Host,
+				Port:     cfg.SMTP.Port,
+				Username: cfg.SMTP.Username,
+				Password: cfg.SMTP.Password,
+				From:     cfg.SMTP.From,
+			}
+		}
+	}
+	// Never email made-up sandbox users, even after sandbox mode is off
+	mailer = sandbox.Mailer{Next: mailer}
+	// Deliver email in the background, retrying failures
+	mailer = notifications.NewQueuedMailer(jobsService, mailer)
+	notificationsService := notifications.NewService(db.DB, mailer)
+	eventBus := events.NewBus()
+	eventDispatcher := events.NewDispatcher(jobsService)
+	webhooksService := webhooks.NewService(db.DB, jobsService)
+	enrollmentService := enrollment.NewService(db.DB, mailer, eventDispatcher, cfg.Schedule.InvitationExpiry)
+
+	// Notify, audit and send webhooks as enrollments change, once per change
+	eventDispatcher.Subscribe(events.TypeEnrollmentChanged, "notifications", notificationsService.HandleEnrollmentChanged)
+	eventDispatcher.Subscribe(events.TypeEnrollmentChanged, "audit", auditService.HandleEnrollmentChanged)
+	eventDispatcher.Subscribe(events.TypeEnrollmentChanged, "webhooks", webhooksService.HandleEnrollmentChanged)
+	imagesService := images.NewService(db.DB, blobStore)
+	scanningService := scanning.NewService(db.DB, uploadScanner, blobStore, documentStore, quarantineStore, imagesService, mailer)
+	avatarsService := avatars.NewService(db.DB, blobStore, scanningService)
+	galleryService := gallery.NewService(db.DB, blobStore, scanningService)
+	documentsService := documents.NewService(db.DB, documentStore, documentSecret, scanningService)
+	reviewsService := reviews.NewService(db.DB, reviews.NewTermModerator(cfg.Engagement.ReviewBlockedTerms))
+	milestonesService := milestones.NewService(db.DB, hourMilestones, mailer)
+	digestsService := digests.NewService(db.DB, mailer)
+	schedulerService := scheduler.NewService(db.DB, jobsService)
+	connectorsService := connectors.NewService(projectsService)
+	importsService := imports.NewService(db.DB, skills.NewService(db.DB))
+	sandboxService := sandbox.NewService(db.DB, jobsService)
+	quotasService := quotas.NewService(db.DB)
+	idempotencyService := idempotency.NewService(db.DB, cfg.Schedule.IdempotencyKeyTTL)
+	duplicatesService := duplicates.NewService(db.DB, jobsService)
+	retentionService := retention.NewService(db.DB, jobsService, avatarsService, retention.Policy{
+		DryRun:                 cfg.Retention.DryRun,
+		AnonymizeInactiveAfter: cfg.Retention.AnonymizeInactiveAfter,
+		AuthEvents:             cfg.Retention.AuthEvents,
+		ExpiredInvitations:     cfg.Retention.ExpiredInvitations,
+		EndedSessions:          cfg.Retention.EndedSessions,
+		ClientEvents:           cfg.Retention.ClientEvents,
+	})
+	var searchService *search.Service
+	if cfg.Search.URL != "" {
+		searchClient := search.NewClient(cfg.Search.URL, cfg.Search.Index, cfg.Search.Username, cfg.Search.Password)
+		searchService = search.NewService(db.DB, searchClient, jobsService)
+	}
 
 	// Initialize API handlers
-	handler := api.NewHandler(db)
-	enrollmentHandler := api.NewEnrollmentHandler(enrollmentService)
+	handler := api.NewHandler(db, jobsService, tokens, mailer, notificationsService, webhooksService, eventBus, api.AuthLinks{
+		VerifyEmailURL:   cfg.Server.VerifyEmailURL,
+		ResetPasswordURL: cfg.Server.ResetPasswordURL,
+		OAuthRedirectURL: cfg.OIDC.RedirectURL,
+	}, oauthProviders, matching.Defaults{
+		SkillWeight:    cfg.Matching.SkillWeight,
+		DistanceWeight: cfg.Matching.DistanceWeight,
+		MaxDistanceKm:  cfg.Matching.MaxDistanceKm,
+		DistanceDecay:  cfg.Matching.DistanceDecay,
+		Limit:          cfg.Matching.Limit,
+		CacheTTL:       cfg.Matching.CacheTTL,
+		NotifyScore:    cfg.Matching.NotifyScore,
+	}, cfg.Auth.DefaultUserPassword)
+	enrollmentHandler := api.NewEnrollmentHandler(enrollmentService, organizationsService, authService)
+	organizationHandler := api.NewOrganizationHandler(organizationsService, mailer, cfg.Server.InviteAcceptURL)
+	teamHandler := api.NewTeamHandler(teamsService, projectsService, organizationsService, mailer)
+	mapHandler := api.NewMapHandler(geoService)
+	locationHandler := api.NewLocationHandler(locationsService, organizationsService)
+	regionHandler := api.NewRegionHandler(regionsService)
+	analyticsHandler := api.NewAnalyticsHandler(analyticsService, geoService, cfg.Engagement.EventSampleRate)
+	exportHandler := api.NewExportHandler(exportService)
+	impactHandler := api.NewImpactHandler(impactService, organizationsService)
+	shiftHandler := api.NewShiftHandler(shiftsService, organizationsService, mailer)
+	calendarHandler := api.NewCalendarHandler(calendarService)
+	notificationHandler := api.NewNotificationHandler(notificationsService)
+	streamHandler := api.NewStreamHandler(eventBus)
+	webhookHandler := api.NewWebhookHandler(webhooksService)
+	availabilityHandler := api.NewAvailabilityHandler(availabilityService, organizationsService)
+	badgeHandler := api.NewBadgeHandler(badgesService)
+	ratingHandler := api.NewRatingHandler(ratingsService, organizationsService)
+	reviewHandler := api.NewReviewHandler(reviewsService)
+	waiverHandler := api.NewWaiverHandler(waiversService, organizationsService)
+	avatarHandler := api.NewAvatarHandler(avatarsService)
+	galleryHandler := api.NewGalleryHandler(galleryService, organizationsService)
+	documentHandler := api.NewDocumentHandler(documentsService)
+	moderationHandler := api.NewModerationHandler(moderationService, auditService, mailer)
+	profileHandler := api.NewProfileHandler(profilesService, badgesService, milestonesService, organizationsService)
+	configHandler := api.NewConfigHandler(cfg)
+	jobHandler := api.NewJobHandler(jobsService)
+	scheduleHandler := api.NewScheduleHandler(schedulerService)
+	connectorHandler := api.NewConnectorHandler(connectorsService)
+	importHandler := api.NewImportHandler(importsService)
+	snapshotHandler := api.NewSnapshotHandler(snapshot.NewService(db.DB), searchService, cfg.Demo.AllowRestore)
+	sandboxParams := sandbox.Params{
+		Seed:                    int64(cfg.Sandbox.Seed),
+		Volunteers:              cfg.Sandbox.Volunteers,
+		Coordinators:            cfg.Sandbox.Coordinators,
+		Projects:                cfg.Sandbox.Projects,
+		EnrollmentsPerVolunteer: cfg.Sandbox.EnrollmentsPerVolunteer,
+	}
+	sandboxHandler := api.NewSandboxHandler(sandboxService, sandboxParams)
+	roleHandler := api.NewRoleHandler(authService)
+	usageHandler := api.NewUsageHandler(quotasService)
+	duplicateHandler := api.NewDuplicateHandler(duplicatesService)
+	retentionHandler := api.NewRetentionHandler(retentionService)
+	var searchHandler *api.SearchHandler
+	if searchService != nil {
+		searchHandler = api.NewSearchHandler(searchService)
+	}
 
 	// Setup router
 	r := mux.NewRouter()
 
+	// Serve uploads kept on local disk; other stores serve their own
+	if dirStore, ok := blobStore.(*blobstore.DirStore); ok {
+		r.PathPrefix(blobstore.LocalPath).Handler(dirStore)
+	}
+
+	// Prometheus metrics, outside /api so scrapes skip its middleware
+	if cfg.Metrics.Enabled {
+		metrics.RegisterDBStats(db.DB)
+		r.Handle("/metrics", metrics.Handler(cfg.Metrics.Token)).Methods("GET")
+	}
+
 	// API routes
 	apiRouter := r.PathPrefix("/api").Subrouter()
 
+	// Resolve the client IP, believing X-Forwarded-For only from trusted proxies
+	trustedProxies, err := auth.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		logging.Fatal("Invalid TRUSTED_PROXIES", "error", err)
+	}
+	apiRouter.Use(trustedProxies.Middleware)
+
+	// Count requests and time them by route
+	apiRouter.Use(metrics.Middleware)
+
+	// Trace each request, continuing any trace the caller started
+	apiRouter.Use(tracing.Middleware)
+
+	// Negotiate the response language from Accept-Language
+	apiRouter.Use(i18n.Middleware)
+
+	// Authenticate partner API keys; a key scopes the request to its organization
+	apiRouter.Use(apikeys.Middleware(organizationsService.AuthenticateAPIKey))
+
+	// Require a bearer token, except on public routes and for API keys, and
+	// refuse tokens of ended sessions
+	sessionCache := auth.NewSessionCache(authService.SessionActive, cfg.Auth.SessionCheckTTL)
+	apiRouter.Use(auth.Middleware(tokens, sessionCache))
+
+	// Throttle bursts per client IP, user and API key, most strictly on
+	// sign-in and other auth routes
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Store == "redis" {
+		if rateLimitStore, err = ratelimit.NewRedisStore(cfg.Redis.URL); err != nil {
+			logging.Fatal("Invalid REDIS_URL", "error", err)
+		}
+	}
+	apiRouter.Use(ratelimit.Middleware(rateLimitStore, ratelimit.Policy{
+		Auth:  ratelimit.Limit{Requests: cfg.RateLimit.AuthRequests, Per: cfg.RateLimit.Window},
+		Read:  ratelimit.Limit{Requests: cfg.RateLimit.ReadRequests, Per: cfg.RateLimit.Window},
+		Write: ratelimit.Limit{Requests: cfg.RateLimit.WriteRequests, Per: cfg.RateLimit.Window},
+	}))
+
+	// Count requests per API key and user, refusing them once a quota is
+	// used up
+	apiRouter.Use(quotas.Middleware(quotasService,
+		quotas.Limits{Daily: cfg.Quotas.APIKeyDaily, Monthly: cfg.Quotas.APIKeyMonthly},
+		quotas.Limits{Daily: cfg.Quotas.UserDaily, Monthly: cfg.Quotas.UserMonthly}))
+
+	// Scope requests to an organization (X-Tenant header or subdomain) the
+	// signed-in user belongs to; platform admins may pick any
+	tenantCaller := func(r *http.Request) (tenant.Caller, error) {
+		userID := auth.UserID(r)
+		if userID == "" {
+			return tenant.Caller{Anonymous: true}, nil
+		}
+		role, err := authService.GetRole(userID)
+		if err != nil && err != auth.ErrUserNotFound {
+			return tenant.Caller{}, err
+		}
+		if role == auth.RoleAdmin {
+			return tenant.Caller{Unrestricted: true}, nil
+		}
+		memberships, err := organizationsService.GetUserOrganizationIDs(userID)
+		if err != nil {
+			return tenant.Caller{}, err
+		}
+		return tenant.Caller{Memberships: memberships}, nil
+	}
+	tenantResolver := tenant.NewResolver(organizationsService.ResolveTenant, tenantCaller, cfg.Tenant.BaseDomain)
+	apiRouter.Use(tenantResolver.Middleware)
+
+	// Refuse requests made on behalf of suspended users
+	apiRouter.Use(moderation.Middleware(moderationService.IsSuspended))
+
+	// Routes limited to platform roles wrap their handler in roles.Require
+	roles := auth.NewRoles(authService.GetRole)
+
+	// Analytics, exports and imports also serve admins of the request's
+	// organization, and imports partner API keys, which apikeys.Middleware
+	// has already checked for volunteers:write
+	tenantAdmin := func(r *http.Request) (bool, error) {
+		userID, tenantID := auth.UserID(r), tenant.FromRequest(r)
+		if userID == "" || tenantID == "" {
+			return false, nil
+		}
+		role, err := organizationsService.GetMemberRole(tenantID, userID)
+		return organizations.RoleAtLeast(role, models.OrgRoleAdmin), err
+	}
+	importer := func(r *http.Request) (bool, error) {
+		if apikeys.FromRequest(r) != nil {
+			return true, nil
+		}
+		return tenantAdmin(r)
+	}
+
 	// Auth routes
-	apiRouter.HandleFunc("/users", handler.GetUsers).Methods("GET")
+	apiRouter.HandleFunc("/users", roles.Require(auth.PermListUsers, handler.GetUsers)).Methods("GET")
+	apiRouter.HandleFunc("/users/{id}/avatar", avatarHandler.UploadAvatar).Methods("POST")
+	apiRouter.HandleFunc("/users/{id}/avatar", avatarHandler.DeleteAvatar).Methods("DELETE")
 	apiRouter.HandleFunc("/auth/login", handler.Login).Methods("POST")
 	apiRouter.HandleFunc("/auth/register", handler.Register).Methods("POST")
+	apiRouter.HandleFunc("/auth/change-password", handler.ChangePassword).Methods("POST")
+	apiRouter.HandleFunc("/auth/refresh", handler.RefreshToken).Methods("POST")
+	apiRouter.HandleFunc("/auth/verify", handler.VerifyEmail).Methods("POST")
+	apiRouter.HandleFunc("/auth/resend-verification", handler.ResendVerification).Methods("POST")
+	apiRouter.HandleFunc("/auth/forgot-password", handler.ForgotPassword).Methods("POST")
+	apiRouter.HandleFunc("/auth/reset-password", handler.ResetPassword).Methods("POST")
+	apiRouter.HandleFunc("/auth/oauth/providers", handler.GetOAuthProviders).Methods("GET")
+	apiRouter.HandleFunc("/auth/oauth/{provider}/login", handler.OAuthLogin).Methods("GET")
+	apiRouter.HandleFunc("/auth/oauth/{provider}/callback", handler.OAuthCallback).Methods("GET")
+	apiRouter.HandleFunc("/auth/logout", handler.Logout).Methods("POST")
+	apiRouter.HandleFunc("/auth/sessions", handler.GetSessions).Methods("GET")
+	apiRouter.HandleFunc("/auth/sessions/{sessionId}", handler.RevokeSession).Methods("DELETE")
+	apiRouter.HandleFunc("/admin/users/{id}/sessions", roles.Require(auth.PermAuditSessions, handler.GetUserSessions)).Methods("GET")
 	apiRouter.HandleFunc("/health", handler.Health).Methods("GET")
+	spec := openapi.NewSpec(openapi.Info{Title: "Civic Weave API", Version: "1.0"}, api.Operations, auth.IsPublicRoute)
+	apiRouter.HandleFunc("/openapi.json", spec.ServeJSON).Methods("GET")
+	apiRouter.HandleFunc("/docs", spec.UI("/api/openapi.json")).Methods("GET")
+	apiRouter.HandleFunc("/admin/config", roles.Require(auth.PermViewConfig, configHandler.GetConfig)).Methods("GET")
 
 	// Skills routes
 	apiRouter.HandleFunc("/skills", handler.GetSkills).Methods("GET")
 	apiRouter.HandleFunc("/skills", handler.CreateSkill).Methods("POST")
+	apiRouter.HandleFunc("/skills/{id}/aliases", handler.CreateSkillAlias).Methods("POST")
 	apiRouter.HandleFunc("/volunteers/{id}/skills", handler.GetVolunteerSkills).Methods("GET")
 	apiRouter.HandleFunc("/volunteers/{id}/skills", handler.UpdateVolunteerSkills).Methods("PUT")
+	apiRouter.HandleFunc("/volunteers/{id}/badges", badgeHandler.GetVolunteerBadges).Methods("GET")
+	apiRouter.HandleFunc("/volunteers/{id}/profile", profileHandler.GetProfile).Methods("GET")
+	if cfg.Features.PublicProfiles {
+		apiRouter.HandleFunc("/volunteers/{id}/public-profile", profileHandler.GetPublicProfile).Methods("GET")
+	}
+	apiRouter.HandleFunc("/volunteers/{id}/privacy", profileHandler.GetPrivacy).Methods("GET")
+	apiRouter.HandleFunc("/volunteers/{id}/privacy", profileHandler.UpdatePrivacy).Methods("PUT")
+	apiRouter.HandleFunc("/volunteers/{id}/references", profileHandler.GetReferences).Methods("GET")
+	apiRouter.HandleFunc("/volunteers/{id}/references/{referenceId}", profileHandler.RespondToReference).Methods("PUT")
+	apiRouter.HandleFunc("/projects/{id}/volunteers/{volunteerId}/references", profileHandler.CreateReference).Methods("POST")
+	apiRouter.HandleFunc("/admin/references/{referenceId}", roles.Require(auth.PermModerate, profileHandler.RemoveReference)).Methods("DELETE")
+
+	// Moderation routes
+	apiRouter.HandleFunc("/reports", moderationHandler.CreateReport).Methods("POST")
+	apiRouter.HandleFunc("/reports/categories", moderationHandler.GetReportCategories).Methods("GET")
+	apiRouter.HandleFunc("/admin/reports", roles.Require(auth.PermModerate, moderationHandler.GetReports)).Methods("GET")
+	apiRouter.HandleFunc("/admin/reports/{reportId}", roles.Require(auth.PermModerate, moderationHandler.GetReport)).Methods("GET")
+	apiRouter.HandleFunc("/admin/reports/{reportId}/actions", roles.Require(auth.PermModerate, moderationHandler.ResolveReport)).Methods("POST")
+	apiRouter.HandleFunc("/admin/users/{id}/suspension", roles.Require(auth.PermModerate, moderationHandler.Unsuspend)).Methods("DELETE")
+	apiRouter.HandleFunc("/admin/audit-log", roles.Require(auth.PermModerate, moderationHandler.GetAuditLog)).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/volunteers/{volunteerId}/skills/{skillId}/verification", handler.VerifyVolunteerSkill).Methods("PUT")
+	apiRouter.HandleFunc("/users/{id}/locale", handler.UpdateUserLocale).Methods("PUT")
+	apiRouter.HandleFunc("/users/{id}/notifications", notificationHandler.GetNotifications).Methods("GET")
+	apiRouter.HandleFunc("/users/{id}/notifications/read", notificationHandler.MarkAllRead).Methods("PUT")
+	apiRouter.HandleFunc("/users/{id}/notifications/{notificationId}/read", notificationHandler.MarkRead).Methods("PUT")
 	apiRouter.HandleFunc("/volunteers/{id}/location", handler.UpdateVolunteerLocation).Methods("PUT")
+	apiRouter.HandleFunc("/volunteers/{id}/locations", locationHandler.GetLocations).Methods("GET")
+	apiRouter.HandleFunc("/volunteers/{id}/locations", locationHandler.CreateLocation).Methods("POST")
+	apiRouter.HandleFunc("/volunteers/{id}/locations/{locationId}", locationHandler.UpdateLocation).Methods("PUT")
+	apiRouter.HandleFunc("/volunteers/{id}/locations/{locationId}", locationHandler.DeleteLocation).Methods("DELETE")
 
 	// Projects routes
 	apiRouter.HandleFunc("/projects", handler.GetProjects).Methods("GET")
-	apiRouter.HandleFunc("/projects", handler.CreateProject).Methods("POST")
+	apiRouter.HandleFunc("/projects", idempotencyService.Wrap(handler.CreateProject)).Methods("POST")
+	apiRouter.HandleFunc("/projects/near", handler.GetProjectsNear).Methods("GET")
 	apiRouter.HandleFunc("/projects/{id}", handler.GetProject).Methods("GET")
 	apiRouter.HandleFunc("/projects/{id}", handler.UpdateProjectDetails).Methods("PUT")
 	apiRouter.HandleFunc("/projects/{id}/skills", handler.GetProjectSkills).Methods("GET")
 	apiRouter.HandleFunc("/projects/{id}/skills", handler.UpdateProjectSkills).Methods("PUT")
 	apiRouter.HandleFunc("/projects/{id}/status", handler.UpdateProjectStatus).Methods("PUT")
+	apiRouter.HandleFunc("/projects/{id}/translations", handler.GetProjectTranslations).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/translations/{locale}", handler.SetProjectTranslation).Methods("PUT")
+	apiRouter.HandleFunc("/projects/{id}/translations/{locale}", handler.DeleteProjectTranslation).Methods("DELETE")
+	apiRouter.HandleFunc("/coordinators/{id}/dashboard", handler.GetCoordinatorDashboard).Methods("GET")
+
+	// Full-text project search, when an index is configured
+	if searchHandler != nil {
+		apiRouter.HandleFunc("/search/projects", searchHandler.SearchProjects).Methods("GET")
+		apiRouter.HandleFunc("/admin/search/reindex", roles.Require(auth.PermReindexSearch, searchHandler.Reindex)).Methods("POST")
+	}
+
+	// Impact metric routes
+	apiRouter.HandleFunc("/projects/{id}/metrics", impactHandler.GetProjectMetrics).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/metrics", impactHandler.CreateMetric).Methods("POST")
+	apiRouter.HandleFunc("/projects/{id}/metrics/{metricId}", impactHandler.DeleteMetric).Methods("DELETE")
+	apiRouter.HandleFunc("/projects/{id}/metrics/{metricId}/entries", impactHandler.GetEntries).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/metrics/{metricId}/entries", impactHandler.LogEntry).Methods("POST")
+	apiRouter.HandleFunc("/projects/{id}/shifts", shiftHandler.GetProjectShifts).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/shifts", shiftHandler.CreateShift).Methods("POST")
+	apiRouter.HandleFunc("/projects/{id}/shifts/roster", shiftHandler.GetRoster).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/shifts/auto-assign", shiftHandler.ProposeAssignments).Methods("POST")
+	apiRouter.HandleFunc("/projects/{id}/shifts/auto-assign/accept", shiftHandler.AcceptAssignments).Methods("POST")
+	apiRouter.HandleFunc("/projects/{id}/shifts/coverage", shiftHandler.GetCoverageRequests).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/shifts/coverage/{requestId}/claim", shiftHandler.ClaimCoverage).Methods("POST")
+	apiRouter.HandleFunc("/projects/{id}/shifts/coverage/{requestId}", shiftHandler.CancelCoverage).Methods("DELETE")
+	apiRouter.HandleFunc("/projects/{id}/shifts/{shiftId}", shiftHandler.GetShift).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/shifts/{shiftId}", shiftHandler.UpdateShift).Methods("PUT")
+	apiRouter.HandleFunc("/projects/{id}/shifts/{shiftId}", shiftHandler.DeleteShift).Methods("DELETE")
+	apiRouter.HandleFunc("/projects/{id}/shifts/{shiftId}/signups", shiftHandler.SignUp).Methods("POST")
+	apiRouter.HandleFunc("/projects/{id}/shifts/{shiftId}/signups/{volunteerId}", shiftHandler.CancelSignup).Methods("DELETE")
+	apiRouter.HandleFunc("/projects/{id}/shifts/{shiftId}/coverage", shiftHandler.RequestCoverage).Methods("POST")
+	apiRouter.HandleFunc("/volunteers/{id}/shifts", shiftHandler.GetVolunteerShifts).Methods("GET")
+	apiRouter.HandleFunc("/volunteers/{id}/calendar.ics", calendarHandler.GetFeed).Methods("GET")
+	apiRouter.HandleFunc("/volunteers/{id}/calendar/token", calendarHandler.RotateToken).Methods("POST")
+	apiRouter.HandleFunc("/volunteers/{id}/calendar/token", calendarHandler.RevokeToken).Methods("DELETE")
+	apiRouter.HandleFunc("/volunteers/{id}/availability", availabilityHandler.GetRules).Methods("GET")
+	apiRouter.HandleFunc("/volunteers/{id}/availability", availabilityHandler.CreateRule).Methods("POST")
+	apiRouter.HandleFunc("/volunteers/{id}/availability/slots", availabilityHandler.GetSlots).Methods("GET")
+	apiRouter.HandleFunc("/volunteers/{id}/availability/blackouts", availabilityHandler.GetBlackouts).Methods("GET")
+	apiRouter.HandleFunc("/volunteers/{id}/availability/blackouts", availabilityHandler.CreateBlackout).Methods("POST")
+	apiRouter.HandleFunc("/volunteers/{id}/availability/blackouts/{blackoutId}", availabilityHandler.DeleteBlackout).Methods("DELETE")
+	apiRouter.HandleFunc("/volunteers/{id}/availability/{ruleId}", availabilityHandler.DeleteRule).Methods("DELETE")
+	apiRouter.HandleFunc("/holidays", availabilityHandler.GetHolidays).Methods("GET")
+	apiRouter.HandleFunc("/holidays", roles.Require(auth.PermManageHolidays, availabilityHandler.CreateHoliday)).Methods("POST")
+	apiRouter.HandleFunc("/holidays/{date}", roles.Require(auth.PermManageHolidays, availabilityHandler.DeleteHoliday)).Methods("DELETE")
+
+	// Map routes
+	apiRouter.HandleFunc("/map/clusters", mapHandler.GetClusters).Methods("GET")
+
+	// Region routes
+	apiRouter.HandleFunc("/regions", regionHandler.GetRegions).Methods("GET")
+	apiRouter.HandleFunc("/regions", roles.Require(auth.PermManageRegions, regionHandler.CreateRegion)).Methods("POST")
+	apiRouter.HandleFunc("/regions/lookup", regionHandler.LookupRegions).Methods("GET")
+	apiRouter.HandleFunc("/regions/analytics", roles.RequireOr(auth.PermViewAnalytics, tenantAdmin, regionHandler.GetRegionAnalytics)).Methods("GET")
+	apiRouter.HandleFunc("/regions/{id}", roles.Require(auth.PermManageRegions, regionHandler.DeleteRegion)).Methods("DELETE")
+
+	// Analytics routes
+	apiRouter.HandleFunc("/admin/analytics/volunteer-heatmap", roles.RequireOr(auth.PermViewAnalytics, tenantAdmin, analyticsHandler.GetVolunteerHeatmap)).Methods("GET")
+	apiRouter.HandleFunc("/admin/analytics/funnel", roles.RequireOr(auth.PermViewAnalytics, tenantAdmin, analyticsHandler.GetFunnel)).Methods("GET")
+	apiRouter.HandleFunc("/admin/analytics/retention", roles.RequireOr(auth.PermViewAnalytics, tenantAdmin, analyticsHandler.GetRetention)).Methods("GET")
+	if cfg.Features.ClientEvents {
+		apiRouter.HandleFunc("/events", analyticsHandler.RecordEvents).Methods("POST")
+	}
+
+	// Live event stream
+	apiRouter.HandleFunc("/events", streamHandler.Stream).Methods("GET")
+	if cfg.Features.Leaderboards {
+		apiRouter.HandleFunc("/leaderboards", analyticsHandler.GetLeaderboards).Methods("GET")
+		apiRouter.HandleFunc("/volunteers/{id}/leaderboard", analyticsHandler.UpdateLeaderboardOptIn).Methods("PUT")
+	}
+
+	// Export routes
+	apiRouter.HandleFunc("/admin/exports/{dataset}", roles.RequireOr(auth.PermViewAnalytics, tenantAdmin, exportHandler.ExportDataset)).Methods("GET")
+
+	// Team routes
+	apiRouter.HandleFunc("/projects/{id}/teams", teamHandler.GetProjectTeams).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/teams", teamHandler.CreateTeam).Methods("POST")
+	apiRouter.HandleFunc("/teams/{teamId}", teamHandler.GetTeam).Methods("GET")
+	apiRouter.HandleFunc("/teams/{teamId}/lead", teamHandler.UpdateTeamLead).Methods("PUT")
+	apiRouter.HandleFunc("/teams/{teamId}/members/{volunteerId}", teamHandler.AssignTeamMember).Methods("PUT")
+	apiRouter.HandleFunc("/teams/{teamId}/members/{volunteerId}", teamHandler.RemoveTeamMember).Methods("DELETE")
+	apiRouter.HandleFunc("/teams/{teamId}/messages", teamHandler.GetTeamMessages).Methods("GET")
+	apiRouter.HandleFunc("/teams/{teamId}/messages", teamHandler.BroadcastTeamMessage).Methods("POST")
 
 	// Matching routes
-	apiRouter.HandleFunc("/projects/{id}/matches", handler.FindMatchesForProject).Methods("GET")
-	apiRouter.HandleFunc("/volunteers/{id}/matches", handler.FindMatchesForVolunteer).Methods("GET")
-	apiRouter.HandleFunc("/admin/refresh-vectors", handler.RefreshSkillVectors).Methods("POST")
+	apiRouter.HandleFunc("/projects/{id}/matches", roles.Require(auth.PermViewVolunteerMatches, handler.FindMatchesForProject)).Methods("GET")
+	apiRouter.HandleFunc("/volunteers/{id}/matches", roles.Require(auth.PermViewProjectMatches, handler.FindMatchesForVolunteer)).Methods("GET")
+	apiRouter.HandleFunc("/admin/refresh-vectors", roles.Require(auth.PermRefreshMatching, handler.RefreshSkillVectors)).Methods("POST")
+	apiRouter.HandleFunc("/admin/recompute-matches", roles.Require(auth.PermRefreshMatching, handler.GetMatchRecomputations)).Methods("GET")
+	apiRouter.HandleFunc("/admin/recompute-matches", roles.Require(auth.PermRefreshMatching, handler.RecomputeMatches)).Methods("POST")
+
+	// Background job routes
+	apiRouter.HandleFunc("/admin/jobs", roles.Require(auth.PermManageJobs, jobHandler.GetJobs)).Methods("GET")
+	apiRouter.HandleFunc("/admin/jobs/status", roles.Require(auth.PermManageJobs, jobHandler.GetJobStatus)).Methods("GET")
+	apiRouter.HandleFunc("/admin/jobs/{jobId}/retry", roles.Require(auth.PermManageJobs, jobHandler.RetryJob)).Methods("POST")
+	apiRouter.HandleFunc("/admin/schedule", roles.Require(auth.PermManageJobs, scheduleHandler.GetTasks)).Methods("GET")
+	apiRouter.HandleFunc("/admin/schedule/runs", roles.Require(auth.PermManageJobs, scheduleHandler.GetRuns)).Methods("GET")
+
+	// Webhook routes
+	apiRouter.HandleFunc("/admin/webhooks", roles.Require(auth.PermManageWebhooks, webhookHandler.GetWebhooks)).Methods("GET")
+	apiRouter.HandleFunc("/admin/webhooks", roles.Require(auth.PermManageWebhooks, webhookHandler.CreateWebhook)).Methods("POST")
+	apiRouter.HandleFunc("/admin/webhooks/{webhookId}", roles.Require(auth.PermManageWebhooks, webhookHandler.UpdateWebhook)).Methods("PUT")
+	apiRouter.HandleFunc("/admin/webhooks/{webhookId}", roles.Require(auth.PermManageWebhooks, webhookHandler.DeleteWebhook)).Methods("DELETE")
+	apiRouter.HandleFunc("/admin/webhooks/{webhookId}/deliveries", roles.Require(auth.PermManageWebhooks, webhookHandler.GetDeliveries)).Methods("GET")
+
+	// Demo snapshots
+	apiRouter.HandleFunc("/admin/snapshot", roles.Require(auth.PermManageSnapshots, snapshotHandler.ExportSnapshot)).Methods("GET")
+	apiRouter.HandleFunc("/admin/snapshot", roles.Require(auth.PermManageSnapshots, snapshotHandler.RestoreSnapshot)).Methods("PUT")
+
+	// Sandbox data
+	apiRouter.HandleFunc("/admin/sandbox", roles.Require(auth.PermManageSandbox, sandboxHandler.GetSandbox)).Methods("GET")
+	apiRouter.HandleFunc("/admin/sandbox", roles.Require(auth.PermManageSandbox, sandboxHandler.GenerateSandbox)).Methods("POST")
+	apiRouter.HandleFunc("/admin/sandbox", roles.Require(auth.PermManageSandbox, sandboxHandler.PurgeSandbox)).Methods("DELETE")
+
+	// Roles by email domain
+	apiRouter.HandleFunc("/admin/domain-rules", roles.Require(auth.PermManageRoles, roleHandler.GetDomainRules)).Methods("GET")
+	apiRouter.HandleFunc("/admin/domain-rules", roles.Require(auth.PermManageRoles, roleHandler.CreateDomainRule)).Methods("POST")
+	apiRouter.HandleFunc("/admin/domain-rules/{id}", roles.Require(auth.PermManageRoles, roleHandler.DeleteDomainRule)).Methods("DELETE")
+	apiRouter.HandleFunc("/admin/role-requests/pending", roles.Require(auth.PermManageRoles, roleHandler.GetPendingRoleRequests)).Methods("GET")
+	apiRouter.HandleFunc("/admin/role-requests/{id}", roles.Require(auth.PermManageRoles, roleHandler.ReviewRoleRequest)).Methods("PUT")
+
+	// API usage
+	apiRouter.HandleFunc("/admin/usage", roles.Require(auth.PermViewUsage, usageHandler.GetTopConsumers)).Methods("GET")
+
+	// Duplicate accounts
+	apiRouter.HandleFunc("/admin/duplicates", roles.Require(auth.PermManageDuplicates, duplicateHandler.GetDuplicates)).Methods("GET")
+	apiRouter.HandleFunc("/admin/duplicates/detect", roles.Require(auth.PermManageDuplicates, duplicateHandler.DetectDuplicates)).Methods("POST")
+	apiRouter.HandleFunc("/admin/duplicates/{id}", roles.Require(auth.PermManageDuplicates, duplicateHandler.DismissDuplicate)).Methods("DELETE")
+	apiRouter.HandleFunc("/admin/users/{id}/merge", roles.Require(auth.PermManageDuplicates, duplicateHandler.MergeAccounts)).Methods("POST")
+
+	// Data retention
+	apiRouter.HandleFunc("/admin/retention", roles.Require(auth.PermManageRetention, retentionHandler.GetPolicy)).Methods("GET")
+	apiRouter.HandleFunc("/admin/retention/runs", roles.Require(auth.PermManageRetention, retentionHandler.GetRuns)).Methods("GET")
+	apiRouter.HandleFunc("/admin/retention/runs", roles.Require(auth.PermManageRetention, retentionHandler.RunRetention)).Methods("POST")
+
+	// Bulk volunteer import
+	apiRouter.HandleFunc("/admin/volunteers/import", roles.RequireOr(auth.PermImportVolunteers, importer, importHandler.ImportVolunteers)).Methods("POST")
+
+	// Inbound connectors for external volunteer platforms
+	apiRouter.HandleFunc("/connectors/{source}/webhook", connectorHandler.Webhook).Methods("POST")
 
 	// Enrollment routes
-	apiRouter.HandleFunc("/enrollments", enrollmentHandler.CreateEnrollment).Methods("POST")
+	apiRouter.HandleFunc("/enrollments", idempotencyService.Wrap(enrollmentHandler.CreateEnrollment)).Methods("POST")
 	apiRouter.HandleFunc("/projects/{projectId}/enrollments", enrollmentHandler.GetProjectEnrollments).Methods("GET")
+	apiRouter.HandleFunc("/projects/{projectId}/waitlist", enrollmentHandler.GetWaitlist).Methods("GET")
 	apiRouter.HandleFunc("/volunteers/{volunteerId}/enrollments", enrollmentHandler.GetVolunteerEnrollments).Methods("GET")
 	apiRouter.HandleFunc("/enrollments/{enrollmentId}/status", enrollmentHandler.UpdateEnrollmentStatus).Methods("PUT")
+	apiRouter.HandleFunc("/enrollments/bulk", enrollmentHandler.BulkUpdateEnrollments).Methods("POST")
+	apiRouter.HandleFunc("/enrollments/{enrollmentId}/complete", enrollmentHandler.CompleteEnrollment).Methods("PUT")
 	apiRouter.HandleFunc("/volunteers/{volunteerId}/projects/{projectId}/enrollment-status", enrollmentHandler.CheckEnrollmentStatus).Methods("GET")
+	apiRouter.HandleFunc("/enrollments/{enrollmentId}/hours", enrollmentHandler.LogHours).Methods("POST")
+	apiRouter.HandleFunc("/enrollments/{enrollmentId}/attendance", enrollmentHandler.GetAttendance).Methods("GET")
+	apiRouter.HandleFunc("/enrollments/{enrollmentId}/checkin", enrollmentHandler.CheckIn).Methods("POST")
+	apiRouter.HandleFunc("/enrollments/{enrollmentId}/checkout", enrollmentHandler.CheckOut).Methods("POST")
 	apiRouter.HandleFunc("/enrollments/pending", enrollmentHandler.GetPendingEnrollments).Methods("GET")
 
+	// Waiver routes
+	apiRouter.HandleFunc("/organizations/{id}/waivers", waiverHandler.GetOrganizationWaivers).Methods("GET")
+	apiRouter.HandleFunc("/organizations/{id}/waivers", waiverHandler.CreateOrganizationWaiver).Methods("POST")
+	apiRouter.HandleFunc("/projects/{id}/waivers", waiverHandler.GetProjectWaivers).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/waivers", waiverHandler.CreateProjectWaiver).Methods("POST")
+	apiRouter.HandleFunc("/waivers/{waiverId}", waiverHandler.UpdateWaiver).Methods("PUT")
+	apiRouter.HandleFunc("/waivers/{waiverId}", waiverHandler.ArchiveWaiver).Methods("DELETE")
+	apiRouter.HandleFunc("/waivers/{waiverId}/signatures", waiverHandler.GetWaiverSignatures).Methods("GET")
+	apiRouter.HandleFunc("/waivers/{waiverId}/signatures", waiverHandler.SignWaiver).Methods("POST")
+
+	// Rating routes
+	apiRouter.HandleFunc("/ratings/tags", ratingHandler.GetRatingTags).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/ratings", ratingHandler.GetProjectRatings).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/volunteers/{volunteerId}/rating", ratingHandler.RateVolunteer).Methods("PUT")
+
+	// Document routes
+	apiRouter.HandleFunc("/volunteers/{id}/documents", documentHandler.GetVolunteerDocuments).Methods("GET")
+	apiRouter.HandleFunc("/volunteers/{id}/documents", documentHandler.UploadCertification).Methods("POST")
+	apiRouter.HandleFunc("/enrollments/{enrollmentId}/documents", documentHandler.GetEnrollmentDocuments).Methods("GET")
+	apiRouter.HandleFunc("/enrollments/{enrollmentId}/documents", documentHandler.UploadWaiver).Methods("POST")
+	apiRouter.HandleFunc("/documents/{documentId}", documentHandler.GetDocument).Methods("GET")
+	apiRouter.HandleFunc("/documents/{documentId}", documentHandler.DeleteDocument).Methods("DELETE")
+	apiRouter.HandleFunc("/documents/{documentId}/content", documentHandler.GetDocumentContent).Methods("GET")
+
+	// Gallery routes
+	apiRouter.HandleFunc("/projects/{id}/photos", galleryHandler.GetPhotos).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/photos", galleryHandler.UploadPhoto).Methods("POST")
+	apiRouter.HandleFunc("/projects/{id}/photos/order", galleryHandler.ReorderPhotos).Methods("PUT")
+	apiRouter.HandleFunc("/projects/{id}/photos/{photoId}", galleryHandler.UpdatePhoto).Methods("PUT")
+	apiRouter.HandleFunc("/projects/{id}/photos/{photoId}", galleryHandler.DeletePhoto).Methods("DELETE")
+
+	// Review routes
+	if cfg.Features.Reviews {
+		apiRouter.HandleFunc("/projects/{id}/reviews", reviewHandler.GetProjectReviews).Methods("GET")
+		apiRouter.HandleFunc("/projects/{id}/review", reviewHandler.SubmitReview).Methods("PUT")
+		apiRouter.HandleFunc("/admin/reviews/pending", roles.Require(auth.PermModerateReviews, reviewHandler.GetPendingReviews)).Methods("GET")
+		apiRouter.HandleFunc("/admin/reviews/{reviewId}/moderation", roles.Require(auth.PermModerateReviews, reviewHandler.ModerateReview)).Methods("PUT")
+	}
+
+	// Organization routes
+	apiRouter.HandleFunc("/organizations", organizationHandler.CreateOrganization).Methods("POST")
+	apiRouter.HandleFunc("/organizations/{id}", organizationHandler.GetOrganization).Methods("GET")
+	apiRouter.HandleFunc("/organizations/{id}/members", organizationHandler.GetMembers).Methods("GET")
+	apiRouter.HandleFunc("/organizations/{id}/invites", organizationHandler.InviteMember).Methods("POST")
+	apiRouter.HandleFunc("/organizations/{id}/invites/accept", organizationHandler.AcceptInvite).Methods("POST")
+	apiRouter.HandleFunc("/organizations/{id}/invitations", organizationHandler.CreateInvitation).Methods("POST")
+	apiRouter.HandleFunc("/organizations/{id}/invitations", organizationHandler.GetInvitations).Methods("GET")
+	apiRouter.HandleFunc("/organizations/{id}/invitations/{invitationId}", organizationHandler.RevokeInvitation).Methods("DELETE")
+	apiRouter.HandleFunc("/invitations/accept", organizationHandler.AcceptInvitation).Methods("POST")
+	apiRouter.HandleFunc("/organizations/{id}/settings", organizationHandler.GetSettings).Methods("GET")
+	apiRouter.HandleFunc("/organizations/{id}/settings", organizationHandler.UpdateSettings).Methods("PUT")
+	apiRouter.HandleFunc("/organizations/{id}/api-keys", organizationHandler.CreateAPIKey).Methods("POST")
+	apiRouter.HandleFunc("/organizations/{id}/api-keys", organizationHandler.GetAPIKeys).Methods("GET")
+	apiRouter.HandleFunc("/organizations/{id}/api-keys/{keyId}", organizationHandler.RevokeAPIKey).Methods("DELETE")
+	apiRouter.HandleFunc("/organizations/{id}/sharing", organizationHandler.GetVolunteerSharing).Methods("GET")
+	apiRouter.HandleFunc("/organizations/{id}/sharing", organizationHandler.ShareVolunteers).Methods("POST")
+	apiRouter.HandleFunc("/organizations/{id}/sharing/{recipientId}", organizationHandler.RevokeVolunteerSharing).Methods("DELETE")
+	apiRouter.HandleFunc("/organizations/{id}/verification", organizationHandler.GetVerification).Methods("GET")
+	apiRouter.HandleFunc("/organizations/{id}/verification", organizationHandler.ReviewVerification).Methods("PUT")
+	apiRouter.HandleFunc("/organizations/{id}/verification/evidence", organizationHandler.UploadVerificationEvidence).Methods("POST")
+	apiRouter.HandleFunc("/organizations/{id}/verification/evidence/{evidenceId}", organizationHandler.DownloadVerificationEvidence).Methods("GET")
+	apiRouter.HandleFunc("/organizations/{id}/verification/request", organizationHandler.RequestVerification).Methods("POST")
+	apiRouter.HandleFunc("/admin/organizations/pending-verification", roles.Require(auth.PermReviewVerifications, organizationHandler.GetPendingVerifications)).Methods("GET")
+	apiRouter.HandleFunc("/organizations/{id}/reports/summary", organizationHandler.GetSummaryReport).Methods("GET")
+	apiRouter.HandleFunc("/organizations/{id}/reports/hours", organizationHandler.GetOrganizationHoursReport).Methods("GET")
+	apiRouter.HandleFunc("/organizations/{id}/reports/impact", impactHandler.GetOrganizationImpact).Methods("GET")
+	apiRouter.HandleFunc("/projects/{id}/reports/hours", organizationHandler.GetProjectHoursReport).Methods("GET")
+	apiRouter.HandleFunc("/volunteers/{id}/reports/hours", organizationHandler.GetVolunteerHoursReport).Methods("GET")
+
+	// Describe the routes registered above in the OpenAPI document
+	missing, err := spec.Build(r, "/api")
+	if err != nil {
+		logging.Fatal("Failed to build the OpenAPI document", "error", err)
+	}
+	if len(missing) > 0 {
+		slog.Warn("Routes missing from the OpenAPI document", "routes", missing)
+	}
+
 	// CORS middleware
 	c := cors.New(cors.Options{
-		AllowedOrigins:   []string{"*"},
+		AllowedOrigins:   cfg.Server.CORSOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"*"},
+		ExposedHeaders:   []string{logging.HeaderRequestID, quotas.HeaderLimit, quotas.HeaderRemaining, quotas.HeaderReset, idempotency.HeaderReplayed, "Retry-After"},
 		AllowCredentials: true,
 	})
 
 	// Create server
 	srv := &http.Server{
-		Addr:         ":" + port,
-		Handler:      c.Handler(r),
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		Addr:         ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:      logging.Middleware(c.Handler(r)),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
+	// Background jobs stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	// Periodically export fact tables for the data warehouse
+	if cfg.Warehouse.Dest != "" {
+		sink, err := warehouse.ParseDestination(cfg.Warehouse.Dest)
+		if err != nil {
+			logging.Fatal("Invalid WAREHOUSE_EXPORT_DEST", "error", err)
+		}
+		go warehouse.NewExporter(exportService, sink, cfg.Warehouse.Interval).Run(jobsCtx)
+		slog.Info("Warehouse export scheduled", "dest", cfg.Warehouse.Dest, "interval", cfg.Warehouse.Interval)
+	}
+
+	// Stream published events to the users connected to this instance,
+	// ending the streams when the server shuts down
+	if _, err := db.Listen(database.EventsChannel, eventBus.HandleNotification); err != nil {
+		slog.Warn("Failed to listen for events", "error", err)
+	}
+	srv.RegisterOnShutdown(eventBus.Close)
+
+	// Award badges as volunteers enroll, log hours and get skills verified,
+	// first catching up on activity from while no instance was listening
+	if _, err := db.Listen(database.VolunteerActivityChannel, badgesService.HandleActivity); err != nil {
+		slog.Warn("Failed to listen for volunteer activity", "error", err)
+	}
+	go badgesService.HandleActivity("")
+
+	// Award hours milestones as volunteers log hours
+	if _, err := db.Listen(database.VolunteerActivityChannel, milestonesService.HandleActivity); err != nil {
+		slog.Warn("Failed to listen for logged hours", "error", err)
+	}
+	go milestonesService.HandleActivity("")
+
+	// Queue recurring maintenance on the instance holding the scheduler lock
+	jobsService.Register(enrollment.ExpireStaleJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			n, err := enrollmentService.ExpireStale(ctx, cfg.Schedule.EnrollmentExpiry)
+			if n > 0 {
+				slog.Info("Expired stale enrollments", "count", n)
+			}
+			return err
+		},
+	})
+	jobsService.Register(enrollment.RemindInvitationsJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			n, err := enrollmentService.RemindExpiringInvitations(ctx, cfg.Schedule.InvitationReminder)
+			if n > 0 {
+				slog.Info("Sent invitation reminders", "count", n)
+			}
+			return err
+		},
+	})
+	jobsService.Register(projects.RetireEndedJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			n, err := projectsService.RetireEnded(ctx, cfg.Schedule.ProjectRetireAfter)
+			if n > 0 {
+				slog.Info("Retired ended projects", "count", n)
+			}
+			return err
+		},
+	})
+	jobsService.Register(digests.CoordinatorDigestJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			n, err := digestsService.SendCoordinatorDigests()
+			if n > 0 {
+				slog.Info("Sent coordinator digests", "count", n)
+			}
+			return err
+		},
+		// A retry would send the digests that did go out again
+		Retry: jobs.RetryPolicy{MaxAttempts: 1},
+	})
+	jobsService.Register(idempotency.PurgeJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			n, err := idempotencyService.Purge(ctx)
+			if n > 0 {
+				slog.Info("Purged idempotency keys", "count", n)
+			}
+			return err
+		},
+	})
+	tasks := []scheduler.Task{
+		{Name: "refresh-skill-vectors", Schedule: cfg.Schedule.RefreshSkillVectors, Kind: matching.RefreshSkillVectorsJob},
+		{Name: "recompute-matches", Schedule: cfg.Schedule.RecomputeMatches, Kind: matching.RecomputeMatchesJob},
+		{Name: "expire-enrollments", Schedule: cfg.Schedule.ExpireEnrollments, Kind: enrollment.ExpireStaleJob},
+		{Name: "retire-projects", Schedule: cfg.Schedule.RetireProjects, Kind: projects.RetireEndedJob},
+		{Name: "coordinator-digests", Schedule: cfg.Schedule.CoordinatorDigests, Kind: digests.CoordinatorDigestJob},
+		{Name: "detect-duplicates", Schedule: cfg.Schedule.DetectDuplicates, Kind: duplicates.DetectJob},
+		{Name: "retention", Schedule: cfg.Schedule.Retention, Kind: retention.RunJob},
+		{Name: "purge-idempotency-keys", Schedule: cfg.Schedule.PurgeIdempotency, Kind: idempotency.PurgeJob},
+	}
+	if cfg.Schedule.InvitationReminder > 0 {
+		tasks = append(tasks, scheduler.Task{Name: "remind-invitations", Schedule: cfg.Schedule.RemindInvitations, Kind: enrollment.RemindInvitationsJob})
+	}
+	if searchService != nil {
+		tasks = append(tasks, scheduler.Task{Name: "reindex-search", Schedule: cfg.Schedule.ReindexSearch, Kind: search.ReindexJob})
+	}
+	for _, task := range tasks {
+		if task.Schedule == config.ScheduleOff {
+			continue
+		}
+		if err := schedulerService.Register(task); err != nil {
+			logging.Fatal("Invalid schedule", "task", task.Name, "error", err)
+		}
+	}
+	go schedulerService.Run(jobsCtx, 30*time.Second)
+
+	// Mirror project changes into the search index, first rebuilding it in
+	// case changes were made while no instance was listening
+	if searchService != nil {
+		if _, err := db.Listen(database.ProjectChangedChannel, searchService.HandleChange); err != nil {
+			slog.Warn("Failed to listen for project changes", "error", err)
+		}
+		go searchService.HandleChange("")
+	}
+
+	// Fill the database with synthetic data for load testing and demos,
+	// unless an earlier start already did
+	if cfg.Sandbox.Enabled {
+		if _, err := sandboxService.QueueGenerate(sandboxParams); err != nil {
+			slog.Warn("Failed to queue sandbox data generation", "error", err)
+		}
+	}
+
+	// Run queued jobs, waking idle workers as soon as any instance queues one
+	if _, err := db.Listen(database.JobQueuedChannel, func(string) { jobsService.Wake() }); err != nil {
+		slog.Warn("Failed to listen for queued jobs", "error", err)
+	}
+	go jobsService.Run(jobsCtx, cfg.Jobs.Workers, cfg.Jobs.PollInterval)
+
+	// Scan uploads for malware, then generate standard sizes of cleared
+	// avatars and photos
+	go scanningService.Run(jobsCtx, time.Minute)
+	go imagesService.Run(jobsCtx, time.Minute)
+
+	// Delete documents once their retention period is over
+	go documentsService.RunRetention(jobsCtx, time.Hour)
+
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Server starting on port %s", port)
+		slog.Info("Server starting", "port", cfg.Server.Port)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server error: %v", err)
+			logging.Fatal("Server error", "error", err)
 		}
 	}()
 
@@ -111,21 +916,18 @@ func main() {
 	signal.Notify(quit, os.Interrupt)
 	<-quit
 
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server...")
+	stopJobs()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		logging.Fatal("Server forced to shutdown", "error", err)
 	}
-
-	log.Println("Server stopped")
-}
-
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Warn("Failed to flush spans", "error", err)
 	}
-	return defaultValue
+
+	slog.Info("Server stopped")
 }
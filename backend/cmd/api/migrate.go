@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/civic-weave/backend/internal/config"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/logging"
+)
+
+const migrateUsage = `usage: api migrate [command]
+
+Commands:
+  up                 Apply every pending migration (the default)
+  down [steps]       Revert the last applied migrations (default: 1)
+  status             List migrations and when each was applied
+  baseline VERSION   Record migrations up to VERSION as applied without
+                     running them, for databases created before migrations
+                     were tracked`
+
+// runMigrate runs the migrate subcommand and returns the exit code
+func runMigrate(cfg *config.Config, args []string) int {
+	command := "up"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	var number int
+	switch {
+	case command == "up" && len(args) == 0, command == "status" && len(args) == 0:
+	case command == "down" && len(args) == 0:
+		number = 1
+	case (command == "down" || command == "baseline") && len(args) == 1:
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		number = n
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	db, err := database.NewPostgresDB(cfg.Database.Host, strconv.Itoa(cfg.Database.Port), cfg.Database.User, cfg.Database.Password, cfg.Database.Name)
+	if err != nil {
+		slog.Error("Failed to connect to database", "error", err)
+		return 1
+	}
+	defer db.Close()
+
+	switch command {
+	case "up":
+		applied, err := db.MigrateUp()
+		for _, m := range applied {
+			fmt.Printf("Applied %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			slog.Error("Migration failed", "error", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("No pending migrations")
+		}
+	case "down":
+		reverted, err := db.MigrateDown(number)
+		for _, m := range reverted {
+			fmt.Printf("Reverted %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			slog.Error("Migration failed", "error", err)
+			return 1
+		}
+	case "status":
+		status, err := db.MigrationStatus()
+		if err != nil {
+			slog.Error("Failed to read migration status", "error", err)
+			return 1
+		}
+		for _, s := range status {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = "applied " + s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%03d_%-40s %s\n", s.Version, s.Name, applied)
+		}
+	case "baseline":
+		if err := db.Baseline(number); err != nil {
+			slog.Error("Baseline failed", "error", err)
+			return 1
+		}
+		fmt.Printf("Recorded migrations up to %03d as applied\n", number)
+	}
+	return 0
+}
+
+// migrateOnStartup applies pending migrations when auto is set, and
+// otherwise refuses to start against a database that has some
+func migrateOnStartup(db *database.PostgresDB, auto bool) {
+	if auto {
+		applied, err := db.MigrateUp()
+		for _, m := range applied {
+			slog.Info("Applied migration", "version", m.Version, "name", m.Name)
+		}
+		if err != nil {
+			logging.Fatal("Failed to migrate database", "error", err)
+		}
+		return
+	}
+
+	status, err := db.MigrationStatus()
+	if err != nil {
+		logging.Fatal("Failed to read migration status", "error", err)
+	}
+	pending := 0
+	for _, s := range status {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+	if pending > 0 {
+		logging.Fatal("Database has pending migrations; run `api migrate up` or set DB_AUTO_MIGRATE=true", "pending", pending)
+	}
+}
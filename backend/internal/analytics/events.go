@@ -0,0 +1,154 @@
+package analytics
+
+import (
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"math/rand"
+	"regexp"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+var (
+	ErrNoEvents           = errors.New("at least one event is required")
+	ErrTooManyEvents      = errors.New("too many events in one batch")
+	ErrInvalidEventType   = errors.New("event type must be lowercase letters, digits, '_' or '.'")
+	ErrInvalidEventID     = errors.New("projectId and volunteerId must be UUIDs")
+	ErrInvalidProperties  = errors.New("event properties must be a JSON object")
+	ErrPropertiesTooLarge = errors.New("event properties are too large")
+	ErrInvalidSampleRate  = errors.New("sample rate must be greater than 0 and at most 1")
+	ErrSessionIDTooLong   = errors.New("sessionId is too long")
+)
+
+const (
+	// MaxEventBatch caps the events accepted in one request
+	MaxEventBatch = 100
+	// maxEventProperties caps the encoded size of one event's properties
+	maxEventProperties = 4096
+	maxSessionID       = 100
+)
+
+var (
+	eventTypePattern = regexp.MustCompile(`^[a-z][a-z0-9_.]{0,49}$`)
+	uuidPattern      = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// RecordEvents validates a batch of client events and stores the sampled
+// ones, returning how many were stored. Sampling keeps or drops whole
+// sessions so per-session sequences stay intact; events without a session
+// are sampled individually. The rate is stored with each event so counts can
+// be weighted back up.
+func (s *Service) RecordEvents(userID string, events []models.ClientEvent, sampleRate float64) (int, error) {
+	if len(events) == 0 {
+		return 0, ErrNoEvents
+	}
+	if len(events) > MaxEventBatch {
+		return 0, ErrTooManyEvents
+	}
+	if sampleRate <= 0 || sampleRate > 1 {
+		return 0, ErrInvalidSampleRate
+	}
+
+	var user *string
+	if userID != "" {
+		if !uuidPattern.MatchString(userID) {
+			return 0, ErrInvalidEventID
+		}
+		user = &userID
+	}
+
+	now := time.Now()
+	var kept []models.ClientEvent
+	for _, e := range events {
+		if err := validateEvent(&e, now); err != nil {
+			return 0, err
+		}
+		if sampled(e.SessionID, sampleRate) {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == 0 {
+		return 0, nil
+	}
+
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		stmt, err := tx.Prepare(`
+			INSERT INTO client_events (event_type, user_id, project_id, volunteer_id, session_id, properties, sample_rate, occurred_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, e := range kept {
+			_, err := stmt.Exec(e.Type, user, e.ProjectID, e.VolunteerID, e.SessionID, string(e.Properties), sampleRate, *e.OccurredAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(kept), nil
+}
+
+// validateEvent checks an event and fills in its defaults. Timestamps from
+// the future are treated as clock skew and replaced with now.
+func validateEvent(e *models.ClientEvent, now time.Time) error {
+	if !eventTypePattern.MatchString(e.Type) {
+		return ErrInvalidEventType
+	}
+	if e.ProjectID != nil && !uuidPattern.MatchString(*e.ProjectID) {
+		return ErrInvalidEventID
+	}
+	if e.VolunteerID != nil && !uuidPattern.MatchString(*e.VolunteerID) {
+		return ErrInvalidEventID
+	}
+	if e.SessionID != nil && len(*e.SessionID) > maxSessionID {
+		return ErrSessionIDTooLong
+	}
+
+	if len(e.Properties) == 0 || string(e.Properties) == "null" {
+		e.Properties = json.RawMessage(`{}`)
+	}
+	if len(e.Properties) > maxEventProperties {
+		return ErrPropertiesTooLarge
+	}
+	var props map[string]interface{}
+	if err := json.Unmarshal(e.Properties, &props); err != nil {
+		return ErrInvalidProperties
+	}
+
+	if e.OccurredAt == nil || e.OccurredAt.After(now) {
+		e.OccurredAt = &now
+	}
+	return nil
+}
+
+// sampled reports whether an event falls within the sample
+func sampled(sessionID *string, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	if sessionID == nil || *sessionID == "" {
+		return rand.Float64() < rate
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(*sessionID))
+	return float64(h.Sum32())/float64(1<<32) < rate
+}
@@ -0,0 +1,155 @@
+package analytics
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+var (
+	ErrInvalidPeriod     = errors.New("period must be week, month, year or all")
+	ErrVolunteerNotFound = errors.New("volunteer not found")
+)
+
+const (
+	DefaultLeaderboardSize = 10
+	MaxLeaderboardSize     = 100
+)
+
+// periodStart returns the UTC start of the calendar period containing now,
+// or nil for all time. Weeks start on Monday.
+func periodStart(period string, now time.Time) (*time.Time, error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var start time.Time
+	switch period {
+	case "week":
+		start = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	case "month":
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case "year":
+		start = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	case "all":
+		return nil, nil
+	default:
+		return nil, ErrInvalidPeriod
+	}
+	return &start, nil
+}
+
+// GetLeaderboards ranks volunteers who opted in by hours logged and by
+// projects completed in the current period. Volunteers who have not opted in
+// are left out before ranking, so hidden volunteers leave no gaps. When
+// tenantID is set only the tenant's projects count.
+func (s *Service) GetLeaderboards(tenantID, period string, limit int) (*models.Leaderboards, error) {
+	start, err := periodStart(period, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var since interface{}
+	report := models.Leaderboards{Period: period}
+	if start != nil {
+		from := start.Format(dateLayout)
+		since = from
+		report.From = &from
+	}
+
+	hoursQuery := `
+		SELECT RANK() OVER (ORDER BY SUM(vh.hours) DESC), u.id, u.name, SUM(vh.hours)
+		FROM volunteer_hours vh
+		JOIN users u ON u.id = vh.volunteer_id
+		JOIN projects p ON p.id = vh.project_id
+		WHERE u.leaderboard_opt_in
+		  AND ($1 = '' OR p.organization_id = NULLIF($1, '')::uuid)
+		  AND ($2::date IS NULL OR vh.worked_on >= $2::date)
+		GROUP BY u.id, u.name
+		ORDER BY 1, u.name
+		LIMIT $3
+	`
+
+	// Completion matches the retention report: completed_at, or when the
+	// project ended
+	projectsQuery := `
+		WITH completions AS (
+			SELECT ve.volunteer_id, ve.project_id, COALESCE(ve.completed_at, p.end_date) AS completed_at
+			FROM volunteer_enrollments ve
+			JOIN projects p ON p.id = ve.project_id
+			WHERE ve.status IN ('enrolled', 'completed')
+			  AND ($1 = '' OR p.organization_id = NULLIF($1, '')::uuid)
+		)
+		SELECT RANK() OVER (ORDER BY COUNT(DISTINCT c.project_id) DESC), u.id, u.name, COUNT(DISTINCT c.project_id)
+		FROM completions c
+		JOIN users u ON u.id = c.volunteer_id
+		WHERE u.leaderboard_opt_in
+		  AND c.completed_at < NOW()
+		  AND ($2::date IS NULL OR c.completed_at >= $2::date AT TIME ZONE 'UTC')
+		GROUP BY u.id, u.name
+		ORDER BY 1, u.name
+		LIMIT $3
+	`
+
+	report.Hours, err = s.queryLeaderboard(hoursQuery, tenantID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	report.ProjectsCompleted, err = s.queryLeaderboard(projectsQuery, tenantID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func (s *Service) queryLeaderboard(query string, args ...interface{}) ([]models.LeaderboardEntry, error) {
+	var entries []models.LeaderboardEntry
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		entries = []models.LeaderboardEntry{}
+		for rows.Next() {
+			var e models.LeaderboardEntry
+			if err := rows.Scan(&e.Rank, &e.VolunteerID, &e.VolunteerName, &e.Value); err != nil {
+				return err
+			}
+			entries = append(entries, e)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// SetLeaderboardOptIn shows or hides the volunteer on leaderboards
+func (s *Service) SetLeaderboardOptIn(volunteerID string, optIn bool) error {
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(`UPDATE users SET leaderboard_opt_in = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, volunteerID, optIn)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrVolunteerNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package analytics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+var ErrInvalidMonthRange = errors.New("from and to must be YYYY-MM months with from not after to")
+
+const monthLayout = "2006-01"
+
+// defaultRetentionMonths is how many cohorts are reported when no range is given
+const defaultRetentionMonths = 12
+
+// MonthRange parses an inclusive YYYY-MM range. A missing to defaults to the
+// current month and a missing from to 11 months before to.
+func MonthRange(from, to string) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if to != "" {
+		parsed, err := time.Parse(monthLayout, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidMonthRange
+		}
+		end = parsed
+	}
+
+	start := end.AddDate(0, 1-defaultRetentionMonths, 0)
+	if from != "" {
+		parsed, err := time.Parse(monthLayout, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidMonthRange
+		}
+		start = parsed
+	}
+
+	if start.After(end) {
+		return time.Time{}, time.Time{}, ErrInvalidMonthRange
+	}
+	return start, end, nil
+}
+
+// GetRetention groups volunteers by the month they completed a project and
+// counts how many had another enrollment accepted within 3 and 6 months.
+// An enrollment counts as completed at completed_at, or when its project
+// ended. When tenantID is set only the tenant's projects are considered.
+func (s *Service) GetRetention(tenantID string, from, to time.Time) (*models.RetentionReport, error) {
+	query := `
+		WITH completions AS (
+			SELECT ve.volunteer_id, COALESCE(ve.completed_at, p.end_date) AS completed_at
+			FROM volunteer_enrollments ve
+			JOIN projects p ON p.id = ve.project_id
+			WHERE ve.status IN ('enrolled', 'completed')
+			  AND ($1 = '' OR p.organization_id = NULLIF($1, '')::uuid)
+		),
+		cohorts AS (
+			SELECT date_trunc('month', completed_at AT TIME ZONE 'UTC') AS month,
+			       volunteer_id,
+			       MIN(completed_at) AS completed_at
+			FROM completions
+			WHERE completed_at < NOW()
+			  AND completed_at >= $2::timestamp AT TIME ZONE 'UTC'
+			  AND completed_at < ($3::timestamp + INTERVAL '1 month') AT TIME ZONE 'UTC'
+			GROUP BY 1, 2
+		)
+		SELECT to_char(c.month, 'YYYY-MM'),
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE r.returned_at <= c.completed_at + INTERVAL '3 months'),
+		       COUNT(*) FILTER (WHERE r.returned_at <= c.completed_at + INTERVAL '6 months')
+		FROM cohorts c
+		LEFT JOIN LATERAL (
+			SELECT MIN(re.approved_at) AS returned_at
+			FROM volunteer_enrollments re
+			JOIN projects rp ON rp.id = re.project_id
+			WHERE re.volunteer_id = c.volunteer_id
+			  AND re.approved_at > c.completed_at
+			  AND ($1 = '' OR rp.organization_id = NULLIF($1, '')::uuid)
+		) r ON TRUE
+		GROUP BY c.month
+		ORDER BY c.month
+	`
+
+	report := models.RetentionReport{
+		From: from.Format(monthLayout),
+		To:   to.Format(monthLayout),
+	}
+
+	now := time.Now().UTC()
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, tenantID, from.Format(dateLayout), to.Format(dateLayout))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		report.Cohorts = []models.RetentionCohort{}
+		for rows.Next() {
+			var c models.RetentionCohort
+			var retained3, retained6 int
+			if err := rows.Scan(&c.Month, &c.Volunteers, &retained3, &retained6); err != nil {
+				return err
+			}
+
+			month, err := time.Parse(monthLayout, c.Month)
+			if err != nil {
+				return err
+			}
+			// The last cohort member may have completed at the end of the month
+			monthEnd := month.AddDate(0, 1, 0)
+			if !monthEnd.AddDate(0, 3, 0).After(now) {
+				c.Retained3Months = &retained3
+				c.Rate3Months = rate(retained3, c.Volunteers)
+			}
+			if !monthEnd.AddDate(0, 6, 0).After(now) {
+				c.Retained6Months = &retained6
+				c.Rate6Months = rate(retained6, c.Volunteers)
+			}
+			report.Cohorts = append(report.Cohorts, c)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func rate(part, whole int) *float64 {
+	if whole == 0 {
+		return nil
+	}
+	r := float64(part) / float64(whole)
+	return &r
+}
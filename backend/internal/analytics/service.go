@@ -0,0 +1,170 @@
+package analytics
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var ErrInvalidInterval = errors.New("interval must be day, week or month")
+
+const dateLayout = "2006-01-02"
+
+// Funnel stage names as produced by the funnel query
+const (
+	stageShown     = "shown"
+	stageInvited   = "invited"
+	stageRequested = "requested"
+	stageAccepted  = "accepted"
+	stageCompleted = "completed"
+)
+
+var validIntervals = map[string]bool{
+	"day":   true,
+	"week":  true,
+	"month": true,
+}
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// RecordMatchImpressions notes that the volunteers were shown as matches for
+// the project
+func (s *Service) RecordMatchImpressions(projectID string, volunteerIDs []string) error {
+	if len(volunteerIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO match_impressions (project_id, volunteer_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT (project_id, volunteer_id) DO UPDATE
+		SET last_shown_at = NOW(),
+		    times_shown = match_impressions.times_shown + 1
+	`
+
+	return database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(query, projectID, pq.Array(volunteerIDs))
+		return err
+	})
+}
+
+// GetFunnel counts matches shown, invitations sent, requests made, and
+// enrollments accepted and completed within the inclusive range, bucketed by
+// interval and by project. tenantID and projectID narrow the projects
+// counted when set.
+func (s *Service) GetFunnel(tenantID, projectID, interval string, from, to time.Time) (*models.FunnelReport, error) {
+	if !validIntervals[interval] {
+		return nil, ErrInvalidInterval
+	}
+
+	// Invitations and requests are told apart by who started the enrollment
+	query := `
+		WITH scoped AS (
+			SELECT id, name
+			FROM projects
+			WHERE ($1 = '' OR organization_id = NULLIF($1, '')::uuid)
+			  AND ($2 = '' OR id = NULLIF($2, '')::uuid)
+		),
+		events AS (
+			SELECT project_id, 'shown' AS stage, first_shown_at AS at
+			FROM match_impressions
+			UNION ALL
+			SELECT project_id,
+			       CASE WHEN initiated_by = volunteer_id THEN 'requested' ELSE 'invited' END,
+			       created_at::timestamptz
+			FROM volunteer_enrollments
+			UNION ALL
+			SELECT project_id, 'accepted', approved_at::timestamptz
+			FROM volunteer_enrollments
+			WHERE approved_at IS NOT NULL
+			UNION ALL
+			SELECT project_id, 'completed', completed_at::timestamptz
+			FROM volunteer_enrollments
+			WHERE completed_at IS NOT NULL
+		)
+		SELECT to_char(date_trunc($5, e.at), 'YYYY-MM-DD'), s.id, s.name, e.stage, COUNT(*)
+		FROM events e
+		JOIN scoped s ON s.id = e.project_id
+		WHERE e.at >= $3::date AND e.at < $4::date + 1
+		GROUP BY 1, s.id, s.name, e.stage
+		ORDER BY 1
+	`
+
+	fromDate := from.Format(dateLayout)
+	toDate := to.Format(dateLayout)
+	report := models.FunnelReport{From: fromDate, To: toDate, Interval: interval}
+
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, tenantID, projectID, fromDate, toDate, interval)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		report.Totals = models.FunnelStages{}
+		report.Series = []models.FunnelPeriod{}
+		report.Projects = []models.ProjectFunnel{}
+		periods := make(map[string]int)
+		projects := make(map[string]int)
+		for rows.Next() {
+			var period, id, name, stage string
+			var count int
+			if err := rows.Scan(&period, &id, &name, &stage, &count); err != nil {
+				return err
+			}
+
+			i, ok := periods[period]
+			if !ok {
+				i = len(report.Series)
+				periods[period] = i
+				report.Series = append(report.Series, models.FunnelPeriod{Period: period})
+			}
+			j, ok := projects[id]
+			if !ok {
+				j = len(report.Projects)
+				projects[id] = j
+				report.Projects = append(report.Projects, models.ProjectFunnel{ProjectID: id, ProjectName: name})
+			}
+
+			addStage(&report.Totals, stage, count)
+			addStage(&report.Series[i].FunnelStages, stage, count)
+			addStage(&report.Projects[j].FunnelStages, stage, count)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(report.Projects, func(i, j int) bool {
+		return report.Projects[i].ProjectName < report.Projects[j].ProjectName
+	})
+
+	return &report, nil
+}
+
+func addStage(stages *models.FunnelStages, stage string, count int) {
+	switch stage {
+	case stageShown:
+		stages.MatchesShown += count
+	case stageInvited:
+		stages.InvitationsSent += count
+	case stageRequested:
+		stages.RequestsMade += count
+	case stageAccepted:
+		stages.Accepted += count
+	case stageCompleted:
+		stages.Completed += count
+	}
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/civic-weave/backend/internal/analytics"
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/geo"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type AnalyticsHandler struct {
+	analyticsService *analytics.Service
+	geoService       *geo.Service
+	eventSampleRate  float64
+}
+
+func NewAnalyticsHandler(analyticsService *analytics.Service, geoService *geo.Service, eventSampleRate float64) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+		geoService:       geoService,
+		eventSampleRate:  eventSampleRate,
+	}
+}
+
+// RecordEvents ingests a batch of frontend interaction events (viewed match,
+// clicked invite), attributed to the user when they are signed in.
+func (h *AnalyticsHandler) RecordEvents(w http.ResponseWriter, r *http.Request) {
+	var req models.RecordEventsRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	recorded, err := h.analyticsService.RecordEvents(auth.UserID(r), req.Events, h.eventSampleRate)
+	switch err {
+	case nil:
+	case analytics.ErrNoEvents, analytics.ErrTooManyEvents, analytics.ErrInvalidEventType, analytics.ErrInvalidEventID,
+		analytics.ErrInvalidProperties, analytics.ErrPropertiesTooLarge, analytics.ErrSessionIDTooLong:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("RecordEvents error", "count", len(req.Events), "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to record events")
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, models.RecordEventsResponse{
+		Received: len(req.Events),
+		Recorded: recorded,
+	})
+}
+
+// GetVolunteerHeatmap bins volunteers and active projects over a bounding
+// box so admins can spot areas where recruitment trails demand
+func (h *AnalyticsHandler) GetVolunteerHeatmap(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	bbox, err := geo.ParseBBox(query.Get("bbox"))
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, "bbox must be minLon,minLat,maxLon,maxLat")
+		return
+	}
+
+	zoom := geo.FitZoom(bbox)
+	if raw := query.Get("zoom"); raw != "" {
+		zoom, err = strconv.Atoi(raw)
+		if err != nil || zoom < 0 || zoom > geo.MaxHeatmapZoom {
+			apierror.Write(w, http.StatusBadRequest, "zoom must be an integer between 0 and 12")
+			return
+		}
+	}
+
+	tenantID := tenant.FromRequest(r)
+	volunteers, err := h.geoService.VolunteerPoints(bbox, tenantID)
+	var projects []geo.Point
+	if err == nil {
+		projects, err = h.geoService.ProjectPoints(bbox, tenantID)
+	}
+	if err == geo.ErrTooManyPoints {
+		apierror.Write(w, http.StatusUnprocessableEntity, "Too many points in bbox; zoom in")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetVolunteerHeatmap error", "tenant", tenantID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to build heatmap")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"zoom":          zoom,
+		"cellSize":      geo.CellSize(zoom),
+		"minBucketSize": geo.MinBucketSize,
+		"bins":          geo.Heatmap(volunteers, projects, zoom),
+	})
+}
+
+// GetFunnel reports the recruitment funnel (matches shown, invitations,
+// requests, accepted, completed) over an optional from/to range, bucketed by
+// ?interval= and optionally narrowed to one ?projectId=
+func (h *AnalyticsHandler) GetFunnel(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	from, to, err := organizations.ReportRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	interval := query.Get("interval")
+	if interval == "" {
+		interval = "week"
+	}
+
+	tenantID := tenant.FromRequest(r)
+	report, err := h.analyticsService.GetFunnel(tenantID, query.Get("projectId"), interval, from, to)
+	if err == analytics.ErrInvalidInterval {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetFunnel error", "tenant", tenantID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to build funnel")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// GetRetention reports monthly cohorts of volunteers who completed a project
+// and how many enrolled again within 3 and 6 months, over an optional
+// from/to month range (YYYY-MM, default the last 12 months)
+func (h *AnalyticsHandler) GetRetention(w http.ResponseWriter, r *http.Request) {
+	from, to, err := analytics.MonthRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenantID := tenant.FromRequest(r)
+	report, err := h.analyticsService.GetRetention(tenantID, from, to)
+	if err != nil {
+		logging.FromRequest(r).Error("GetRetention error", "tenant", tenantID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to build retention report")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// GetLeaderboards ranks volunteers who opted in by hours logged and projects
+// completed in the current ?period= (week, month, year or all; default
+// month), scoped to the request's tenant
+func (h *AnalyticsHandler) GetLeaderboards(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	period := query.Get("period")
+	if period == "" {
+		period = "month"
+	}
+
+	limit := analytics.DefaultLeaderboardSize
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			apierror.Write(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+	if limit > analytics.MaxLeaderboardSize {
+		limit = analytics.MaxLeaderboardSize
+	}
+
+	tenantID := tenant.FromRequest(r)
+	leaderboards, err := h.analyticsService.GetLeaderboards(tenantID, period, limit)
+	if err == analytics.ErrInvalidPeriod {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetLeaderboards error", "tenant", tenantID, "period", period, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch leaderboards")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, leaderboards)
+}
+
+// UpdateLeaderboardOptIn lets volunteers show or hide themselves on
+// leaderboards. Only the volunteer can change the setting.
+func (h *AnalyticsHandler) UpdateLeaderboardOptIn(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+
+	var req models.UpdateLeaderboardOptInRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+	if userID != volunteerID {
+		apierror.Write(w, http.StatusForbidden, "Volunteers can only change their own leaderboard setting")
+		return
+	}
+
+	err := h.analyticsService.SetLeaderboardOptIn(volunteerID, req.OptIn)
+	if err == analytics.ErrVolunteerNotFound {
+		apierror.Write(w, http.StatusNotFound, "Volunteer not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("UpdateLeaderboardOptIn error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update leaderboard setting")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]bool{"leaderboardOptIn": req.OptIn})
+}
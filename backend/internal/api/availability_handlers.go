@@ -0,0 +1,304 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/availability"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/gorilla/mux"
+)
+
+// defaultSlotDays is how many days of slots are listed without ?to=
+const defaultSlotDays = 14
+
+type AvailabilityHandler struct {
+	availabilityService  *availability.Service
+	organizationsService *organizations.Service
+}
+
+func NewAvailabilityHandler(availabilityService *availability.Service, organizationsService *organizations.Service) *AvailabilityHandler {
+	return &AvailabilityHandler{
+		availabilityService:  availabilityService,
+		organizationsService: organizationsService,
+	}
+}
+
+// GetRules lists a volunteer's recurring availability rules
+func (h *AvailabilityHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+	if !requireSelfOrAdmin(w, r, h.organizationsService, volunteerID, "Volunteers can only view their own availability") {
+		return
+	}
+
+	rules, err := h.availabilityService.GetRules(volunteerID)
+	if err != nil {
+		logging.FromRequest(r).Error("GetRules error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch availability")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rules)
+}
+
+// CreateRule adds a recurring availability rule such as every Saturday
+// morning except holidays
+func (h *AvailabilityHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	volunteerID, ok := h.requireVolunteer(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateAvailabilityRuleRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	rule, err := h.availabilityService.CreateRule(volunteerID, req)
+	switch err {
+	case nil:
+	case availability.ErrInvalidRRule, availability.ErrInvalidTime, availability.ErrInvalidTimezone,
+		availability.ErrInvalidDate, availability.ErrTooManyExceptions:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case availability.ErrTooManyRules:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	case availability.ErrVolunteerNotFound:
+		apierror.Write(w, http.StatusNotFound, "Volunteer not found")
+		return
+	default:
+		logging.FromRequest(r).Error("CreateRule error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create availability rule")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, rule)
+}
+
+// DeleteRule removes one of the volunteer's availability rules
+func (h *AvailabilityHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	volunteerID, ok := h.requireVolunteer(w, r)
+	if !ok {
+		return
+	}
+	ruleID := mux.Vars(r)["ruleId"]
+
+	err := h.availabilityService.DeleteRule(volunteerID, ruleID)
+	if err == availability.ErrRuleNotFound {
+		apierror.Write(w, http.StatusNotFound, "Availability rule not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("DeleteRule error", "rule", ruleID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to delete availability rule")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetBlackouts lists the date ranges a volunteer is away
+func (h *AvailabilityHandler) GetBlackouts(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+	if !requireSelfOrAdmin(w, r, h.organizationsService, volunteerID, "Volunteers can only view their own availability") {
+		return
+	}
+
+	blackouts, err := h.availabilityService.GetBlackouts(volunteerID)
+	if err != nil {
+		logging.FromRequest(r).Error("GetBlackouts error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch blackouts")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, blackouts)
+}
+
+// CreateBlackout marks the volunteer away for a range of days, overriding
+// their rules
+func (h *AvailabilityHandler) CreateBlackout(w http.ResponseWriter, r *http.Request) {
+	volunteerID, ok := h.requireVolunteer(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateAvailabilityBlackoutRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	blackout, err := h.availabilityService.CreateBlackout(volunteerID, req)
+	switch err {
+	case nil:
+	case availability.ErrBlackoutRange:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case availability.ErrTooManyBlackouts:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	case availability.ErrVolunteerNotFound:
+		apierror.Write(w, http.StatusNotFound, "Volunteer not found")
+		return
+	default:
+		logging.FromRequest(r).Error("CreateBlackout error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create blackout")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, blackout)
+}
+
+// DeleteBlackout removes one of the volunteer's blackouts
+func (h *AvailabilityHandler) DeleteBlackout(w http.ResponseWriter, r *http.Request) {
+	volunteerID, ok := h.requireVolunteer(w, r)
+	if !ok {
+		return
+	}
+	blackoutID := mux.Vars(r)["blackoutId"]
+
+	err := h.availabilityService.DeleteBlackout(volunteerID, blackoutID)
+	if err == availability.ErrBlackoutNotFound {
+		apierror.Write(w, http.StatusNotFound, "Blackout not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("DeleteBlackout error", "blackout", blackoutID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to delete blackout")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSlots expands the volunteer's rules into concrete windows between
+// ?from= (default today) and ?to= (default two weeks later), as UTC dates
+func (h *AvailabilityHandler) GetSlots(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+	if !requireSelfOrAdmin(w, r, h.organizationsService, volunteerID, "Volunteers can only view their own availability") {
+		return
+	}
+
+	from := time.Now().UTC().Truncate(24 * time.Hour)
+	if v := r.URL.Query().Get("from"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			apierror.Write(w, http.StatusBadRequest, availability.ErrInvalidRange.Error())
+			return
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 0, defaultSlotDays)
+	if v := r.URL.Query().Get("to"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			apierror.Write(w, http.StatusBadRequest, availability.ErrInvalidRange.Error())
+			return
+		}
+		to = parsed
+	}
+
+	slots, err := h.availabilityService.GetSlots(volunteerID, from, to)
+	if err == availability.ErrInvalidRange {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetSlots error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to expand availability")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, slots)
+}
+
+// GetHolidays lists the holidays rules can skip; ?year= narrows to one year
+func (h *AvailabilityHandler) GetHolidays(w http.ResponseWriter, r *http.Request) {
+	year := 0
+	if v := r.URL.Query().Get("year"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			apierror.Write(w, http.StatusBadRequest, "year must be a positive integer")
+			return
+		}
+		year = parsed
+	}
+
+	holidays, err := h.availabilityService.GetHolidays(year)
+	if err != nil {
+		logging.FromRequest(r).Error("GetHolidays error", "year", year, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch holidays")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, holidays)
+}
+
+// CreateHoliday adds a platform-wide holiday
+func (h *AvailabilityHandler) CreateHoliday(w http.ResponseWriter, r *http.Request) {
+	var req models.Holiday
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	holiday, err := h.availabilityService.CreateHoliday(req)
+	switch err {
+	case nil:
+	case availability.ErrInvalidDate, availability.ErrHolidayName:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case availability.ErrHolidayExists:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("CreateHoliday error", "date", req.Date, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create holiday")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, holiday)
+}
+
+// DeleteHoliday removes the holiday on the date in the path
+func (h *AvailabilityHandler) DeleteHoliday(w http.ResponseWriter, r *http.Request) {
+	date := mux.Vars(r)["date"]
+
+	err := h.availabilityService.DeleteHoliday(date)
+	switch err {
+	case nil:
+	case availability.ErrInvalidDate:
+		apierror.Write(w, http.StatusBadRequest, "date must be YYYY-MM-DD")
+		return
+	case availability.ErrHolidayNotFound:
+		apierror.Write(w, http.StatusNotFound, "Holiday not found")
+		return
+	default:
+		logging.FromRequest(r).Error("DeleteHoliday error", "date", date, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to delete holiday")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireVolunteer checks the signed-in user is the volunteer in the path. It
+// writes the error response and returns false otherwise.
+func (h *AvailabilityHandler) requireVolunteer(w http.ResponseWriter, r *http.Request) (string, bool) {
+	volunteerID := mux.Vars(r)["id"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return "", false
+	}
+	if userID != volunteerID {
+		apierror.Write(w, http.StatusForbidden, "Volunteers can only manage their own availability")
+		return "", false
+	}
+	return volunteerID, true
+}
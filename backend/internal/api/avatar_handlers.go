@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/avatars"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// multipartOverhead allows for the multipart framing around an avatar
+const multipartOverhead = 64 << 10
+
+type AvatarHandler struct {
+	avatarsService *avatars.Service
+}
+
+func NewAvatarHandler(avatarsService *avatars.Service) *AvatarHandler {
+	return &AvatarHandler{avatarsService: avatarsService}
+}
+
+// UploadAvatar replaces the user's avatar with the image in the multipart
+// "avatar" field
+func (h *AvatarHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, avatars.MaxBytes+multipartOverhead)
+	file, _, err := r.FormFile("avatar")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		apierror.Write(w, http.StatusRequestEntityTooLarge, avatars.ErrImageTooLarge.Error())
+		return
+	}
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, "Multipart form with an avatar file is required")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, avatars.MaxBytes+1))
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, "Failed to read avatar")
+		return
+	}
+
+	url, err := h.avatarsService.Upload(r.Context(), userID, data)
+	switch err {
+	case nil:
+	case avatars.ErrImageTooLarge:
+		apierror.Write(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	case avatars.ErrUnsupportedImage, avatars.ErrImageDimensions:
+		apierror.Write(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	case avatars.ErrUserNotFound:
+		apierror.Write(w, http.StatusNotFound, "User not found")
+		return
+	default:
+		logging.FromRequest(r).Error("UploadAvatar error", "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to upload avatar")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"avatarUrl": url, "scanStatus": models.ScanPending})
+}
+
+// DeleteAvatar removes the user's avatar
+func (h *AvatarHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.avatarsService.Remove(r.Context(), userID)
+	if err == avatars.ErrUserNotFound {
+		apierror.Write(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("DeleteAvatar error", "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to delete avatar")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireSelf checks that the signed-in user is the user in the path; users only
+// change their own avatar
+func (h *AvatarHandler) requireSelf(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := mux.Vars(r)["id"]
+
+	requester := auth.UserID(r)
+	if requester == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return "", false
+	}
+	if requester != userID {
+		apierror.Write(w, http.StatusForbidden, "Users can only change their own avatar")
+		return "", false
+	}
+	return userID, true
+}
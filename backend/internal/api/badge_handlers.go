@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/badges"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/gorilla/mux"
+)
+
+type BadgeHandler struct {
+	badgesService *badges.Service
+}
+
+func NewBadgeHandler(badgesService *badges.Service) *BadgeHandler {
+	return &BadgeHandler{badgesService: badgesService}
+}
+
+// GetVolunteerBadges lists the badges a volunteer has earned
+func (h *BadgeHandler) GetVolunteerBadges(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+
+	list, err := h.badgesService.GetBadges(volunteerID)
+	if err != nil {
+		logging.FromRequest(r).Error("GetVolunteerBadges error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch badges")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, list)
+}
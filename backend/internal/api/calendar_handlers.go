@@ -0,0 +1,120 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/calendar"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/gorilla/mux"
+)
+
+type CalendarHandler struct {
+	calendarService *calendar.Service
+}
+
+func NewCalendarHandler(calendarService *calendar.Service) *CalendarHandler {
+	return &CalendarHandler{calendarService: calendarService}
+}
+
+// GetFeed serves a volunteer's commitments as an iCal feed. Calendar apps
+// can't send credentials, so the feed is authorized by ?token= instead of
+// a bearer token.
+func (h *CalendarHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		apierror.Write(w, http.StatusUnauthorized, "Calendar token required")
+		return
+	}
+
+	err := h.calendarService.Authenticate(volunteerID, token)
+	if err == calendar.ErrInvalidToken {
+		apierror.Write(w, http.StatusUnauthorized, "Invalid or rotated calendar token")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("Calendar authentication error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check calendar token")
+		return
+	}
+
+	events, err := h.calendarService.GetCommitments(volunteerID)
+	if err != nil {
+		logging.FromRequest(r).Error("GetFeed error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to build calendar")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="civic-weave.ics"`)
+	if err := calendar.WriteICS(w, "Civic Weave", events, time.Now()); err != nil {
+		logging.FromRequest(r).Error("GetFeed write error", "volunteer", volunteerID, "error", err)
+	}
+}
+
+// RotateToken issues a new feed URL for the volunteer; any previous URL
+// stops working. Only the volunteer can manage their feed.
+func (h *CalendarHandler) RotateToken(w http.ResponseWriter, r *http.Request) {
+	volunteerID, ok := h.requireVolunteer(w, r)
+	if !ok {
+		return
+	}
+
+	token, err := h.calendarService.RotateToken(volunteerID)
+	if err == calendar.ErrVolunteerNotFound {
+		apierror.Write(w, http.StatusNotFound, "Volunteer not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("RotateToken error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to issue calendar token")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]string{
+		"token": token,
+		"path":  "/api/volunteers/" + volunteerID + "/calendar.ics?token=" + token,
+	})
+}
+
+// RevokeToken disables the volunteer's feed URL
+func (h *CalendarHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	volunteerID, ok := h.requireVolunteer(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.calendarService.RevokeToken(volunteerID)
+	if err == calendar.ErrTokenNotFound {
+		apierror.Write(w, http.StatusNotFound, "Calendar feed is not enabled")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("RevokeToken error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to revoke calendar token")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireVolunteer checks the signed-in user is the volunteer in the path. It
+// writes the error response and returns false otherwise.
+func (h *CalendarHandler) requireVolunteer(w http.ResponseWriter, r *http.Request) (string, bool) {
+	volunteerID := mux.Vars(r)["id"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return "", false
+	}
+	if userID != volunteerID {
+		apierror.Write(w, http.StatusForbidden, "Volunteers can only manage their own calendar feed")
+		return "", false
+	}
+	return volunteerID, true
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/config"
+)
+
+type ConfigHandler struct {
+	config *config.Config
+}
+
+func NewConfigHandler(cfg *config.Config) *ConfigHandler {
+	return &ConfigHandler{
+		config: cfg,
+	}
+}
+
+// GetConfig shows platform admins the settings the server started with,
+// with passwords, keys and other secrets redacted
+func (h *ConfigHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.config.Redacted())
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/apikeys"
+	"github.com/civic-weave/backend/internal/connectors"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/gorilla/mux"
+)
+
+// maxConnectorPayloadBytes bounds webhook deliveries from external platforms
+const maxConnectorPayloadBytes = 5 << 20
+
+type ConnectorHandler struct {
+	connectorsService *connectors.Service
+}
+
+func NewConnectorHandler(connectorsService *connectors.Service) *ConnectorHandler {
+	return &ConnectorHandler{connectorsService: connectorsService}
+}
+
+// Webhook imports the projects an external platform posts, mapped by the
+// connector named in the path, into the organization of the request's API
+// key. Deliveries are idempotent: projects are matched on their external IDs.
+func (h *ConnectorHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	key := apikeys.FromRequest(r)
+	if key == nil {
+		apierror.Write(w, http.StatusUnauthorized, "An API key with the projects:write scope is required")
+		return
+	}
+	source := mux.Vars(r)["source"]
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxConnectorPayloadBytes))
+	if err != nil {
+		apierror.Write(w, http.StatusRequestEntityTooLarge, "Payload is too large")
+		return
+	}
+
+	result, err := h.connectorsService.Import(r.Context(), key.OrganizationID, source, payload)
+	var invalid *connectors.InvalidPayloadError
+	switch {
+	case err == connectors.ErrUnknownSource:
+		apierror.Write(w, http.StatusNotFound, "Unknown connector source")
+		return
+	case err == connectors.ErrNoProjects, err == connectors.ErrTooManyProjects:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.As(err, &invalid):
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		logging.FromRequest(r).Error("Connector webhook error", "org", key.OrganizationID, "source", source, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to import projects")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
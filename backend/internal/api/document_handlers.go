@@ -0,0 +1,268 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/documents"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/gorilla/mux"
+)
+
+type DocumentHandler struct {
+	documentsService *documents.Service
+}
+
+func NewDocumentHandler(documentsService *documents.Service) *DocumentHandler {
+	return &DocumentHandler{documentsService: documentsService}
+}
+
+// UploadCertification stores a certification from the multipart "file"
+// field, with "title" and an optional "expiresOn" field. Only the volunteer
+// can upload their certifications.
+func (h *DocumentHandler) UploadCertification(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+	if userID != volunteerID {
+		apierror.Write(w, http.StatusForbidden, "Volunteers can only upload their own certifications")
+		return
+	}
+
+	req, data, ok := readDocumentUpload(w, r)
+	if !ok {
+		return
+	}
+
+	doc, err := h.documentsService.UploadCertification(r.Context(), volunteerID, userID, req, data)
+	switch err {
+	case nil:
+	case documents.ErrFileTooLarge:
+		apierror.Write(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	case documents.ErrUnsupportedFile:
+		apierror.Write(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	case documents.ErrInvalidTitle, documents.ErrInvalidExpiry:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case documents.ErrVolunteerNotFound:
+		apierror.Write(w, http.StatusNotFound, "Volunteer not found")
+		return
+	default:
+		logging.FromRequest(r).Error("UploadCertification error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to upload document")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, doc)
+}
+
+// UploadWaiver attaches a signed waiver from the multipart "file" field,
+// with a "title" field, to an enrollment
+func (h *DocumentHandler) UploadWaiver(w http.ResponseWriter, r *http.Request) {
+	enrollmentID := mux.Vars(r)["enrollmentId"]
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	req, data, ok := readDocumentUpload(w, r)
+	if !ok {
+		return
+	}
+
+	doc, err := h.documentsService.UploadWaiver(r.Context(), enrollmentID, userID, req, data)
+	switch err {
+	case nil:
+	case documents.ErrFileTooLarge:
+		apierror.Write(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	case documents.ErrUnsupportedFile:
+		apierror.Write(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	case documents.ErrInvalidTitle:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case documents.ErrEnrollmentNotFound:
+		apierror.Write(w, http.StatusNotFound, "Enrollment not found")
+		return
+	case documents.ErrForbidden:
+		apierror.Write(w, http.StatusForbidden, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("UploadWaiver error", "enrollment", enrollmentID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to upload document")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, doc)
+}
+
+// GetVolunteerDocuments lists the volunteer's documents the signed-in user
+// may see
+func (h *DocumentHandler) GetVolunteerDocuments(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	docs, err := h.documentsService.GetVolunteerDocuments(volunteerID, userID)
+	if err != nil {
+		logging.FromRequest(r).Error("GetVolunteerDocuments error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch documents")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, docs)
+}
+
+// GetEnrollmentDocuments lists the waivers attached to an enrollment that
+// the signed-in user may see
+func (h *DocumentHandler) GetEnrollmentDocuments(w http.ResponseWriter, r *http.Request) {
+	enrollmentID := mux.Vars(r)["enrollmentId"]
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	docs, err := h.documentsService.GetEnrollmentDocuments(enrollmentID, userID)
+	if err != nil {
+		logging.FromRequest(r).Error("GetEnrollmentDocuments error", "enrollment", enrollmentID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch documents")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, docs)
+}
+
+// GetDocument returns a document with a short-lived signed link to its file
+func (h *DocumentHandler) GetDocument(w http.ResponseWriter, r *http.Request) {
+	documentID := mux.Vars(r)["documentId"]
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	doc, err := h.documentsService.GetDocument(documentID, userID)
+	if err == documents.ErrDocumentNotFound {
+		apierror.Write(w, http.StatusNotFound, "Document not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetDocument error", "document", documentID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch document")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, doc)
+}
+
+// GetDocumentContent serves a document's file to anyone holding a valid
+// signed link
+func (h *DocumentHandler) GetDocumentContent(w http.ResponseWriter, r *http.Request) {
+	documentID := mux.Vars(r)["documentId"]
+	q := r.URL.Query()
+
+	doc, file, err := h.documentsService.Open(r.Context(), documentID, q.Get("expires"), q.Get("signature"))
+	switch err {
+	case nil:
+	case documents.ErrInvalidSignature:
+		apierror.Write(w, http.StatusForbidden, err.Error())
+		return
+	case documents.ErrNotScanned, documents.ErrQuarantined:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	case documents.ErrDocumentNotFound:
+		apierror.Write(w, http.StatusNotFound, "Document not found")
+		return
+	default:
+		logging.FromRequest(r).Error("GetDocumentContent error", "document", documentID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch document")
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", doc.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(doc.SizeBytes))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": doc.FileName}))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err := io.Copy(w, file); err != nil {
+		logging.FromRequest(r).Error("GetDocumentContent copy error", "document", documentID, "error", err)
+	}
+}
+
+// DeleteDocument removes a document; only its owner can
+func (h *DocumentHandler) DeleteDocument(w http.ResponseWriter, r *http.Request) {
+	documentID := mux.Vars(r)["documentId"]
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	err := h.documentsService.Delete(r.Context(), documentID, userID)
+	switch err {
+	case nil:
+	case documents.ErrDocumentNotFound:
+		apierror.Write(w, http.StatusNotFound, "Document not found")
+		return
+	case documents.ErrNotOwner:
+		apierror.Write(w, http.StatusForbidden, err.Error())
+		return
+	case documents.ErrRetained:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("DeleteDocument error", "document", documentID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to delete document")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readDocumentUpload reads the multipart "file", "title" and "expiresOn"
+// fields, writing the error response and returning false when it can't
+func readDocumentUpload(w http.ResponseWriter, r *http.Request) (models.UploadDocumentRequest, []byte, bool) {
+	var req models.UploadDocumentRequest
+
+	r.Body = http.MaxBytesReader(w, r.Body, documents.MaxBytes+multipartOverhead)
+	file, header, err := r.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		apierror.Write(w, http.StatusRequestEntityTooLarge, documents.ErrFileTooLarge.Error())
+		return req, nil, false
+	}
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, "Multipart form with a file is required")
+		return req, nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, documents.MaxBytes+1))
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, "Failed to read file")
+		return req, nil, false
+	}
+
+	req.Title = r.FormValue("title")
+	req.ExpiresOn = r.FormValue("expiresOn")
+	req.FileName = header.Filename
+	return req, data, true
+}
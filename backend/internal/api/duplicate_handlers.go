@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/duplicates"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/gorilla/mux"
+)
+
+type DuplicateHandler struct {
+	duplicatesService *duplicates.Service
+}
+
+func NewDuplicateHandler(duplicatesService *duplicates.Service) *DuplicateHandler {
+	return &DuplicateHandler{
+		duplicatesService: duplicatesService,
+	}
+}
+
+// GetDuplicates lists pairs of accounts that look like the same person
+func (h *DuplicateHandler) GetDuplicates(w http.ResponseWriter, r *http.Request) {
+	candidates, err := h.duplicatesService.GetPendingCandidates()
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get duplicate accounts")
+		return
+	}
+	respondJSON(w, http.StatusOK, candidates)
+}
+
+// DetectDuplicates queues a detection run rather than waiting for the
+// scheduled one
+func (h *DuplicateHandler) DetectDuplicates(w http.ResponseWriter, r *http.Request) {
+	job, err := h.duplicatesService.QueueDetect()
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to queue duplicate detection")
+		return
+	}
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// DismissDuplicate marks a pair as different people
+func (h *DuplicateHandler) DismissDuplicate(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r)
+
+	err := h.duplicatesService.DismissCandidate(mux.Vars(r)["id"], userID)
+	if err == duplicates.ErrCandidateNotFound {
+		apierror.Write(w, http.StatusNotFound, "Duplicate candidate not found")
+		return
+	}
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to dismiss duplicate candidate")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MergeAccounts moves everything the duplicate account in the body owns to
+// the account in the path, then deletes the duplicate
+func (h *DuplicateHandler) MergeAccounts(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r)
+
+	var req models.MergeAccountsRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.DuplicateID == userID {
+		apierror.Write(w, http.StatusBadRequest, "You cannot merge away your own account")
+		return
+	}
+
+	keptID := mux.Vars(r)["id"]
+	merge, err := h.duplicatesService.Merge(keptID, req.DuplicateID, userID)
+	switch err {
+	case nil:
+	case duplicates.ErrSameAccount:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case duplicates.ErrAccountNotFound:
+		apierror.Write(w, http.StatusNotFound, "Account not found")
+		return
+	default:
+		logging.FromRequest(r).Error("MergeAccounts error", "user", keptID, "duplicate", req.DuplicateID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to merge accounts")
+		return
+	}
+	logging.FromRequest(r).Info("Accounts merged", "duplicate", merge.DuplicateID, "kept", merge.UserID, "user", userID,
+		"skills", merge.Skills, "enrollments", merge.Enrollments, "hours", merge.Hours)
+	respondJSON(w, http.StatusOK, merge)
+}
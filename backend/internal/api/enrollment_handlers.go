@@ -2,61 +2,90 @@ package api
 
 import (
 	"encoding/json"
-	"fmt"
-	"log"
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/apikeys"
+	"github.com/civic-weave/backend/internal/auth"
 	"github.com/civic-weave/backend/internal/enrollment"
+	"github.com/civic-weave/backend/internal/logging"
 	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/civic-weave/backend/internal/pagination"
+	"github.com/civic-weave/backend/internal/tenant"
 	"github.com/gorilla/mux"
 )
 
 type EnrollmentHandler struct {
-	enrollmentService *enrollment.Service
+	enrollmentService    *enrollment.Service
+	organizationsService *organizations.Service
+	authService          *auth.Service
 }
 
-func NewEnrollmentHandler(enrollmentService *enrollment.Service) *EnrollmentHandler {
+func NewEnrollmentHandler(enrollmentService *enrollment.Service, organizationsService *organizations.Service, authService *auth.Service) *EnrollmentHandler {
 	return &EnrollmentHandler{
-		enrollmentService: enrollmentService,
+		enrollmentService:    enrollmentService,
+		organizationsService: organizationsService,
+		authService:          authService,
 	}
 }
 
 // CreateEnrollment creates a new enrollment request
 func (h *EnrollmentHandler) CreateEnrollment(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateEnrollmentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("ERROR: Failed to decode request body: %v", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeBody(w, r, &req) {
 		return
 	}
 
-	// Get user ID from query parameter (in real app, this would come from auth)
-	userID := r.URL.Query().Get("userId")
-	if userID == "" {
-		log.Printf("ERROR: User ID not provided")
-		http.Error(w, "User ID required", http.StatusBadRequest)
+	volunteerID, initiatedBy, status, reason := enrollmentParties(r, req)
+	if status != 0 {
+		apierror.Write(w, status, reason)
 		return
 	}
 
-	log.Printf("DEBUG: CreateEnrollment request - ProjectID: %s, Action: %s, VolunteerID: %v, UserID: %s",
-		req.ProjectID, req.Action, req.VolunteerID, userID)
+	logging.FromRequest(r).Debug("CreateEnrollment request",
+		"project", req.ProjectID, "action", req.Action, "volunteer", volunteerID, "initiatedBy", initiatedBy)
 
-	// Validate action
-	if req.Action != "request" && req.Action != "invite" {
-		http.Error(w, "Invalid action (must be 'request' or 'invite')", http.StatusBadRequest)
+	if key := apikeys.FromRequest(r); key != nil {
+		// Partner sites may only enroll their own organization's volunteers
+		role, err := h.organizationsService.GetMemberRole(key.OrganizationID, volunteerID)
+		if err != nil {
+			logging.FromRequest(r).Error("Failed to check organization membership", "organization", key.OrganizationID, "volunteer", volunteerID, "error", err)
+			apierror.Write(w, http.StatusInternalServerError, "Failed to check organization membership")
+			return
+		}
+		if role == "" {
+			apierror.Write(w, http.StatusForbidden, "Volunteer is not a member of this organization")
+			return
+		}
+	}
+
+	// Self-registered users verify their email address before enrolling
+	verified, err := h.authService.IsEmailVerified(initiatedBy)
+	if err != nil {
+		logging.FromRequest(r).Error("Failed to check email verification", "user", initiatedBy, "error", err)
+		apierror.Write(w, http.StatusInternalServerError, "Failed to check email verification")
+		return
+	}
+	if !verified {
+		apierror.Write(w, http.StatusForbidden, "Verify your email address before joining projects")
 		return
 	}
 
-	// For "request" action, volunteer initiates for themselves
-	// For "invite" action, TL initiates and specifies which volunteer to invite
-	volunteerID := userID
 	if req.Action == "invite" {
-		if req.VolunteerID == nil || *req.VolunteerID == "" {
-			http.Error(w, "Volunteer ID required for invite action", http.StatusBadRequest)
+		// Only people managing the project may invite volunteers to it
+		allowed, err := h.organizationsService.CanManageProject(initiatedBy, req.ProjectID)
+		if err != nil {
+			logging.FromRequest(r).Error("Failed to check project permissions", "project", req.ProjectID, "user", initiatedBy, "error", err)
+			apierror.Write(w, http.StatusInternalServerError, "Failed to check project permissions")
+			return
+		}
+		if !allowed {
+			apierror.Write(w, http.StatusForbidden, "Only the project coordinator or organization coordinators can invite volunteers")
 			return
 		}
-		volunteerID = *req.VolunteerID
 	}
 
 	var message string
@@ -64,60 +93,189 @@ func (h *EnrollmentHandler) CreateEnrollment(w http.ResponseWriter, r *http.Requ
 		message = *req.Message
 	}
 
-	enrollment, err := h.enrollmentService.CreateEnrollment(
+	// Apply the hosting organization's enrollment policies to volunteer requests
+	if req.Action == "request" {
+		settings, err := h.organizationsService.GetSettingsForProject(req.ProjectID)
+		if err != nil {
+			logging.FromRequest(r).Error("Failed to load enrollment policies", "project", req.ProjectID, "error", err)
+			apierror.Write(w, http.StatusInternalServerError, "Failed to load enrollment policies")
+			return
+		}
+		if !settings.Enrollment.AllowVolunteerRequests {
+			apierror.Write(w, http.StatusForbidden, "This organization only enrolls volunteers by invitation")
+			return
+		}
+		if settings.Enrollment.RequireRequestMessage && strings.TrimSpace(message) == "" {
+			apierror.Write(w, http.StatusBadRequest, "A message is required when requesting to join this project")
+			return
+		}
+	}
+
+	created, err := h.enrollmentService.CreateEnrollment(r.Context(),
 		volunteerID,
 		req.ProjectID,
 		req.Action,
 		message,
-		userID,
+		initiatedBy,
+		tenant.FromRequest(r),
 	)
 	if err != nil {
-		log.Printf("ERROR: Failed to create enrollment - volunteerID: %s, projectID: %s, action: %s, error: %v",
-			volunteerID, req.ProjectID, req.Action, err)
+		logging.FromRequest(r).Error("Failed to create enrollment",
+			"volunteer", volunteerID, "project", req.ProjectID, "action", req.Action, "error", err)
 
-		// Check for duplicate enrollment error
-		errStr := strings.ToLower(fmt.Sprintf("%v", err))
-		if strings.Contains(errStr, "duplicate key") && strings.Contains(errStr, "volunteer_enrollments_volunteer_id_project_id_key") {
-			http.Error(w, "This volunteer is already enrolled or has a pending enrollment for this project", http.StatusConflict)
+		if respondScheduleConflict(w, err) {
 			return
 		}
-
-		http.Error(w, fmt.Sprintf("Failed to create enrollment: %v", err), http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, enrollment.ErrProjectNotFound):
+			apierror.Write(w, http.StatusNotFound, "Project not found")
+		case errors.Is(err, enrollment.ErrAlreadyEnrolled):
+			apierror.Write(w, http.StatusConflict, "This volunteer is already enrolled or has a pending enrollment for this project")
+		default:
+			apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create enrollment")
+		}
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(enrollment)
+	json.NewEncoder(w).Encode(created)
+}
+
+// enrollmentParties resolves the volunteer an enrollment is for and the user
+// recorded as initiating it, or the status and message to reject the request
+// with. Signed-in volunteers request for themselves and coordinators name the
+// volunteer they invite. Partner API keys carry no user, so they name the
+// volunteer in the body and may only post requests on their behalf.
+func enrollmentParties(r *http.Request, req models.CreateEnrollmentRequest) (volunteerID, initiatedBy string, status int, message string) {
+	named := ""
+	if req.VolunteerID != nil {
+		named = *req.VolunteerID
+	}
+
+	if apikeys.FromRequest(r) != nil {
+		if req.Action != "request" {
+			return "", "", http.StatusForbidden, "API keys can only create enrollment requests"
+		}
+		if named == "" {
+			return "", "", http.StatusBadRequest, "Volunteer ID required for API key requests"
+		}
+		return named, named, 0, ""
+	}
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		return "", "", http.StatusBadRequest, "User ID required"
+	}
+	if req.Action != "invite" {
+		return userID, userID, 0, ""
+	}
+	if named == "" {
+		return "", "", http.StatusBadRequest, "Volunteer ID required for invite action"
+	}
+	return named, userID, 0, ""
+}
+
+// respondScheduleConflict writes a 409 listing the overlapping enrollments
+// when err is a *enrollment.ScheduleConflictError, and reports whether it did
+func respondScheduleConflict(w http.ResponseWriter, err error) bool {
+	var conflictErr *enrollment.ScheduleConflictError
+	if !errors.As(err, &conflictErr) {
+		return false
+	}
+
+	apierror.WriteDetails(w, http.StatusConflict, "This project does not allow enrollments that overlap the volunteer's other commitments",
+		map[string]interface{}{"conflicts": conflictErr.Conflicts})
+	return true
+}
+
+// respondUnsignedWaivers writes a 409 listing the waivers the volunteer must
+// sign when err is an *enrollment.UnsignedWaiversError
+func respondUnsignedWaivers(w http.ResponseWriter, err error) bool {
+	var waiversErr *enrollment.UnsignedWaiversError
+	if !errors.As(err, &waiversErr) {
+		return false
+	}
+
+	apierror.WriteDetails(w, http.StatusConflict, "The volunteer must sign the project's waivers before enrolling",
+		map[string]interface{}{"waivers": waiversErr.Waivers})
+	return true
 }
 
-// GetProjectEnrollments gets all enrollments for a project
+// GetProjectEnrollments lists a page of a project's enrollments, optionally
+// only those with ?status=, to the people managing the project
 func (h *EnrollmentHandler) GetProjectEnrollments(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	projectID := vars["projectId"]
 
-	enrollments, err := h.enrollmentService.GetProjectEnrollments(projectID)
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+	allowed, err := h.organizationsService.CanManageProject(userID, projectID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to get project enrollments: %v", err), http.StatusInternalServerError)
+		logging.FromRequest(r).Error("Failed to check project permissions", "project", projectID, "user", userID, "error", err)
+		apierror.Write(w, http.StatusInternalServerError, "Failed to check project permissions")
+		return
+	}
+	if !allowed {
+		apierror.Write(w, http.StatusForbidden, "Only the project coordinator or organization coordinators can view its enrollments")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(enrollments)
+	page, ok := parsePage(w, r, enrollment.Sorts)
+	if !ok {
+		return
+	}
+
+	enrollments, total, err := h.enrollmentService.GetProjectEnrollments(r.Context(), projectID, tenant.FromRequest(r), r.URL.Query().Get("status"), page)
+	if err != nil {
+		logging.FromRequest(r).Error("GetProjectEnrollments error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get project enrollments")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pagination.NewPage(enrollments, total, page))
+}
+
+// GetWaitlist lists a project's waitlisted enrollments, next to be enrolled
+// first
+func (h *EnrollmentHandler) GetWaitlist(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["projectId"]
+
+	entries, err := h.enrollmentService.GetWaitlist(r.Context(), projectID, tenant.FromRequest(r))
+	if err != nil {
+		logging.FromRequest(r).Error("GetWaitlist error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get waitlist")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, entries)
 }
 
-// GetVolunteerEnrollments gets all enrollments for a volunteer
+// GetVolunteerEnrollments lists a page of a volunteer's enrollments,
+// optionally only those with ?status=, to the volunteer and platform admins
 func (h *EnrollmentHandler) GetVolunteerEnrollments(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	volunteerID := vars["volunteerId"]
+	if !requireSelfOrAdmin(w, r, h.organizationsService, volunteerID, "Volunteers can only view their own enrollments") {
+		return
+	}
+
+	page, ok := parsePage(w, r, enrollment.Sorts)
+	if !ok {
+		return
+	}
 
-	enrollments, err := h.enrollmentService.GetVolunteerEnrollments(volunteerID)
+	enrollments, total, err := h.enrollmentService.GetVolunteerEnrollments(r.Context(), volunteerID, tenant.FromRequest(r), r.URL.Query().Get("status"), page)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to get volunteer enrollments: %v", err), http.StatusInternalServerError)
+		logging.FromRequest(r).Error("GetVolunteerEnrollments error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get volunteer enrollments")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(enrollments)
+	respondJSON(w, http.StatusOK, pagination.NewPage(enrollments, total, page))
 }
 
 // UpdateEnrollmentStatus updates the status of an enrollment
@@ -126,18 +284,37 @@ func (h *EnrollmentHandler) UpdateEnrollmentStatus(w http.ResponseWriter, r *htt
 	enrollmentID := vars["enrollmentId"]
 
 	var req models.UpdateEnrollmentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("ERROR: Failed to decode update enrollment request: %v", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeBody(w, r, &req) {
 		return
 	}
 
-	log.Printf("DEBUG: UpdateEnrollmentStatus request - EnrollmentID: %s, Action: %s", enrollmentID, req.Action)
+	logging.FromRequest(r).Debug("UpdateEnrollmentStatus request", "enrollment", enrollmentID, "action", req.Action)
 
-	// Validate action
-	if req.Action != "accept" && req.Action != "reject" && req.Action != "withdraw" {
-		log.Printf("ERROR: Invalid action provided: %s", req.Action)
-		http.Error(w, "Invalid action (must be 'accept', 'reject' or 'withdraw')", http.StatusBadRequest)
+	var responseMessage string
+	if req.ResponseMessage != nil {
+		responseMessage = *req.ResponseMessage
+	}
+
+	status, err := h.enrollmentService.UpdateEnrollmentStatus(r.Context(), enrollmentID, req.Action, responseMessage, auth.UserID(r), tenant.FromRequest(r))
+	if err != nil {
+		logging.FromRequest(r).Error("Failed to update enrollment status",
+			"enrollment", enrollmentID, "action", req.Action, "error", err)
+		if respondScheduleConflict(w, err) || respondUnsignedWaivers(w, err) {
+			return
+		}
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update enrollment")
+		return
+	}
+
+	logging.FromRequest(r).Info("Enrollment action executed", "action", req.Action, "enrollment", enrollmentID, "status", status)
+	respondJSON(w, http.StatusOK, map[string]string{"status": status})
+}
+
+// BulkUpdateEnrollments accepts or rejects many enrollments at once and
+// reports how each one went
+func (h *EnrollmentHandler) BulkUpdateEnrollments(w http.ResponseWriter, r *http.Request) {
+	var req models.BulkUpdateEnrollmentsRequest
+	if !decodeBody(w, r, &req) {
 		return
 	}
 
@@ -146,21 +323,182 @@ func (h *EnrollmentHandler) UpdateEnrollmentStatus(w http.ResponseWriter, r *htt
 		responseMessage = *req.ResponseMessage
 	}
 
-	err := h.enrollmentService.UpdateEnrollmentStatus(enrollmentID, req.Action, responseMessage)
+	report, err := h.enrollmentService.BulkUpdateEnrollmentStatus(r.Context(), req.EnrollmentIDs, req.Action, responseMessage, auth.UserID(r), tenant.FromRequest(r))
 	if err != nil {
-		log.Printf("ERROR: Failed to update enrollment status - enrollmentID: %s, action: %s, error: %v",
-			enrollmentID, req.Action, err)
-		// Map invalid transitions to 400
-		if strings.HasPrefix(err.Error(), "cannot ") || strings.Contains(strings.ToLower(err.Error()), "invalid action") {
-			http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
-			return
-		}
-		http.Error(w, fmt.Sprintf("Failed to update enrollment: %v", err), http.StatusInternalServerError)
+		logging.FromRequest(r).Error("Failed to update enrollments", "action", req.Action, "count", len(req.EnrollmentIDs), "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update enrollments")
+		return
+	}
+
+	logging.FromRequest(r).Info("Bulk enrollment action executed", "action", req.Action, "succeeded", report.Succeeded, "failed", report.Failed)
+	respondJSON(w, http.StatusOK, report)
+}
+
+// CompleteEnrollment marks an active enrollment completed at project
+// close-out (people who can manage the project)
+func (h *EnrollmentHandler) CompleteEnrollment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	enrollmentID := vars["enrollmentId"]
+
+	if _, err := h.enrollmentService.UpdateEnrollmentStatus(r.Context(), enrollmentID, "complete", "", auth.UserID(r), tenant.FromRequest(r)); err != nil {
+		logging.FromRequest(r).Error("Failed to complete enrollment", "enrollment", enrollmentID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to complete enrollment")
+		return
+	}
+
+	enr, err := h.enrollmentService.GetEnrollment(r.Context(), enrollmentID, tenant.FromRequest(r))
+	if err != nil {
+		logging.FromRequest(r).Error("Failed to get enrollment", "enrollment", enrollmentID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get enrollment")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, enr)
+}
+
+// LogHours records hours worked under an enrollment. Volunteers log their
+// own hours; project coordinators may log on a volunteer's behalf.
+func (h *EnrollmentHandler) LogHours(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	enrollmentID := vars["enrollmentId"]
+
+	var req models.LogHoursRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	enr, userID, ok := h.authorizeEnrollment(w, r, enrollmentID, "Only the volunteer or the project's coordinators can log hours")
+	if !ok {
+		return
+	}
+
+	entry, err := h.enrollmentService.LogHours(r.Context(), enr, req, userID)
+	if err != nil {
+		logging.FromRequest(r).Error("Failed to log hours", "enrollment", enrollmentID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to log hours")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// CheckIn starts recording the volunteer's attendance, optionally for a
+// shift they are booked onto
+func (h *EnrollmentHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	enrollmentID := mux.Vars(r)["enrollmentId"]
+
+	var req models.CheckInRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	enr, userID, ok := h.authorizeEnrollment(w, r, enrollmentID, "Only the volunteer or the project's coordinators can record attendance")
+	if !ok {
+		return
+	}
+
+	attendance, err := h.enrollmentService.CheckIn(r.Context(), enr, req, userID)
+	switch {
+	case err == nil:
+	case errors.Is(err, enrollment.ErrAttendanceNotEnrolled), errors.Is(err, enrollment.ErrShiftNotBooked):
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, enrollment.ErrAlreadyCheckedIn):
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("Failed to check in", "enrollment", enrollmentID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check in")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, attendance)
+}
+
+// CheckOut ends the volunteer's attendance and logs the time since check-in
+// as hours
+func (h *EnrollmentHandler) CheckOut(w http.ResponseWriter, r *http.Request) {
+	enrollmentID := mux.Vars(r)["enrollmentId"]
+
+	var req models.CheckOutRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	enr, userID, ok := h.authorizeEnrollment(w, r, enrollmentID, "Only the volunteer or the project's coordinators can record attendance")
+	if !ok {
+		return
+	}
+
+	attendance, err := h.enrollmentService.CheckOut(r.Context(), enr, req, userID)
+	if errors.Is(err, enrollment.ErrNotCheckedIn) {
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("Failed to check out", "enrollment", enrollmentID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check out")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, attendance)
+}
+
+// GetAttendance lists an enrollment's check-ins, latest first
+func (h *EnrollmentHandler) GetAttendance(w http.ResponseWriter, r *http.Request) {
+	enrollmentID := mux.Vars(r)["enrollmentId"]
+
+	if _, _, ok := h.authorizeEnrollment(w, r, enrollmentID, "Only the volunteer or the project's coordinators can view attendance"); !ok {
+		return
+	}
+
+	sessions, err := h.enrollmentService.GetAttendance(r.Context(), enrollmentID)
+	if err != nil {
+		logging.FromRequest(r).Error("Failed to get attendance", "enrollment", enrollmentID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get attendance")
 		return
 	}
 
-	log.Printf("INFO: Successfully executed %s action on enrollment %s", req.Action, enrollmentID)
-	w.WriteHeader(http.StatusOK)
+	respondJSON(w, http.StatusOK, sessions)
+}
+
+// authorizeEnrollment loads the enrollment and checks the signed-in user is
+// its volunteer or can manage its project. It writes the error response,
+// with forbidden as the 403 message, and returns false otherwise.
+func (h *EnrollmentHandler) authorizeEnrollment(w http.ResponseWriter, r *http.Request, enrollmentID, forbidden string) (*models.Enrollment, string, bool) {
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return nil, "", false
+	}
+
+	enr, err := h.enrollmentService.GetEnrollment(r.Context(), enrollmentID, tenant.FromRequest(r))
+	if err == enrollment.ErrEnrollmentNotFound {
+		apierror.Write(w, http.StatusNotFound, "Enrollment not found")
+		return nil, "", false
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("Failed to get enrollment", "enrollment", enrollmentID, "error", err)
+		apierror.Write(w, http.StatusInternalServerError, "Failed to get enrollment")
+		return nil, "", false
+	}
+
+	if enr.VolunteerID != userID {
+		allowed, err := h.organizationsService.CanManageProject(userID, enr.ProjectID)
+		if err != nil {
+			logging.FromRequest(r).Error("Failed to check project permissions", "project", enr.ProjectID, "user", userID, "error", err)
+			apierror.Write(w, http.StatusInternalServerError, "Failed to check project permissions")
+			return nil, "", false
+		}
+		if !allowed {
+			apierror.Write(w, http.StatusForbidden, forbidden)
+			return nil, "", false
+		}
+	}
+
+	return enr, userID, true
 }
 
 // CheckEnrollmentStatus checks if a volunteer is enrolled in a project
@@ -169,9 +507,14 @@ func (h *EnrollmentHandler) CheckEnrollmentStatus(w http.ResponseWriter, r *http
 	volunteerID := vars["volunteerId"]
 	projectID := vars["projectId"]
 
-	enrolled, err := h.enrollmentService.IsVolunteerEnrolled(volunteerID, projectID)
+	if !requireSelfOrAdmin(w, r, h.organizationsService, volunteerID, "Volunteers can only check their own enrollments") {
+		return
+	}
+
+	enrolled, err := h.enrollmentService.IsVolunteerEnrolled(r.Context(), volunteerID, projectID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to check enrollment status: %v", err), http.StatusInternalServerError)
+		logging.FromRequest(r).Error("Failed to check enrollment status", "volunteer", volunteerID, "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check enrollment status")
 		return
 	}
 
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/civic-weave/backend/internal/apikeys"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+func TestEnrollmentParties(t *testing.T) {
+	volunteer := "volunteer-1"
+	empty := ""
+	key := &models.APIKey{
+		ID:             "key-1",
+		OrganizationID: "org-1",
+		Scopes:         []string{models.APIKeyScopeEnrollmentsWrite},
+	}
+
+	tests := []struct {
+		name            string
+		userID          string
+		key             *models.APIKey
+		req             models.CreateEnrollmentRequest
+		wantVolunteer   string
+		wantInitiatedBy string
+		wantStatus      int
+	}{
+		{
+			name:            "api key request for named volunteer",
+			key:             key,
+			req:             models.CreateEnrollmentRequest{Action: "request", VolunteerID: &volunteer},
+			wantVolunteer:   volunteer,
+			wantInitiatedBy: volunteer,
+		},
+		{
+			name:       "api key request without volunteer",
+			key:        key,
+			req:        models.CreateEnrollmentRequest{Action: "request"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "api key request with empty volunteer",
+			key:        key,
+			req:        models.CreateEnrollmentRequest{Action: "request", VolunteerID: &empty},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "api key invite",
+			key:        key,
+			req:        models.CreateEnrollmentRequest{Action: "invite", VolunteerID: &volunteer},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:            "signed-in volunteer requests for themselves",
+			userID:          "user-1",
+			req:             models.CreateEnrollmentRequest{Action: "request", VolunteerID: &volunteer},
+			wantVolunteer:   "user-1",
+			wantInitiatedBy: "user-1",
+		},
+		{
+			name:            "signed-in coordinator invites named volunteer",
+			userID:          "user-1",
+			req:             models.CreateEnrollmentRequest{Action: "invite", VolunteerID: &volunteer},
+			wantVolunteer:   volunteer,
+			wantInitiatedBy: "user-1",
+		},
+		{
+			name:       "signed-in invite without volunteer",
+			userID:     "user-1",
+			req:        models.CreateEnrollmentRequest{Action: "invite"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "no user and no key",
+			req:        models.CreateEnrollmentRequest{Action: "request"},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/enrollments", nil)
+			if tt.userID != "" {
+				r = r.WithContext(auth.WithClaims(r.Context(), &auth.Claims{UserID: tt.userID}))
+			}
+			if tt.key != nil {
+				r = r.WithContext(apikeys.WithKey(r.Context(), tt.key))
+			}
+
+			volunteerID, initiatedBy, status, _ := enrollmentParties(r, tt.req)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if volunteerID != tt.wantVolunteer || initiatedBy != tt.wantInitiatedBy {
+				t.Errorf("enrollmentParties() = (%q, %q), want (%q, %q)",
+					volunteerID, initiatedBy, tt.wantVolunteer, tt.wantInitiatedBy)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/analytics"
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/availability"
+	"github.com/civic-weave/backend/internal/avatars"
+	"github.com/civic-weave/backend/internal/calendar"
+	"github.com/civic-weave/backend/internal/connectors"
+	"github.com/civic-weave/backend/internal/documents"
+	"github.com/civic-weave/backend/internal/duplicates"
+	"github.com/civic-weave/backend/internal/enrollment"
+	"github.com/civic-weave/backend/internal/export"
+	"github.com/civic-weave/backend/internal/gallery"
+	"github.com/civic-weave/backend/internal/geo"
+	"github.com/civic-weave/backend/internal/impact"
+	"github.com/civic-weave/backend/internal/imports"
+	"github.com/civic-weave/backend/internal/jobs"
+	"github.com/civic-weave/backend/internal/locations"
+	"github.com/civic-weave/backend/internal/matching"
+	"github.com/civic-weave/backend/internal/moderation"
+	"github.com/civic-weave/backend/internal/notifications"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/civic-weave/backend/internal/pagination"
+	"github.com/civic-weave/backend/internal/profiles"
+	"github.com/civic-weave/backend/internal/projects"
+	"github.com/civic-weave/backend/internal/quotas"
+	"github.com/civic-weave/backend/internal/ratings"
+	"github.com/civic-weave/backend/internal/regions"
+	"github.com/civic-weave/backend/internal/reviews"
+	"github.com/civic-weave/backend/internal/shifts"
+	"github.com/civic-weave/backend/internal/skills"
+	"github.com/civic-weave/backend/internal/snapshot"
+	"github.com/civic-weave/backend/internal/teams"
+	"github.com/civic-weave/backend/internal/waivers"
+	"github.com/civic-weave/backend/internal/webhooks"
+)
+
+// Service errors are reported with these statuses wherever a handler passes
+// them to apierror.WriteErr. Errors whose status depends on the endpoint,
+// such as a wrong password when signing in versus when changing it, are
+// left to their handlers.
+func init() {
+	apierror.Register(http.StatusBadRequest,
+		analytics.ErrInvalidEventID,
+		analytics.ErrInvalidEventType,
+		analytics.ErrInvalidInterval,
+		analytics.ErrInvalidPeriod,
+		analytics.ErrInvalidProperties,
+		analytics.ErrNoEvents,
+		analytics.ErrPropertiesTooLarge,
+		analytics.ErrSessionIDTooLong,
+		analytics.ErrTooManyEvents,
+		auth.ErrAdminRuleApproval,
+		auth.ErrInvalidDomain,
+		auth.ErrInvalidResetToken,
+		auth.ErrInvalidRoleRequestDecision,
+		auth.ErrInvalidRuleRole,
+		auth.ErrInvalidVerificationToken,
+		auth.ErrPasswordTooLong,
+		auth.ErrPasswordTooShort,
+		availability.ErrBlackoutRange,
+		availability.ErrHolidayName,
+		availability.ErrInvalidDate,
+		availability.ErrInvalidRRule,
+		availability.ErrInvalidRange,
+		availability.ErrInvalidTime,
+		availability.ErrInvalidTimezone,
+		availability.ErrTooManyExceptions,
+		connectors.ErrNoProjects,
+		connectors.ErrTooManyProjects,
+		documents.ErrInvalidExpiry,
+		documents.ErrInvalidTitle,
+		duplicates.ErrSameAccount,
+		enrollment.ErrAttendanceNotEnrolled,
+		enrollment.ErrInvalidAction,
+		enrollment.ErrInvalidHours,
+		enrollment.ErrInvalidTransition,
+		enrollment.ErrInvalidWorkDate,
+		enrollment.ErrShiftNotBooked,
+		export.ErrUnknownField,
+		gallery.ErrCaptionTooLong,
+		gallery.ErrInvalidOrder,
+		impact.ErrInvalidImpactRange,
+		impact.ErrInvalidRecordedOn,
+		impact.ErrInvalidValue,
+		impact.ErrNameRequired,
+		impact.ErrNameTooLong,
+		impact.ErrUnitRequired,
+		imports.ErrDuplicateColumn,
+		imports.ErrMissingHeader,
+		imports.ErrNoRows,
+		imports.ErrTooManyRows,
+		jobs.ErrInvalidState,
+		locations.ErrInvalidCoordinates,
+		locations.ErrLabelRequired,
+		locations.ErrLabelTooLong,
+		matching.ErrInvalidDistanceDecay,
+		moderation.ErrDetailsTooLong,
+		moderation.ErrInvalidAction,
+		moderation.ErrInvalidCategory,
+		moderation.ErrInvalidStatus,
+		moderation.ErrInvalidTarget,
+		moderation.ErrNoteTooLong,
+		organizations.ErrAPIKeyNameRequired,
+		organizations.ErrEvidenceTooLarge,
+		organizations.ErrEvidenceType,
+		organizations.ErrInvalidAPIKeyQuota,
+		organizations.ErrInvalidAPIKeyScope,
+		organizations.ErrInvalidRole,
+		organizations.ErrInvalidVerification,
+		organizations.ErrNameRequired,
+		organizations.ErrShareWithSelf,
+		pagination.ErrCursorOffset,
+		pagination.ErrInvalidCursor,
+		pagination.ErrInvalidLimit,
+		pagination.ErrInvalidOffset,
+		pagination.ErrInvalidSort,
+		profiles.ErrInvalidAction,
+		profiles.ErrInvalidBody,
+		profiles.ErrOwnReference,
+		projects.ErrInvalidTimezone,
+		quotas.ErrInvalidPeriod,
+		ratings.ErrInvalidRating,
+		ratings.ErrUnknownTag,
+		regions.ErrBoundaryTooLarge,
+		regions.ErrInvalidBoundary,
+		regions.ErrInvalidKind,
+		regions.ErrNameRequired,
+		regions.ErrParentNotFound,
+		reviews.ErrCommentTooLong,
+		reviews.ErrInvalidAction,
+		reviews.ErrInvalidRating,
+		reviews.ErrNoOrganization,
+		shifts.ErrDuplicateAssignment,
+		shifts.ErrInvalidCapacity,
+		shifts.ErrInvalidTimes,
+		shifts.ErrNoAssignments,
+		shifts.ErrNoteTooLong,
+		shifts.ErrOwnCoverage,
+		shifts.ErrTitleTooLong,
+		shifts.ErrTooManySkills,
+		shifts.ErrUnknownSkill,
+		skills.ErrAliasRequired,
+		skills.ErrInvalidMaxTravel,
+		skills.ErrInvalidTimezone,
+		snapshot.ErrFormat,
+		snapshot.ErrInvalidArchive,
+		teams.ErrEmptyTeamMessage,
+		teams.ErrNameRequired,
+		teams.ErrNotEnrolled,
+		teams.ErrSubjectTooLong,
+		waivers.ErrInvalidBody,
+		waivers.ErrInvalidSignedName,
+		waivers.ErrInvalidTitle,
+		webhooks.ErrInvalidEvents,
+	)
+	apierror.Register(http.StatusUnauthorized,
+		auth.ErrInvalidRefreshToken,
+		calendar.ErrInvalidToken,
+	)
+	apierror.Register(http.StatusForbidden,
+		documents.ErrForbidden,
+		documents.ErrInvalidSignature,
+		documents.ErrNotOwner,
+		enrollment.ErrActionForbidden,
+		organizations.ErrInsufficientRole,
+		projects.ErrProjectHidden,
+		reviews.ErrNotEnrolled,
+		shifts.ErrNotEnrolled,
+		skills.ErrNotEnrolled,
+	)
+	apierror.Register(http.StatusNotFound,
+		analytics.ErrVolunteerNotFound,
+		auth.ErrDomainRuleNotFound,
+		auth.ErrRoleRequestNotFound,
+		auth.ErrSessionNotFound,
+		availability.ErrBlackoutNotFound,
+		availability.ErrHolidayNotFound,
+		availability.ErrRuleNotFound,
+		availability.ErrVolunteerNotFound,
+		avatars.ErrUserNotFound,
+		calendar.ErrTokenNotFound,
+		calendar.ErrVolunteerNotFound,
+		connectors.ErrUnknownSource,
+		documents.ErrDocumentNotFound,
+		documents.ErrEnrollmentNotFound,
+		documents.ErrVolunteerNotFound,
+		duplicates.ErrAccountNotFound,
+		duplicates.ErrCandidateNotFound,
+		enrollment.ErrEnrollmentNotFound,
+		enrollment.ErrProjectNotFound,
+		export.ErrUnknownDataset,
+		gallery.ErrPhotoNotFound,
+		gallery.ErrProjectNotFound,
+		impact.ErrMetricNotFound,
+		impact.ErrProjectNotFound,
+		jobs.ErrJobNotFound,
+		locations.ErrLocationNotFound,
+		matching.ErrVolunteerNotFound,
+		moderation.ErrReportNotFound,
+		moderation.ErrTargetNotFound,
+		moderation.ErrUserNotFound,
+		notifications.ErrNotificationNotFound,
+		organizations.ErrAPIKeyNotFound,
+		organizations.ErrEvidenceNotFound,
+		organizations.ErrInvitationNotFound,
+		organizations.ErrInviteNotFound,
+		organizations.ErrOrganizationNotFound,
+		organizations.ErrShareNotFound,
+		profiles.ErrNotEnrolled,
+		profiles.ErrProjectNotFound,
+		profiles.ErrReferenceNotFound,
+		profiles.ErrVolunteerNotFound,
+		projects.ErrProjectNotFound,
+		projects.ErrTranslationNotFound,
+		ratings.ErrNotEnrolled,
+		ratings.ErrProjectNotFound,
+		regions.ErrRegionNotFound,
+		reviews.ErrProjectNotFound,
+		reviews.ErrReviewNotFound,
+		shifts.ErrCoverageNotFound,
+		shifts.ErrProjectNotFound,
+		shifts.ErrShiftNotFound,
+		skills.ErrSkillNotClaimed,
+		skills.ErrSkillNotFound,
+		teams.ErrMemberNotFound,
+		teams.ErrProjectNotFound,
+		teams.ErrTeamNotFound,
+		waivers.ErrOrganizationNotFound,
+		waivers.ErrProjectNotFound,
+		waivers.ErrWaiverNotFound,
+		webhooks.ErrWebhookNotFound,
+	)
+	apierror.Register(http.StatusConflict,
+		auth.ErrDomainRuleExists,
+		auth.ErrEmailAlreadyVerified,
+		auth.ErrRoleRequestNotPending,
+		auth.ErrUserExists,
+		availability.ErrHolidayExists,
+		availability.ErrTooManyBlackouts,
+		availability.ErrTooManyRules,
+		documents.ErrNotScanned,
+		documents.ErrQuarantined,
+		documents.ErrRetained,
+		enrollment.ErrAlreadyCheckedIn,
+		enrollment.ErrAlreadyEnrolled,
+		enrollment.ErrNotCheckedIn,
+		enrollment.ErrNotEnrolled,
+		enrollment.ErrProjectFull,
+		gallery.ErrGalleryFull,
+		impact.ErrMetricNameTaken,
+		jobs.ErrJobNotFailed,
+		locations.ErrLabelTaken,
+		locations.ErrTooManyLocations,
+		moderation.ErrAlreadyReported,
+		moderation.ErrCannotSuspendAdmin,
+		moderation.ErrNoOwner,
+		moderation.ErrNotSuspended,
+		moderation.ErrReportClosed,
+		organizations.ErrAlreadyMember,
+		organizations.ErrEvidenceRequired,
+		organizations.ErrInvitationPending,
+		organizations.ErrSlugTaken,
+		organizations.ErrVerificationNotAllowed,
+		organizations.ErrVerificationNotPending,
+		profiles.ErrReferenceExists,
+		ratings.ErrNotCompleted,
+		reviews.ErrNotCompleted,
+		reviews.ErrReviewNotPending,
+		shifts.ErrAlreadySignedUp,
+		shifts.ErrCapacityBelowSignups,
+		shifts.ErrCoverageClosed,
+		shifts.ErrCoverageRequested,
+		shifts.ErrShiftConflict,
+		shifts.ErrShiftFull,
+		shifts.ErrShiftStarted,
+		shifts.ErrUnavailable,
+		skills.ErrAliasExists,
+		skills.ErrSkillExists,
+		teams.ErrNoTeamMembers,
+		teams.ErrTeamNameTaken,
+		waivers.ErrAlreadySigned,
+		waivers.ErrStaleVersion,
+	)
+	apierror.Register(http.StatusGone,
+		organizations.ErrInvitationExpired,
+	)
+	apierror.Register(http.StatusRequestEntityTooLarge,
+		avatars.ErrImageTooLarge,
+		documents.ErrFileTooLarge,
+		gallery.ErrImageTooLarge,
+	)
+	apierror.Register(http.StatusUnsupportedMediaType,
+		avatars.ErrImageDimensions,
+		avatars.ErrUnsupportedImage,
+		documents.ErrUnsupportedFile,
+		gallery.ErrImageDimensions,
+		gallery.ErrUnsupportedImage,
+	)
+	apierror.Register(http.StatusUnprocessableEntity,
+		geo.ErrTooManyPoints,
+	)
+	apierror.Register(http.StatusTooManyRequests,
+		auth.ErrVerificationRecentlySent,
+	)
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/export"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type ExportHandler struct {
+	exportService *export.Service
+}
+
+func NewExportHandler(exportService *export.Service) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportDataset streams users, projects, enrollments or hours as CSV or
+// XLSX, with an optional ?fields= selection
+func (h *ExportHandler) ExportDataset(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	dataset, err := export.GetDataset(mux.Vars(r)["dataset"])
+	if err != nil {
+		apierror.Write(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	format, err := export.ParseFormat(query.Get("format"))
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	fields, err := dataset.SelectFields(query.Get("fields"))
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, fmt.Sprintf("%v; valid fields are %s", err, fieldNames(dataset.Fields)))
+		return
+	}
+
+	tenantID := tenant.FromRequest(r)
+	filename := fmt.Sprintf("%s-%s", dataset.Name, time.Now().UTC().Format("2006-01-02"))
+	out := startDownload(w, filename, format)
+	err = h.exportService.Stream(export.NewWriter(out, format), dataset, fields, tenantID)
+	if err != nil {
+		logging.FromRequest(r).Error("ExportDataset error", "dataset", dataset.Name, "tenant", tenantID, "error", err)
+		out.fail(err, "Failed to export "+dataset.Name)
+	}
+}
+
+func fieldNames(fields []export.Field) string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// downloadWriter sends a file download, holding the attachment headers back
+// until the first byte so a failure before then can still get a JSON error
+type downloadWriter struct {
+	w           http.ResponseWriter
+	filename    string
+	contentType string
+	started     bool
+}
+
+func startDownload(w http.ResponseWriter, filename, format string) *downloadWriter {
+	return &downloadWriter{
+		w:           w,
+		filename:    filename + "." + format,
+		contentType: export.ContentType(format),
+	}
+}
+
+func (d *downloadWriter) Write(p []byte) (int, error) {
+	if !d.started {
+		d.started = true
+		d.w.Header().Set("Content-Type", d.contentType)
+		d.w.Header().Set("Content-Disposition", `attachment; filename="`+d.filename+`"`)
+		d.w.WriteHeader(http.StatusOK)
+	}
+	return d.w.Write(p)
+}
+
+// fail reports an error if nothing has been sent yet; otherwise the download
+// is already under way and is simply cut short
+func (d *downloadWriter) fail(err error, message string) {
+	if d.started {
+		return
+	}
+	apierror.WriteErr(d.w, err, http.StatusInternalServerError, message)
+}
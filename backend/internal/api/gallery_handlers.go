@@ -0,0 +1,215 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/gallery"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type GalleryHandler struct {
+	galleryService       *gallery.Service
+	organizationsService *organizations.Service
+}
+
+func NewGalleryHandler(galleryService *gallery.Service, organizationsService *organizations.Service) *GalleryHandler {
+	return &GalleryHandler{galleryService: galleryService, organizationsService: organizationsService}
+}
+
+// GetPhotos lists a project's gallery in order
+func (h *GalleryHandler) GetPhotos(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	photos, err := h.galleryService.GetPhotos(projectID, tenant.FromRequest(r))
+	if err == gallery.ErrProjectNotFound {
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetPhotos error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch photos")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, photos)
+}
+
+// UploadPhoto adds the image in the multipart "photo" field to the end of
+// the gallery, with an optional "caption" field
+func (h *GalleryHandler) UploadPhoto(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+	userID, ok := h.authorizeProject(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, gallery.MaxBytes+multipartOverhead)
+	file, _, err := r.FormFile("photo")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		apierror.Write(w, http.StatusRequestEntityTooLarge, gallery.ErrImageTooLarge.Error())
+		return
+	}
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, "Multipart form with a photo file is required")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, gallery.MaxBytes+1))
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, "Failed to read photo")
+		return
+	}
+	var caption *string
+	if values, ok := r.MultipartForm.Value["caption"]; ok && len(values) > 0 {
+		caption = &values[0]
+	}
+
+	photo, err := h.galleryService.Upload(r.Context(), projectID, tenant.FromRequest(r), userID, data, caption)
+	switch err {
+	case nil:
+	case gallery.ErrImageTooLarge:
+		apierror.Write(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	case gallery.ErrUnsupportedImage, gallery.ErrImageDimensions:
+		apierror.Write(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	case gallery.ErrCaptionTooLong:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case gallery.ErrGalleryFull:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	case gallery.ErrProjectNotFound:
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	default:
+		logging.FromRequest(r).Error("UploadPhoto error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to upload photo")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, photo)
+}
+
+// UpdatePhoto changes a photo's caption
+func (h *GalleryHandler) UpdatePhoto(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	photoID := vars["photoId"]
+	if _, ok := h.authorizeProject(w, r, projectID); !ok {
+		return
+	}
+
+	var req models.UpdatePhotoRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	photo, err := h.galleryService.UpdateCaption(projectID, photoID, tenant.FromRequest(r), req.Caption)
+	switch err {
+	case nil:
+	case gallery.ErrCaptionTooLong:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case gallery.ErrProjectNotFound:
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	case gallery.ErrPhotoNotFound:
+		apierror.Write(w, http.StatusNotFound, "Photo not found")
+		return
+	default:
+		logging.FromRequest(r).Error("UpdatePhoto error", "project", projectID, "photo", photoID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update photo")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, photo)
+}
+
+// DeletePhoto removes a photo from the gallery
+func (h *GalleryHandler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	photoID := vars["photoId"]
+	if _, ok := h.authorizeProject(w, r, projectID); !ok {
+		return
+	}
+
+	err := h.galleryService.Delete(r.Context(), projectID, photoID, tenant.FromRequest(r))
+	switch err {
+	case nil:
+	case gallery.ErrProjectNotFound:
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	case gallery.ErrPhotoNotFound:
+		apierror.Write(w, http.StatusNotFound, "Photo not found")
+		return
+	default:
+		logging.FromRequest(r).Error("DeletePhoto error", "project", projectID, "photo", photoID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to delete photo")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReorderPhotos puts the gallery in the order given
+func (h *GalleryHandler) ReorderPhotos(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+	if _, ok := h.authorizeProject(w, r, projectID); !ok {
+		return
+	}
+
+	var req models.ReorderPhotosRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	photos, err := h.galleryService.Reorder(projectID, tenant.FromRequest(r), req.PhotoIDs)
+	switch err {
+	case nil:
+	case gallery.ErrInvalidOrder:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case gallery.ErrProjectNotFound:
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	default:
+		logging.FromRequest(r).Error("ReorderPhotos error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to reorder photos")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, photos)
+}
+
+func (h *GalleryHandler) authorizeProject(w http.ResponseWriter, r *http.Request, projectID string) (string, bool) {
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return "", false
+	}
+
+	allowed, err := h.organizationsService.CanManageProject(userID, projectID)
+	if err != nil {
+		logging.FromRequest(r).Error("CanManageProject error", "project", projectID, "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check project permissions")
+		return "", false
+	}
+	if !allowed {
+		apierror.Write(w, http.StatusForbidden, "Only the project coordinator or organization coordinators can manage the gallery")
+		return "", false
+	}
+
+	return userID, true
+}
@@ -1,47 +1,167 @@
 package api
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/civic-weave/backend/internal/analytics"
+	"github.com/civic-weave/backend/internal/apierror"
 	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/auth/oidc"
+	"github.com/civic-weave/backend/internal/availability"
 	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/events"
+	"github.com/civic-weave/backend/internal/i18n"
+	"github.com/civic-weave/backend/internal/jobs"
+	"github.com/civic-weave/backend/internal/logging"
 	"github.com/civic-weave/backend/internal/matching"
 	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/notifications"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/civic-weave/backend/internal/pagination"
 	"github.com/civic-weave/backend/internal/projects"
+	"github.com/civic-weave/backend/internal/ratings"
 	"github.com/civic-weave/backend/internal/skills"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/civic-weave/backend/internal/validate"
+	"github.com/civic-weave/backend/internal/webhooks"
 	"github.com/gorilla/mux"
 )
 
+// maxUserAgentBytes bounds the user agent recorded for a session
+const maxUserAgentBytes = 512
+
 type Handler struct {
-	authService     *auth.Service
-	skillsService   *skills.Service
-	projectsService *projects.Service
-	matchingService *matching.Service
+	analyticsService     *analytics.Service
+	jobsService          *jobs.Service
+	authService          *auth.Service
+	availabilityService  *availability.Service
+	skillsService        *skills.Service
+	projectsService      *projects.Service
+	matchingService      *matching.Service
+	organizationsService *organizations.Service
+	ratingsService       *ratings.Service
+	tokens               *auth.Tokens
+	matchDefaults        matching.Defaults
+	notificationsService *notifications.Service
+	webhooksService      *webhooks.Service
+	mailer               notifications.Mailer
+	links                AuthLinks
+	oauthProviders       *oidc.Providers
 }
 
-func NewHandler(db *database.PostgresDB) *Handler {
-	// Initialize schema
-	if err := db.InitSchema(); err != nil {
-		log.Printf("Warning: Failed to initialize schema: %v", err)
-	}
+// AuthLinks are the frontend pages auth flows send users to. The email
+// pages take the token as a query parameter.
+type AuthLinks struct {
+	VerifyEmailURL   string
+	ResetPasswordURL string
+	// OAuthRedirectURL finishes signing in with an external provider
+	OAuthRedirectURL string
+}
 
+// NewHandler signs users in with tokens, or through oauthProviders, and
+// sends auth emails linking to links. The default users sign in with
+// defaultUserPassword. Volunteers matching a newly published project well
+// enough are notified through notificationsService, and registrations and
+// published projects are sent to webhooksService.
+func NewHandler(db *database.PostgresDB, jobsService *jobs.Service, tokens *auth.Tokens, mailer notifications.Mailer, notificationsService *notifications.Service, webhooksService *webhooks.Service, eventBus *events.Bus, links AuthLinks, oauthProviders *oidc.Providers, matchDefaults matching.Defaults, defaultUserPassword string) *Handler {
 	authService := auth.NewService(db.DB)
 
 	// Create default users for testing
-	if err := authService.CreateDefaultUsers(); err != nil {
-		log.Printf("Warning: Failed to create default users: %v", err)
+	if err := authService.CreateDefaultUsers(defaultUserPassword); err != nil {
+		slog.Warn("Failed to create default users", "error", err)
+	}
+
+	matchingService := matching.NewService(db.DB, matchDefaults)
+
+	// Invalidate cached matches when skills change on any instance
+	if _, err := db.Listen(database.MatchInvalidationChannel, matchingService.HandleInvalidation); err != nil {
+		slog.Warn("Failed to listen for match invalidations", "error", err)
+	}
+	// and when enrollments change
+	eventBus.Handle(events.TypeEnrollmentChanged, matchingService.HandleEvent)
+	eventBus.Handle(events.TypeResync, matchingService.HandleEvent)
+
+	jobsService.Register(matching.RefreshSkillVectorsJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			return matchingService.RefreshSkillVectors(ctx)
+		},
+		Timeout: 15 * time.Minute,
+	})
+	jobsService.Register(matching.RecomputeMatchesJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			// Scheduled runs carry no scope and recompute everything
+			var p recomputeMatchesPayload
+			if err := json.Unmarshal(payload, &p); err != nil {
+				return fmt.Errorf("invalid payload %s", payload)
+			}
+			if p.Scope == "" {
+				p.Scope = matching.RecomputeAll
+			}
+			_, err := matchingService.RecomputeMatches(ctx, p.Scope)
+			return err
+		},
+		Timeout: 30 * time.Minute,
+	})
+
+	// Recompute stale matches as skills, locations and projects change on
+	// any instance, first catching up on changes made while none listened
+	queueStale := func(string) {
+		_, err := jobsService.EnqueueUnique(matching.RecomputeMatchesJob, matching.RecomputeStale, recomputeMatchesPayload{Scope: matching.RecomputeStale})
+		if err != nil {
+			slog.Error("Queue stale match recomputation error", "error", err)
+		}
+	}
+	if _, err := db.Listen(database.MatchesStaleChannel, queueStale); err != nil {
+		slog.Warn("Failed to listen for stale matches", "error", err)
 	}
+	go queueStale("")
+
+	jobsService.Register(notifications.NotifyMatchesJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			var p notifyMatchesPayload
+			if err := json.Unmarshal(payload, &p); err != nil || p.ProjectID == "" {
+				return fmt.Errorf("invalid payload %s", payload)
+			}
+			matches, err := matchingService.FindMatchingVolunteers(ctx, p.ProjectID, "", 0, 0, 0, "", true, 0)
+			if err != nil {
+				return err
+			}
+			n, err := notificationsService.NotifyMatches(ctx, p.ProjectID, matches, matchDefaults.NotifyScore)
+			if err == nil {
+				logging.FromContext(ctx).Info("Notified matching volunteers", "project", p.ProjectID, "count", n)
+			}
+			return err
+		},
+		Timeout: 5 * time.Minute,
+	})
 
 	return &Handler{
-		authService:     authService,
-		skillsService:   skills.NewService(db.DB),
-		projectsService: projects.NewService(db.DB),
-		matchingService: matching.NewService(db.DB),
+		analyticsService:     analytics.NewService(db.DB),
+		jobsService:          jobsService,
+		authService:          authService,
+		availabilityService:  availability.NewService(db.DB),
+		skillsService:        skills.NewService(db.DB),
+		projectsService:      projects.NewService(db.DB),
+		matchingService:      matchingService,
+		organizationsService: organizations.NewService(db.DB),
+		ratingsService:       ratings.NewService(db.DB),
+		tokens:               tokens,
+		notificationsService: notificationsService,
+		webhooksService:      webhooksService,
+		matchDefaults:        matchDefaults,
+		mailer:               mailer,
+		links:                links,
+		oauthProviders:       oauthProviders,
 	}
 }
 
@@ -50,60 +170,620 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 }
 
+// GetUsers lists a page of users, optionally only those in ?region= or
+// with ?role= (platform admins)
 func (h *Handler) GetUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := h.authService.GetAllUsers()
+	page, ok := parsePage(w, r, auth.UserSorts)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	users, total, err := h.authService.GetAllUsers(q.Get("region"), q.Get("role"), page)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to fetch users")
+		logging.FromRequest(r).Error("GetUsers error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch users")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, users)
+	respondJSON(w, http.StatusOK, pagination.NewPage(users, total, page))
 }
 
+// Login checks the user's password and returns a bearer token. Unknown
+// addresses and wrong passwords get the same answer.
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req models.LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &req) {
 		return
 	}
 
 	user, err := h.authService.GetUserByEmail(req.Email)
 	if err == auth.ErrUserNotFound {
-		respondError(w, http.StatusNotFound, "User not found")
+		h.recordAuthEvent(r, auth.EventLoginFailed, "", req.Email)
+		apierror.Write(w, http.StatusUnauthorized, "Invalid email or password")
+		return
+	}
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to login")
+		return
+	}
+	err = h.authService.CheckPassword(user.ID, req.Password)
+	if err == auth.ErrInvalidCredentials {
+		h.recordAuthEvent(r, auth.EventLoginFailed, user.ID, user.Email)
+		apierror.Write(w, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to login")
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to login")
+		return
+	}
+	if user.SuspendedAt != nil {
+		h.recordAuthEvent(r, auth.EventLoginFailed, user.ID, user.Email)
+		apierror.Write(w, http.StatusForbidden, "Account suspended")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, user)
+	h.recordAuthEvent(r, auth.EventLogin, user.ID, user.Email)
+	h.respondSignedIn(w, r, http.StatusOK, user)
 }
 
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	var req models.RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &req) {
 		return
 	}
 
-	if req.Email == "" || req.Name == "" {
-		respondError(w, http.StatusBadRequest, "Email and name are required")
-		return
+	locale := i18n.FromRequest(r)
+	if req.Locale != "" {
+		var ok bool
+		if locale, ok = i18n.Normalize(req.Locale); !ok {
+			apierror.Write(w, http.StatusBadRequest, "Unsupported locale")
+			return
+		}
 	}
 
-	user, err := h.authService.RegisterVolunteer(req.Name, req.Email)
+	var invitation *models.OrganizationInvitation
+	if req.InvitationToken != "" {
+		var err error
+		invitation, err = h.organizationsService.GetInvitationByToken(req.InvitationToken)
+		switch err {
+		case nil:
+		case organizations.ErrInvitationNotFound:
+			apierror.Write(w, http.StatusNotFound, "Invitation not found or no longer pending")
+			return
+		case organizations.ErrInvitationExpired:
+			apierror.Write(w, http.StatusGone, "Invitation has expired")
+			return
+		default:
+			logging.FromRequest(r).Error("Registration invitation error", "error", err)
+			apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to register user")
+			return
+		}
+		if !strings.EqualFold(invitation.Email, strings.TrimSpace(req.Email)) {
+			apierror.Write(w, http.StatusBadRequest, "Invitation is for a different email address")
+			return
+		}
+	}
+
+	user, err := h.authService.RegisterVolunteer(req.Name, req.Email, req.Password, locale)
 	if err == auth.ErrUserExists {
-		respondError(w, http.StatusConflict, "User already exists")
+		apierror.Write(w, http.StatusConflict, "User already exists")
+		return
+	}
+	if err == auth.ErrPasswordTooShort || err == auth.ErrPasswordTooLong {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	if err != nil {
-		log.Printf("Registration error: %v", err)
-		respondError(w, http.StatusInternalServerError, "Failed to register user")
+		logging.FromRequest(r).Error("Registration error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to register user")
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, user)
+	h.recordAuthEvent(r, auth.EventRegister, user.ID, user.Email)
+	h.dispatchWebhook(r, webhooks.EventUserRegistered, registeredUser(user))
+
+	// The account stays unverified if delivery fails; the volunteer can resend
+	if err := h.sendVerification(user); err != nil {
+		logging.FromRequest(r).Error("Registration verification email error", "user", user.ID, "error", err)
+	}
+
+	// Volunteers join the organization that invited them, or the one whose
+	// tenant they registered through when it allows self signup
+	if invitation != nil {
+		resp, err := h.organizationsService.AcceptInvitation(req.InvitationToken, req.Name)
+		if err != nil {
+			logging.FromRequest(r).Error("Registration invitation error", "user", user.ID, "org", invitation.OrganizationID, "error", err)
+		} else {
+			user.Role = resp.User.Role
+		}
+	} else if tenantID := tenant.FromRequest(r); tenantID != "" {
+		settings, err := h.organizationsService.GetSettings(tenantID)
+		if err != nil {
+			logging.FromRequest(r).Error("Registration membership error", "user", user.ID, "org", tenantID, "error", err)
+		} else if settings.Enrollment.AllowSelfSignup {
+			if err := h.organizationsService.AddMember(tenantID, user.ID, models.OrgRoleMember); err != nil {
+				logging.FromRequest(r).Error("Registration membership error", "user", user.ID, "org", tenantID, "error", err)
+			}
+		}
+	}
+
+	h.respondSignedIn(w, r, http.StatusCreated, user)
+}
+
+// ChangePassword sets a new password for the signed-in user, who must give
+// their current one
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	claims := auth.FromRequest(r)
+	if claims == nil {
+		apierror.Write(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.ChangePasswordRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	err := h.authService.ChangePassword(claims.UserID, req.CurrentPassword, req.NewPassword)
+	switch err {
+	case nil:
+	case auth.ErrInvalidCredentials:
+		apierror.Write(w, http.StatusForbidden, "Current password is incorrect")
+		return
+	case auth.ErrPasswordTooShort, auth.ErrPasswordTooLong:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case auth.ErrUserNotFound:
+		apierror.Write(w, http.StatusNotFound, "User not found")
+		return
+	default:
+		logging.FromRequest(r).Error("ChangePassword error", "user", claims.UserID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to change password")
+		return
+	}
+
+	h.recordAuthEvent(r, auth.EventPasswordChanged, claims.UserID, claims.Email)
+
+	// Sign out everywhere else; whoever knew the old password may be too
+	if err := h.authService.RevokeOtherSessions(claims.UserID, claims.SessionID); err != nil {
+		logging.FromRequest(r).Error("Revoke sessions error", "user", claims.UserID, "error", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyEmail marks the email address the token was sent to as verified
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req models.VerifyEmailRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID, err := h.authService.VerifyEmail(req.Token)
+	if err == auth.ErrInvalidVerificationToken {
+		apierror.Write(w, http.StatusBadRequest, "Invalid or expired verification token")
+		return
+	}
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to verify email")
+		return
+	}
+
+	user, err := h.authService.GetUser(userID)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to verify email")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, user)
+}
+
+// ResendVerification sends the signed-in user a new verification email;
+// earlier links stop working
+func (h *Handler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	user, err := h.authService.GetUser(userID)
+	if err == auth.ErrUserNotFound {
+		apierror.Write(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to send verification email")
+		return
+	}
+
+	err = h.sendVerification(user)
+	switch err {
+	case nil:
+	case auth.ErrEmailAlreadyVerified:
+		apierror.Write(w, http.StatusConflict, "Email address is already verified")
+		return
+	case auth.ErrVerificationRecentlySent:
+		w.Header().Set("Retry-After", "60")
+		apierror.Write(w, http.StatusTooManyRequests, "A verification email was sent less than a minute ago")
+		return
+	default:
+		logging.FromRequest(r).Error("ResendVerification error", "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to send verification email")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// sendVerification emails user a new verification link
+func (h *Handler) sendVerification(user *models.User) error {
+	token, expiresAt, err := h.authService.CreateVerificationToken(user.ID)
+	if err != nil {
+		return err
+	}
+	return h.mailer.Send(auth.VerificationMessage(user, token, h.links.VerifyEmailURL, expiresAt))
+}
+
+// ForgotPassword emails a password reset link to the address, if it
+// belongs to a user. It answers the same either way, so it cannot be used
+// to find out who has an account.
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ForgotPasswordRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	user, token, err := h.authService.CreatePasswordReset(req.Email)
+	if err == nil {
+		err = h.mailer.Send(auth.PasswordResetMessage(user, token, h.links.ResetPasswordURL))
+	}
+	if err != nil && err != auth.ErrUserNotFound && err != auth.ErrResetRecentlySent {
+		logging.FromRequest(r).Error("ForgotPassword error", "email", req.Email, "error", err)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ResetPassword sets a new password with the token from a reset link, and
+// ends every session of the user
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ResetPasswordRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	user, err := h.authService.ResetPassword(req.Token, req.Password)
+	switch err {
+	case nil:
+	case auth.ErrInvalidResetToken, auth.ErrPasswordTooShort, auth.ErrPasswordTooLong:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("ResetPassword error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to reset password")
+		return
+	}
+
+	h.recordAuthEvent(r, auth.EventPasswordReset, user.ID, user.Email)
+	if err := h.authService.RevokeOtherSessions(user.ID, ""); err != nil {
+		logging.FromRequest(r).Error("Revoke sessions error", "user", user.ID, "error", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// oauthStateCookie holds the state and PKCE verifier of a sign-in with an
+// external provider until the provider sends the user back
+const (
+	oauthStateCookie = "oauth_state"
+	oauthStateTTL    = 10 * time.Minute
+)
+
+// GetOAuthProviders lists the external providers users can sign in with
+func (h *Handler) GetOAuthProviders(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.oauthProviders.Names())
+}
+
+// OAuthLogin sends the user to the provider's sign-in page
+func (h *Handler) OAuthLogin(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["provider"]
+	provider, ok := h.oauthProviders.Get(name)
+	if !ok {
+		apierror.Write(w, http.StatusNotFound, "Sign-in provider not found")
+		return
+	}
+
+	state, verifier, err := oidc.NewState()
+	if err != nil {
+		apierror.Write(w, http.StatusInternalServerError, "Failed to sign in")
+		return
+	}
+	authURL, err := provider.AuthCodeURL(r.Context(), state, verifier)
+	if err != nil {
+		logging.FromRequest(r).Error("OAuth login error", "provider", name, "error", err)
+		apierror.Write(w, http.StatusBadGateway, "Sign-in provider unavailable")
+		return
+	}
+
+	http.SetCookie(w, oauthCookie(r, name, state+"."+verifier, int(oauthStateTTL.Seconds())))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// OAuthCallback finishes signing in once the provider sends the user back,
+// linking or creating the user for the identity. The user is redirected to
+// the frontend with the new session's refresh token in the URL fragment,
+// for the page to redeem at /auth/refresh, or with an error code.
+func (h *Handler) OAuthCallback(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["provider"]
+	provider, ok := h.oauthProviders.Get(name)
+	if !ok {
+		apierror.Write(w, http.StatusNotFound, "Sign-in provider not found")
+		return
+	}
+
+	// The state is single use whatever the outcome
+	cookie, err := r.Cookie(oauthStateCookie)
+	http.SetCookie(w, oauthCookie(r, name, "", -1))
+
+	q := r.URL.Query()
+	if q.Get("error") != "" {
+		h.redirectOAuth(w, r, "error", "access_denied")
+		return
+	}
+	var state, verifier string
+	if err == nil {
+		state, verifier, _ = strings.Cut(cookie.Value, ".")
+	}
+	if state == "" || verifier == "" || subtle.ConstantTimeCompare([]byte(state), []byte(q.Get("state"))) != 1 || q.Get("code") == "" {
+		h.redirectOAuth(w, r, "error", "invalid_state")
+		return
+	}
+
+	identity, err := provider.Exchange(r.Context(), q.Get("code"), verifier)
+	if err != nil {
+		logging.FromRequest(r).Error("OAuth exchange error", "provider", name, "error", err)
+		h.redirectOAuth(w, r, "error", "sign_in_failed")
+		return
+	}
+	user, created, err := h.authService.SignInWithIdentity(*identity, i18n.FromRequest(r))
+	if err == auth.ErrEmailNotVerified {
+		h.recordAuthEvent(r, auth.EventLoginFailed, "", identity.Email)
+		h.redirectOAuth(w, r, "error", "email_not_verified")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("OAuth sign-in error", "provider", name, "error", err)
+		h.redirectOAuth(w, r, "error", "sign_in_failed")
+		return
+	}
+	if user.SuspendedAt != nil {
+		h.recordAuthEvent(r, auth.EventLoginFailed, user.ID, user.Email)
+		h.redirectOAuth(w, r, "error", "account_suspended")
+		return
+	}
+
+	if created {
+		h.recordAuthEvent(r, auth.EventRegister, user.ID, user.Email)
+		h.dispatchWebhook(r, webhooks.EventUserRegistered, registeredUser(user))
+	}
+	h.recordAuthEvent(r, auth.EventLogin, user.ID, user.Email)
+	_, refreshToken, err := h.startSession(r, user)
+	if err != nil {
+		logging.FromRequest(r).Error("Start session error", "user", user.ID, "error", err)
+		h.redirectOAuth(w, r, "error", "sign_in_failed")
+		return
+	}
+	h.redirectOAuth(w, r, "refreshToken", refreshToken)
+}
+
+// redirectOAuth sends the user to the frontend page finishing sign-in with
+// key set in the URL fragment, which browsers do not send to servers
+func (h *Handler) redirectOAuth(w http.ResponseWriter, r *http.Request, key, value string) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	http.Redirect(w, r, h.links.OAuthRedirectURL+"#"+url.Values{key: {value}}.Encode(), http.StatusFound)
+}
+
+// oauthCookie scopes the state cookie to the provider's callback
+func oauthCookie(r *http.Request, provider, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    value,
+		Path:     "/api/auth/oauth/" + provider + "/callback",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		// Lax lets the cookie through on the provider's top-level redirect
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// RefreshToken renews an access token with the refresh token of its
+// session, which is replaced by a new one in the response
+func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	refreshToken, refreshExpiresAt, err := h.tokens.NewRefreshToken()
+	if err != nil {
+		logging.FromRequest(r).Error("New refresh token error", "error", err)
+		apierror.Write(w, http.StatusInternalServerError, "Failed to refresh session")
+		return
+	}
+	session, err := h.authService.RefreshSession(req.RefreshToken, refreshToken, refreshExpiresAt, auth.ClientIP(r))
+	if err == auth.ErrInvalidRefreshToken {
+		apierror.Write(w, http.StatusUnauthorized, "Invalid or expired refresh token")
+		return
+	}
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to refresh session")
+		return
+	}
+
+	user, err := h.authService.GetUser(session.UserID)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to refresh session")
+		return
+	}
+	if user.SuspendedAt != nil {
+		apierror.Write(w, http.StatusForbidden, "Account suspended")
+		return
+	}
+
+	h.respondWithTokens(w, http.StatusOK, user, session.ID, refreshToken, refreshExpiresAt)
+}
+
+// Logout ends the session the request was made in
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	claims := auth.FromRequest(r)
+	if claims == nil {
+		apierror.Write(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	if claims.SessionID != "" {
+		err := h.authService.RevokeSession(claims.UserID, claims.SessionID)
+		if err != nil && err != auth.ErrSessionNotFound {
+			apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to sign out")
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSessions lists the signed-in user's active sessions, marking the one
+// the request was made in
+func (h *Handler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	claims := auth.FromRequest(r)
+	if claims == nil {
+		apierror.Write(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	sessions, err := h.authService.GetSessions(claims.UserID, false)
+	if err != nil {
+		logging.FromRequest(r).Error("GetSessions error", "user", claims.UserID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch sessions")
+		return
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == claims.SessionID
+	}
+
+	respondJSON(w, http.StatusOK, sessions)
+}
+
+// RevokeSession ends one of the signed-in user's sessions
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	sessionID := mux.Vars(r)["sessionId"]
+
+	err := h.authService.RevokeSession(userID, sessionID)
+	if err == auth.ErrSessionNotFound {
+		apierror.Write(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("RevokeSession error", "session", sessionID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetUserSessions lists every session of the user in the path, including
+// ended ones, for admins auditing sign-ins
+func (h *Handler) GetUserSessions(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["id"]
+
+	sessions, err := h.authService.GetSessions(userID, true)
+	if err != nil {
+		logging.FromRequest(r).Error("GetUserSessions error", "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch sessions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, sessions)
+}
+
+// respondSignedIn starts a session for user on the requesting device and
+// answers with its tokens
+func (h *Handler) respondSignedIn(w http.ResponseWriter, r *http.Request, status int, user *models.User) {
+	session, refreshToken, err := h.startSession(r, user)
+	if err != nil {
+		logging.FromRequest(r).Error("Start session error", "user", user.ID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to sign in")
+		return
+	}
+
+	h.respondWithTokens(w, status, user, session.ID, refreshToken, session.ExpiresAt)
+}
+
+// startSession starts a session for user on the requesting device and
+// returns it with its refresh token
+func (h *Handler) startSession(r *http.Request, user *models.User) (*models.Session, string, error) {
+	refreshToken, refreshExpiresAt, err := h.tokens.NewRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentBytes {
+		userAgent = userAgent[:maxUserAgentBytes]
+	}
+	session, err := h.authService.CreateSession(user.ID, userAgent, auth.ClientIP(r), refreshToken, refreshExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+	return session, refreshToken, nil
+}
+
+// respondWithTokens answers with an access token for user in the session,
+// alongside the session's refresh token
+func (h *Handler) respondWithTokens(w http.ResponseWriter, status int, user *models.User, sessionID, refreshToken string, refreshExpiresAt time.Time) {
+	token, expiresAt, err := h.tokens.Issue(user, sessionID)
+	if err != nil {
+		slog.Error("Issue token error", "user", user.ID, "error", err)
+		apierror.Write(w, http.StatusInternalServerError, "Failed to sign in")
+		return
+	}
+	respondJSON(w, status, models.AuthResponse{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+		User:             user,
+	})
+}
+
+// recordAuthEvent logs a sign-in attempt, registration, or password change
+// or reset; failing to log it does not fail the request
+func (h *Handler) recordAuthEvent(r *http.Request, eventType, userID, email string) {
+	if err := h.authService.RecordAuthEvent(eventType, userID, email, auth.ClientIP(r)); err != nil {
+		logging.FromRequest(r).Error("Record auth event error", "event", eventType, "email", email, "error", err)
+	}
+}
+
+// dispatchWebhook sends event to the webhooks subscribed to it. The change
+// has already been made, so failures are only logged.
+func (h *Handler) dispatchWebhook(r *http.Request, event string, data interface{}) {
+	if _, err := h.webhooksService.Dispatch(r.Context(), event, data); err != nil {
+		logging.FromRequest(r).Error("Webhook dispatch error", "event", event, "error", err)
+	}
+}
+
+// registeredUser is the data of user.registered webhook events
+func registeredUser(user *models.User) map[string]string {
+	return map[string]string{"userId": user.ID, "name": user.Name, "email": user.Email, "role": user.Role}
 }
 
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -112,80 +792,115 @@ func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	json.NewEncoder(w).Encode(data)
 }
 
-func respondError(w http.ResponseWriter, status int, message string) {
-	respondJSON(w, status, map[string]string{"error": message})
-}
-
 // Skills handlers
 
+// GetSkills lists a page of the skill catalog, or of the skills matching
+// ?q= ranked by relevance, optionally only those in ?category=
 func (h *Handler) GetSkills(w http.ResponseWriter, r *http.Request) {
-	// Check if search query is provided
-	query := r.URL.Query().Get("q")
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	q := r.URL.Query()
+	query := q.Get("q")
 
-	var skills []models.Skill
-	var err error
+	sorts := skills.Sorts
+	if query != "" {
+		sorts = skills.SearchSorts
+	}
+	page, ok := parsePage(w, r, sorts)
+	if !ok {
+		return
+	}
 
+	var found []models.Skill
+	var total int
+	var err error
 	if query != "" {
-		skills, err = h.skillsService.SearchSkills(query, limit)
+		found, total, err = h.skillsService.SearchSkills(r.Context(), query, q.Get("category"), page)
 	} else {
-		skills, err = h.skillsService.GetAllSkills()
+		found, total, err = h.skillsService.GetAllSkills(r.Context(), q.Get("category"), page)
 	}
-
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to fetch skills")
+		logging.FromRequest(r).Error("GetSkills error", "q", query, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch skills")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, skills)
+	respondJSON(w, http.StatusOK, pagination.NewPage(found, total, page))
 }
 
 func (h *Handler) CreateSkill(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateSkillRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &req) {
 		return
 	}
 
-	if req.Name == "" {
-		respondError(w, http.StatusBadRequest, "Skill name is required")
-		return
-	}
-
-	skill, err := h.skillsService.CreateSkill(req.Name, req.Description, req.Category)
+	skill, err := h.skillsService.CreateSkill(r.Context(), req.Name, req.Description, req.Category)
 	if err == skills.ErrSkillExists {
-		respondError(w, http.StatusConflict, "Skill already exists")
+		apierror.Write(w, http.StatusConflict, "Skill already exists")
 		return
 	}
 	if err != nil {
-		log.Printf("Create skill error: %v", err)
-		respondError(w, http.StatusInternalServerError, "Failed to create skill")
+		logging.FromRequest(r).Error("Create skill error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create skill")
 		return
 	}
 
 	respondJSON(w, http.StatusCreated, skill)
 }
 
+// CreateSkillAlias adds another name a skill is matched by, e.g. in volunteer
+// imports
+func (h *Handler) CreateSkillAlias(w http.ResponseWriter, r *http.Request) {
+	skillID := mux.Vars(r)["id"]
+
+	var req models.CreateSkillAliasRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	alias, err := h.skillsService.AddAlias(r.Context(), skillID, req.Alias)
+	switch err {
+	case nil:
+	case skills.ErrAliasRequired:
+		apierror.Write(w, http.StatusBadRequest, "Alias is required")
+		return
+	case skills.ErrSkillNotFound:
+		apierror.Write(w, http.StatusNotFound, "Skill not found")
+		return
+	case skills.ErrAliasExists:
+		apierror.Write(w, http.StatusConflict, "Alias is already a skill name or alias")
+		return
+	default:
+		logging.FromRequest(r).Error("CreateSkillAlias error", "skill", skillID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to add skill alias")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, alias)
+}
+
 func (h *Handler) GetVolunteerSkills(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	volunteerID := vars["id"]
 
-	volunteerSkills, err := h.skillsService.GetVolunteerSkills(volunteerID)
+	volunteerSkills, err := h.skillsService.GetVolunteerSkills(r.Context(), volunteerID)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to fetch volunteer skills")
+		apierror.Write(w, http.StatusInternalServerError, "Failed to fetch volunteer skills")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, volunteerSkills)
 }
 
+// UpdateVolunteerSkills replaces a volunteer's skills. Volunteers can change
+// their own skills and platform admins anyone's.
 func (h *Handler) UpdateVolunteerSkills(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	volunteerID := vars["id"]
+	if !requireSelfOrAdmin(w, r, h.organizationsService, volunteerID, "Volunteers can only change their own skills") {
+		return
+	}
 
 	var req models.UpdateSkillsRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &req) {
 		return
 	}
 
@@ -202,66 +917,468 @@ func (h *Handler) UpdateVolunteerSkills(w http.ResponseWriter, r *http.Request)
 		skillUpdates[i].Score = skill.Score
 	}
 
-	err := h.skillsService.UpdateVolunteerSkills(volunteerID, skillUpdates)
+	err := h.skillsService.UpdateVolunteerSkills(r.Context(), volunteerID, skillUpdates)
 	if err != nil {
-		log.Printf("Update skills error: %v", err)
-		respondError(w, http.StatusInternalServerError, "Failed to update skills")
+		logging.FromRequest(r).Error("Update skills error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update skills")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Skills updated successfully"})
 }
 
+// VerifyVolunteerSkill lets someone who can manage a project vouch for a
+// claimed skill of a volunteer enrolled in it
+func (h *Handler) VerifyVolunteerSkill(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	volunteerID := vars["volunteerId"]
+	skillID := vars["skillId"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	allowed, err := h.organizationsService.CanManageProject(userID, projectID)
+	if err != nil {
+		logging.FromRequest(r).Error("CanManageProject error", "project", projectID, "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check project permissions")
+		return
+	}
+	if !allowed {
+		apierror.Write(w, http.StatusForbidden, "Only the project coordinator or organization coordinators can verify skills")
+		return
+	}
+
+	err = h.skillsService.VerifySkill(r.Context(), projectID, volunteerID, skillID, userID, tenant.FromRequest(r))
+	switch err {
+	case nil:
+	case skills.ErrNotEnrolled:
+		apierror.Write(w, http.StatusForbidden, "Only skills of volunteers enrolled in the project can be verified")
+		return
+	case skills.ErrSkillNotClaimed:
+		apierror.Write(w, http.StatusNotFound, "Volunteer has not claimed this skill")
+		return
+	default:
+		logging.FromRequest(r).Error("VerifySkill error", "volunteer", volunteerID, "skill", skillID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to verify skill")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateVolunteerLocation sets a volunteer's primary location. Volunteers
+// can change their own location and platform admins anyone's.
 func (h *Handler) UpdateVolunteerLocation(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	volunteerID := vars["id"]
+	if !requireSelfOrAdmin(w, r, h.organizationsService, volunteerID, "Volunteers can only change their own location") {
+		return
+	}
 
 	var req models.UpdateLocationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &req) {
 		return
 	}
 
-	err := h.skillsService.UpdateVolunteerLocation(volunteerID, req.Latitude, req.Longitude, req.LocationName)
+	err := h.skillsService.UpdateVolunteerLocation(r.Context(), volunteerID, req)
+	if err == skills.ErrInvalidMaxTravel || err == skills.ErrInvalidTimezone {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to update location")
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update location")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Location updated successfully"})
 }
 
+// UpdateUserLocale changes the language a user's emails are written in.
+// Only the user can change it.
+func (h *Handler) UpdateUserLocale(w http.ResponseWriter, r *http.Request) {
+	targetID := mux.Vars(r)["id"]
+
+	var req models.UpdateLocaleRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	locale, ok := i18n.Normalize(req.Locale)
+	if !ok {
+		apierror.Write(w, http.StatusBadRequest, "Unsupported locale")
+		return
+	}
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+	if userID != targetID {
+		apierror.Write(w, http.StatusForbidden, "Users can only change their own language")
+		return
+	}
+
+	err := h.authService.SetLocale(targetID, locale)
+	if err == auth.ErrUserNotFound {
+		apierror.Write(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("UpdateUserLocale error", "user", targetID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update language")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"locale": locale})
+}
+
 // Projects handlers
 
+// requireProjectInTenant reports a project outside the request's tenant as
+// not found, writing the error response and returning false
+func (h *Handler) requireProjectInTenant(w http.ResponseWriter, r *http.Request, projectID string) bool {
+	inTenant, err := h.projectsService.InTenant(r.Context(), projectID, tenant.FromRequest(r))
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch project")
+		return false
+	}
+	if !inTenant {
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return false
+	}
+	return true
+}
+
+// authorizeProject checks that the acting user may manage the project,
+// writing the error response and returning false when not
+func (h *Handler) authorizeProject(w http.ResponseWriter, r *http.Request, projectID string) bool {
+	if !h.requireProjectInTenant(w, r, projectID) {
+		return false
+	}
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return false
+	}
+
+	allowed, err := h.organizationsService.CanManageProject(userID, projectID)
+	if err != nil {
+		logging.FromRequest(r).Error("CanManageProject error", "project", projectID, "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check project permissions")
+		return false
+	}
+	if !allowed {
+		apierror.Write(w, http.StatusForbidden, "Only the project coordinator or organization coordinators can manage this project")
+		return false
+	}
+
+	return true
+}
+
+// GetProjects lists a page of projects, optionally only remote or on-site
+// ones (?remote=), those in ?region= or those with ?status=
 func (h *Handler) GetProjects(w http.ResponseWriter, r *http.Request) {
-	log.Printf("GetProjects: fetching all projects")
-	projects, err := h.projectsService.GetAllProjects()
+	remote, ok := parseRemoteFilter(w, r)
+	if !ok {
+		return
+	}
+	page, ok := parsePage(w, r, projects.Sorts)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	found, total, err := h.projectsService.GetAllProjects(r.Context(), tenant.FromRequest(r), remote, q.Get("region"), q.Get("status"), i18n.FromRequest(r), page)
+	if err != nil {
+		logging.FromRequest(r).Error("GetProjects error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch projects")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pagination.NewPage(found, total, page))
+}
+
+// requireSelfOrAdmin checks the request is made by userID or a platform
+// admin, otherwise writing forbidden as a 403
+func requireSelfOrAdmin(w http.ResponseWriter, r *http.Request, organizationsService *organizations.Service, userID, forbidden string) bool {
+	requester := auth.UserID(r)
+	if requester == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return false
+	}
+	if requester == userID {
+		return true
+	}
+
+	isAdmin, err := organizationsService.IsPlatformAdmin(requester)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check permissions")
+		return false
+	}
+	if !isAdmin {
+		apierror.Write(w, http.StatusForbidden, forbidden)
+		return false
+	}
+	return true
+}
+
+// GetCoordinatorDashboard returns the coordinator's projects, pending
+// requests and upcoming starts in one call. Coordinators can view their own
+// dashboard and platform admins anyone's.
+func (h *Handler) GetCoordinatorDashboard(w http.ResponseWriter, r *http.Request) {
+	coordinatorID := mux.Vars(r)["id"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+	if userID != coordinatorID {
+		isAdmin, err := h.organizationsService.IsPlatformAdmin(userID)
+		if err != nil {
+			apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check permissions")
+			return
+		}
+		if !isAdmin {
+			apierror.Write(w, http.StatusForbidden, "Coordinators can only view their own dashboard")
+			return
+		}
+	}
+
+	dashboard, err := h.projectsService.GetCoordinatorDashboard(r.Context(), coordinatorID, tenant.FromRequest(r))
+	if err != nil {
+		logging.FromRequest(r).Error("GetCoordinatorDashboard error", "coordinator", coordinatorID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to load dashboard")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dashboard)
+}
+
+// parseRemoteFilter reads the optional ?remote=true|false filter; nil means
+// no filter. It writes the error response and returns false when invalid.
+func parseRemoteFilter(w http.ResponseWriter, r *http.Request) (*bool, bool) {
+	raw := r.URL.Query().Get("remote")
+	if raw == "" {
+		return nil, true
+	}
+
+	remote, err := strconv.ParseBool(raw)
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, "remote must be true or false")
+		return nil, false
+	}
+	return &remote, true
+}
+
+// matchParams are the optional search parameters of the matching endpoints;
+// zero values are left for the matching service to default
+type matchParams struct {
+	skillWeight    float64
+	distanceWeight float64
+	maxDistanceKm  float64
+	distanceDecay  string
+	limit          int
+	// enforceRequired leaves out volunteers missing a required skill
+	enforceRequired bool
+	explain         bool
+}
+
+// parseMatchParams reads ?skillWeight, ?distanceWeight, ?maxDistanceKm,
+// ?distanceDecay, ?limit, ?enforceRequired (true by default) and ?explain.
+// It writes the error response and returns false when invalid.
+func parseMatchParams(w http.ResponseWriter, r *http.Request) (matchParams, bool) {
+	q := r.URL.Query()
+	params := matchParams{enforceRequired: true}
+
+	for _, f := range []struct {
+		param string
+		value *float64
+	}{
+		{"skillWeight", &params.skillWeight},
+		{"distanceWeight", &params.distanceWeight},
+		{"maxDistanceKm", &params.maxDistanceKm},
+	} {
+		raw := q.Get(f.param)
+		if raw == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed < 0 || math.IsInf(parsed, 0) || math.IsNaN(parsed) {
+			apierror.Write(w, http.StatusBadRequest, fmt.Sprintf("%s must be a non-negative number", f.param))
+			return matchParams{}, false
+		}
+		*f.value = parsed
+	}
+
+	if raw := q.Get("distanceDecay"); raw != "" {
+		if !matching.ValidDistanceDecay(raw) {
+			apierror.Write(w, http.StatusBadRequest, matching.ErrInvalidDistanceDecay.Error())
+			return matchParams{}, false
+		}
+		params.distanceDecay = raw
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			apierror.Write(w, http.StatusBadRequest, "limit must be a positive integer")
+			return matchParams{}, false
+		}
+		params.limit = parsed
+	}
+
+	for _, f := range []struct {
+		param string
+		value *bool
+	}{
+		{"enforceRequired", &params.enforceRequired},
+		{"explain", &params.explain},
+	} {
+		raw := q.Get(f.param)
+		if raw == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			apierror.Write(w, http.StatusBadRequest, fmt.Sprintf("%s must be true or false", f.param))
+			return matchParams{}, false
+		}
+		*f.value = parsed
+	}
+	return params, true
+}
+
+// parsePage reads the paging and sort parameters of a list endpoint. It
+// writes the error response and returns false when they are invalid.
+func parsePage(w http.ResponseWriter, r *http.Request, sorts pagination.Sorts) (pagination.Params, bool) {
+	page, err := pagination.Parse(r.URL.Query(), sorts)
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return pagination.Params{}, false
+	}
+	return page, true
+}
+
+// decodeBody decodes the JSON request body into v and checks it against its
+// validate tags. It writes a 400 for malformed JSON or a 422 listing the
+// invalid fields, and returns false, when the body can't be used.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		apierror.Write(w, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+	if err := validate.Struct(v); err != nil {
+		apierror.WriteDetails(w, http.StatusUnprocessableEntity, "Request validation failed",
+			map[string]interface{}{"fields": err})
+		return false
+	}
+	return true
+}
+
+// Radius search limits
+const (
+	defaultNearRadiusKm = 25.0
+	maxNearRadiusKm     = 500.0
+	defaultNearLimit    = 100
+	maxNearLimit        = 500
+)
+
+// GetProjectsNear lists active projects within radiusKm of lat/lon, nearest
+// first, for the map view
+func (h *Handler) GetProjectsNear(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	lat, latErr := strconv.ParseFloat(q.Get("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(q.Get("lon"), 64)
+	if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		apierror.Write(w, http.StatusBadRequest, "Valid lat and lon are required")
+		return
+	}
+
+	radiusKm := defaultNearRadiusKm
+	if raw := q.Get("radiusKm"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed <= 0 || parsed > maxNearRadiusKm {
+			apierror.Write(w, http.StatusBadRequest, fmt.Sprintf("radiusKm must be between 0 and %g", maxNearRadiusKm))
+			return
+		}
+		radiusKm = parsed
+	}
+
+	limit := defaultNearLimit
+	if raw := q.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			apierror.Write(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+	if limit > maxNearLimit {
+		limit = maxNearLimit
+	}
+
+	projects, err := h.projectsService.FindProjectsNear(r.Context(), lat, lon, radiusKm, limit, tenant.FromRequest(r), i18n.FromRequest(r))
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to fetch projects")
+		logging.FromRequest(r).Error("GetProjectsNear error", "lat", lat, "lon", lon, "radiusKm", radiusKm, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to search projects")
 		return
 	}
+	if projects == nil {
+		projects = []models.NearbyProject{}
+	}
 
 	respondJSON(w, http.StatusOK, projects)
 }
 
 func (h *Handler) CreateProject(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateProjectRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &req) {
 		return
 	}
-	if strings.TrimSpace(req.Name) == "" {
-		respondError(w, http.StatusBadRequest, "Project name is required")
+	// Projects created within a tenant belong to it
+	if tenantID := tenant.FromRequest(r); tenantID != "" {
+		if req.OrganizationID == nil {
+			req.OrganizationID = &tenantID
+		} else if *req.OrganizationID != tenantID {
+			apierror.Write(w, http.StatusForbidden, "Project organization does not match tenant")
+			return
+		}
+	}
+	if req.OrganizationID != nil {
+		userID := auth.UserID(r)
+		if userID == "" {
+			apierror.Write(w, http.StatusBadRequest, "User ID required")
+			return
+		}
+		allowed, err := h.organizationsService.CanCreateProjects(userID, *req.OrganizationID)
+		if err != nil {
+			apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check organization permissions")
+			return
+		}
+		if !allowed {
+			apierror.Write(w, http.StatusForbidden, "Only organization coordinators can create projects for this organization")
+			return
+		}
+	}
+	logging.FromRequest(r).Debug("CreateProject: status will be 'draft'", "name", req.Name, "coordinatorId", req.CoordinatorID, "organizationId", req.OrganizationID)
+	p, err := h.projectsService.CreateProject(r.Context(), req.Name, req.Description, req.CoordinatorID, req.OrganizationID, req.Latitude, req.Longitude, req.LocationName, req.IsRemote, req.Timezone, req.BlockScheduleConflicts, req.StartDate, req.EndDate, req.MaxVolunteers)
+	if err == projects.ErrInvalidTimezone {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	log.Printf("CreateProject: name=%q status will be 'draft' coordinatorId=%v", req.Name, req.CoordinatorID)
-	p, err := h.projectsService.CreateProject(req.Name, req.Description, req.CoordinatorID, req.Latitude, req.Longitude, req.LocationName, req.StartDate, req.EndDate, req.MaxVolunteers)
 	if err != nil {
-		log.Printf("CreateProject error: %v", err)
-		respondError(w, http.StatusInternalServerError, "Failed to create project")
+		logging.FromRequest(r).Error("CreateProject error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create project")
 		return
 	}
-	log.Printf("CreateProject: created id=%s status=%s", p.ID, p.Status)
+	logging.FromRequest(r).Debug("CreateProject: created", "id", p.ID, "status", p.Status)
 	respondJSON(w, http.StatusCreated, p)
 }
 
@@ -269,13 +1386,13 @@ func (h *Handler) GetProject(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	projectID := vars["id"]
 
-	project, err := h.projectsService.GetProject(projectID)
+	project, err := h.projectsService.GetProject(r.Context(), projectID, tenant.FromRequest(r), i18n.FromRequest(r))
 	if err == projects.ErrProjectNotFound {
-		respondError(w, http.StatusNotFound, "Project not found")
+		apierror.Write(w, http.StatusNotFound, "Project not found")
 		return
 	}
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to fetch project")
+		apierror.Write(w, http.StatusInternalServerError, "Failed to fetch project")
 		return
 	}
 
@@ -286,9 +1403,13 @@ func (h *Handler) GetProjectSkills(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	projectID := vars["id"]
 
-	projectSkills, err := h.projectsService.GetProjectSkills(projectID)
+	if !h.requireProjectInTenant(w, r, projectID) {
+		return
+	}
+
+	projectSkills, err := h.projectsService.GetProjectSkills(r.Context(), projectID)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to fetch project skills")
+		apierror.Write(w, http.StatusInternalServerError, "Failed to fetch project skills")
 		return
 	}
 
@@ -300,8 +1421,11 @@ func (h *Handler) UpdateProjectSkills(w http.ResponseWriter, r *http.Request) {
 	projectID := vars["id"]
 
 	var req models.UpdateProjectSkillsRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	if !h.authorizeProject(w, r, projectID) {
 		return
 	}
 
@@ -318,10 +1442,10 @@ func (h *Handler) UpdateProjectSkills(w http.ResponseWriter, r *http.Request) {
 		skillUpdates[i].Weight = skill.Weight
 	}
 
-	err := h.projectsService.SetProjectSkills(projectID, skillUpdates)
+	err := h.projectsService.SetProjectSkills(r.Context(), projectID, skillUpdates)
 	if err != nil {
-		log.Printf("Update project skills error: %v", err)
-		respondError(w, http.StatusInternalServerError, "Failed to update project skills")
+		logging.FromRequest(r).Error("Update project skills error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update project skills")
 		return
 	}
 
@@ -333,15 +1457,23 @@ func (h *Handler) UpdateProjectDetails(w http.ResponseWriter, r *http.Request) {
 	projectID := vars["id"]
 
 	var req models.UpdateProjectRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &req) {
 		return
 	}
 
-	log.Printf("UpdateProjectDetails: id=%s name=%q hasLocation=%v", projectID, req.Name, req.LocationName != nil)
-	if err := h.projectsService.UpdateProjectDetails(projectID, req.Name, req.Description, req.Latitude, req.Longitude, req.LocationName); err != nil {
-		log.Printf("UpdateProjectDetails error id=%s: %v", projectID, err)
-		respondError(w, http.StatusInternalServerError, "Failed to update project")
+	if !h.authorizeProject(w, r, projectID) {
+		return
+	}
+
+	logging.FromRequest(r).Debug("UpdateProjectDetails", "id", projectID, "name", req.Name, "hasLocation", req.LocationName != nil)
+	err := h.projectsService.UpdateProjectDetails(r.Context(), projectID, req.Name, req.Description, req.Latitude, req.Longitude, req.LocationName, req.IsRemote, req.Timezone, req.BlockScheduleConflicts)
+	if err == projects.ErrInvalidTimezone {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("UpdateProjectDetails error", "id", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update project")
 		return
 	}
 
@@ -353,66 +1485,174 @@ func (h *Handler) UpdateProjectStatus(w http.ResponseWriter, r *http.Request) {
 	projectID := vars["id"]
 
 	var req models.UpdateProjectStatusRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if !h.authorizeProject(w, r, projectID) {
 		return
 	}
-	if req.Status == "" {
-		respondError(w, http.StatusBadRequest, "Status is required")
+	// Publishing makes a project public; only verified organizations may publish
+	if req.Status == "active" {
+		allowed, err := h.organizationsService.CanPublishProject(projectID)
+		if err != nil {
+			apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check organization verification")
+			return
+		}
+		if !allowed {
+			apierror.Write(w, http.StatusForbidden, "Only verified organizations can publish projects")
+			return
+		}
+	}
+	logging.FromRequest(r).Debug("UpdateProjectStatus", "id", projectID, "status", req.Status)
+	err := h.projectsService.UpdateProjectStatus(r.Context(), projectID, req.Status)
+	if err == projects.ErrProjectHidden {
+		apierror.Write(w, http.StatusForbidden, "Project was hidden by a moderator")
 		return
 	}
-	log.Printf("UpdateProjectStatus: id=%s -> %s", projectID, req.Status)
-	if err := h.projectsService.UpdateProjectStatus(projectID, req.Status); err != nil {
-		log.Printf("UpdateProjectStatus error id=%s: %v", projectID, err)
-		respondError(w, http.StatusInternalServerError, "Failed to update status")
+	if err != nil {
+		logging.FromRequest(r).Error("UpdateProjectStatus error", "id", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update status")
 		return
 	}
+
+	if _, err := h.notificationsService.ProjectStatusChanged(r.Context(), projectID, req.Status); err != nil {
+		logging.FromRequest(r).Error("Project status notification error", "id", projectID, "error", err)
+	}
+	if req.Status == "active" {
+		h.dispatchWebhook(r, webhooks.EventProjectPublished, map[string]string{"projectId": projectID})
+	}
+	// Let well-matched volunteers know a project is looking for them
+	if req.Status == "active" && h.matchDefaults.NotifyScore > 0 {
+		if _, err := h.jobsService.EnqueueUnique(notifications.NotifyMatchesJob, projectID, notifyMatchesPayload{ProjectID: projectID}); err != nil {
+			logging.FromRequest(r).Error("Queue match notifications error", "id", projectID, "error", err)
+		}
+	}
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Status updated"})
 }
 
+type notifyMatchesPayload struct {
+	ProjectID string `json:"projectId"`
+}
+
+// GetProjectTranslations lists the languages a project's name and
+// description are translated into
+func (h *Handler) GetProjectTranslations(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+	if !h.authorizeProject(w, r, projectID) {
+		return
+	}
+
+	translations, err := h.projectsService.GetTranslations(r.Context(), projectID)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch translations")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, translations)
+}
+
+// SetProjectTranslation creates or replaces the project's translation into
+// the locale in the path
+func (h *Handler) SetProjectTranslation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+
+	locale, ok := i18n.Normalize(vars["locale"])
+	if !ok {
+		apierror.Write(w, http.StatusBadRequest, "Unsupported locale")
+		return
+	}
+	var req models.SetProjectTranslationRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if !h.authorizeProject(w, r, projectID) {
+		return
+	}
+
+	translation, err := h.projectsService.SetTranslation(r.Context(), projectID, locale, req.Name, req.Description, auth.UserID(r))
+	if err != nil {
+		logging.FromRequest(r).Error("SetProjectTranslation error", "id", projectID, "locale", locale, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to save translation")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, translation)
+}
+
+// DeleteProjectTranslation removes the project's translation into the
+// locale in the path
+func (h *Handler) DeleteProjectTranslation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+
+	locale, ok := i18n.Normalize(vars["locale"])
+	if !ok {
+		apierror.Write(w, http.StatusBadRequest, "Unsupported locale")
+		return
+	}
+	if !h.authorizeProject(w, r, projectID) {
+		return
+	}
+
+	err := h.projectsService.DeleteTranslation(r.Context(), projectID, locale)
+	if err == projects.ErrTranslationNotFound {
+		apierror.Write(w, http.StatusNotFound, "Translation not found")
+		return
+	}
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to delete translation")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // Matching handlers
 
 func (h *Handler) FindMatchesForProject(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	projectID := vars["id"]
 
-	// Get impersonation parameter (optional)
-	impersonateRole := r.URL.Query().Get("impersonate")
-
-	// Simple role check - only allow coordinators to access matching
-	// For demo purposes, we'll check if impersonate=coordinator is provided
-	if impersonateRole != "coordinator" {
-		respondError(w, http.StatusForbidden, "Access denied. Only coordinators can view volunteer matches. Use ?impersonate=coordinator")
+	if !h.requireProjectInTenant(w, r, projectID) {
 		return
 	}
 
-	// Get query parameters
-	skillWeight, _ := strconv.ParseFloat(r.URL.Query().Get("skillWeight"), 64)
-	distanceWeight, _ := strconv.ParseFloat(r.URL.Query().Get("distanceWeight"), 64)
-	maxDistanceKm, _ := strconv.ParseFloat(r.URL.Query().Get("maxDistanceKm"), 64)
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	params, ok := parseMatchParams(w, r)
+	if !ok {
+		return
+	}
+	skillWeight, distanceWeight, maxDistanceKm := params.skillWeight, params.distanceWeight, params.maxDistanceKm
 
-	// Defaults
+	// Defaults come from the hosting organization's settings, then from the
+	// matching service's
+	settings, err := h.organizationsService.GetSettingsForProject(projectID)
+	if err != nil {
+		logging.FromRequest(r).Error("Matching settings error", "project", projectID, "error", err)
+		defaults := models.DefaultOrganizationSettings("")
+		settings = &defaults
+	}
 	if skillWeight == 0 && distanceWeight == 0 {
-		skillWeight = 0.7
-		distanceWeight = 0.3
+		skillWeight = settings.Matching.SkillWeight
+		distanceWeight = settings.Matching.DistanceWeight
 	}
 	if maxDistanceKm == 0 {
-		maxDistanceKm = 100 // Default 100km radius
-	}
-	if limit == 0 {
-		limit = 20 // Default 20 results
+		maxDistanceKm = settings.Matching.MaxDistanceKm
 	}
 
 	matches, err := h.matchingService.FindMatchingVolunteers(
+		r.Context(),
 		projectID,
+		tenant.FromRequest(r),
 		skillWeight,
 		distanceWeight,
 		maxDistanceKm,
-		limit,
+		params.distanceDecay,
+		params.enforceRequired,
+		params.limit,
 	)
 	if err != nil {
-		log.Printf("Matching error: %v", err)
+		logging.FromRequest(r).Error("Matching error", "error", err)
 		// Return empty array to avoid null on frontend while investigating
 		respondJSON(w, http.StatusOK, []models.VolunteerMatch{})
 		return
@@ -422,75 +1662,172 @@ func (h *Handler) FindMatchesForProject(w http.ResponseWriter, r *http.Request)
 	if matches == nil {
 		matches = []models.VolunteerMatch{}
 	}
+
+	// Annotate availability on a copy; matches may be shared with the cache
+	matches = append([]models.VolunteerMatch(nil), matches...)
+	candidateIDs := make([]string, len(matches))
+	for i, m := range matches {
+		candidateIDs[i] = m.VolunteerID
+	}
+	fits, err := h.availabilityService.MatchAvailability(projectID, candidateIDs)
+	if err != nil {
+		logging.FromRequest(r).Error("Match availability error", "project", projectID, "error", err)
+		fits = map[string]availability.Match{}
+	}
+	onlyAvailable := r.URL.Query().Get("available") == "true"
+	minOverlap, _ := strconv.ParseFloat(r.URL.Query().Get("minOverlap"), 64)
+	annotated := matches[:0]
+	for _, m := range matches {
+		if fit, known := fits[m.VolunteerID]; known {
+			m.Available = &fit.Available
+			m.AvailabilityOverlap = &fit.Overlap
+			if (onlyAvailable && !fit.Available) || fit.Overlap < minOverlap {
+				continue
+			}
+		}
+		annotated = append(annotated, m)
+	}
+	matches = annotated
+	if matches == nil {
+		matches = []models.VolunteerMatch{}
+	}
+
+	// Rating scores are shown only to the project's coordinators, and only
+	// when the organization opts in
+	showRatings := false
+	if userID := auth.UserID(r); settings.Matching.ShowRatings && userID != "" && len(matches) > 0 {
+		showRatings, err = h.organizationsService.CanManageProject(userID, projectID)
+		if err != nil {
+			logging.FromRequest(r).Error("CanManageProject error", "project", projectID, "user", userID, "error", err)
+		}
+	}
+	if showRatings {
+		ratedIDs := make([]string, len(matches))
+		for i, m := range matches {
+			ratedIDs[i] = m.VolunteerID
+		}
+		scores, err := h.ratingsService.GetScores(ratedIDs)
+		if err != nil {
+			logging.FromRequest(r).Error("Match rating scores error", "project", projectID, "error", err)
+		}
+		for i := range matches {
+			if score, ok := scores[matches[i].VolunteerID]; ok {
+				matches[i].Rating = &score
+			}
+		}
+	}
+
+	// Shown matches are the top of the recruitment funnel
+	volunteerIDs := make([]string, len(matches))
+	for i, m := range matches {
+		volunteerIDs[i] = m.VolunteerID
+	}
+	if err := h.analyticsService.RecordMatchImpressions(projectID, volunteerIDs); err != nil {
+		logging.FromRequest(r).Error("Record match impressions error", "project", projectID, "error", err)
+	}
+
+	if params.explain {
+		matches, err = h.matchingService.ExplainVolunteerMatches(r.Context(), projectID, matches, skillWeight, distanceWeight, maxDistanceKm, params.distanceDecay)
+		if err != nil {
+			logging.FromRequest(r).Error("Explain matches error", "project", projectID, "error", err)
+			apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to explain matches")
+			return
+		}
+	}
+
 	respondJSON(w, http.StatusOK, matches)
 }
 
+// RefreshSkillVectors queues a refresh of the skill vectors matching reads;
+// requests made while one is waiting share it
 func (h *Handler) RefreshSkillVectors(w http.ResponseWriter, r *http.Request) {
-	err := h.matchingService.RefreshSkillVectors()
+	job, err := h.jobsService.EnqueueUnique(matching.RefreshSkillVectorsJob, "all", nil)
 	if err != nil {
-		log.Printf("Refresh skill vectors error: %v", err)
-		respondError(w, http.StatusInternalServerError, "Failed to refresh skill vectors")
+		logging.FromRequest(r).Error("Refresh skill vectors error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to queue skill vector refresh")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Skill vectors refreshed successfully"})
+	respondJSON(w, http.StatusAccepted, job)
 }
 
-func (h *Handler) FindMatchesForVolunteer(w http.ResponseWriter, r *http.Request) {
-	log.Printf("FindMatchesForVolunteer called")
-	vars := mux.Vars(r)
-	volunteerID := vars["id"]
-	log.Printf("Volunteer ID: %s", volunteerID)
+// recomputationListLimit is how many runs of the batch matcher are listed
+const recomputationListLimit = 20
 
-	// Impersonation: allow volunteers
-	impersonateRole := r.URL.Query().Get("impersonate")
-	log.Printf("Impersonate role: %s", impersonateRole)
-	if impersonateRole != "volunteer" {
-		respondError(w, http.StatusForbidden, "Access denied. Only volunteers can view project matches. Use ?impersonate=volunteer")
+type recomputeMatchesPayload struct {
+	Scope string `json:"scope"`
+}
+
+// RecomputeMatches queues a recomputation of every precomputed match;
+// requests made while one is waiting share it
+func (h *Handler) RecomputeMatches(w http.ResponseWriter, r *http.Request) {
+	job, err := h.jobsService.EnqueueUnique(matching.RecomputeMatchesJob, matching.RecomputeAll, recomputeMatchesPayload{Scope: matching.RecomputeAll})
+	if err != nil {
+		logging.FromRequest(r).Error("Recompute matches error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to queue match recomputation")
 		return
 	}
 
-	// Simple test response
-	respondJSON(w, http.StatusOK, []models.ProjectMatch{
-		{
-			ProjectID:     "test-id",
-			ProjectName:   "Test Project",
-			SkillScore:    0.5,
-			DistanceKm:    0.0,
-			CombinedScore: 0.5,
-			MatchedSkills: []string{},
-		},
-	})
-	return
+	respondJSON(w, http.StatusAccepted, job)
+}
 
-	skillWeight, _ := strconv.ParseFloat(r.URL.Query().Get("skillWeight"), 64)
-	distanceWeight, _ := strconv.ParseFloat(r.URL.Query().Get("distanceWeight"), 64)
-	maxDistanceKm, _ := strconv.ParseFloat(r.URL.Query().Get("maxDistanceKm"), 64)
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+// GetMatchRecomputations lists recent runs of the batch matcher, newest
+// first, with the progress of any still running
+func (h *Handler) GetMatchRecomputations(w http.ResponseWriter, r *http.Request) {
+	runs, err := h.matchingService.GetRecomputations(r.Context(), recomputationListLimit)
+	if err != nil {
+		logging.FromRequest(r).Error("Get match recomputations error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch match recomputations")
+		return
+	}
 
-	if skillWeight == 0 && distanceWeight == 0 {
-		skillWeight = 0.7
-		distanceWeight = 0.3
+	respondJSON(w, http.StatusOK, runs)
+}
+
+// FindMatchesForVolunteer ranks the active projects a volunteer matches,
+// with the skills they share with each. Volunteers can find their own
+// matches and platform admins anyone's.
+func (h *Handler) FindMatchesForVolunteer(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+	if !requireSelfOrAdmin(w, r, h.organizationsService, volunteerID, "Volunteers can only view their own matches") {
+		return
 	}
-	if maxDistanceKm == 0 {
-		maxDistanceKm = 100
+
+	params, ok := parseMatchParams(w, r)
+	if !ok {
+		return
 	}
-	if limit == 0 {
-		limit = 20
+	remote, ok := parseRemoteFilter(w, r)
+	if !ok {
+		return
 	}
 
 	matches, err := h.matchingService.FindMatchingProjects(
+		r.Context(),
 		volunteerID,
-		skillWeight,
-		distanceWeight,
-		maxDistanceKm,
-		limit,
+		tenant.FromRequest(r),
+		remote,
+		params.skillWeight,
+		params.distanceWeight,
+		params.maxDistanceKm,
+		params.distanceDecay,
+		params.enforceRequired,
+		params.limit,
 	)
 	if err != nil {
-		log.Printf("Project matching error: %v", err)
-		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Project matching error: %v", err))
+		logging.FromRequest(r).Error("Project matching error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to find matching projects")
 		return
 	}
 
+	if params.explain {
+		matches, err = h.matchingService.ExplainProjectMatches(r.Context(), volunteerID, matches, params.skillWeight, params.distanceWeight, params.maxDistanceKm, params.distanceDecay)
+		if err != nil {
+			logging.FromRequest(r).Error("Explain matches error", "volunteer", volunteerID, "error", err)
+			apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to explain matches")
+			return
+		}
+	}
+
 	respondJSON(w, http.StatusOK, matches)
 }
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/impact"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type ImpactHandler struct {
+	impactService        *impact.Service
+	organizationsService *organizations.Service
+}
+
+func NewImpactHandler(impactService *impact.Service, organizationsService *organizations.Service) *ImpactHandler {
+	return &ImpactHandler{
+		impactService:        impactService,
+		organizationsService: organizationsService,
+	}
+}
+
+// GetProjectMetrics lists a project's impact metrics with their totals
+func (h *ImpactHandler) GetProjectMetrics(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	metrics, err := h.impactService.GetProjectMetrics(projectID, tenant.FromRequest(r))
+	if err == impact.ErrProjectNotFound {
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetProjectMetrics error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch impact metrics")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, metrics)
+}
+
+// CreateMetric defines an impact metric for a project
+func (h *ImpactHandler) CreateMetric(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	var req models.CreateImpactMetricRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID, ok := h.authorizeProject(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	metric, err := h.impactService.CreateMetric(projectID, tenant.FromRequest(r), userID, req)
+	switch err {
+	case nil:
+	case impact.ErrNameRequired, impact.ErrUnitRequired, impact.ErrNameTooLong:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case impact.ErrProjectNotFound:
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	case impact.ErrMetricNameTaken:
+		apierror.Write(w, http.StatusConflict, "A metric with this name already exists in the project")
+		return
+	default:
+		logging.FromRequest(r).Error("CreateMetric error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create impact metric")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, metric)
+}
+
+// DeleteMetric removes a metric and its recorded entries
+func (h *ImpactHandler) DeleteMetric(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	metricID := vars["metricId"]
+
+	if _, ok := h.authorizeProject(w, r, projectID); !ok {
+		return
+	}
+
+	err := h.impactService.DeleteMetric(projectID, metricID, tenant.FromRequest(r))
+	if err == impact.ErrMetricNotFound {
+		apierror.Write(w, http.StatusNotFound, "Impact metric not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("DeleteMetric error", "metric", metricID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to delete impact metric")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogEntry records a value against a metric
+func (h *ImpactHandler) LogEntry(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	metricID := vars["metricId"]
+
+	var req models.LogImpactEntryRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID, ok := h.authorizeProject(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	entry, err := h.impactService.LogEntry(projectID, metricID, tenant.FromRequest(r), userID, req)
+	switch err {
+	case nil:
+	case impact.ErrInvalidValue, impact.ErrInvalidRecordedOn:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case impact.ErrMetricNotFound:
+		apierror.Write(w, http.StatusNotFound, "Impact metric not found")
+		return
+	default:
+		logging.FromRequest(r).Error("LogEntry error", "metric", metricID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to record impact")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, entry)
+}
+
+// GetEntries lists a metric's entries for an optional from/to range
+func (h *ImpactHandler) GetEntries(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	metricID := vars["metricId"]
+
+	entries, err := h.impactService.GetEntries(projectID, metricID, tenant.FromRequest(r), r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	switch err {
+	case nil:
+	case impact.ErrInvalidImpactRange:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case impact.ErrMetricNotFound:
+		apierror.Write(w, http.StatusNotFound, "Impact metric not found")
+		return
+	default:
+		logging.FromRequest(r).Error("GetEntries error", "metric", metricID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch impact entries")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, entries)
+}
+
+// GetOrganizationImpact totals impact across the organization's projects
+// for an optional from/to range (YYYY-MM-DD, default last 30 days)
+func (h *ImpactHandler) GetOrganizationImpact(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	from, to, err := organizations.ReportRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	role, err := h.organizationsService.GetMemberRole(orgID, userID)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check permissions")
+		return
+	}
+	if !organizations.RoleAtLeast(role, models.OrgRoleAdmin) {
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can view reports")
+		return
+	}
+
+	report, err := h.impactService.GetOrganizationImpact(orgID, from, to)
+	if err != nil {
+		logging.FromRequest(r).Error("GetOrganizationImpact error", "org", orgID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to build impact report")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// authorizeProject checks the signed-in user can manage the
+// project. It writes the error response and returns false otherwise.
+func (h *ImpactHandler) authorizeProject(w http.ResponseWriter, r *http.Request, projectID string) (string, bool) {
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return "", false
+	}
+
+	allowed, err := h.organizationsService.CanManageProject(userID, projectID)
+	if err != nil {
+		logging.FromRequest(r).Error("CanManageProject error", "project", projectID, "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check project permissions")
+		return "", false
+	}
+	if !allowed {
+		apierror.Write(w, http.StatusForbidden, "Only the project coordinator or organization coordinators can manage impact metrics")
+		return "", false
+	}
+
+	return userID, true
+}
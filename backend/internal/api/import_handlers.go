@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/imports"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/tenant"
+)
+
+// maxImportBytes bounds uploaded volunteer CSVs
+const maxImportBytes = 10 << 20
+
+type ImportHandler struct {
+	importsService *imports.Service
+}
+
+func NewImportHandler(importsService *imports.Service) *ImportHandler {
+	return &ImportHandler{
+		importsService: importsService,
+	}
+}
+
+// ImportVolunteers creates volunteers from a CSV sent as the request body or
+// as the multipart "file" field. Every row must be valid for any to be
+// imported; otherwise it answers 422 with the per-row report. ?dryRun=true
+// only validates.
+func (h *ImportHandler) ImportVolunteers(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	switch r.URL.Query().Get("dryRun") {
+	case "", "false":
+	case "true":
+		dryRun = true
+	default:
+		apierror.Write(w, http.StatusBadRequest, "dryRun must be true or false")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	var body io.Reader = r.Body
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		file, _, err := r.FormFile("file")
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apierror.Write(w, http.StatusRequestEntityTooLarge, "CSV may be at most 10 MB")
+			return
+		}
+		if err != nil {
+			apierror.Write(w, http.StatusBadRequest, "Multipart form with a file is required")
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	tenantID := tenant.FromRequest(r)
+	report, err := h.importsService.ImportVolunteers(r.Context(), body, tenantID, dryRun)
+	var invalidCSV *imports.InvalidCSVError
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		apierror.Write(w, http.StatusRequestEntityTooLarge, "CSV may be at most 10 MB")
+		return
+	case err == imports.ErrMissingHeader, err == imports.ErrNoRows, err == imports.ErrTooManyRows, err == imports.ErrDuplicateColumn:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.As(err, &invalidCSV):
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		logging.FromRequest(r).Error("ImportVolunteers error", "tenant", tenantID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to import volunteers")
+		return
+	}
+
+	switch {
+	case report.Invalid > 0:
+		respondJSON(w, http.StatusUnprocessableEntity, report)
+	case report.Imported:
+		respondJSON(w, http.StatusCreated, report)
+	default:
+		respondJSON(w, http.StatusOK, report)
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/jobs"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultJobListLimit = 100
+	maxJobListLimit     = 500
+)
+
+type JobHandler struct {
+	jobsService *jobs.Service
+}
+
+func NewJobHandler(jobsService *jobs.Service) *JobHandler {
+	return &JobHandler{
+		jobsService: jobsService,
+	}
+}
+
+// GetJobs lists background jobs newest first, optionally only those with
+// ?status= (queued, running, succeeded or failed) or of ?kind=
+func (h *JobHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := defaultJobListLimit
+	if raw := q.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			apierror.Write(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+	if limit > maxJobListLimit {
+		limit = maxJobListLimit
+	}
+
+	jobList, err := h.jobsService.GetJobs(q.Get("status"), q.Get("kind"), limit)
+	switch err {
+	case nil:
+	case jobs.ErrInvalidState:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("GetJobs error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch jobs")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, jobList)
+}
+
+// GetJobStatus sums up the queue: for each kind of job, how many are
+// queued, running and failed, how long the oldest has waited and when one
+// last succeeded
+func (h *JobHandler) GetJobStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.jobsService.GetStatus()
+	if err != nil {
+		logging.FromRequest(r).Error("GetJobStatus error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch job status")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, status)
+}
+
+// RetryJob queues a failed job again
+func (h *JobHandler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r)
+	jobID := mux.Vars(r)["jobId"]
+
+	job, err := h.jobsService.RetryJob(jobID)
+	switch err {
+	case nil:
+	case jobs.ErrJobNotFound:
+		apierror.Write(w, http.StatusNotFound, err.Error())
+		return
+	case jobs.ErrJobNotFailed:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("RetryJob error", "job", jobID, "admin", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to retry job")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/locations"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/gorilla/mux"
+)
+
+type LocationHandler struct {
+	locationsService     *locations.Service
+	organizationsService *organizations.Service
+}
+
+func NewLocationHandler(locationsService *locations.Service, organizationsService *organizations.Service) *LocationHandler {
+	return &LocationHandler{locationsService: locationsService, organizationsService: organizationsService}
+}
+
+// GetLocations lists a volunteer's saved locations
+func (h *LocationHandler) GetLocations(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+	if !requireSelfOrAdmin(w, r, h.organizationsService, volunteerID, "Volunteers can only manage their own locations") {
+		return
+	}
+
+	saved, err := h.locationsService.GetLocations(volunteerID)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch locations")
+		return
+	}
+	if saved == nil {
+		saved = []models.VolunteerLocation{}
+	}
+
+	respondJSON(w, http.StatusOK, saved)
+}
+
+// CreateLocation saves a labeled location for a volunteer
+func (h *LocationHandler) CreateLocation(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+	if !requireSelfOrAdmin(w, r, h.organizationsService, volunteerID, "Volunteers can only manage their own locations") {
+		return
+	}
+
+	var req models.SaveLocationRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	location, err := h.locationsService.CreateLocation(volunteerID, req)
+	if !h.checkSaveError(w, r, err, "CreateLocation", volunteerID) {
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, location)
+}
+
+// UpdateLocation edits a saved location or makes it primary
+func (h *LocationHandler) UpdateLocation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	volunteerID := vars["id"]
+	if !requireSelfOrAdmin(w, r, h.organizationsService, volunteerID, "Volunteers can only manage their own locations") {
+		return
+	}
+
+	var req models.SaveLocationRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	location, err := h.locationsService.UpdateLocation(volunteerID, vars["locationId"], req)
+	if !h.checkSaveError(w, r, err, "UpdateLocation", volunteerID) {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, location)
+}
+
+// DeleteLocation removes a saved location
+func (h *LocationHandler) DeleteLocation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	volunteerID := vars["id"]
+	if !requireSelfOrAdmin(w, r, h.organizationsService, volunteerID, "Volunteers can only manage their own locations") {
+		return
+	}
+
+	err := h.locationsService.DeleteLocation(volunteerID, vars["locationId"])
+	if err == locations.ErrLocationNotFound {
+		apierror.Write(w, http.StatusNotFound, "Location not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("DeleteLocation error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to delete location")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Location deleted"})
+}
+
+// checkSaveError maps create/update errors to responses, returning false
+// once a response has been written
+func (h *LocationHandler) checkSaveError(w http.ResponseWriter, r *http.Request, err error, op, volunteerID string) bool {
+	switch err {
+	case nil:
+		return true
+	case locations.ErrLabelRequired, locations.ErrLabelTooLong, locations.ErrInvalidCoordinates:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+	case locations.ErrLabelTaken, locations.ErrTooManyLocations:
+		apierror.Write(w, http.StatusConflict, err.Error())
+	case locations.ErrLocationNotFound:
+		apierror.Write(w, http.StatusNotFound, "Location not found")
+	default:
+		logging.FromRequest(r).Error(op+" error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to save location")
+	}
+	return false
+}
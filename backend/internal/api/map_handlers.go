@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/geo"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/tenant"
+)
+
+type MapHandler struct {
+	geoService *geo.Service
+}
+
+func NewMapHandler(geoService *geo.Service) *MapHandler {
+	return &MapHandler{geoService: geoService}
+}
+
+// GetClusters groups active projects, or volunteers for coordinators, into
+// map clusters for the visible bounding box and zoom level
+func (h *MapHandler) GetClusters(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	bbox, err := geo.ParseBBox(query.Get("bbox"))
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, "bbox must be minLon,minLat,maxLon,maxLat")
+		return
+	}
+
+	zoom, err := strconv.Atoi(query.Get("zoom"))
+	if err != nil || zoom < 0 || zoom > geo.MaxZoom {
+		apierror.Write(w, http.StatusBadRequest, "zoom must be an integer between 0 and 20")
+		return
+	}
+
+	layer := query.Get("layer")
+	if layer == "" {
+		layer = "projects"
+	}
+
+	var points []geo.Point
+	switch layer {
+	case "projects":
+		points, err = h.geoService.ProjectPoints(bbox, tenant.FromRequest(r))
+	case "volunteers":
+		userID := auth.UserID(r)
+		if userID == "" {
+			apierror.Write(w, http.StatusBadRequest, "User ID required")
+			return
+		}
+		allowed, permErr := h.geoService.CanViewVolunteers(userID)
+		if permErr != nil {
+			apierror.WriteErr(w, permErr, http.StatusInternalServerError, "Failed to check permissions")
+			return
+		}
+		if !allowed {
+			apierror.Write(w, http.StatusForbidden, "Only coordinators can view volunteer clusters")
+			return
+		}
+		points, err = h.geoService.VolunteerPoints(bbox, tenant.FromRequest(r))
+	default:
+		apierror.Write(w, http.StatusBadRequest, "layer must be projects or volunteers")
+		return
+	}
+	if err == geo.ErrTooManyPoints {
+		apierror.Write(w, http.StatusUnprocessableEntity, "Too many points in bbox; zoom in")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetClusters error", "layer", layer, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to load map points")
+		return
+	}
+
+	// Volunteer clusters sit on cell centers so no home location is exposed
+	clusters := geo.GridCluster(points, zoom, layer == "volunteers")
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"layer":    layer,
+		"zoom":     zoom,
+		"total":    len(points),
+		"clusters": clusters,
+	})
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/audit"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/moderation"
+	"github.com/civic-weave/backend/internal/notifications"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultAuditLogLimit = 100
+	maxAuditLogLimit     = 500
+)
+
+type ModerationHandler struct {
+	moderationService *moderation.Service
+	auditService      *audit.Service
+	mailer            notifications.Mailer
+}
+
+func NewModerationHandler(
+	moderationService *moderation.Service,
+	auditService *audit.Service,
+	mailer notifications.Mailer,
+) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+		auditService:      auditService,
+		mailer:            mailer,
+	}
+}
+
+// CreateReport flags a project, team message or profile as inappropriate
+func (h *ModerationHandler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	var req models.CreateReportRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	report, err := h.moderationService.CreateReport(userID, req)
+	switch err {
+	case nil:
+	case moderation.ErrInvalidTarget, moderation.ErrInvalidCategory, moderation.ErrDetailsTooLong:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case moderation.ErrTargetNotFound:
+		apierror.Write(w, http.StatusNotFound, "Reported content not found")
+		return
+	case moderation.ErrAlreadyReported:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("CreateReport error", "reporter", userID, "targetType", req.TargetType, "target", req.TargetID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to file report")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, report)
+}
+
+// GetReportCategories lists the categories a report can be filed under
+func (h *ModerationHandler) GetReportCategories(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, moderation.Categories)
+}
+
+// GetReports lists reports for platform admins, open ones by default or
+// those with ?status=
+func (h *ModerationHandler) GetReports(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.moderationService.GetReports(r.URL.Query().Get("status"))
+	if err == moderation.ErrInvalidStatus {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetReports error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch reports")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, reports)
+}
+
+// GetReport returns a report with the reported content and the other
+// reports filed against it
+func (h *ModerationHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	reportID := mux.Vars(r)["reportId"]
+	report, err := h.moderationService.GetReport(reportID)
+	if err == moderation.ErrReportNotFound {
+		apierror.Write(w, http.StatusNotFound, "Report not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetReport error", "report", reportID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch report")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// ResolveReport dismisses a report, hides the reported content or suspends
+// its owner, then lets the reporters and the owner know
+func (h *ModerationHandler) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	reportID := mux.Vars(r)["reportId"]
+	moderatorID := auth.UserID(r)
+
+	var req models.ResolveReportRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	resolution, recipients, err := h.moderationService.Resolve(reportID, moderatorID, req)
+	switch err {
+	case nil:
+	case moderation.ErrInvalidAction, moderation.ErrNoteTooLong:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case moderation.ErrReportNotFound:
+		apierror.Write(w, http.StatusNotFound, "Report not found")
+		return
+	case moderation.ErrTargetNotFound:
+		apierror.Write(w, http.StatusNotFound, "Reported content not found")
+		return
+	case moderation.ErrReportClosed, moderation.ErrNoOwner, moderation.ErrCannotSuspendAdmin:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("ResolveReport error", "report", reportID, "moderator", moderatorID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to resolve report")
+		return
+	}
+
+	h.notifyResolution(resolution, recipients, req)
+
+	respondJSON(w, http.StatusOK, resolution)
+}
+
+func (h *ModerationHandler) notifyResolution(resolution *models.ReportResolution, recipients *moderation.Recipients, req models.ResolveReportRequest) {
+	notice := notifications.ModerationNotice{
+		ContentType:  resolution.Content.Type,
+		ContentTitle: resolution.Content.Title,
+		Actioned:     resolution.Action != models.ModerationDismiss,
+	}
+
+	if owner := recipients.Owner; owner != nil {
+		data := notice
+		data.To, data.Name, data.Locale = owner.Email, owner.Name, owner.Locale
+		if req.Note != nil {
+			data.Note = *req.Note
+		}
+		msg := notifications.RenderContentHidden(data)
+		if resolution.Action == models.ModerationSuspend {
+			msg = notifications.RenderAccountSuspended(data)
+		}
+		if err := h.mailer.Send(msg); err != nil {
+			slog.Error("ResolveReport owner email error", "to", owner.Email, "error", err)
+		}
+	}
+
+	for _, reporter := range recipients.Reporters {
+		data := notice
+		data.To, data.Name, data.Locale = reporter.Email, reporter.Name, reporter.Locale
+		if err := h.mailer.Send(notifications.RenderReportReviewed(data)); err != nil {
+			slog.Error("ResolveReport reporter email error", "to", reporter.Email, "error", err)
+		}
+	}
+}
+
+// Unsuspend lifts a user's suspension
+func (h *ModerationHandler) Unsuspend(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["id"]
+	moderatorID := auth.UserID(r)
+
+	err := h.moderationService.Unsuspend(userID, moderatorID)
+	switch err {
+	case nil:
+	case moderation.ErrUserNotFound:
+		apierror.Write(w, http.StatusNotFound, "User not found")
+		return
+	case moderation.ErrNotSuspended:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("Unsuspend error", "user", userID, "moderator", moderatorID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to lift suspension")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetAuditLog lists audit entries newest first, optionally only those about
+// ?targetType= and ?targetId=
+func (h *ModerationHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := defaultAuditLogLimit
+	if raw := q.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			apierror.Write(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+	if limit > maxAuditLogLimit {
+		limit = maxAuditLogLimit
+	}
+
+	entries, err := h.auditService.GetEntries(q.Get("targetType"), q.Get("targetId"), limit)
+	if err != nil {
+		logging.FromRequest(r).Error("GetAuditLog error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch audit log")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, entries)
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/notifications"
+	"github.com/civic-weave/backend/internal/pagination"
+	"github.com/gorilla/mux"
+)
+
+type NotificationHandler struct {
+	notificationsService *notifications.Service
+}
+
+func NewNotificationHandler(notificationsService *notifications.Service) *NotificationHandler {
+	return &NotificationHandler{notificationsService: notificationsService}
+}
+
+// GetNotifications lists a page of the user's notifications, newest first,
+// only unread ones with ?unread=true
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePage(w, r, notifications.Sorts)
+	if !ok {
+		return
+	}
+
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+	list, total, err := h.notificationsService.GetNotifications(r.Context(), userID, unreadOnly, page)
+	if err != nil {
+		logging.FromRequest(r).Error("GetNotifications error", "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get notifications")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pagination.NewPage(list, total, page))
+}
+
+// MarkRead marks one of the user's notifications read
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireSelf(w, r)
+	if !ok {
+		return
+	}
+	notificationID := mux.Vars(r)["notificationId"]
+
+	notification, err := h.notificationsService.MarkRead(r.Context(), userID, notificationID)
+	if err != nil {
+		logging.FromRequest(r).Error("MarkRead error", "user", userID, "notification", notificationID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to mark notification read")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, notification)
+}
+
+// MarkAllRead marks all of the user's notifications read
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	n, err := h.notificationsService.MarkAllRead(r.Context(), userID)
+	if err != nil {
+		logging.FromRequest(r).Error("MarkAllRead error", "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to mark notifications read")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]int64{"marked": n})
+}
+
+// requireSelf checks the signed-in user is the user in the path. It writes
+// the error response and returns false otherwise.
+func (h *NotificationHandler) requireSelf(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := mux.Vars(r)["id"]
+
+	signedIn := auth.UserID(r)
+	if signedIn == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return "", false
+	}
+	if signedIn != userID {
+		apierror.Write(w, http.StatusForbidden, "Users can only see their own notifications")
+		return "", false
+	}
+	return userID, true
+}
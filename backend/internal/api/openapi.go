@@ -0,0 +1,238 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/openapi"
+	"github.com/civic-weave/backend/internal/snapshot"
+)
+
+// Operations describes every route for the OpenAPI document, keyed by method
+// and route template. Add an entry alongside each route registered in main;
+// routes without one are logged at startup.
+var Operations = map[string]openapi.Operation{
+	"GET /api/users":                                              {Summary: "Lists a page of users, optionally only those in ?region= or with ?role=", Response: models.User{}, Paged: true},
+	"POST /api/users/{id}/avatar":                                 {Summary: "Replaces the user's avatar with the image in the multipart \"avatar\" field", Response: map[string]string{}},
+	"DELETE /api/users/{id}/avatar":                               {Summary: "Removes the user's avatar", Status: http.StatusNoContent},
+	"POST /api/auth/login":                                        {Summary: "Checks the user's password and returns a bearer token", Request: models.LoginRequest{}, Response: models.AuthResponse{}},
+	"POST /api/auth/register":                                     {Summary: "Creates a volunteer account and signs it in", Request: models.RegisterRequest{}, Response: models.AuthResponse{}, Status: http.StatusCreated},
+	"POST /api/auth/change-password":                              {Summary: "Sets a new password for the signed-in user, who must give their current one", Request: models.ChangePasswordRequest{}, Status: http.StatusNoContent},
+	"POST /api/auth/refresh":                                      {Summary: "Renews an access token with the refresh token of its session, which is replaced by a new one in the response", Request: models.RefreshRequest{}, Response: models.AuthResponse{}},
+	"POST /api/auth/verify":                                       {Summary: "Marks the email address the token was sent to as verified", Request: models.VerifyEmailRequest{}, Response: models.User{}},
+	"POST /api/auth/resend-verification":                          {Summary: "Sends the signed-in user a new verification email", Status: http.StatusAccepted},
+	"POST /api/auth/forgot-password":                              {Summary: "Emails a password reset link to the address, if it belongs to a user", Request: models.ForgotPasswordRequest{}, Status: http.StatusAccepted},
+	"POST /api/auth/reset-password":                               {Summary: "Sets a new password with the token from a reset link, and ends every session of the user", Request: models.ResetPasswordRequest{}, Status: http.StatusNoContent},
+	"GET /api/auth/oauth/providers":                               {Summary: "Lists the external providers users can sign in with", Response: []string{}},
+	"GET /api/auth/oauth/{provider}/login":                        {Summary: "Sends the user to the provider's sign-in page", Status: http.StatusFound},
+	"GET /api/auth/oauth/{provider}/callback":                     {Summary: "Finishes signing in once the provider sends the user back, linking or creating the user for the identity", Status: http.StatusFound},
+	"POST /api/auth/logout":                                       {Summary: "Ends the session the request was made in", Status: http.StatusNoContent},
+	"GET /api/auth/sessions":                                      {Summary: "Lists the signed-in user's active sessions, marking the one the request was made in", Response: []models.Session{}},
+	"DELETE /api/auth/sessions/{sessionId}":                       {Summary: "Ends one of the signed-in user's sessions", Status: http.StatusNoContent},
+	"GET /api/admin/users/{id}/sessions":                          {Summary: "Lists every session of the user in the path, including ended ones, for admins auditing sign-ins", Response: []models.Session{}},
+	"GET /api/health":                                             {Summary: "Reports whether the API and its database are up", Response: map[string]string{}},
+	"GET /api/admin/config":                                       {Summary: "Shows platform admins the settings the server started with, with passwords, keys and other secrets redacted", Response: map[string]map[string]interface{}{}},
+	"GET /api/skills":                                             {Summary: "Lists a page of the skill catalog, or of the skills matching ?q= ranked by relevance, optionally only those in ?category=", Response: models.Skill{}, Paged: true},
+	"POST /api/skills":                                            {Summary: "Adds a skill to the taxonomy", Request: models.CreateSkillRequest{}, Response: models.Skill{}, Status: http.StatusCreated},
+	"POST /api/skills/{id}/aliases":                               {Summary: "Adds another name a skill is matched by, e.g", Request: models.CreateSkillAliasRequest{}, Response: models.SkillAlias{}, Status: http.StatusCreated},
+	"GET /api/volunteers/{id}/skills":                             {Summary: "Lists a volunteer's skills and their scores", Response: []models.VolunteerSkill{}},
+	"PUT /api/volunteers/{id}/skills":                             {Summary: "Replaces a volunteer's skills", Request: models.UpdateSkillsRequest{}, Response: map[string]string{}},
+	"GET /api/volunteers/{id}/badges":                             {Summary: "Lists the badges a volunteer has earned", Response: []models.VolunteerBadge{}},
+	"GET /api/volunteers/{id}/profile":                            {Summary: "Returns a volunteer's public profile: skills, badges, hours milestones and approved references", Response: models.VolunteerProfile{}},
+	"GET /api/volunteers/{id}/public-profile":                     {Summary: "Returns only the fields of a volunteer's profile they opted into showing", Response: models.PublicProfile{}},
+	"GET /api/volunteers/{id}/privacy":                            {Summary: "Returns which fields the volunteer shows on their public profile", Response: models.ProfilePrivacy{}},
+	"PUT /api/volunteers/{id}/privacy":                            {Summary: "Replaces which fields the volunteer shows on their public profile", Request: models.ProfilePrivacy{}, Response: models.ProfilePrivacy{}},
+	"GET /api/volunteers/{id}/references":                         {Summary: "Lists every reference written for the volunteer, for the volunteer to review", Response: []models.VolunteerReference{}},
+	"PUT /api/volunteers/{id}/references/{referenceId}":           {Summary: "Approves or declines a reference for the volunteer's public profile", Request: models.RespondToReferenceRequest{}, Response: models.VolunteerReference{}},
+	"POST /api/projects/{id}/volunteers/{volunteerId}/references": {Summary: "Records a coordinator's reference for a volunteer enrolled in their project", Request: models.CreateReferenceRequest{}, Response: models.VolunteerReference{}, Status: http.StatusCreated},
+	"DELETE /api/admin/references/{referenceId}":                  {Summary: "Takes an abusive reference off a profile (platform admins)", Status: http.StatusNoContent},
+	"POST /api/reports":                                           {Summary: "Flags a project, team message or profile as inappropriate", Request: models.CreateReportRequest{}, Response: models.ContentReport{}, Status: http.StatusCreated},
+	"GET /api/reports/categories":                                 {Summary: "Lists the categories a report can be filed under", Response: []string{}},
+	"GET /api/admin/reports":                                      {Summary: "Lists reports for platform admins, open ones by default or those with ?status=", Response: []models.ContentReport{}},
+	"GET /api/admin/reports/{reportId}":                           {Summary: "Returns a report with the reported content and the other reports filed against it", Response: models.ReportDetail{}},
+	"POST /api/admin/reports/{reportId}/actions":                  {Summary: "Dismisses a report, hides the reported content or suspends its owner, then lets the reporters and the owner know", Request: models.ResolveReportRequest{}, Response: models.ReportResolution{}},
+	"DELETE /api/admin/users/{id}/suspension":                     {Summary: "Lifts a user's suspension", Status: http.StatusNoContent},
+	"GET /api/admin/audit-log":                                    {Summary: "Lists audit entries newest first, optionally only those about ?targetType= and ?targetId=", Response: []models.AuditEntry{}},
+	"PUT /api/projects/{id}/volunteers/{volunteerId}/skills/{skillId}/verification": {Summary: "Lets someone who can manage a project vouch for a claimed skill of a volunteer enrolled in it", Status: http.StatusNoContent},
+	"PUT /api/users/{id}/locale":                                               {Summary: "Changes the language a user's emails are written in", Request: models.UpdateLocaleRequest{}, Response: map[string]string{}},
+	"GET /api/users/{id}/notifications":                                        {Summary: "Lists a page of the signed-in user's notifications newest first, only unread ones with ?unread=true", Response: models.Notification{}, Paged: true},
+	"PUT /api/users/{id}/notifications/read":                                   {Summary: "Marks all of the signed-in user's notifications read and reports how many were unread", Response: map[string]int64{}},
+	"PUT /api/users/{id}/notifications/{notificationId}/read":                  {Summary: "Marks one of the signed-in user's notifications read", Response: models.Notification{}},
+	"PUT /api/volunteers/{id}/location":                                        {Summary: "Sets a volunteer's home location", Request: models.UpdateLocationRequest{}, Response: map[string]string{}},
+	"GET /api/volunteers/{id}/locations":                                       {Summary: "Lists a volunteer's saved locations", Response: []models.VolunteerLocation{}},
+	"POST /api/volunteers/{id}/locations":                                      {Summary: "Saves a labeled location for a volunteer", Request: models.SaveLocationRequest{}, Response: models.VolunteerLocation{}, Status: http.StatusCreated},
+	"PUT /api/volunteers/{id}/locations/{locationId}":                          {Summary: "Edits a saved location or makes it primary", Request: models.SaveLocationRequest{}, Response: models.VolunteerLocation{}},
+	"DELETE /api/volunteers/{id}/locations/{locationId}":                       {Summary: "Removes a saved location", Response: map[string]string{}},
+	"GET /api/projects":                                                        {Summary: "Lists a page of projects, optionally only remote or on-site ones (?remote=), those in ?region= or those with ?status=", Response: models.Project{}, Paged: true},
+	"POST /api/projects":                                                       {Summary: "Creates a project", Request: models.CreateProjectRequest{}, Response: models.Project{}, Status: http.StatusCreated},
+	"GET /api/projects/near":                                                   {Summary: "Lists active projects within radiusKm of lat/lon, nearest first, for the map view", Response: []models.NearbyProject{}},
+	"GET /api/projects/{id}":                                                   {Summary: "Returns a project", Response: models.Project{}},
+	"PUT /api/projects/{id}":                                                   {Summary: "Updates a project's details", Request: models.UpdateProjectRequest{}, Response: map[string]string{}},
+	"GET /api/projects/{id}/skills":                                            {Summary: "Lists the skills a project needs", Response: []models.ProjectSkill{}},
+	"PUT /api/projects/{id}/skills":                                            {Summary: "Replaces the skills a project needs", Request: models.UpdateProjectSkillsRequest{}, Response: map[string]string{}},
+	"PUT /api/projects/{id}/status":                                            {Summary: "Moves a project to a new status", Request: models.UpdateProjectStatusRequest{}, Response: map[string]string{}},
+	"GET /api/projects/{id}/translations":                                      {Summary: "Lists the languages a project's name and description are translated into", Response: []models.ProjectTranslation{}},
+	"PUT /api/projects/{id}/translations/{locale}":                             {Summary: "Creates or replaces the project's translation into the locale in the path", Request: models.SetProjectTranslationRequest{}, Response: models.ProjectTranslation{}},
+	"DELETE /api/projects/{id}/translations/{locale}":                          {Summary: "Removes the project's translation into the locale in the path", Status: http.StatusNoContent},
+	"GET /api/coordinators/{id}/dashboard":                                     {Summary: "Returns the coordinator's projects, pending requests and upcoming starts in one call", Response: models.CoordinatorDashboard{}},
+	"GET /api/search/projects":                                                 {Summary: "Finds active projects by ?q= text, tolerating typos, and narrows them by ?category= (repeatable or comma-separated), distance from ?lat= and ?lon= within ?radiusKm=, start date between ?startAfter= and ?startBefore= (YYYY-MM-DD) and ?remote=", Response: models.ProjectSearchResult{}},
+	"POST /api/admin/search/reindex":                                           {Summary: "Queues a rebuild of the search index from every project", Response: models.Job{}, Status: http.StatusAccepted},
+	"GET /api/projects/{id}/metrics":                                           {Summary: "Lists a project's impact metrics with their totals", Response: []models.ImpactMetric{}},
+	"POST /api/projects/{id}/metrics":                                          {Summary: "Defines an impact metric for a project", Request: models.CreateImpactMetricRequest{}, Response: models.ImpactMetric{}, Status: http.StatusCreated},
+	"DELETE /api/projects/{id}/metrics/{metricId}":                             {Summary: "Removes a metric and its recorded entries", Status: http.StatusNoContent},
+	"GET /api/projects/{id}/metrics/{metricId}/entries":                        {Summary: "Lists a metric's entries for an optional from/to range", Response: []models.ImpactEntry{}},
+	"POST /api/projects/{id}/metrics/{metricId}/entries":                       {Summary: "Records a value against a metric", Request: models.LogImpactEntryRequest{}, Response: models.ImpactEntry{}, Status: http.StatusCreated},
+	"GET /api/projects/{id}/shifts":                                            {Summary: "Lists a project's upcoming shifts with how many volunteers each has", Response: []models.Shift{}},
+	"POST /api/projects/{id}/shifts":                                           {Summary: "Schedules a shift for a project", Request: models.CreateShiftRequest{}, Response: models.Shift{}, Status: http.StatusCreated},
+	"GET /api/projects/{id}/shifts/roster":                                     {Summary: "Lists a project's upcoming shifts with the volunteers booked on each", Response: []models.ShiftRoster{}},
+	"POST /api/projects/{id}/shifts/auto-assign":                               {Summary: "Suggests volunteers for a project's open upcoming shifts", Response: models.AssignmentProposal{}},
+	"POST /api/projects/{id}/shifts/auto-assign/accept":                        {Summary: "Books a reviewed proposal, all or nothing, and returns the updated roster", Request: models.AcceptAssignmentsRequest{}, Response: []models.ShiftRoster{}, Status: http.StatusCreated},
+	"GET /api/projects/{id}/shifts/coverage":                                   {Summary: "Lists the open coverage requests for a project's upcoming shifts", Response: []models.ShiftCoverageRequest{}},
+	"POST /api/projects/{id}/shifts/coverage/{requestId}/claim":                {Summary: "Gives the signed-in volunteer the requester's place on the shift and lets the requester know", Response: models.ShiftCoverageRequest{}},
+	"DELETE /api/projects/{id}/shifts/coverage/{requestId}":                    {Summary: "Withdraws an open coverage request", Status: http.StatusNoContent},
+	"GET /api/projects/{id}/shifts/{shiftId}":                                  {Summary: "Returns one of a project's shifts", Response: models.Shift{}},
+	"PUT /api/projects/{id}/shifts/{shiftId}":                                  {Summary: "Reschedules a shift that has not started, keeping its signups", Request: models.CreateShiftRequest{}, Response: models.Shift{}},
+	"DELETE /api/projects/{id}/shifts/{shiftId}":                               {Summary: "Cancels a shift and its signups", Status: http.StatusNoContent},
+	"POST /api/projects/{id}/shifts/{shiftId}/signups":                         {Summary: "Books the signed-in volunteer onto a shift", Response: models.Shift{}, Status: http.StatusCreated},
+	"DELETE /api/projects/{id}/shifts/{shiftId}/signups/{volunteerId}":         {Summary: "Removes a volunteer from a shift", Status: http.StatusNoContent},
+	"POST /api/projects/{id}/shifts/{shiftId}/coverage":                        {Summary: "Asks for someone to take over the signed-in volunteer's shift", Request: models.CreateCoverageRequest{}, Response: models.ShiftCoverageRequest{}, Status: http.StatusCreated},
+	"GET /api/volunteers/{id}/shifts":                                          {Summary: "Lists the upcoming shifts a volunteer is booked onto", Response: []models.VolunteerShift{}},
+	"GET /api/volunteers/{id}/calendar.ics":                                    {Summary: "Serves a volunteer's commitments as an iCal feed"},
+	"POST /api/volunteers/{id}/calendar/token":                                 {Summary: "Issues a new feed URL for the volunteer", Response: map[string]string{}, Status: http.StatusCreated},
+	"DELETE /api/volunteers/{id}/calendar/token":                               {Summary: "Disables the volunteer's feed URL", Status: http.StatusNoContent},
+	"GET /api/volunteers/{id}/availability":                                    {Summary: "Lists a volunteer's recurring availability rules", Response: []models.AvailabilityRule{}},
+	"POST /api/volunteers/{id}/availability":                                   {Summary: "Adds a recurring availability rule such as every Saturday morning except holidays", Request: models.CreateAvailabilityRuleRequest{}, Response: models.AvailabilityRule{}, Status: http.StatusCreated},
+	"GET /api/volunteers/{id}/availability/slots":                              {Summary: "Expands the volunteer's rules into concrete windows between ?from= (default today) and ?to= (default two weeks later), as UTC dates", Response: []models.AvailabilitySlot{}},
+	"GET /api/volunteers/{id}/availability/blackouts":                          {Summary: "Lists the date ranges a volunteer is away", Response: []models.AvailabilityBlackout{}},
+	"POST /api/volunteers/{id}/availability/blackouts":                         {Summary: "Marks the volunteer away for a range of days, overriding their rules", Request: models.CreateAvailabilityBlackoutRequest{}, Response: models.AvailabilityBlackout{}, Status: http.StatusCreated},
+	"DELETE /api/volunteers/{id}/availability/blackouts/{blackoutId}":          {Summary: "Removes one of the volunteer's blackouts", Status: http.StatusNoContent},
+	"DELETE /api/volunteers/{id}/availability/{ruleId}":                        {Summary: "Removes one of the volunteer's availability rules", Status: http.StatusNoContent},
+	"GET /api/holidays":                                                        {Summary: "Lists the holidays rules can skip", Response: []models.Holiday{}},
+	"POST /api/holidays":                                                       {Summary: "Adds a platform-wide holiday", Request: models.Holiday{}, Response: models.Holiday{}, Status: http.StatusCreated},
+	"DELETE /api/holidays/{date}":                                              {Summary: "Removes the holiday on the date in the path", Status: http.StatusNoContent},
+	"GET /api/map/clusters":                                                    {Summary: "Groups active projects, or volunteers for coordinators, into map clusters for the visible bounding box and zoom level", Response: map[string]interface{}{}},
+	"GET /api/regions":                                                         {Summary: "Lists regions, optionally filtered by ?kind=", Response: []models.Region{}},
+	"POST /api/regions":                                                        {Summary: "Adds a region from a GeoJSON boundary", Request: models.CreateRegionRequest{}, Response: models.Region{}, Status: http.StatusCreated},
+	"GET /api/regions/lookup":                                                  {Summary: "Returns the regions containing lat/lon, smallest first", Response: []models.Region{}},
+	"GET /api/regions/analytics":                                               {Summary: "Reports per-region volunteer and project totals", Response: []models.RegionAnalytics{}},
+	"DELETE /api/regions/{id}":                                                 {Summary: "Removes a region and its child regions", Status: http.StatusNoContent},
+	"GET /api/admin/analytics/volunteer-heatmap":                               {Summary: "Bins volunteers and active projects over a bounding box so admins can spot areas where recruitment trails demand", Response: map[string]interface{}{}},
+	"GET /api/admin/analytics/funnel":                                          {Summary: "Reports the recruitment funnel (matches shown, invitations, requests, accepted, completed) over an optional from/to range, bucketed by ?interval= and optionally narrowed to one ?projectId=", Response: models.FunnelReport{}},
+	"GET /api/admin/analytics/retention":                                       {Summary: "Reports monthly cohorts of volunteers who completed a project and how many enrolled again within 3 and 6 months, over an optional from/to month range (YYYY-MM, default the last 12 months)", Response: models.RetentionReport{}},
+	"GET /api/events":                                                          {Summary: "Streams the enrollment, team message and project status events that concern the signed-in user as server-sent events"},
+	"POST /api/events":                                                         {Summary: "Ingests a batch of frontend interaction events (viewed match, clicked invite), attributed to the user when they are signed in", Request: models.RecordEventsRequest{}, Response: models.RecordEventsResponse{}, Status: http.StatusAccepted},
+	"GET /api/leaderboards":                                                    {Summary: "Ranks volunteers who opted in by hours logged and projects completed in the current ?period= (week, month, year or all)", Response: models.Leaderboards{}},
+	"PUT /api/volunteers/{id}/leaderboard":                                     {Summary: "Lets volunteers show or hide themselves on leaderboards", Request: models.UpdateLeaderboardOptInRequest{}, Response: map[string]bool{}},
+	"GET /api/admin/exports/{dataset}":                                         {Summary: "Streams users, projects, enrollments or hours as CSV or XLSX, with an optional ?fields= selection"},
+	"GET /api/projects/{id}/teams":                                             {Summary: "Lists a project's teams", Response: []models.Team{}},
+	"POST /api/projects/{id}/teams":                                            {Summary: "Adds a team to a project", Request: models.CreateTeamRequest{}, Response: models.Team{}, Status: http.StatusCreated},
+	"GET /api/teams/{teamId}":                                                  {Summary: "Returns a team with its members", Response: models.TeamWithMembers{}},
+	"PUT /api/teams/{teamId}/lead":                                             {Summary: "Sets or clears a team's lead", Request: models.UpdateTeamLeadRequest{}, Response: map[string]string{}},
+	"PUT /api/teams/{teamId}/members/{volunteerId}":                            {Summary: "Puts an enrolled volunteer on a team, moving them off any other team in the project", Response: map[string]string{}},
+	"DELETE /api/teams/{teamId}/members/{volunteerId}":                         {Summary: "Takes a volunteer off a team", Response: map[string]string{}},
+	"GET /api/teams/{teamId}/messages":                                         {Summary: "Lists a team's broadcasts", Response: []models.TeamMessage{}},
+	"POST /api/teams/{teamId}/messages":                                        {Summary: "Emails a message to every enrolled member of a team", Request: models.BroadcastTeamMessageRequest{}, Response: models.TeamMessage{}, Status: http.StatusCreated},
+	"GET /api/projects/{id}/matches":                                           {Summary: "Ranks volunteers by how well they match a project", Response: []models.VolunteerMatch{}},
+	"GET /api/volunteers/{id}/matches":                                         {Summary: "Ranks projects by how well they match a volunteer", Response: []models.ProjectMatch{}},
+	"GET /api/admin/recompute-matches":                                         {Summary: "Lists recent runs of the batch matcher newest first, with the progress of any still running", Response: []models.MatchRecomputation{}},
+	"POST /api/admin/recompute-matches":                                        {Summary: "Queues a recomputation of every precomputed match", Response: models.Job{}, Status: http.StatusAccepted},
+	"POST /api/admin/refresh-vectors":                                          {Summary: "Queues a refresh of the skill vectors matching reads", Response: models.Job{}, Status: http.StatusAccepted},
+	"GET /api/admin/jobs":                                                      {Summary: "Lists background jobs newest first, optionally only those with ?status= (queued, running, succeeded or failed) or of ?kind=", Response: []models.Job{}},
+	"GET /api/admin/jobs/status":                                               {Summary: "Sums up the queue: for each kind of job, how many are queued, running and failed, how long the oldest has waited and when one last succeeded", Response: []models.JobKindStatus{}},
+	"POST /api/admin/jobs/{jobId}/retry":                                       {Summary: "Queues a failed job again", Response: models.Job{}},
+	"GET /api/admin/schedule":                                                  {Summary: "Lists the recurring maintenance tasks with their schedules and next and last runs", Response: []models.ScheduledTask{}},
+	"GET /api/admin/schedule/runs":                                             {Summary: "Lists runs of the tasks newest first, optionally only of ?task=", Response: []models.RetentionRun{}},
+	"GET /api/admin/webhooks":                                                  {Summary: "Lists the registered webhooks newest first, without their secrets", Response: []models.Webhook{}},
+	"POST /api/admin/webhooks":                                                 {Summary: "Registers a callback URL for enrollment.accepted, project.published or user.registered events; the response carries the signing secret, shown only once", Request: models.CreateWebhookRequest{}, Response: models.Webhook{}, Status: http.StatusCreated},
+	"PUT /api/admin/webhooks/{webhookId}":                                      {Summary: "Changes the events a webhook receives, or pauses and resumes it with active", Request: models.UpdateWebhookRequest{}, Response: models.Webhook{}},
+	"DELETE /api/admin/webhooks/{webhookId}":                                   {Summary: "Removes a webhook and its delivery log", Status: http.StatusNoContent},
+	"GET /api/admin/webhooks/{webhookId}/deliveries":                           {Summary: "Lists a page of a webhook's deliveries newest first, with each one's payload, attempts, last response status and error", Response: models.WebhookDelivery{}, Paged: true},
+	"GET /api/admin/snapshot":                                                  {Summary: "Downloads the whole database as a gzipped tar archive that RestoreSnapshot can load, e.g"},
+	"PUT /api/admin/snapshot":                                                  {Summary: "Replaces the whole database with an archive from ExportSnapshot, sent as the request body", Response: snapshot.Manifest{}},
+	"GET /api/admin/sandbox":                                                   {Summary: "Counts the synthetic data currently in the database", Response: models.SandboxSummary{}},
+	"POST /api/admin/sandbox":                                                  {Summary: "Queues generation of synthetic data with the configured seed and volumes", Response: models.Job{}, Status: http.StatusAccepted},
+	"DELETE /api/admin/sandbox":                                                {Summary: "Deletes every synthetic user, project and skill with all their records, and returns what was deleted", Response: models.SandboxSummary{}},
+	"GET /api/admin/domain-rules":                                              {Summary: "Lists the rules giving roles by email domain", Response: []models.EmailDomainRule{}},
+	"POST /api/admin/domain-rules":                                             {Summary: "Adds a rule giving users who register at a domain a role, by default once a platform admin approves it", Request: models.CreateEmailDomainRuleRequest{}, Response: models.EmailDomainRule{}, Status: http.StatusCreated},
+	"DELETE /api/admin/domain-rules/{id}":                                      {Summary: "Removes a rule", Status: http.StatusNoContent},
+	"GET /api/admin/role-requests/pending":                                     {Summary: "Lists roles email domain rules queued for approval, oldest first", Response: []models.RoleRequest{}},
+	"PUT /api/admin/role-requests/{id}":                                        {Summary: "Approves or rejects a pending role request", Request: models.ReviewRoleRequestRequest{}, Response: models.RoleRequest{}},
+	"GET /api/admin/usage":                                                     {Summary: "Lists the API keys and users that made the most requests today or this month (?period=day|month, default day), up to ?limit= (default 20, at most 100)", Response: models.APIUsageReport{}},
+	"GET /api/admin/duplicates":                                                {Summary: "Lists pairs of accounts that look like the same person", Response: []models.DuplicateCandidate{}},
+	"POST /api/admin/duplicates/detect":                                        {Summary: "Queues a detection run rather than waiting for the scheduled one", Response: models.Job{}, Status: http.StatusAccepted},
+	"DELETE /api/admin/duplicates/{id}":                                        {Summary: "Marks a pair as different people", Status: http.StatusNoContent},
+	"POST /api/admin/users/{id}/merge":                                         {Summary: "Moves everything the duplicate account in the body owns to the account in the path, then deletes the duplicate", Request: models.MergeAccountsRequest{}, Response: models.AccountMerge{}},
+	"GET /api/admin/retention":                                                 {Summary: "Lists the retention rules in effect and whether scheduled runs are dry runs", Response: models.RetentionPolicy{}},
+	"GET /api/admin/retention/runs":                                            {Summary: "Lists recent retention runs with what each rule purged, up to ?limit= (default 20, at most 100)", Response: []models.RetentionRun{}},
+	"POST /api/admin/retention/runs":                                           {Summary: "Queues a run now, a dry run unless ?dryRun=false, or in the configured mode without ?dryRun=", Response: models.Job{}, Status: http.StatusAccepted},
+	"POST /api/admin/volunteers/import":                                        {Summary: "Creates volunteers from a CSV sent as the request body or as the multipart \"file\" field", Response: models.VolunteerImportReport{}},
+	"POST /api/connectors/{source}/webhook":                                    {Summary: "Imports the projects an external platform posts, mapped by the connector named in the path, into the organization of the request's API key", Response: models.ConnectorImport{}},
+	"POST /api/enrollments":                                                    {Summary: "Creates a new enrollment request", Request: models.CreateEnrollmentRequest{}, Response: models.Enrollment{}},
+	"GET /api/projects/{projectId}/enrollments":                                {Summary: "Lists a page of a project's enrollments, optionally only those with ?status=", Response: models.EnrollmentWithDetails{}, Paged: true},
+	"GET /api/projects/{projectId}/waitlist":                                   {Summary: "Lists a project's waitlisted enrollments in the order they will be enrolled", Response: []models.WaitlistEntry{}},
+	"GET /api/volunteers/{volunteerId}/enrollments":                            {Summary: "Lists a page of a volunteer's enrollments, optionally only those with ?status=", Response: models.EnrollmentWithDetails{}, Paged: true},
+	"PUT /api/enrollments/{enrollmentId}/status":                               {Summary: "Updates the status of an enrollment and returns the status it ends in", Request: models.UpdateEnrollmentRequest{}, Response: map[string]string{}},
+	"POST /api/enrollments/bulk":                                               {Summary: "Accepts or rejects many enrollments in one transaction, reporting each one's new status or error", Request: models.BulkUpdateEnrollmentsRequest{}, Response: models.BulkEnrollmentReport{}},
+	"PUT /api/enrollments/{enrollmentId}/complete":                             {Summary: "Marks an active enrollment completed at project close-out", Response: models.Enrollment{}},
+	"GET /api/volunteers/{volunteerId}/projects/{projectId}/enrollment-status": {Summary: "Checks if a volunteer is enrolled in a project", Response: map[string]bool{}},
+	"POST /api/enrollments/{enrollmentId}/hours":                               {Summary: "Records hours worked under an enrollment", Request: models.LogHoursRequest{}, Response: models.HoursEntry{}, Status: http.StatusCreated},
+	"GET /api/enrollments/{enrollmentId}/attendance":                           {Summary: "Lists an enrollment's check-ins, latest first", Response: []models.Attendance{}},
+	"POST /api/enrollments/{enrollmentId}/checkin":                             {Summary: "Starts recording the volunteer's attendance, optionally for a shift they are booked onto", Request: models.CheckInRequest{}, Response: models.Attendance{}, Status: http.StatusCreated},
+	"POST /api/enrollments/{enrollmentId}/checkout":                            {Summary: "Ends the volunteer's attendance and logs the time since check-in as hours", Request: models.CheckOutRequest{}, Response: models.Attendance{}},
+	"GET /api/enrollments/pending":                                             {Summary: "Gets all pending enrollments (for TLs to review)", Response: []models.EnrollmentWithDetails{}},
+	"GET /api/organizations/{id}/waivers":                                      {Summary: "Lists the waivers an organization requires for all of its projects", Response: []models.WaiverTemplate{}},
+	"POST /api/organizations/{id}/waivers":                                     {Summary: "Adds a waiver required for every project of the organization (organization admins)", Request: models.WaiverTemplateRequest{}, Response: models.WaiverTemplate{}, Status: http.StatusCreated},
+	"GET /api/projects/{id}/waivers":                                           {Summary: "Lists the waivers volunteers must sign to enroll in the project", Response: []models.WaiverTemplate{}},
+	"POST /api/projects/{id}/waivers":                                          {Summary: "Adds a waiver required for the project", Request: models.WaiverTemplateRequest{}, Response: models.WaiverTemplate{}, Status: http.StatusCreated},
+	"PUT /api/waivers/{waiverId}":                                              {Summary: "Publishes a new version of a waiver", Request: models.WaiverTemplateRequest{}, Response: models.WaiverTemplate{}},
+	"DELETE /api/waivers/{waiverId}":                                           {Summary: "Stops requiring a waiver", Status: http.StatusNoContent},
+	"GET /api/waivers/{waiverId}/signatures":                                   {Summary: "Lists who signed a waiver, across all its versions", Response: []models.WaiverSignature{}},
+	"POST /api/waivers/{waiverId}/signatures":                                  {Summary: "Records the acting volunteer's e-signature of a waiver", Request: models.SignWaiverRequest{}, Response: models.WaiverSignature{}, Status: http.StatusCreated},
+	"GET /api/ratings/tags":                                                    {Summary: "Lists the tags a rating can carry", Response: []string{}},
+	"GET /api/projects/{id}/ratings":                                           {Summary: "Lists the ratings given to a project's volunteers", Response: []models.VolunteerRating{}},
+	"PUT /api/projects/{id}/volunteers/{volunteerId}/rating":                   {Summary: "Records a coordinator's rating of a volunteer who completed the project", Request: models.RateVolunteerRequest{}, Response: models.VolunteerRating{}},
+	"GET /api/volunteers/{id}/documents":                                       {Summary: "Lists the volunteer's documents the signed-in user may see", Response: []models.Document{}},
+	"POST /api/volunteers/{id}/documents":                                      {Summary: "Stores a certification from the multipart \"file\" field, with \"title\" and an optional \"expiresOn\" field", Response: models.Document{}, Status: http.StatusCreated},
+	"GET /api/enrollments/{enrollmentId}/documents":                            {Summary: "Lists the waivers attached to an enrollment that the signed-in user may see", Response: []models.Document{}},
+	"POST /api/enrollments/{enrollmentId}/documents":                           {Summary: "Attaches a signed waiver from the multipart \"file\" field, with a \"title\" field, to an enrollment", Response: models.Document{}, Status: http.StatusCreated},
+	"GET /api/documents/{documentId}":                                          {Summary: "Returns a document with a short-lived signed link to its file", Response: models.Document{}},
+	"DELETE /api/documents/{documentId}":                                       {Summary: "Removes a document", Status: http.StatusNoContent},
+	"GET /api/documents/{documentId}/content":                                  {Summary: "Serves a document's file to anyone holding a valid signed link"},
+	"GET /api/projects/{id}/photos":                                            {Summary: "Lists a project's gallery in order", Response: []models.ProjectPhoto{}},
+	"POST /api/projects/{id}/photos":                                           {Summary: "Adds the image in the multipart \"photo\" field to the end of the gallery, with an optional \"caption\" field", Response: models.ProjectPhoto{}, Status: http.StatusCreated},
+	"PUT /api/projects/{id}/photos/order":                                      {Summary: "Puts the gallery in the order given", Request: models.ReorderPhotosRequest{}, Response: []models.ProjectPhoto{}},
+	"PUT /api/projects/{id}/photos/{photoId}":                                  {Summary: "Changes a photo's caption", Request: models.UpdatePhotoRequest{}, Response: models.ProjectPhoto{}},
+	"DELETE /api/projects/{id}/photos/{photoId}":                               {Summary: "Removes a photo from the gallery", Status: http.StatusNoContent},
+	"GET /api/projects/{id}/reviews":                                           {Summary: "Lists a project's published reviews", Response: []models.ProjectReview{}},
+	"PUT /api/projects/{id}/review":                                            {Summary: "Records the acting volunteer's review of a project they completed and of its organization", Request: models.SubmitReviewRequest{}, Response: models.ProjectReview{}},
+	"GET /api/admin/reviews/pending":                                           {Summary: "Lists reviews held for moderation", Response: []models.ProjectReview{}},
+	"PUT /api/admin/reviews/{reviewId}/moderation":                             {Summary: "Publishes or removes a review held for moderation", Request: models.ModerateReviewRequest{}, Response: models.ProjectReview{}},
+	"POST /api/organizations":                                                  {Summary: "Creates an organization owned by the acting user", Request: models.CreateOrganizationRequest{}, Response: models.Organization{}, Status: http.StatusCreated},
+	"GET /api/organizations/{id}":                                              {Summary: "Returns a single organization", Response: models.Organization{}},
+	"GET /api/organizations/{id}/members":                                      {Summary: "Lists members and pending invites of an organization", Response: []models.OrganizationMember{}},
+	"POST /api/organizations/{id}/invites":                                     {Summary: "Invites an existing user to the organization with a role", Request: models.InviteMemberRequest{}, Response: models.OrganizationMember{}, Status: http.StatusCreated},
+	"POST /api/organizations/{id}/invites/accept":                              {Summary: "Activates the acting user's pending membership", Response: map[string]string{}},
+	"POST /api/organizations/{id}/invitations":                                 {Summary: "Emails an invitation to join the organization with a role", Request: models.CreateInvitationRequest{}, Response: models.OrganizationInvitation{}, Status: http.StatusCreated},
+	"GET /api/organizations/{id}/invitations":                                  {Summary: "Lists an organization's pending invitations", Response: []models.OrganizationInvitation{}},
+	"DELETE /api/organizations/{id}/invitations/{invitationId}":                {Summary: "Cancels a pending invitation", Response: map[string]string{}},
+	"POST /api/invitations/accept":                                             {Summary: "Redeems an emailed invitation token, creating the invitee's account if needed", Request: models.AcceptInvitationRequest{}, Response: models.AcceptInvitationResponse{}},
+	"GET /api/organizations/{id}/settings":                                     {Summary: "Returns an organization's settings (defaults when never saved)", Response: models.OrganizationSettings{}},
+	"PUT /api/organizations/{id}/settings":                                     {Summary: "Replaces an organization's settings", Request: models.UpdateOrganizationSettingsRequest{}, Response: models.OrganizationSettings{}},
+	"POST /api/organizations/{id}/api-keys":                                    {Summary: "Issues an organization-scoped API key for a partner site", Request: models.CreateAPIKeyRequest{}, Response: models.CreateAPIKeyResponse{}, Status: http.StatusCreated},
+	"GET /api/organizations/{id}/api-keys":                                     {Summary: "Lists an organization's API keys without the keys themselves", Response: []models.APIKey{}},
+	"DELETE /api/organizations/{id}/api-keys/{keyId}":                          {Summary: "Permanently disables an API key", Response: map[string]string{}},
+	"GET /api/organizations/{id}/sharing":                                      {Summary: "Lists who the organization shares volunteers with and who shares with it", Response: models.VolunteerSharing{}},
+	"POST /api/organizations/{id}/sharing":                                     {Summary: "Lets another organization's coordinators see this organization's volunteers in match results", Request: models.ShareVolunteersRequest{}, Response: models.VolunteerSharingAgreement{}, Status: http.StatusCreated},
+	"DELETE /api/organizations/{id}/sharing/{recipientId}":                     {Summary: "Stops sharing volunteers with another organization", Response: map[string]string{}},
+	"GET /api/organizations/{id}/verification":                                 {Summary: "Returns an organization's verification status and evidence", Response: models.OrganizationVerification{}},
+	"PUT /api/organizations/{id}/verification":                                 {Summary: "Lets a platform admin verify or reject an organization", Request: models.UpdateVerificationRequest{}, Response: models.Organization{}},
+	"POST /api/organizations/{id}/verification/evidence":                       {Summary: "Stores a PDF or image supporting verification, sent as the \"file\" field of a multipart form", Response: models.VerificationEvidence{}, Status: http.StatusCreated},
+	"GET /api/organizations/{id}/verification/evidence/{evidenceId}":           {Summary: "Serves an uploaded evidence document"},
+	"POST /api/organizations/{id}/verification/request":                        {Summary: "Submits the organization for platform admin review", Response: models.Organization{}},
+	"GET /api/admin/organizations/pending-verification":                        {Summary: "Lists organizations awaiting platform admin review", Response: []models.Organization{}},
+	"GET /api/organizations/{id}/reports/summary":                              {Summary: "Aggregates activity across the organization's projects for an optional from/to date range (YYYY-MM-DD, default last 30 days)", Response: models.OrganizationSummaryReport{}},
+	"GET /api/organizations/{id}/reports/hours":                                {Summary: "Totals hours per volunteer and project across the organization for an optional from/to range, as JSON or ?format=csv"},
+	"GET /api/organizations/{id}/reports/impact":                               {Summary: "Totals impact across the organization's projects for an optional from/to range (YYYY-MM-DD, default last 30 days)", Response: models.OrganizationImpactReport{}},
+	"GET /api/projects/{id}/reports/hours":                                     {Summary: "Totals a project's hours per volunteer"},
+	"GET /api/volunteers/{id}/reports/hours":                                   {Summary: "Totals a volunteer's hours per project"},
+}
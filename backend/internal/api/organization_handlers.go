@@ -0,0 +1,898 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/export"
+	"github.com/civic-weave/backend/internal/i18n"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/notifications"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type OrganizationHandler struct {
+	organizationsService *organizations.Service
+	mailer               notifications.Mailer
+	inviteAcceptURL      string
+}
+
+func NewOrganizationHandler(organizationsService *organizations.Service, mailer notifications.Mailer, inviteAcceptURL string) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationsService: organizationsService,
+		mailer:               mailer,
+		inviteAcceptURL:      inviteAcceptURL,
+	}
+}
+
+// CreateOrganization creates an organization owned by the acting user
+func (h *OrganizationHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateOrganizationRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	org, err := h.organizationsService.CreateOrganization(req.Name, req.Slug, req.Description, userID)
+	if err == organizations.ErrSlugTaken {
+		apierror.Write(w, http.StatusConflict, "Organization slug already taken")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("CreateOrganization error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create organization")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, org)
+}
+
+// GetOrganization returns a single organization
+func (h *OrganizationHandler) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	org, err := h.organizationsService.GetOrganization(orgID)
+	if err == organizations.ErrOrganizationNotFound {
+		apierror.Write(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+	if err != nil {
+		apierror.Write(w, http.StatusInternalServerError, "Failed to fetch organization")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, org)
+}
+
+// GetMembers lists members and pending invites of an organization. Members
+// below coordinator see only active members, without their emails.
+func (h *OrganizationHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	role, ok := h.memberRole(w, r, orgID)
+	if !ok {
+		return
+	}
+
+	members, err := h.organizationsService.GetMembers(orgID)
+	if err != nil {
+		apierror.Write(w, http.StatusInternalServerError, "Failed to fetch members")
+		return
+	}
+
+	if !organizations.RoleAtLeast(role, models.OrgRoleCoordinator) {
+		visible := members[:0]
+		for _, m := range members {
+			if m.Status != "active" {
+				continue
+			}
+			m.UserEmail = ""
+			visible = append(visible, m)
+		}
+		members = visible
+	}
+	if members == nil {
+		members = []models.OrganizationMember{}
+	}
+
+	respondJSON(w, http.StatusOK, members)
+}
+
+// InviteMember invites an existing user to the organization with a role
+func (h *OrganizationHandler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	var req models.InviteMemberRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	member, err := h.organizationsService.InviteMember(orgID, req.UserID, req.Role, userID)
+	switch err {
+	case nil:
+	case organizations.ErrInvalidRole:
+		apierror.Write(w, http.StatusBadRequest, "Role must be one of owner, admin, coordinator, member")
+		return
+	case organizations.ErrInsufficientRole:
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can invite members")
+		return
+	case organizations.ErrAlreadyMember:
+		apierror.Write(w, http.StatusConflict, "User is already a member or has a pending invite")
+		return
+	default:
+		logging.FromRequest(r).Error("InviteMember error", "org", orgID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to invite member")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, member)
+}
+
+// AcceptInvite activates the acting user's pending membership
+func (h *OrganizationHandler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	err := h.organizationsService.AcceptInvite(orgID, userID)
+	if err == organizations.ErrInviteNotFound {
+		apierror.Write(w, http.StatusNotFound, "No pending invite for this user")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("AcceptInvite error", "org", orgID, "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to accept invite")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Invite accepted"})
+}
+
+// CreateInvitation emails an invitation to join the organization with a role
+func (h *OrganizationHandler) CreateInvitation(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	var req models.CreateInvitationRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	invitation, token, err := h.organizationsService.CreateInvitation(orgID, req.Email, req.Role, userID)
+	switch err {
+	case nil:
+	case organizations.ErrInvalidRole:
+		apierror.Write(w, http.StatusBadRequest, "Role must be one of owner, admin, coordinator, member")
+		return
+	case organizations.ErrInsufficientRole:
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can invite members")
+		return
+	case organizations.ErrInvitationPending:
+		apierror.Write(w, http.StatusConflict, "A pending invitation already exists for this email")
+		return
+	default:
+		logging.FromRequest(r).Error("CreateInvitation error", "org", orgID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create invitation")
+		return
+	}
+
+	// The invitation stays valid if delivery fails; admins can revoke and re-invite
+	msg, err := h.organizationsService.InvitationMessage(invitation, token, h.inviteAcceptURL, i18n.FromRequest(r))
+	if err == nil {
+		err = h.mailer.Send(msg)
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("CreateInvitation email error", "org", orgID, "invitation", invitation.ID, "error", err)
+	}
+
+	respondJSON(w, http.StatusCreated, invitation)
+}
+
+// GetInvitations lists an organization's pending invitations
+func (h *OrganizationHandler) GetInvitations(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	role, ok := h.memberRole(w, r, orgID)
+	if !ok {
+		return
+	}
+	if !organizations.RoleAtLeast(role, models.OrgRoleCoordinator) {
+		apierror.Write(w, http.StatusForbidden, "Only organization coordinators and admins can view invitations")
+		return
+	}
+
+	invitations, err := h.organizationsService.GetPendingInvitations(orgID)
+	if err != nil {
+		apierror.Write(w, http.StatusInternalServerError, "Failed to fetch invitations")
+		return
+	}
+	if invitations == nil {
+		invitations = []models.OrganizationInvitation{}
+	}
+
+	respondJSON(w, http.StatusOK, invitations)
+}
+
+// RevokeInvitation cancels a pending invitation
+func (h *OrganizationHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orgID := vars["id"]
+	invitationID := vars["invitationId"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	err := h.organizationsService.RevokeInvitation(orgID, invitationID, userID)
+	switch err {
+	case nil:
+	case organizations.ErrInsufficientRole:
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can revoke invitations")
+		return
+	case organizations.ErrInvitationNotFound:
+		apierror.Write(w, http.StatusNotFound, "Pending invitation not found")
+		return
+	default:
+		logging.FromRequest(r).Error("RevokeInvitation error", "org", orgID, "invitation", invitationID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to revoke invitation")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Invitation revoked"})
+}
+
+// AcceptInvitation redeems an emailed invitation token, creating the
+// invitee's account if needed
+func (h *OrganizationHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	var req models.AcceptInvitationRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	resp, err := h.organizationsService.AcceptInvitation(req.Token, req.Name)
+	switch err {
+	case nil:
+	case organizations.ErrInvitationNotFound:
+		apierror.Write(w, http.StatusNotFound, "Invitation not found or no longer pending")
+		return
+	case organizations.ErrInvitationExpired:
+		apierror.Write(w, http.StatusGone, "Invitation has expired")
+		return
+	case organizations.ErrNameRequired:
+		apierror.Write(w, http.StatusBadRequest, "Name is required to create an account")
+		return
+	default:
+		logging.FromRequest(r).Error("AcceptInvitation error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to accept invitation")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// GetSettings returns an organization's settings (defaults when never saved)
+func (h *OrganizationHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	if _, ok := h.memberRole(w, r, orgID); !ok {
+		return
+	}
+
+	settings, err := h.organizationsService.GetSettings(orgID)
+	if err == organizations.ErrOrganizationNotFound {
+		apierror.Write(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+	if err != nil {
+		apierror.Write(w, http.StatusInternalServerError, "Failed to fetch settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// memberRole returns the signed-in user's role in the organization, treating
+// platform admins as owners. It writes the error response and returns false
+// when the user isn't an active member.
+func (h *OrganizationHandler) memberRole(w http.ResponseWriter, r *http.Request, orgID string) (string, bool) {
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return "", false
+	}
+
+	role, err := h.organizationsService.GetMemberRole(orgID, userID)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check permissions")
+		return "", false
+	}
+	if role != "" {
+		return role, true
+	}
+
+	isAdmin, err := h.organizationsService.IsPlatformAdmin(userID)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check permissions")
+		return "", false
+	}
+	if !isAdmin {
+		apierror.Write(w, http.StatusForbidden, "Only members of this organization can view it")
+		return "", false
+	}
+	return models.OrgRoleOwner, true
+}
+
+// UpdateSettings replaces an organization's settings
+func (h *OrganizationHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	var req models.UpdateOrganizationSettingsRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	settings, err := h.organizationsService.UpdateSettings(orgID, userID, req)
+	var settingsErr *organizations.SettingsError
+	switch {
+	case err == nil:
+	case errors.As(err, &settingsErr):
+		apierror.Write(w, http.StatusBadRequest, settingsErr.Error())
+		return
+	case err == organizations.ErrOrganizationNotFound:
+		apierror.Write(w, http.StatusNotFound, "Organization not found")
+		return
+	case err == organizations.ErrInsufficientRole:
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can change settings")
+		return
+	default:
+		logging.FromRequest(r).Error("UpdateSettings error", "org", orgID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// GetSummaryReport aggregates activity across the organization's projects
+// for an optional from/to date range (YYYY-MM-DD, default last 30 days)
+func (h *OrganizationHandler) GetSummaryReport(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	from, to, err := organizations.ReportRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.organizationsService.GetSummaryReport(orgID, userID, from, to)
+	if err == organizations.ErrInsufficientRole {
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can view reports")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetSummaryReport error", "org", orgID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to build report")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// GetOrganizationHoursReport totals hours per volunteer and project across
+// the organization for an optional from/to range, as JSON or ?format=csv
+func (h *OrganizationHandler) GetOrganizationHoursReport(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+	h.respondHoursReport(w, r, "organization-"+orgID, func(userID string, from, to time.Time) (*models.HoursReport, error) {
+		return h.organizationsService.GetOrganizationHoursReport(orgID, userID, from, to)
+	})
+}
+
+// GetProjectHoursReport totals a project's hours per volunteer
+func (h *OrganizationHandler) GetProjectHoursReport(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+	h.respondHoursReport(w, r, "project-"+projectID, func(userID string, from, to time.Time) (*models.HoursReport, error) {
+		return h.organizationsService.GetProjectHoursReport(projectID, userID, from, to)
+	})
+}
+
+// GetVolunteerHoursReport totals a volunteer's hours per project
+func (h *OrganizationHandler) GetVolunteerHoursReport(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+	h.respondHoursReport(w, r, "volunteer-"+volunteerID, func(userID string, from, to time.Time) (*models.HoursReport, error) {
+		return h.organizationsService.GetVolunteerHoursReport(volunteerID, userID, tenant.FromRequest(r), from, to)
+	})
+}
+
+// respondHoursReport handles the parameters shared by the hours reports and
+// writes the report as JSON or, with ?format=csv|xlsx, as a download
+func (h *OrganizationHandler) respondHoursReport(w http.ResponseWriter, r *http.Request, subject string, build func(userID string, from, to time.Time) (*models.HoursReport, error)) {
+	query := r.URL.Query()
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	format := query.Get("format")
+	if format != "" && format != "json" {
+		if _, err := export.ParseFormat(format); err != nil {
+			apierror.Write(w, http.StatusBadRequest, "format must be json, csv or xlsx")
+			return
+		}
+	}
+
+	from, to, err := organizations.ReportRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := build(userID, from, to)
+	if err == organizations.ErrInsufficientRole {
+		apierror.Write(w, http.StatusForbidden, "You are not allowed to view this hours report")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("Hours report error", "subject", subject, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to build hours report")
+		return
+	}
+
+	if format == "" || format == "json" {
+		respondJSON(w, http.StatusOK, report)
+		return
+	}
+
+	out := startDownload(w, fmt.Sprintf("hours-%s-%s-%s", subject, report.From, report.To), format)
+	if err := writeHoursReport(export.NewWriter(out, format), report); err != nil {
+		logging.FromRequest(r).Error("Hours report write error", "subject", subject, "error", err)
+		out.fail(err, "Failed to write hours report")
+	}
+}
+
+func writeHoursReport(out export.RowWriter, report *models.HoursReport) error {
+	err := out.WriteHeader([]export.Column{
+		{Name: "volunteer_id"},
+		{Name: "volunteer_name"},
+		{Name: "project_id"},
+		{Name: "project_name"},
+		{Name: "entries", Numeric: true},
+		{Name: "hours", Numeric: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range report.Rows {
+		err := out.WriteRow([]string{
+			row.VolunteerID,
+			row.VolunteerName,
+			row.ProjectID,
+			row.ProjectName,
+			strconv.Itoa(row.Entries),
+			strconv.FormatFloat(row.Hours, 'f', 2, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return out.Close()
+}
+
+// CreateAPIKey issues an organization-scoped API key for a partner site.
+// The key is only shown in this response.
+func (h *OrganizationHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	var req models.CreateAPIKeyRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	key, err := h.organizationsService.CreateAPIKey(orgID, userID, req)
+	switch err {
+	case nil:
+	case organizations.ErrAPIKeyNameRequired, organizations.ErrInvalidAPIKeyScope, organizations.ErrInvalidAPIKeyQuota:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case organizations.ErrInsufficientRole:
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can manage API keys")
+		return
+	default:
+		logging.FromRequest(r).Error("CreateAPIKey error", "org", orgID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create API key")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, key)
+}
+
+// GetAPIKeys lists an organization's API keys without the keys themselves
+func (h *OrganizationHandler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	keys, err := h.organizationsService.GetAPIKeys(orgID, userID)
+	if err == organizations.ErrInsufficientRole {
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can manage API keys")
+		return
+	}
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch API keys")
+		return
+	}
+	if keys == nil {
+		keys = []models.APIKey{}
+	}
+
+	respondJSON(w, http.StatusOK, keys)
+}
+
+// RevokeAPIKey permanently disables an API key
+func (h *OrganizationHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orgID := vars["id"]
+	keyID := vars["keyId"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	err := h.organizationsService.RevokeAPIKey(orgID, keyID, userID)
+	switch err {
+	case nil:
+	case organizations.ErrInsufficientRole:
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can manage API keys")
+		return
+	case organizations.ErrAPIKeyNotFound:
+		apierror.Write(w, http.StatusNotFound, "Active API key not found")
+		return
+	default:
+		logging.FromRequest(r).Error("RevokeAPIKey error", "org", orgID, "key", keyID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to revoke API key")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "API key revoked"})
+}
+
+// GetVolunteerSharing lists who the organization shares volunteers with and who shares with it
+func (h *OrganizationHandler) GetVolunteerSharing(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	sharing, err := h.organizationsService.GetVolunteerSharing(orgID, userID)
+	if err == organizations.ErrInsufficientRole {
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can manage volunteer sharing")
+		return
+	}
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch sharing agreements")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, sharing)
+}
+
+// ShareVolunteers lets another organization's coordinators see this organization's volunteers in match results
+func (h *OrganizationHandler) ShareVolunteers(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	var req models.ShareVolunteersRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	agreement, err := h.organizationsService.ShareVolunteers(orgID, strings.TrimSpace(req.OrganizationID), userID)
+	switch err {
+	case nil:
+	case organizations.ErrInsufficientRole:
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can manage volunteer sharing")
+		return
+	case organizations.ErrOrganizationNotFound:
+		apierror.Write(w, http.StatusNotFound, "Recipient organization not found")
+		return
+	case organizations.ErrShareWithSelf:
+		apierror.Write(w, http.StatusBadRequest, "An organization cannot share volunteers with itself")
+		return
+	default:
+		logging.FromRequest(r).Error("ShareVolunteers error", "org", orgID, "recipient", req.OrganizationID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to share volunteers")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, agreement)
+}
+
+// RevokeVolunteerSharing stops sharing volunteers with another organization
+func (h *OrganizationHandler) RevokeVolunteerSharing(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orgID := vars["id"]
+	recipientID := vars["recipientId"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	err := h.organizationsService.RevokeVolunteerSharing(orgID, recipientID, userID)
+	switch err {
+	case nil:
+	case organizations.ErrInsufficientRole:
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can manage volunteer sharing")
+		return
+	case organizations.ErrShareNotFound:
+		apierror.Write(w, http.StatusNotFound, "Active sharing agreement not found")
+		return
+	default:
+		logging.FromRequest(r).Error("RevokeVolunteerSharing error", "org", orgID, "recipient", recipientID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to revoke sharing")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Volunteer sharing revoked"})
+}
+
+// GetVerification returns an organization's verification status and evidence
+func (h *OrganizationHandler) GetVerification(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	verification, err := h.organizationsService.GetVerification(orgID, userID)
+	switch err {
+	case nil:
+	case organizations.ErrInsufficientRole:
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can view verification")
+		return
+	case organizations.ErrOrganizationNotFound:
+		apierror.Write(w, http.StatusNotFound, "Organization not found")
+		return
+	default:
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch verification")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, verification)
+}
+
+// UploadVerificationEvidence stores a PDF or image supporting verification,
+// sent as the "file" field of a multipart form
+func (h *OrganizationHandler) UploadVerificationEvidence(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	// Leave headroom for the multipart framing around the file
+	r.Body = http.MaxBytesReader(w, r.Body, organizations.MaxEvidenceBytes+1<<20)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, "Evidence file is required (multipart field \"file\", at most 10 MB)")
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, organizations.MaxEvidenceBytes+1))
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, "Failed to read evidence file")
+		return
+	}
+
+	// Trust the bytes rather than the client's declared type
+	contentType := http.DetectContentType(content)
+	filename := filepath.Base(header.Filename)
+
+	evidence, err := h.organizationsService.AddVerificationEvidence(orgID, userID, filename, contentType, content)
+	switch err {
+	case nil:
+	case organizations.ErrEvidenceTooLarge, organizations.ErrEvidenceType:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case organizations.ErrInsufficientRole:
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can upload verification evidence")
+		return
+	default:
+		logging.FromRequest(r).Error("UploadVerificationEvidence error", "org", orgID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to store evidence")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, evidence)
+}
+
+// DownloadVerificationEvidence serves an uploaded evidence document
+func (h *OrganizationHandler) DownloadVerificationEvidence(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orgID := vars["id"]
+	evidenceID := vars["evidenceId"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	evidence, content, err := h.organizationsService.GetVerificationEvidenceContent(orgID, evidenceID, userID)
+	switch err {
+	case nil:
+	case organizations.ErrInsufficientRole:
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can view verification evidence")
+		return
+	case organizations.ErrEvidenceNotFound:
+		apierror.Write(w, http.StatusNotFound, "Evidence not found")
+		return
+	default:
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch evidence")
+		return
+	}
+
+	w.Header().Set("Content-Type", evidence.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Header().Set("Content-Disposition", "attachment; filename="+strconv.Quote(evidence.Filename))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
+
+// RequestVerification submits the organization for platform admin review
+func (h *OrganizationHandler) RequestVerification(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	org, err := h.organizationsService.RequestVerification(orgID, userID)
+	switch err {
+	case nil:
+	case organizations.ErrInsufficientRole:
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can request verification")
+		return
+	case organizations.ErrOrganizationNotFound:
+		apierror.Write(w, http.StatusNotFound, "Organization not found")
+		return
+	case organizations.ErrEvidenceRequired, organizations.ErrVerificationNotAllowed:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("RequestVerification error", "org", orgID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to request verification")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, org)
+}
+
+// ReviewVerification lets a platform admin verify or reject an organization
+func (h *OrganizationHandler) ReviewVerification(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	var req models.UpdateVerificationRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	org, err := h.organizationsService.ReviewVerification(orgID, userID, req)
+	switch err {
+	case nil:
+	case organizations.ErrInvalidVerification:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case organizations.ErrInsufficientRole:
+		apierror.Write(w, http.StatusForbidden, "Only platform admins can review verification")
+		return
+	case organizations.ErrOrganizationNotFound:
+		apierror.Write(w, http.StatusNotFound, "Organization not found")
+		return
+	case organizations.ErrVerificationNotPending:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("ReviewVerification error", "org", orgID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to review verification")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, org)
+}
+
+// GetPendingVerifications lists organizations awaiting platform admin review
+func (h *OrganizationHandler) GetPendingVerifications(w http.ResponseWriter, r *http.Request) {
+	orgs, err := h.organizationsService.GetPendingVerifications()
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch pending verifications")
+		return
+	}
+	if orgs == nil {
+		orgs = []models.Organization{}
+	}
+
+	respondJSON(w, http.StatusOK, orgs)
+}
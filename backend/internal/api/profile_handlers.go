@@ -0,0 +1,279 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/badges"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/milestones"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/civic-weave/backend/internal/profiles"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type ProfileHandler struct {
+	profilesService      *profiles.Service
+	badgesService        *badges.Service
+	milestonesService    *milestones.Service
+	organizationsService *organizations.Service
+}
+
+func NewProfileHandler(profilesService *profiles.Service, badgesService *badges.Service, milestonesService *milestones.Service, organizationsService *organizations.Service) *ProfileHandler {
+	return &ProfileHandler{
+		profilesService:      profilesService,
+		badgesService:        badgesService,
+		milestonesService:    milestonesService,
+		organizationsService: organizationsService,
+	}
+}
+
+// GetProfile returns a volunteer's public profile: skills, badges, hours
+// milestones and approved references
+func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+
+	profile, err := h.profilesService.GetProfile(volunteerID)
+	if err == profiles.ErrVolunteerNotFound {
+		apierror.Write(w, http.StatusNotFound, "Volunteer not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetProfile error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch profile")
+		return
+	}
+
+	profile.Badges, err = h.badgesService.GetBadges(profile.ID)
+	if err != nil {
+		logging.FromRequest(r).Error("GetProfile badges error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch profile")
+		return
+	}
+
+	profile.Milestones, err = h.milestonesService.GetMilestones(profile.ID)
+	if err != nil {
+		logging.FromRequest(r).Error("GetProfile milestones error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch profile")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, profile)
+}
+
+// GetPublicProfile returns only the fields of a volunteer's profile they
+// opted into showing
+func (h *ProfileHandler) GetPublicProfile(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+
+	profile, privacy, err := h.profilesService.GetPublicProfile(volunteerID)
+	if err == profiles.ErrVolunteerNotFound {
+		apierror.Write(w, http.StatusNotFound, "Volunteer not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetPublicProfile error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch profile")
+		return
+	}
+
+	if privacy.Badges {
+		profile.Badges, err = h.badgesService.GetBadges(profile.ID)
+		if err != nil {
+			logging.FromRequest(r).Error("GetPublicProfile badges error", "volunteer", volunteerID, "error", err)
+			apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch profile")
+			return
+		}
+	}
+
+	respondJSON(w, http.StatusOK, profile)
+}
+
+// GetPrivacy returns which fields the volunteer shows on their public profile
+func (h *ProfileHandler) GetPrivacy(w http.ResponseWriter, r *http.Request) {
+	volunteerID, ok := h.requireVolunteer(w, r)
+	if !ok {
+		return
+	}
+
+	privacy, err := h.profilesService.GetPrivacy(volunteerID)
+	if err == profiles.ErrVolunteerNotFound {
+		apierror.Write(w, http.StatusNotFound, "Volunteer not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetPrivacy error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch privacy settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, privacy)
+}
+
+// UpdatePrivacy replaces which fields the volunteer shows on their public
+// profile
+func (h *ProfileHandler) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	volunteerID, ok := h.requireVolunteer(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.ProfilePrivacy
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	err := h.profilesService.UpdatePrivacy(volunteerID, req)
+	if err == profiles.ErrVolunteerNotFound {
+		apierror.Write(w, http.StatusNotFound, "Volunteer not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("UpdatePrivacy error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update privacy settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, req)
+}
+
+// GetReferences lists every reference written for the volunteer, for the
+// volunteer to review
+func (h *ProfileHandler) GetReferences(w http.ResponseWriter, r *http.Request) {
+	volunteerID, ok := h.requireVolunteer(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := h.profilesService.GetReferences(volunteerID)
+	if err != nil {
+		logging.FromRequest(r).Error("GetReferences error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch references")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, list)
+}
+
+// CreateReference records a coordinator's reference for a volunteer enrolled
+// in their project
+func (h *ProfileHandler) CreateReference(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	volunteerID := vars["volunteerId"]
+
+	var req models.CreateReferenceRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	allowed, err := h.organizationsService.CanManageProject(userID, projectID)
+	if err != nil {
+		logging.FromRequest(r).Error("CanManageProject error", "project", projectID, "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check project permissions")
+		return
+	}
+	if !allowed {
+		apierror.Write(w, http.StatusForbidden, "Only the project coordinator or organization coordinators can write references")
+		return
+	}
+
+	reference, err := h.profilesService.CreateReference(projectID, volunteerID, userID, tenant.FromRequest(r), req)
+	switch err {
+	case nil:
+	case profiles.ErrInvalidBody, profiles.ErrOwnReference:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case profiles.ErrProjectNotFound:
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	case profiles.ErrNotEnrolled:
+		apierror.Write(w, http.StatusNotFound, "Volunteer is not enrolled in the project")
+		return
+	case profiles.ErrReferenceExists:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("CreateReference error", "project", projectID, "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create reference")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, reference)
+}
+
+// RespondToReference approves or declines a reference for the volunteer's
+// public profile
+func (h *ProfileHandler) RespondToReference(w http.ResponseWriter, r *http.Request) {
+	referenceID := mux.Vars(r)["referenceId"]
+
+	volunteerID, ok := h.requireVolunteer(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.RespondToReferenceRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	reference, err := h.profilesService.RespondToReference(referenceID, volunteerID, req)
+	switch err {
+	case nil:
+	case profiles.ErrInvalidAction:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case profiles.ErrReferenceNotFound:
+		apierror.Write(w, http.StatusNotFound, "Reference not found")
+		return
+	default:
+		logging.FromRequest(r).Error("RespondToReference error", "reference", referenceID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update reference")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, reference)
+}
+
+// RemoveReference takes an abusive reference off a profile (platform admins)
+func (h *ProfileHandler) RemoveReference(w http.ResponseWriter, r *http.Request) {
+	referenceID := mux.Vars(r)["referenceId"]
+	userID := auth.UserID(r)
+
+	err := h.profilesService.RemoveReference(referenceID, userID)
+	if err == profiles.ErrReferenceNotFound {
+		apierror.Write(w, http.StatusNotFound, "Reference not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("RemoveReference error", "reference", referenceID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to remove reference")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProfileHandler) requireVolunteer(w http.ResponseWriter, r *http.Request) (string, bool) {
+	volunteerID := mux.Vars(r)["id"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return "", false
+	}
+	if userID != volunteerID {
+		apierror.Write(w, http.StatusForbidden, "Volunteers can only manage their own profile")
+		return "", false
+	}
+	return volunteerID, true
+}
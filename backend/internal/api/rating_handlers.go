@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/civic-weave/backend/internal/ratings"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type RatingHandler struct {
+	ratingsService       *ratings.Service
+	organizationsService *organizations.Service
+}
+
+func NewRatingHandler(ratingsService *ratings.Service, organizationsService *organizations.Service) *RatingHandler {
+	return &RatingHandler{
+		ratingsService:       ratingsService,
+		organizationsService: organizationsService,
+	}
+}
+
+// GetProjectRatings lists the ratings given to a project's volunteers
+func (h *RatingHandler) GetProjectRatings(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	if _, ok := h.authorizeProject(w, r, projectID); !ok {
+		return
+	}
+
+	list, err := h.ratingsService.GetProjectRatings(projectID, tenant.FromRequest(r))
+	if err == ratings.ErrProjectNotFound {
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetProjectRatings error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch ratings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, list)
+}
+
+// RateVolunteer records a coordinator's rating of a volunteer who completed
+// the project
+func (h *RatingHandler) RateVolunteer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	volunteerID := vars["volunteerId"]
+
+	var req models.RateVolunteerRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID, ok := h.authorizeProject(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	rating, err := h.ratingsService.Rate(projectID, volunteerID, userID, tenant.FromRequest(r), req)
+	switch err {
+	case nil:
+	case ratings.ErrInvalidRating, ratings.ErrUnknownTag:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case ratings.ErrProjectNotFound:
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	case ratings.ErrNotEnrolled:
+		apierror.Write(w, http.StatusNotFound, "Volunteer is not enrolled in the project")
+		return
+	case ratings.ErrNotCompleted:
+		apierror.Write(w, http.StatusConflict, "Volunteers can be rated once they complete the project")
+		return
+	default:
+		logging.FromRequest(r).Error("RateVolunteer error", "project", projectID, "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to rate volunteer")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rating)
+}
+
+// GetRatingTags lists the tags a rating can carry
+func (h *RatingHandler) GetRatingTags(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, ratings.Tags)
+}
+
+func (h *RatingHandler) authorizeProject(w http.ResponseWriter, r *http.Request, projectID string) (string, bool) {
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return "", false
+	}
+
+	allowed, err := h.organizationsService.CanManageProject(userID, projectID)
+	if err != nil {
+		logging.FromRequest(r).Error("CanManageProject error", "project", projectID, "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check project permissions")
+		return "", false
+	}
+	if !allowed {
+		apierror.Write(w, http.StatusForbidden, "Only the project coordinator or organization coordinators can rate volunteers")
+		return "", false
+	}
+
+	return userID, true
+}
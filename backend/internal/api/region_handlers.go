@@ -0,0 +1,121 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/regions"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type RegionHandler struct {
+	regionsService *regions.Service
+}
+
+func NewRegionHandler(regionsService *regions.Service) *RegionHandler {
+	return &RegionHandler{
+		regionsService: regionsService,
+	}
+}
+
+// GetRegions lists regions, optionally filtered by ?kind=
+func (h *RegionHandler) GetRegions(w http.ResponseWriter, r *http.Request) {
+	list, err := h.regionsService.GetRegions(r.URL.Query().Get("kind"))
+	if err == regions.ErrInvalidKind {
+		apierror.Write(w, http.StatusBadRequest, "kind must be city, ward or region")
+		return
+	}
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch regions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, list)
+}
+
+// LookupRegions returns the regions containing lat/lon, smallest first
+func (h *RegionHandler) LookupRegions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	lat, latErr := strconv.ParseFloat(q.Get("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(q.Get("lon"), 64)
+	if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		apierror.Write(w, http.StatusBadRequest, "Valid lat and lon are required")
+		return
+	}
+
+	list, err := h.regionsService.Lookup(lat, lon)
+	if err != nil {
+		logging.FromRequest(r).Error("LookupRegions error", "lat", lat, "lon", lon, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to look up regions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, list)
+}
+
+// CreateRegion adds a region from a GeoJSON boundary. Only platform admins
+// can manage regions.
+func (h *RegionHandler) CreateRegion(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateRegionRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID := auth.UserID(r)
+
+	region, err := h.regionsService.CreateRegion(userID, req)
+	switch err {
+	case nil:
+	case regions.ErrNameRequired, regions.ErrInvalidKind, regions.ErrInvalidBoundary, regions.ErrBoundaryTooLarge:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case regions.ErrParentNotFound:
+		apierror.Write(w, http.StatusBadRequest, "Parent region not found")
+		return
+	default:
+		logging.FromRequest(r).Error("CreateRegion error", "name", req.Name, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create region")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, region)
+}
+
+// DeleteRegion removes a region and its child regions
+func (h *RegionHandler) DeleteRegion(w http.ResponseWriter, r *http.Request) {
+	regionID := mux.Vars(r)["id"]
+
+	err := h.regionsService.DeleteRegion(regionID)
+	if err == regions.ErrRegionNotFound {
+		apierror.Write(w, http.StatusNotFound, "Region not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("DeleteRegion error", "region", regionID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to delete region")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetRegionAnalytics reports per-region volunteer and project totals.
+// Within a tenant, organization admins see their organization's figures;
+// platform admins can also view platform-wide totals.
+func (h *RegionHandler) GetRegionAnalytics(w http.ResponseWriter, r *http.Request) {
+	tenantID := tenant.FromRequest(r)
+	analytics, err := h.regionsService.GetRegionAnalytics(tenantID)
+	if err != nil {
+		logging.FromRequest(r).Error("GetRegionAnalytics error", "tenant", tenantID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch region analytics")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, analytics)
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/retention"
+)
+
+type RetentionHandler struct {
+	retentionService *retention.Service
+}
+
+func NewRetentionHandler(retentionService *retention.Service) *RetentionHandler {
+	return &RetentionHandler{
+		retentionService: retentionService,
+	}
+}
+
+// GetPolicy lists the retention rules in effect and whether scheduled runs
+// are dry runs
+func (h *RetentionHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.retentionService.Policy())
+}
+
+// GetRuns lists recent retention runs with what each rule purged, up to
+// ?limit= (default 20, at most 100)
+func (h *RetentionHandler) GetRuns(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 100 {
+			apierror.Write(w, http.StatusBadRequest, "limit must be between 1 and 100")
+			return
+		}
+		limit = n
+	}
+
+	runs, err := h.retentionService.GetRuns(limit)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get retention runs")
+		return
+	}
+	respondJSON(w, http.StatusOK, runs)
+}
+
+// RunRetention queues a run now, a dry run unless ?dryRun=false, or in the
+// configured mode without ?dryRun=
+func (h *RetentionHandler) RunRetention(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r)
+
+	var dryRun *bool
+	if raw := r.URL.Query().Get("dryRun"); raw != "" {
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			apierror.Write(w, http.StatusBadRequest, "dryRun must be true or false")
+			return
+		}
+		dryRun = &b
+	}
+
+	job, err := h.retentionService.QueueRun(dryRun)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to queue retention run")
+		return
+	}
+	logging.FromRequest(r).Info("Retention run queued", "user", userID, "dryRun", r.URL.Query().Get("dryRun"))
+	respondJSON(w, http.StatusAccepted, job)
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/reviews"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type ReviewHandler struct {
+	reviewsService *reviews.Service
+}
+
+func NewReviewHandler(reviewsService *reviews.Service) *ReviewHandler {
+	return &ReviewHandler{
+		reviewsService: reviewsService,
+	}
+}
+
+// GetProjectReviews lists a project's published reviews
+func (h *ReviewHandler) GetProjectReviews(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	list, err := h.reviewsService.GetProjectReviews(projectID, tenant.FromRequest(r))
+	if err == reviews.ErrProjectNotFound {
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetProjectReviews error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch reviews")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, list)
+}
+
+// SubmitReview records the acting volunteer's review of a project they
+// completed and of its organization
+func (h *ReviewHandler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	var req models.SubmitReviewRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	review, err := h.reviewsService.SubmitReview(projectID, userID, tenant.FromRequest(r), req)
+	switch err {
+	case nil:
+	case reviews.ErrInvalidRating, reviews.ErrCommentTooLong, reviews.ErrNoOrganization:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case reviews.ErrProjectNotFound:
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	case reviews.ErrNotEnrolled:
+		apierror.Write(w, http.StatusForbidden, "Only volunteers enrolled in the project can review it")
+		return
+	case reviews.ErrNotCompleted:
+		apierror.Write(w, http.StatusConflict, "Projects can be reviewed once completed")
+		return
+	default:
+		logging.FromRequest(r).Error("SubmitReview error", "project", projectID, "volunteer", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to submit review")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, review)
+}
+
+// GetPendingReviews lists reviews held for moderation
+func (h *ReviewHandler) GetPendingReviews(w http.ResponseWriter, r *http.Request) {
+	list, err := h.reviewsService.GetPendingReviews()
+	if err != nil {
+		logging.FromRequest(r).Error("GetPendingReviews error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch pending reviews")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, list)
+}
+
+// ModerateReview publishes or removes a review held for moderation
+func (h *ReviewHandler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	reviewID := mux.Vars(r)["reviewId"]
+
+	var req models.ModerateReviewRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID := auth.UserID(r)
+
+	review, err := h.reviewsService.ModerateReview(reviewID, userID, req)
+	switch err {
+	case nil:
+	case reviews.ErrInvalidAction:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case reviews.ErrReviewNotFound:
+		apierror.Write(w, http.StatusNotFound, "Review not found")
+		return
+	case reviews.ErrReviewNotPending:
+		apierror.Write(w, http.StatusConflict, "Review is not awaiting moderation")
+		return
+	default:
+		logging.FromRequest(r).Error("ModerateReview error", "review", reviewID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to moderate review")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, review)
+}
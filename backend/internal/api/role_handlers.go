@@ -0,0 +1,112 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/gorilla/mux"
+)
+
+type RoleHandler struct {
+	authService *auth.Service
+}
+
+func NewRoleHandler(authService *auth.Service) *RoleHandler {
+	return &RoleHandler{
+		authService: authService,
+	}
+}
+
+// GetDomainRules lists the rules giving roles by email domain
+func (h *RoleHandler) GetDomainRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.authService.GetDomainRules()
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get email domain rules")
+		return
+	}
+	respondJSON(w, http.StatusOK, rules)
+}
+
+// CreateDomainRule adds a rule giving users who register at a domain a
+// role, by default once a platform admin approves it
+func (h *RoleHandler) CreateDomainRule(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r)
+
+	var req models.CreateEmailDomainRuleRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	rule, err := h.authService.CreateDomainRule(req, userID)
+	switch err {
+	case nil:
+	case auth.ErrInvalidDomain, auth.ErrInvalidRuleRole, auth.ErrAdminRuleApproval:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case auth.ErrDomainRuleExists:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("CreateDomainRule error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create email domain rule")
+		return
+	}
+	respondJSON(w, http.StatusCreated, rule)
+}
+
+// DeleteDomainRule removes a rule; requests it already queued stay pending
+func (h *RoleHandler) DeleteDomainRule(w http.ResponseWriter, r *http.Request) {
+	err := h.authService.DeleteDomainRule(mux.Vars(r)["id"])
+	if err == auth.ErrDomainRuleNotFound {
+		apierror.Write(w, http.StatusNotFound, "Email domain rule not found")
+		return
+	}
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to delete email domain rule")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPendingRoleRequests lists roles email domain rules queued for
+// approval, oldest first
+func (h *RoleHandler) GetPendingRoleRequests(w http.ResponseWriter, r *http.Request) {
+	requests, err := h.authService.GetPendingRoleRequests()
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get role requests")
+		return
+	}
+	respondJSON(w, http.StatusOK, requests)
+}
+
+// ReviewRoleRequest approves or rejects a pending role request
+func (h *RoleHandler) ReviewRoleRequest(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r)
+
+	var req models.ReviewRoleRequestRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	request, err := h.authService.ReviewRoleRequest(mux.Vars(r)["id"], userID, req)
+	switch err {
+	case nil:
+	case auth.ErrInvalidRoleRequestDecision:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case auth.ErrRoleRequestNotFound:
+		apierror.Write(w, http.StatusNotFound, "Role request not found")
+		return
+	case auth.ErrRoleRequestNotPending:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("ReviewRoleRequest error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to review role request")
+		return
+	}
+	respondJSON(w, http.StatusOK, request)
+}
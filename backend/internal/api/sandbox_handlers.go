@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/sandbox"
+)
+
+type SandboxHandler struct {
+	sandboxService *sandbox.Service
+	params         sandbox.Params
+}
+
+// NewSandboxHandler takes the params configured for sandbox mode, which
+// GenerateSandbox reuses
+func NewSandboxHandler(sandboxService *sandbox.Service, params sandbox.Params) *SandboxHandler {
+	return &SandboxHandler{
+		sandboxService: sandboxService,
+		params:         params,
+	}
+}
+
+// GetSandbox counts the synthetic data currently in the database
+func (h *SandboxHandler) GetSandbox(w http.ResponseWriter, r *http.Request) {
+	summary, err := h.sandboxService.Status()
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get sandbox data")
+		return
+	}
+	respondJSON(w, http.StatusOK, summary)
+}
+
+// GenerateSandbox queues generation of synthetic data with the configured
+// seed and volumes. Sandbox data must be purged before it can be generated
+// again.
+func (h *SandboxHandler) GenerateSandbox(w http.ResponseWriter, r *http.Request) {
+	summary, err := h.sandboxService.Status()
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to generate sandbox data")
+		return
+	}
+	if summary.Volunteers > 0 || summary.Coordinators > 0 || summary.Projects > 0 {
+		apierror.Write(w, http.StatusConflict, sandbox.ErrAlreadyGenerated.Error())
+		return
+	}
+
+	job, err := h.sandboxService.QueueGenerate(h.params)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to generate sandbox data")
+		return
+	}
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// PurgeSandbox deletes every synthetic user, project and skill with all
+// their records, and returns what was deleted
+func (h *SandboxHandler) PurgeSandbox(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r)
+
+	summary, err := h.sandboxService.Purge()
+	if err != nil {
+		logging.FromRequest(r).Error("PurgeSandbox error", "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to purge sandbox data")
+		return
+	}
+	logging.FromRequest(r).Info("Sandbox data purged", "user", userID,
+		"volunteers", summary.Volunteers, "coordinators", summary.Coordinators, "projects", summary.Projects)
+	respondJSON(w, http.StatusOK, summary)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/scheduler"
+)
+
+const (
+	defaultScheduledRunLimit = 100
+	maxScheduledRunLimit     = 500
+)
+
+type ScheduleHandler struct {
+	schedulerService *scheduler.Service
+}
+
+func NewScheduleHandler(schedulerService *scheduler.Service) *ScheduleHandler {
+	return &ScheduleHandler{
+		schedulerService: schedulerService,
+	}
+}
+
+// GetTasks lists the recurring maintenance tasks with their schedules and
+// next and last runs
+func (h *ScheduleHandler) GetTasks(w http.ResponseWriter, r *http.Request) {
+	tasks, err := h.schedulerService.GetTasks()
+	if err != nil {
+		logging.FromRequest(r).Error("GetScheduledTasks error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch scheduled tasks")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tasks)
+}
+
+// GetRuns lists runs of the tasks newest first, optionally only of ?task=
+func (h *ScheduleHandler) GetRuns(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := defaultScheduledRunLimit
+	if raw := q.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			apierror.Write(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+	if limit > maxScheduledRunLimit {
+		limit = maxScheduledRunLimit
+	}
+
+	runs, err := h.schedulerService.GetRuns(q.Get("task"), limit)
+	if err != nil {
+		logging.FromRequest(r).Error("GetScheduledRuns error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch scheduled runs")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, runs)
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/search"
+	"github.com/civic-weave/backend/internal/tenant"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	// maxSearchOffset keeps paging within the index's default result window
+	maxSearchOffset = 10000
+)
+
+type SearchHandler struct {
+	searchService *search.Service
+}
+
+func NewSearchHandler(searchService *search.Service) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// SearchProjects finds active projects by ?q= text, tolerating typos, and
+// narrows them by ?category= (repeatable or comma-separated), distance from
+// ?lat= and ?lon= within ?radiusKm=, start date between ?startAfter= and
+// ?startBefore= (YYYY-MM-DD) and ?remote=. Facet counts by category,
+// distance and start month cover every match, not just the page.
+func (h *SearchHandler) SearchProjects(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := search.Query{
+		Text:     strings.TrimSpace(q.Get("q")),
+		TenantID: tenant.FromRequest(r),
+		Limit:    defaultSearchLimit,
+	}
+
+	for _, raw := range q["category"] {
+		for _, category := range strings.Split(raw, ",") {
+			if category = strings.TrimSpace(category); category != "" {
+				query.Categories = append(query.Categories, category)
+			}
+		}
+	}
+
+	if q.Get("lat") != "" || q.Get("lon") != "" {
+		lat, latErr := strconv.ParseFloat(q.Get("lat"), 64)
+		lon, lonErr := strconv.ParseFloat(q.Get("lon"), 64)
+		if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			apierror.Write(w, http.StatusBadRequest, "Valid lat and lon are required together")
+			return
+		}
+		query.Lat, query.Lon = &lat, &lon
+	}
+	if raw := q.Get("radiusKm"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed <= 0 || parsed > maxNearRadiusKm {
+			apierror.Write(w, http.StatusBadRequest, fmt.Sprintf("radiusKm must be between 0 and %g", maxNearRadiusKm))
+			return
+		}
+		if query.Lat == nil {
+			apierror.Write(w, http.StatusBadRequest, "radiusKm requires lat and lon")
+			return
+		}
+		query.RadiusKm = parsed
+	}
+
+	for _, d := range []struct {
+		param string
+		dest  **time.Time
+	}{
+		{"startAfter", &query.StartsAfter},
+		{"startBefore", &query.StartsBefore},
+	} {
+		if raw := q.Get(d.param); raw != "" {
+			parsed, err := time.Parse("2006-01-02", raw)
+			if err != nil {
+				apierror.Write(w, http.StatusBadRequest, fmt.Sprintf("%s must be a date (YYYY-MM-DD)", d.param))
+				return
+			}
+			*d.dest = &parsed
+		}
+	}
+
+	if raw := q.Get("remote"); raw != "" {
+		remote, err := strconv.ParseBool(raw)
+		if err != nil {
+			apierror.Write(w, http.StatusBadRequest, "remote must be true or false")
+			return
+		}
+		query.Remote = &remote
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			apierror.Write(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		query.Limit = parsed
+	}
+	if query.Limit > maxSearchLimit {
+		query.Limit = maxSearchLimit
+	}
+	if raw := q.Get("offset"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			apierror.Write(w, http.StatusBadRequest, "offset must be a non-negative integer")
+			return
+		}
+		query.Offset = parsed
+	}
+	if query.Offset+query.Limit > maxSearchOffset {
+		apierror.Write(w, http.StatusBadRequest, fmt.Sprintf("Search results can only be paged through the first %d", maxSearchOffset))
+		return
+	}
+
+	result, err := h.searchService.Search(r.Context(), query)
+	if err != nil {
+		logging.FromRequest(r).Error("SearchProjects error", "q", query.Text, "error", err)
+		apierror.Write(w, http.StatusBadGateway, "Search is unavailable")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Reindex queues a rebuild of the search index from every project
+func (h *SearchHandler) Reindex(w http.ResponseWriter, r *http.Request) {
+	job, err := h.searchService.QueueReindex()
+	if err != nil {
+		logging.FromRequest(r).Error("Reindex search error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to queue search reindex")
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, job)
+}
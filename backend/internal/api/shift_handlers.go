@@ -0,0 +1,538 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/notifications"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/civic-weave/backend/internal/shifts"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type ShiftHandler struct {
+	shiftsService        *shifts.Service
+	organizationsService *organizations.Service
+	mailer               notifications.Mailer
+}
+
+func NewShiftHandler(shiftsService *shifts.Service, organizationsService *organizations.Service, mailer notifications.Mailer) *ShiftHandler {
+	return &ShiftHandler{
+		shiftsService:        shiftsService,
+		organizationsService: organizationsService,
+		mailer:               mailer,
+	}
+}
+
+// GetProjectShifts lists a project's upcoming shifts with how many
+// volunteers each has; ?includePast=true also lists ended shifts
+func (h *ShiftHandler) GetProjectShifts(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	list, err := h.shiftsService.GetProjectShifts(projectID, tenant.FromRequest(r), r.URL.Query().Get("includePast") == "true")
+	if err == shifts.ErrProjectNotFound {
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetProjectShifts error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch shifts")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, list)
+}
+
+// CreateShift schedules a shift for a project
+func (h *ShiftHandler) CreateShift(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	var req models.CreateShiftRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID, ok := h.authorizeProject(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	shift, err := h.shiftsService.CreateShift(projectID, tenant.FromRequest(r), userID, req)
+	switch err {
+	case nil:
+	case shifts.ErrInvalidTimes, shifts.ErrInvalidCapacity, shifts.ErrTitleTooLong, shifts.ErrTooManySkills, shifts.ErrUnknownSkill:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case shifts.ErrProjectNotFound:
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	default:
+		logging.FromRequest(r).Error("CreateShift error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create shift")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, shift)
+}
+
+// GetShift returns one of a project's shifts
+func (h *ShiftHandler) GetShift(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	shiftID := vars["shiftId"]
+
+	shift, err := h.shiftsService.GetShift(projectID, shiftID, tenant.FromRequest(r))
+	if err == shifts.ErrShiftNotFound {
+		apierror.Write(w, http.StatusNotFound, "Shift not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetShift error", "shift", shiftID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch shift")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, shift)
+}
+
+// UpdateShift reschedules a shift that has not started, keeping its signups
+func (h *ShiftHandler) UpdateShift(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	shiftID := vars["shiftId"]
+
+	var req models.CreateShiftRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	if _, ok := h.authorizeProject(w, r, projectID); !ok {
+		return
+	}
+
+	shift, err := h.shiftsService.UpdateShift(projectID, shiftID, tenant.FromRequest(r), req)
+	switch err {
+	case nil:
+	case shifts.ErrInvalidTimes, shifts.ErrInvalidCapacity, shifts.ErrTitleTooLong, shifts.ErrTooManySkills, shifts.ErrUnknownSkill:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case shifts.ErrShiftNotFound:
+		apierror.Write(w, http.StatusNotFound, "Shift not found")
+		return
+	case shifts.ErrShiftStarted, shifts.ErrCapacityBelowSignups, shifts.ErrShiftConflict:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("UpdateShift error", "shift", shiftID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update shift")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, shift)
+}
+
+// DeleteShift cancels a shift and its signups
+func (h *ShiftHandler) DeleteShift(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	shiftID := vars["shiftId"]
+
+	if _, ok := h.authorizeProject(w, r, projectID); !ok {
+		return
+	}
+
+	err := h.shiftsService.DeleteShift(projectID, shiftID, tenant.FromRequest(r))
+	if err == shifts.ErrShiftNotFound {
+		apierror.Write(w, http.StatusNotFound, "Shift not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("DeleteShift error", "shift", shiftID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to delete shift")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetRoster lists a project's upcoming shifts with the volunteers booked on
+// each; ?includePast=true also lists ended shifts
+func (h *ShiftHandler) GetRoster(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	if _, ok := h.authorizeProject(w, r, projectID); !ok {
+		return
+	}
+
+	roster, err := h.shiftsService.GetRoster(projectID, tenant.FromRequest(r), r.URL.Query().Get("includePast") == "true")
+	if err == shifts.ErrProjectNotFound {
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetRoster error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch roster")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, roster)
+}
+
+// SignUp books the signed-in volunteer onto a shift. The volunteer must be
+// enrolled in the project and free for the whole shift.
+func (h *ShiftHandler) SignUp(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	shiftID := vars["shiftId"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	shift, err := h.shiftsService.SignUp(projectID, shiftID, userID, tenant.FromRequest(r))
+	switch err {
+	case nil:
+	case shifts.ErrShiftNotFound:
+		apierror.Write(w, http.StatusNotFound, "Shift not found")
+		return
+	case shifts.ErrNotEnrolled:
+		apierror.Write(w, http.StatusForbidden, "Only volunteers enrolled in the project can sign up for its shifts")
+		return
+	case shifts.ErrShiftStarted, shifts.ErrAlreadySignedUp, shifts.ErrShiftFull, shifts.ErrShiftConflict, shifts.ErrUnavailable:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("SignUp error", "shift", shiftID, "volunteer", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to sign up for shift")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, shift)
+}
+
+// CancelSignup removes a volunteer from a shift. Volunteers can cancel their
+// own signups; project coordinators can remove anyone.
+func (h *ShiftHandler) CancelSignup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	shiftID := vars["shiftId"]
+	volunteerID := vars["volunteerId"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+	if userID != volunteerID {
+		if _, ok := h.authorizeProject(w, r, projectID); !ok {
+			return
+		}
+	}
+
+	err := h.shiftsService.CancelSignup(projectID, shiftID, volunteerID, tenant.FromRequest(r))
+	if err == shifts.ErrSignupNotFound {
+		apierror.Write(w, http.StatusNotFound, "Shift signup not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("CancelSignup error", "shift", shiftID, "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to cancel shift signup")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetVolunteerShifts lists the upcoming shifts a volunteer is booked onto
+func (h *ShiftHandler) GetVolunteerShifts(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["id"]
+
+	list, err := h.shiftsService.GetVolunteerShifts(volunteerID, tenant.FromRequest(r))
+	if err != nil {
+		logging.FromRequest(r).Error("GetVolunteerShifts error", "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch shifts")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, list)
+}
+
+// ProposeAssignments suggests volunteers for a project's open upcoming
+// shifts. Nothing is booked until the proposal is accepted.
+func (h *ShiftHandler) ProposeAssignments(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	if _, ok := h.authorizeProject(w, r, projectID); !ok {
+		return
+	}
+
+	proposal, err := h.shiftsService.ProposeAssignments(projectID, tenant.FromRequest(r))
+	if err == shifts.ErrProjectNotFound {
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("ProposeAssignments error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to propose shift assignments")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, proposal)
+}
+
+// AcceptAssignments books a reviewed proposal, all or nothing, and returns
+// the updated roster
+func (h *ShiftHandler) AcceptAssignments(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	var req models.AcceptAssignmentsRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	if _, ok := h.authorizeProject(w, r, projectID); !ok {
+		return
+	}
+
+	err := h.shiftsService.AcceptAssignments(projectID, tenant.FromRequest(r), req.Assignments)
+	var assignmentErr *shifts.AssignmentError
+	switch {
+	case err == nil:
+	case err == shifts.ErrNoAssignments, err == shifts.ErrDuplicateAssignment:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.As(err, &assignmentErr):
+		apierror.WriteDetails(w, http.StatusConflict, assignmentErr.Error(), map[string]interface{}{
+			"shiftId":     assignmentErr.ShiftID,
+			"volunteerId": assignmentErr.VolunteerID,
+		})
+		return
+	default:
+		logging.FromRequest(r).Error("AcceptAssignments error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to accept shift assignments")
+		return
+	}
+
+	roster, err := h.shiftsService.GetRoster(projectID, tenant.FromRequest(r), false)
+	if err != nil {
+		logging.FromRequest(r).Error("GetRoster error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch roster")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, roster)
+}
+
+// GetCoverageRequests lists the open coverage requests for a project's
+// upcoming shifts
+func (h *ShiftHandler) GetCoverageRequests(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	requests, err := h.shiftsService.GetCoverageRequests(projectID, tenant.FromRequest(r))
+	if err == shifts.ErrProjectNotFound {
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetCoverageRequests error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch coverage requests")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, requests)
+}
+
+// RequestCoverage asks for someone to take over the signed-in volunteer's
+// shift. Enrolled volunteers free for the whole shift are emailed.
+func (h *ShiftHandler) RequestCoverage(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	shiftID := vars["shiftId"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	var req models.CreateCoverageRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	coverage, candidates, err := h.shiftsService.RequestCoverage(projectID, shiftID, userID, tenant.FromRequest(r), req)
+	switch err {
+	case nil:
+	case shifts.ErrNoteTooLong:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case shifts.ErrShiftNotFound:
+		apierror.Write(w, http.StatusNotFound, "Shift not found")
+		return
+	case shifts.ErrSignupNotFound:
+		apierror.Write(w, http.StatusForbidden, "Only volunteers booked onto the shift can request coverage")
+		return
+	case shifts.ErrShiftStarted, shifts.ErrCoverageRequested:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("RequestCoverage error", "shift", shiftID, "volunteer", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to request coverage")
+		return
+	}
+
+	branding := h.branding(projectID)
+	for _, candidate := range candidates {
+		data := coverageEmail(coverage, candidate.Email, candidate.Locale)
+		if coverage.Note != nil {
+			data.Note = *coverage.Note
+		}
+		if err := h.mailer.Send(notifications.RenderShiftCoverageRequest(data, branding)); err != nil {
+			logging.FromRequest(r).Error("RequestCoverage email error", "request", coverage.ID, "to", candidate.Email, "error", err)
+		}
+	}
+
+	respondJSON(w, http.StatusCreated, coverage)
+}
+
+// ClaimCoverage gives the signed-in volunteer the requester's place on the
+// shift and lets the requester know
+func (h *ShiftHandler) ClaimCoverage(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	requestID := vars["requestId"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	coverage, err := h.shiftsService.ClaimCoverage(projectID, requestID, userID, tenant.FromRequest(r))
+	switch err {
+	case nil:
+	case shifts.ErrCoverageNotFound:
+		apierror.Write(w, http.StatusNotFound, "Coverage request not found")
+		return
+	case shifts.ErrNotEnrolled:
+		apierror.Write(w, http.StatusForbidden, "Only volunteers enrolled in the project can cover its shifts")
+		return
+	case shifts.ErrOwnCoverage:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case shifts.ErrCoverageClosed, shifts.ErrShiftStarted, shifts.ErrAlreadySignedUp, shifts.ErrShiftConflict, shifts.ErrUnavailable:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("ClaimCoverage error", "request", requestID, "volunteer", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to claim coverage request")
+		return
+	}
+
+	if email, locale, err := h.shiftsService.GetVolunteerContact(coverage.RequesterID); err != nil {
+		logging.FromRequest(r).Error("ClaimCoverage requester lookup error", "request", requestID, "error", err)
+	} else if err := h.mailer.Send(notifications.RenderShiftCovered(coverageEmail(coverage, email, locale), h.branding(projectID))); err != nil {
+		logging.FromRequest(r).Error("ClaimCoverage email error", "request", requestID, "to", email, "error", err)
+	}
+
+	respondJSON(w, http.StatusOK, coverage)
+}
+
+// CancelCoverage withdraws an open coverage request. The requester and
+// project coordinators can cancel it.
+func (h *ShiftHandler) CancelCoverage(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["id"]
+	requestID := vars["requestId"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	coverage, err := h.shiftsService.GetCoverageRequest(projectID, requestID, tenant.FromRequest(r))
+	if err == shifts.ErrCoverageNotFound {
+		apierror.Write(w, http.StatusNotFound, "Coverage request not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetCoverageRequest error", "request", requestID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch coverage request")
+		return
+	}
+	if coverage.RequesterID != userID {
+		if _, ok := h.authorizeProject(w, r, projectID); !ok {
+			return
+		}
+	}
+
+	err = h.shiftsService.CancelCoverage(requestID)
+	if err == shifts.ErrCoverageClosed {
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("CancelCoverage error", "request", requestID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to cancel coverage request")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// branding returns the email branding of the project's organization, or
+// none if it can't be loaded
+func (h *ShiftHandler) branding(projectID string) models.Branding {
+	if settings, err := h.organizationsService.GetSettingsForProject(projectID); err == nil {
+		return settings.Branding
+	}
+	return models.Branding{}
+}
+
+func coverageEmail(coverage *models.ShiftCoverageRequest, to, locale string) notifications.ShiftCoverage {
+	data := notifications.ShiftCoverage{
+		To:            to,
+		Locale:        locale,
+		ProjectName:   coverage.ProjectName,
+		StartsAt:      coverage.StartsAt,
+		RequesterName: coverage.RequesterName,
+	}
+	if coverage.ShiftTitle != nil {
+		data.ShiftName = *coverage.ShiftTitle
+	}
+	if coverage.ClaimedByName != nil {
+		data.ClaimerName = *coverage.ClaimedByName
+	}
+	return data
+}
+
+// authorizeProject checks the signed-in user can manage the
+// project. It writes the error response and returns false otherwise.
+func (h *ShiftHandler) authorizeProject(w http.ResponseWriter, r *http.Request, projectID string) (string, bool) {
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return "", false
+	}
+
+	allowed, err := h.organizationsService.CanManageProject(userID, projectID)
+	if err != nil {
+		logging.FromRequest(r).Error("CanManageProject error", "project", projectID, "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check project permissions")
+		return "", false
+	}
+	if !allowed {
+		apierror.Write(w, http.StatusForbidden, "Only the project coordinator or organization coordinators can manage shifts")
+		return "", false
+	}
+
+	return userID, true
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/search"
+	"github.com/civic-weave/backend/internal/snapshot"
+)
+
+// maxSnapshotBytes bounds uploaded snapshot archives
+const maxSnapshotBytes = 1 << 30
+
+type SnapshotHandler struct {
+	snapshotService *snapshot.Service
+	// searchService is nil when project search is off
+	searchService *search.Service
+	allowRestore  bool
+}
+
+func NewSnapshotHandler(snapshotService *snapshot.Service, searchService *search.Service, allowRestore bool) *SnapshotHandler {
+	return &SnapshotHandler{
+		snapshotService: snapshotService,
+		searchService:   searchService,
+		allowRestore:    allowRestore,
+	}
+}
+
+// ExportSnapshot downloads the whole database as a gzipped tar archive that
+// RestoreSnapshot can load, e.g. to reset a demo between presentations.
+// The archive is built in a temporary file first so failures are reported
+// before anything is sent.
+func (h *SnapshotHandler) ExportSnapshot(w http.ResponseWriter, r *http.Request) {
+	file, err := os.CreateTemp("", "civic-weave-snapshot-*.tar.gz")
+	if err != nil {
+		logging.FromRequest(r).Error("ExportSnapshot temp file error", "error", err)
+		apierror.Write(w, http.StatusInternalServerError, "Failed to export snapshot")
+		return
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	manifest, err := h.snapshotService.Export(r.Context(), file)
+	if err != nil {
+		logging.FromRequest(r).Error("ExportSnapshot error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to export snapshot")
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		logging.FromRequest(r).Error("ExportSnapshot rewind error", "error", err)
+		apierror.Write(w, http.StatusInternalServerError, "Failed to export snapshot")
+		return
+	}
+
+	name := fmt.Sprintf("civic-weave-%s.tar.gz", manifest.CreatedAt.Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	http.ServeContent(w, r, name, manifest.CreatedAt.Truncate(time.Second), file)
+}
+
+// RestoreSnapshot replaces the whole database with an archive from
+// ExportSnapshot, sent as the request body. It is refused unless
+// DEMO_ALLOW_RESTORE is on.
+func (h *SnapshotHandler) RestoreSnapshot(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r)
+	if !h.allowRestore {
+		apierror.Write(w, http.StatusForbidden, "Restoring snapshots is disabled; set DEMO_ALLOW_RESTORE to enable it")
+		return
+	}
+
+	manifest, err := h.snapshotService.Restore(r.Context(), http.MaxBytesReader(w, r.Body, maxSnapshotBytes))
+	var tooLarge *http.MaxBytesError
+	var schemaErr *snapshot.SchemaError
+	switch {
+	case errors.As(err, &tooLarge):
+		apierror.Write(w, http.StatusRequestEntityTooLarge, "Snapshot may be at most 1 GB")
+		return
+	case err == snapshot.ErrInvalidArchive, err == snapshot.ErrFormat:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.As(err, &schemaErr):
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		logging.FromRequest(r).Error("RestoreSnapshot error", "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to restore snapshot")
+		return
+	}
+	logging.FromRequest(r).Info("Snapshot restored", "createdAt", manifest.CreatedAt, "user", userID)
+
+	// Projects removed by the restore are only dropped from the index by a
+	// full rebuild
+	if h.searchService != nil {
+		if _, err := h.searchService.QueueReindex(); err != nil {
+			logging.FromRequest(r).Error("RestoreSnapshot reindex error", "error", err)
+		}
+	}
+
+	respondJSON(w, http.StatusOK, manifest)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/events"
+	"github.com/civic-weave/backend/internal/logging"
+)
+
+// streamHeartbeat is how often an idle stream sends a comment, so proxies
+// don't close it
+const streamHeartbeat = 25 * time.Second
+
+type StreamHandler struct {
+	bus *events.Bus
+}
+
+func NewStreamHandler(bus *events.Bus) *StreamHandler {
+	return &StreamHandler{bus: bus}
+}
+
+// Stream sends the signed-in user the events that concern them as
+// server-sent events, until they disconnect
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		logging.FromRequest(r).Error("Stream write deadline error", "error", err)
+		apierror.Write(w, http.StatusInternalServerError, "Streaming is not supported")
+		return
+	}
+
+	ch, unsubscribe := h.bus.Subscribe(userID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	// Tell clients how long to wait before reconnecting
+	fmt.Fprint(w, "retry: 5000\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case e, ok := <-ch:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, e.Data)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
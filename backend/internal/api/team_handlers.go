@@ -0,0 +1,324 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/notifications"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/civic-weave/backend/internal/projects"
+	"github.com/civic-weave/backend/internal/teams"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+type TeamHandler struct {
+	teamsService         *teams.Service
+	projectsService      *projects.Service
+	organizationsService *organizations.Service
+	mailer               notifications.Mailer
+}
+
+func NewTeamHandler(teamsService *teams.Service, projectsService *projects.Service, organizationsService *organizations.Service, mailer notifications.Mailer) *TeamHandler {
+	return &TeamHandler{
+		teamsService:         teamsService,
+		projectsService:      projectsService,
+		organizationsService: organizationsService,
+		mailer:               mailer,
+	}
+}
+
+// GetProjectTeams lists a project's teams
+func (h *TeamHandler) GetProjectTeams(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	projectTeams, err := h.teamsService.GetProjectTeams(projectID, tenant.FromRequest(r))
+	if err == teams.ErrProjectNotFound {
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch teams")
+		return
+	}
+	if projectTeams == nil {
+		projectTeams = []models.Team{}
+	}
+
+	respondJSON(w, http.StatusOK, projectTeams)
+}
+
+// CreateTeam adds a team to a project
+func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	var req models.CreateTeamRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	if !h.authorizeProject(w, r, projectID) {
+		return
+	}
+
+	team, err := h.teamsService.CreateTeam(projectID, tenant.FromRequest(r), req)
+	switch err {
+	case nil:
+	case teams.ErrNameRequired:
+		apierror.Write(w, http.StatusBadRequest, "Team name is required")
+		return
+	case teams.ErrProjectNotFound:
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	case teams.ErrNotEnrolled:
+		apierror.Write(w, http.StatusBadRequest, "Team lead must be enrolled in the project")
+		return
+	case teams.ErrTeamNameTaken:
+		apierror.Write(w, http.StatusConflict, "A team with this name already exists in the project")
+		return
+	default:
+		logging.FromRequest(r).Error("CreateTeam error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create team")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, team)
+}
+
+// GetTeam returns a team with its members
+func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := h.teamsService.GetTeamMembers(team.ID)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch team members")
+		return
+	}
+	if members == nil {
+		members = []models.TeamMember{}
+	}
+
+	respondJSON(w, http.StatusOK, models.TeamWithMembers{Team: *team, Members: members})
+}
+
+// UpdateTeamLead sets or clears a team's lead
+func (h *TeamHandler) UpdateTeamLead(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateTeamLeadRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	team, ok := h.loadTeam(w, r)
+	if !ok || !h.authorizeProject(w, r, team.ProjectID) {
+		return
+	}
+
+	err := h.teamsService.SetTeamLead(team, req.LeadID)
+	if err == teams.ErrNotEnrolled {
+		apierror.Write(w, http.StatusBadRequest, "Team lead must be enrolled in the project")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("UpdateTeamLead error", "team", team.ID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update team lead")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Team lead updated"})
+}
+
+// AssignTeamMember puts an enrolled volunteer on a team, moving them off
+// any other team in the project
+func (h *TeamHandler) AssignTeamMember(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["volunteerId"]
+
+	team, ok := h.loadTeam(w, r)
+	if !ok || !h.authorizeProject(w, r, team.ProjectID) {
+		return
+	}
+
+	err := h.teamsService.AssignMember(team, volunteerID, auth.UserID(r))
+	if err == teams.ErrNotEnrolled {
+		apierror.Write(w, http.StatusBadRequest, "Volunteer must be enrolled in the project")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("AssignTeamMember error", "team", team.ID, "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to assign volunteer")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Volunteer assigned to team"})
+}
+
+// RemoveTeamMember takes a volunteer off a team
+func (h *TeamHandler) RemoveTeamMember(w http.ResponseWriter, r *http.Request) {
+	volunteerID := mux.Vars(r)["volunteerId"]
+
+	team, ok := h.loadTeam(w, r)
+	if !ok || !h.authorizeProject(w, r, team.ProjectID) {
+		return
+	}
+
+	err := h.teamsService.RemoveMember(team.ID, volunteerID)
+	if err == teams.ErrMemberNotFound {
+		apierror.Write(w, http.StatusNotFound, "Volunteer is not on this team")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("RemoveTeamMember error", "team", team.ID, "volunteer", volunteerID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to remove volunteer")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Volunteer removed from team"})
+}
+
+// GetTeamMessages lists a team's broadcasts
+func (h *TeamHandler) GetTeamMessages(w http.ResponseWriter, r *http.Request) {
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	messages, err := h.teamsService.GetTeamMessages(team.ID)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch team messages")
+		return
+	}
+	if messages == nil {
+		messages = []models.TeamMessage{}
+	}
+
+	respondJSON(w, http.StatusOK, messages)
+}
+
+// BroadcastTeamMessage emails a message to every enrolled member of a team.
+// The team lead and the project's managers may broadcast.
+func (h *TeamHandler) BroadcastTeamMessage(w http.ResponseWriter, r *http.Request) {
+	var req models.BroadcastTeamMessageRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	if team.LeadID == nil || *team.LeadID != userID {
+		allowed, err := h.organizationsService.CanManageProject(userID, team.ProjectID)
+		if err != nil {
+			logging.FromRequest(r).Error("CanManageProject error", "project", team.ProjectID, "user", userID, "error", err)
+			apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check project permissions")
+			return
+		}
+		if !allowed {
+			apierror.Write(w, http.StatusForbidden, "Only the team lead or project coordinators can message this team")
+			return
+		}
+	}
+
+	msg, recipients, err := h.teamsService.CreateBroadcast(r.Context(), team, userID, req)
+	switch err {
+	case nil:
+	case teams.ErrEmptyTeamMessage, teams.ErrSubjectTooLong:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case teams.ErrNoTeamMembers:
+		apierror.Write(w, http.StatusConflict, "Team has no enrolled members to message")
+		return
+	default:
+		logging.FromRequest(r).Error("BroadcastTeamMessage error", "team", team.ID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to send team message")
+		return
+	}
+
+	h.deliverBroadcast(r.Context(), team, msg, recipients)
+
+	respondJSON(w, http.StatusCreated, msg)
+}
+
+// deliverBroadcast emails a recorded broadcast to its recipients. Failures
+// are logged; the message stays in the team's history either way.
+func (h *TeamHandler) deliverBroadcast(ctx context.Context, team *models.Team, msg *models.TeamMessage, recipients []models.TeamMember) {
+	projectName := ""
+	if project, err := h.projectsService.GetProject(ctx, team.ProjectID, "", ""); err == nil {
+		projectName = project.Name
+	}
+
+	branding := models.Branding{}
+	if settings, err := h.organizationsService.GetSettingsForProject(team.ProjectID); err == nil {
+		branding = settings.Branding
+	}
+
+	for _, recipient := range recipients {
+		email := notifications.RenderTeamBroadcast(notifications.TeamBroadcast{
+			To:          recipient.VolunteerEmail,
+			Locale:      recipient.VolunteerLocale,
+			SenderName:  msg.SenderName,
+			TeamName:    team.Name,
+			ProjectName: projectName,
+			Subject:     msg.Subject,
+			Body:        msg.Body,
+		}, branding)
+		if err := h.mailer.Send(email); err != nil {
+			logging.FromContext(ctx).Error("BroadcastTeamMessage email error", "team", team.ID, "message", msg.ID, "to", recipient.VolunteerEmail, "error", err)
+		}
+	}
+}
+
+// loadTeam fetches the team named in the route, reporting teams outside the
+// request's tenant as not found. It writes the error response and returns
+// false on failure.
+func (h *TeamHandler) loadTeam(w http.ResponseWriter, r *http.Request) (*models.Team, bool) {
+	teamID := mux.Vars(r)["teamId"]
+
+	team, err := h.teamsService.GetTeam(teamID, tenant.FromRequest(r))
+	if err == teams.ErrTeamNotFound {
+		apierror.Write(w, http.StatusNotFound, "Team not found")
+		return nil, false
+	}
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch team")
+		return nil, false
+	}
+
+	return team, true
+}
+
+// authorizeProject checks that the acting user may manage the project's
+// teams, writing the error response and returning false when not
+func (h *TeamHandler) authorizeProject(w http.ResponseWriter, r *http.Request, projectID string) bool {
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return false
+	}
+
+	allowed, err := h.organizationsService.CanManageProject(userID, projectID)
+	if err != nil {
+		logging.FromRequest(r).Error("CanManageProject error", "project", projectID, "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check project permissions")
+		return false
+	}
+	if !allowed {
+		apierror.Write(w, http.StatusForbidden, "Only the project coordinator or organization coordinators can manage teams")
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/quotas"
+)
+
+type UsageHandler struct {
+	quotasService *quotas.Service
+}
+
+func NewUsageHandler(quotasService *quotas.Service) *UsageHandler {
+	return &UsageHandler{
+		quotasService: quotasService,
+	}
+}
+
+// GetTopConsumers lists the API keys and users that made the most requests
+// today or this month (?period=day|month, default day), up to ?limit=
+// (default 20, at most 100)
+func (h *UsageHandler) GetTopConsumers(w http.ResponseWriter, r *http.Request) {
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = quotas.PeriodDay
+	}
+	limit := 20
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 100 {
+			apierror.Write(w, http.StatusBadRequest, "limit must be between 1 and 100")
+			return
+		}
+		limit = n
+	}
+
+	report, err := h.quotasService.TopConsumers(period, limit, time.Now())
+	if err == quotas.ErrInvalidPeriod {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get API usage")
+		return
+	}
+	respondJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,305 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/organizations"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/civic-weave/backend/internal/waivers"
+	"github.com/gorilla/mux"
+)
+
+type WaiverHandler struct {
+	waiversService       *waivers.Service
+	organizationsService *organizations.Service
+}
+
+func NewWaiverHandler(waiversService *waivers.Service, organizationsService *organizations.Service) *WaiverHandler {
+	return &WaiverHandler{
+		waiversService:       waiversService,
+		organizationsService: organizationsService,
+	}
+}
+
+// GetOrganizationWaivers lists the waivers an organization requires for all
+// of its projects
+func (h *WaiverHandler) GetOrganizationWaivers(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	list, err := h.waiversService.GetOrganizationWaivers(orgID, tenant.FromRequest(r))
+	if err == waivers.ErrOrganizationNotFound {
+		apierror.Write(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetOrganizationWaivers error", "org", orgID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch waivers")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, list)
+}
+
+// CreateOrganizationWaiver adds a waiver required for every project of the
+// organization (organization admins)
+func (h *WaiverHandler) CreateOrganizationWaiver(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["id"]
+
+	var req models.WaiverTemplateRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID, ok := h.authorizeOrganization(w, r, orgID)
+	if !ok {
+		return
+	}
+
+	waiver, err := h.waiversService.CreateOrganizationWaiver(orgID, userID, tenant.FromRequest(r), req)
+	if h.respondTemplateError(w, err) {
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("CreateOrganizationWaiver error", "org", orgID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create waiver")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, waiver)
+}
+
+// GetProjectWaivers lists the waivers volunteers must sign to enroll in the
+// project. Each shows when the signed-in user signed its current version.
+func (h *WaiverHandler) GetProjectWaivers(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	list, err := h.waiversService.GetProjectWaivers(projectID, auth.UserID(r), tenant.FromRequest(r))
+	if err == waivers.ErrProjectNotFound {
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetProjectWaivers error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch waivers")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, list)
+}
+
+// CreateProjectWaiver adds a waiver required for the project
+func (h *WaiverHandler) CreateProjectWaiver(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+
+	var req models.WaiverTemplateRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID, ok := h.authorizeProject(w, r, projectID)
+	if !ok {
+		return
+	}
+
+	waiver, err := h.waiversService.CreateProjectWaiver(projectID, userID, tenant.FromRequest(r), req)
+	if h.respondTemplateError(w, err) {
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("CreateProjectWaiver error", "project", projectID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create waiver")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, waiver)
+}
+
+// UpdateWaiver publishes a new version of a waiver
+func (h *WaiverHandler) UpdateWaiver(w http.ResponseWriter, r *http.Request) {
+	waiverID := mux.Vars(r)["waiverId"]
+
+	var req models.WaiverTemplateRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	userID, ok := h.authorizeWaiver(w, r, waiverID)
+	if !ok {
+		return
+	}
+
+	waiver, err := h.waiversService.UpdateWaiver(waiverID, userID, tenant.FromRequest(r), req)
+	if h.respondTemplateError(w, err) {
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("UpdateWaiver error", "waiver", waiverID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update waiver")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, waiver)
+}
+
+// ArchiveWaiver stops requiring a waiver
+func (h *WaiverHandler) ArchiveWaiver(w http.ResponseWriter, r *http.Request) {
+	waiverID := mux.Vars(r)["waiverId"]
+
+	if _, ok := h.authorizeWaiver(w, r, waiverID); !ok {
+		return
+	}
+
+	err := h.waiversService.ArchiveWaiver(waiverID, tenant.FromRequest(r))
+	if err == waivers.ErrWaiverNotFound {
+		apierror.Write(w, http.StatusNotFound, "Waiver not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("ArchiveWaiver error", "waiver", waiverID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to archive waiver")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SignWaiver records the acting volunteer's e-signature of a waiver
+func (h *WaiverHandler) SignWaiver(w http.ResponseWriter, r *http.Request) {
+	waiverID := mux.Vars(r)["waiverId"]
+
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return
+	}
+
+	var req models.SignWaiverRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	sig, err := h.waiversService.Sign(waiverID, userID, auth.ClientIP(r), tenant.FromRequest(r), req)
+	switch err {
+	case nil:
+	case waivers.ErrInvalidSignedName:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	case waivers.ErrWaiverNotFound:
+		apierror.Write(w, http.StatusNotFound, "Waiver not found")
+		return
+	case waivers.ErrStaleVersion, waivers.ErrAlreadySigned:
+		apierror.Write(w, http.StatusConflict, err.Error())
+		return
+	default:
+		logging.FromRequest(r).Error("SignWaiver error", "waiver", waiverID, "volunteer", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to sign waiver")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, sig)
+}
+
+// GetWaiverSignatures lists who signed a waiver, across all its versions
+func (h *WaiverHandler) GetWaiverSignatures(w http.ResponseWriter, r *http.Request) {
+	waiverID := mux.Vars(r)["waiverId"]
+
+	if _, ok := h.authorizeWaiver(w, r, waiverID); !ok {
+		return
+	}
+
+	list, err := h.waiversService.GetSignatures(waiverID, tenant.FromRequest(r))
+	if err == waivers.ErrWaiverNotFound {
+		apierror.Write(w, http.StatusNotFound, "Waiver not found")
+		return
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetWaiverSignatures error", "waiver", waiverID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch signatures")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, list)
+}
+
+// respondTemplateError writes the response for validation and not-found
+// errors from creating or updating a waiver
+func (h *WaiverHandler) respondTemplateError(w http.ResponseWriter, err error) bool {
+	switch err {
+	case waivers.ErrInvalidTitle, waivers.ErrInvalidBody:
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+	case waivers.ErrOrganizationNotFound:
+		apierror.Write(w, http.StatusNotFound, "Organization not found")
+	case waivers.ErrProjectNotFound:
+		apierror.Write(w, http.StatusNotFound, "Project not found")
+	case waivers.ErrWaiverNotFound:
+		apierror.Write(w, http.StatusNotFound, "Waiver not found")
+	default:
+		return false
+	}
+	return true
+}
+
+// authorizeWaiver checks the acting user may manage the waiver: organization
+// admins for organization waivers, project managers for project waivers
+func (h *WaiverHandler) authorizeWaiver(w http.ResponseWriter, r *http.Request, waiverID string) (string, bool) {
+	waiver, err := h.waiversService.GetWaiver(waiverID, tenant.FromRequest(r))
+	if err == waivers.ErrWaiverNotFound {
+		apierror.Write(w, http.StatusNotFound, "Waiver not found")
+		return "", false
+	}
+	if err != nil {
+		logging.FromRequest(r).Error("GetWaiver error", "waiver", waiverID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to fetch waiver")
+		return "", false
+	}
+
+	if waiver.OrganizationID != nil {
+		return h.authorizeOrganization(w, r, *waiver.OrganizationID)
+	}
+	return h.authorizeProject(w, r, *waiver.ProjectID)
+}
+
+func (h *WaiverHandler) authorizeOrganization(w http.ResponseWriter, r *http.Request, orgID string) (string, bool) {
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return "", false
+	}
+
+	role, err := h.organizationsService.GetMemberRole(orgID, userID)
+	if err != nil {
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check permissions")
+		return "", false
+	}
+	if !organizations.RoleAtLeast(role, models.OrgRoleAdmin) {
+		apierror.Write(w, http.StatusForbidden, "Only organization admins can manage organization waivers")
+		return "", false
+	}
+
+	return userID, true
+}
+
+func (h *WaiverHandler) authorizeProject(w http.ResponseWriter, r *http.Request, projectID string) (string, bool) {
+	userID := auth.UserID(r)
+	if userID == "" {
+		apierror.Write(w, http.StatusBadRequest, "User ID required")
+		return "", false
+	}
+
+	allowed, err := h.organizationsService.CanManageProject(userID, projectID)
+	if err != nil {
+		logging.FromRequest(r).Error("CanManageProject error", "project", projectID, "user", userID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check project permissions")
+		return "", false
+	}
+	if !allowed {
+		apierror.Write(w, http.StatusForbidden, "Only the project coordinator or organization coordinators can manage project waivers")
+		return "", false
+	}
+
+	return userID, true
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/pagination"
+	"github.com/civic-weave/backend/internal/webhooks"
+	"github.com/gorilla/mux"
+)
+
+type WebhookHandler struct {
+	webhooksService *webhooks.Service
+}
+
+func NewWebhookHandler(webhooksService *webhooks.Service) *WebhookHandler {
+	return &WebhookHandler{
+		webhooksService: webhooksService,
+	}
+}
+
+// CreateWebhook registers a callback URL for events. The response carries
+// the signing secret, which is not shown again.
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserID(r)
+
+	var req models.CreateWebhookRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	webhook, err := h.webhooksService.CreateWebhook(r.Context(), req.URL, req.Events, userID)
+	if err != nil {
+		logging.FromRequest(r).Error("CreateWebhook error", "url", req.URL, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to create webhook")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, webhook)
+}
+
+// GetWebhooks lists the registered webhooks
+func (h *WebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	list, err := h.webhooksService.GetWebhooks(r.Context())
+	if err != nil {
+		logging.FromRequest(r).Error("GetWebhooks error", "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get webhooks")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, list)
+}
+
+// UpdateWebhook changes a webhook's events or pauses and resumes it
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID := mux.Vars(r)["webhookId"]
+
+	var req models.UpdateWebhookRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	webhook, err := h.webhooksService.UpdateWebhook(r.Context(), webhookID, req.Events, req.Active)
+	if err != nil {
+		logging.FromRequest(r).Error("UpdateWebhook error", "webhook", webhookID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to update webhook")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, webhook)
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID := mux.Vars(r)["webhookId"]
+
+	if err := h.webhooksService.DeleteWebhook(r.Context(), webhookID); err != nil {
+		logging.FromRequest(r).Error("DeleteWebhook error", "webhook", webhookID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to delete webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDeliveries lists a page of a webhook's deliveries, newest first, with
+// the outcome of each one's latest attempt
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhookID := mux.Vars(r)["webhookId"]
+
+	page, ok := parsePage(w, r, webhooks.DeliverySorts)
+	if !ok {
+		return
+	}
+
+	deliveries, total, err := h.webhooksService.GetDeliveries(r.Context(), webhookID, page)
+	if err != nil {
+		logging.FromRequest(r).Error("GetDeliveries error", "webhook", webhookID, "error", err)
+		apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to get webhook deliveries")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pagination.NewPage(deliveries, total, page))
+}
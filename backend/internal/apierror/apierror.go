@@ -0,0 +1,142 @@
+// Package apierror writes every API error response in one format:
+//
+//	{"error": "Project not found", "type": "not_found", "code": "error.project_not_found", "requestId": "..."}
+//
+// error is the message in the response's language, type a stable code for
+// the kind of failure, code the message's own code when it has one, and
+// requestId the ID to quote when reporting a problem. Services register
+// their sentinel errors with the status they are reported as, so a handler
+// can pass any service error to WriteErr without the error's text, which
+// may carry SQL details, reaching the client.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/i18n"
+	"github.com/civic-weave/backend/internal/logging"
+)
+
+// Code is the kind of failure an error response reports
+type Code string
+
+const (
+	CodeInvalidRequest       Code = "invalid_request"
+	CodeUnauthenticated      Code = "unauthenticated"
+	CodeForbidden            Code = "forbidden"
+	CodeNotFound             Code = "not_found"
+	CodeConflict             Code = "conflict"
+	CodeGone                 Code = "gone"
+	CodeTooLarge             Code = "too_large"
+	CodeUnsupportedMediaType Code = "unsupported_media_type"
+	CodeUnprocessable        Code = "unprocessable"
+	CodeRateLimited          Code = "rate_limited"
+	CodeInternal             Code = "internal"
+	CodeUpstream             Code = "upstream_unavailable"
+	CodeUnavailable          Code = "unavailable"
+)
+
+// codes maps statuses to the code reported with them
+var codes = map[int]Code{
+	http.StatusBadRequest:            CodeInvalidRequest,
+	http.StatusUnauthorized:          CodeUnauthenticated,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeConflict,
+	http.StatusGone:                  CodeGone,
+	http.StatusRequestEntityTooLarge: CodeTooLarge,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMediaType,
+	http.StatusUnprocessableEntity:   CodeUnprocessable,
+	http.StatusTooManyRequests:       CodeRateLimited,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusBadGateway:            CodeUpstream,
+	http.StatusServiceUnavailable:    CodeUnavailable,
+}
+
+// CodeFor returns the code reported with status
+func CodeFor(status int) Code {
+	if code, ok := codes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeInvalidRequest
+}
+
+// Body is the JSON body of an error response
+type Body struct {
+	Error     string `json:"error"`
+	Type      Code   `json:"type"`
+	Code      string `json:"code,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// Write responds with status and message, translated into the response's
+// language when the message has a translation
+func Write(w http.ResponseWriter, status int, message string) {
+	code, text := i18n.Error(w.Header().Get("Content-Language"), message)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Body{
+		Error:     text,
+		Type:      CodeFor(status),
+		Code:      code,
+		RequestID: w.Header().Get(logging.HeaderRequestID),
+	})
+}
+
+// WriteDetails responds like Write, adding details' fields to the body so
+// clients can show what caused the failure, such as the conflicting records
+func WriteDetails(w http.ResponseWriter, status int, message string, details map[string]interface{}) {
+	code, text := i18n.Error(w.Header().Get("Content-Language"), message)
+	body := map[string]interface{}{}
+	for k, v := range details {
+		body[k] = v
+	}
+	body["error"] = text
+	body["type"] = CodeFor(status)
+	if code != "" {
+		body["code"] = code
+	}
+	if id := w.Header().Get(logging.HeaderRequestID); id != "" {
+		body["requestId"] = id
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// sentinels maps registered service errors to their statuses. It is only
+// written by Register during initialization.
+var sentinels = map[error]int{}
+
+// Register reports errs, and errors wrapping them, with status and the
+// error's own message. Call it from init functions.
+func Register(status int, errs ...error) {
+	for _, err := range errs {
+		sentinels[err] = status
+	}
+}
+
+// WriteErr reports err from a service call. Registered errors are written
+// with their status and message, and transient database errors as 503 with
+// Retry-After. Anything else is written with status and message; the
+// error's own text is never sent.
+func WriteErr(w http.ResponseWriter, err error, status int, message string) {
+	for sentinel, sentinelStatus := range sentinels {
+		if errors.Is(err, sentinel) {
+			Write(w, sentinelStatus, sentinel.Error())
+			return
+		}
+	}
+	if database.IsTransient(err) {
+		w.Header().Set("Retry-After", "1")
+		Write(w, http.StatusServiceUnavailable, "Service temporarily unavailable, please retry")
+		return
+	}
+	Write(w, status, message)
+}
@@ -0,0 +1,126 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+// Header carries the API key on partner requests. Keys may instead be sent
+// as "Authorization: ApiKey <key>".
+const Header = "X-API-Key"
+
+// authScheme is the Authorization scheme for API keys
+const authScheme = "ApiKey "
+
+var ErrInvalidKey = errors.New("invalid api key")
+
+// Authenticate maps a raw key to the active key it names, returning
+// ErrInvalidKey when none matches
+type Authenticate func(raw string) (*models.APIKey, error)
+
+type contextKey struct{}
+
+// WithKey returns a copy of ctx carrying the API key a request was
+// authenticated with
+func WithKey(ctx context.Context, key *models.APIKey) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// FromRequest returns the API key the request was authenticated with, or nil
+// for requests made without one
+func FromRequest(r *http.Request) *models.APIKey {
+	key, _ := r.Context().Value(contextKey{}).(*models.APIKey)
+	return key
+}
+
+// routeScopes lists the only routes API keys may call, keyed by method and
+// route template, with the scope each requires
+var routeScopes = map[string]string{
+	"GET /api/projects":                     models.APIKeyScopeProjectsRead,
+	"GET /api/projects/near":                models.APIKeyScopeProjectsRead,
+	"GET /api/projects/{id}":                models.APIKeyScopeProjectsRead,
+	"GET /api/projects/{id}/skills":         models.APIKeyScopeProjectsRead,
+	"POST /api/enrollments":                 models.APIKeyScopeEnrollmentsWrite,
+	"POST /api/connectors/{source}/webhook": models.APIKeyScopeProjectsWrite,
+	"POST /api/admin/volunteers/import":     models.APIKeyScopeVolunteersWrite,
+}
+
+// Middleware authenticates requests carrying an API key, limits them to the
+// routes their scopes allow, and scopes them to the key's organization.
+// Requests without a key pass through untouched. It must run after routing
+// so the matched route template is available.
+func Middleware(authenticate Authenticate) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw := rawKey(r)
+			if raw == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key, err := authenticate(raw)
+			if errors.Is(err, ErrInvalidKey) {
+				apierror.Write(w, http.StatusUnauthorized, "Invalid or revoked API key")
+				return
+			}
+			if err != nil {
+				slog.Error("API key authentication error", "error", err)
+				apierror.Write(w, http.StatusInternalServerError, "Failed to authenticate API key")
+				return
+			}
+
+			if !allowed(r, key) {
+				apierror.Write(w, http.StatusForbidden, "API key is not allowed to call this endpoint")
+				return
+			}
+
+			ctx := WithKey(r.Context(), key)
+			ctx = tenant.WithTenant(ctx, key.OrganizationID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// rawKey returns the key sent in the header or the Authorization header.
+// Other Authorization schemes, such as the Bearer tokens of signed-in users,
+// aren't keys and are left to auth.Middleware.
+func rawKey(r *http.Request) string {
+	if raw := strings.TrimSpace(r.Header.Get(Header)); raw != "" {
+		return raw
+	}
+	raw, found := strings.CutPrefix(r.Header.Get("Authorization"), authScheme)
+	if !found {
+		return ""
+	}
+	return strings.TrimSpace(raw)
+}
+
+func allowed(r *http.Request, key *models.APIKey) bool {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return false
+	}
+
+	scope, ok := routeScopes[r.Method+" "+template]
+	if !ok {
+		return false
+	}
+	for _, granted := range key.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/events"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+// Execer runs statements on a database or inside a transaction
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Record appends an entry to the audit log. Run it in the transaction making
+// the change so the log never disagrees with the data.
+func Record(e Execer, actorID, action, targetType, targetID string, details map[string]string) error {
+	if details == nil {
+		details = map[string]string{}
+	}
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.Exec(`
+		INSERT INTO audit_log (actor_id, action, target_type, target_id, details)
+		VALUES (NULLIF($1, '')::uuid, $2, $3, $4, $5)
+	`, actorID, action, targetType, targetID, raw)
+	return err
+}
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// HandleEnrollmentChanged is the events.Dispatcher subscriber logging each
+// events.TypeEnrollmentChanged event as an enrollment.status entry
+func (s *Service) HandleEnrollmentChanged(ctx context.Context, e events.Event) error {
+	var c events.EnrollmentChanged
+	if err := e.Decode(&c); err != nil {
+		return err
+	}
+	return database.WithWriteGuard(func() error {
+		return Record(s.db, c.ActorID, "enrollment.status", "enrollment", c.EnrollmentID, map[string]string{
+			"status":      c.Status,
+			"projectId":   c.ProjectID,
+			"volunteerId": c.VolunteerID,
+		})
+	})
+}
+
+// GetEntries lists audit entries newest first, optionally only those about
+// one record
+func (s *Service) GetEntries(targetType, targetID string, limit int) ([]models.AuditEntry, error) {
+	query := `
+		SELECT id, actor_id, action, target_type, target_id, details, created_at
+		FROM audit_log
+		WHERE ($1 = '' OR target_type = $1)
+		  AND ($2 = '' OR target_id = $2)
+		ORDER BY created_at DESC, id
+		LIMIT $3
+	`
+
+	var entries []models.AuditEntry
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, targetType, targetID, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		entries = []models.AuditEntry{}
+		for rows.Next() {
+			var e models.AuditEntry
+			var details []byte
+			if err := rows.Scan(&e.ID, &e.ActorID, &e.Action, &e.TargetType, &e.TargetID, &details, &e.CreatedAt); err != nil {
+				return err
+			}
+			if err := json.Unmarshal(details, &e.Details); err != nil {
+				return err
+			}
+			entries = append(entries, e)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"time"
 
+	"github.com/civic-weave/backend/internal/database"
 	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/pagination"
 )
 
 var (
@@ -21,23 +23,104 @@ func NewService(db *sql.DB) *Service {
 	return &Service{db: db}
 }
 
-func (s *Service) GetAllUsers() ([]models.User, error) {
-	query := `
-		SELECT id, email, name, role, profile_complete, latitude, longitude, location_name, created_at, updated_at
-		FROM users
-		ORDER BY created_at DESC
+// UserSorts are the fields GetAllUsers can sort by, newest first by default
+var UserSorts = pagination.Sorts{
+	Fields: map[string]string{
+		"createdAt": "created_at",
+		"name":      "name",
+		"email":     "email",
+	},
+	Default:  "-createdAt",
+	Tiebreak: "id",
+}
+
+// GetAllUsers returns a page of users, limited to volunteers located in the
+// region when regionID is set and to one role when role is set, along with
+// how many users there are across all pages
+func (s *Service) GetAllUsers(regionID, role string, page pagination.Params) ([]models.User, int, error) {
+	where := `
+		WHERE ($1 = '' OR id IN (SELECT volunteer_id FROM volunteer_regions WHERE region_id = NULLIF($1, '')::uuid))
+		  AND ($2 = '' OR role = $2)
 	`
+	query := `
+		SELECT id, email, name, role, profile_complete, latitude, longitude, location_name, max_travel_km, timezone, locale, leaderboard_opt_in,
+		       CASE WHEN avatar_scan_status = 'clean' THEN avatar_url END, avatar_variants, suspended_at, email_verified_at,
+		       (SELECT role FROM role_requests WHERE user_id = users.id AND status = 'pending'), created_at, updated_at
+		FROM users` + where + `
+		ORDER BY ` + page.OrderBy + `
+		` + page.Window(3)
+
+	var users []models.User
+	var total int
+	err := database.WithReadRetry(func() error {
+		if err := s.db.QueryRow(`SELECT COUNT(*) FROM users`+where, regionID, role).Scan(&total); err != nil {
+			return err
+		}
+
+		rows, err := s.db.Query(query, append([]interface{}{regionID, role}, page.Args()...)...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
 
-	rows, err := s.db.Query(query)
+		users = nil
+		for rows.Next() {
+			var user models.User
+			err := rows.Scan(
+				&user.ID,
+				&user.Email,
+				&user.Name,
+				&user.Role,
+				&user.ProfileComplete,
+				&user.Latitude,
+				&user.Longitude,
+				&user.LocationName,
+				&user.MaxTravelKm,
+				&user.Timezone,
+				&user.Locale,
+				&user.LeaderboardOptIn,
+				&user.AvatarURL,
+				&user.AvatarVariants,
+				&user.SuspendedAt,
+				&user.EmailVerifiedAt,
+				&user.PendingRole,
+				&user.CreatedAt,
+				&user.UpdatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			users = append(users, user)
+		}
+		return rows.Err()
+	})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	defer rows.Close()
 
-	var users []models.User
-	for rows.Next() {
-		var user models.User
-		err := rows.Scan(
+	return users, total, nil
+}
+
+func (s *Service) GetUserByEmail(email string) (*models.User, error) {
+	return s.getUser("email = $1", email)
+}
+
+// GetUser returns the user with the ID
+func (s *Service) GetUser(userID string) (*models.User, error) {
+	return s.getUser("id::text = $1", userID)
+}
+
+func (s *Service) getUser(where, arg string) (*models.User, error) {
+	query := `
+		SELECT id, email, name, role, profile_complete, latitude, longitude, location_name, max_travel_km, timezone, locale, leaderboard_opt_in,
+		       CASE WHEN avatar_scan_status = 'clean' THEN avatar_url END, avatar_variants, suspended_at, email_verified_at,
+		       (SELECT role FROM role_requests WHERE user_id = users.id AND status = 'pending'), created_at, updated_at
+		FROM users
+		WHERE ` + where
+
+	var user models.User
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, arg).Scan(
 			&user.ID,
 			&user.Email,
 			&user.Name,
@@ -46,38 +129,19 @@ func (s *Service) GetAllUsers() ([]models.User, error) {
 			&user.Latitude,
 			&user.Longitude,
 			&user.LocationName,
+			&user.MaxTravelKm,
+			&user.Timezone,
+			&user.Locale,
+			&user.LeaderboardOptIn,
+			&user.AvatarURL,
+			&user.AvatarVariants,
+			&user.SuspendedAt,
+			&user.EmailVerifiedAt,
+			&user.PendingRole,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
-		if err != nil {
-			return nil, err
-		}
-		users = append(users, user)
-	}
-
-	return users, nil
-}
-
-func (s *Service) GetUserByEmail(email string) (*models.User, error) {
-	query := `
-		SELECT id, email, name, role, profile_complete, latitude, longitude, location_name, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
-
-	var user models.User
-	err := s.db.QueryRow(query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Name,
-		&user.Role,
-		&user.ProfileComplete,
-		&user.Latitude,
-		&user.Longitude,
-		&user.LocationName,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	})
 
 	if err == sql.ErrNoRows {
 		return nil, ErrUserNotFound
@@ -89,38 +153,127 @@ func (s *Service) GetUserByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-func (s *Service) RegisterVolunteer(name, email string) (*models.User, error) {
+// RegisterVolunteer creates a volunteer signing in with password, whose
+// emails are written in locale. When an email domain rule covers the
+// address, the user gets the rule's role instead, or stays a volunteer with
+// the role pending approval. The email address starts out unverified.
+func (s *Service) RegisterVolunteer(name, email, password, locale string) (*models.User, error) {
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if user already exists
 	existing, err := s.GetUserByEmail(email)
 	if err == nil && existing != nil {
 		return nil, ErrUserExists
 	}
 
-	query := `
-		INSERT INTO users (email, name, role, profile_complete)
-		VALUES ($1, $2, 'volunteer', FALSE)
-		RETURNING id, email, name, role, profile_complete, created_at, updated_at
-	`
+	var user *models.User
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		user, err = insertUser(tx, name, email, locale, sql.NullString{String: passwordHash, Valid: true}, false)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// insertUser creates a user, applying any email domain rule covering the
+// address. Users without a password hash can only sign in through a linked
+// identity.
+func insertUser(tx *sql.Tx, name, email, locale string, passwordHash sql.NullString, emailVerified bool) (*models.User, error) {
+	rule, err := matchDomainRule(tx, email)
+	if err != nil {
+		return nil, err
+	}
+	role := "volunteer"
+	if rule != nil && !rule.RequiresApproval {
+		role = rule.Role
+	}
 
 	var user models.User
-	err = s.db.QueryRow(query, email, name).Scan(
+	err = tx.QueryRow(`
+		INSERT INTO users (email, name, role, profile_complete, locale, password_hash, email_verified_at)
+		VALUES ($1, $2, $3, FALSE, $4, $5, CASE WHEN $6 THEN CURRENT_TIMESTAMP END)
+		RETURNING id, email, name, role, profile_complete, timezone, locale, leaderboard_opt_in,
+		       CASE WHEN avatar_scan_status = 'clean' THEN avatar_url END, avatar_variants, suspended_at, email_verified_at,
+		       created_at, updated_at
+	`, email, name, role, locale, passwordHash, emailVerified).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Name,
 		&user.Role,
 		&user.ProfileComplete,
+		&user.Timezone,
+		&user.Locale,
+		&user.LeaderboardOptIn,
+		&user.AvatarURL,
+		&user.AvatarVariants,
+		&user.SuspendedAt,
+		&user.EmailVerifiedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
-
 	if err != nil {
 		return nil, err
 	}
 
+	if rule != nil && rule.RequiresApproval {
+		_, err := tx.Exec(`
+			INSERT INTO role_requests (user_id, role, rule_id) VALUES ($1, $2, $3)
+		`, user.ID, rule.Role, rule.ID)
+		if err != nil {
+			return nil, err
+		}
+		user.PendingRole = &rule.Role
+	}
 	return &user, nil
 }
 
-func (s *Service) CreateDefaultUsers() error {
+// SetLocale changes the language a user's emails are written in
+func (s *Service) SetLocale(userID, locale string) error {
+	var res sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		res, err = s.db.Exec(`
+			UPDATE users SET locale = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+		`, userID, locale)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// CreateDefaultUsers adds an admin, a coordinator and a volunteer for
+// testing, signing in with password; with no password they cannot sign in.
+// Existing default users without a password get this one.
+func (s *Service) CreateDefaultUsers(password string) error {
+	passwordHash := ""
+	if password != "" {
+		var err error
+		if passwordHash, err = hashPassword(password); err != nil {
+			return err
+		}
+	}
+
 	defaultUsers := []struct {
 		email string
 		name  string
@@ -135,10 +288,17 @@ func (s *Service) CreateDefaultUsers() error {
 		_, err := s.GetUserByEmail(u.email)
 		if err == ErrUserNotFound {
 			query := `
-				INSERT INTO users (email, name, role, profile_complete, created_at, updated_at)
-				VALUES ($1, $2, $3, TRUE, $4, $4)
+				INSERT INTO users (email, name, role, profile_complete, password_hash, created_at, updated_at)
+				VALUES ($1, $2, $3, TRUE, NULLIF($4, ''), $5, $5)
 			`
-			_, err := s.db.Exec(query, u.email, u.name, u.role, time.Now())
+			_, err := s.db.Exec(query, u.email, u.name, u.role, passwordHash, time.Now())
+			if err != nil {
+				return err
+			}
+		} else if err == nil && passwordHash != "" {
+			_, err := s.db.Exec(`
+				UPDATE users SET password_hash = $2 WHERE email = $1 AND password_hash IS NULL
+			`, u.email, passwordHash)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrInvalidDomain              = errors.New("domain must be a domain name such as cityhall.gov")
+	ErrInvalidRuleRole            = errors.New("role must be coordinator or admin")
+	ErrAdminRuleApproval          = errors.New("rules granting the admin role must require approval")
+	ErrDomainRuleExists           = errors.New("a rule for this domain already exists")
+	ErrDomainRuleNotFound         = errors.New("email domain rule not found")
+	ErrRoleRequestNotFound        = errors.New("role request not found")
+	ErrRoleRequestNotPending      = errors.New("role request has already been reviewed")
+	ErrInvalidRoleRequestDecision = errors.New("status must be approved or rejected")
+)
+
+const domainRuleColumns = `id, domain, role, requires_approval, created_by, created_at`
+
+func scanDomainRule(scanner interface{ Scan(...interface{}) error }, r *models.EmailDomainRule) error {
+	return scanner.Scan(&r.ID, &r.Domain, &r.Role, &r.RequiresApproval, &r.CreatedBy, &r.CreatedAt)
+}
+
+// normalizeDomain lowercases a domain, dropping a leading @, and reports
+// whether it is a plausible domain name
+func normalizeDomain(domain string) (string, bool) {
+	domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+	if len(domain) > 255 || !strings.Contains(domain, ".") {
+		return "", false
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return "", false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+				return "", false
+			}
+		}
+	}
+	return domain, true
+}
+
+// matchDomainRule returns the rule for the domain of email, or nil if there
+// is none
+func matchDomainRule(tx *sql.Tx, email string) (*models.EmailDomainRule, error) {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return nil, nil
+	}
+
+	var rule models.EmailDomainRule
+	row := tx.QueryRow(`
+		SELECT `+domainRuleColumns+` FROM email_domain_rules WHERE domain = $1
+	`, strings.ToLower(email[at+1:]))
+	err := scanDomainRule(row, &rule)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// GetDomainRules lists the email domain rules by domain
+func (s *Service) GetDomainRules() ([]models.EmailDomainRule, error) {
+	rules := []models.EmailDomainRule{}
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(`SELECT ` + domainRuleColumns + ` FROM email_domain_rules ORDER BY domain`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		rules = rules[:0]
+		for rows.Next() {
+			var rule models.EmailDomainRule
+			if err := scanDomainRule(rows, &rule); err != nil {
+				return err
+			}
+			rules = append(rules, rule)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CreateDomainRule adds a rule giving users who register at req.Domain
+// req.Role. It only applies to later registrations.
+func (s *Service) CreateDomainRule(req models.CreateEmailDomainRuleRequest, createdBy string) (*models.EmailDomainRule, error) {
+	domain, ok := normalizeDomain(req.Domain)
+	if !ok {
+		return nil, ErrInvalidDomain
+	}
+	if req.Role != "coordinator" && req.Role != "admin" {
+		return nil, ErrInvalidRuleRole
+	}
+	requiresApproval := req.RequiresApproval == nil || *req.RequiresApproval
+	if req.Role == "admin" && !requiresApproval {
+		return nil, ErrAdminRuleApproval
+	}
+
+	var rule models.EmailDomainRule
+	err := database.WithWriteGuard(func() error {
+		row := s.db.QueryRow(`
+			INSERT INTO email_domain_rules (domain, role, requires_approval, created_by)
+			VALUES ($1, $2, $3, $4)
+			RETURNING `+domainRuleColumns,
+			domain, req.Role, requiresApproval, createdBy)
+		return scanDomainRule(row, &rule)
+	})
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return nil, ErrDomainRuleExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// DeleteDomainRule removes a rule. Role requests it queued stay pending.
+func (s *Service) DeleteDomainRule(ruleID string) error {
+	var res sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		res, err = s.db.Exec(`DELETE FROM email_domain_rules WHERE id = $1`, ruleID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrDomainRuleNotFound
+	}
+	return nil
+}
+
+const roleRequestColumns = `
+	rr.id, rr.user_id, u.name, u.email, rr.role, rr.rule_id, rr.status, rr.note, rr.reviewed_by, rr.reviewed_at, rr.created_at
+`
+
+func scanRoleRequest(scanner interface{ Scan(...interface{}) error }, r *models.RoleRequest) error {
+	return scanner.Scan(
+		&r.ID,
+		&r.UserID,
+		&r.UserName,
+		&r.UserEmail,
+		&r.Role,
+		&r.RuleID,
+		&r.Status,
+		&r.Note,
+		&r.ReviewedBy,
+		&r.ReviewedAt,
+		&r.CreatedAt,
+	)
+}
+
+// GetPendingRoleRequests lists role requests awaiting review, oldest first
+func (s *Service) GetPendingRoleRequests() ([]models.RoleRequest, error) {
+	requests := []models.RoleRequest{}
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(`
+			SELECT ` + roleRequestColumns + `
+			FROM role_requests rr
+			JOIN users u ON u.id = rr.user_id
+			WHERE rr.status = 'pending'
+			ORDER BY rr.created_at
+		`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		requests = requests[:0]
+		for rows.Next() {
+			var request models.RoleRequest
+			if err := scanRoleRequest(rows, &request); err != nil {
+				return err
+			}
+			requests = append(requests, request)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// ReviewRoleRequest records a platform admin's decision on a pending role
+// request, giving the user the role when it is approved
+func (s *Service) ReviewRoleRequest(requestID, reviewedBy string, req models.ReviewRoleRequestRequest) (*models.RoleRequest, error) {
+	if req.Status != models.RoleRequestApproved && req.Status != models.RoleRequestRejected {
+		return nil, ErrInvalidRoleRequestDecision
+	}
+	var note *string
+	if req.Note != nil && strings.TrimSpace(*req.Note) != "" {
+		trimmed := strings.TrimSpace(*req.Note)
+		note = &trimmed
+	}
+
+	var request models.RoleRequest
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var status string
+		err = tx.QueryRow(`SELECT status FROM role_requests WHERE id = $1 FOR UPDATE`, requestID).Scan(&status)
+		if err == sql.ErrNoRows {
+			return ErrRoleRequestNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if status != models.RoleRequestPending {
+			return ErrRoleRequestNotPending
+		}
+
+		row := tx.QueryRow(`
+			UPDATE role_requests rr
+			SET status = $2, note = $3, reviewed_by = $4, reviewed_at = CURRENT_TIMESTAMP
+			FROM users u
+			WHERE rr.id = $1 AND u.id = rr.user_id
+			RETURNING `+roleRequestColumns,
+			requestID, req.Status, note, reviewedBy)
+		if err := scanRoleRequest(row, &request); err != nil {
+			return err
+		}
+
+		if req.Status == models.RoleRequestApproved {
+			_, err := tx.Exec(`
+				UPDATE users SET role = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+			`, request.UserID, request.Role)
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
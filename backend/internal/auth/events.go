@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"github.com/civic-weave/backend/internal/database"
+)
+
+// Auth event types
+const (
+	EventLogin           = "login"
+	EventLoginFailed     = "login_failed"
+	EventRegister        = "register"
+	EventPasswordChanged = "password_changed"
+	EventPasswordReset   = "password_reset"
+)
+
+// RecordAuthEvent logs a sign-in attempt, registration, or password change
+// or reset from ip. userID is empty for failed sign-ins by unknown
+// addresses. A successful sign-in also becomes the user's last sign-in,
+// which retention measures inactivity from.
+func (s *Service) RecordAuthEvent(eventType, userID, email, ip string) error {
+	return database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		_, err = tx.Exec(`
+			INSERT INTO auth_events (user_id, email, event_type, ip_address)
+			VALUES (NULLIF($1, '')::uuid, $2, $3, NULLIF($4, ''))
+		`, userID, email, eventType, ip)
+		if err != nil {
+			return err
+		}
+
+		if eventType == EventLogin {
+			_, err := tx.Exec(`UPDATE users SET last_login_at = CURRENT_TIMESTAMP WHERE id = $1`, userID)
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+}
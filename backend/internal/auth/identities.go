@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/auth/oidc"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+var ErrEmailNotVerified = errors.New("the provider has not verified the email address")
+
+// SignInWithIdentity returns the user an external identity signs in as. An
+// identity seen before signs in as the user it was linked to. Otherwise it
+// is linked to the user with the same email address, which the provider
+// must have verified, or a volunteer is created for it whose emails are
+// written in locale. created reports whether a user was created.
+func (s *Service) SignInWithIdentity(identity oidc.Identity, locale string) (user *models.User, created bool, err error) {
+	var userID string
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		err = tx.QueryRow(`
+			UPDATE user_identities SET last_used_at = CURRENT_TIMESTAMP
+			WHERE provider = $1 AND subject = $2
+			RETURNING user_id
+		`, identity.Provider, identity.Subject).Scan(&userID)
+		if err == nil {
+			return tx.Commit()
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if !identity.EmailVerified {
+			return ErrEmailNotVerified
+		}
+
+		// The provider vouches for the address, so it counts as verified
+		err = tx.QueryRow(`
+			UPDATE users SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP), updated_at = CURRENT_TIMESTAMP
+			WHERE LOWER(email) = LOWER($1)
+			RETURNING id
+		`, identity.Email).Scan(&userID)
+		if err == sql.ErrNoRows {
+			name := strings.TrimSpace(identity.Name)
+			if name == "" {
+				name = identity.Email
+			}
+			u, err := insertUser(tx, name, identity.Email, locale, sql.NullString{}, true)
+			if err != nil {
+				return err
+			}
+			userID = u.ID
+			created = true
+		} else if err != nil {
+			return err
+		} else if _, err := tx.Exec(`DELETE FROM email_verification_tokens WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO user_identities (provider, subject, user_id, email)
+			VALUES ($1, $2, $3, $4)
+		`, identity.Provider, identity.Subject, userID, identity.Email)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	user, err = s.GetUser(userID)
+	if err != nil {
+		return nil, false, err
+	}
+	return user, created, nil
+}
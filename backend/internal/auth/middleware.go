@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/apikeys"
+	"github.com/gorilla/mux"
+)
+
+type contextKey struct{}
+
+// publicRoutes lists the routes callers may use without signing in, keyed
+// by method and route template. Some authenticate by other means: the
+// calendar feed by its token and document content by its signed URL.
+var publicRoutes = map[string]bool{
+	"POST /api/auth/login":                    true,
+	"POST /api/auth/register":                 true,
+	"POST /api/auth/refresh":                  true,
+	"POST /api/auth/verify":                   true,
+	"POST /api/auth/forgot-password":          true,
+	"POST /api/auth/reset-password":           true,
+	"GET /api/auth/oauth/providers":           true,
+	"GET /api/auth/oauth/{provider}/login":    true,
+	"GET /api/auth/oauth/{provider}/callback": true,
+	"GET /api/health":                         true,
+	"GET /api/volunteers/{id}/public-profile": true,
+	"GET /api/volunteers/{id}/calendar.ics":   true,
+	"GET /api/documents/{documentId}/content": true,
+	"POST /api/events":                        true,
+	"GET /api/openapi.json":                   true,
+	"GET /api/docs":                           true,
+}
+
+// IsPublicRoute reports whether callers may use the route with method and
+// template without signing in
+func IsPublicRoute(method, template string) bool {
+	return publicRoutes[method+" "+template]
+}
+
+// WithClaims returns a copy of ctx carrying the signed-in user's claims
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, contextKey{}, claims)
+}
+
+// FromRequest returns the claims of the user the request was signed in as,
+// or nil for requests made without a token
+func FromRequest(r *http.Request) *Claims {
+	claims, _ := r.Context().Value(contextKey{}).(*Claims)
+	return claims
+}
+
+// UserID returns the ID of the user the request was signed in as, or ""
+func UserID(r *http.Request) string {
+	if claims := FromRequest(r); claims != nil {
+		return claims.UserID
+	}
+	return ""
+}
+
+// ClientIP is the address a request came from, as resolved by
+// TrustedProxies.Middleware, or the connection's remote address
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+// Middleware authenticates requests by the bearer token in their
+// Authorization header, adding its claims to the request context. Requests
+// without a token are refused with 401 unless they carry an API key or
+// call a public route, and so are tokens whose session has ended. It must
+// run after routing, so the matched route template is available, and after
+// apikeys.Middleware.
+func Middleware(tokens *Tokens, sessions *SessionCache) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || strings.TrimSpace(raw) == "" {
+				if apikeys.FromRequest(r) != nil || public(r) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				apierror.Write(w, http.StatusUnauthorized, "Authentication required")
+				return
+			}
+
+			claims, err := tokens.Verify(strings.TrimSpace(raw))
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				apierror.Write(w, http.StatusUnauthorized, "Invalid or expired token")
+				return
+			}
+
+			if claims.SessionID != "" {
+				active, err := sessions.Active(claims.SessionID)
+				if err != nil {
+					slog.Error("Session check error", "session", claims.SessionID, "error", err)
+					apierror.Write(w, http.StatusInternalServerError, "Failed to check session")
+					return
+				}
+				if !active {
+					w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+					apierror.Write(w, http.StatusUnauthorized, "Session has ended")
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+func public(r *http.Request) bool {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return false
+	}
+	return IsPublicRoute(r.Method, template)
+}
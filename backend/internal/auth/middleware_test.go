@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/civic-weave/backend/internal/models"
+)
+
+func TestMiddlewareSessions(t *testing.T) {
+	tokens := NewTokens([]byte("0123456789abcdef0123456789abcdef"), time.Minute, time.Hour)
+	sessions := NewSessionCache(func(sessionID string) (bool, error) {
+		switch sessionID {
+		case "active":
+			return true, nil
+		case "broken":
+			return false, errors.New("lookup failed")
+		}
+		return false, nil
+	}, time.Minute)
+
+	tests := []struct {
+		name      string
+		sessionID string
+		want      int
+	}{
+		{"active session", "active", http.StatusOK},
+		{"revoked session", "revoked", http.StatusUnauthorized},
+		{"token without session", "", http.StatusOK},
+		{"lookup error", "broken", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, _, err := tokens.Issue(&models.User{ID: "user-1", Role: RoleVolunteer}, tt.sessionID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			handler := Middleware(tokens, sessions)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/api/projects", nil)
+			r.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionCache(t *testing.T) {
+	revoked := false
+	lookups := 0
+	active := func(sessionID string) (bool, error) {
+		lookups++
+		return !revoked, nil
+	}
+
+	cached := NewSessionCache(active, time.Hour)
+	for i := 0; i < 3; i++ {
+		if ok, _ := cached.Active("s1"); !ok {
+			t.Fatalf("Active() = false before revoking")
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("lookups = %d, want 1 within the TTL", lookups)
+	}
+
+	revoked = true
+	uncached := NewSessionCache(active, 0)
+	if ok, _ := uncached.Active("s1"); ok {
+		t.Errorf("Active() = true after revoking with no TTL")
+	}
+}
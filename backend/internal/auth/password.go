@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/civic-weave/backend/internal/database"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password length limits; bcrypt ignores bytes past the 72nd
+const (
+	MinPasswordLength = 8
+	maxPasswordBytes  = 72
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrPasswordTooShort   = errors.New("password must be at least 8 characters")
+	ErrPasswordTooLong    = errors.New("password must be at most 72 bytes")
+)
+
+// hashPassword validates a new password's length and returns its bcrypt
+// hash
+func hashPassword(password string) (string, error) {
+	if len([]rune(password)) < MinPasswordLength {
+		return "", ErrPasswordTooShort
+	}
+	if len(password) > maxPasswordBytes {
+		return "", ErrPasswordTooLong
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword returns ErrInvalidCredentials unless password is the
+// user's. Users without a password, such as imported volunteers, cannot
+// sign in with one.
+func (s *Service) CheckPassword(userID, password string) error {
+	var hash sql.NullString
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(`SELECT password_hash FROM users WHERE id = $1`, userID).Scan(&hash)
+	})
+	if err == sql.ErrNoRows {
+		return ErrInvalidCredentials
+	}
+	if err != nil {
+		return err
+	}
+	if !hash.Valid || bcrypt.CompareHashAndPassword([]byte(hash.String), []byte(password)) != nil {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// ChangePassword replaces the user's password with newPassword once
+// currentPassword checks out. Users without a password get
+// ErrInvalidCredentials.
+func (s *Service) ChangePassword(userID, currentPassword, newPassword string) error {
+	if err := s.CheckPassword(userID, currentPassword); err != nil {
+		return err
+	}
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	var res sql.Result
+	err = database.WithWriteGuard(func() error {
+		var err error
+		res, err = s.db.Exec(`
+			UPDATE users SET password_hash = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+		`, userID, hash)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrustedProxiesClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded string
+		want      string
+	}{
+		{"direct", "203.0.113.5:4000", "", "203.0.113.5"},
+		{"untrusted peer spoofing", "203.0.113.5:4000", "198.51.100.7", "203.0.113.5"},
+		{"trusted proxy", "10.1.2.3:4000", "198.51.100.7", "198.51.100.7"},
+		{"trusted proxy with spoofed leftmost hop", "10.1.2.3:4000", "1.2.3.4, 198.51.100.7", "198.51.100.7"},
+		{"chain of trusted proxies", "10.1.2.3:4000", "198.51.100.7, 192.0.2.1, 10.9.9.9", "198.51.100.7"},
+		{"trusted proxy without header", "192.0.2.1:4000", "", "192.0.2.1"},
+		{"trusted proxy with garbage hop", "10.1.2.3:4000", "not-an-ip", "10.1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if got := proxies.clientIP(r); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesRejectsInvalid(t *testing.T) {
+	for _, entry := range []string{"", "proxy.internal", "10.0.0.0/33"} {
+		if _, err := ParseTrustedProxies([]string{entry}); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) succeeded, want error", entry)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"database/sql"
+	"log/slog"
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/database"
+)
+
+// Platform roles, as stored in users.role
+const (
+	RoleVolunteer   = "volunteer"
+	RoleCoordinator = "coordinator"
+	RoleAdmin       = "admin"
+)
+
+// Permission names something a route lets its caller do
+type Permission string
+
+const (
+	// PermViewVolunteerMatches lists the volunteers matching a project
+	PermViewVolunteerMatches Permission = "matches.volunteers"
+	// PermViewProjectMatches lists the projects matching a volunteer
+	PermViewProjectMatches Permission = "matches.projects"
+	// PermRefreshMatching rebuilds the skill vectors matching reads
+	PermRefreshMatching Permission = "matching.refresh"
+	// PermAuditSessions lists any user's sessions, including ended ones
+	PermAuditSessions Permission = "sessions.audit"
+	// PermListUsers lists every user
+	PermListUsers Permission = "users.list"
+	// PermManageHolidays adds and removes platform-wide holidays
+	PermManageHolidays Permission = "holidays.manage"
+	// PermManageRegions adds and removes regions
+	PermManageRegions Permission = "regions.manage"
+	// PermModerate reviews reports, lifts suspensions, removes profile
+	// references and reads the audit log
+	PermModerate Permission = "moderation.manage"
+	// PermModerateReviews publishes or removes reviews held for moderation
+	PermModerateReviews Permission = "reviews.moderate"
+	// PermManageRoles manages email domain rules and the role requests they
+	// queue
+	PermManageRoles Permission = "roles.manage"
+	// PermManageDuplicates finds, dismisses and merges duplicate accounts
+	PermManageDuplicates Permission = "duplicates.manage"
+	// PermManageJobs lists and retries background jobs and views scheduled
+	// tasks
+	PermManageJobs Permission = "jobs.manage"
+	// PermManageWebhooks registers webhooks and reads their deliveries
+	PermManageWebhooks Permission = "webhooks.manage"
+	// PermReindexSearch rebuilds the search index
+	PermReindexSearch Permission = "search.reindex"
+	// PermManageSnapshots exports and restores whole-database snapshots
+	PermManageSnapshots Permission = "snapshots.manage"
+	// PermManageSandbox generates and purges synthetic data
+	PermManageSandbox Permission = "sandbox.manage"
+	// PermManageRetention views retention policy and runs
+	PermManageRetention Permission = "retention.manage"
+	// PermViewUsage lists the API keys and users making the most requests
+	PermViewUsage Permission = "usage.view"
+	// PermViewConfig shows the redacted server configuration
+	PermViewConfig Permission = "config.view"
+	// PermReviewVerifications lists organizations awaiting verification
+	PermReviewVerifications Permission = "verifications.review"
+	// PermViewAnalytics views analytics and exports across all organizations
+	PermViewAnalytics Permission = "analytics.view"
+	// PermImportVolunteers imports volunteers into any organization
+	PermImportVolunteers Permission = "volunteers.import"
+)
+
+// rolePermissions grants each platform role its permissions
+var rolePermissions = map[string][]Permission{
+	RoleVolunteer:   {PermViewProjectMatches},
+	RoleCoordinator: {PermViewVolunteerMatches},
+	RoleAdmin: {
+		PermViewVolunteerMatches, PermViewProjectMatches, PermRefreshMatching, PermAuditSessions, PermListUsers,
+		PermManageHolidays, PermManageRegions, PermModerate, PermModerateReviews, PermManageRoles,
+		PermManageDuplicates, PermManageJobs, PermManageWebhooks, PermReindexSearch,
+		PermManageSnapshots, PermManageSandbox, PermManageRetention, PermViewUsage, PermViewConfig,
+		PermReviewVerifications, PermViewAnalytics, PermImportVolunteers,
+	},
+}
+
+// HasPermission reports whether the platform role grants perm
+func HasPermission(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRole returns the user's current platform role
+func (s *Service) GetRole(userID string) (string, error) {
+	var role string
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(`SELECT role FROM users WHERE id::text = $1`, userID).Scan(&role)
+	})
+	if err == sql.ErrNoRows {
+		return "", ErrUserNotFound
+	}
+	return role, err
+}
+
+// Roles guards routes by the permissions of the signed-in user's platform
+// role. Roles are looked up on every request rather than read from the
+// token, so a changed role applies at once.
+type Roles struct {
+	roleOf func(userID string) (string, error)
+}
+
+func NewRoles(roleOf func(userID string) (string, error)) *Roles {
+	return &Roles{roleOf: roleOf}
+}
+
+// Require wraps next so it only runs for signed-in users whose role grants
+// perm. Others are refused with 401 when not signed in and 403 otherwise.
+func (ro *Roles) Require(perm Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := UserID(r)
+		if userID == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			apierror.Write(w, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+
+		role, err := ro.roleOf(userID)
+		if err != nil && err != ErrUserNotFound {
+			slog.Error("Role check error", "user", userID, "error", err)
+			apierror.Write(w, http.StatusInternalServerError, "Failed to check permissions")
+			return
+		}
+		if !HasPermission(role, perm) {
+			apierror.Write(w, http.StatusForbidden, "You do not have permission to do this")
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// RequireOr wraps next like Require, but also lets through requests allow
+// admits, such as admins of the request's organization on routes that serve
+// organizations as well as the platform
+func (ro *Roles) RequireOr(perm Permission, allow func(r *http.Request) (bool, error), next http.HandlerFunc) http.HandlerFunc {
+	guarded := ro.Require(perm, next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		allowed, err := allow(r)
+		if err != nil {
+			slog.Error("Access check error", "path", r.URL.Path, "error", err)
+			apierror.Write(w, http.StatusInternalServerError, "Failed to check permissions")
+			return
+		}
+		if allowed {
+			next(w, r)
+			return
+		}
+		guarded(w, r)
+	}
+}
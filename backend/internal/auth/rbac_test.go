@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireOr(t *testing.T) {
+	roles := NewRoles(func(userID string) (string, error) {
+		switch userID {
+		case "admin":
+			return RoleAdmin, nil
+		case "volunteer":
+			return RoleVolunteer, nil
+		}
+		return "", ErrUserNotFound
+	})
+	allowTenantAdmin := func(r *http.Request) (bool, error) {
+		if r.Header.Get("X-Test-Allow") == "error" {
+			return false, errors.New("lookup failed")
+		}
+		return r.Header.Get("X-Test-Allow") == "yes", nil
+	}
+
+	tests := []struct {
+		name   string
+		userID string
+		allow  string
+		want   int
+	}{
+		{"platform admin", "admin", "", http.StatusOK},
+		{"allowed without permission", "volunteer", "yes", http.StatusOK},
+		{"allowed without user", "", "yes", http.StatusOK},
+		{"neither", "volunteer", "", http.StatusForbidden},
+		{"not signed in", "", "", http.StatusUnauthorized},
+		{"allow error", "admin", "error", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := roles.RequireOr(PermViewAnalytics, allowTenantAdmin, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/api/admin/analytics/funnel", nil)
+			if tt.userID != "" {
+				r = r.WithContext(WithClaims(r.Context(), &Claims{UserID: tt.userID}))
+			}
+			if tt.allow != "" {
+				r.Header.Set("X-Test-Allow", tt.allow)
+			}
+			w := httptest.NewRecorder()
+			handler(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/notifications"
+)
+
+const (
+	// resetTTL is how long a password reset link works
+	resetTTL = time.Hour
+	// resetRequestInterval is how long a user waits between reset emails
+	resetRequestInterval = time.Minute
+)
+
+var (
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+	ErrResetRecentlySent = errors.New("a reset email was sent less than a minute ago")
+)
+
+// CreatePasswordReset issues a password reset token for the user with the
+// email address, replacing any earlier one, and returns the user with the
+// token. The raw token is only returned here; only its hash is stored.
+func (s *Service) CreatePasswordReset(email string) (*models.User, string, error) {
+	user, err := s.GetUserByEmail(email)
+	if err != nil {
+		return nil, "", err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+	token := hex.EncodeToString(b)
+
+	var inserted bool
+	err = database.WithWriteGuard(func() error {
+		// An earlier token is only replaced once the interval has passed
+		return s.db.QueryRow(`
+			INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (user_id) DO UPDATE
+			SET token_hash = EXCLUDED.token_hash, created_at = CURRENT_TIMESTAMP, expires_at = EXCLUDED.expires_at
+			WHERE password_reset_tokens.created_at < CURRENT_TIMESTAMP - $4 * INTERVAL '1 second'
+			RETURNING TRUE
+		`, user.ID, hashToken(token), time.Now().Add(resetTTL), resetRequestInterval.Seconds()).Scan(&inserted)
+	})
+	if err == sql.ErrNoRows {
+		return nil, "", ErrResetRecentlySent
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return user, token, nil
+}
+
+// ResetPassword sets a new password for the user the token was issued to,
+// using up the token, and returns the user. Receiving the token proves the
+// user owns their email address, so it also counts as verifying it.
+func (s *Service) ResetPassword(token, password string) (*models.User, error) {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	var userID string
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		err = tx.QueryRow(`
+			DELETE FROM password_reset_tokens
+			WHERE token_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+			RETURNING user_id
+		`, hashToken(token)).Scan(&userID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			UPDATE users
+			SET password_hash = $2, email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP),
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+		`, userID, hash)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM email_verification_tokens WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalidResetToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetUser(userID)
+}
+
+// PasswordResetMessage renders the email sending user their reset token.
+// resetBaseURL is the frontend page that takes the token as a query
+// parameter.
+func PasswordResetMessage(user *models.User, token, resetBaseURL string) notifications.Message {
+	return notifications.RenderPasswordReset(notifications.PasswordReset{
+		To:       user.Email,
+		Locale:   user.Locale,
+		Name:     user.Name,
+		ResetURL: resetBaseURL + "?token=" + url.QueryEscape(token),
+	})
+}
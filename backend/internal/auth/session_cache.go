@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// maxCachedSessions bounds the cache; when full, expired entries are
+// dropped, and everything if none have expired
+const maxCachedSessions = 10000
+
+type sessionEntry struct {
+	active    bool
+	expiresAt time.Time
+}
+
+// SessionCache remembers for a short while whether sessions are active, so
+// access tokens of ended sessions are refused without a query per request.
+// An ended session's tokens keep working until its entry expires.
+type SessionCache struct {
+	active func(sessionID string) (bool, error)
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[string]sessionEntry
+}
+
+// NewSessionCache looks sessions up with active and remembers the answer
+// for ttl; a ttl of 0 looks them up on every request
+func NewSessionCache(active func(sessionID string) (bool, error), ttl time.Duration) *SessionCache {
+	return &SessionCache{
+		active:  active,
+		ttl:     ttl,
+		entries: make(map[string]sessionEntry),
+	}
+}
+
+// Active reports whether the session has not been revoked
+func (c *SessionCache) Active(sessionID string) (bool, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[sessionID]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.active, nil
+	}
+
+	active, err := c.active(sessionID)
+	if err != nil {
+		return false, err
+	}
+	if c.ttl <= 0 {
+		return active, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCachedSessions {
+		for id, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, id)
+			}
+		}
+		if len(c.entries) >= maxCachedSessions {
+			c.entries = make(map[string]sessionEntry)
+		}
+	}
+	c.entries[sessionID] = sessionEntry{active: active, expiresAt: now.Add(c.ttl)}
+	return active, nil
+}
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+var (
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+)
+
+const sessionColumns = `id, user_id, user_agent, ip_address, created_at, last_used_at, expires_at, revoked_at`
+
+// CreateSession starts a session for the user signing in from userAgent
+// and ip, renewable with refreshToken until expiresAt
+func (s *Service) CreateSession(userID, userAgent, ip, refreshToken string, expiresAt time.Time) (*models.Session, error) {
+	var session models.Session
+	err := database.WithWriteGuard(func() error {
+		return scanSession(s.db.QueryRow(`
+			INSERT INTO auth_sessions (user_id, refresh_token_hash, user_agent, ip_address, expires_at)
+			VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5)
+			RETURNING `+sessionColumns,
+			userID, hashToken(refreshToken), userAgent, ip, expiresAt,
+		), &session)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// RefreshSession swaps the session's refreshToken for newToken, which is
+// valid until expiresAt, so each refresh token renews the session once.
+// Unknown, expired and revoked tokens get ErrInvalidRefreshToken.
+func (s *Service) RefreshSession(refreshToken, newToken string, expiresAt time.Time, ip string) (*models.Session, error) {
+	var session models.Session
+	err := database.WithWriteGuard(func() error {
+		return scanSession(s.db.QueryRow(`
+			UPDATE auth_sessions
+			SET refresh_token_hash = $2, expires_at = $3,
+			    ip_address = COALESCE(NULLIF($4, ''), ip_address), last_used_at = CURRENT_TIMESTAMP
+			WHERE refresh_token_hash = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+			RETURNING `+sessionColumns,
+			hashToken(refreshToken), hashToken(newToken), expiresAt, ip,
+		), &session)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetSessions lists the user's sessions, most recently used first. Unless
+// includeEnded is set, expired and revoked sessions are left out.
+func (s *Service) GetSessions(userID string, includeEnded bool) ([]models.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM auth_sessions
+		WHERE user_id = $1 AND ($2 OR (revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP))
+		ORDER BY last_used_at DESC
+	`
+
+	var sessions []models.Session
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, userID, includeEnded)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		sessions = []models.Session{}
+		for rows.Next() {
+			var session models.Session
+			if err := scanSession(rows, &session); err != nil {
+				return err
+			}
+			sessions = append(sessions, session)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// SessionActive reports whether the session exists and has not been revoked
+func (s *Service) SessionActive(sessionID string) (bool, error) {
+	var active bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(`
+			SELECT EXISTS (SELECT 1 FROM auth_sessions WHERE id::text = $1 AND revoked_at IS NULL)
+		`, sessionID).Scan(&active)
+	})
+	return active, err
+}
+
+// RevokeSession ends one of the user's sessions; its refresh token stops
+// working at once, and its access tokens once SessionCache notices
+func (s *Service) RevokeSession(userID, sessionID string) error {
+	var res sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		res, err = s.db.Exec(`
+			UPDATE auth_sessions SET revoked_at = CURRENT_TIMESTAMP
+			WHERE id::text = $2 AND user_id = $1 AND revoked_at IS NULL
+		`, userID, sessionID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeOtherSessions ends all of the user's sessions but keepSessionID,
+// which may be empty to end them all
+func (s *Service) RevokeOtherSessions(userID, keepSessionID string) error {
+	return database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(`
+			UPDATE auth_sessions SET revoked_at = CURRENT_TIMESTAMP
+			WHERE user_id = $1 AND id::text <> $2 AND revoked_at IS NULL
+		`, userID, keepSessionID)
+		return err
+	})
+}
+
+func scanSession(scanner interface{ Scan(...interface{}) error }, session *models.Session) error {
+	return scanner.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.CreatedAt,
+		&session.LastUsedAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+	)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
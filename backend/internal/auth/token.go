@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/models"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// tokenHeader is the encoded header of every token: HMAC-SHA256 JWTs
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims identify the user a token was issued to and the session it
+// belongs to
+type Claims struct {
+	UserID    string `json:"sub"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Tokens issues and verifies signed JWTs, and the opaque refresh tokens
+// that renew them
+type Tokens struct {
+	secret     []byte
+	ttl        time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokens signs tokens with secret; each is valid for ttl. Refresh tokens
+// are valid for refreshTTL after they are issued.
+func NewTokens(secret []byte, ttl, refreshTTL time.Duration) *Tokens {
+	return &Tokens{secret: secret, ttl: ttl, refreshTTL: refreshTTL}
+}
+
+// Issue returns a token for user in the session and when it expires
+func (t *Tokens) Issue(user *models.User, sessionID string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(t.ttl)
+	claims, err := json.Marshal(Claims{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		SessionID: sessionID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return unsigned + "." + t.sign(unsigned), expiresAt, nil
+}
+
+// Verify returns the claims of a token this service signed, or
+// ErrInvalidToken when it is malformed, signed otherwise or expired
+func (t *Tokens) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return nil, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(t.sign(parts[0]+"."+parts[1]))) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.UserID == "" {
+		return nil, ErrInvalidToken
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+// NewRefreshToken returns a random refresh token and when it expires. Only
+// its hash is stored.
+func (t *Tokens) NewRefreshToken() (string, time.Time, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
+	return hex.EncodeToString(b), time.Now().Add(t.refreshTTL), nil
+}
+
+func (t *Tokens) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/notifications"
+)
+
+const (
+	// verificationTTL is how long a verification link works
+	verificationTTL = 48 * time.Hour
+	// verificationResendInterval is how long a user waits between
+	// verification emails
+	verificationResendInterval = time.Minute
+)
+
+var (
+	ErrEmailAlreadyVerified     = errors.New("email address is already verified")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	ErrVerificationRecentlySent = errors.New("a verification email was sent less than a minute ago")
+)
+
+// CreateVerificationToken issues a verification token for the user,
+// replacing any earlier one, and returns it with when it expires. The raw
+// token is only returned here; only its hash is stored.
+func (s *Service) CreateVerificationToken(userID string) (string, time.Time, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(b)
+	expiresAt := time.Now().Add(verificationTTL)
+
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var verified bool
+		var lastSent sql.NullTime
+		err = tx.QueryRow(`
+			SELECT u.email_verified_at IS NOT NULL, t.created_at
+			FROM users u
+			LEFT JOIN email_verification_tokens t ON t.user_id = u.id
+			WHERE u.id = $1
+			FOR UPDATE OF u
+		`, userID).Scan(&verified, &lastSent)
+		if err == sql.ErrNoRows {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if verified {
+			return ErrEmailAlreadyVerified
+		}
+		if lastSent.Valid && time.Since(lastSent.Time) < verificationResendInterval {
+			return ErrVerificationRecentlySent
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO email_verification_tokens (user_id, token_hash, expires_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (user_id) DO UPDATE
+			SET token_hash = EXCLUDED.token_hash, created_at = CURRENT_TIMESTAMP, expires_at = EXCLUDED.expires_at
+		`, userID, hashToken(token), expiresAt)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// VerifyEmail marks the email address of the user the token was issued to
+// as verified, using up the token, and returns the user's ID
+func (s *Service) VerifyEmail(token string) (string, error) {
+	var userID string
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		err = tx.QueryRow(`
+			DELETE FROM email_verification_tokens
+			WHERE token_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+			RETURNING user_id
+		`, hashToken(token)).Scan(&userID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			UPDATE users SET email_verified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND email_verified_at IS NULL
+		`, userID)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err == sql.ErrNoRows {
+		return "", ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return "", err
+	}
+	return userID, nil
+}
+
+// IsEmailVerified reports whether the user has verified their email address
+func (s *Service) IsEmailVerified(userID string) (bool, error) {
+	var verified bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(`
+			SELECT EXISTS (SELECT 1 FROM users WHERE id::text = $1 AND email_verified_at IS NOT NULL)
+		`, userID).Scan(&verified)
+	})
+	return verified, err
+}
+
+// VerificationMessage renders the email sending user their verification
+// token. verifyBaseURL is the frontend page that takes the token as a query
+// parameter.
+func VerificationMessage(user *models.User, token, verifyBaseURL string, expiresAt time.Time) notifications.Message {
+	return notifications.RenderEmailVerification(notifications.EmailVerification{
+		To:        user.Email,
+		Locale:    user.Locale,
+		Name:      user.Name,
+		VerifyURL: verifyBaseURL + "?token=" + url.QueryEscape(token),
+		ExpiresAt: expiresAt,
+	})
+}
@@ -0,0 +1,199 @@
+package availability
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+const blackoutColumns = `id, volunteer_id, to_char(starts_on, 'YYYY-MM-DD'), to_char(ends_on, 'YYYY-MM-DD'), reason, created_at`
+
+func scanBlackout(scanner interface{ Scan(...interface{}) error }, b *models.AvailabilityBlackout) error {
+	return scanner.Scan(&b.ID, &b.VolunteerID, &b.StartsOn, &b.EndsOn, &b.Reason, &b.CreatedAt)
+}
+
+// GetBlackouts lists a volunteer's blackouts, earliest first
+func (s *Service) GetBlackouts(volunteerID string) ([]models.AvailabilityBlackout, error) {
+	blackouts := []models.AvailabilityBlackout{}
+	err := database.WithReadRetry(func() error {
+		blackouts = blackouts[:0]
+		rows, err := s.db.Query(`
+			SELECT `+blackoutColumns+`
+			FROM volunteer_availability_blackouts
+			WHERE volunteer_id = $1
+			ORDER BY starts_on, ends_on, id
+		`, volunteerID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var b models.AvailabilityBlackout
+			if err := scanBlackout(rows, &b); err != nil {
+				return err
+			}
+			blackouts = append(blackouts, b)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blackouts, nil
+}
+
+// CreateBlackout marks the volunteer away from StartsOn through EndsOn
+func (s *Service) CreateBlackout(volunteerID string, req models.CreateAvailabilityBlackoutRequest) (*models.AvailabilityBlackout, error) {
+	startsOn, startErr := time.Parse(dateLayout, req.StartsOn)
+	endsOn, endErr := time.Parse(dateLayout, req.EndsOn)
+	if startErr != nil || endErr != nil || endsOn.Before(startsOn) || endsOn.Sub(startsOn) > maxBlackoutDays*24*time.Hour {
+		return nil, ErrBlackoutRange
+	}
+
+	var blackout models.AvailabilityBlackout
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// Locking the volunteer serializes the blackout cap
+		var exists bool
+		err = tx.QueryRow(`SELECT true FROM users WHERE id = $1 FOR NO KEY UPDATE`, volunteerID).Scan(&exists)
+		if err == sql.ErrNoRows {
+			return ErrVolunteerNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM volunteer_availability_blackouts WHERE volunteer_id = $1`, volunteerID).Scan(&count); err != nil {
+			return err
+		}
+		if count >= maxBlackouts {
+			return ErrTooManyBlackouts
+		}
+
+		err = scanBlackout(tx.QueryRow(`
+			INSERT INTO volunteer_availability_blackouts (volunteer_id, starts_on, ends_on, reason)
+			VALUES ($1, $2, $3, $4)
+			RETURNING `+blackoutColumns,
+			volunteerID, req.StartsOn, req.EndsOn, req.Reason,
+		), &blackout)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &blackout, nil
+}
+
+// DeleteBlackout removes one of a volunteer's blackouts
+func (s *Service) DeleteBlackout(volunteerID, blackoutID string) error {
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(`DELETE FROM volunteer_availability_blackouts WHERE id = $1 AND volunteer_id = $2`, blackoutID, volunteerID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrBlackoutNotFound
+	}
+
+	return nil
+}
+
+// loadBlackouts returns the volunteers' blackouts overlapping [from, to) as
+// sorted [start, end) ranges, from midnight on their first day to midnight
+// after their last in each volunteer's zone
+func loadBlackouts(q Querier, volunteerIDs []string, from, to time.Time) (map[string][][2]time.Time, error) {
+	// Widen by a day either way for volunteers' zones
+	rows, err := q.Query(`
+		SELECT b.volunteer_id, to_char(b.starts_on, 'YYYY-MM-DD'), to_char(b.ends_on, 'YYYY-MM-DD'), u.timezone
+		FROM volunteer_availability_blackouts b
+		JOIN users u ON u.id = b.volunteer_id
+		WHERE b.volunteer_id = ANY($1::uuid[])
+		  AND b.ends_on >= $2::date AND b.starts_on <= $3::date
+		ORDER BY b.starts_on
+	`, pq.Array(volunteerIDs), from.AddDate(0, 0, -1).Format(dateLayout), to.AddDate(0, 0, 1).Format(dateLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blackouts := make(map[string][][2]time.Time)
+	for rows.Next() {
+		var volunteerID, startsOn, endsOn, timezone string
+		if err := rows.Scan(&volunteerID, &startsOn, &endsOn, &timezone); err != nil {
+			return nil, err
+		}
+		loc := loadLocation(timezone)
+		first, err := time.ParseInLocation(dateLayout, startsOn, loc)
+		if err != nil {
+			return nil, err
+		}
+		last, err := time.ParseInLocation(dateLayout, endsOn, loc)
+		if err != nil {
+			return nil, err
+		}
+		start, end := first.UTC(), last.AddDate(0, 0, 1).UTC()
+		if !end.After(from) || !start.Before(to) {
+			continue
+		}
+		blackouts[volunteerID] = append(blackouts[volunteerID], [2]time.Time{start, end})
+	}
+	return blackouts, rows.Err()
+}
+
+// subtract cuts the blackouts out of slots, splitting slots that span one,
+// and returns the rest sorted by start
+func subtract(slots []models.AvailabilitySlot, blackouts [][2]time.Time) []models.AvailabilitySlot {
+	if len(blackouts) == 0 {
+		return slots
+	}
+
+	kept := []models.AvailabilitySlot{}
+	for _, slot := range slots {
+		pieces := []models.AvailabilitySlot{slot}
+		for _, b := range blackouts {
+			var rest []models.AvailabilitySlot
+			for _, p := range pieces {
+				if !b[0].Before(p.End) || !b[1].After(p.Start) {
+					rest = append(rest, p)
+					continue
+				}
+				if p.Start.Before(b[0]) {
+					rest = append(rest, models.AvailabilitySlot{RuleID: p.RuleID, Start: p.Start, End: b[0]})
+				}
+				if p.End.After(b[1]) {
+					rest = append(rest, models.AvailabilitySlot{RuleID: p.RuleID, Start: b[1], End: p.End})
+				}
+			}
+			pieces = rest
+		}
+		kept = append(kept, pieces...)
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Start.Before(kept[j].Start) })
+	return kept
+}
@@ -0,0 +1,242 @@
+package availability
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRRule = errors.New("rrule must use FREQ=DAILY, WEEKLY or MONTHLY with optional INTERVAL, BYDAY, BYMONTHDAY and one of COUNT or UNTIL")
+
+const dateLayout = "2006-01-02"
+
+// maxExpansionDays bounds how far a rule is walked from its first day, so a
+// rule with COUNT far in the past cannot make expansion unbounded
+const maxExpansionDays = 10 * 366
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// byDay is a BYDAY entry; Ordinal is 0 for every such weekday, or the nth
+// (negative from the end) weekday of the month for MONTHLY rules
+type byDay struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+// Recurrence is the subset of RFC 5545 RRULE availability rules support
+type Recurrence struct {
+	Freq       string
+	Interval   int
+	ByDay      []byDay
+	ByMonthDay []int
+	Count      int
+	Until      *time.Time // civil date, inclusive
+}
+
+// ParseRRule parses rules such as FREQ=WEEKLY;BYDAY=SA or
+// FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20271231. An optional RRULE: prefix is
+// accepted.
+func ParseRRule(s string) (*Recurrence, error) {
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:")
+	r := &Recurrence{Interval: 1}
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" || seen[key] {
+			return nil, ErrInvalidRRule
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			if value != "DAILY" && value != "WEEKLY" && value != "MONTHLY" {
+				return nil, ErrInvalidRRule
+			}
+			r.Freq = value
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 366 {
+				return nil, ErrInvalidRRule
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, item := range strings.Split(value, ",") {
+				if len(item) < 2 {
+					return nil, ErrInvalidRRule
+				}
+				wd, ok := weekdays[item[len(item)-2:]]
+				if !ok {
+					return nil, ErrInvalidRRule
+				}
+				d := byDay{Weekday: wd}
+				if prefix := item[:len(item)-2]; prefix != "" {
+					n, err := strconv.Atoi(prefix)
+					if err != nil || n == 0 || n < -5 || n > 5 {
+						return nil, ErrInvalidRRule
+					}
+					d.Ordinal = n
+				}
+				r.ByDay = append(r.ByDay, d)
+			}
+		case "BYMONTHDAY":
+			for _, item := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(item)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, ErrInvalidRRule
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, ErrInvalidRRule
+			}
+			r.Count = n
+		case "UNTIL":
+			// Date-time UNTIL values are truncated to their date
+			if len(value) > 8 {
+				value = value[:8]
+			}
+			until, err := time.Parse("20060102", value)
+			if err != nil {
+				return nil, ErrInvalidRRule
+			}
+			r.Until = &until
+		default:
+			return nil, ErrInvalidRRule
+		}
+	}
+
+	if r.Freq == "" || (r.Count > 0 && r.Until != nil) {
+		return nil, ErrInvalidRRule
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq != "MONTHLY" {
+		return nil, ErrInvalidRRule
+	}
+	for _, d := range r.ByDay {
+		if d.Ordinal != 0 && r.Freq != "MONTHLY" {
+			return nil, ErrInvalidRRule
+		}
+	}
+	return r, nil
+}
+
+// Dates returns the civil dates (as UTC midnights) on which the rule occurs
+// between from and to inclusive, for a rule first occurring on or after
+// startsOn. COUNT counts occurrences from startsOn.
+func (r *Recurrence) Dates(startsOn, from, to time.Time) []time.Time {
+	startsOn = civil(startsOn)
+	from, to = civil(from), civil(to)
+	last := to
+	if r.Until != nil && r.Until.Before(last) {
+		last = *r.Until
+	}
+	if limit := startsOn.AddDate(0, 0, maxExpansionDays); limit.Before(last) {
+		last = limit
+	}
+
+	var dates []time.Time
+	count := 0
+	for day := startsOn; !day.After(last); day = day.AddDate(0, 0, 1) {
+		// Without COUNT there is nothing to tally before the window
+		if r.Count == 0 && day.Before(from) {
+			day = from.AddDate(0, 0, -1)
+			continue
+		}
+		if !r.matches(startsOn, day) {
+			continue
+		}
+		count++
+		if r.Count > 0 && count > r.Count {
+			break
+		}
+		if !day.Before(from) {
+			dates = append(dates, day)
+		}
+	}
+	return dates
+}
+
+func (r *Recurrence) matches(startsOn, day time.Time) bool {
+	switch r.Freq {
+	case "DAILY":
+		days := int(day.Sub(startsOn).Hours() / 24)
+		return days%r.Interval == 0 && r.matchesWeekday(day)
+	case "WEEKLY":
+		weeks := int(weekStart(day).Sub(weekStart(startsOn)).Hours() / (24 * 7))
+		if weeks%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == startsOn.Weekday()
+		}
+		return r.matchesWeekday(day)
+	case "MONTHLY":
+		months := (day.Year()-startsOn.Year())*12 + int(day.Month()) - int(startsOn.Month())
+		if months%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+			return day.Day() == startsOn.Day()
+		}
+		return r.matchesMonthDay(day) || r.matchesMonthWeekday(day)
+	}
+	return false
+}
+
+func (r *Recurrence) matchesWeekday(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d.Weekday == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Recurrence) matchesMonthDay(day time.Time) bool {
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, n := range r.ByMonthDay {
+		if n == day.Day() || (n < 0 && daysInMonth+n+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Recurrence) matchesMonthWeekday(day time.Time) bool {
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	nth := (day.Day()-1)/7 + 1
+	nthFromEnd := -((daysInMonth-day.Day())/7 + 1)
+	for _, d := range r.ByDay {
+		if d.Weekday != day.Weekday() {
+			continue
+		}
+		if d.Ordinal == 0 || d.Ordinal == nth || d.Ordinal == nthFromEnd {
+			return true
+		}
+	}
+	return false
+}
+
+// civil drops the time of day, keeping the calendar date as a UTC midnight
+func civil(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// weekStart returns the Monday starting day's week
+func weekStart(day time.Time) time.Time {
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
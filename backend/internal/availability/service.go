@@ -0,0 +1,633 @@
+package availability
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrRuleNotFound      = errors.New("availability rule not found")
+	ErrVolunteerNotFound = errors.New("volunteer not found")
+	ErrInvalidTime       = errors.New("startTime and endTime must be different HH:MM times")
+	ErrInvalidTimezone   = errors.New("timezone must be an IANA time zone such as America/Toronto")
+	ErrInvalidDate       = errors.New("startsOn and exceptDates must be YYYY-MM-DD dates")
+	ErrTooManyExceptions = errors.New("at most 366 exceptDates are allowed")
+	ErrTooManyRules      = errors.New("volunteers can have at most 50 availability rules")
+	ErrInvalidRange      = errors.New("from and to must be YYYY-MM-DD dates with from before to, at most 92 days apart")
+	ErrHolidayExists     = errors.New("a holiday already exists on this date")
+	ErrHolidayNotFound   = errors.New("holiday not found")
+	ErrHolidayName       = errors.New("holiday name is required and must be at most 100 characters")
+	ErrBlackoutNotFound  = errors.New("availability blackout not found")
+	ErrBlackoutRange     = errors.New("startsOn and endsOn must be YYYY-MM-DD dates with endsOn not before startsOn, at most 366 days apart")
+	ErrTooManyBlackouts  = errors.New("volunteers can have at most 100 blackouts")
+)
+
+const (
+	maxRulesPerVolunteer = 50
+	maxExceptDates       = 366
+	maxBlackouts         = 100
+	maxBlackoutDays      = 366
+	// MaxSlotRange caps how many days of slots one request expands
+	MaxSlotRange = 92
+	// matchHorizon is how far ahead matching looks for a project's windows
+	matchHorizon = 8 * 7 * 24 * time.Hour
+)
+
+const timeLayout = "15:04"
+
+// Querier is satisfied by *sql.DB and *sql.Tx so other services can check
+// availability inside their own transactions
+type Querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+const ruleColumns = `id, volunteer_id, rrule, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'),
+	timezone, to_char(starts_on, 'YYYY-MM-DD'), except_dates::text[], skip_holidays, created_at`
+
+func scanRule(scanner interface{ Scan(...interface{}) error }, r *models.AvailabilityRule) error {
+	return scanner.Scan(
+		&r.ID,
+		&r.VolunteerID,
+		&r.RRule,
+		&r.StartTime,
+		&r.EndTime,
+		&r.Timezone,
+		&r.StartsOn,
+		pq.Array(&r.ExceptDates),
+		&r.SkipHolidays,
+		&r.CreatedAt,
+	)
+}
+
+// GetRules lists a volunteer's availability rules
+func (s *Service) GetRules(volunteerID string) ([]models.AvailabilityRule, error) {
+	var rules []models.AvailabilityRule
+	err := database.WithReadRetry(func() error {
+		var err error
+		rules, err = loadRules(s.db, []string{volunteerID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []models.AvailabilityRule{}
+	}
+
+	return rules, nil
+}
+
+// CreateRule adds a recurring availability window for the volunteer. The
+// zone defaults to the volunteer's and the first day to today.
+func (s *Service) CreateRule(volunteerID string, req models.CreateAvailabilityRuleRequest) (*models.AvailabilityRule, error) {
+	if _, err := ParseRRule(req.RRule); err != nil {
+		return nil, err
+	}
+	start, startErr := time.Parse(timeLayout, req.StartTime)
+	end, endErr := time.Parse(timeLayout, req.EndTime)
+	if startErr != nil || endErr != nil || start.Equal(end) {
+		return nil, ErrInvalidTime
+	}
+	if req.Timezone != nil && !models.ValidTimezone(*req.Timezone) {
+		return nil, ErrInvalidTimezone
+	}
+	if req.StartsOn != nil {
+		if _, err := time.Parse(dateLayout, *req.StartsOn); err != nil {
+			return nil, ErrInvalidDate
+		}
+	}
+	if len(req.ExceptDates) > maxExceptDates {
+		return nil, ErrTooManyExceptions
+	}
+	for _, d := range req.ExceptDates {
+		if _, err := time.Parse(dateLayout, d); err != nil {
+			return nil, ErrInvalidDate
+		}
+	}
+	exceptDates := req.ExceptDates
+	if exceptDates == nil {
+		exceptDates = []string{}
+	}
+
+	var rule models.AvailabilityRule
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// Locking the volunteer serializes the rule cap
+		var timezone string
+		err = tx.QueryRow(`SELECT timezone FROM users WHERE id = $1 FOR NO KEY UPDATE`, volunteerID).Scan(&timezone)
+		if err == sql.ErrNoRows {
+			return ErrVolunteerNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if req.Timezone != nil {
+			timezone = *req.Timezone
+		}
+
+		startsOn := time.Now().In(loadLocation(timezone)).Format(dateLayout)
+		if req.StartsOn != nil {
+			startsOn = *req.StartsOn
+		}
+
+		var count int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM volunteer_availability_rules WHERE volunteer_id = $1`, volunteerID).Scan(&count); err != nil {
+			return err
+		}
+		if count >= maxRulesPerVolunteer {
+			return ErrTooManyRules
+		}
+
+		err = scanRule(tx.QueryRow(`
+			INSERT INTO volunteer_availability_rules (volunteer_id, rrule, start_time, end_time, timezone, starts_on, except_dates, skip_holidays)
+			VALUES ($1, $2, $3, $4, $5, $6, $7::date[], $8)
+			RETURNING `+ruleColumns,
+			volunteerID, req.RRule, req.StartTime, req.EndTime, timezone, startsOn, pq.Array(exceptDates), req.SkipHolidays,
+		), &rule)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// DeleteRule removes one of a volunteer's availability rules
+func (s *Service) DeleteRule(volunteerID, ruleID string) error {
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(`DELETE FROM volunteer_availability_rules WHERE id = $1 AND volunteer_id = $2`, ruleID, volunteerID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrRuleNotFound
+	}
+
+	return nil
+}
+
+// GetSlots expands the volunteer's rules into the windows overlapping
+// [from, to)
+func (s *Service) GetSlots(volunteerID string, from, to time.Time) ([]models.AvailabilitySlot, error) {
+	if !to.After(from) || to.Sub(from) > MaxSlotRange*24*time.Hour {
+		return nil, ErrInvalidRange
+	}
+
+	var slots map[string][]models.AvailabilitySlot
+	err := database.WithReadRetry(func() error {
+		var err error
+		slots, err = LoadSlots(s.db, []string{volunteerID}, from, to)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if slots[volunteerID] == nil {
+		return []models.AvailabilitySlot{}, nil
+	}
+	return slots[volunteerID], nil
+}
+
+// Match is how a volunteer's availability fits a project
+type Match struct {
+	Available bool
+	// Overlap is the share of the project's upcoming shifts the volunteer
+	// is free for, or for projects without shifts, of its days on which
+	// they are free at some time
+	Overlap float64
+}
+
+// MatchAvailability reports, for each volunteer with availability rules or
+// blackouts, whether they are free for the project within the next eight
+// weeks: for at least one whole upcoming shift or, for projects without
+// shifts, at any time between the project's start and end dates. Volunteers
+// without rules or blackouts and projects without shifts or dates are left
+// out.
+func (s *Service) MatchAvailability(projectID string, volunteerIDs []string) (map[string]Match, error) {
+	matches := map[string]Match{}
+	if len(volunteerIDs) == 0 {
+		return matches, nil
+	}
+
+	now := time.Now()
+	horizon := now.Add(matchHorizon)
+
+	var windows [][2]time.Time
+	var from, to time.Time
+	var ranged bool
+	err := database.WithReadRetry(func() error {
+		windows = nil
+		rows, err := s.db.Query(`
+			SELECT starts_at, ends_at
+			FROM project_shifts
+			WHERE project_id = $1 AND starts_at > $2 AND starts_at < $3
+			ORDER BY starts_at
+		`, projectID, now, horizon)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var w [2]time.Time
+			if err := rows.Scan(&w[0], &w[1]); err != nil {
+				return err
+			}
+			windows = append(windows, w)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(windows) > 0 {
+			return nil
+		}
+
+		var start, end *time.Time
+		err = s.db.QueryRow(`SELECT start_date, end_date FROM projects WHERE id = $1`, projectID).Scan(&start, &end)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil || start == nil {
+			return err
+		}
+		from, to = *start, horizon
+		if end != nil && end.Before(to) {
+			to = *end
+		}
+		if from.Before(now) {
+			from = now
+		}
+		ranged = to.After(from)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(windows) > 0 {
+		from, to = windows[0][0], windows[len(windows)-1][1]
+	} else if !ranged {
+		return matches, nil
+	}
+
+	var slots map[string][]models.AvailabilitySlot
+	err = database.WithReadRetry(func() error {
+		var err error
+		slots, err = LoadSlots(s.db, volunteerIDs, from, to)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for volunteerID, volunteerSlots := range slots {
+		if len(windows) == 0 {
+			overlap := dayOverlap(volunteerSlots, from, to)
+			matches[volunteerID] = Match{Available: len(volunteerSlots) > 0, Overlap: overlap}
+			continue
+		}
+		covered := 0
+		for _, w := range windows {
+			if Covers(volunteerSlots, w[0], w[1]) {
+				covered++
+			}
+		}
+		matches[volunteerID] = Match{Available: covered > 0, Overlap: float64(covered) / float64(len(windows))}
+	}
+
+	return matches, nil
+}
+
+// dayOverlap returns the share of the days from from to to, counted in
+// 24-hour steps, that slots sorted by start overlap
+func dayOverlap(slots []models.AvailabilitySlot, from, to time.Time) float64 {
+	days, free := 0, 0
+	i := 0
+	for day := from; day.Before(to); day = day.Add(24 * time.Hour) {
+		end := day.Add(24 * time.Hour)
+		if end.After(to) {
+			end = to
+		}
+		days++
+		for i < len(slots) && !slots[i].End.After(day) {
+			i++
+		}
+		if i < len(slots) && slots[i].Start.Before(end) {
+			free++
+		}
+	}
+	if days == 0 {
+		return 0
+	}
+	return float64(free) / float64(days)
+}
+
+// IsAvailable reports whether the volunteer's rules, less their blackouts,
+// cover all of [start, end). Volunteers without rules are available outside
+// their blackouts.
+func IsAvailable(q Querier, volunteerID string, start, end time.Time) (bool, error) {
+	slots, err := LoadSlots(q, []string{volunteerID}, start, end)
+	if err != nil {
+		return false, err
+	}
+	volunteerSlots, known := slots[volunteerID]
+	if !known {
+		return true, nil
+	}
+	return Covers(volunteerSlots, start, end), nil
+}
+
+// LoadSlots expands the volunteers' rules into the windows overlapping
+// [from, to), less their blackouts, sorted by start. Volunteers with
+// blackouts but no rules are free outside them. Only volunteers with rules
+// or blackouts have an entry.
+func LoadSlots(q Querier, volunteerIDs []string, from, to time.Time) (map[string][]models.AvailabilitySlot, error) {
+	rules, err := loadRules(q, volunteerIDs)
+	if err != nil {
+		return nil, err
+	}
+	blackouts, err := loadBlackouts(q, volunteerIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make(map[string][]models.AvailabilitySlot)
+	if len(rules) > 0 {
+		holidays, err := loadHolidays(q, from.AddDate(0, 0, -2), to.AddDate(0, 0, 2))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rule := range rules {
+			expanded, err := Expand(rule, holidays, from, to)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := slots[rule.VolunteerID]; !ok {
+				slots[rule.VolunteerID] = []models.AvailabilitySlot{}
+			}
+			slots[rule.VolunteerID] = append(slots[rule.VolunteerID], expanded...)
+		}
+	}
+	for volunteerID := range blackouts {
+		if _, ok := slots[volunteerID]; !ok {
+			slots[volunteerID] = []models.AvailabilitySlot{{Start: from.UTC(), End: to.UTC()}}
+		}
+	}
+	for volunteerID, list := range slots {
+		sort.Slice(list, func(i, j int) bool { return list[i].Start.Before(list[j].Start) })
+		slots[volunteerID] = subtract(list, blackouts[volunteerID])
+	}
+
+	return slots, nil
+}
+
+// Expand returns the rule's occurrences overlapping [from, to), leaving out
+// its exception dates and, when it skips holidays, the given holidays
+func Expand(rule models.AvailabilityRule, holidays map[string]bool, from, to time.Time) ([]models.AvailabilitySlot, error) {
+	rec, err := ParseRRule(rule.RRule)
+	if err != nil {
+		return nil, err
+	}
+	startsOn, err := time.Parse(dateLayout, rule.StartsOn)
+	if err != nil {
+		return nil, err
+	}
+	startTime, err := time.Parse(timeLayout, rule.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := time.Parse(timeLayout, rule.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	except := make(map[string]bool, len(rule.ExceptDates))
+	for _, d := range rule.ExceptDates {
+		except[d] = true
+	}
+
+	loc := loadLocation(rule.Timezone)
+	// Start a day early for windows running past midnight
+	firstDay := civil(from.In(loc)).AddDate(0, 0, -1)
+	lastDay := civil(to.In(loc))
+
+	slots := []models.AvailabilitySlot{}
+	for _, day := range rec.Dates(startsOn, firstDay, lastDay) {
+		key := day.Format(dateLayout)
+		if except[key] || (rule.SkipHolidays && holidays[key]) {
+			continue
+		}
+
+		start := time.Date(day.Year(), day.Month(), day.Day(), startTime.Hour(), startTime.Minute(), 0, 0, loc)
+		end := time.Date(day.Year(), day.Month(), day.Day(), endTime.Hour(), endTime.Minute(), 0, 0, loc)
+		if !end.After(start) {
+			end = time.Date(day.Year(), day.Month(), day.Day()+1, endTime.Hour(), endTime.Minute(), 0, 0, loc)
+		}
+		if !end.After(from) || !start.Before(to) {
+			continue
+		}
+		slots = append(slots, models.AvailabilitySlot{RuleID: rule.ID, Start: start.UTC(), End: end.UTC()})
+	}
+
+	return slots, nil
+}
+
+// Covers reports whether slots sorted by start cover all of [start, end),
+// treating touching or overlapping slots as one window
+func Covers(slots []models.AvailabilitySlot, start, end time.Time) bool {
+	covered := start
+	for _, slot := range slots {
+		if slot.Start.After(covered) {
+			if slot.Start.Before(end) {
+				return false
+			}
+			break
+		}
+		if slot.End.After(covered) {
+			covered = slot.End
+		}
+		if !covered.Before(end) {
+			return true
+		}
+	}
+	return !covered.Before(end)
+}
+
+func loadRules(q Querier, volunteerIDs []string) ([]models.AvailabilityRule, error) {
+	rows, err := q.Query(`
+		SELECT `+ruleColumns+`
+		FROM volunteer_availability_rules
+		WHERE volunteer_id = ANY($1::uuid[])
+		ORDER BY created_at, id
+	`, pq.Array(volunteerIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.AvailabilityRule
+	for rows.Next() {
+		var r models.AvailabilityRule
+		if err := scanRule(rows, &r); err != nil {
+			return nil, err
+		}
+		if r.ExceptDates == nil {
+			r.ExceptDates = []string{}
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+func loadHolidays(q Querier, from, to time.Time) (map[string]bool, error) {
+	rows, err := q.Query(`
+		SELECT to_char(holiday_date, 'YYYY-MM-DD')
+		FROM holidays
+		WHERE holiday_date BETWEEN $1::date AND $2::date
+	`, from.Format(dateLayout), to.Format(dateLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holidays := make(map[string]bool)
+	for rows.Next() {
+		var d string
+		if err := rows.Scan(&d); err != nil {
+			return nil, err
+		}
+		holidays[d] = true
+	}
+	return holidays, rows.Err()
+}
+
+func loadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// GetHolidays lists the holidays in the given year, or all holidays when
+// year is 0
+func (s *Service) GetHolidays(year int) ([]models.Holiday, error) {
+	var holidays []models.Holiday
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(`
+			SELECT to_char(holiday_date, 'YYYY-MM-DD'), name
+			FROM holidays
+			WHERE $1 = 0 OR EXTRACT(YEAR FROM holiday_date) = $1
+			ORDER BY holiday_date
+		`, year)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		holidays = []models.Holiday{}
+		for rows.Next() {
+			var h models.Holiday
+			if err := rows.Scan(&h.Date, &h.Name); err != nil {
+				return err
+			}
+			holidays = append(holidays, h)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return holidays, nil
+}
+
+// CreateHoliday adds a platform-wide holiday skipped by rules that opt out
+// of holidays
+func (s *Service) CreateHoliday(h models.Holiday) (*models.Holiday, error) {
+	if _, err := time.Parse(dateLayout, h.Date); err != nil {
+		return nil, ErrInvalidDate
+	}
+	if h.Name == "" || len(h.Name) > 100 {
+		return nil, ErrHolidayName
+	}
+
+	err := database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(`INSERT INTO holidays (holiday_date, name) VALUES ($1, $2)`, h.Date, h.Name)
+		return err
+	})
+	if isUniqueViolation(err) {
+		return nil, ErrHolidayExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &h, nil
+}
+
+// DeleteHoliday removes the holiday on date
+func (s *Service) DeleteHoliday(date string) error {
+	if _, err := time.Parse(dateLayout, date); err != nil {
+		return ErrInvalidDate
+	}
+
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(`DELETE FROM holidays WHERE holiday_date = $1`, date)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrHolidayNotFound
+	}
+
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
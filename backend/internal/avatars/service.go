@@ -0,0 +1,160 @@
+package avatars
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/blobstore"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/images"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/scanning"
+)
+
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrUnsupportedImage = errors.New("avatar must be a PNG, JPEG or GIF image")
+	ErrImageTooLarge    = errors.New("avatar must be at most 2 MB")
+	ErrImageDimensions  = errors.New("avatar must be at most 4096 pixels wide and high")
+)
+
+const (
+	// MaxBytes is the largest avatar accepted
+	MaxBytes     = 2 << 20
+	maxDimension = 4096
+)
+
+// extensions are the accepted image types by sniffed content type
+var extensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+}
+
+type Service struct {
+	db              *sql.DB
+	store           blobstore.Store
+	scanningService *scanning.Service
+}
+
+func NewService(db *sql.DB, store blobstore.Store, scanningService *scanning.Service) *Service {
+	return &Service{db: db, store: store, scanningService: scanningService}
+}
+
+// Upload stores a new avatar for the user and returns the URL it is served
+// from. The type is sniffed from the content rather than trusted from the
+// client, and the image header must decode. The previous avatar is deleted.
+// The new one is shown to others once it passes the malware scan, after
+// which its standard sizes are generated.
+func (s *Service) Upload(ctx context.Context, userID string, data []byte) (string, error) {
+	if len(data) > MaxBytes {
+		return "", ErrImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return "", ErrUnsupportedImage
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrUnsupportedImage
+	}
+	if config.Width > maxDimension || config.Height > maxDimension {
+		return "", ErrImageDimensions
+	}
+
+	var exists bool
+	err = database.WithReadRetry(func() error {
+		return s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM users WHERE id::text = $1)`, userID).Scan(&exists)
+	})
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", ErrUserNotFound
+	}
+
+	// A fresh key per upload lets clients cache avatars for good
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	key := "avatars/" + userID + "/" + hex.EncodeToString(suffix) + ext
+	if err := s.store.Put(ctx, key, contentType, data); err != nil {
+		return "", err
+	}
+	url := s.store.URL(key)
+
+	previous, err := s.setAvatar(userID, &key, &url)
+	if err != nil {
+		s.deleteBlob(ctx, key)
+		return "", err
+	}
+	if previous != nil {
+		s.DeleteFiles(ctx, *previous)
+	}
+	s.scanningService.Wake()
+
+	return url, nil
+}
+
+// Remove clears the user's avatar and deletes its files
+func (s *Service) Remove(ctx context.Context, userID string) error {
+	previous, err := s.setAvatar(userID, nil, nil)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		s.DeleteFiles(ctx, *previous)
+	}
+	return nil
+}
+
+// setAvatar points the user at a new avatar, returning the key of the one
+// it replaces. The new avatar waits for the malware scan.
+func (s *Service) setAvatar(userID string, key, url *string) (*string, error) {
+	var previous *string
+	err := database.WithWriteGuard(func() error {
+		return s.db.QueryRow(`
+			UPDATE users u
+			SET avatar_key = $2, avatar_url = $3, avatar_variants = NULL, avatar_variant_attempts = 0,
+			    avatar_scan_status = 'pending', updated_at = CURRENT_TIMESTAMP
+			FROM (SELECT id, avatar_key FROM users WHERE id::text = $1 FOR UPDATE) old
+			WHERE u.id = old.id
+			RETURNING old.avatar_key
+		`, userID, key, url).Scan(&previous)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+// DeleteFiles removes an avatar no user refers to any more, along with its
+// variants
+func (s *Service) DeleteFiles(ctx context.Context, key string) {
+	s.deleteBlob(ctx, key)
+	for _, variantKey := range images.VariantKeys(key, images.AvatarVariants) {
+		s.deleteBlob(ctx, variantKey)
+	}
+}
+
+// deleteBlob removes a file that is no longer referenced; failures only
+// leave an orphaned file behind, so they are logged
+func (s *Service) deleteBlob(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		logging.FromContext(ctx).Error("Delete avatar error", "key", key, "error", err)
+	}
+}
@@ -0,0 +1,179 @@
+package badges
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+// Metrics badges are earned on
+const (
+	metricProjects       = "projects"        // projects enrolled in
+	metricHours          = "hours"           // hours logged
+	metricVerifiedSkills = "verified_skills" // claimed skills a coordinator verified
+)
+
+// Definition is a badge earned once a volunteer's metric reaches Threshold
+type Definition struct {
+	ID          string
+	Name        string
+	Description string
+	Metric      string
+	Threshold   float64
+}
+
+// Definitions are the badges volunteers can earn. IDs are stored with
+// awards, so they must not change.
+var Definitions = []Definition{
+	{ID: "first_project", Name: "First Project", Description: "Joined a first project", Metric: metricProjects, Threshold: 1},
+	{ID: "ten_hours", Name: "10 Hours", Description: "Logged 10 volunteer hours", Metric: metricHours, Threshold: 10},
+	{ID: "five_verified_skills", Name: "Verified Skills", Description: "Had 5 skills verified by coordinators", Metric: metricVerifiedSkills, Threshold: 5},
+}
+
+func definition(id string) (Definition, bool) {
+	for _, d := range Definitions {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// GetBadges lists the badges a volunteer has earned, oldest first
+func (s *Service) GetBadges(volunteerID string) ([]models.VolunteerBadge, error) {
+	query := `
+		SELECT badge, awarded_at
+		FROM volunteer_badges
+		WHERE volunteer_id = $1
+		ORDER BY awarded_at, badge
+	`
+
+	var badges []models.VolunteerBadge
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, volunteerID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		badges = []models.VolunteerBadge{}
+		for rows.Next() {
+			var b models.VolunteerBadge
+			if err := rows.Scan(&b.Badge, &b.AwardedAt); err != nil {
+				return err
+			}
+			// Skip awards for badges that were since retired
+			d, ok := definition(b.Badge)
+			if !ok {
+				continue
+			}
+			b.Name, b.Description = d.Name, d.Description
+			badges = append(badges, b)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return badges, nil
+}
+
+// Award grants the volunteer every badge they have earned but not yet
+// received, or checks every volunteer when volunteerID is empty. Awards are
+// idempotent, so instances handling the same event don't double up.
+func (s *Service) Award(volunteerID string) ([]models.VolunteerBadge, error) {
+	query := `
+		WITH metrics AS (
+			SELECT u.id AS volunteer_id,
+			       (SELECT COUNT(*) FROM volunteer_enrollments ve
+			        WHERE ve.volunteer_id = u.id AND ve.status IN ('enrolled', 'completed')) AS projects,
+			       (SELECT COALESCE(SUM(vh.hours), 0) FROM volunteer_hours vh
+			        WHERE vh.volunteer_id = u.id) AS hours,
+			       (SELECT COUNT(*) FROM volunteer_skills vs
+			        WHERE vs.volunteer_id = u.id AND vs.claimed AND vs.verified_at IS NOT NULL) AS verified_skills
+			FROM users u
+			WHERE $1 = '' OR u.id = NULLIF($1, '')::uuid
+		)
+		INSERT INTO volunteer_badges (volunteer_id, badge)
+		SELECT m.volunteer_id, d.badge
+		FROM metrics m
+		CROSS JOIN unnest($2::text[], $3::text[], $4::numeric[]) AS d(badge, metric, threshold)
+		WHERE CASE d.metric
+		          WHEN 'projects' THEN m.projects
+		          WHEN 'hours' THEN m.hours
+		          WHEN 'verified_skills' THEN m.verified_skills
+		      END >= d.threshold
+		ON CONFLICT (volunteer_id, badge) DO NOTHING
+		RETURNING badge, awarded_at
+	`
+
+	ids := make([]string, len(Definitions))
+	metrics := make([]string, len(Definitions))
+	thresholds := make([]float64, len(Definitions))
+	for i, d := range Definitions {
+		ids[i], metrics[i], thresholds[i] = d.ID, d.Metric, d.Threshold
+	}
+
+	var awarded []models.VolunteerBadge
+	err := database.WithWriteGuard(func() error {
+		rows, err := s.db.Query(query, volunteerID, pq.Array(ids), pq.Array(metrics), pq.Array(thresholds))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var b models.VolunteerBadge
+			if err := rows.Scan(&b.Badge, &b.AwardedAt); err != nil {
+				return err
+			}
+			d, _ := definition(b.Badge)
+			b.Name, b.Description = d.Name, d.Description
+			awarded = append(awarded, b)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return awarded, nil
+}
+
+// HandleActivity consumes payloads from database.VolunteerActivityChannel,
+// awarding badges to the volunteer named in each. An empty payload means
+// events may have been missed, so every volunteer is checked.
+func (s *Service) HandleActivity(payload string) {
+	var event struct {
+		Table       string `json:"table"`
+		VolunteerID string `json:"volunteerId"`
+	}
+	if payload != "" {
+		if err := json.Unmarshal([]byte(payload), &event); err != nil || event.VolunteerID == "" {
+			slog.Warn("Ignoring malformed volunteer activity payload", "payload", payload)
+			return
+		}
+	}
+
+	awarded, err := s.Award(event.VolunteerID)
+	if err != nil {
+		slog.Error("Award badges error", "volunteer", event.VolunteerID, "error", err)
+		return
+	}
+	for _, b := range awarded {
+		slog.Info("Awarded badge", "badge", b.Badge, "volunteer", event.VolunteerID, "table", event.Table)
+	}
+}
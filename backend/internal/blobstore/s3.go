@@ -0,0 +1,209 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Store keeps files in an S3 bucket, or any S3-compatible service when
+// Endpoint is set. Requests are signed with AWS Signature Version 4.
+type S3Store struct {
+	Bucket string
+	Prefix string
+	Region string
+	// Endpoint addresses an S3-compatible service with path-style URLs,
+	// e.g. http://minio:9000; empty means AWS
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	PublicURL       string
+	Client          *http.Client
+}
+
+// S3Settings are the credentials, region and endpoint S3 stores use
+type S3Settings struct {
+	Region          string
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+func NewS3Store(bucket, prefix, publicURL string, settings S3Settings) *S3Store {
+	region := settings.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3Store{
+		Bucket:          bucket,
+		Prefix:          prefix,
+		Region:          region,
+		Endpoint:        strings.TrimSuffix(settings.Endpoint, "/"),
+		AccessKeyID:     settings.AccessKeyID,
+		SecretAccessKey: settings.SecretAccessKey,
+		SessionToken:    settings.SessionToken,
+		PublicURL:       publicURL,
+		Client:          http.DefaultClient,
+	}
+}
+
+func (s *S3Store) object(key string) string {
+	if s.Prefix != "" {
+		return s.Prefix + "/" + key
+	}
+	return key
+}
+
+// objectURL is the bucket endpoint's URL for the object stored under key
+func (s *S3Store) objectURL(key string) string {
+	escaped := escapePath(s.object(key))
+	if s.Endpoint != "" {
+		return s.Endpoint + "/" + s.Bucket + "/" + escaped
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.Bucket, s.Region, escaped)
+}
+
+func (s *S3Store) Put(ctx context.Context, key, contentType string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	return s.do(req, data, key)
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, nil)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("GET %s: %s: %s", s.object(key), resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req, nil, key)
+}
+
+func (s *S3Store) URL(key string) string {
+	if s.PublicURL != "" {
+		return s.PublicURL + "/" + escapePath(s.object(key))
+	}
+	return s.objectURL(key)
+}
+
+func (s *S3Store) do(req *http.Request, payload []byte, key string) error {
+	s.sign(req, payload)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: %s: %s", req.Method, s.object(key), resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// sign adds AWS Signature Version 4 headers to the request
+func (s *S3Store) sign(req *http.Request, payload []byte) {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// escapePath escapes each segment of an object name for use in a URL
+func escapePath(name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,127 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrInvalidDestination = errors.New("blob store must be a directory path, file:// URL or s3://bucket/prefix URL")
+	ErrNotFound           = errors.New("blob not found")
+)
+
+// Store keeps uploaded files such as avatars and serves them from a URL
+type Store interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL is where clients fetch the object stored under key
+	URL(key string) string
+}
+
+// ParseDestination returns the store for a destination: a local directory
+// (or file:// URL) or an s3://bucket/prefix URL. Objects are served from
+// publicURL when set; otherwise local files are served by the API under
+// LocalPath and S3 objects from the bucket's own endpoint.
+func ParseDestination(dest, publicURL string, s3 S3Settings) (Store, error) {
+	publicURL = strings.TrimSuffix(publicURL, "/")
+	switch {
+	case strings.HasPrefix(dest, "s3://"):
+		u, err := url.Parse(dest)
+		if err != nil || u.Host == "" {
+			return nil, ErrInvalidDestination
+		}
+		return NewS3Store(u.Host, strings.Trim(u.Path, "/"), publicURL, s3), nil
+	case strings.HasPrefix(dest, "file://"):
+		return &DirStore{Dir: strings.TrimPrefix(dest, "file://"), PublicURL: publicURL}, nil
+	case dest != "" && !strings.Contains(dest, "://"):
+		return &DirStore{Dir: dest, PublicURL: publicURL}, nil
+	}
+	return nil, ErrInvalidDestination
+}
+
+// LocalPath is where the API serves files kept in a DirStore
+const LocalPath = "/uploads/"
+
+// DirStore keeps files under a local directory and serves them itself
+type DirStore struct {
+	Dir       string
+	PublicURL string
+}
+
+func (d *DirStore) path(key string) string {
+	return filepath.Join(d.Dir, filepath.FromSlash(path.Clean("/"+key)))
+}
+
+func (d *DirStore) Put(ctx context.Context, key, contentType string, data []byte) error {
+	p := d.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+func (d *DirStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(d.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (d *DirStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(d.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (d *DirStore) URL(key string) string {
+	base := d.PublicURL
+	if base == "" {
+		base = strings.TrimSuffix(LocalPath, "/")
+	}
+	return base + "/" + key
+}
+
+// ServeHTTP serves stored files by key, mounted under LocalPath. Keys are
+// never reused, so files are cached for good; directories are not listed.
+func (d *DirStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f, err := os.Open(d.path(strings.TrimPrefix(r.URL.Path, LocalPath)))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+}
@@ -0,0 +1,93 @@
+package calendar
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event is one VEVENT in a feed. End may be zero for events without a known
+// end.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+}
+
+const icsTimeLayout = "20060102T150405Z"
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding
+const maxLineOctets = 75
+
+// WriteICS writes the events as an iCalendar (RFC 5545) feed named name
+func WriteICS(w io.Writer, name string, events []Event, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) {
+		writeFolded(bw, s)
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Civic Weave//Volunteer Calendar//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeText(name))
+
+	stamp := now.UTC().Format(icsTimeLayout)
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + stamp)
+		line("DTSTART:" + e.Start.UTC().Format(icsTimeLayout))
+		if !e.End.IsZero() {
+			line("DTEND:" + e.End.UTC().Format(icsTimeLayout))
+		}
+		line("SUMMARY:" + escapeText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escapeText(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION:" + escapeText(e.Location))
+		}
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// escapeText escapes a TEXT property value
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// writeFolded writes a content line, folding it onto continuation lines of
+// at most 75 octets without splitting UTF-8 sequences
+func writeFolded(w *bufio.Writer, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines spend one octet on the leading space
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,179 @@
+package calendar
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+)
+
+var (
+	ErrInvalidToken      = errors.New("invalid calendar token")
+	ErrTokenNotFound     = errors.New("calendar feed is not enabled")
+	ErrVolunteerNotFound = errors.New("volunteer not found")
+)
+
+// pastWindow is how far back finished commitments stay in the feed
+const pastWindow = 30 * 24 * time.Hour
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// RotateToken issues a new feed token for the volunteer, invalidating any
+// previous one. The raw token is only returned here; only its hash is
+// stored.
+func (s *Service) RotateToken(volunteerID string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	query := `
+		INSERT INTO calendar_feed_tokens (volunteer_id, token_hash)
+		SELECT id, $2 FROM users WHERE id = $1
+		ON CONFLICT (volunteer_id) DO UPDATE
+		SET token_hash = EXCLUDED.token_hash,
+		    created_at = CURRENT_TIMESTAMP,
+		    last_used_at = NULL
+	`
+
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(query, volunteerID, hashToken(token))
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+	if rowsAffected == 0 {
+		return "", ErrVolunteerNotFound
+	}
+
+	return token, nil
+}
+
+// RevokeToken disables the volunteer's feed
+func (s *Service) RevokeToken(volunteerID string) error {
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(`DELETE FROM calendar_feed_tokens WHERE volunteer_id = $1`, volunteerID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrTokenNotFound
+	}
+
+	return nil
+}
+
+// Authenticate checks token against the volunteer's current feed token
+func (s *Service) Authenticate(volunteerID, token string) error {
+	var stored string
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(`SELECT token_hash FROM calendar_feed_tokens WHERE volunteer_id = $1`, volunteerID).Scan(&stored)
+	})
+	if err == sql.ErrNoRows {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(hashToken(token))) != 1 {
+		return ErrInvalidToken
+	}
+
+	// Recording use is best effort; feeds are polled often
+	database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(`UPDATE calendar_feed_tokens SET last_used_at = CURRENT_TIMESTAMP WHERE volunteer_id = $1`, volunteerID)
+		return err
+	})
+	return nil
+}
+
+// GetCommitments lists the volunteer's enrolled projects that have a start
+// date and the shifts they are booked onto, leaving out those that ended
+// more than 30 days ago. The feed is personal, so it spans all
+// organizations.
+func (s *Service) GetCommitments(volunteerID string) ([]Event, error) {
+	query := `
+		SELECT 'project-' || ve.id, p.name, p.description, COALESCE(p.location_name, ''),
+		       p.start_date, p.end_date
+		FROM volunteer_enrollments ve
+		JOIN projects p ON p.id = ve.project_id
+		WHERE ve.volunteer_id = $1
+		  AND ve.status = 'enrolled'
+		  AND p.start_date IS NOT NULL
+		  AND COALESCE(p.end_date, p.start_date) > $2
+		UNION ALL
+		SELECT 'shift-' || s.id || '-' || ss.volunteer_id,
+		       COALESCE(s.title, p.name || ' shift'), 'Shift for ' || p.name, COALESCE(p.location_name, ''),
+		       s.starts_at, s.ends_at
+		FROM shift_signups ss
+		JOIN project_shifts s ON s.id = ss.shift_id
+		JOIN projects p ON p.id = s.project_id
+		WHERE ss.volunteer_id = $1
+		  AND s.ends_at > $2
+		ORDER BY 5
+	`
+
+	since := time.Now().Add(-pastWindow)
+	var events []Event
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, volunteerID, since)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		events = nil
+		for rows.Next() {
+			var e Event
+			var end *time.Time
+			if err := rows.Scan(&e.UID, &e.Summary, &e.Description, &e.Location, &e.Start, &end); err != nil {
+				return err
+			}
+			e.UID += "@civicweave"
+			if end != nil {
+				e.End = *end
+			}
+			events = append(events, e)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
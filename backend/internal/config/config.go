@@ -0,0 +1,649 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/cron"
+)
+
+// FileEnv names the environment variable pointing at an optional YAML
+// configuration file
+const FileEnv = "CONFIG_FILE"
+
+// Config is every setting the API server reads at startup. Each field is
+// set from its environment variable when present, then from the YAML file
+// at its dotted path, then from its default. Fields tagged secret are
+// redacted from Redacted.
+type Config struct {
+	Server     Server     `yaml:"server"`
+	Logging    Logging    `yaml:"logging"`
+	Metrics    Metrics    `yaml:"metrics"`
+	Tracing    Tracing    `yaml:"tracing"`
+	Database   Database   `yaml:"database"`
+	Auth       Auth       `yaml:"auth"`
+	OIDC       OIDC       `yaml:"oidc"`
+	Tenant     Tenant     `yaml:"tenant"`
+	Features   Features   `yaml:"features"`
+	Storage    Storage    `yaml:"storage"`
+	S3         S3         `yaml:"s3"`
+	Scanning   Scanning   `yaml:"scanning"`
+	SMTP       SMTP       `yaml:"smtp"`
+	Email      Email      `yaml:"email"`
+	Redis      Redis      `yaml:"redis"`
+	Jobs       Jobs       `yaml:"jobs"`
+	Schedule   Schedule   `yaml:"schedule"`
+	Search     Search     `yaml:"search"`
+	Matching   Matching   `yaml:"matching"`
+	Warehouse  Warehouse  `yaml:"warehouse"`
+	Engagement Engagement `yaml:"engagement"`
+	Demo       Demo       `yaml:"demo"`
+	Sandbox    Sandbox    `yaml:"sandbox"`
+	Quotas     Quotas     `yaml:"quotas"`
+	RateLimit  RateLimit  `yaml:"rateLimit"`
+	Retention  Retention  `yaml:"retention"`
+}
+
+type Server struct {
+	Port             int           `yaml:"port" env:"PORT" default:"8080"`
+	ReadTimeout      time.Duration `yaml:"readTimeout" env:"READ_TIMEOUT" default:"15s"`
+	WriteTimeout     time.Duration `yaml:"writeTimeout" env:"WRITE_TIMEOUT" default:"15s"`
+	IdleTimeout      time.Duration `yaml:"idleTimeout" env:"IDLE_TIMEOUT" default:"60s"`
+	ShutdownTimeout  time.Duration `yaml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT" default:"30s"`
+	CORSOrigins      []string      `yaml:"corsOrigins" env:"CORS_ALLOWED_ORIGINS" default:"*"`
+	InviteAcceptURL  string        `yaml:"inviteAcceptUrl" env:"INVITE_ACCEPT_URL" default:"http://localhost:3000/invitations/accept"`
+	VerifyEmailURL   string        `yaml:"verifyEmailUrl" env:"VERIFY_EMAIL_URL" default:"http://localhost:3000/verify-email"`
+	ResetPasswordURL string        `yaml:"resetPasswordUrl" env:"RESET_PASSWORD_URL" default:"http://localhost:3000/reset-password"`
+	TrustedProxies   []string      `yaml:"trustedProxies" env:"TRUSTED_PROXIES"`
+}
+
+// Logging sets how logs are written to stderr
+type Logging struct {
+	// Level is the least severe level logged: debug, info, warn or error
+	Level  string `yaml:"level" env:"LOG_LEVEL" default:"info"`
+	Format string `yaml:"format" env:"LOG_FORMAT" default:"json"`
+}
+
+// Metrics exposes Prometheus metrics at /metrics
+type Metrics struct {
+	Enabled bool `yaml:"enabled" env:"METRICS_ENABLED" default:"true"`
+	// Token, when set, must be sent by scrapers as a bearer token
+	Token string `yaml:"token" env:"METRICS_TOKEN" secret:"true"`
+}
+
+// Tracing exports OpenTelemetry spans to an OTLP/HTTP collector. The
+// variables are the standard OpenTelemetry ones.
+type Tracing struct {
+	// Endpoint is the collector base URL; tracing is off without one
+	Endpoint string `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	// Headers are key=value pairs sent with each export
+	Headers     []string `yaml:"headers" env:"OTEL_EXPORTER_OTLP_HEADERS" secret:"true"`
+	ServiceName string   `yaml:"serviceName" env:"OTEL_SERVICE_NAME" default:"civic-weave-api"`
+	// SampleRatio is the share of new traces recorded, from 0 to 1
+	SampleRatio float64 `yaml:"sampleRatio" env:"OTEL_TRACES_SAMPLER_ARG" default:"1"`
+}
+
+type Database struct {
+	Host     string `yaml:"host" env:"DB_HOST" default:"localhost"`
+	Port     int    `yaml:"port" env:"DB_PORT" default:"5432"`
+	User     string `yaml:"user" env:"DB_USER" default:"postgres"`
+	Password string `yaml:"password" env:"DB_PASSWORD" default:"postgres" secret:"true"`
+	Name     string `yaml:"name" env:"DB_NAME" default:"civic_weave"`
+	// AutoMigrate applies pending migrations at startup; without it the
+	// server refuses to start until they are applied with `api migrate`
+	AutoMigrate bool `yaml:"autoMigrate" env:"DB_AUTO_MIGRATE" default:"true"`
+}
+
+// Auth configures sign-in. Access tokens are JWTs signed with JWTSecret,
+// renewed with a session's refresh token until it expires.
+type Auth struct {
+	JWTSecret  string        `yaml:"jwtSecret" env:"JWT_SECRET" secret:"true"`
+	TokenTTL   time.Duration `yaml:"tokenTtl" env:"AUTH_TOKEN_TTL" default:"15m"`
+	RefreshTTL time.Duration `yaml:"refreshTtl" env:"AUTH_REFRESH_TTL" default:"720h"`
+	// SessionCheckTTL is how long whether a session is still active is
+	// remembered, so ended sessions stop their access tokens within it
+	SessionCheckTTL time.Duration `yaml:"sessionCheckTtl" env:"AUTH_SESSION_CHECK_TTL" default:"30s"`
+	// DefaultUserPassword is the password of the test users created at
+	// startup; without one they cannot sign in
+	DefaultUserPassword string `yaml:"defaultUserPassword" env:"DEFAULT_USER_PASSWORD" secret:"true"`
+}
+
+// OIDC configures signing in with external OpenID Connect providers. A
+// provider is offered once its client ID is set.
+type OIDC struct {
+	// CallbackBaseURL is where providers send users back to, followed by
+	// /{provider}/callback; register that URL with each provider
+	CallbackBaseURL string `yaml:"callbackBaseUrl" env:"OIDC_CALLBACK_BASE_URL" default:"http://localhost:8080/api/auth/oauth"`
+	// RedirectURL is the frontend page that finishes signing in
+	RedirectURL           string `yaml:"redirectUrl" env:"OIDC_REDIRECT_URL" default:"http://localhost:3000/oauth/callback"`
+	GoogleClientID        string `yaml:"googleClientId" env:"OIDC_GOOGLE_CLIENT_ID"`
+	GoogleClientSecret    string `yaml:"googleClientSecret" env:"OIDC_GOOGLE_CLIENT_SECRET" secret:"true"`
+	MicrosoftClientID     string `yaml:"microsoftClientId" env:"OIDC_MICROSOFT_CLIENT_ID"`
+	MicrosoftClientSecret string `yaml:"microsoftClientSecret" env:"OIDC_MICROSOFT_CLIENT_SECRET" secret:"true"`
+	MicrosoftTenant       string `yaml:"microsoftTenant" env:"OIDC_MICROSOFT_TENANT" default:"common"`
+	// MicrosoftTrustEmails counts Microsoft email addresses as verified,
+	// which Microsoft does not say; only set it for a single tenant you run
+	MicrosoftTrustEmails bool `yaml:"microsoftTrustEmails" env:"OIDC_MICROSOFT_TRUST_EMAILS" default:"false"`
+	// The custom provider is any other OpenID Connect provider, such as an
+	// organization's own identity server
+	CustomName         string `yaml:"customName" env:"OIDC_CUSTOM_NAME" default:"sso"`
+	CustomIssuerURL    string `yaml:"customIssuerUrl" env:"OIDC_CUSTOM_ISSUER_URL"`
+	CustomClientID     string `yaml:"customClientId" env:"OIDC_CUSTOM_CLIENT_ID"`
+	CustomClientSecret string `yaml:"customClientSecret" env:"OIDC_CUSTOM_CLIENT_SECRET" secret:"true"`
+}
+
+type Tenant struct {
+	BaseDomain string `yaml:"baseDomain" env:"TENANT_BASE_DOMAIN"`
+}
+
+// Features switch optional parts of the API on or off
+type Features struct {
+	Leaderboards   bool `yaml:"leaderboards" env:"FEATURE_LEADERBOARDS" default:"true"`
+	Reviews        bool `yaml:"reviews" env:"FEATURE_REVIEWS" default:"true"`
+	PublicProfiles bool `yaml:"publicProfiles" env:"FEATURE_PUBLIC_PROFILES" default:"true"`
+	ClientEvents   bool `yaml:"clientEvents" env:"FEATURE_CLIENT_EVENTS" default:"true"`
+}
+
+type Storage struct {
+	BlobStore       string `yaml:"blobStore" env:"BLOB_STORE" default:"./uploads"`
+	BlobPublicURL   string `yaml:"blobPublicUrl" env:"BLOB_PUBLIC_URL"`
+	DocumentStore   string `yaml:"documentStore" env:"DOCUMENT_STORE" default:"./documents"`
+	DocumentSecret  string `yaml:"documentUrlSecret" env:"DOCUMENT_URL_SECRET" secret:"true"`
+	QuarantineStore string `yaml:"quarantineStore" env:"QUARANTINE_STORE" default:"./quarantine"`
+}
+
+type S3 struct {
+	Region          string `yaml:"region" env:"AWS_REGION" default:"us-east-1"`
+	Endpoint        string `yaml:"endpoint" env:"S3_ENDPOINT"`
+	AccessKeyID     string `yaml:"accessKeyId" env:"AWS_ACCESS_KEY_ID" secret:"true"`
+	SecretAccessKey string `yaml:"secretAccessKey" env:"AWS_SECRET_ACCESS_KEY" secret:"true"`
+	SessionToken    string `yaml:"sessionToken" env:"AWS_SESSION_TOKEN" secret:"true"`
+}
+
+type Scanning struct {
+	Scanner          string `yaml:"scanner" env:"UPLOAD_SCANNER"`
+	VirusTotalAPIKey string `yaml:"virusTotalApiKey" env:"VIRUSTOTAL_API_KEY" secret:"true"`
+	VirusTotalUpload bool   `yaml:"virusTotalUpload" env:"VIRUSTOTAL_UPLOAD" default:"false"`
+}
+
+// SMTP configures email delivery; without a host emails are only logged
+type SMTP struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" env:"SMTP_PORT" default:"587"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD" secret:"true"`
+	From     string `yaml:"from" env:"SMTP_FROM"`
+}
+
+// Email picks how email is delivered: smtp, sendgrid, or log to only log
+// it. When unset, SMTP is used if SMTP_HOST is set and email is logged
+// otherwise.
+type Email struct {
+	Provider       string `yaml:"provider" env:"EMAIL_PROVIDER"`
+	SendGridAPIKey string `yaml:"sendGridApiKey" env:"SENDGRID_API_KEY" secret:"true"`
+	SendGridFrom   string `yaml:"sendGridFrom" env:"SENDGRID_FROM"`
+}
+
+// Redis is shared between instances; rate limiting can keep its buckets
+// there
+type Redis struct {
+	URL string `yaml:"url" env:"REDIS_URL" secret:"true"`
+}
+
+// Jobs sizes the background job worker pool
+type Jobs struct {
+	Workers      int           `yaml:"workers" env:"JOB_WORKERS" default:"4"`
+	PollInterval time.Duration `yaml:"pollInterval" env:"JOB_POLL_INTERVAL" default:"5s"`
+}
+
+// ScheduleOff turns a scheduled task off
+const ScheduleOff = "off"
+
+// Schedule sets when recurring maintenance runs, as cron expressions
+// matched in UTC, or off
+type Schedule struct {
+	RefreshSkillVectors string        `yaml:"refreshSkillVectors" env:"SCHEDULE_REFRESH_SKILL_VECTORS" default:"0 * * * *"`
+	RecomputeMatches    string        `yaml:"recomputeMatches" env:"SCHEDULE_RECOMPUTE_MATCHES" default:"30 4 * * *"`
+	ExpireEnrollments   string        `yaml:"expireEnrollments" env:"SCHEDULE_EXPIRE_ENROLLMENTS" default:"0 3 * * *"`
+	RetireProjects      string        `yaml:"retireProjects" env:"SCHEDULE_RETIRE_PROJECTS" default:"30 3 * * *"`
+	CoordinatorDigests  string        `yaml:"coordinatorDigests" env:"SCHEDULE_COORDINATOR_DIGESTS" default:"0 13 * * mon"`
+	ReindexSearch       string        `yaml:"reindexSearch" env:"SCHEDULE_REINDEX_SEARCH" default:"0 4 * * *"`
+	Retention           string        `yaml:"retention" env:"SCHEDULE_RETENTION" default:"0 2 * * *"`
+	DetectDuplicates    string        `yaml:"detectDuplicates" env:"SCHEDULE_DETECT_DUPLICATES" default:"0 5 * * *"`
+	PurgeIdempotency    string        `yaml:"purgeIdempotency" env:"SCHEDULE_PURGE_IDEMPOTENCY" default:"15 * * * *"`
+	RemindInvitations   string        `yaml:"remindInvitations" env:"SCHEDULE_REMIND_INVITATIONS" default:"0 9 * * *"`
+	EnrollmentExpiry    time.Duration `yaml:"enrollmentExpiry" env:"ENROLLMENT_EXPIRY" default:"720h"`
+	InvitationExpiry    time.Duration `yaml:"invitationExpiry" env:"INVITATION_EXPIRY" default:"720h"`
+	// InvitationReminder is how long before an invitation expires the
+	// volunteer is reminded of it; 0 turns reminders off
+	InvitationReminder time.Duration `yaml:"invitationReminder" env:"INVITATION_REMINDER" default:"72h"`
+	ProjectRetireAfter time.Duration `yaml:"projectRetireAfter" env:"PROJECT_RETIRE_AFTER" default:"720h"`
+	IdempotencyKeyTTL  time.Duration `yaml:"idempotencyKeyTtl" env:"IDEMPOTENCY_KEY_TTL" default:"24h"`
+}
+
+// Search mirrors projects into OpenSearch or Elasticsearch for full-text
+// search; without a URL project search is off
+type Search struct {
+	URL      string `yaml:"url" env:"SEARCH_URL"`
+	Index    string `yaml:"index" env:"SEARCH_INDEX" default:"projects"`
+	Username string `yaml:"username" env:"SEARCH_USERNAME"`
+	Password string `yaml:"password" env:"SEARCH_PASSWORD" secret:"true"`
+}
+
+// Matching sets the parameters of match searches that leave them unset.
+// Project searches use the organization's own weights and distance first.
+type Matching struct {
+	SkillWeight    float64 `yaml:"skillWeight" env:"MATCH_SKILL_WEIGHT" default:"0.7"`
+	DistanceWeight float64 `yaml:"distanceWeight" env:"MATCH_DISTANCE_WEIGHT" default:"0.3"`
+	MaxDistanceKm  float64 `yaml:"maxDistanceKm" env:"MATCH_MAX_DISTANCE_KM" default:"100"`
+	// DistanceDecay is how distance lowers the distance score: linear,
+	// exponential or step
+	DistanceDecay string `yaml:"distanceDecay" env:"MATCH_DISTANCE_DECAY" default:"linear"`
+	Limit         int    `yaml:"limit" env:"MATCH_LIMIT" default:"20"`
+	// CacheTTL is how long match results are cached; 0 turns caching off
+	CacheTTL time.Duration `yaml:"cacheTtl" env:"MATCH_CACHE_TTL" default:"5m"`
+	// NotifyScore is the combined score at which volunteers are notified
+	// of a newly published project they match; 0 turns these off
+	NotifyScore float64 `yaml:"notifyScore" env:"MATCH_NOTIFY_SCORE" default:"0.75"`
+}
+
+type Warehouse struct {
+	Dest     string        `yaml:"dest" env:"WAREHOUSE_EXPORT_DEST"`
+	Interval time.Duration `yaml:"interval" env:"WAREHOUSE_EXPORT_INTERVAL" default:"24h"`
+}
+
+type Engagement struct {
+	ReviewBlockedTerms []string `yaml:"reviewBlockedTerms" env:"REVIEW_BLOCKED_TERMS"`
+	EventSampleRate    float64  `yaml:"eventSampleRate" env:"EVENT_SAMPLE_RATE" default:"1"`
+	HourMilestones     string   `yaml:"hourMilestones" env:"HOUR_MILESTONES" default:"25,100,500"`
+}
+
+// Demo holds settings for demo deployments
+type Demo struct {
+	// AllowRestore lets platform admins replace the whole database with a
+	// snapshot; leave it off anywhere the data matters
+	AllowRestore bool `yaml:"allowRestore" env:"DEMO_ALLOW_RESTORE" default:"false"`
+}
+
+// Sandbox fills the database with synthetic volunteers, projects and
+// enrollment histories at startup, for load testing and demos
+type Sandbox struct {
+	Enabled bool `yaml:"enabled" env:"SANDBOX" default:"false"`
+	// Seed makes generation repeatable: the same seed and volumes give the
+	// same data, down to the IDs
+	Seed                    int `yaml:"seed" env:"SANDBOX_SEED" default:"1"`
+	Volunteers              int `yaml:"volunteers" env:"SANDBOX_VOLUNTEERS" default:"500"`
+	Coordinators            int `yaml:"coordinators" env:"SANDBOX_COORDINATORS" default:"10"`
+	Projects                int `yaml:"projects" env:"SANDBOX_PROJECTS" default:"40"`
+	EnrollmentsPerVolunteer int `yaml:"enrollmentsPerVolunteer" env:"SANDBOX_ENROLLMENTS_PER_VOLUNTEER" default:"3"`
+}
+
+// Quotas cap how many API requests each API key and user may make per UTC
+// day and calendar month; 0 means unlimited. Keys may override the key
+// quotas.
+type Quotas struct {
+	APIKeyDaily   int `yaml:"apiKeyDaily" env:"API_KEY_DAILY_QUOTA" default:"10000"`
+	APIKeyMonthly int `yaml:"apiKeyMonthly" env:"API_KEY_MONTHLY_QUOTA" default:"200000"`
+	UserDaily     int `yaml:"userDaily" env:"USER_DAILY_QUOTA" default:"0"`
+	UserMonthly   int `yaml:"userMonthly" env:"USER_MONTHLY_QUOTA" default:"0"`
+}
+
+// RateLimit throttles bursts: sign-ins and other auth requests per client
+// IP, and reads and writes per user, API key or anonymous client IP. Each
+// allows its requests per Window; 0 turns a limit off. Buckets are kept in
+// memory, per instance, or in Redis, shared by every instance.
+type RateLimit struct {
+	Store         string        `yaml:"store" env:"RATE_LIMIT_STORE" default:"memory"`
+	Window        time.Duration `yaml:"window" env:"RATE_LIMIT_WINDOW" default:"1m"`
+	AuthRequests  int           `yaml:"authRequests" env:"RATE_LIMIT_AUTH" default:"10"`
+	ReadRequests  int           `yaml:"readRequests" env:"RATE_LIMIT_READ" default:"300"`
+	WriteRequests int           `yaml:"writeRequests" env:"RATE_LIMIT_WRITE" default:"60"`
+}
+
+// Retention says how long personal data is kept before the retention task
+// anonymizes or deletes it; 0 keeps it forever. A dry run only reports
+// what would be purged.
+type Retention struct {
+	DryRun                 bool          `yaml:"dryRun" env:"RETENTION_DRY_RUN" default:"true"`
+	AnonymizeInactiveAfter time.Duration `yaml:"anonymizeInactiveAfter" env:"RETENTION_ANONYMIZE_INACTIVE_AFTER" default:"26280h"`
+	AuthEvents             time.Duration `yaml:"authEvents" env:"RETENTION_AUTH_EVENTS" default:"8760h"`
+	ExpiredInvitations     time.Duration `yaml:"expiredInvitations" env:"RETENTION_EXPIRED_INVITATIONS" default:"720h"`
+	EndedSessions          time.Duration `yaml:"endedSessions" env:"RETENTION_ENDED_SESSIONS" default:"720h"`
+	ClientEvents           time.Duration `yaml:"clientEvents" env:"RETENTION_CLIENT_EVENTS" default:"0"`
+}
+
+// Load reads the configuration from the environment and the YAML file named
+// by CONFIG_FILE, if any, and validates it. Every problem found is
+// reported, not just the first.
+func Load() (*Config, error) {
+	file := map[string]yamlValue{}
+	if path := os.Getenv(FileEnv); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", FileEnv, err)
+		}
+		file, err = parseYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+
+	var cfg Config
+	var errs []error
+	walk(reflect.ValueOf(&cfg).Elem(), "", func(f field) {
+		if err := f.load(file); err != nil {
+			errs = append(errs, err)
+		}
+		delete(file, f.path)
+	})
+	for path := range file {
+		errs = append(errs, fmt.Errorf("%s: unknown setting", path))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks settings that parse but can't work
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validPort(c.Server.Port), "PORT must be between 1 and 65535")
+	check(validPort(c.Database.Port), "DB_PORT must be between 1 and 65535")
+	check(c.Server.ReadTimeout > 0, "READ_TIMEOUT must be positive")
+	check(c.Server.WriteTimeout > 0, "WRITE_TIMEOUT must be positive")
+	check(c.Server.IdleTimeout > 0, "IDLE_TIMEOUT must be positive")
+	check(c.Server.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be positive")
+	check(len(c.Server.CORSOrigins) > 0, "CORS_ALLOWED_ORIGINS must list at least one origin or *")
+	for _, origin := range c.Server.CORSOrigins {
+		check(origin == "*" || validURL(origin, "http", "https"), "CORS_ALLOWED_ORIGINS: %q is not * or an http(s) origin", origin)
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		check(validProxy(proxy), "TRUSTED_PROXIES: %q is not an IP address or CIDR", proxy)
+	}
+	check(validURL(c.Server.InviteAcceptURL, "http", "https"), "INVITE_ACCEPT_URL must be an http(s) URL")
+	check(validURL(c.Server.VerifyEmailURL, "http", "https"), "VERIFY_EMAIL_URL must be an http(s) URL")
+	check(validURL(c.Server.ResetPasswordURL, "http", "https"), "RESET_PASSWORD_URL must be an http(s) URL")
+
+	var level slog.Level
+	check(level.UnmarshalText([]byte(c.Logging.Level)) == nil, "LOG_LEVEL must be debug, info, warn or error")
+	check(c.Logging.Format == "json" || c.Logging.Format == "text", "LOG_FORMAT must be json or text")
+
+	check(c.Tracing.Endpoint == "" || validURL(c.Tracing.Endpoint, "http", "https"), "OTEL_EXPORTER_OTLP_ENDPOINT must be an http(s) URL")
+	for _, header := range c.Tracing.Headers {
+		key, _, ok := strings.Cut(header, "=")
+		check(ok && strings.TrimSpace(key) != "", "OTEL_EXPORTER_OTLP_HEADERS entries must be key=value")
+	}
+	check(c.Tracing.ServiceName != "", "OTEL_SERVICE_NAME is required")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "OTEL_TRACES_SAMPLER_ARG must be between 0 and 1")
+
+	check(c.Database.Host != "", "DB_HOST is required")
+	check(c.Database.Name != "", "DB_NAME is required")
+
+	check(c.Auth.JWTSecret == "" || len(c.Auth.JWTSecret) >= 32, "JWT_SECRET must be at least 32 bytes")
+	check(c.Auth.TokenTTL > 0, "AUTH_TOKEN_TTL must be positive")
+	check(c.Auth.RefreshTTL >= c.Auth.TokenTTL, "AUTH_REFRESH_TTL must be at least AUTH_TOKEN_TTL")
+	check(c.Auth.SessionCheckTTL >= 0, "AUTH_SESSION_CHECK_TTL must not be negative")
+	check(c.Auth.DefaultUserPassword == "" || len(c.Auth.DefaultUserPassword) >= 8, "DEFAULT_USER_PASSWORD must be at least 8 characters")
+
+	check(validURL(c.OIDC.CallbackBaseURL, "http", "https"), "OIDC_CALLBACK_BASE_URL must be an http(s) URL")
+	check(validURL(c.OIDC.RedirectURL, "http", "https"), "OIDC_REDIRECT_URL must be an http(s) URL")
+	check(c.OIDC.GoogleClientID == "" || c.OIDC.GoogleClientSecret != "", "OIDC_GOOGLE_CLIENT_SECRET is required with OIDC_GOOGLE_CLIENT_ID")
+	if c.OIDC.MicrosoftClientID != "" {
+		check(c.OIDC.MicrosoftClientSecret != "", "OIDC_MICROSOFT_CLIENT_SECRET is required with OIDC_MICROSOFT_CLIENT_ID")
+		check(c.OIDC.MicrosoftTenant != "" && !strings.ContainsAny(c.OIDC.MicrosoftTenant, "/?#"), "OIDC_MICROSOFT_TENANT must be a tenant ID, domain, common or organizations")
+	}
+	if c.OIDC.CustomClientID != "" {
+		check(c.OIDC.CustomClientSecret != "", "OIDC_CUSTOM_CLIENT_SECRET is required with OIDC_CUSTOM_CLIENT_ID")
+		check(validURL(c.OIDC.CustomIssuerURL, "https"), "OIDC_CUSTOM_ISSUER_URL must be an https URL")
+		check(c.OIDC.CustomName != "" && strings.Trim(c.OIDC.CustomName, "abcdefghijklmnopqrstuvwxyz0123456789-") == "",
+			"OIDC_CUSTOM_NAME must be lowercase letters, digits and hyphens")
+		check(c.OIDC.CustomName != "google" && c.OIDC.CustomName != "microsoft", "OIDC_CUSTOM_NAME must differ from the built-in providers")
+	}
+
+	check(c.Storage.DocumentStore != c.Storage.BlobStore, "DOCUMENT_STORE must differ from BLOB_STORE, which is served publicly")
+	check(c.Storage.QuarantineStore != c.Storage.BlobStore && c.Storage.QuarantineStore != c.Storage.DocumentStore,
+		"QUARANTINE_STORE must differ from BLOB_STORE and DOCUMENT_STORE")
+	check(c.Storage.BlobPublicURL == "" || validURL(c.Storage.BlobPublicURL, "http", "https"), "BLOB_PUBLIC_URL must be an http(s) URL")
+	check(c.S3.Endpoint == "" || validURL(c.S3.Endpoint, "http", "https"), "S3_ENDPOINT must be an http(s) URL")
+
+	check(c.Scanning.Scanner != "virustotal" || c.Scanning.VirusTotalAPIKey != "", "VIRUSTOTAL_API_KEY is required to scan with VirusTotal")
+
+	if c.SMTP.Host != "" {
+		check(validPort(c.SMTP.Port), "SMTP_PORT must be between 1 and 65535")
+		check(strings.Contains(c.SMTP.From, "@"), "SMTP_FROM must be an email address when SMTP_HOST is set")
+	}
+	switch c.Email.Provider {
+	case "", "log":
+	case "smtp":
+		check(c.SMTP.Host != "", "SMTP_HOST is required with EMAIL_PROVIDER=smtp")
+	case "sendgrid":
+		check(c.Email.SendGridAPIKey != "", "SENDGRID_API_KEY is required with EMAIL_PROVIDER=sendgrid")
+		check(strings.Contains(c.Email.SendGridFrom, "@"), "SENDGRID_FROM must be an email address with EMAIL_PROVIDER=sendgrid")
+	default:
+		check(false, "EMAIL_PROVIDER must be smtp, sendgrid or log")
+	}
+	check(c.Redis.URL == "" || validURL(c.Redis.URL, "redis", "rediss"), "REDIS_URL must be a redis:// or rediss:// URL")
+
+	check(c.Jobs.Workers > 0, "JOB_WORKERS must be positive")
+	check(c.Jobs.PollInterval > 0, "JOB_POLL_INTERVAL must be positive")
+	for _, s := range []struct{ env, spec string }{
+		{"SCHEDULE_REFRESH_SKILL_VECTORS", c.Schedule.RefreshSkillVectors},
+		{"SCHEDULE_RECOMPUTE_MATCHES", c.Schedule.RecomputeMatches},
+		{"SCHEDULE_EXPIRE_ENROLLMENTS", c.Schedule.ExpireEnrollments},
+		{"SCHEDULE_RETIRE_PROJECTS", c.Schedule.RetireProjects},
+		{"SCHEDULE_COORDINATOR_DIGESTS", c.Schedule.CoordinatorDigests},
+		{"SCHEDULE_REINDEX_SEARCH", c.Schedule.ReindexSearch},
+		{"SCHEDULE_DETECT_DUPLICATES", c.Schedule.DetectDuplicates},
+		{"SCHEDULE_RETENTION", c.Schedule.Retention},
+		{"SCHEDULE_PURGE_IDEMPOTENCY", c.Schedule.PurgeIdempotency},
+		{"SCHEDULE_REMIND_INVITATIONS", c.Schedule.RemindInvitations},
+	} {
+		if s.spec != ScheduleOff {
+			_, err := cron.Parse(s.spec)
+			check(err == nil, "%s: %v", s.env, err)
+		}
+	}
+	check(c.Schedule.EnrollmentExpiry > 0, "ENROLLMENT_EXPIRY must be positive")
+	check(c.Schedule.InvitationExpiry > 0, "INVITATION_EXPIRY must be positive")
+	check(c.Schedule.InvitationReminder >= 0 && c.Schedule.InvitationReminder < c.Schedule.InvitationExpiry,
+		"INVITATION_REMINDER must not be negative and must be shorter than INVITATION_EXPIRY")
+	check(c.Schedule.ProjectRetireAfter >= 0, "PROJECT_RETIRE_AFTER must not be negative")
+	check(c.Schedule.IdempotencyKeyTTL > 0, "IDEMPOTENCY_KEY_TTL must be positive")
+	check(c.Search.URL == "" || validURL(c.Search.URL, "http", "https"), "SEARCH_URL must be an http(s) URL")
+	check(c.Search.Index != "" && c.Search.Index == strings.ToLower(c.Search.Index) && !strings.ContainsAny(c.Search.Index, `/\*?"<>| ,#`),
+		"SEARCH_INDEX must be a lowercase index name")
+	check(c.Matching.SkillWeight >= 0 && c.Matching.DistanceWeight >= 0 && c.Matching.SkillWeight+c.Matching.DistanceWeight > 0,
+		"MATCH_SKILL_WEIGHT and MATCH_DISTANCE_WEIGHT must not be negative or both 0")
+	check(c.Matching.MaxDistanceKm > 0, "MATCH_MAX_DISTANCE_KM must be positive")
+	check(c.Matching.DistanceDecay == "linear" || c.Matching.DistanceDecay == "exponential" || c.Matching.DistanceDecay == "step",
+		"MATCH_DISTANCE_DECAY must be linear, exponential or step")
+	check(c.Matching.Limit > 0 && c.Matching.Limit <= 100, "MATCH_LIMIT must be between 1 and 100")
+	check(c.Matching.CacheTTL >= 0, "MATCH_CACHE_TTL must not be negative")
+	check(c.Matching.NotifyScore >= 0 && c.Matching.NotifyScore <= 1, "MATCH_NOTIFY_SCORE must be between 0 and 1")
+	check(c.Warehouse.Interval > 0, "WAREHOUSE_EXPORT_INTERVAL must be positive")
+	check(c.Sandbox.Volunteers >= 0 && c.Sandbox.Volunteers <= 1000000, "SANDBOX_VOLUNTEERS must be between 0 and 1000000")
+	check(c.Sandbox.Coordinators >= 1 && c.Sandbox.Coordinators <= 10000, "SANDBOX_COORDINATORS must be between 1 and 10000")
+	check(c.Sandbox.Projects >= 0 && c.Sandbox.Projects <= 100000, "SANDBOX_PROJECTS must be between 0 and 100000")
+	check(c.Sandbox.EnrollmentsPerVolunteer >= 0 && c.Sandbox.EnrollmentsPerVolunteer <= 50, "SANDBOX_ENROLLMENTS_PER_VOLUNTEER must be between 0 and 50")
+	check(c.Quotas.APIKeyDaily >= 0, "API_KEY_DAILY_QUOTA must not be negative")
+	check(c.Quotas.APIKeyMonthly >= 0, "API_KEY_MONTHLY_QUOTA must not be negative")
+	check(c.Quotas.UserDaily >= 0, "USER_DAILY_QUOTA must not be negative")
+	check(c.Quotas.UserMonthly >= 0, "USER_MONTHLY_QUOTA must not be negative")
+	check(c.RateLimit.Store == "memory" || c.RateLimit.Store == "redis", "RATE_LIMIT_STORE must be memory or redis")
+	check(c.RateLimit.Store != "redis" || c.Redis.URL != "", "REDIS_URL is required with RATE_LIMIT_STORE=redis")
+	check(c.RateLimit.Window > 0, "RATE_LIMIT_WINDOW must be positive")
+	check(c.RateLimit.AuthRequests >= 0, "RATE_LIMIT_AUTH must not be negative")
+	check(c.RateLimit.ReadRequests >= 0, "RATE_LIMIT_READ must not be negative")
+	check(c.RateLimit.WriteRequests >= 0, "RATE_LIMIT_WRITE must not be negative")
+	check(c.Retention.AnonymizeInactiveAfter >= 0, "RETENTION_ANONYMIZE_INACTIVE_AFTER must not be negative")
+	check(c.Retention.AuthEvents >= 0, "RETENTION_AUTH_EVENTS must not be negative")
+	check(c.Retention.ExpiredInvitations >= 0, "RETENTION_EXPIRED_INVITATIONS must not be negative")
+	check(c.Retention.EndedSessions >= 0, "RETENTION_ENDED_SESSIONS must not be negative")
+	check(c.Retention.ClientEvents >= 0, "RETENTION_CLIENT_EVENTS must not be negative")
+	check(c.Engagement.EventSampleRate > 0 && c.Engagement.EventSampleRate <= 1, "EVENT_SAMPLE_RATE must be greater than 0 and at most 1")
+
+	return errors.Join(errs...)
+}
+
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+func validProxy(proxy string) bool {
+	if _, _, err := net.ParseCIDR(proxy); err == nil {
+		return true
+	}
+	return net.ParseIP(proxy) != nil
+}
+
+func validURL(raw string, schemes ...string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// field is one setting found while walking the Config struct
+type field struct {
+	value  reflect.Value
+	path   string
+	env    string
+	def    string
+	secret bool
+}
+
+// walk calls fn for every setting below v, naming each by its dotted YAML
+// path
+func walk(v reflect.Value, prefix string, fn func(field)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		path := prefix + sf.Tag.Get("yaml")
+		if sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(time.Duration(0)) {
+			walk(v.Field(i), path+".", fn)
+			continue
+		}
+		fn(field{
+			value:  v.Field(i),
+			path:   path,
+			env:    sf.Tag.Get("env"),
+			def:    sf.Tag.Get("default"),
+			secret: sf.Tag.Get("secret") == "true",
+		})
+	}
+}
+
+// load sets the field from its environment variable, the file or its
+// default, in that order
+func (f field) load(file map[string]yamlValue) error {
+	if raw, ok := os.LookupEnv(f.env); ok && raw != "" {
+		if err := f.set(raw, nil); err != nil {
+			return fmt.Errorf("%s: %w", f.env, err)
+		}
+		return nil
+	}
+	if v, ok := file[f.path]; ok {
+		if v.isList && f.value.Kind() != reflect.Slice {
+			return fmt.Errorf("%s: expected a single value, not a list", f.path)
+		}
+		if err := f.set(v.scalar, v.list); err != nil {
+			return fmt.Errorf("%s: %w", f.path, err)
+		}
+		return nil
+	}
+	return f.set(f.def, nil)
+}
+
+// set parses raw into the field. Lists come from a YAML list, or from a
+// comma-separated string.
+func (f field) set(raw string, list []string) error {
+	switch f.value.Interface().(type) {
+	case string:
+		f.value.SetString(raw)
+	case int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", raw)
+		}
+		f.value.SetInt(int64(n))
+	case float64:
+		x, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		f.value.SetFloat(x)
+	case bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", raw)
+		}
+		f.value.SetBool(b)
+	case time.Duration:
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%q is not a duration such as 30s or 24h", raw)
+		}
+		f.value.SetInt(int64(d))
+	case []string:
+		if list == nil {
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+		}
+		f.value.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("unsupported setting type %s", f.value.Type())
+	}
+	return nil
+}
+
+// Redacted returns the settings by YAML section and name, with secrets
+// that are set masked, for showing to administrators
+func (c *Config) Redacted() map[string]map[string]interface{} {
+	out := map[string]map[string]interface{}{}
+	walk(reflect.ValueOf(c).Elem(), "", func(f field) {
+		section, name, _ := strings.Cut(f.path, ".")
+		if out[section] == nil {
+			out[section] = map[string]interface{}{}
+		}
+		var v interface{}
+		switch val := f.value.Interface().(type) {
+		case time.Duration:
+			v = val.String()
+		default:
+			v = val
+		}
+		if f.secret && !f.value.IsZero() {
+			v = "[redacted]"
+		}
+		out[section][name] = v
+	})
+	return out
+}
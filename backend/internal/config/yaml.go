@@ -0,0 +1,169 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlValue is a setting read from the configuration file: a scalar, or a
+// list of scalars
+type yamlValue struct {
+	scalar string
+	list   []string
+	isList bool
+}
+
+// parseYAML reads the subset of YAML configuration files need: nested
+// mappings, scalars, quoted strings, comments, and lists written either
+// inline as [a, b] or as "- item" lines. Settings are returned by their
+// dotted path, e.g. server.port.
+func parseYAML(data []byte) (map[string]yamlValue, error) {
+	out := map[string]yamlValue{}
+
+	type level struct {
+		indent int
+		path   string
+	}
+	// stack holds the mappings enclosing the current line
+	stack := []level{{indent: -1}}
+	// listPath is the key whose "- item" lines are being read
+	listPath, listIndent := "", -1
+
+	for n, raw := range strings.Split(string(data), "\n") {
+		lineNo := n + 1
+		line := stripComment(strings.TrimRight(raw, " \t\r"))
+		content := strings.TrimLeft(line, " ")
+		if content == "" || content == "---" {
+			continue
+		}
+		if strings.HasPrefix(line, "\t") {
+			return nil, fmt.Errorf("line %d: indent with spaces, not tabs", lineNo)
+		}
+		indent := len(line) - len(content)
+
+		if strings.HasPrefix(content, "- ") || content == "-" {
+			if listPath == "" || indent < listIndent {
+				return nil, fmt.Errorf("line %d: list item outside a list", lineNo)
+			}
+			item, err := unquote(strings.TrimSpace(strings.TrimPrefix(content, "-")))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			v := out[listPath]
+			v.list = append(v.list, item)
+			out[listPath] = v
+			continue
+		}
+		listPath = ""
+
+		key, rest, ok := strings.Cut(content, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || (rest != "" && rest[0] != ' ') {
+			return nil, fmt.Errorf("line %d: expected key: value", lineNo)
+		}
+		rest = strings.TrimSpace(rest)
+
+		for indent <= stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		path := key
+		if parent := stack[len(stack)-1].path; parent != "" {
+			path = parent + "." + key
+		}
+		if _, dup := out[path]; dup {
+			return nil, fmt.Errorf("line %d: %s is set twice", lineNo, path)
+		}
+
+		switch {
+		case rest == "":
+			// Either a nested mapping or a block list follows
+			stack = append(stack, level{indent: indent, path: path})
+			listPath, listIndent = path, indent
+			out[path] = yamlValue{isList: true, list: []string{}}
+		case strings.HasPrefix(rest, "{"):
+			return nil, fmt.Errorf("line %d: write mappings as indented lines, not {...}", lineNo)
+		case strings.HasPrefix(rest, "["):
+			if !strings.HasSuffix(rest, "]") {
+				return nil, fmt.Errorf("line %d: unterminated list", lineNo)
+			}
+			list := []string{}
+			if inner := strings.TrimSpace(rest[1 : len(rest)-1]); inner != "" {
+				for _, item := range strings.Split(inner, ",") {
+					item, err := unquote(strings.TrimSpace(item))
+					if err != nil {
+						return nil, fmt.Errorf("line %d: %w", lineNo, err)
+					}
+					list = append(list, item)
+				}
+			}
+			out[path] = yamlValue{list: list, isList: true}
+		default:
+			value, err := unquote(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			out[path] = yamlValue{scalar: value}
+		}
+	}
+
+	// Keys with nothing below them are empty, and keys that turned out to
+	// hold a mapping aren't settings themselves
+	for path, v := range out {
+		if v.isList && len(v.list) == 0 {
+			if hasChildren(out, path) {
+				delete(out, path)
+			} else {
+				out[path] = yamlValue{}
+			}
+		}
+	}
+	return out, nil
+}
+
+func hasChildren(values map[string]yamlValue, path string) bool {
+	for p := range values {
+		if strings.HasPrefix(p, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// stripComment removes a # comment that isn't inside a quoted value
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" \t[,", line[i-1]) >= 0):
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return strings.TrimRight(line[:i], " \t")
+		}
+	}
+	return line
+}
+
+// unquote returns a scalar's value, removing single or double quotes
+func unquote(s string) (string, error) {
+	if len(s) == 0 || (s[0] != '"' && s[0] != '\'') {
+		return s, nil
+	}
+	if len(s) < 2 || s[len(s)-1] != s[0] {
+		return "", fmt.Errorf("unterminated string %s", s)
+	}
+	if s[0] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	v, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	return v, nil
+}
@@ -0,0 +1,145 @@
+package connectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/projects"
+)
+
+// MaxProjects bounds how many projects one webhook delivery may carry
+const MaxProjects = 500
+
+var (
+	ErrUnknownSource   = errors.New("unknown connector source")
+	ErrNoProjects      = errors.New("payload contains no projects")
+	ErrTooManyProjects = fmt.Errorf("payload may contain at most %d projects", MaxProjects)
+)
+
+// InvalidPayloadError reports a payload a mapper could not read, or a
+// project in it that could not be imported
+type InvalidPayloadError struct {
+	Reason string
+}
+
+func (e *InvalidPayloadError) Error() string {
+	return "invalid payload: " + e.Reason
+}
+
+func invalid(format string, args ...interface{}) error {
+	return &InvalidPayloadError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// Mapper turns an external platform's webhook payload into projects
+type Mapper interface {
+	Map(payload []byte) ([]models.ExternalProject, error)
+}
+
+// MapperFunc adapts a function to Mapper
+type MapperFunc func(payload []byte) ([]models.ExternalProject, error)
+
+func (f MapperFunc) Map(payload []byte) ([]models.ExternalProject, error) {
+	return f(payload)
+}
+
+// mappers holds the mapper for each source, by name
+var mappers = map[string]Mapper{
+	"generic":   MapperFunc(mapGeneric),
+	"schemaorg": MapperFunc(mapSchemaOrg),
+}
+
+// Register adds the mapper for a source, replacing any registered under the
+// same name. Sources are registered before the server starts.
+func Register(source string, mapper Mapper) {
+	mappers[source] = mapper
+}
+
+type Service struct {
+	projectsService *projects.Service
+}
+
+func NewService(projectsService *projects.Service) *Service {
+	return &Service{projectsService: projectsService}
+}
+
+// Import maps a webhook payload from source and upserts its projects into
+// the organization, deduplicated by their external IDs. The payload is
+// checked in full before any project is written.
+func (s *Service) Import(ctx context.Context, orgID, source string, payload []byte) (*models.ConnectorImport, error) {
+	mapper, ok := mappers[source]
+	if !ok {
+		return nil, ErrUnknownSource
+	}
+
+	external, err := mapper.Map(payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(external) == 0 {
+		return nil, ErrNoProjects
+	}
+	if len(external) > MaxProjects {
+		return nil, ErrTooManyProjects
+	}
+
+	seen := make(map[string]bool, len(external))
+	for i := range external {
+		p := &external[i]
+		p.ExternalID = strings.TrimSpace(p.ExternalID)
+		p.Name = strings.TrimSpace(p.Name)
+		if p.ExternalID == "" {
+			return nil, invalid("project %d has no external ID", i)
+		}
+		if len(p.ExternalID) > 255 {
+			return nil, invalid("project %q has an external ID over 255 characters", p.ExternalID)
+		}
+		if seen[p.ExternalID] {
+			return nil, invalid("project %q appears more than once", p.ExternalID)
+		}
+		seen[p.ExternalID] = true
+		if p.Name == "" {
+			return nil, invalid("project %q has no name", p.ExternalID)
+		}
+		if (p.Latitude == nil) != (p.Longitude == nil) {
+			return nil, invalid("project %q needs both latitude and longitude", p.ExternalID)
+		}
+		if p.Latitude != nil && (*p.Latitude < -90 || *p.Latitude > 90 || *p.Longitude < -180 || *p.Longitude > 180) {
+			return nil, invalid("project %q has an invalid location", p.ExternalID)
+		}
+		if p.StartDate != nil && p.EndDate != nil && p.EndDate.Before(*p.StartDate) {
+			return nil, invalid("project %q ends before it starts", p.ExternalID)
+		}
+		if p.MaxVolunteers != nil && *p.MaxVolunteers < 1 {
+			return nil, invalid("project %q must allow at least one volunteer", p.ExternalID)
+		}
+		if p.Timezone != "" && !models.ValidTimezone(p.Timezone) {
+			return nil, invalid("project %q: %v", p.ExternalID, projects.ErrInvalidTimezone)
+		}
+	}
+
+	result := &models.ConnectorImport{
+		Source:   source,
+		Projects: []models.ConnectorImportResult{},
+	}
+	for _, p := range external {
+		id, status, created, err := s.projectsService.UpsertExternalProject(ctx, orgID, source, p)
+		if err != nil {
+			return nil, fmt.Errorf("upsert external project %q: %w", p.ExternalID, err)
+		}
+		if created {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+		result.Projects = append(result.Projects, models.ConnectorImportResult{
+			ExternalID: p.ExternalID,
+			ProjectID:  id,
+			Created:    created,
+			Status:     status,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package connectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/civic-weave/backend/internal/models"
+)
+
+// genericPayload is the connector format platforms can post without a
+// dedicated mapper
+type genericPayload struct {
+	Projects []genericProject `json:"projects"`
+}
+
+type genericProject struct {
+	ID            string   `json:"id"`
+	URL           *string  `json:"url"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	Latitude      *float64 `json:"latitude"`
+	Longitude     *float64 `json:"longitude"`
+	LocationName  *string  `json:"locationName"`
+	Remote        bool     `json:"remote"`
+	Timezone      string   `json:"timezone"`
+	StartDate     string   `json:"startDate"`
+	EndDate       string   `json:"endDate"`
+	MaxVolunteers *int     `json:"maxVolunteers"`
+	Closed        bool     `json:"closed"`
+}
+
+func mapGeneric(payload []byte) ([]models.ExternalProject, error) {
+	var body genericPayload
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&body); err != nil {
+		return nil, invalid("%v", err)
+	}
+
+	external := make([]models.ExternalProject, 0, len(body.Projects))
+	for _, p := range body.Projects {
+		start, err := parseDate(p.StartDate)
+		if err != nil {
+			return nil, invalid("project %q has an invalid startDate", p.ID)
+		}
+		end, err := parseDate(p.EndDate)
+		if err != nil {
+			return nil, invalid("project %q has an invalid endDate", p.ID)
+		}
+		external = append(external, models.ExternalProject{
+			ExternalID:    p.ID,
+			URL:           p.URL,
+			Name:          p.Name,
+			Description:   p.Description,
+			Latitude:      p.Latitude,
+			Longitude:     p.Longitude,
+			LocationName:  p.LocationName,
+			IsRemote:      p.Remote,
+			Timezone:      p.Timezone,
+			StartDate:     start,
+			EndDate:       end,
+			MaxVolunteers: p.MaxVolunteers,
+			Closed:        p.Closed,
+		})
+	}
+	return external, nil
+}
+
+// parseDate reads an RFC 3339 timestamp or a YYYY-MM-DD date, returning nil
+// for an empty string
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse("2006-01-02", value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
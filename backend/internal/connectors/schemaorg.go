@@ -0,0 +1,249 @@
+package connectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/models"
+)
+
+// schemaEvent is the part of a schema.org Event (https://schema.org/Event)
+// that maps onto a project, as platforms publish volunteer opportunities
+// in JSON-LD
+type schemaEvent struct {
+	Type                    interface{}     `json:"@type"`
+	ID                      string          `json:"@id"`
+	Identifier              interface{}     `json:"identifier"`
+	URL                     string          `json:"url"`
+	Name                    string          `json:"name"`
+	Description             string          `json:"description"`
+	StartDate               string          `json:"startDate"`
+	EndDate                 string          `json:"endDate"`
+	Location                json.RawMessage `json:"location"`
+	EventAttendanceMode     string          `json:"eventAttendanceMode"`
+	EventStatus             string          `json:"eventStatus"`
+	MaximumAttendeeCapacity *int            `json:"maximumAttendeeCapacity"`
+}
+
+// schemaPlace is a schema.org Place or VirtualLocation
+type schemaPlace struct {
+	Type    interface{}     `json:"@type"`
+	Name    string          `json:"name"`
+	Address json.RawMessage `json:"address"`
+	Geo     *struct {
+		Latitude  json.Number `json:"latitude"`
+		Longitude json.Number `json:"longitude"`
+	} `json:"geo"`
+}
+
+// mapSchemaOrg reads an Event, an ItemList of Events or a JSON-LD @graph
+func mapSchemaOrg(payload []byte) ([]models.ExternalProject, error) {
+	var events []schemaEvent
+	if err := collectEvents(payload, &events); err != nil {
+		return nil, err
+	}
+
+	external := make([]models.ExternalProject, 0, len(events))
+	for _, e := range events {
+		p := models.ExternalProject{
+			ExternalID:    identifier(e),
+			Name:          e.Name,
+			Description:   e.Description,
+			IsRemote:      strings.HasSuffix(e.EventAttendanceMode, "OnlineEventAttendanceMode"),
+			MaxVolunteers: e.MaximumAttendeeCapacity,
+			Closed:        strings.HasSuffix(e.EventStatus, "EventCancelled"),
+		}
+		if e.URL != "" {
+			url := e.URL
+			p.URL = &url
+		}
+
+		var err error
+		if p.StartDate, err = parseDate(e.StartDate); err != nil {
+			return nil, invalid("event %q has an invalid startDate", p.ExternalID)
+		}
+		if p.EndDate, err = parseDate(e.EndDate); err != nil {
+			return nil, invalid("event %q has an invalid endDate", p.ExternalID)
+		}
+		if err := applyLocation(&p, e.Location); err != nil {
+			return nil, invalid("event %q has an invalid location: %v", p.ExternalID, err)
+		}
+		external = append(external, p)
+	}
+	return external, nil
+}
+
+// collectEvents appends the Events in a JSON-LD node to events, descending
+// into ItemLists, ListItems and @graph arrays
+func collectEvents(node json.RawMessage, events *[]schemaEvent) error {
+	trimmed := strings.TrimSpace(string(node))
+	if strings.HasPrefix(trimmed, "[") {
+		var nodes []json.RawMessage
+		if err := json.Unmarshal(node, &nodes); err != nil {
+			return invalid("%v", err)
+		}
+		for _, n := range nodes {
+			if err := collectEvents(n, events); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var container struct {
+		Type            interface{}     `json:"@type"`
+		Graph           json.RawMessage `json:"@graph"`
+		ItemListElement json.RawMessage `json:"itemListElement"`
+		Item            json.RawMessage `json:"item"`
+	}
+	if err := json.Unmarshal(node, &container); err != nil {
+		return invalid("%v", err)
+	}
+	switch {
+	case len(container.Graph) > 0:
+		return collectEvents(container.Graph, events)
+	case hasType(container.Type, "ItemList"):
+		return collectEvents(container.ItemListElement, events)
+	case hasType(container.Type, "ListItem"):
+		return collectEvents(container.Item, events)
+	}
+
+	var event schemaEvent
+	if err := json.Unmarshal(node, &event); err != nil {
+		return invalid("%v", err)
+	}
+	// Subtypes such as VolunteerEvent or SocialEvent end in Event
+	if !hasTypeSuffix(event.Type, "Event") {
+		return nil
+	}
+	*events = append(*events, event)
+	return nil
+}
+
+// identifier picks an event's ID: its identifier, which may be a
+// PropertyValue, then its @id, then its url
+func identifier(e schemaEvent) string {
+	switch id := e.Identifier.(type) {
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	case map[string]interface{}:
+		if value, ok := id["value"]; ok {
+			return fmt.Sprint(value)
+		}
+	}
+	if e.ID != "" {
+		return e.ID
+	}
+	return e.URL
+}
+
+// applyLocation sets the project's place from an Event's location, which is
+// a Place, a VirtualLocation, plain text or a list of them
+func applyLocation(p *models.ExternalProject, raw json.RawMessage) error {
+	trimmed := strings.TrimSpace(string(raw))
+	switch {
+	case trimmed == "" || trimmed == "null":
+		return nil
+	case strings.HasPrefix(trimmed, "["):
+		var places []json.RawMessage
+		if err := json.Unmarshal(raw, &places); err != nil {
+			return err
+		}
+		for _, place := range places {
+			if err := applyLocation(p, place); err != nil {
+				return err
+			}
+		}
+		return nil
+	case strings.HasPrefix(trimmed, `"`):
+		var name string
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return err
+		}
+		p.LocationName = &name
+		return nil
+	}
+
+	var place schemaPlace
+	if err := json.Unmarshal(raw, &place); err != nil {
+		return err
+	}
+	if hasType(place.Type, "VirtualLocation") {
+		p.IsRemote = true
+		return nil
+	}
+
+	name := place.Name
+	if name == "" {
+		name = address(place.Address)
+	}
+	if name != "" {
+		p.LocationName = &name
+	}
+	if place.Geo != nil && place.Geo.Latitude != "" && place.Geo.Longitude != "" {
+		lat, err := place.Geo.Latitude.Float64()
+		if err != nil {
+			return err
+		}
+		lon, err := place.Geo.Longitude.Float64()
+		if err != nil {
+			return err
+		}
+		p.Latitude, p.Longitude = &lat, &lon
+	}
+	return nil
+}
+
+// address writes out a Place's address, given as text or a PostalAddress
+func address(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+	var postal struct {
+		StreetAddress   string `json:"streetAddress"`
+		AddressLocality string `json:"addressLocality"`
+		AddressRegion   string `json:"addressRegion"`
+	}
+	if json.Unmarshal(raw, &postal) != nil {
+		return ""
+	}
+	var parts []string
+	for _, part := range []string{postal.StreetAddress, postal.AddressLocality, postal.AddressRegion} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// hasType reports whether a JSON-LD @type, a string or list of strings,
+// names typ
+func hasType(types interface{}, typ string) bool {
+	return matchType(types, func(t string) bool { return t == typ || strings.HasSuffix(t, "/"+typ) })
+}
+
+func hasTypeSuffix(types interface{}, suffix string) bool {
+	return matchType(types, func(t string) bool { return strings.HasSuffix(t, suffix) })
+}
+
+func matchType(types interface{}, match func(string) bool) bool {
+	switch t := types.(type) {
+	case string:
+		return match(t)
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok && match(s) {
+				return true
+			}
+		}
+	}
+	return false
+}
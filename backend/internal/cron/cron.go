@@ -0,0 +1,186 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression. Times are matched in UTC.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// When both day fields are restricted a day matching either runs, as
+	// in standard cron; a field starting with * doesn't restrict
+	domAny, dowAny bool
+}
+
+type fieldRange struct {
+	name     string
+	min, max int
+	names    []string // names for min, min+1, ...
+}
+
+var (
+	minutes  = fieldRange{name: "minute", min: 0, max: 59}
+	hours    = fieldRange{name: "hour", min: 0, max: 23}
+	days     = fieldRange{name: "day of month", min: 1, max: 31}
+	months   = fieldRange{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	weekdays = fieldRange{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse reads a five-field cron expression (minute, hour, day of month,
+// month, day of week) or one of @yearly, @monthly, @weekly, @daily and
+// @hourly. Fields take *, numbers, ranges (1-5), steps (*/15, 0-30/10),
+// comma-separated lists, and month and weekday names such as jan and mon.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if expanded, ok := descriptors[spec]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields", spec)
+	}
+
+	var s Schedule
+	var err error
+	if s.minute, err = parseField(fields[0], minutes); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hours); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], days); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], months); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], weekdays); err != nil {
+		return nil, err
+	}
+	// 7 is Sunday too
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domAny = strings.HasPrefix(fields[2], "*")
+	s.dowAny = strings.HasPrefix(fields[4], "*")
+	return &s, nil
+}
+
+func parseField(field string, r fieldRange) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		expr, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepStr, r.name)
+			}
+			step = n
+		}
+
+		lo, hi := r.min, r.max
+		switch {
+		case expr == "*":
+		case strings.Contains(expr, "-"):
+			a, b, _ := strings.Cut(expr, "-")
+			var err error
+			if lo, err = r.value(a); err != nil {
+				return 0, err
+			}
+			if hi, err = r.value(b); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("range %q in %s field is backwards", expr, r.name)
+			}
+		default:
+			v, err := r.value(expr)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			// A single value with a step runs from it to the end
+			hi = v
+			if hasStep {
+				hi = r.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (r fieldRange) value(s string) (int, error) {
+	for i, name := range r.names {
+		if s == name {
+			return r.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < r.min || v > r.max {
+		return 0, fmt.Errorf("invalid %s %q", r.name, s)
+	}
+	return v, nil
+}
+
+// Next returns the first matching minute after t, or the zero time for
+// expressions that never match
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	// Every valid expression matches within a few years; stop looking
+	// after five in case of e.g. 30 February
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	}
+	return dom || dow
+}
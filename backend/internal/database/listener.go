@@ -0,0 +1,115 @@
+package database
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// MatchInvalidationChannel carries skill and match changes published by the
+// triggers in migration 009. Payloads are JSON: {"table": "...", "id": "..."}.
+const MatchInvalidationChannel = "match_invalidation"
+
+// VolunteerActivityChannel carries enrollments, logged hours and skill
+// verifications published by the triggers in migration 035. Payloads are
+// JSON: {"table": "...", "volunteerId": "..."}.
+const VolunteerActivityChannel = "volunteer_activity"
+
+// JobQueuedChannel carries the kind of each job queued, published by the
+// trigger in migration 049
+const JobQueuedChannel = "job_queued"
+
+// ProjectChangedChannel carries the ID of each project inserted, updated or
+// deleted, or whose skills changed, published by the triggers in migration 051
+const ProjectChangedChannel = "project_changed"
+
+// MatchesStaleChannel carries the kind, project or volunteer, of each
+// entity whose precomputed matches went stale, published by the triggers in
+// migration 075
+const MatchesStaleChannel = "matches_stale"
+
+// EventsChannel carries the events services publish with events.Publish.
+// Payloads are JSON events: {"type": "...", "data": {...}, "userIds": [...]}.
+const EventsChannel = "app_events"
+
+const (
+	listenerMinReconnect = 10 * time.Second
+	listenerMaxReconnect = time.Minute
+	listenerPingInterval = 90 * time.Second
+)
+
+// NotificationHandler receives the payload of each notification. An empty
+// payload means the connection was re-established and events may have been
+// missed, so handlers should drop everything they derived from the channel.
+type NotificationHandler func(payload string)
+
+// Listener owns a dedicated LISTEN connection and the goroutine dispatching
+// its notifications
+type Listener struct {
+	channel  string
+	listener *pq.Listener
+	done     chan struct{}
+	once     sync.Once
+}
+
+// Listen subscribes handler to a Postgres NOTIFY channel. The listener
+// reconnects on its own and is stopped when the database is closed.
+func (db *PostgresDB) Listen(channel string, handler NotificationHandler) (*Listener, error) {
+	pl := pq.NewListener(db.connStr, listenerMinReconnect, listenerMaxReconnect, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Warn("Listener event", "channel", channel, "error", err)
+		}
+	})
+
+	if err := pl.Listen(channel); err != nil {
+		pl.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+
+	l := &Listener{
+		channel:  channel,
+		listener: pl,
+		done:     make(chan struct{}),
+	}
+	go l.run(handler)
+
+	db.listeners = append(db.listeners, l)
+	slog.Info("Listening for notifications", "channel", channel)
+	return l, nil
+}
+
+func (l *Listener) run(handler NotificationHandler) {
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case n, ok := <-l.listener.Notify:
+			if !ok {
+				return
+			}
+			// pq sends nil after reconnecting
+			if n == nil {
+				handler("")
+				continue
+			}
+			handler(n.Extra)
+		case <-ticker.C:
+			// Detect dead connections that never reported an error
+			go l.listener.Ping()
+		}
+	}
+}
+
+// Close stops dispatching and releases the LISTEN connection
+func (l *Listener) Close() {
+	l.once.Do(func() {
+		close(l.done)
+		l.listener.Close()
+	})
+}
@@ -0,0 +1,290 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles are the versioned schema changes, NNN_name.up.sql with a
+// matching NNN_name.down.sql that reverts it
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLock is the advisory lock held while migrating, so instances
+// starting together apply each migration once
+const migrationLock = 7240611
+
+// ErrUnversionedSchema is returned when the database has tables but no
+// migration history, such as databases set up by running the SQL files
+// directly. Record what they already have with Baseline.
+var ErrUnversionedSchema = errors.New("database has tables but no migration history; run `migrate baseline <version>` with the last migration it has")
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+// MigrationStatus is a migration with when it was applied, if it has been
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrations returns the embedded migrations in version order
+func Migrations() ([]Migration, error) {
+	files, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, file := range files {
+		base := strings.TrimPrefix(file, "migrations/")
+		stem, direction := strings.TrimSuffix(base, ".up.sql"), "up"
+		if stem == base {
+			stem, direction = strings.TrimSuffix(base, ".down.sql"), "down"
+		}
+		prefix, name, ok := strings.Cut(stem, "_")
+		version, err := strconv.Atoi(prefix)
+		if stem == base || !ok || err != nil {
+			return nil, fmt.Errorf("migration %s: name must be NNN_name.up.sql or NNN_name.down.sql", base)
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration %s: version %d is also used by %s", base, version, m.Name)
+		}
+		content, err := migrationFiles.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if direction == "up" {
+			m.up = string(content)
+		} else {
+			m.down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("migration %03d_%s: needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// MigrateUp applies every migration not yet applied, in version order, and
+// returns them. Each runs in its own transaction with its history record.
+func (db *PostgresDB) MigrateUp() ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	err = db.withMigrationLock(func(conn *sql.Conn) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			err := runMigration(conn, m.up, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name)
+			if err != nil {
+				return fmt.Errorf("migration %03d_%s: %w", m.Version, m.Name, err)
+			}
+			applied = append(applied, m)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// MigrateDown reverts the last steps applied migrations, newest first, and
+// returns them
+func (db *PostgresDB) MigrateDown(steps int) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	err = db.withMigrationLock(func(conn *sql.Conn) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			m := migrations[i]
+			if _, ok := done[m.Version]; !ok {
+				continue
+			}
+			err := runMigration(conn, m.down, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+			if err != nil {
+				return fmt.Errorf("migration %03d_%s down: %w", m.Version, m.Name, err)
+			}
+			reverted = append(reverted, m)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// MigrationStatus lists the embedded migrations with when each was applied
+func (db *PostgresDB) MigrationStatus() ([]MigrationStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var status []MigrationStatus
+	err = db.withMigrationLock(func(conn *sql.Conn) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			s := MigrationStatus{Migration: m}
+			if appliedAt, ok := done[m.Version]; ok {
+				s.AppliedAt = &appliedAt
+			}
+			status = append(status, s)
+		}
+		return nil
+	})
+	return status, err
+}
+
+// Baseline records every migration up to version as applied without running
+// it, for databases whose schema was created before migrations were tracked
+func (db *PostgresDB) Baseline(version int) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	return db.withMigrationLock(func(conn *sql.Conn) error {
+		if err := createMigrationTable(conn); err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if m.Version > version {
+				break
+			}
+			_, err := conn.ExecContext(context.Background(), `
+				INSERT INTO schema_migrations (version, name) VALUES ($1, $2)
+				ON CONFLICT (version) DO NOTHING
+			`, m.Version, m.Name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// withMigrationLock runs fn on one connection holding the migration lock
+func (db *PostgresDB) withMigrationLock(fn func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLock); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLock)
+
+	return fn(conn)
+}
+
+// appliedVersions returns when each applied migration was applied, creating
+// the history table for new databases
+func appliedVersions(conn *sql.Conn) (map[int]time.Time, error) {
+	ctx := context.Background()
+	var tracked, hasTables bool
+	err := conn.QueryRowContext(ctx, `
+		SELECT to_regclass('schema_migrations') IS NOT NULL, to_regclass('users') IS NOT NULL
+	`).Scan(&tracked, &hasTables)
+	if err != nil {
+		return nil, err
+	}
+	if !tracked {
+		if hasTables {
+			return nil, ErrUnversionedSchema
+		}
+		if err := createMigrationTable(conn); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+func createMigrationTable(conn *sql.Conn) error {
+	_, err := conn.ExecContext(context.Background(), `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+// runMigration runs a migration's SQL and updates its history record in one
+// transaction
+func runMigration(conn *sql.Conn, script, record string, args ...interface{}) error {
+	ctx := context.Background()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,8 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS project_volunteer_matches_notify ON project_volunteer_matches;
+DROP TRIGGER IF EXISTS project_skills_notify ON project_skills;
+DROP TRIGGER IF EXISTS volunteer_skills_notify ON volunteer_skills;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS notify_matches_refreshed();
+DROP FUNCTION IF EXISTS notify_skill_change();
@@ -0,0 +1,54 @@
+-- Broadcast skill and match changes so every API instance can invalidate
+-- its in-memory match cache (see internal/database/listener.go)
+
+-- Row-level notification for volunteer/project skill edits.
+-- pg_notify collapses identical payloads within a transaction, so bulk
+-- updates for one volunteer or project only produce a single event.
+CREATE OR REPLACE FUNCTION notify_skill_change() RETURNS trigger AS $$
+DECLARE
+    rec RECORD;
+    entity_id UUID;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    IF TG_TABLE_NAME = 'volunteer_skills' THEN
+        entity_id := rec.volunteer_id;
+    ELSE
+        entity_id := rec.project_id;
+    END IF;
+
+    PERFORM pg_notify('match_invalidation', json_build_object('table', TG_TABLE_NAME, 'id', entity_id)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Statement-level notification when the batch matcher rewrites cached matches
+CREATE OR REPLACE FUNCTION notify_matches_refreshed() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('match_invalidation', json_build_object('table', TG_TABLE_NAME)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS volunteer_skills_notify ON volunteer_skills;
+CREATE TRIGGER volunteer_skills_notify
+AFTER INSERT OR UPDATE OR DELETE ON volunteer_skills
+FOR EACH ROW EXECUTE FUNCTION notify_skill_change();
+
+DROP TRIGGER IF EXISTS project_skills_notify ON project_skills;
+CREATE TRIGGER project_skills_notify
+AFTER INSERT OR UPDATE OR DELETE ON project_skills
+FOR EACH ROW EXECUTE FUNCTION notify_skill_change();
+
+DROP TRIGGER IF EXISTS project_volunteer_matches_notify ON project_volunteer_matches;
+CREATE TRIGGER project_volunteer_matches_notify
+AFTER INSERT OR UPDATE OR DELETE ON project_volunteer_matches
+FOR EACH STATEMENT EXECUTE FUNCTION notify_matches_refreshed();
+
+-- Add comments
+COMMENT ON FUNCTION notify_skill_change IS 'Publishes volunteer/project skill changes on the match_invalidation channel';
+COMMENT ON FUNCTION notify_matches_refreshed IS 'Publishes batch match refreshes on the match_invalidation channel';
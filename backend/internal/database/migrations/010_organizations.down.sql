@@ -0,0 +1,11 @@
+-- Drop project ownership
+DROP INDEX IF EXISTS idx_projects_organization;
+ALTER TABLE projects DROP COLUMN IF EXISTS organization_id;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_organization_members_status;
+DROP INDEX IF EXISTS idx_organization_members_user;
+
+-- Drop tables
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,55 @@
+-- Organizations host projects and group the people who manage them
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Organization membership with per-org roles.
+-- Invited members have no permissions until they accept.
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'admin', 'coordinator', 'member')),
+    status VARCHAR(20) NOT NULL DEFAULT 'invited' CHECK (status IN ('invited', 'active')),
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    accepted_at TIMESTAMP,
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_members_user ON organization_members(user_id);
+CREATE INDEX IF NOT EXISTS idx_organization_members_status ON organization_members(organization_id, status);
+
+-- Projects belong to an organization
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS organization_id UUID REFERENCES organizations(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_projects_organization ON projects(organization_id);
+
+-- Seed a default organization owning the existing demo projects
+INSERT INTO organizations (name, slug, description, created_by)
+SELECT 'Civic Weave', 'civic-weave', 'Default demo organization', u.id
+FROM users u
+WHERE u.email = 'admin@civicweave.org'
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO organization_members (organization_id, user_id, role, status, accepted_at)
+SELECT o.id, u.id,
+       CASE WHEN u.role = 'admin' THEN 'owner' ELSE 'coordinator' END,
+       'active', CURRENT_TIMESTAMP
+FROM organizations o
+JOIN users u ON u.role IN ('admin', 'coordinator')
+WHERE o.slug = 'civic-weave'
+ON CONFLICT (organization_id, user_id) DO NOTHING;
+
+UPDATE projects
+SET organization_id = (SELECT id FROM organizations WHERE slug = 'civic-weave')
+WHERE organization_id IS NULL;
+
+-- Add comments
+COMMENT ON TABLE organizations IS 'Civic organizations hosting projects';
+COMMENT ON TABLE organization_members IS 'Organization membership: owner, admin, coordinator, member';
+COMMENT ON COLUMN organization_members.status IS 'Membership status: invited, active';
@@ -0,0 +1,12 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_organization_members_org_user_active;
+DROP INDEX IF EXISTS idx_projects_organization_status;
+
+-- Remove seeded volunteer memberships
+DELETE FROM organization_members om
+USING organizations o, users u
+WHERE om.organization_id = o.id
+  AND om.user_id = u.id
+  AND o.slug = 'civic-weave'
+  AND u.role = 'volunteer'
+  AND om.role = 'member';
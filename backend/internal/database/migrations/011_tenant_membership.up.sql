@@ -0,0 +1,13 @@
+-- Volunteers are scoped to the organizations they belong to.
+-- Existing volunteers join the default demo organization so tenant-scoped
+-- matching keeps returning them.
+INSERT INTO organization_members (organization_id, user_id, role, status, accepted_at)
+SELECT o.id, u.id, 'member', 'active', CURRENT_TIMESTAMP
+FROM organizations o
+JOIN users u ON u.role = 'volunteer'
+WHERE o.slug = 'civic-weave'
+ON CONFLICT (organization_id, user_id) DO NOTHING;
+
+-- Tenant-scoped listings filter by organization
+CREATE INDEX IF NOT EXISTS idx_projects_organization_status ON projects(organization_id, status);
+CREATE INDEX IF NOT EXISTS idx_organization_members_org_user_active ON organization_members(organization_id, user_id) WHERE status = 'active';
@@ -0,0 +1,25 @@
+-- Per-organization settings: matching defaults, enrollment policies, branding
+CREATE TABLE IF NOT EXISTS organization_settings (
+    organization_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+
+    -- Matching defaults used when a request doesn't specify weights
+    default_skill_weight DECIMAL(3, 2) NOT NULL DEFAULT 0.7 CHECK (default_skill_weight >= 0 AND default_skill_weight <= 1),
+    default_distance_weight DECIMAL(3, 2) NOT NULL DEFAULT 0.3 CHECK (default_distance_weight >= 0 AND default_distance_weight <= 1),
+    default_max_distance_km DECIMAL(8, 2) NOT NULL DEFAULT 100 CHECK (default_max_distance_km > 0),
+
+    -- Enrollment policies
+    allow_volunteer_requests BOOLEAN NOT NULL DEFAULT TRUE,
+    require_request_message BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- Branding for notifications and embedded pages
+    email_footer TEXT NOT NULL DEFAULT '',
+    primary_color VARCHAR(7) NOT NULL DEFAULT '',
+    secondary_color VARCHAR(7) NOT NULL DEFAULT '',
+    logo_url TEXT NOT NULL DEFAULT '',
+
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add comments
+COMMENT ON TABLE organization_settings IS 'Typed per-organization settings; missing rows mean defaults';
@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_organization_invitations_pending_email;
+DROP INDEX IF EXISTS idx_organization_invitations_org_status;
+
+-- Drop tables
+DROP TABLE IF EXISTS organization_invitations;
@@ -0,0 +1,27 @@
+-- Email invitations to join an organization with a pre-selected role.
+-- The invitee may not have an account yet; accepting creates or links it.
+CREATE TABLE IF NOT EXISTS organization_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'admin', 'coordinator', 'member')),
+    token_hash VARCHAR(64) UNIQUE NOT NULL, -- SHA-256 of the emailed token
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'revoked')),
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    accepted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    accepted_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_invitations_org_status ON organization_invitations(organization_id, status);
+
+-- One pending invitation per email per organization
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organization_invitations_pending_email
+    ON organization_invitations(organization_id, LOWER(email))
+    WHERE status = 'pending';
+
+-- Add comments
+COMMENT ON TABLE organization_invitations IS 'Emailed organization invitations with a pre-selected role';
+COMMENT ON COLUMN organization_invitations.status IS 'Invitation status: pending, accepted, revoked (expiry is derived from expires_at)';
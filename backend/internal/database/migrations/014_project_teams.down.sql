@@ -0,0 +1,9 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_project_team_messages_team_created;
+DROP INDEX IF EXISTS idx_project_team_members_volunteer_id;
+DROP INDEX IF EXISTS idx_project_teams_project_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS project_team_messages;
+DROP TABLE IF EXISTS project_team_members;
+DROP TABLE IF EXISTS project_teams;
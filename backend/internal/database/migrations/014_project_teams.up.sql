@@ -0,0 +1,43 @@
+-- Teams split a project's enrolled volunteers into groups (logistics, outreach)
+CREATE TABLE IF NOT EXISTS project_teams (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    lead_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(project_id, name)
+);
+
+-- A volunteer belongs to at most one team per project; project_id is
+-- denormalized from project_teams so the constraint can be enforced
+CREATE TABLE IF NOT EXISTS project_team_members (
+    team_id UUID NOT NULL REFERENCES project_teams(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    volunteer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assigned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    assigned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (team_id, volunteer_id),
+    UNIQUE(project_id, volunteer_id)
+);
+
+-- Broadcasts sent to a team, kept as the team's message history
+CREATE TABLE IF NOT EXISTS project_team_messages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id UUID NOT NULL REFERENCES project_teams(id) ON DELETE CASCADE,
+    sender_id UUID NOT NULL REFERENCES users(id),
+    subject VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    recipient_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_teams_project_id ON project_teams(project_id);
+CREATE INDEX IF NOT EXISTS idx_project_team_members_volunteer_id ON project_team_members(volunteer_id);
+CREATE INDEX IF NOT EXISTS idx_project_team_messages_team_created ON project_team_messages(team_id, created_at DESC);
+
+-- Add comments
+COMMENT ON TABLE project_teams IS 'Teams of enrolled volunteers within a project';
+COMMENT ON COLUMN project_teams.lead_id IS 'Team lead; must be enrolled in the project';
+COMMENT ON TABLE project_team_messages IS 'Team-scoped broadcast messages';
@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_volunteer_enrollments_project_approved;
+DROP INDEX IF EXISTS idx_volunteer_hours_project_worked_on;
+DROP INDEX IF EXISTS idx_volunteer_hours_enrollment_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS volunteer_hours;
@@ -0,0 +1,22 @@
+-- Hours volunteers log against their enrollments, rolled up by org reports
+CREATE TABLE IF NOT EXISTS volunteer_hours (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    enrollment_id UUID NOT NULL REFERENCES volunteer_enrollments(id) ON DELETE CASCADE,
+    volunteer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    hours NUMERIC(5,2) NOT NULL CHECK (hours > 0 AND hours <= 24),
+    worked_on DATE NOT NULL,
+    note TEXT,
+    logged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_volunteer_hours_enrollment_id ON volunteer_hours(enrollment_id);
+CREATE INDEX IF NOT EXISTS idx_volunteer_hours_project_worked_on ON volunteer_hours(project_id, worked_on);
+
+-- Reports filter enrollments by approval date
+CREATE INDEX IF NOT EXISTS idx_volunteer_enrollments_project_approved ON volunteer_enrollments(project_id, approved_at) WHERE status = 'enrolled';
+
+-- Add comments
+COMMENT ON TABLE volunteer_hours IS 'Volunteer hours logged per enrollment';
+COMMENT ON COLUMN volunteer_hours.project_id IS 'Denormalized from the enrollment for per-project rollups';
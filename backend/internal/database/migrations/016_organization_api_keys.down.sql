@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_organization_api_keys_org;
+
+-- Drop tables
+DROP TABLE IF EXISTS organization_api_keys;
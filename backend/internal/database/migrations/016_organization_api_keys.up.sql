@@ -0,0 +1,20 @@
+-- API keys partner websites use to act for an organization.
+-- Keys are scoped to one organization and a fixed set of scopes.
+CREATE TABLE IF NOT EXISTS organization_api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    key_prefix VARCHAR(16) NOT NULL, -- leading characters shown to admins to tell keys apart
+    key_hash VARCHAR(64) UNIQUE NOT NULL, -- SHA-256 of the key
+    scopes TEXT[] NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_api_keys_org ON organization_api_keys(organization_id);
+
+-- Add comments
+COMMENT ON TABLE organization_api_keys IS 'Organization-scoped API keys for partner integrations';
+COMMENT ON COLUMN organization_api_keys.scopes IS 'Granted scopes: projects:read, enrollments:write';
@@ -0,0 +1,11 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS organization_volunteer_shares_notify ON organization_volunteer_shares;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS volunteer_visible_to_org(UUID, UUID);
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_organization_volunteer_shares_recipient;
+
+-- Drop tables
+DROP TABLE IF EXISTS organization_volunteer_shares;
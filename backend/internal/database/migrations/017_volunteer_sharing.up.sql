@@ -0,0 +1,47 @@
+-- Sharing agreements let one organization's volunteers appear in another
+-- organization's match results. Grants are one-way: a row lets the recipient
+-- see the owner's volunteers; mutual sharing is two rows.
+CREATE TABLE IF NOT EXISTS organization_volunteer_shares (
+    owner_organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    recipient_organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    granted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP,
+    PRIMARY KEY (owner_organization_id, recipient_organization_id),
+    CHECK (owner_organization_id <> recipient_organization_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_volunteer_shares_recipient
+    ON organization_volunteer_shares(recipient_organization_id)
+    WHERE revoked_at IS NULL;
+
+-- Whether a volunteer shows up in an organization's match results: active
+-- members of the organization, or of an organization sharing with it
+CREATE OR REPLACE FUNCTION volunteer_visible_to_org(p_volunteer_id UUID, p_org_id UUID)
+RETURNS BOOLEAN AS $$
+    SELECT EXISTS (
+        SELECT 1
+        FROM organization_members om
+        WHERE om.user_id = p_volunteer_id
+          AND om.status = 'active'
+          AND (
+              om.organization_id = p_org_id
+              OR om.organization_id IN (
+                  SELECT s.owner_organization_id
+                  FROM organization_volunteer_shares s
+                  WHERE s.recipient_organization_id = p_org_id
+                    AND s.revoked_at IS NULL
+              )
+          )
+    );
+$$ LANGUAGE sql STABLE;
+
+-- Cached tenant-scoped matches go stale when agreements change
+DROP TRIGGER IF EXISTS organization_volunteer_shares_notify ON organization_volunteer_shares;
+CREATE TRIGGER organization_volunteer_shares_notify
+AFTER INSERT OR UPDATE OR DELETE ON organization_volunteer_shares
+FOR EACH STATEMENT EXECUTE FUNCTION notify_matches_refreshed();
+
+-- Add comments
+COMMENT ON TABLE organization_volunteer_shares IS 'One-way grants letting the recipient organization match the owner organization''s volunteers';
+COMMENT ON FUNCTION volunteer_visible_to_org IS 'Tenant visibility check used by the matching queries';
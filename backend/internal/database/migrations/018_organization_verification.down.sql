@@ -0,0 +1,13 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_organization_verification_evidence_org;
+DROP INDEX IF EXISTS idx_organizations_verification_status;
+
+-- Drop tables
+DROP TABLE IF EXISTS organization_verification_evidence;
+
+-- Drop columns
+ALTER TABLE organizations
+    DROP COLUMN IF EXISTS verified_at,
+    DROP COLUMN IF EXISTS verified_by,
+    DROP COLUMN IF EXISTS verification_note,
+    DROP COLUMN IF EXISTS verification_status;
@@ -0,0 +1,35 @@
+-- Organizations are verified by platform admins before they can publish
+-- projects. Anyone can create an organization; it starts unverified.
+ALTER TABLE organizations
+    ADD COLUMN IF NOT EXISTS verification_status VARCHAR(20) NOT NULL DEFAULT 'unverified'
+        CHECK (verification_status IN ('unverified', 'pending', 'verified')),
+    ADD COLUMN IF NOT EXISTS verification_note TEXT,
+    ADD COLUMN IF NOT EXISTS verified_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_organizations_verification_status ON organizations(verification_status);
+
+-- Documents supporting a verification request (registration certificates,
+-- charity filings). Stored in the database so every API instance can serve them.
+CREATE TABLE IF NOT EXISTS organization_verification_evidence (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    content BYTEA NOT NULL,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_verification_evidence_org ON organization_verification_evidence(organization_id);
+
+-- The default demo organization already publishes projects
+UPDATE organizations
+SET verification_status = 'verified', verified_at = CURRENT_TIMESTAMP
+WHERE slug = 'civic-weave';
+
+-- Add comments
+COMMENT ON COLUMN organizations.verification_status IS 'Verification status: unverified, pending, verified';
+COMMENT ON COLUMN organizations.verification_note IS 'Platform admin note on the last verification decision';
+COMMENT ON TABLE organization_verification_evidence IS 'Evidence uploaded with organization verification requests';
@@ -0,0 +1,8 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_projects_lat_lon;
+
+-- Drop triggers
+DROP TRIGGER IF EXISTS projects_sync_location_point ON projects;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS sync_location_point();
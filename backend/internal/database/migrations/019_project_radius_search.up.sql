@@ -0,0 +1,32 @@
+-- Radius search reads projects.location_point when PostGIS is available.
+-- Keep it in sync with latitude/longitude, which the API writes directly.
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'postgis') THEN
+    CREATE OR REPLACE FUNCTION sync_location_point() RETURNS trigger AS $fn$
+    BEGIN
+      IF NEW.latitude IS NULL OR NEW.longitude IS NULL THEN
+        NEW.location_point := NULL;
+      ELSE
+        NEW.location_point := ST_SetSRID(ST_MakePoint(NEW.longitude, NEW.latitude), 4326)::geography;
+      END IF;
+      RETURN NEW;
+    END;
+    $fn$ LANGUAGE plpgsql;
+
+    DROP TRIGGER IF EXISTS projects_sync_location_point ON projects;
+    CREATE TRIGGER projects_sync_location_point
+    BEFORE INSERT OR UPDATE OF latitude, longitude ON projects
+    FOR EACH ROW EXECUTE FUNCTION sync_location_point();
+
+    -- Backfill projects created since 003 without a point
+    UPDATE projects
+    SET location_point = ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography
+    WHERE latitude IS NOT NULL AND longitude IS NOT NULL AND location_point IS NULL;
+
+    COMMENT ON FUNCTION sync_location_point IS 'Derives location_point from latitude/longitude';
+  END IF;
+END $$;
+
+-- Bounding-box prefilter for the Haversine fallback
+CREATE INDEX IF NOT EXISTS idx_projects_lat_lon ON projects(latitude, longitude) WHERE latitude IS NOT NULL;
@@ -0,0 +1,144 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS users_max_travel_notify ON users;
+
+-- Restore the matching function from 003
+CREATE OR REPLACE FUNCTION find_matching_volunteers(
+  p_project_id UUID,
+  p_skill_weight FLOAT DEFAULT 0.7,
+  p_distance_weight FLOAT DEFAULT 0.3,
+  p_max_distance_km FLOAT DEFAULT 100,
+  p_limit INTEGER DEFAULT 20
+)
+RETURNS TABLE (
+  volunteer_id UUID,
+  volunteer_name VARCHAR,
+  email VARCHAR,
+  skill_score FLOAT,
+  distance_km FLOAT,
+  combined_score FLOAT,
+  latitude DECIMAL,
+  longitude DECIMAL,
+  location_name VARCHAR
+) AS $$
+DECLARE
+  has_postgis BOOLEAN;
+BEGIN
+  -- Check if PostGIS is available
+  SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'postgis') INTO has_postgis;
+
+  -- Use PostGIS if available, otherwise use Haversine
+  IF has_postgis THEN
+    RETURN QUERY
+    WITH project_info AS (
+      SELECT
+        p.id,
+        p.latitude as p_lat,
+        p.longitude as p_lon,
+        p.location_point,
+        get_project_skill_vector(p.id) as project_vector
+      FROM projects p
+      WHERE p.id = p_project_id
+    ),
+    volunteer_matches AS (
+      SELECT
+        u.id,
+        u.name,
+        u.email,
+        u.latitude,
+        u.longitude,
+        u.location_name,
+        CASE
+          WHEN vsv.skill_vector IS NOT NULL AND pi.project_vector IS NOT NULL THEN
+            1 - (vsv.skill_vector <=> pi.project_vector)
+          ELSE 0
+        END AS skill_similarity,
+        CASE
+          WHEN u.location_point IS NOT NULL AND pi.location_point IS NOT NULL THEN
+            ST_Distance(u.location_point, pi.location_point) / 1000
+          ELSE NULL
+        END AS distance
+      FROM users u
+      CROSS JOIN project_info pi
+      LEFT JOIN volunteer_skill_vectors vsv ON vsv.volunteer_id = u.id
+      WHERE u.role = 'volunteer'
+    )
+    SELECT
+      vm.id,
+      vm.name,
+      vm.email,
+      vm.skill_similarity,
+      COALESCE(vm.distance, 0),
+      (p_skill_weight * vm.skill_similarity) +
+      (p_distance_weight * CASE
+        WHEN vm.distance IS NOT NULL AND p_max_distance_km > 0 THEN
+          GREATEST(0, 1 - (vm.distance / p_max_distance_km))
+        ELSE 0.5
+      END) AS combined,
+      vm.latitude,
+      vm.longitude,
+      vm.location_name
+    FROM volunteer_matches vm
+    WHERE vm.distance IS NULL OR vm.distance <= p_max_distance_km
+    ORDER BY combined DESC
+    LIMIT p_limit;
+  ELSE
+    -- Fallback to Haversine formula
+    RETURN QUERY
+    WITH project_info AS (
+      SELECT
+        p.id,
+        p.latitude as p_lat,
+        p.longitude as p_lon,
+        get_project_skill_vector(p.id) as project_vector
+      FROM projects p
+      WHERE p.id = p_project_id
+    ),
+    volunteer_matches AS (
+      SELECT
+        u.id,
+        u.name,
+        u.email,
+        u.latitude,
+        u.longitude,
+        u.location_name,
+        CASE
+          WHEN vsv.skill_vector IS NOT NULL AND pi.project_vector IS NOT NULL THEN
+            1 - (vsv.skill_vector <=> pi.project_vector)
+          ELSE 0
+        END AS skill_similarity,
+        CASE
+          WHEN u.latitude IS NOT NULL AND u.longitude IS NOT NULL
+           AND pi.p_lat IS NOT NULL AND pi.p_lon IS NOT NULL THEN
+            haversine_distance_km(u.latitude, u.longitude, pi.p_lat, pi.p_lon)
+          ELSE NULL
+        END AS distance
+      FROM users u
+      CROSS JOIN project_info pi
+      LEFT JOIN volunteer_skill_vectors vsv ON vsv.volunteer_id = u.id
+      WHERE u.role = 'volunteer'
+    )
+    SELECT
+      vm.id,
+      vm.name,
+      vm.email,
+      vm.skill_similarity,
+      COALESCE(vm.distance, 0),
+      (p_skill_weight * vm.skill_similarity) +
+      (p_distance_weight * CASE
+        WHEN vm.distance IS NOT NULL AND p_max_distance_km > 0 THEN
+          GREATEST(0, 1 - (vm.distance / p_max_distance_km))
+        ELSE 0.5
+      END) AS combined,
+      vm.latitude,
+      vm.longitude,
+      vm.location_name
+    FROM volunteer_matches vm
+    WHERE vm.distance IS NULL OR vm.distance <= p_max_distance_km
+    ORDER BY combined DESC
+    LIMIT p_limit;
+  END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Drop columns
+ALTER TABLE users DROP COLUMN IF EXISTS max_travel_km;
@@ -0,0 +1,261 @@
+-- Restore matching functions from 006 and 020
+CREATE OR REPLACE FUNCTION refresh_all_matches()
+RETURNS INTEGER AS $$
+DECLARE
+    match_count INTEGER := 0;
+BEGIN
+    -- Clear existing matches
+    DELETE FROM project_volunteer_matches;
+    
+    -- Insert new matches using tiered matching logic
+    -- Tier 1: Exclude already enrolled volunteers
+    -- Tier 2: Prioritize geo distance (with national exception)
+    -- Tier 3: Match skills last
+    INSERT INTO project_volunteer_matches (project_id, volunteer_id, skill_score, distance_km, combined_score, matched_skills)
+    WITH volunteer_project_combinations AS (
+        SELECT 
+            p.id as project_id,
+            u.id as volunteer_id,
+            p.latitude as p_lat,
+            p.longitude as p_lon,
+            u.latitude as u_lat,
+            u.longitude as u_lon,
+            p.location_name as p_location,
+            u.location_name as u_location,
+            COALESCE(1 - (vsv.skill_vector <=> get_project_skill_vector(p.id)), 0) as skill_score,
+            CASE 
+                WHEN p.latitude IS NOT NULL AND p.longitude IS NOT NULL AND u.latitude IS NOT NULL AND u.longitude IS NOT NULL THEN
+                    6371 * 2 * ASIN(
+                        LEAST(1, SQRT(
+                            POWER(SIN(RADIANS((p.latitude::double precision) - (u.latitude::double precision)) / 2), 2) +
+                            COS(RADIANS(u.latitude::double precision)) * COS(RADIANS(p.latitude::double precision)) *
+                            POWER(SIN(RADIANS((p.longitude::double precision) - (u.longitude::double precision)) / 2), 2)
+                        ))
+                    )
+                ELSE 999999  -- Very large distance for volunteers without location
+            END as distance_km,
+            ARRAY(
+                SELECT s.name 
+                FROM volunteer_skills vs 
+                JOIN project_skills ps ON vs.skill_id = ps.skill_id 
+                JOIN skills s ON vs.skill_id = s.id 
+                WHERE vs.volunteer_id = u.id AND ps.project_id = p.id AND vs.claimed = TRUE
+            ) as matched_skills
+        FROM projects p
+        CROSS JOIN users u
+        LEFT JOIN volunteer_skill_vectors vsv ON vsv.volunteer_id = u.id
+        WHERE p.status = 'active' 
+          AND u.role = 'volunteer'
+          AND u.latitude IS NOT NULL 
+          AND u.longitude IS NOT NULL
+          -- Tier 1: Exclude already enrolled volunteers
+          AND NOT EXISTS (
+              SELECT 1 FROM volunteer_enrollments ve 
+              WHERE ve.volunteer_id = u.id 
+                AND ve.project_id = p.id 
+                AND ve.status = 'active'
+          )
+    ),
+    tiered_matches AS (
+        SELECT 
+            project_id,
+            volunteer_id,
+            skill_score,
+            distance_km,
+            matched_skills,
+            -- Tier 2: Geo distance priority (with national exception)
+            CASE 
+                -- National exception: if both locations contain "Canada" or same country, prioritize by skills
+                WHEN (p_location ILIKE '%Canada%' AND u_location ILIKE '%Canada%') 
+                  OR (p_location ILIKE '%Ontario%' AND u_location ILIKE '%Ontario%')
+                  OR (p_location ILIKE '%Alberta%' AND u_location ILIKE '%Alberta%')
+                  OR (p_location ILIKE '%British Columbia%' AND u_location ILIKE '%British Columbia%')
+                  OR (p_location ILIKE '%Quebec%' AND u_location ILIKE '%Quebec%')
+                  OR (p_location ILIKE '%Manitoba%' AND u_location ILIKE '%Manitoba%')
+                  OR (p_location ILIKE '%Saskatchewan%' AND u_location ILIKE '%Saskatchewan%')
+                  OR (p_location ILIKE '%Nova Scotia%' AND u_location ILIKE '%Nova Scotia%')
+                  OR (p_location ILIKE '%New Brunswick%' AND u_location ILIKE '%New Brunswick%')
+                  OR (p_location ILIKE '%Newfoundland%' AND u_location ILIKE '%Newfoundland%')
+                  OR (p_location ILIKE '%Prince Edward Island%' AND u_location ILIKE '%Prince Edward Island%')
+                  OR (p_location ILIKE '%Northwest Territories%' AND u_location ILIKE '%Northwest Territories%')
+                  OR (p_location ILIKE '%Yukon%' AND u_location ILIKE '%Yukon%')
+                  OR (p_location ILIKE '%Nunavut%' AND u_location ILIKE '%Nunavut%')
+                THEN 
+                    -- For national/same province: prioritize skills (70%) over distance (30%)
+                    0.7 * skill_score + 0.3 * GREATEST(0, 1 - (distance_km / 100))
+                ELSE
+                    -- For different regions: prioritize distance (60%) over skills (40%)
+                    0.4 * skill_score + 0.6 * GREATEST(0, 1 - (distance_km / 100))
+            END as combined_score
+        FROM volunteer_project_combinations
+        WHERE distance_km <= 500  -- Maximum 500km radius
+    )
+    SELECT 
+        project_id,
+        volunteer_id,
+        skill_score,
+        distance_km,
+        combined_score,
+        matched_skills
+    FROM tiered_matches
+    WHERE combined_score > 0.1  -- Minimum threshold for matches
+    ORDER BY combined_score DESC;
+    
+    GET DIAGNOSTICS match_count = ROW_COUNT;
+    
+    -- Update the updated_at timestamp
+    UPDATE project_volunteer_matches SET updated_at = NOW();
+    
+    RETURN match_count;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION find_matching_volunteers(
+  p_project_id UUID,
+  p_skill_weight FLOAT DEFAULT 0.7,
+  p_distance_weight FLOAT DEFAULT 0.3,
+  p_max_distance_km FLOAT DEFAULT 100,
+  p_limit INTEGER DEFAULT 20
+)
+RETURNS TABLE (
+  volunteer_id UUID,
+  volunteer_name VARCHAR,
+  email VARCHAR,
+  skill_score FLOAT,
+  distance_km FLOAT,
+  combined_score FLOAT,
+  latitude DECIMAL,
+  longitude DECIMAL,
+  location_name VARCHAR
+) AS $$
+DECLARE
+  has_postgis BOOLEAN;
+BEGIN
+  -- Check if PostGIS is available
+  SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'postgis') INTO has_postgis;
+
+  -- Use PostGIS if available, otherwise use Haversine
+  IF has_postgis THEN
+    RETURN QUERY
+    WITH project_info AS (
+      SELECT
+        p.id,
+        p.latitude as p_lat,
+        p.longitude as p_lon,
+        p.location_point,
+        get_project_skill_vector(p.id) as project_vector
+      FROM projects p
+      WHERE p.id = p_project_id
+    ),
+    volunteer_matches AS (
+      SELECT
+        u.id,
+        u.name,
+        u.email,
+        u.latitude,
+        u.longitude,
+        u.location_name,
+        CASE
+          WHEN vsv.skill_vector IS NOT NULL AND pi.project_vector IS NOT NULL THEN
+            1 - (vsv.skill_vector <=> pi.project_vector)
+          ELSE 0
+        END AS skill_similarity,
+        CASE
+          WHEN u.location_point IS NOT NULL AND pi.location_point IS NOT NULL THEN
+            ST_Distance(u.location_point, pi.location_point) / 1000
+          ELSE NULL
+        END AS distance,
+        COALESCE(u.max_travel_km::float, p_max_distance_km) AS max_distance
+      FROM users u
+      CROSS JOIN project_info pi
+      LEFT JOIN volunteer_skill_vectors vsv ON vsv.volunteer_id = u.id
+      WHERE u.role = 'volunteer'
+    )
+    SELECT
+      vm.id,
+      vm.name,
+      vm.email,
+      vm.skill_similarity,
+      COALESCE(vm.distance, 0),
+      (p_skill_weight * vm.skill_similarity) +
+      (p_distance_weight * CASE
+        WHEN vm.distance IS NOT NULL AND vm.max_distance > 0 THEN
+          GREATEST(0, 1 - (vm.distance / vm.max_distance))
+        ELSE 0.5
+      END) AS combined,
+      vm.latitude,
+      vm.longitude,
+      vm.location_name
+    FROM volunteer_matches vm
+    WHERE vm.distance IS NULL OR vm.distance <= vm.max_distance
+    ORDER BY combined DESC
+    LIMIT p_limit;
+  ELSE
+    -- Fallback to Haversine formula
+    RETURN QUERY
+    WITH project_info AS (
+      SELECT
+        p.id,
+        p.latitude as p_lat,
+        p.longitude as p_lon,
+        get_project_skill_vector(p.id) as project_vector
+      FROM projects p
+      WHERE p.id = p_project_id
+    ),
+    volunteer_matches AS (
+      SELECT
+        u.id,
+        u.name,
+        u.email,
+        u.latitude,
+        u.longitude,
+        u.location_name,
+        CASE
+          WHEN vsv.skill_vector IS NOT NULL AND pi.project_vector IS NOT NULL THEN
+            1 - (vsv.skill_vector <=> pi.project_vector)
+          ELSE 0
+        END AS skill_similarity,
+        CASE
+          WHEN u.latitude IS NOT NULL AND u.longitude IS NOT NULL
+           AND pi.p_lat IS NOT NULL AND pi.p_lon IS NOT NULL THEN
+            haversine_distance_km(u.latitude, u.longitude, pi.p_lat, pi.p_lon)
+          ELSE NULL
+        END AS distance,
+        COALESCE(u.max_travel_km::float, p_max_distance_km) AS max_distance
+      FROM users u
+      CROSS JOIN project_info pi
+      LEFT JOIN volunteer_skill_vectors vsv ON vsv.volunteer_id = u.id
+      WHERE u.role = 'volunteer'
+    )
+    SELECT
+      vm.id,
+      vm.name,
+      vm.email,
+      vm.skill_similarity,
+      COALESCE(vm.distance, 0),
+      (p_skill_weight * vm.skill_similarity) +
+      (p_distance_weight * CASE
+        WHEN vm.distance IS NOT NULL AND vm.max_distance > 0 THEN
+          GREATEST(0, 1 - (vm.distance / vm.max_distance))
+        ELSE 0.5
+      END) AS combined,
+      vm.latitude,
+      vm.longitude,
+      vm.location_name
+    FROM volunteer_matches vm
+    WHERE vm.distance IS NULL OR vm.distance <= vm.max_distance
+    ORDER BY combined DESC
+    LIMIT p_limit;
+  END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Drop triggers
+DROP TRIGGER IF EXISTS volunteer_locations_notify ON volunteer_locations;
+DROP TRIGGER IF EXISTS volunteer_locations_sync_primary ON volunteer_locations;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS sync_primary_volunteer_location();
+
+-- Drop tables (users keeps the mirrored primary location)
+DROP TABLE IF EXISTS volunteer_locations;
@@ -0,0 +1,220 @@
+-- Remote projects can be done from anywhere: matching ranks them on skills
+-- alone and offers them to volunteers wherever they are
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS is_remote BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_projects_is_remote ON projects(is_remote) WHERE is_remote;
+
+-- Batch matching: skip distance for remote projects
+CREATE OR REPLACE FUNCTION refresh_all_matches()
+RETURNS INTEGER AS $$
+DECLARE
+    match_count INTEGER := 0;
+BEGIN
+    -- Clear existing matches
+    DELETE FROM project_volunteer_matches;
+    
+    -- Insert new matches using tiered matching logic
+    -- Tier 1: Exclude already enrolled volunteers
+    -- Tier 2: Prioritize geo distance (with national exception)
+    -- Tier 3: Match skills last
+    INSERT INTO project_volunteer_matches (project_id, volunteer_id, skill_score, distance_km, combined_score, matched_skills)
+    WITH volunteer_project_combinations AS (
+        SELECT 
+            p.id as project_id,
+            u.id as volunteer_id,
+            p.latitude as p_lat,
+            p.longitude as p_lon,
+            nl.latitude as u_lat,
+            nl.longitude as u_lon,
+            p.location_name as p_location,
+            nl.location_name as u_location,
+            p.is_remote,
+            COALESCE(1 - (vsv.skill_vector <=> get_project_skill_vector(p.id)), 0) as skill_score,
+            -- Remote projects are distance-free; projects without a location have no distance
+            CASE WHEN p.is_remote THEN 0 ELSE COALESCE(nl.distance_km, 999999) END as distance_km,
+            ARRAY(
+                SELECT s.name 
+                FROM volunteer_skills vs 
+                JOIN project_skills ps ON vs.skill_id = ps.skill_id 
+                JOIN skills s ON vs.skill_id = s.id 
+                WHERE vs.volunteer_id = u.id AND ps.project_id = p.id AND vs.claimed = TRUE
+            ) as matched_skills
+        FROM projects p
+        CROSS JOIN users u
+        LEFT JOIN volunteer_skill_vectors vsv ON vsv.volunteer_id = u.id
+        -- Measure from the volunteer's saved location nearest the project
+        LEFT JOIN LATERAL (
+            SELECT vl.latitude, vl.longitude, vl.location_name,
+                   haversine_distance_km(vl.latitude, vl.longitude, p.latitude, p.longitude) AS distance_km
+            FROM volunteer_locations vl
+            WHERE vl.volunteer_id = u.id
+            ORDER BY distance_km NULLS LAST, vl.is_primary DESC
+            LIMIT 1
+        ) nl ON TRUE
+        WHERE p.status = 'active' 
+          AND u.role = 'volunteer'
+          -- Remote projects match volunteers regardless of location
+          AND (p.is_remote OR nl.latitude IS NOT NULL)
+          -- Tier 1: Exclude already enrolled volunteers
+          AND NOT EXISTS (
+              SELECT 1 FROM volunteer_enrollments ve 
+              WHERE ve.volunteer_id = u.id 
+                AND ve.project_id = p.id 
+                AND ve.status = 'active'
+          )
+    ),
+    tiered_matches AS (
+        SELECT 
+            project_id,
+            volunteer_id,
+            skill_score,
+            distance_km,
+            matched_skills,
+            -- Tier 2: Geo distance priority (with national exception)
+            CASE 
+                -- Remote projects rank on skills alone
+                WHEN is_remote THEN skill_score
+                -- National exception: if both locations contain "Canada" or same country, prioritize by skills
+                WHEN (p_location ILIKE '%Canada%' AND u_location ILIKE '%Canada%') 
+                  OR (p_location ILIKE '%Ontario%' AND u_location ILIKE '%Ontario%')
+                  OR (p_location ILIKE '%Alberta%' AND u_location ILIKE '%Alberta%')
+                  OR (p_location ILIKE '%British Columbia%' AND u_location ILIKE '%British Columbia%')
+                  OR (p_location ILIKE '%Quebec%' AND u_location ILIKE '%Quebec%')
+                  OR (p_location ILIKE '%Manitoba%' AND u_location ILIKE '%Manitoba%')
+                  OR (p_location ILIKE '%Saskatchewan%' AND u_location ILIKE '%Saskatchewan%')
+                  OR (p_location ILIKE '%Nova Scotia%' AND u_location ILIKE '%Nova Scotia%')
+                  OR (p_location ILIKE '%New Brunswick%' AND u_location ILIKE '%New Brunswick%')
+                  OR (p_location ILIKE '%Newfoundland%' AND u_location ILIKE '%Newfoundland%')
+                  OR (p_location ILIKE '%Prince Edward Island%' AND u_location ILIKE '%Prince Edward Island%')
+                  OR (p_location ILIKE '%Northwest Territories%' AND u_location ILIKE '%Northwest Territories%')
+                  OR (p_location ILIKE '%Yukon%' AND u_location ILIKE '%Yukon%')
+                  OR (p_location ILIKE '%Nunavut%' AND u_location ILIKE '%Nunavut%')
+                THEN 
+                    -- For national/same province: prioritize skills (70%) over distance (30%)
+                    0.7 * skill_score + 0.3 * GREATEST(0, 1 - (distance_km / 100))
+                ELSE
+                    -- For different regions: prioritize distance (60%) over skills (40%)
+                    0.4 * skill_score + 0.6 * GREATEST(0, 1 - (distance_km / 100))
+            END as combined_score
+        FROM volunteer_project_combinations
+        WHERE distance_km <= 500  -- Maximum 500km radius
+    )
+    SELECT 
+        project_id,
+        volunteer_id,
+        skill_score,
+        distance_km,
+        combined_score,
+        matched_skills
+    FROM tiered_matches
+    WHERE combined_score > 0.1  -- Minimum threshold for matches
+    ORDER BY combined_score DESC;
+    
+    GET DIAGNOSTICS match_count = ROW_COUNT;
+    
+    -- Update the updated_at timestamp
+    UPDATE project_volunteer_matches SET updated_at = NOW();
+    
+    RETURN match_count;
+END;
+$$ LANGUAGE plpgsql;
+
+-- On-demand matching: skip distance for remote projects
+CREATE OR REPLACE FUNCTION find_matching_volunteers(
+  p_project_id UUID,
+  p_skill_weight FLOAT DEFAULT 0.7,
+  p_distance_weight FLOAT DEFAULT 0.3,
+  p_max_distance_km FLOAT DEFAULT 100,
+  p_limit INTEGER DEFAULT 20
+)
+RETURNS TABLE (
+  volunteer_id UUID,
+  volunteer_name VARCHAR,
+  email VARCHAR,
+  skill_score FLOAT,
+  distance_km FLOAT,
+  combined_score FLOAT,
+  latitude DECIMAL,
+  longitude DECIMAL,
+  location_name VARCHAR
+) AS $$
+BEGIN
+  -- Volunteers have a handful of saved locations at most, so Haversine over
+  -- them is cheap with or without PostGIS
+  RETURN QUERY
+  WITH project_info AS (
+    SELECT
+      p.id,
+      p.latitude as p_lat,
+      p.longitude as p_lon,
+      p.is_remote,
+      get_project_skill_vector(p.id) as project_vector
+    FROM projects p
+    WHERE p.id = p_project_id
+  ),
+  volunteer_matches AS (
+    SELECT
+      u.id,
+      u.name,
+      u.email,
+      nl.latitude,
+      nl.longitude,
+      nl.location_name,
+      CASE
+        WHEN vsv.skill_vector IS NOT NULL AND pi.project_vector IS NOT NULL THEN
+          1 - (vsv.skill_vector <=> pi.project_vector)
+        ELSE 0
+      END AS skill_similarity,
+      -- Remote projects have no distance to score or cap
+      CASE WHEN pi.is_remote THEN NULL ELSE nl.distance END AS distance,
+      pi.is_remote,
+      COALESCE(u.max_travel_km::float, p_max_distance_km) AS max_distance
+    FROM users u
+    CROSS JOIN project_info pi
+    LEFT JOIN volunteer_skill_vectors vsv ON vsv.volunteer_id = u.id
+    -- The volunteer's saved location nearest the project
+    LEFT JOIN LATERAL (
+      SELECT
+        vl.latitude,
+        vl.longitude,
+        vl.location_name,
+        haversine_distance_km(vl.latitude, vl.longitude, pi.p_lat, pi.p_lon) AS distance
+      FROM volunteer_locations vl
+      WHERE vl.volunteer_id = u.id
+      ORDER BY distance NULLS LAST, vl.is_primary DESC
+      LIMIT 1
+    ) nl ON TRUE
+    WHERE u.role = 'volunteer'
+  )
+  SELECT
+    vm.id,
+    vm.name,
+    vm.email,
+    vm.skill_similarity,
+    COALESCE(vm.distance, 0),
+    CASE
+      WHEN vm.is_remote THEN vm.skill_similarity
+      ELSE (p_skill_weight * vm.skill_similarity) +
+        (p_distance_weight * CASE
+          WHEN vm.distance IS NOT NULL AND vm.max_distance > 0 THEN
+            GREATEST(0, 1 - (vm.distance / vm.max_distance))
+          ELSE 0.5
+        END)
+    END AS combined,
+    vm.latitude,
+    vm.longitude,
+    vm.location_name
+  FROM volunteer_matches vm
+  WHERE vm.distance IS NULL OR vm.distance <= vm.max_distance
+  ORDER BY combined DESC
+  LIMIT p_limit;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Toggling remote changes who matches, so drop cached results
+DROP TRIGGER IF EXISTS projects_is_remote_notify ON projects;
+CREATE TRIGGER projects_is_remote_notify
+AFTER UPDATE OF is_remote ON projects
+FOR EACH STATEMENT EXECUTE FUNCTION notify_matches_refreshed();
+
+COMMENT ON COLUMN projects.is_remote IS 'Remote/virtual project; distance is ignored in matching';
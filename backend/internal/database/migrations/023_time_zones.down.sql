@@ -0,0 +1,8 @@
+-- Restore bare UTC timestamps
+ALTER TABLE projects
+    ALTER COLUMN start_date TYPE TIMESTAMP USING start_date AT TIME ZONE 'UTC',
+    ALTER COLUMN end_date TYPE TIMESTAMP USING end_date AT TIME ZONE 'UTC';
+
+-- Drop columns
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
+ALTER TABLE projects DROP COLUMN IF EXISTS timezone;
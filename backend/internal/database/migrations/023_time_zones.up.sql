@@ -0,0 +1,12 @@
+-- Projects and users carry an IANA time zone so schedules render in local time
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- Project dates were bare timestamps; existing values were written as UTC
+ALTER TABLE projects
+    ALTER COLUMN start_date TYPE TIMESTAMPTZ USING start_date AT TIME ZONE 'UTC',
+    ALTER COLUMN end_date TYPE TIMESTAMPTZ USING end_date AT TIME ZONE 'UTC';
+
+-- Add comments
+COMMENT ON COLUMN projects.timezone IS 'IANA time zone the project''s schedule is expressed in';
+COMMENT ON COLUMN users.timezone IS 'IANA time zone of the user';
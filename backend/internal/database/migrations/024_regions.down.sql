@@ -0,0 +1,7 @@
+-- Drop views
+DROP VIEW IF EXISTS volunteer_regions;
+DROP VIEW IF EXISTS project_regions;
+
+-- Drop tables
+DROP TABLE IF EXISTS region_boundaries;
+DROP TABLE IF EXISTS regions;
@@ -0,0 +1,43 @@
+-- Administrative regions (cities, wards, regions) defined by boundary
+-- polygons. Uses the built-in geometric types so it works without PostGIS;
+-- coordinates are (longitude, latitude).
+CREATE TABLE IF NOT EXISTS regions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('city', 'ward', 'region')),
+    parent_id UUID REFERENCES regions(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A region may span several polygons (islands, detached wards)
+CREATE TABLE IF NOT EXISTS region_boundaries (
+    id SERIAL PRIMARY KEY,
+    region_id UUID NOT NULL REFERENCES regions(id) ON DELETE CASCADE,
+    boundary POLYGON NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_regions_parent_id ON regions(parent_id);
+CREATE INDEX IF NOT EXISTS idx_region_boundaries_region_id ON region_boundaries(region_id);
+CREATE INDEX IF NOT EXISTS idx_region_boundaries_boundary ON region_boundaries USING GIST(boundary);
+
+-- Region tags are derived from coordinates, so they never go stale when a
+-- project or volunteer moves or a boundary changes
+CREATE OR REPLACE VIEW project_regions AS
+SELECT DISTINCT p.id AS project_id, rb.region_id
+FROM projects p
+JOIN region_boundaries rb ON rb.boundary @> point(p.longitude::float8, p.latitude::float8)
+WHERE p.latitude IS NOT NULL AND p.longitude IS NOT NULL;
+
+-- Volunteers are tagged by their primary location
+CREATE OR REPLACE VIEW volunteer_regions AS
+SELECT DISTINCT u.id AS volunteer_id, rb.region_id
+FROM users u
+JOIN region_boundaries rb ON rb.boundary @> point(u.longitude::float8, u.latitude::float8)
+WHERE u.role = 'volunteer' AND u.latitude IS NOT NULL AND u.longitude IS NOT NULL;
+
+-- Add comments
+COMMENT ON TABLE regions IS 'Administrative areas used for region filters and analytics';
+COMMENT ON TABLE region_boundaries IS 'Boundary polygons of a region as (longitude, latitude) points';
+COMMENT ON VIEW project_regions IS 'Regions containing each located project';
+COMMENT ON VIEW volunteer_regions IS 'Regions containing each volunteer''s primary location';
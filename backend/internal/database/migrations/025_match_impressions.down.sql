@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_volunteer_enrollments_created_at;
+
+-- Drop tables
+DROP TABLE IF EXISTS match_impressions;
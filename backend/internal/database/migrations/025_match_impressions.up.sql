@@ -0,0 +1,19 @@
+-- Volunteers shown to coordinators as project matches: the top of the
+-- recruitment funnel. One row per pair keeps the table bounded.
+CREATE TABLE IF NOT EXISTS match_impressions (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    volunteer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    first_shown_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_shown_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    times_shown INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (project_id, volunteer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_match_impressions_first_shown_at ON match_impressions(first_shown_at);
+
+-- Funnel stages are bucketed by when enrollments were created
+CREATE INDEX IF NOT EXISTS idx_volunteer_enrollments_created_at ON volunteer_enrollments(created_at);
+
+-- Add comments
+COMMENT ON TABLE match_impressions IS 'Volunteer matches shown to project coordinators, for funnel metrics';
+COMMENT ON COLUMN match_impressions.first_shown_at IS 'When the volunteer first appeared in the project''s matches';
@@ -0,0 +1,3 @@
+-- Drop tables
+DROP TABLE IF EXISTS project_impact_entries;
+DROP TABLE IF EXISTS project_impact_metrics;
@@ -0,0 +1,29 @@
+-- Coordinator-defined KPIs for a project (trees planted, meals served)
+CREATE TABLE IF NOT EXISTS project_impact_metrics (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    unit VARCHAR(50) NOT NULL,
+    description TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(project_id, name)
+);
+
+-- Values recorded against a metric over time; totals are their sum
+CREATE TABLE IF NOT EXISTS project_impact_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    metric_id UUID NOT NULL REFERENCES project_impact_metrics(id) ON DELETE CASCADE,
+    value NUMERIC(14,2) NOT NULL CHECK (value >= 0),
+    recorded_on DATE NOT NULL,
+    note TEXT,
+    recorded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_impact_metrics_project_id ON project_impact_metrics(project_id);
+CREATE INDEX IF NOT EXISTS idx_project_impact_entries_metric_recorded_on ON project_impact_entries(metric_id, recorded_on);
+
+-- Add comments
+COMMENT ON TABLE project_impact_metrics IS 'Custom impact KPIs defined per project';
+COMMENT ON TABLE project_impact_entries IS 'Impact values recorded against a project metric';
@@ -0,0 +1,22 @@
+-- Interactions reported by the frontend (viewed match, clicked invite).
+-- Append-only and written in batches, so there are no foreign keys: events
+-- outlive the rows they mention and bad references must not fail a batch.
+CREATE TABLE IF NOT EXISTS client_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    user_id UUID,
+    project_id UUID,
+    volunteer_id UUID,
+    session_id VARCHAR(100),
+    properties JSONB NOT NULL DEFAULT '{}',
+    sample_rate REAL NOT NULL DEFAULT 1 CHECK (sample_rate > 0 AND sample_rate <= 1),
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_events_type_occurred_at ON client_events(event_type, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_client_events_project_volunteer ON client_events(project_id, volunteer_id);
+
+-- Add comments
+COMMENT ON TABLE client_events IS 'Frontend interaction events for product analytics';
+COMMENT ON COLUMN client_events.sample_rate IS 'Fraction of sessions kept when the event was recorded; weight counts by 1/sample_rate';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_volunteer_hours_worked_on;
+ALTER TABLE users DROP COLUMN IF EXISTS leaderboard_opt_in;
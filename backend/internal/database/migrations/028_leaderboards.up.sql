@@ -0,0 +1,8 @@
+-- Volunteers appear on public leaderboards only after opting in
+ALTER TABLE users ADD COLUMN IF NOT EXISTS leaderboard_opt_in BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Leaderboards rank hours within a period
+CREATE INDEX IF NOT EXISTS idx_volunteer_hours_worked_on ON volunteer_hours(worked_on);
+
+-- Add comments
+COMMENT ON COLUMN users.leaderboard_opt_in IS 'Whether the user is shown on volunteer leaderboards';
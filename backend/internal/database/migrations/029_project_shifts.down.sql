@@ -0,0 +1,3 @@
+-- Drop tables
+DROP TABLE IF EXISTS shift_signups;
+DROP TABLE IF EXISTS project_shifts;
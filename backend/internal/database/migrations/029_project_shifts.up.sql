@@ -0,0 +1,27 @@
+-- Time slots within a project that volunteers sign up for
+CREATE TABLE IF NOT EXISTS project_shifts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    title VARCHAR(255),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+-- Volunteers booked onto a shift; cancelling deletes the row
+CREATE TABLE IF NOT EXISTS shift_signups (
+    shift_id UUID NOT NULL REFERENCES project_shifts(id) ON DELETE CASCADE,
+    volunteer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (shift_id, volunteer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_shifts_project_starts_at ON project_shifts(project_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_shift_signups_volunteer_id ON shift_signups(volunteer_id);
+
+-- Add comments
+COMMENT ON TABLE project_shifts IS 'Scheduled shifts within a project, each with a volunteer capacity';
+COMMENT ON TABLE shift_signups IS 'Volunteers signed up for a shift';
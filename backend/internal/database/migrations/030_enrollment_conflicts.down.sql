@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_volunteer_enrollments_volunteer_enrolled;
+ALTER TABLE projects DROP COLUMN IF EXISTS block_schedule_conflicts;
@@ -0,0 +1,8 @@
+-- Projects can refuse enrollments that overlap a volunteer's other commitments
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS block_schedule_conflicts BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Conflict checks look up a volunteer's active enrollments
+CREATE INDEX IF NOT EXISTS idx_volunteer_enrollments_volunteer_enrolled ON volunteer_enrollments(volunteer_id) WHERE status = 'enrolled';
+
+-- Add comments
+COMMENT ON COLUMN projects.block_schedule_conflicts IS 'Reject enrollments whose dates overlap the volunteer''s other active enrollments';
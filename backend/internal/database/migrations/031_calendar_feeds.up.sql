@@ -0,0 +1,11 @@
+-- Secret tokens for volunteers' subscribable calendar feeds; one per
+-- volunteer, replaced on rotation
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    volunteer_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL, -- SHA-256 of the token
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP
+);
+
+-- Add comments
+COMMENT ON TABLE calendar_feed_tokens IS 'Tokens authorizing volunteers'' iCal feed URLs';
@@ -0,0 +1,3 @@
+-- Drop tables
+DROP TABLE IF EXISTS holidays;
+DROP TABLE IF EXISTS volunteer_availability_rules;
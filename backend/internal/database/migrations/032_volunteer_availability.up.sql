@@ -0,0 +1,28 @@
+-- Recurring availability, e.g. every Saturday 09:00-12:00 except holidays.
+-- Recurrence is stored as an RRULE subset and expanded in the API.
+CREATE TABLE IF NOT EXISTS volunteer_availability_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    volunteer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rrule VARCHAR(255) NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL, -- before start_time when the window runs past midnight
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    starts_on DATE NOT NULL,
+    except_dates DATE[] NOT NULL DEFAULT '{}',
+    skip_holidays BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_time <> start_time)
+);
+
+-- Dates rules with skip_holidays leave out
+CREATE TABLE IF NOT EXISTS holidays (
+    holiday_date DATE PRIMARY KEY,
+    name VARCHAR(100) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_volunteer_availability_rules_volunteer_id ON volunteer_availability_rules(volunteer_id);
+
+-- Add comments
+COMMENT ON TABLE volunteer_availability_rules IS 'Recurring windows when a volunteer is available';
+COMMENT ON COLUMN volunteer_availability_rules.rrule IS 'RFC 5545 RRULE subset: FREQ DAILY/WEEKLY/MONTHLY, INTERVAL, BYDAY, BYMONTHDAY, COUNT, UNTIL';
+COMMENT ON TABLE holidays IS 'Platform holiday calendar used by availability rules';
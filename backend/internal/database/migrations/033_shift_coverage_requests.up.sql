@@ -0,0 +1,19 @@
+-- Requests from booked volunteers for someone to take over their shift.
+-- A claim moves the signup to the claimer and records who covered.
+CREATE TABLE IF NOT EXISTS shift_coverage_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shift_id UUID NOT NULL REFERENCES project_shifts(id) ON DELETE CASCADE,
+    requester_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'claimed', 'cancelled')),
+    claimed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP
+);
+
+-- One open request per volunteer and shift
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shift_coverage_requests_open
+    ON shift_coverage_requests(shift_id, requester_id) WHERE status = 'open';
+
+-- Add comments
+COMMENT ON TABLE shift_coverage_requests IS 'Shift swap and coverage requests and who claimed them';
@@ -0,0 +1,9 @@
+-- Skills a volunteer must have claimed to be assigned to a shift
+CREATE TABLE IF NOT EXISTS shift_skills (
+    shift_id UUID NOT NULL REFERENCES project_shifts(id) ON DELETE CASCADE,
+    skill_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+    PRIMARY KEY (shift_id, skill_id)
+);
+
+-- Add comments
+COMMENT ON TABLE shift_skills IS 'Skills required of volunteers assigned to a shift';
@@ -0,0 +1,12 @@
+-- Drop triggers and functions
+DROP TRIGGER IF EXISTS volunteer_skills_activity ON volunteer_skills;
+DROP TRIGGER IF EXISTS volunteer_hours_activity ON volunteer_hours;
+DROP TRIGGER IF EXISTS volunteer_enrollments_activity ON volunteer_enrollments;
+DROP FUNCTION IF EXISTS notify_volunteer_activity();
+
+-- Drop tables
+DROP TABLE IF EXISTS volunteer_badges;
+
+ALTER TABLE volunteer_skills
+    DROP COLUMN IF EXISTS verified_at,
+    DROP COLUMN IF EXISTS verified_by;
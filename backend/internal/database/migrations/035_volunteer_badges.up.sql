@@ -0,0 +1,42 @@
+-- Coordinators can verify a volunteer's claimed skill
+ALTER TABLE volunteer_skills
+    ADD COLUMN IF NOT EXISTS verified_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP;
+
+-- Badges earned by volunteers; definitions live in internal/badges
+CREATE TABLE IF NOT EXISTS volunteer_badges (
+    volunteer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    badge VARCHAR(50) NOT NULL,
+    awarded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (volunteer_id, badge)
+);
+
+-- Publish volunteer activity so every API instance can award badges
+-- (see internal/database/listener.go). Payloads are JSON:
+-- {"table": "...", "volunteerId": "..."}.
+CREATE OR REPLACE FUNCTION notify_volunteer_activity() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('volunteer_activity', json_build_object('table', TG_TABLE_NAME, 'volunteerId', NEW.volunteer_id)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS volunteer_enrollments_activity ON volunteer_enrollments;
+CREATE TRIGGER volunteer_enrollments_activity
+AFTER INSERT OR UPDATE OF status ON volunteer_enrollments
+FOR EACH ROW WHEN (NEW.status = 'enrolled') EXECUTE FUNCTION notify_volunteer_activity();
+
+DROP TRIGGER IF EXISTS volunteer_hours_activity ON volunteer_hours;
+CREATE TRIGGER volunteer_hours_activity
+AFTER INSERT ON volunteer_hours
+FOR EACH ROW EXECUTE FUNCTION notify_volunteer_activity();
+
+DROP TRIGGER IF EXISTS volunteer_skills_activity ON volunteer_skills;
+CREATE TRIGGER volunteer_skills_activity
+AFTER INSERT OR UPDATE OF verified_at ON volunteer_skills
+FOR EACH ROW WHEN (NEW.verified_at IS NOT NULL) EXECUTE FUNCTION notify_volunteer_activity();
+
+-- Add comments
+COMMENT ON TABLE volunteer_badges IS 'Badges awarded to volunteers';
+COMMENT ON COLUMN volunteer_skills.verified_at IS 'When a coordinator verified the claimed skill; NULL if unverified';
+COMMENT ON FUNCTION notify_volunteer_activity IS 'Publishes enrollments, logged hours and skill verifications on the volunteer_activity channel';
@@ -0,0 +1,4 @@
+ALTER TABLE organization_settings DROP COLUMN IF EXISTS show_volunteer_ratings;
+
+-- Drop tables
+DROP TABLE IF EXISTS volunteer_ratings;
@@ -0,0 +1,21 @@
+-- Coordinator ratings of volunteers, one per completed enrollment
+CREATE TABLE IF NOT EXISTS volunteer_ratings (
+    enrollment_id UUID PRIMARY KEY REFERENCES volunteer_enrollments(id) ON DELETE CASCADE,
+    volunteer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    rated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_volunteer_ratings_volunteer_id ON volunteer_ratings(volunteer_id);
+CREATE INDEX IF NOT EXISTS idx_volunteer_ratings_project_id ON volunteer_ratings(project_id);
+
+-- Organizations opt in to showing rating scores in volunteer matches
+ALTER TABLE organization_settings ADD COLUMN IF NOT EXISTS show_volunteer_ratings BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Add comments
+COMMENT ON TABLE volunteer_ratings IS 'Coordinator ratings (1-5 plus tags) of volunteers per completed enrollment';
+COMMENT ON COLUMN organization_settings.show_volunteer_ratings IS 'Show volunteer rating scores to project coordinators in matches';
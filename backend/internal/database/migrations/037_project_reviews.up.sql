@@ -0,0 +1,23 @@
+-- Volunteer reviews of completed projects and their hosting organizations
+CREATE TABLE IF NOT EXISTS project_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    enrollment_id UUID NOT NULL UNIQUE REFERENCES volunteer_enrollments(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    volunteer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    project_rating SMALLINT NOT NULL CHECK (project_rating BETWEEN 1 AND 5),
+    organization_rating SMALLINT CHECK (organization_rating BETWEEN 1 AND 5),
+    comment TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'published' CHECK (status IN ('published', 'pending', 'removed')),
+    moderation_reason TEXT,
+    moderated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_reviews_project_id ON project_reviews(project_id);
+CREATE INDEX IF NOT EXISTS idx_project_reviews_pending ON project_reviews(created_at) WHERE status = 'pending';
+
+-- Add comments
+COMMENT ON TABLE project_reviews IS 'Volunteer ratings and comments on completed projects and their organizations';
+COMMENT ON COLUMN project_reviews.status IS 'published, pending (held for moderation) or removed by a moderator';
@@ -0,0 +1,4 @@
+-- Drop tables
+DROP TABLE IF EXISTS waiver_signatures;
+DROP TABLE IF EXISTS waiver_template_versions;
+DROP TABLE IF EXISTS waiver_templates;
@@ -0,0 +1,48 @@
+-- Waivers volunteers must sign before enrolling, either for every project of
+-- an organization or for a single project
+CREATE TABLE IF NOT EXISTS waiver_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    archived_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((organization_id IS NULL) <> (project_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_waiver_templates_organization_id ON waiver_templates(organization_id) WHERE archived_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_waiver_templates_project_id ON waiver_templates(project_id) WHERE archived_at IS NULL;
+
+-- Every version of a waiver's text, so signatures keep the document signed
+CREATE TABLE IF NOT EXISTS waiver_template_versions (
+    waiver_id UUID NOT NULL REFERENCES waiver_templates(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (waiver_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS waiver_signatures (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    waiver_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    volunteer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    signed_name VARCHAR(200) NOT NULL,
+    ip_address VARCHAR(64) NOT NULL,
+    signed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (waiver_id, version) REFERENCES waiver_template_versions(waiver_id, version) ON DELETE CASCADE,
+    UNIQUE (waiver_id, version, volunteer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_waiver_signatures_volunteer_id ON waiver_signatures(volunteer_id);
+
+-- Add comments
+COMMENT ON TABLE waiver_templates IS 'Waivers required before enrolling in an organization''s projects or a single project';
+COMMENT ON TABLE waiver_template_versions IS 'Text of every published version of a waiver';
+COMMENT ON TABLE waiver_signatures IS 'Volunteer e-signatures of a specific waiver version';
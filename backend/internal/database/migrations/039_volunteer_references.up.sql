@@ -0,0 +1,21 @@
+-- Coordinator references on volunteer profiles; public once the volunteer approves
+CREATE TABLE IF NOT EXISTS volunteer_references (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    volunteer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'declined', 'removed')),
+    responded_at TIMESTAMP,
+    moderated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (project_id, volunteer_id, author_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_volunteer_references_volunteer_id ON volunteer_references(volunteer_id);
+
+-- Add comments
+COMMENT ON TABLE volunteer_references IS 'References coordinators write for volunteers they worked with';
+COMMENT ON COLUMN volunteer_references.status IS 'pending (awaiting the volunteer), approved (public), declined by the volunteer, or removed by a moderator';
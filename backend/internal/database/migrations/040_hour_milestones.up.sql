@@ -0,0 +1,24 @@
+-- Logged-hour milestones volunteers have reached; thresholds are configured
+-- with HOUR_MILESTONES
+CREATE TABLE IF NOT EXISTS volunteer_milestones (
+    volunteer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hours INTEGER NOT NULL CHECK (hours > 0),
+    awarded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (volunteer_id, hours)
+);
+
+-- Record milestones already reached at the default thresholds so volunteers
+-- aren't congratulated for old hours when this is deployed
+INSERT INTO volunteer_milestones (volunteer_id, hours)
+SELECT totals.volunteer_id, m.hours
+FROM (
+    SELECT volunteer_id, SUM(hours) AS total
+    FROM volunteer_hours
+    GROUP BY volunteer_id
+) totals
+CROSS JOIN unnest(ARRAY[25, 100, 500]) AS m(hours)
+WHERE totals.total >= m.hours
+ON CONFLICT DO NOTHING;
+
+-- Add comments
+COMMENT ON TABLE volunteer_milestones IS 'Logged-hour thresholds each volunteer has reached';
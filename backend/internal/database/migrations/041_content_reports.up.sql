@@ -0,0 +1,23 @@
+-- Reports of inappropriate content, reviewed in the moderation queue
+CREATE TABLE IF NOT EXISTS content_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('project', 'message', 'profile')),
+    target_id UUID NOT NULL,
+    category VARCHAR(30) NOT NULL,
+    details TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'dismissed', 'actioned')),
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A reporter can only have one open report per piece of content
+CREATE UNIQUE INDEX IF NOT EXISTS idx_content_reports_open_reporter
+    ON content_reports(reporter_id, target_type, target_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_content_reports_open_target
+    ON content_reports(target_type, target_id) WHERE status = 'open';
+
+-- Add comments
+COMMENT ON TABLE content_reports IS 'User reports of inappropriate projects, team messages and profiles';
+COMMENT ON COLUMN content_reports.target_id IS 'projects.id, project_team_messages.id or users.id depending on target_type';
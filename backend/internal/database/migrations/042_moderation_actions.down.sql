@@ -0,0 +1,10 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS suspended_at,
+    DROP COLUMN IF EXISTS profile_hidden_at;
+ALTER TABLE project_team_messages DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE content_reports
+    DROP COLUMN IF EXISTS resolution_note,
+    DROP COLUMN IF EXISTS resolution_action;
+
+-- Drop tables
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,29 @@
+-- Audit log of privileged actions
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id VARCHAR(100) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log(target_type, target_id);
+
+-- How each report was resolved
+ALTER TABLE content_reports
+    ADD COLUMN IF NOT EXISTS resolution_action VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS resolution_note TEXT;
+
+-- Content moderators hid; hidden projects have status 'hidden'
+ALTER TABLE project_team_messages ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP;
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS profile_hidden_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP;
+
+-- Add comments
+COMMENT ON TABLE audit_log IS 'Who did what to which record, for privileged actions such as moderation';
+COMMENT ON COLUMN users.profile_hidden_at IS 'Set when a moderator hides the user''s public profile';
+COMMENT ON COLUMN users.suspended_at IS 'Set while the user is suspended; suspended users cannot log in or act';
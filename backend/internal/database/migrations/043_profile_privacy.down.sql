@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS public_total_hours,
+    DROP COLUMN IF EXISTS public_skill_categories,
+    DROP COLUMN IF EXISTS public_badges,
+    DROP COLUMN IF EXISTS public_name;
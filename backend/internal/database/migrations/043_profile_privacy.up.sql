@@ -0,0 +1,12 @@
+-- Fields volunteers choose to show on their public profile; all hidden by default
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS public_name BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS public_badges BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS public_skill_categories BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS public_total_hours BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Add comments
+COMMENT ON COLUMN users.public_name IS 'Whether the user''s name appears on their public profile';
+COMMENT ON COLUMN users.public_badges IS 'Whether the user''s badges appear on their public profile';
+COMMENT ON COLUMN users.public_skill_categories IS 'Whether the categories of the user''s claimed skills appear on their public profile';
+COMMENT ON COLUMN users.public_total_hours IS 'Whether the user''s total logged hours appear on their public profile';
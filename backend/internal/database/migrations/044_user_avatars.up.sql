@@ -0,0 +1,8 @@
+-- Uploaded avatars; the key locates the file in the blob store
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS avatar_key VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS avatar_url TEXT;
+
+-- Add comments
+COMMENT ON COLUMN users.avatar_key IS 'Blob store key of the user''s avatar image';
+COMMENT ON COLUMN users.avatar_url IS 'URL the user''s avatar is served from';
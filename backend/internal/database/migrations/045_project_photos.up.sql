@@ -0,0 +1,23 @@
+-- Photos in a project's gallery, e.g. before and after shots; files live in
+-- the blob store
+CREATE TABLE IF NOT EXISTS project_photos (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    photo_key VARCHAR(255) NOT NULL,
+    photo_url TEXT NOT NULL,
+    thumbnail_key VARCHAR(255) NOT NULL,
+    thumbnail_url TEXT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    caption VARCHAR(500),
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_photos_project_position ON project_photos(project_id, position);
+
+-- Add comments
+COMMENT ON TABLE project_photos IS 'Photos in a project''s gallery, shown in position order';
+COMMENT ON COLUMN project_photos.thumbnail_key IS 'Blob store key of the generated JPEG thumbnail';
@@ -0,0 +1,28 @@
+-- Private documents: volunteers' certifications and signed waivers attached
+-- to enrollments. Files live in the private document store and are only
+-- served through short-lived signed URLs.
+CREATE TABLE IF NOT EXISTS documents (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('certification', 'waiver')),
+    enrollment_id UUID REFERENCES volunteer_enrollments(id) ON DELETE SET NULL,
+    title VARCHAR(200) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    blob_key VARCHAR(255) NOT NULL,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_on DATE,
+    retain_until TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (kind <> 'waiver' OR retain_until IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_documents_owner_id ON documents(owner_id);
+CREATE INDEX IF NOT EXISTS idx_documents_enrollment_id ON documents(enrollment_id);
+CREATE INDEX IF NOT EXISTS idx_documents_retain_until ON documents(retain_until) WHERE retain_until IS NOT NULL;
+
+-- Add comments
+COMMENT ON TABLE documents IS 'Private certification and signed waiver files, served through signed URLs';
+COMMENT ON COLUMN documents.expires_on IS 'When a certification lapses';
+COMMENT ON COLUMN documents.retain_until IS 'When the retention job deletes the document; NULL keeps it until its owner deletes it';
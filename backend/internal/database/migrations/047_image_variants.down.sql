@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_project_photos_variants_pending;
+DROP INDEX IF EXISTS idx_users_avatar_variants_pending;
+
+ALTER TABLE project_photos
+    DROP COLUMN IF EXISTS variant_attempts,
+    DROP COLUMN IF EXISTS variants;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS avatar_variant_attempts,
+    DROP COLUMN IF EXISTS avatar_variants;
@@ -0,0 +1,20 @@
+-- Standard sizes of uploaded images, generated in the background; NULL
+-- until generated
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS avatar_variants JSONB,
+    ADD COLUMN IF NOT EXISTS avatar_variant_attempts INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE project_photos
+    ADD COLUMN IF NOT EXISTS variants JSONB,
+    ADD COLUMN IF NOT EXISTS variant_attempts INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_users_avatar_variants_pending ON users(updated_at)
+    WHERE avatar_key IS NOT NULL AND avatar_variants IS NULL;
+CREATE INDEX IF NOT EXISTS idx_project_photos_variants_pending ON project_photos(created_at)
+    WHERE variants IS NULL;
+
+-- Add comments
+COMMENT ON COLUMN users.avatar_variants IS 'URLs of the avatar''s generated sizes by size name';
+COMMENT ON COLUMN users.avatar_variant_attempts IS 'Failed attempts to generate the avatar''s sizes';
+COMMENT ON COLUMN project_photos.variants IS 'URLs of the photo''s generated display sizes by size name';
+COMMENT ON COLUMN project_photos.variant_attempts IS 'Failed attempts to generate the photo''s sizes';
@@ -0,0 +1,10 @@
+-- Drop tables
+DROP TABLE IF EXISTS quarantined_files;
+
+DROP INDEX IF EXISTS idx_documents_scan_pending;
+DROP INDEX IF EXISTS idx_project_photos_scan_pending;
+DROP INDEX IF EXISTS idx_users_avatar_scan_pending;
+
+ALTER TABLE documents DROP COLUMN IF EXISTS scan_status;
+ALTER TABLE project_photos DROP COLUMN IF EXISTS scan_status;
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_scan_status;
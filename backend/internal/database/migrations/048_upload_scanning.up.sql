@@ -0,0 +1,40 @@
+-- Malware scan state of uploaded files. Files stay pending, and are not
+-- shown to other users, until the scan job clears them; existing uploads
+-- are scanned too.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS avatar_scan_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (avatar_scan_status IN ('pending', 'clean', 'infected'));
+
+ALTER TABLE project_photos
+    ADD COLUMN IF NOT EXISTS scan_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (scan_status IN ('pending', 'clean', 'infected'));
+
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS scan_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (scan_status IN ('pending', 'clean', 'infected'));
+
+CREATE INDEX IF NOT EXISTS idx_users_avatar_scan_pending ON users(id)
+    WHERE avatar_key IS NOT NULL AND avatar_scan_status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_project_photos_scan_pending ON project_photos(id) WHERE scan_status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_documents_scan_pending ON documents(id) WHERE scan_status = 'pending';
+
+-- Infected uploads, moved out of the live stores into the quarantine store
+CREATE TABLE IF NOT EXISTS quarantined_files (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('avatar', 'photo', 'document')),
+    subject_id VARCHAR(100) NOT NULL,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    blob_key VARCHAR(255) NOT NULL,
+    threat TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_quarantined_files_created_at ON quarantined_files(created_at DESC);
+
+-- Add comments
+COMMENT ON COLUMN users.avatar_scan_status IS 'Malware scan state of the avatar: pending, clean or infected';
+COMMENT ON COLUMN project_photos.scan_status IS 'Malware scan state of the photo: pending, clean or infected';
+COMMENT ON COLUMN documents.scan_status IS 'Malware scan state of the file: pending, clean or infected';
+COMMENT ON TABLE quarantined_files IS 'Uploads the malware scanner flagged, kept in the quarantine store for review';
+COMMENT ON COLUMN quarantined_files.subject_id IS 'ID of the user, photo or document the file was uploaded for';
+COMMENT ON COLUMN quarantined_files.blob_key IS 'Key of the file in the quarantine store';
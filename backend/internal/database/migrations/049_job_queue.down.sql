@@ -0,0 +1,4 @@
+-- Drop tables
+DROP TRIGGER IF EXISTS jobs_queued ON jobs;
+DROP FUNCTION IF EXISTS notify_job_queued();
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,45 @@
+-- Background jobs, run by the worker pool in internal/jobs. Workers claim
+-- jobs with FOR UPDATE SKIP LOCKED, so any number of instances can share
+-- the queue.
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'running', 'succeeded', 'failed')),
+    unique_key VARCHAR(255),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL CHECK (max_attempts > 0),
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_runnable ON jobs(run_at) WHERE status IN ('queued', 'running');
+CREATE INDEX IF NOT EXISTS idx_jobs_status_created_at ON jobs(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_jobs_finished_at ON jobs(finished_at) WHERE status = 'succeeded';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_unique_queued ON jobs(kind, unique_key)
+    WHERE status = 'queued' AND unique_key IS NOT NULL;
+
+-- Wake idle workers on every instance when a job is queued (see
+-- internal/database/listener.go). Payloads are the job kind.
+CREATE OR REPLACE FUNCTION notify_job_queued() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('job_queued', NEW.kind);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS jobs_queued ON jobs;
+CREATE TRIGGER jobs_queued
+AFTER INSERT ON jobs
+FOR EACH ROW EXECUTE FUNCTION notify_job_queued();
+
+-- Add comments
+COMMENT ON TABLE jobs IS 'Queue of background work such as emails and skill vector refreshes';
+COMMENT ON COLUMN jobs.unique_key IS 'At most one queued job of a kind has each key; cleared once the job is claimed';
+COMMENT ON COLUMN jobs.run_at IS 'When the job may next run; pushed back after a failed attempt';
+COMMENT ON COLUMN jobs.locked_until IS 'When a running job is presumed abandoned and may be claimed again';
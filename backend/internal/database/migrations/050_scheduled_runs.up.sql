@@ -0,0 +1,17 @@
+-- Runs of recurring maintenance tasks, queued by the scheduler in
+-- internal/scheduler as background jobs. A task runs at most once per
+-- scheduled time however many instances are running.
+CREATE TABLE IF NOT EXISTS scheduled_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task VARCHAR(100) NOT NULL,
+    scheduled_for TIMESTAMP NOT NULL,
+    job_id UUID REFERENCES jobs(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (task, scheduled_for)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_runs_scheduled_for ON scheduled_runs(scheduled_for DESC);
+
+-- Add comments
+COMMENT ON TABLE scheduled_runs IS 'History of recurring maintenance task runs, kept for 90 days';
+COMMENT ON COLUMN scheduled_runs.job_id IS 'Job doing the run; cleared when a succeeded job is deleted';
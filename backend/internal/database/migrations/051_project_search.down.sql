@@ -0,0 +1,6 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS project_skills_changed_notify ON project_skills;
+DROP TRIGGER IF EXISTS projects_changed_notify ON projects;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS notify_project_changed();
@@ -0,0 +1,39 @@
+-- Broadcast project changes so the search indexer can mirror them into
+-- OpenSearch (see internal/search)
+
+-- pg_notify collapses identical payloads within a transaction, so editing
+-- a project and its skills together produces a single event
+CREATE OR REPLACE FUNCTION notify_project_changed() RETURNS trigger AS $$
+DECLARE
+    rec RECORD;
+    changed_id UUID;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    IF TG_TABLE_NAME = 'projects' THEN
+        changed_id := rec.id;
+    ELSE
+        changed_id := rec.project_id;
+    END IF;
+
+    PERFORM pg_notify('project_changed', changed_id::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS projects_changed_notify ON projects;
+CREATE TRIGGER projects_changed_notify
+AFTER INSERT OR UPDATE OR DELETE ON projects
+FOR EACH ROW EXECUTE FUNCTION notify_project_changed();
+
+DROP TRIGGER IF EXISTS project_skills_changed_notify ON project_skills;
+CREATE TRIGGER project_skills_changed_notify
+AFTER INSERT OR UPDATE OR DELETE ON project_skills
+FOR EACH ROW EXECUTE FUNCTION notify_project_changed();
+
+-- Add comments
+COMMENT ON FUNCTION notify_project_changed IS 'Publishes the ID of each changed project on the project_changed channel';
@@ -0,0 +1,6 @@
+-- Emails to a user are written in their language. New users take the
+-- language their client negotiated when they registered.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(10) NOT NULL DEFAULT 'en';
+
+-- Add comments
+COMMENT ON COLUMN users.locale IS 'Language the user receives emails in, e.g. en, fr or es';
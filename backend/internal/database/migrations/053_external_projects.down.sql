@@ -0,0 +1,7 @@
+COMMENT ON COLUMN organization_api_keys.scopes IS 'Granted scopes: projects:read, enrollments:write';
+DROP INDEX IF EXISTS idx_projects_external;
+ALTER TABLE projects
+    DROP CONSTRAINT IF EXISTS projects_external_id_check,
+    DROP COLUMN IF EXISTS external_url,
+    DROP COLUMN IF EXISTS external_id,
+    DROP COLUMN IF EXISTS external_source;
@@ -0,0 +1,19 @@
+-- Projects imported from other volunteer platforms by internal/connectors
+-- keep the platform's ID, so repeated deliveries update the same project
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS external_source VARCHAR(50);
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS external_id VARCHAR(255);
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS external_url TEXT;
+
+ALTER TABLE projects DROP CONSTRAINT IF EXISTS projects_external_id_check;
+ALTER TABLE projects ADD CONSTRAINT projects_external_id_check
+    CHECK ((external_source IS NULL) = (external_id IS NULL));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_external
+    ON projects(organization_id, external_source, external_id)
+    WHERE external_id IS NOT NULL;
+
+-- Add comments
+COMMENT ON COLUMN projects.external_source IS 'Connector the project was imported through, e.g. generic or schemaorg';
+COMMENT ON COLUMN projects.external_id IS 'ID of the project on the external platform, unique per organization and source';
+COMMENT ON COLUMN projects.external_url IS 'Listing of the project on the external platform';
+COMMENT ON COLUMN organization_api_keys.scopes IS 'Granted scopes: projects:read, projects:write, enrollments:write';
@@ -0,0 +1,14 @@
+-- Other names for skills, e.g. "First Aid" for "First Aid / CPR", so
+-- volunteer imports from spreadsheets can name skills the way orgs do
+CREATE TABLE IF NOT EXISTS skill_aliases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    skill_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+    alias VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_skill_aliases_alias ON skill_aliases(LOWER(alias));
+CREATE INDEX IF NOT EXISTS idx_skill_aliases_skill_id ON skill_aliases(skill_id);
+
+-- Add comments
+COMMENT ON TABLE skill_aliases IS 'Alternative names a skill is matched by, unique regardless of case';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_projects_sandbox;
+DROP INDEX IF EXISTS idx_users_sandbox;
+ALTER TABLE skills DROP COLUMN IF EXISTS sandbox;
+ALTER TABLE projects DROP COLUMN IF EXISTS sandbox;
+ALTER TABLE users DROP COLUMN IF EXISTS sandbox;
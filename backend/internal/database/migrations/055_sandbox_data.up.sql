@@ -0,0 +1,14 @@
+-- Synthetic data generated by sandbox mode (internal/sandbox) is flagged so
+-- it can be purged without touching real records. Enrollments, skills
+-- claims, locations and hours of sandbox users and projects go with them.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS sandbox BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS sandbox BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE skills ADD COLUMN IF NOT EXISTS sandbox BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_users_sandbox ON users(id) WHERE sandbox;
+CREATE INDEX IF NOT EXISTS idx_projects_sandbox ON projects(id) WHERE sandbox;
+
+-- Add comments
+COMMENT ON COLUMN users.sandbox IS 'Synthetic user generated by sandbox mode';
+COMMENT ON COLUMN projects.sandbox IS 'Synthetic project generated by sandbox mode';
+COMMENT ON COLUMN skills.sandbox IS 'Skill generated by sandbox mode because the catalogue was empty';
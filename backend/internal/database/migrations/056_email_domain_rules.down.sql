@@ -0,0 +1,3 @@
+-- Drop tables
+DROP TABLE IF EXISTS role_requests;
+DROP TABLE IF EXISTS email_domain_rules;
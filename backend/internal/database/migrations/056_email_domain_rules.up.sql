@@ -0,0 +1,35 @@
+-- Rules giving users who register with an email address at a domain a
+-- role other than volunteer, e.g. coordinator for @cityhall.gov. Rules
+-- that require approval leave the user a volunteer and queue a role
+-- request for platform admins to review.
+CREATE TABLE IF NOT EXISTS email_domain_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    domain VARCHAR(255) NOT NULL UNIQUE,
+    role VARCHAR(50) NOT NULL CHECK (role IN ('coordinator', 'admin')),
+    requires_approval BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (role <> 'admin' OR requires_approval)
+);
+
+CREATE TABLE IF NOT EXISTS role_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL,
+    rule_id UUID REFERENCES email_domain_rules(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    note TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_role_requests_pending_user ON role_requests(user_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_role_requests_status ON role_requests(status, created_at);
+
+-- Add comments
+COMMENT ON TABLE email_domain_rules IS 'Roles given to users registering with an email address at a domain';
+COMMENT ON COLUMN email_domain_rules.domain IS 'Lowercase domain, matched exactly against the part of the email after @';
+COMMENT ON COLUMN email_domain_rules.requires_approval IS 'Queue a role request instead of granting the role; always set for admin';
+COMMENT ON TABLE role_requests IS 'Roles awaiting platform admin approval, queued by email domain rules';
+COMMENT ON COLUMN role_requests.status IS 'Request status: pending, approved, rejected';
@@ -0,0 +1,5 @@
+-- Drop tables
+DROP TABLE IF EXISTS api_usage;
+
+ALTER TABLE organization_api_keys DROP COLUMN IF EXISTS monthly_quota;
+ALTER TABLE organization_api_keys DROP COLUMN IF EXISTS daily_quota;
@@ -0,0 +1,24 @@
+-- Per-key quota overrides; NULL falls back to the configured default
+ALTER TABLE organization_api_keys ADD COLUMN IF NOT EXISTS daily_quota INTEGER CHECK (daily_quota > 0);
+ALTER TABLE organization_api_keys ADD COLUMN IF NOT EXISTS monthly_quota INTEGER CHECK (monthly_quota > 0);
+
+-- API requests counted per consumer (an API key or a user) and UTC day,
+-- for quota enforcement and usage reports. Monthly usage is the sum of the
+-- month's days.
+CREATE TABLE IF NOT EXISTS api_usage (
+    consumer_type VARCHAR(10) NOT NULL CHECK (consumer_type IN ('api_key', 'user')),
+    consumer_id UUID NOT NULL,
+    day DATE NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    rejected BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (consumer_type, consumer_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_usage_day ON api_usage(day);
+
+-- Add comments
+COMMENT ON COLUMN organization_api_keys.daily_quota IS 'Requests allowed per UTC day; NULL uses API_KEY_DAILY_QUOTA';
+COMMENT ON COLUMN organization_api_keys.monthly_quota IS 'Requests allowed per calendar month; NULL uses API_KEY_MONTHLY_QUOTA';
+COMMENT ON TABLE api_usage IS 'API requests per consumer and UTC day; consumer_id is an organization_api_keys or users ID';
+COMMENT ON COLUMN api_usage.requests IS 'Requests served';
+COMMENT ON COLUMN api_usage.rejected IS 'Requests refused because a quota was used up';
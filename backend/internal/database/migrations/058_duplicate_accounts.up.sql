@@ -0,0 +1,24 @@
+-- Pairs of accounts the duplicate detection job thinks belong to the same
+-- person, for platform admins to merge or dismiss. user_id is the older
+-- account, the one a merge keeps by default.
+CREATE TABLE IF NOT EXISTS duplicate_candidates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    duplicate_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reasons TEXT[] NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'dismissed')),
+    detected_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    CHECK (user_id <> duplicate_id)
+);
+
+-- One candidate per pair, whichever way round
+CREATE UNIQUE INDEX IF NOT EXISTS idx_duplicate_candidates_pair
+    ON duplicate_candidates(LEAST(user_id, duplicate_id), GREATEST(user_id, duplicate_id));
+CREATE INDEX IF NOT EXISTS idx_duplicate_candidates_pending ON duplicate_candidates(detected_at) WHERE status = 'pending';
+
+-- Add comments
+COMMENT ON TABLE duplicate_candidates IS 'Accounts that look like the same person, awaiting a merge or dismissal';
+COMMENT ON COLUMN duplicate_candidates.reasons IS 'Why the pair was flagged: email, name_location';
+COMMENT ON COLUMN duplicate_candidates.status IS 'Candidate status: pending, dismissed; merged pairs are deleted with the duplicate';
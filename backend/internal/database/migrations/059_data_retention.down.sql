@@ -0,0 +1,7 @@
+-- Drop tables
+DROP TABLE IF EXISTS retention_runs;
+DROP TABLE IF EXISTS auth_events;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS anonymized_at,
+    DROP COLUMN IF EXISTS last_login_at;
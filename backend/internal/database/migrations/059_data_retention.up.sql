@@ -0,0 +1,35 @@
+-- Sign-ins and registrations, kept for security review until the retention
+-- job purges them. Failed sign-ins have no user.
+CREATE TABLE IF NOT EXISTS auth_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    event_type VARCHAR(30) NOT NULL CHECK (event_type IN ('login', 'login_failed', 'register')),
+    ip_address VARCHAR(64),
+    occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_events_user ON auth_events(user_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_auth_events_occurred_at ON auth_events(occurred_at);
+
+-- Inactivity is measured from the last sign-in
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMP;
+
+-- Each retention run and what it purged, or would have in a dry run
+CREATE TABLE IF NOT EXISTS retention_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    dry_run BOOLEAN NOT NULL,
+    results JSONB NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_retention_runs_started_at ON retention_runs(started_at);
+
+-- Add comments
+COMMENT ON TABLE auth_events IS 'Sign-ins, failed sign-ins and registrations';
+COMMENT ON COLUMN users.last_login_at IS 'Last successful sign-in';
+COMMENT ON COLUMN users.anonymized_at IS 'When the retention job replaced the account''s personal details';
+COMMENT ON TABLE retention_runs IS 'Retention policy runs with the rows each rule purged';
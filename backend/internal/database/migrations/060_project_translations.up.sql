@@ -0,0 +1,17 @@
+-- Project names and descriptions in other languages, so one project can be
+-- listed to each community in its own. A missing description falls back to
+-- the original.
+CREATE TABLE IF NOT EXISTS project_translations (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    locale VARCHAR(10) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, locale)
+);
+
+-- Add comments
+COMMENT ON TABLE project_translations IS 'Per-locale project names and descriptions served to clients that prefer the locale';
+COMMENT ON COLUMN project_translations.description IS 'Sanitized rich text; NULL shows the original description';
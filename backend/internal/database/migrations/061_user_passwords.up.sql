@@ -0,0 +1,6 @@
+-- Users sign in with a password; accounts created without one, such as
+-- imported volunteers, cannot sign in until one is set
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash VARCHAR(255);
+
+-- Add comments
+COMMENT ON COLUMN users.password_hash IS 'bcrypt hash of the user''s password; NULL when none is set';
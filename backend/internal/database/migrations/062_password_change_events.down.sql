@@ -0,0 +1,7 @@
+DELETE FROM auth_events WHERE event_type = 'password_changed';
+
+ALTER TABLE auth_events DROP CONSTRAINT IF EXISTS auth_events_event_type_check;
+ALTER TABLE auth_events ADD CONSTRAINT auth_events_event_type_check
+    CHECK (event_type IN ('login', 'login_failed', 'register'));
+
+COMMENT ON TABLE auth_events IS 'Sign-ins, failed sign-ins and registrations';
@@ -0,0 +1,7 @@
+-- Record password changes alongside sign-ins and registrations
+ALTER TABLE auth_events DROP CONSTRAINT IF EXISTS auth_events_event_type_check;
+ALTER TABLE auth_events ADD CONSTRAINT auth_events_event_type_check
+    CHECK (event_type IN ('login', 'login_failed', 'register', 'password_changed'));
+
+-- Add comments
+COMMENT ON TABLE auth_events IS 'Sign-ins, failed sign-ins, registrations and password changes';
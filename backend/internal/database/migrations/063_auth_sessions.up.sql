@@ -0,0 +1,21 @@
+-- Signed-in sessions. Each holds the hash of its current refresh token,
+-- which is replaced every time it renews the short-lived access token.
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_token_hash VARCHAR(64) NOT NULL UNIQUE,
+    user_agent TEXT,
+    ip_address VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_user ON auth_sessions(user_id, last_used_at);
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_expires_at ON auth_sessions(expires_at);
+
+-- Add comments
+COMMENT ON TABLE auth_sessions IS 'Signed-in sessions, renewed with rotating refresh tokens';
+COMMENT ON COLUMN auth_sessions.refresh_token_hash IS 'SHA-256 of the current refresh token; the token itself is never stored';
+COMMENT ON COLUMN auth_sessions.revoked_at IS 'Set when the session was signed out or revoked';
@@ -0,0 +1,16 @@
+-- Volunteers who register themselves verify their email address before
+-- they can enroll. Existing users, and users created by import, invitation
+-- or an admin, count as verified.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+-- One outstanding verification token per user; resending replaces it
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Add comments
+COMMENT ON COLUMN users.email_verified_at IS 'When the user proved they own their email address; NULL until a self-registered user verifies';
+COMMENT ON TABLE email_verification_tokens IS 'Outstanding email verification tokens, stored as SHA-256 hashes';
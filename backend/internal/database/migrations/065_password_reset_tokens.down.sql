@@ -0,0 +1,10 @@
+-- Drop tables
+DROP TABLE IF EXISTS password_reset_tokens;
+
+DELETE FROM auth_events WHERE event_type = 'password_reset';
+
+ALTER TABLE auth_events DROP CONSTRAINT IF EXISTS auth_events_event_type_check;
+ALTER TABLE auth_events ADD CONSTRAINT auth_events_event_type_check
+    CHECK (event_type IN ('login', 'login_failed', 'register', 'password_changed'));
+
+COMMENT ON TABLE auth_events IS 'Sign-ins, failed sign-ins, registrations and password changes';
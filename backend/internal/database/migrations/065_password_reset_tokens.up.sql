@@ -0,0 +1,17 @@
+-- One outstanding password reset per user; asking again replaces it, and
+-- resetting the password deletes it
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Record password resets alongside the other auth events
+ALTER TABLE auth_events DROP CONSTRAINT IF EXISTS auth_events_event_type_check;
+ALTER TABLE auth_events ADD CONSTRAINT auth_events_event_type_check
+    CHECK (event_type IN ('login', 'login_failed', 'register', 'password_changed', 'password_reset'));
+
+-- Add comments
+COMMENT ON TABLE password_reset_tokens IS 'Outstanding single-use password reset tokens, stored as SHA-256 hashes';
+COMMENT ON TABLE auth_events IS 'Sign-ins, failed sign-ins, registrations, password changes and resets';
@@ -0,0 +1,18 @@
+-- Accounts at external sign-in providers linked to users. The subject is the
+-- provider's stable ID for the account; the email is what the provider said
+-- when it was linked.
+CREATE TABLE IF NOT EXISTS user_identities (
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+
+-- Add comments
+COMMENT ON TABLE user_identities IS 'External sign-in provider accounts linked to users';
+COMMENT ON COLUMN user_identities.subject IS 'The provider''s stable ID for the account (the OIDC sub claim)';
@@ -0,0 +1,24 @@
+-- Responses to requests sent with an Idempotency-Key header, per consumer
+-- (an API key or a user), so a retried request gets the original response
+-- instead of running again. status is NULL while the first request runs.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    consumer_type VARCHAR(10) NOT NULL CHECK (consumer_type IN ('api_key', 'user')),
+    consumer_id UUID NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status INTEGER,
+    content_type VARCHAR(255),
+    body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP,
+    PRIMARY KEY (consumer_type, consumer_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
+
+-- Add comments
+COMMENT ON TABLE idempotency_keys IS 'Stored responses of requests sent with an Idempotency-Key header; consumer_id is an organization_api_keys or users ID';
+COMMENT ON COLUMN idempotency_keys.request_hash IS 'SHA-256 of the method, path and body, so a key reused for a different request is refused';
+COMMENT ON COLUMN idempotency_keys.status IS 'Response status; NULL while the first request is still running';
@@ -0,0 +1,16 @@
+-- Dates a volunteer is away, e.g. 2024-07-10 to 2024-07-14 on vacation.
+-- Blackouts override availability rules and are days in the volunteer's zone.
+CREATE TABLE IF NOT EXISTS volunteer_availability_blackouts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    volunteer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_on DATE NOT NULL,
+    ends_on DATE NOT NULL, -- inclusive
+    reason VARCHAR(200),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_on >= starts_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_volunteer_availability_blackouts_volunteer_id ON volunteer_availability_blackouts(volunteer_id, starts_on);
+
+-- Add comments
+COMMENT ON TABLE volunteer_availability_blackouts IS 'Date ranges when a volunteer is unavailable regardless of their rules';
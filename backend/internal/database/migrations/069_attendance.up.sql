@@ -0,0 +1,23 @@
+-- Check-ins against enrollments. Checking out logs the time in between as
+-- volunteer_hours, so attendance feeds the same totals as logged hours.
+CREATE TABLE IF NOT EXISTS attendance (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    enrollment_id UUID NOT NULL REFERENCES volunteer_enrollments(id) ON DELETE CASCADE,
+    volunteer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    shift_id UUID REFERENCES project_shifts(id) ON DELETE SET NULL,
+    checked_in_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    checked_in_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    checked_out_at TIMESTAMP,
+    checked_out_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    hours_id UUID REFERENCES volunteer_hours(id) ON DELETE SET NULL,
+    CHECK (checked_out_at IS NULL OR checked_out_at >= checked_in_at)
+);
+
+-- One open check-in per enrollment
+CREATE UNIQUE INDEX IF NOT EXISTS idx_attendance_open ON attendance(enrollment_id) WHERE checked_out_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_attendance_enrollment_id ON attendance(enrollment_id, checked_in_at);
+
+-- Add comments
+COMMENT ON TABLE attendance IS 'Volunteer check-ins and check-outs per enrollment';
+COMMENT ON COLUMN attendance.hours_id IS 'Hours logged at check-out; NULL while checked in or when too short to count';
@@ -0,0 +1,9 @@
+-- Waitlisted enrollments go back to awaiting a decision
+UPDATE volunteer_enrollments SET status = 'requested' WHERE status = 'waitlisted';
+
+DROP INDEX IF EXISTS idx_volunteer_enrollments_waitlist;
+ALTER TABLE volunteer_enrollments DROP COLUMN IF EXISTS waitlisted_at;
+
+ALTER TABLE volunteer_enrollments DROP CONSTRAINT IF EXISTS chk_enrollment_status;
+ALTER TABLE volunteer_enrollments ADD CONSTRAINT chk_enrollment_status
+    CHECK (status IN ('requested', 'invited', 'enrolled', 'tl_rejected', 'v_rejected', 'expired'));
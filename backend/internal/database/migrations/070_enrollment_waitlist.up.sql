@@ -0,0 +1,14 @@
+-- Enrollments in projects at max_volunteers wait on a waitlist and are
+-- enrolled oldest first as spots open. The check also gains 'expired',
+-- which stale requests and invitations are moved to.
+ALTER TABLE volunteer_enrollments DROP CONSTRAINT IF EXISTS chk_enrollment_status;
+ALTER TABLE volunteer_enrollments ADD CONSTRAINT chk_enrollment_status
+    CHECK (status IN ('requested', 'invited', 'enrolled', 'waitlisted', 'tl_rejected', 'v_rejected', 'expired'));
+
+ALTER TABLE volunteer_enrollments ADD COLUMN IF NOT EXISTS waitlisted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_volunteer_enrollments_waitlist ON volunteer_enrollments(project_id, waitlisted_at) WHERE status = 'waitlisted';
+
+-- Add comments
+COMMENT ON COLUMN volunteer_enrollments.status IS 'Enrollment state: requested, invited, enrolled, waitlisted, tl_rejected, v_rejected, expired';
+COMMENT ON COLUMN volunteer_enrollments.waitlisted_at IS 'When the enrollment joined the waitlist; the oldest is enrolled first';
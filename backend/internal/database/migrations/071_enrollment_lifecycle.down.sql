@@ -0,0 +1,9 @@
+-- Completed and no-show enrollments were enrolled; cancelled ones were given up
+UPDATE volunteer_enrollments SET status = 'enrolled' WHERE status IN ('completed', 'no_show');
+UPDATE volunteer_enrollments SET status = 'v_rejected' WHERE status = 'cancelled';
+
+ALTER TABLE volunteer_enrollments DROP CONSTRAINT IF EXISTS chk_enrollment_status;
+ALTER TABLE volunteer_enrollments ADD CONSTRAINT chk_enrollment_status
+    CHECK (status IN ('requested', 'invited', 'enrolled', 'waitlisted', 'tl_rejected', 'v_rejected', 'expired'));
+
+COMMENT ON COLUMN volunteer_enrollments.status IS 'Enrollment state: requested, invited, enrolled, waitlisted, tl_rejected, v_rejected, expired';
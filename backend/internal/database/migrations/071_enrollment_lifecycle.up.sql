@@ -0,0 +1,11 @@
+-- Enrollments end as completed at close-out, cancelled when a place is given
+-- up, or no_show when the volunteer never turned up. Enrollments already
+-- marked complete move to the new status.
+ALTER TABLE volunteer_enrollments DROP CONSTRAINT IF EXISTS chk_enrollment_status;
+ALTER TABLE volunteer_enrollments ADD CONSTRAINT chk_enrollment_status
+    CHECK (status IN ('requested', 'invited', 'enrolled', 'waitlisted', 'completed', 'cancelled', 'no_show', 'tl_rejected', 'v_rejected', 'expired'));
+
+UPDATE volunteer_enrollments SET status = 'completed' WHERE status = 'enrolled' AND completed_at IS NOT NULL;
+
+-- Add comments
+COMMENT ON COLUMN volunteer_enrollments.status IS 'Enrollment state: requested, invited, enrolled, waitlisted, completed, cancelled, no_show, tl_rejected, v_rejected, expired';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_volunteer_enrollments_invitation_expiry;
+ALTER TABLE volunteer_enrollments DROP COLUMN IF EXISTS reminded_at;
+ALTER TABLE volunteer_enrollments DROP COLUMN IF EXISTS expires_at;
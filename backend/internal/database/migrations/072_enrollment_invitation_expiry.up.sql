@@ -0,0 +1,13 @@
+-- Invitations expire at a set time, and volunteers are reminded once
+-- shortly before. Open invitations get the default 30 day window from
+-- when they were last updated.
+ALTER TABLE volunteer_enrollments ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE volunteer_enrollments ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE volunteer_enrollments SET expires_at = updated_at + INTERVAL '30 days' WHERE status = 'invited' AND expires_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_volunteer_enrollments_invitation_expiry ON volunteer_enrollments(expires_at) WHERE status = 'invited';
+
+-- Add comments
+COMMENT ON COLUMN volunteer_enrollments.expires_at IS 'When an open invitation expires';
+COMMENT ON COLUMN volunteer_enrollments.reminded_at IS 'When the volunteer was reminded of the invitation before it expires';
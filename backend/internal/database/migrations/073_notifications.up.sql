@@ -0,0 +1,23 @@
+-- In-app notifications of enrollment answers, project status changes and new
+-- matches, rendered in the recipient's language and also emailed to them
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL,
+    title VARCHAR(300) NOT NULL,
+    body TEXT NOT NULL,
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+    enrollment_id UUID REFERENCES volunteer_enrollments(id) ON DELETE CASCADE,
+    dedupe_key VARCHAR(200),
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+-- Notifications with a dedupe key are sent to a user once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_dedupe ON notifications(user_id, dedupe_key) WHERE dedupe_key IS NOT NULL;
+
+-- Add comments
+COMMENT ON TABLE notifications IS 'In-app notifications per user; each is also emailed when created';
+COMMENT ON COLUMN notifications.dedupe_key IS 'Identifies notifications a user should only get once, such as a match with a project';
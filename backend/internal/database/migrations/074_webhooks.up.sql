@@ -0,0 +1,33 @@
+-- Callback URLs platform admins register to hear about events, and every
+-- delivery made to them, kept for debugging
+CREATE TABLE IF NOT EXISTS webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    url VARCHAR(2000) NOT NULL,
+    events TEXT[] NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_events ON webhooks USING GIN (events) WHERE active;
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, created_at DESC);
+
+-- Add comments
+COMMENT ON TABLE webhooks IS 'Callback URLs sent events they subscribe to, signed with their secret';
+COMMENT ON COLUMN webhooks.events IS 'Events the webhook receives, e.g. enrollment.accepted';
+COMMENT ON COLUMN webhooks.secret IS 'Key the HMAC-SHA256 signature of each delivery is made with';
+COMMENT ON TABLE webhook_deliveries IS 'Each event sent to a webhook, with the outcome of its last attempt';
@@ -0,0 +1,13 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS projects_mark_stale ON projects;
+DROP TRIGGER IF EXISTS project_skills_mark_stale ON project_skills;
+DROP TRIGGER IF EXISTS users_mark_stale ON users;
+DROP TRIGGER IF EXISTS volunteer_locations_mark_stale ON volunteer_locations;
+DROP TRIGGER IF EXISTS volunteer_skills_mark_stale ON volunteer_skills;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS mark_matches_stale();
+
+-- Drop tables
+DROP TABLE IF EXISTS match_stale;
+DROP TABLE IF EXISTS match_recomputations;
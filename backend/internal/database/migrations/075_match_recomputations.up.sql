@@ -0,0 +1,92 @@
+-- Runs of the Go-side batch matcher filling project_volunteer_matches, so
+-- their progress can be followed from any instance
+CREATE TABLE IF NOT EXISTS match_recomputations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('all', 'stale')),
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed')),
+    total INTEGER NOT NULL DEFAULT 0,
+    processed INTEGER NOT NULL DEFAULT 0,
+    matches INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_match_recomputations_started_at ON match_recomputations(started_at DESC);
+
+-- Projects and volunteers whose precomputed matches are out of date. Rows
+-- are written in the transaction making the change, so none is missed.
+CREATE TABLE IF NOT EXISTS match_stale (
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('project', 'volunteer')),
+    id UUID NOT NULL,
+    marked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (kind, id)
+);
+
+-- Mark the project or volunteer a row belongs to stale, and tell the API
+-- (see internal/database/listener.go). pg_notify collapses identical
+-- payloads within a transaction, so bulk edits produce one event per kind.
+CREATE OR REPLACE FUNCTION mark_matches_stale() RETURNS trigger AS $$
+DECLARE
+    rec RECORD;
+    stale_kind VARCHAR(20);
+    stale_id UUID;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    IF TG_TABLE_NAME IN ('volunteer_skills', 'volunteer_locations') THEN
+        stale_kind := 'volunteer';
+        stale_id := rec.volunteer_id;
+    ELSIF TG_TABLE_NAME = 'users' THEN
+        stale_kind := 'volunteer';
+        stale_id := rec.id;
+    ELSIF TG_TABLE_NAME = 'projects' THEN
+        stale_kind := 'project';
+        stale_id := rec.id;
+    ELSE
+        stale_kind := 'project';
+        stale_id := rec.project_id;
+    END IF;
+
+    INSERT INTO match_stale (kind, id) VALUES (stale_kind, stale_id)
+    ON CONFLICT (kind, id) DO UPDATE SET marked_at = NOW();
+    PERFORM pg_notify('matches_stale', stale_kind);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS volunteer_skills_mark_stale ON volunteer_skills;
+CREATE TRIGGER volunteer_skills_mark_stale
+AFTER INSERT OR UPDATE OR DELETE ON volunteer_skills
+FOR EACH ROW EXECUTE FUNCTION mark_matches_stale();
+
+DROP TRIGGER IF EXISTS volunteer_locations_mark_stale ON volunteer_locations;
+CREATE TRIGGER volunteer_locations_mark_stale
+AFTER INSERT OR UPDATE OR DELETE ON volunteer_locations
+FOR EACH ROW EXECUTE FUNCTION mark_matches_stale();
+
+DROP TRIGGER IF EXISTS users_mark_stale ON users;
+CREATE TRIGGER users_mark_stale
+AFTER UPDATE OF role, max_travel_km ON users
+FOR EACH ROW EXECUTE FUNCTION mark_matches_stale();
+
+DROP TRIGGER IF EXISTS project_skills_mark_stale ON project_skills;
+CREATE TRIGGER project_skills_mark_stale
+AFTER INSERT OR UPDATE OR DELETE ON project_skills
+FOR EACH ROW EXECUTE FUNCTION mark_matches_stale();
+
+DROP TRIGGER IF EXISTS projects_mark_stale ON projects;
+CREATE TRIGGER projects_mark_stale
+AFTER INSERT OR UPDATE OF status, latitude, longitude, is_remote ON projects
+FOR EACH ROW EXECUTE FUNCTION mark_matches_stale();
+
+-- Add comments
+COMMENT ON TABLE match_recomputations IS 'Runs of the batch matcher, with how many projects and volunteers they have scored so far';
+COMMENT ON COLUMN match_recomputations.total IS 'Projects and volunteers to score';
+COMMENT ON COLUMN match_recomputations.matches IS 'Matches saved for the projects and volunteers scored so far';
+COMMENT ON TABLE match_stale IS 'Projects and volunteers whose precomputed matches the batch matcher has to recompute';
+COMMENT ON FUNCTION mark_matches_stale IS 'Marks precomputed matches stale and publishes it on the matches_stale channel';
@@ -0,0 +1,8 @@
+-- Matched skills go back to a list of names
+ALTER TABLE project_volunteer_matches ADD COLUMN IF NOT EXISTS matched_skill_names TEXT[] DEFAULT '{}';
+
+UPDATE project_volunteer_matches
+SET matched_skill_names = ARRAY(SELECT jsonb_array_elements(matched_skills)->>'name');
+
+ALTER TABLE project_volunteer_matches DROP COLUMN IF EXISTS matched_skills;
+ALTER TABLE project_volunteer_matches RENAME COLUMN matched_skill_names TO matched_skills;
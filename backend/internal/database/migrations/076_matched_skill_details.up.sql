@@ -0,0 +1,25 @@
+-- Precomputed matches keep each matched skill with the volunteer's score
+-- and the project's weight, so searches needn't look them up
+ALTER TABLE project_volunteer_matches ADD COLUMN IF NOT EXISTS matched_skill_details JSONB NOT NULL DEFAULT '[]';
+
+UPDATE project_volunteer_matches m
+SET matched_skill_details = COALESCE((
+    SELECT jsonb_agg(jsonb_build_object(
+        'id', s.id,
+        'name', s.name,
+        'volunteerScore', vs.score,
+        'projectWeight', ps.weight
+    ) ORDER BY s.name, s.id)
+    FROM volunteer_skills vs
+    JOIN project_skills ps ON ps.skill_id = vs.skill_id
+    JOIN skills s ON s.id = vs.skill_id
+    WHERE vs.volunteer_id = m.volunteer_id
+      AND ps.project_id = m.project_id
+      AND vs.claimed = TRUE
+), '[]');
+
+ALTER TABLE project_volunteer_matches DROP COLUMN IF EXISTS matched_skills;
+ALTER TABLE project_volunteer_matches RENAME COLUMN matched_skill_details TO matched_skills;
+
+-- Add comments
+COMMENT ON COLUMN project_volunteer_matches.matched_skills IS 'Skills the volunteer shares with the project, by name: [{id, name, volunteerScore, projectWeight}]';
@@ -0,0 +1 @@
+ALTER TABLE project_volunteer_matches DROP COLUMN IF EXISTS has_required_skills;
@@ -0,0 +1,20 @@
+-- Whether each precomputed match's volunteer claims every skill the project
+-- requires, so searches can leave out those who don't
+ALTER TABLE project_volunteer_matches ADD COLUMN IF NOT EXISTS has_required_skills BOOLEAN NOT NULL DEFAULT TRUE;
+
+UPDATE project_volunteer_matches m
+SET has_required_skills = NOT EXISTS (
+    SELECT 1
+    FROM project_skills ps
+    WHERE ps.project_id = m.project_id
+      AND ps.required
+      AND NOT EXISTS (
+          SELECT 1 FROM volunteer_skills vs
+          WHERE vs.volunteer_id = m.volunteer_id
+            AND vs.skill_id = ps.skill_id
+            AND vs.claimed = TRUE
+      )
+);
+
+-- Add comments
+COMMENT ON COLUMN project_volunteer_matches.has_required_skills IS 'Whether the volunteer claims every skill the project requires';
@@ -0,0 +1 @@
+ALTER TABLE organization_settings DROP COLUMN IF EXISTS allow_self_signup;
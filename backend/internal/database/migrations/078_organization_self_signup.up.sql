@@ -0,0 +1,6 @@
+-- Organizations opt in to volunteers joining them by registering through
+-- their tenant; otherwise only invitations grant membership
+ALTER TABLE organization_settings ADD COLUMN IF NOT EXISTS allow_self_signup BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Add comments
+COMMENT ON COLUMN organization_settings.allow_self_signup IS 'Volunteers registering through the organization''s tenant join it';
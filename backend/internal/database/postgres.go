@@ -1,22 +1,39 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"time"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type PostgresDB struct {
 	*sql.DB
+	connStr   string
+	listeners []*Listener
 }
 
 func NewPostgresDB(host, port, user, password, dbname string) (*PostgresDB, error) {
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		host, port, user, password, dbname)
 
-	db, err := sql.Open("postgres", connStr)
+	// Queries made with a context inside a trace get spans of their own
+	db, err := otelsql.Open("postgres", connStr,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitConnectorConnect: true,
+			SpanFilter: func(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) bool {
+				return trace.SpanFromContext(ctx).SpanContext().IsValid()
+			},
+		}),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -31,25 +48,14 @@ func NewPostgresDB(host, port, user, password, dbname string) (*PostgresDB, erro
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &PostgresDB{db}, nil
+	return &PostgresDB{DB: db, connStr: connStr}, nil
 }
 
-func (db *PostgresDB) InitSchema() error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS users (
-		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-		email VARCHAR(255) UNIQUE NOT NULL,
-		name VARCHAR(255) NOT NULL,
-		role VARCHAR(50) NOT NULL DEFAULT 'volunteer',
-		profile_complete BOOLEAN NOT NULL DEFAULT FALSE,
-		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
-	CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
-	`
-
-	_, err := db.Exec(schema)
-	return err
+// Close stops any notification listeners before closing the connection pool
+func (db *PostgresDB) Close() error {
+	for _, l := range db.listeners {
+		l.Close()
+	}
+	db.listeners = nil
+	return db.DB.Close()
 }
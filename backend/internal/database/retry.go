@@ -0,0 +1,121 @@
+package database
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Retry policy for idempotent reads
+const (
+	maxReadAttempts  = 4
+	baseRetryBackoff = 50 * time.Millisecond
+	maxRetryBackoff  = 1 * time.Second
+)
+
+// TransientError wraps a failure that is likely to succeed if attempted again
+// (serialization conflicts, dropped connections, failover). Writes surface it
+// to callers instead of retrying, since a write may already have been applied.
+type TransientError struct {
+	Err      error
+	Attempts int
+}
+
+func (e *TransientError) Error() string {
+	return fmt.Sprintf("transient database error after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransient reports whether err is a database failure worth retrying
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var te *TransientError
+	if errors.As(err, &te) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "40001", // serialization_failure
+			"40P01", // deadlock_detected
+			"57P01", // admin_shutdown
+			"57P02", // crash_shutdown
+			"57P03", // cannot_connect_now
+			"25006": // read_only_sql_transaction (writing to a demoted primary during failover)
+			return true
+		}
+		// Class 08: connection exceptions
+		return pqErr.Code.Class() == "08"
+	}
+
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// lib/pq reports some connection drops as plain strings
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "connection reset by peer") ||
+		strings.Contains(msg, "broken pipe") ||
+		strings.Contains(msg, "bad connection")
+}
+
+// WithReadRetry runs an idempotent read, retrying transient failures with
+// jittered exponential backoff. Non-transient errors are returned unchanged so
+// callers can keep comparing against sentinels such as sql.ErrNoRows.
+func WithReadRetry(fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= maxReadAttempts; attempt++ {
+		err = fn()
+		if err == nil || !IsTransient(err) {
+			return err
+		}
+		if attempt < maxReadAttempts {
+			time.Sleep(retryBackoff(attempt))
+		}
+	}
+	return &TransientError{Err: err, Attempts: maxReadAttempts}
+}
+
+// WithWriteGuard runs a write exactly once. Transient failures are returned as
+// *TransientError so handlers can tell clients the request may be retried.
+func WithWriteGuard(fn func() error) error {
+	err := fn()
+	if err != nil && IsTransient(err) {
+		return &TransientError{Err: err, Attempts: 1}
+	}
+	return err
+}
+
+// retryBackoff returns a full-jitter exponential delay for the given attempt
+func retryBackoff(attempt int) time.Duration {
+	backoff := baseRetryBackoff << (attempt - 1)
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	return time.Duration(rand.Int63n(int64(backoff)))
+}
@@ -0,0 +1,79 @@
+package digests
+
+import (
+	"database/sql"
+	"log/slog"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/notifications"
+)
+
+// CoordinatorDigestJob is the kind of background job that runs
+// SendCoordinatorDigests
+const CoordinatorDigestJob = "digests.coordinator"
+
+// Service emails periodic summaries
+type Service struct {
+	db     *sql.DB
+	mailer notifications.Mailer
+}
+
+func NewService(db *sql.DB, mailer notifications.Mailer) *Service {
+	return &Service{db: db, mailer: mailer}
+}
+
+// SendCoordinatorDigests emails every coordinator with pending enrollment
+// requests a summary by project, and returns how many were sent. Failed
+// emails are logged; the rest are still sent.
+func (s *Service) SendCoordinatorDigests() (int, error) {
+	query := `
+		SELECT u.id, u.email, u.name, u.locale, p.name, COUNT(*),
+		       EXTRACT(DAY FROM NOW() - MIN(ve.created_at))::int
+		FROM volunteer_enrollments ve
+		JOIN projects p ON p.id = ve.project_id
+		JOIN users u ON u.id = p.coordinator_id
+		WHERE ve.status = 'requested'
+		  AND p.status <> 'hidden'
+		GROUP BY u.id, u.email, u.name, u.locale, p.id, p.name
+		ORDER BY u.id, p.name
+	`
+
+	var digests []notifications.CoordinatorDigest
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		digests = nil
+		lastID := ""
+		for rows.Next() {
+			var id, email, name, locale string
+			var p notifications.DigestProject
+			if err := rows.Scan(&id, &email, &name, &locale, &p.Name, &p.Pending, &p.OldestDays); err != nil {
+				return err
+			}
+			if id != lastID {
+				digests = append(digests, notifications.CoordinatorDigest{To: email, Locale: locale, Name: name})
+				lastID = id
+			}
+			d := &digests[len(digests)-1]
+			d.Projects = append(d.Projects, p)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, d := range digests {
+		if err := s.mailer.Send(notifications.RenderCoordinatorDigest(d)); err != nil {
+			slog.Error("Coordinator digest error", "to", d.To, "error", err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
@@ -0,0 +1,480 @@
+package documents
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/civic-weave/backend/internal/blobstore"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/scanning"
+)
+
+var (
+	ErrDocumentNotFound   = errors.New("document not found")
+	ErrEnrollmentNotFound = errors.New("enrollment not found")
+	ErrVolunteerNotFound  = errors.New("volunteer not found")
+	ErrForbidden          = errors.New("only the volunteer or the project's coordinators can add waivers to an enrollment")
+	ErrNotOwner           = errors.New("only the document's owner can delete it")
+	ErrRetained           = errors.New("signed waivers are kept until their retention period ends")
+	ErrUnsupportedFile    = errors.New("document must be a PDF, PNG or JPEG file")
+	ErrFileTooLarge       = errors.New("document must be at most 10 MB")
+	ErrInvalidTitle       = errors.New("title is required and must be at most 200 characters")
+	ErrInvalidExpiry      = errors.New("expiresOn must be a date in YYYY-MM-DD format")
+	ErrInvalidSignature   = errors.New("document link is invalid or has expired")
+	ErrNotScanned         = errors.New("document is waiting for its malware scan")
+	ErrQuarantined        = errors.New("document failed its malware scan and was quarantined")
+)
+
+const (
+	// MaxBytes is the largest document accepted
+	MaxBytes       = 10 << 20
+	maxTitleLength = 200
+	// URLTTL is how long a signed document link works
+	URLTTL = 15 * time.Minute
+	// WaiverRetention is how long signed waivers are kept after upload
+	WaiverRetention = 7 * 365 * 24 * time.Hour
+	// CertificationGrace is how long certifications are kept after they expire
+	CertificationGrace = 90 * 24 * time.Hour
+)
+
+// extensions are the accepted file types by sniffed content type
+var extensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+}
+
+// canAccess is true when the user in $1 may see document d: its owner, and
+// whoever can manage a project the document relates to. Waivers relate to
+// their enrollment's project; certifications to every project the owner has
+// an active or pending enrollment in.
+const canAccess = `(
+	d.owner_id::text = $1
+	OR EXISTS (SELECT 1 FROM users WHERE id::text = $1 AND role = 'admin')
+	OR EXISTS (
+		SELECT 1
+		FROM volunteer_enrollments ve
+		JOIN projects p ON p.id = ve.project_id
+		WHERE (ve.id = d.enrollment_id
+		       OR (d.kind = 'certification' AND ve.volunteer_id = d.owner_id AND ve.status NOT IN ('rejected', 'withdrawn')))
+		  AND (p.coordinator_id::text = $1 OR EXISTS (
+		      SELECT 1 FROM organization_members om
+		      WHERE om.organization_id = p.organization_id
+		        AND om.user_id::text = $1
+		        AND om.status = 'active'
+		        AND om.role IN ('owner', 'admin', 'coordinator')
+		  ))
+	)
+)`
+
+const documentColumns = `
+	d.id, d.owner_id, d.kind, d.enrollment_id, d.title, d.file_name, d.content_type,
+	d.size_bytes, d.uploaded_by, TO_CHAR(d.expires_on, 'YYYY-MM-DD'), d.retain_until, d.scan_status, d.created_at
+`
+
+func scanDocument(row interface{ Scan(...interface{}) error }, doc *models.Document) error {
+	return row.Scan(
+		&doc.ID,
+		&doc.OwnerID,
+		&doc.Kind,
+		&doc.EnrollmentID,
+		&doc.Title,
+		&doc.FileName,
+		&doc.ContentType,
+		&doc.SizeBytes,
+		&doc.UploadedBy,
+		&doc.ExpiresOn,
+		&doc.RetainUntil,
+		&doc.ScanStatus,
+		&doc.CreatedAt,
+	)
+}
+
+type Service struct {
+	db              *sql.DB
+	store           blobstore.Store
+	secret          []byte
+	scanningService *scanning.Service
+}
+
+// NewService keeps documents in store, which must not be publicly served,
+// and signs document links with secret
+func NewService(db *sql.DB, store blobstore.Store, secret []byte, scanningService *scanning.Service) *Service {
+	return &Service{db: db, store: store, secret: secret, scanningService: scanningService}
+}
+
+// UploadCertification stores a certification for the volunteer. It is kept
+// until CertificationGrace after it expires, or until the volunteer deletes
+// it.
+func (s *Service) UploadCertification(ctx context.Context, volunteerID, uploadedBy string, req models.UploadDocumentRequest, data []byte) (*models.Document, error) {
+	var expiresOn *string
+	var retainUntil *time.Time
+	if req.ExpiresOn != "" {
+		date, err := time.Parse("2006-01-02", req.ExpiresOn)
+		if err != nil {
+			return nil, ErrInvalidExpiry
+		}
+		retain := date.Add(CertificationGrace)
+		expiresOn, retainUntil = &req.ExpiresOn, &retain
+	}
+
+	var exists bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM users WHERE id::text = $1)`, volunteerID).Scan(&exists)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrVolunteerNotFound
+	}
+
+	return s.upload(ctx, volunteerID, models.DocumentCertification, nil, uploadedBy, req, data, expiresOn, retainUntil)
+}
+
+// UploadWaiver attaches a signed waiver to an enrollment. The volunteer and
+// the project's coordinators can upload it; it is kept for WaiverRetention.
+func (s *Service) UploadWaiver(ctx context.Context, enrollmentID, uploadedBy string, req models.UploadDocumentRequest, data []byte) (*models.Document, error) {
+	var volunteerID string
+	var allowed bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(`
+			SELECT ve.volunteer_id,
+			       ve.volunteer_id::text = $1
+			       OR EXISTS (SELECT 1 FROM users WHERE id::text = $1 AND role = 'admin')
+			       OR p.coordinator_id::text = $1
+			       OR EXISTS (
+			           SELECT 1 FROM organization_members om
+			           WHERE om.organization_id = p.organization_id
+			             AND om.user_id::text = $1
+			             AND om.status = 'active'
+			             AND om.role IN ('owner', 'admin', 'coordinator')
+			       )
+			FROM volunteer_enrollments ve
+			JOIN projects p ON p.id = ve.project_id
+			WHERE ve.id::text = $2
+		`, uploadedBy, enrollmentID).Scan(&volunteerID, &allowed)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrEnrollmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrForbidden
+	}
+
+	retainUntil := time.Now().Add(WaiverRetention)
+	return s.upload(ctx, volunteerID, models.DocumentWaiver, &enrollmentID, uploadedBy, req, data, nil, &retainUntil)
+}
+
+func (s *Service) upload(ctx context.Context, ownerID, kind string, enrollmentID *string, uploadedBy string, req models.UploadDocumentRequest, data []byte, expiresOn *string, retainUntil *time.Time) (*models.Document, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" || len([]rune(title)) > maxTitleLength {
+		return nil, ErrInvalidTitle
+	}
+	if len(data) > MaxBytes {
+		return nil, ErrFileTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedFile
+	}
+
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	key := "documents/" + ownerID + "/" + hex.EncodeToString(suffix) + ext
+	if err := s.store.Put(ctx, key, contentType, data); err != nil {
+		return nil, err
+	}
+
+	var doc models.Document
+	err := database.WithWriteGuard(func() error {
+		return scanDocument(s.db.QueryRow(`
+			INSERT INTO documents AS d (owner_id, kind, enrollment_id, title, file_name, content_type, size_bytes,
+			                            blob_key, uploaded_by, expires_on, retain_until)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING `+documentColumns,
+			ownerID, kind, enrollmentID, title, fileName(req.FileName, ext), contentType, len(data),
+			key, uploadedBy, expiresOn, retainUntil,
+		), &doc)
+	})
+	if err != nil {
+		s.deleteBlob(ctx, key)
+		return nil, err
+	}
+	s.scanningService.Wake()
+
+	return &doc, nil
+}
+
+// GetVolunteerDocuments lists the volunteer's documents the user may see,
+// newest first
+func (s *Service) GetVolunteerDocuments(volunteerID, userID string) ([]models.Document, error) {
+	return s.queryDocuments(`
+		SELECT `+documentColumns+`
+		FROM documents d
+		WHERE d.owner_id::text = $2 AND `+canAccess+`
+		ORDER BY d.created_at DESC, d.id
+	`, userID, volunteerID)
+}
+
+// GetEnrollmentDocuments lists the waivers attached to an enrollment that
+// the user may see, newest first
+func (s *Service) GetEnrollmentDocuments(enrollmentID, userID string) ([]models.Document, error) {
+	return s.queryDocuments(`
+		SELECT `+documentColumns+`
+		FROM documents d
+		WHERE d.enrollment_id::text = $2 AND `+canAccess+`
+		ORDER BY d.created_at DESC, d.id
+	`, userID, enrollmentID)
+}
+
+// GetDocument returns a document the user may see, with a signed link to
+// its file once it has passed the malware scan. Documents the user can't
+// see are reported as not found.
+func (s *Service) GetDocument(documentID, userID string) (*models.Document, error) {
+	doc, _, err := s.getDocument(documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if doc.ScanStatus != models.ScanClean {
+		return doc, nil
+	}
+
+	expires := time.Now().Add(URLTTL).Truncate(time.Second)
+	doc.URL = "/api/documents/" + doc.ID + "/content?expires=" + strconv.FormatInt(expires.Unix(), 10) +
+		"&signature=" + s.sign(doc.ID, expires.Unix())
+	doc.URLExpiresAt = &expires
+	return doc, nil
+}
+
+// Open verifies a signed link and opens the document's file; the caller
+// closes it
+func (s *Service) Open(ctx context.Context, documentID, expires, signature string) (*models.Document, io.ReadCloser, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt ||
+		!hmac.Equal([]byte(signature), []byte(s.sign(documentID, expiresAt))) {
+		return nil, nil, ErrInvalidSignature
+	}
+
+	var doc models.Document
+	var key string
+	err = database.WithReadRetry(func() error {
+		return s.db.QueryRow(`
+			SELECT `+documentColumns+`, d.blob_key
+			FROM documents d
+			WHERE d.id::text = $1
+		`, documentID).Scan(
+			&doc.ID, &doc.OwnerID, &doc.Kind, &doc.EnrollmentID, &doc.Title, &doc.FileName, &doc.ContentType,
+			&doc.SizeBytes, &doc.UploadedBy, &doc.ExpiresOn, &doc.RetainUntil, &doc.ScanStatus, &doc.CreatedAt, &key,
+		)
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil, ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	switch doc.ScanStatus {
+	case models.ScanPending:
+		return nil, nil, ErrNotScanned
+	case models.ScanInfected:
+		return nil, nil, ErrQuarantined
+	}
+
+	file, err := s.store.Get(ctx, key)
+	if err == blobstore.ErrNotFound {
+		return nil, nil, ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return &doc, file, nil
+}
+
+// Delete removes a document and its file. Only the owner can delete, and
+// signed waivers only once their retention period is over or when they
+// were quarantined.
+func (s *Service) Delete(ctx context.Context, documentID, userID string) error {
+	doc, key, err := s.getDocument(documentID, userID)
+	if err != nil {
+		return err
+	}
+	if doc.OwnerID != userID {
+		return ErrNotOwner
+	}
+	if doc.Kind == models.DocumentWaiver && doc.ScanStatus != models.ScanInfected &&
+		doc.RetainUntil != nil && doc.RetainUntil.After(time.Now()) {
+		return ErrRetained
+	}
+
+	err = database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(`DELETE FROM documents WHERE id = $1`, doc.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deleteBlob(ctx, key)
+	return nil
+}
+
+// PurgeExpired deletes every document past its retention date, returning
+// how many were removed. Each row is deleted by exactly one caller, so
+// instances can run it concurrently.
+func (s *Service) PurgeExpired(ctx context.Context) (int, error) {
+	var keys []string
+	err := database.WithWriteGuard(func() error {
+		rows, err := s.db.Query(`
+			DELETE FROM documents
+			WHERE retain_until <= NOW()
+			RETURNING blob_key
+		`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		keys = nil
+		for rows.Next() {
+			var key string
+			if err := rows.Scan(&key); err != nil {
+				return err
+			}
+			keys = append(keys, key)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range keys {
+		s.deleteBlob(ctx, key)
+	}
+	return len(keys), nil
+}
+
+// RunRetention purges expired documents now and then every interval until
+// ctx is cancelled
+func (s *Service) RunRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.PurgeExpired(ctx); err != nil {
+			logging.FromContext(ctx).Error("Document retention error", "error", err)
+		} else if n > 0 {
+			logging.FromContext(ctx).Info("Document retention removed documents", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// getDocument returns a document the user may see with its blob key
+func (s *Service) getDocument(documentID, userID string) (*models.Document, string, error) {
+	var doc models.Document
+	var key string
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(`
+			SELECT `+documentColumns+`, d.blob_key
+			FROM documents d
+			WHERE d.id::text = $2 AND `+canAccess,
+			userID, documentID,
+		).Scan(
+			&doc.ID, &doc.OwnerID, &doc.Kind, &doc.EnrollmentID, &doc.Title, &doc.FileName, &doc.ContentType,
+			&doc.SizeBytes, &doc.UploadedBy, &doc.ExpiresOn, &doc.RetainUntil, &doc.ScanStatus, &doc.CreatedAt, &key,
+		)
+	})
+	if err == sql.ErrNoRows {
+		return nil, "", ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return &doc, key, nil
+}
+
+func (s *Service) queryDocuments(query string, args ...interface{}) ([]models.Document, error) {
+	var docs []models.Document
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		docs = []models.Document{}
+		for rows.Next() {
+			var doc models.Document
+			if err := scanDocument(rows, &doc); err != nil {
+				return err
+			}
+			docs = append(docs, doc)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+func (s *Service) sign(documentID string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(documentID + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// fileName keeps the base of the uploaded name without control characters,
+// falling back to a generic name with the sniffed extension
+func fileName(name, ext string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, path.Base(strings.ReplaceAll(name, `\`, "/")))
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "document" + ext
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255])
+	}
+	return name
+}
+
+// deleteBlob removes a file that is no longer referenced; failures only
+// leave an orphaned file behind, so they are logged
+func (s *Service) deleteBlob(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		logging.FromContext(ctx).Error("Delete document file error", "key", key, "error", err)
+	}
+}
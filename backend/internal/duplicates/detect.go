@@ -0,0 +1,216 @@
+package duplicates
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/matching"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+// nearbyKm is how close two accounts with the same name must live to be
+// flagged
+const nearbyKm = 5.0
+
+// gmailDomains ignore dots in the local part and are the same mailbox
+var gmailDomains = map[string]bool{"gmail.com": true, "googlemail.com": true}
+
+type account struct {
+	id           string
+	email        string
+	name         string
+	latitude     *float64
+	longitude    *float64
+	locationName *string
+	createdAt    time.Time
+}
+
+// older reports whether a registered before b
+func (a *account) older(b *account) bool {
+	if !a.createdAt.Equal(b.createdAt) {
+		return a.createdAt.Before(b.createdAt)
+	}
+	return a.id < b.id
+}
+
+// nearby reports whether both accounts have locations within nearbyKm of
+// each other, or the same location name
+func (a *account) nearby(b *account) bool {
+	if a.latitude != nil && a.longitude != nil && b.latitude != nil && b.longitude != nil {
+		return matching.HaversineDistance(*a.latitude, *a.longitude, *b.latitude, *b.longitude) <= nearbyKm
+	}
+	return a.locationName != nil && b.locationName != nil &&
+		strings.TrimSpace(*a.locationName) != "" &&
+		strings.EqualFold(strings.TrimSpace(*a.locationName), strings.TrimSpace(*b.locationName))
+}
+
+// normalizeEmail maps the variants of an address that reach the same
+// mailbox to one form: it lowercases it and drops a +tag, and for Gmail
+// also dots and the googlemail.com domain
+func normalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	local, domain := email[:at], email[at+1:]
+	if plus := strings.Index(local, "+"); plus > 0 {
+		local = local[:plus]
+	}
+	if gmailDomains[domain] {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+	return local + "@" + domain
+}
+
+// normalizeName lowercases a name and sorts its words, ignoring
+// punctuation, so "Smith, Jane" and "jane smith" match
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+type pair struct {
+	user, duplicate *account
+	reasons         []string
+}
+
+// Detect flags pairs of accounts whose email addresses reach the same
+// mailbox, or that share a name and live close together, and returns how
+// many are pending. Pending pairs no longer detected are dropped; dismissed
+// pairs stay dismissed. Sandbox accounts are ignored.
+func (s *Service) Detect(ctx context.Context) (int, error) {
+	var accounts []*account
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, `
+			SELECT id, email, name, latitude, longitude, location_name, created_at
+			FROM users
+			WHERE NOT sandbox
+		`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		accounts = nil
+		for rows.Next() {
+			var a account
+			if err := rows.Scan(&a.id, &a.email, &a.name, &a.latitude, &a.longitude, &a.locationName, &a.createdAt); err != nil {
+				return err
+			}
+			accounts = append(accounts, &a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	pairs := findPairs(accounts)
+	userIDs := make([]string, len(pairs))
+	duplicateIDs := make([]string, len(pairs))
+	reasons := make([]string, len(pairs))
+	for i, p := range pairs {
+		userIDs[i], duplicateIDs[i], reasons[i] = p.user.id, p.duplicate.id, strings.Join(p.reasons, ",")
+	}
+
+	var pending int
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		_, err = tx.Exec(`
+			DELETE FROM duplicate_candidates dc
+			WHERE dc.status = 'pending'
+			  AND NOT EXISTS (
+				SELECT 1 FROM unnest($1::uuid[], $2::uuid[]) AS p(user_id, duplicate_id)
+				WHERE p.user_id = dc.user_id AND p.duplicate_id = dc.duplicate_id
+			  )
+		`, pq.Array(userIDs), pq.Array(duplicateIDs))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO duplicate_candidates (user_id, duplicate_id, reasons)
+			SELECT user_id, duplicate_id, string_to_array(reasons, ',')
+			FROM unnest($1::uuid[], $2::uuid[], $3::text[]) AS p(user_id, duplicate_id, reasons)
+			ON CONFLICT (LEAST(user_id, duplicate_id), GREATEST(user_id, duplicate_id)) DO UPDATE
+			SET reasons = EXCLUDED.reasons
+			WHERE duplicate_candidates.status = 'pending'
+		`, pq.Array(userIDs), pq.Array(duplicateIDs), pq.Array(reasons))
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(`SELECT COUNT(*) FROM duplicate_candidates WHERE status = 'pending'`).Scan(&pending)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return pending, nil
+}
+
+// findPairs groups accounts by normalized email and by normalized name,
+// and pairs up each group's accounts, the older one first
+func findPairs(accounts []*account) []*pair {
+	byEmail := map[string][]*account{}
+	byName := map[string][]*account{}
+	for _, a := range accounts {
+		email := normalizeEmail(a.email)
+		byEmail[email] = append(byEmail[email], a)
+		if name := normalizeName(a.name); name != "" {
+			byName[name] = append(byName[name], a)
+		}
+	}
+
+	found := map[[2]string]*pair{}
+	var pairs []*pair
+	add := func(a, b *account, reason string) {
+		if b.older(a) {
+			a, b = b, a
+		}
+		key := [2]string{a.id, b.id}
+		p, ok := found[key]
+		if !ok {
+			p = &pair{user: a, duplicate: b}
+			found[key] = p
+			pairs = append(pairs, p)
+		}
+		p.reasons = append(p.reasons, reason)
+	}
+
+	for _, group := range byEmail {
+		for i := range group {
+			for j := i + 1; j < len(group); j++ {
+				add(group[i], group[j], models.DuplicateReasonEmail)
+			}
+		}
+	}
+	for _, group := range byName {
+		for i := range group {
+			for j := i + 1; j < len(group); j++ {
+				if group[i].nearby(group[j]) {
+					add(group[i], group[j], models.DuplicateReasonNameLocation)
+				}
+			}
+		}
+	}
+	return pairs
+}
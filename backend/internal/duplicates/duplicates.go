@@ -0,0 +1,130 @@
+package duplicates
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/jobs"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+// DetectJob is the kind of background job that runs Detect
+const DetectJob = "duplicates.detect"
+
+var (
+	ErrCandidateNotFound = errors.New("duplicate candidate not found")
+	ErrAccountNotFound   = errors.New("account not found")
+	ErrSameAccount       = errors.New("an account cannot be merged into itself")
+)
+
+// Service flags accounts that look like the same person registering twice,
+// and merges them
+type Service struct {
+	db          *sql.DB
+	jobsService *jobs.Service
+}
+
+// NewService registers the detection job with jobsService, which must not
+// be running yet
+func NewService(db *sql.DB, jobsService *jobs.Service) *Service {
+	s := &Service{db: db, jobsService: jobsService}
+	jobsService.Register(DetectJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			n, err := s.Detect(ctx)
+			if n > 0 {
+				slog.Info("Found possible duplicate accounts", "count", n)
+			}
+			return err
+		},
+		Timeout: 10 * time.Minute,
+	})
+	return s
+}
+
+// QueueDetect queues detection, unless it is already waiting
+func (s *Service) QueueDetect() (*models.Job, error) {
+	return s.jobsService.EnqueueUnique(DetectJob, "all", nil)
+}
+
+// accountSelect selects the DuplicateAccount columns of the users row
+// aliased as alias
+func accountSelect(alias string) string {
+	return fmt.Sprintf(`
+		%[1]s.id, %[1]s.email, %[1]s.name, %[1]s.role, %[1]s.location_name,
+		(SELECT COUNT(*) FROM volunteer_skills vs WHERE vs.volunteer_id = %[1]s.id),
+		(SELECT COUNT(*) FROM volunteer_enrollments ve WHERE ve.volunteer_id = %[1]s.id),
+		(SELECT COALESCE(SUM(vh.hours), 0) FROM volunteer_hours vh WHERE vh.volunteer_id = %[1]s.id),
+		%[1]s.created_at`, alias)
+}
+
+// GetPendingCandidates lists flagged pairs awaiting a merge or dismissal,
+// most recently detected first
+func (s *Service) GetPendingCandidates() ([]models.DuplicateCandidate, error) {
+	query := `
+		SELECT dc.id, dc.reasons, dc.status, dc.detected_at,
+		` + accountSelect("u") + `,
+		` + accountSelect("d") + `
+		FROM duplicate_candidates dc
+		JOIN users u ON u.id = dc.user_id
+		JOIN users d ON d.id = dc.duplicate_id
+		WHERE dc.status = 'pending'
+		ORDER BY dc.detected_at DESC, dc.id
+	`
+
+	candidates := []models.DuplicateCandidate{}
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		candidates = candidates[:0]
+		for rows.Next() {
+			var c models.DuplicateCandidate
+			u, d := &c.User, &c.Duplicate
+			err := rows.Scan(
+				&c.ID, pq.Array(&c.Reasons), &c.Status, &c.DetectedAt,
+				&u.ID, &u.Email, &u.Name, &u.Role, &u.LocationName, &u.Skills, &u.Enrollments, &u.Hours, &u.CreatedAt,
+				&d.ID, &d.Email, &d.Name, &d.Role, &d.LocationName, &d.Skills, &d.Enrollments, &d.Hours, &d.CreatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			candidates = append(candidates, c)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// DismissCandidate marks a pair as different people, so detection no longer
+// flags it
+func (s *Service) DismissCandidate(candidateID, reviewedBy string) error {
+	var res sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		res, err = s.db.Exec(`
+			UPDATE duplicate_candidates
+			SET status = 'dismissed', reviewed_by = $2, reviewed_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+		`, candidateID, reviewedBy)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrCandidateNotFound
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package duplicates
+
+import (
+	"log/slog"
+
+	"github.com/civic-weave/backend/internal/audit"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/matching"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+// mergeStatements move the duplicate account's ($2) records to the kept
+// account ($1). Where the kept account already has a record the duplicate's
+// would clash with, the kept account's wins and the duplicate's is deleted
+// along with the duplicate. Hours move before enrollments so that hours
+// logged against a clashing enrollment move to the kept account's.
+var mergeStatements = []struct {
+	query string
+	// count is the AccountMerge field the statement's affected rows add to
+	count func(*models.AccountMerge) *int64
+}{
+	// Skills both claimed keep the stronger claim
+	{query: `
+		UPDATE volunteer_skills k
+		SET claimed = k.claimed OR d.claimed,
+		    score = GREATEST(k.score, d.score),
+		    verified_by = COALESCE(k.verified_by, d.verified_by),
+		    verified_at = COALESCE(k.verified_at, d.verified_at),
+		    updated_at = CURRENT_TIMESTAMP
+		FROM volunteer_skills d
+		WHERE k.volunteer_id = $1 AND d.volunteer_id = $2 AND d.skill_id = k.skill_id
+	`},
+	{query: `
+		UPDATE volunteer_skills SET volunteer_id = $1
+		WHERE volunteer_id = $2
+		  AND skill_id NOT IN (SELECT skill_id FROM volunteer_skills WHERE volunteer_id = $1)
+	`, count: func(m *models.AccountMerge) *int64 { return &m.Skills }},
+	{query: `
+		UPDATE volunteer_hours h
+		SET volunteer_id = $1,
+		    enrollment_id = COALESCE(
+		        (SELECT e.id FROM volunteer_enrollments e WHERE e.volunteer_id = $1 AND e.project_id = h.project_id),
+		        h.enrollment_id)
+		WHERE h.volunteer_id = $2
+	`, count: func(m *models.AccountMerge) *int64 { return &m.Hours }},
+	// Check-ins follow the hours, unless both accounts are checked in to
+	// the project
+	{query: `
+		UPDATE attendance a
+		SET volunteer_id = $1,
+		    enrollment_id = COALESCE(
+		        (SELECT e.id FROM volunteer_enrollments e WHERE e.volunteer_id = $1 AND e.project_id = a.project_id),
+		        a.enrollment_id)
+		WHERE a.volunteer_id = $2
+		  AND NOT (a.checked_out_at IS NULL AND EXISTS (
+		      SELECT 1 FROM attendance k
+		      WHERE k.volunteer_id = $1 AND k.project_id = a.project_id AND k.checked_out_at IS NULL))
+	`},
+	{query: `
+		UPDATE volunteer_enrollments SET volunteer_id = $1
+		WHERE volunteer_id = $2
+		  AND project_id NOT IN (SELECT project_id FROM volunteer_enrollments WHERE volunteer_id = $1)
+	`, count: func(m *models.AccountMerge) *int64 { return &m.Enrollments }},
+	{query: `UPDATE volunteer_enrollments SET initiated_by = $1 WHERE initiated_by = $2`},
+	// Reviews follow the enrollments that moved
+	{query: `
+		UPDATE project_reviews SET volunteer_id = $1
+		WHERE volunteer_id = $2
+		  AND enrollment_id IN (SELECT id FROM volunteer_enrollments WHERE volunteer_id = $1)
+	`},
+	{query: `
+		UPDATE project_volunteers SET volunteer_id = $1
+		WHERE volunteer_id = $2
+		  AND project_id NOT IN (SELECT project_id FROM project_volunteers WHERE volunteer_id = $1)
+	`},
+	{query: `
+		UPDATE match_impressions SET volunteer_id = $1
+		WHERE volunteer_id = $2
+		  AND project_id NOT IN (SELECT project_id FROM match_impressions WHERE volunteer_id = $1)
+	`},
+	{query: `
+		UPDATE organization_members SET user_id = $1
+		WHERE user_id = $2
+		  AND organization_id NOT IN (SELECT organization_id FROM organization_members WHERE user_id = $1)
+	`},
+	{query: `
+		UPDATE project_team_members SET volunteer_id = $1
+		WHERE volunteer_id = $2
+		  AND project_id NOT IN (SELECT project_id FROM project_team_members WHERE volunteer_id = $1)
+	`},
+	{query: `UPDATE project_team_messages SET sender_id = $1 WHERE sender_id = $2`},
+	// The kept account's primary location stays primary
+	{query: `
+		UPDATE volunteer_locations SET is_primary = FALSE
+		WHERE volunteer_id = $2 AND is_primary
+		  AND EXISTS (SELECT 1 FROM volunteer_locations WHERE volunteer_id = $1 AND is_primary)
+	`},
+	{query: `
+		UPDATE volunteer_locations SET volunteer_id = $1
+		WHERE volunteer_id = $2
+		  AND label NOT IN (SELECT label FROM volunteer_locations WHERE volunteer_id = $1)
+	`},
+	{query: `
+		UPDATE shift_signups SET volunteer_id = $1
+		WHERE volunteer_id = $2
+		  AND shift_id NOT IN (SELECT shift_id FROM shift_signups WHERE volunteer_id = $1)
+	`},
+	{query: `
+		UPDATE shift_coverage_requests d SET requester_id = $1
+		WHERE d.requester_id = $2
+		  AND NOT (d.status = 'open' AND EXISTS (
+		      SELECT 1 FROM shift_coverage_requests k
+		      WHERE k.requester_id = $1 AND k.shift_id = d.shift_id AND k.status = 'open'))
+	`},
+	{query: `UPDATE shift_coverage_requests SET claimed_by = $1 WHERE claimed_by = $2`},
+	{query: `
+		UPDATE calendar_feed_tokens SET volunteer_id = $1
+		WHERE volunteer_id = $2
+		  AND NOT EXISTS (SELECT 1 FROM calendar_feed_tokens WHERE volunteer_id = $1)
+	`},
+	{query: `UPDATE volunteer_availability_rules SET volunteer_id = $1 WHERE volunteer_id = $2`},
+	{query: `UPDATE volunteer_availability_blackouts SET volunteer_id = $1 WHERE volunteer_id = $2`},
+	{query: `
+		UPDATE volunteer_badges SET volunteer_id = $1
+		WHERE volunteer_id = $2
+		  AND badge NOT IN (SELECT badge FROM volunteer_badges WHERE volunteer_id = $1)
+	`},
+	{query: `
+		UPDATE volunteer_milestones SET volunteer_id = $1
+		WHERE volunteer_id = $2
+		  AND hours NOT IN (SELECT hours FROM volunteer_milestones WHERE volunteer_id = $1)
+	`},
+	{query: `UPDATE volunteer_ratings SET volunteer_id = $1 WHERE volunteer_id = $2`},
+	{query: `
+		UPDATE waiver_signatures d SET volunteer_id = $1
+		WHERE d.volunteer_id = $2
+		  AND NOT EXISTS (
+		      SELECT 1 FROM waiver_signatures k
+		      WHERE k.volunteer_id = $1 AND k.waiver_id = d.waiver_id AND k.version = d.version)
+	`},
+	{query: `
+		UPDATE volunteer_references d SET volunteer_id = $1
+		WHERE d.volunteer_id = $2
+		  AND NOT EXISTS (
+		      SELECT 1 FROM volunteer_references k
+		      WHERE k.volunteer_id = $1 AND k.project_id = d.project_id AND k.author_id = d.author_id)
+	`},
+	{query: `
+		UPDATE volunteer_references d SET author_id = $1
+		WHERE d.author_id = $2
+		  AND NOT EXISTS (
+		      SELECT 1 FROM volunteer_references k
+		      WHERE k.author_id = $1 AND k.project_id = d.project_id AND k.volunteer_id = d.volunteer_id)
+	`},
+	{query: `
+		UPDATE content_reports d SET reporter_id = $1
+		WHERE d.reporter_id = $2
+		  AND NOT (d.status = 'open' AND EXISTS (
+		      SELECT 1 FROM content_reports k
+		      WHERE k.reporter_id = $1 AND k.target_type = d.target_type AND k.target_id = d.target_id AND k.status = 'open'))
+	`},
+	{query: `UPDATE documents SET owner_id = $1 WHERE owner_id = $2`},
+	{query: `UPDATE auth_events SET user_id = $1 WHERE user_id = $2`},
+}
+
+// attributionColumns record who created, reviewed or uploaded something;
+// they move to the kept account rather than being cleared
+var attributionColumns = []struct{ table, column string }{
+	{"projects", "coordinator_id"},
+	{"project_teams", "lead_id"},
+	{"project_team_members", "assigned_by"},
+	{"volunteer_hours", "logged_by"},
+	{"volunteer_skills", "verified_by"},
+	{"volunteer_ratings", "rated_by"},
+	{"project_reviews", "moderated_by"},
+	{"volunteer_references", "moderated_by"},
+	{"organizations", "created_by"},
+	{"organizations", "verified_by"},
+	{"organization_members", "invited_by"},
+	{"organization_settings", "updated_by"},
+	{"organization_invitations", "invited_by"},
+	{"organization_invitations", "accepted_by"},
+	{"organization_api_keys", "created_by"},
+	{"organization_volunteer_shares", "granted_by"},
+	{"organization_verification_evidence", "uploaded_by"},
+	{"regions", "created_by"},
+	{"project_impact_metrics", "created_by"},
+	{"project_impact_entries", "recorded_by"},
+	{"project_shifts", "created_by"},
+	{"waiver_templates", "created_by"},
+	{"waiver_template_versions", "created_by"},
+	{"content_reports", "resolved_by"},
+	{"audit_log", "actor_id"},
+	{"project_photos", "uploaded_by"},
+	{"documents", "uploaded_by"},
+	{"quarantined_files", "uploaded_by"},
+	{"email_domain_rules", "created_by"},
+	{"role_requests", "reviewed_by"},
+	{"duplicate_candidates", "reviewed_by"},
+}
+
+// Merge moves the duplicate account's skills, enrollments, hours, shifts,
+// memberships and everything else it owns or is credited with to userID,
+// then deletes the duplicate, all in one transaction. The kept account
+// keeps its own details, gaining the duplicate's role if that is higher.
+func (s *Service) Merge(userID, duplicateID, mergedBy string) (*models.AccountMerge, error) {
+	if userID == duplicateID {
+		return nil, ErrSameAccount
+	}
+
+	var merge models.AccountMerge
+	err := database.WithWriteGuard(func() error {
+		merge = models.AccountMerge{UserID: userID, DuplicateID: duplicateID}
+
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// Lock both accounts, in a fixed order so concurrent merges of the
+		// same pair cannot deadlock
+		rows, err := tx.Query(`
+			SELECT id, email FROM users WHERE id IN ($1, $2) ORDER BY id FOR UPDATE
+		`, userID, duplicateID)
+		if err != nil {
+			return err
+		}
+		found := 0
+		for rows.Next() {
+			var id, email string
+			if err := rows.Scan(&id, &email); err != nil {
+				rows.Close()
+				return err
+			}
+			if id == duplicateID {
+				merge.DuplicateEmail = email
+			}
+			found++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if found < 2 {
+			return ErrAccountNotFound
+		}
+
+		for _, statement := range mergeStatements {
+			res, err := tx.Exec(statement.query, userID, duplicateID)
+			if err != nil {
+				return err
+			}
+			if statement.count != nil {
+				n, _ := res.RowsAffected()
+				*statement.count(&merge) += n
+			}
+		}
+		for _, c := range attributionColumns {
+			_, err := tx.Exec(`UPDATE `+c.table+` SET `+c.column+` = $1 WHERE `+c.column+` = $2`, userID, duplicateID)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(`
+			UPDATE users k
+			SET role = CASE
+			        WHEN 'admin' IN (k.role, d.role) THEN 'admin'
+			        WHEN 'coordinator' IN (k.role, d.role) THEN 'coordinator'
+			        ELSE k.role
+			    END,
+			    max_travel_km = COALESCE(k.max_travel_km, d.max_travel_km),
+			    profile_complete = k.profile_complete OR d.profile_complete,
+			    last_login_at = GREATEST(k.last_login_at, d.last_login_at),
+			    updated_at = CURRENT_TIMESTAMP
+			FROM users d
+			WHERE k.id = $1 AND d.id = $2
+		`, userID, duplicateID)
+		if err != nil {
+			return err
+		}
+
+		err = audit.Record(tx, mergedBy, "user.merge", "user", userID, map[string]string{
+			"duplicateId":    duplicateID,
+			"duplicateEmail": merge.DuplicateEmail,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`DELETE FROM users WHERE id = $1`, duplicateID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The kept account's skills changed, and the duplicate's are gone
+	if _, err := s.jobsService.EnqueueUnique(matching.RefreshSkillVectorsJob, "all", nil); err != nil {
+		slog.Error("Queue skill vector refresh error", "error", err)
+	}
+	return &merge, nil
+}
@@ -0,0 +1,219 @@
+package enrollment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/tracing"
+)
+
+var (
+	ErrAttendanceNotEnrolled = errors.New("attendance can only be recorded against an active enrollment")
+	ErrAlreadyCheckedIn      = errors.New("volunteer is already checked in to this project")
+	ErrNotCheckedIn          = errors.New("volunteer is not checked in to this project")
+	ErrShiftNotBooked        = errors.New("volunteer is not booked onto this shift of the project")
+)
+
+// maxSessionHours caps the hours one check-in logs, as an hours entry holds
+// at most a day
+const maxSessionHours = 24
+
+const attendanceSelect = `
+	SELECT a.id, a.enrollment_id, a.volunteer_id, a.project_id, a.shift_id,
+	       a.checked_in_at, a.checked_in_by, a.checked_out_at, a.checked_out_by,
+	       a.hours_id, vh.hours::float8
+	FROM attendance a
+	LEFT JOIN volunteer_hours vh ON vh.id = a.hours_id
+`
+
+func scanAttendance(scanner interface{ Scan(...interface{}) error }, a *models.Attendance) error {
+	return scanner.Scan(
+		&a.ID,
+		&a.EnrollmentID,
+		&a.VolunteerID,
+		&a.ProjectID,
+		&a.ShiftID,
+		&a.CheckedInAt,
+		&a.CheckedInBy,
+		&a.CheckedOutAt,
+		&a.CheckedOutBy,
+		&a.HoursID,
+		&a.Hours,
+	)
+}
+
+// CheckIn starts an attendance session under an active enrollment,
+// optionally for one of the project's shifts the volunteer is booked onto
+func (s *Service) CheckIn(ctx context.Context, enrollment *models.Enrollment, req models.CheckInRequest, checkedInBy string) (*models.Attendance, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.CheckIn")
+	defer span.End()
+
+	if enrollment.Status != "enrolled" {
+		return nil, ErrAttendanceNotEnrolled
+	}
+
+	var attendance models.Attendance
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if req.ShiftID != nil {
+			// Matching on text reports malformed IDs as not booked
+			var booked bool
+			err := tx.QueryRowContext(ctx, `
+				SELECT EXISTS (
+					SELECT 1
+					FROM shift_signups ss
+					JOIN project_shifts ps ON ps.id = ss.shift_id
+					WHERE ss.shift_id::text = $1 AND ss.volunteer_id = $2 AND ps.project_id = $3
+				)
+			`, *req.ShiftID, enrollment.VolunteerID, enrollment.ProjectID).Scan(&booked)
+			if err != nil {
+				return err
+			}
+			if !booked {
+				return ErrShiftNotBooked
+			}
+		}
+
+		var id string
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO attendance (enrollment_id, volunteer_id, project_id, shift_id, checked_in_by)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, enrollment.ID, enrollment.VolunteerID, enrollment.ProjectID, req.ShiftID, checkedInBy).Scan(&id)
+		if isUniqueViolation(err) {
+			return ErrAlreadyCheckedIn
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := scanAttendance(tx.QueryRowContext(ctx, attendanceSelect+`WHERE a.id = $1`, id), &attendance); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		if errors.Is(err, ErrShiftNotBooked) || errors.Is(err, ErrAlreadyCheckedIn) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to check in: %w", err)
+	}
+
+	return &attendance, nil
+}
+
+// CheckOut ends the enrollment's open attendance session and logs the time
+// since check-in as hours worked on the day of check-in, in the volunteer's
+// zone. Sessions too short to round to 0.01 hours log nothing, and longer
+// ones than a day log 24 hours.
+func (s *Service) CheckOut(ctx context.Context, enrollment *models.Enrollment, req models.CheckOutRequest, checkedOutBy string) (*models.Attendance, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.CheckOut")
+	defer span.End()
+
+	var attendance models.Attendance
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var id, timezone string
+		var checkedInAt, checkedOutAt time.Time
+		err = tx.QueryRowContext(ctx, `
+			UPDATE attendance a
+			SET checked_out_at = CURRENT_TIMESTAMP, checked_out_by = $2
+			FROM users u
+			WHERE a.enrollment_id = $1 AND a.checked_out_at IS NULL AND u.id = a.volunteer_id
+			RETURNING a.id, a.checked_in_at, a.checked_out_at, u.timezone
+		`, enrollment.ID, checkedOutBy).Scan(&id, &checkedInAt, &checkedOutAt, &timezone)
+		if err == sql.ErrNoRows {
+			return ErrNotCheckedIn
+		}
+		if err != nil {
+			return err
+		}
+
+		hours := math.Min(math.Round(checkedOutAt.Sub(checkedInAt).Hours()*100)/100, maxSessionHours)
+		if hours > 0 {
+			loc, err := time.LoadLocation(timezone)
+			if err != nil {
+				loc = time.UTC
+			}
+			workedOn := checkedInAt.In(loc).Format("2006-01-02")
+
+			_, err = tx.ExecContext(ctx, `
+				WITH logged AS (
+					INSERT INTO volunteer_hours (enrollment_id, volunteer_id, project_id, hours, worked_on, note, logged_by)
+					VALUES ($2, $3, $4, $5, $6, $7, $8)
+					RETURNING id
+				)
+				UPDATE attendance SET hours_id = (SELECT id FROM logged) WHERE id = $1
+			`, id, enrollment.ID, enrollment.VolunteerID, enrollment.ProjectID, hours, workedOn, req.Note, checkedOutBy)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := scanAttendance(tx.QueryRowContext(ctx, attendanceSelect+`WHERE a.id = $1`, id), &attendance); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotCheckedIn) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to check out: %w", err)
+	}
+
+	return &attendance, nil
+}
+
+// GetAttendance lists an enrollment's attendance sessions, latest first
+func (s *Service) GetAttendance(ctx context.Context, enrollmentID string) ([]models.Attendance, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.GetAttendance")
+	defer span.End()
+
+	query := attendanceSelect + `
+		WHERE a.enrollment_id = $1
+		ORDER BY a.checked_in_at DESC, a.id
+	`
+
+	var sessions []models.Attendance
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, query, enrollmentID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		sessions = []models.Attendance{}
+		for rows.Next() {
+			var a models.Attendance
+			if err := scanAttendance(rows, &a); err != nil {
+				return err
+			}
+			sessions = append(sessions, a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance: %w", err)
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,72 @@
+package enrollment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/tracing"
+)
+
+// BulkUpdateEnrollmentStatus applies the action to each enrollment in one
+// transaction, as UpdateEnrollmentStatus would. Enrollments the action is
+// refused for are rolled back on their own and reported, while the rest
+// are committed together; a database failure applies none of them.
+func (s *Service) BulkUpdateEnrollmentStatus(ctx context.Context, enrollmentIDs []string, action, responseMessage, actorID, tenantID string) (*models.BulkEnrollmentReport, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.BulkUpdateEnrollmentStatus")
+	defer span.End()
+
+	var report models.BulkEnrollmentReport
+	err := database.WithWriteGuard(func() error {
+		report = models.BulkEnrollmentReport{Action: action, Results: make([]models.BulkEnrollmentResult, 0, len(enrollmentIDs))}
+
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		for _, id := range enrollmentIDs {
+			if _, err := tx.ExecContext(ctx, `SAVEPOINT bulk_enrollment`); err != nil {
+				return err
+			}
+
+			result := models.BulkEnrollmentResult{EnrollmentID: id}
+			status, err := s.updateStatus(ctx, tx, id, action, responseMessage, actorID, tenantID)
+			if err != nil {
+				if !isActionError(err) {
+					return err
+				}
+				if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT bulk_enrollment`); err != nil {
+					return err
+				}
+
+				result.Error = err.Error()
+				var conflictErr *ScheduleConflictError
+				var waiversErr *UnsignedWaiversError
+				if errors.As(err, &conflictErr) {
+					result.Conflicts = conflictErr.Conflicts
+				} else if errors.As(err, &waiversErr) {
+					result.Waivers = waiversErr.Waivers
+				}
+				report.Failed++
+			} else {
+				if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT bulk_enrollment`); err != nil {
+					return err
+				}
+				result.Status = status
+				report.Succeeded++
+			}
+			report.Results = append(report.Results, result)
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update enrollments: %w", err)
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,47 @@
+package enrollment
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/civic-weave/backend/internal/events"
+)
+
+// publishChange publishes the enrollment's new status within tx to the
+// volunteer and to the people managing the project: its coordinator and
+// its organization's owners, admins and coordinators. Subscribers such as
+// notifications and the audit log pick it up once the change commits.
+func (s *Service) publishChange(ctx context.Context, tx *sql.Tx, change events.EnrollmentChanged) error {
+	projectID, volunteerID := change.ProjectID, change.VolunteerID
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT coordinator_id::text FROM projects WHERE id = $1 AND coordinator_id IS NOT NULL
+		UNION
+		SELECT om.user_id::text
+		FROM projects p
+		JOIN organization_members om ON om.organization_id = p.organization_id
+		WHERE p.id = $1
+		  AND om.status = 'active'
+		  AND om.role IN ('owner', 'admin', 'coordinator')
+	`, projectID)
+	if err != nil {
+		return err
+	}
+	userIDs := []string{volunteerID}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		if id != volunteerID {
+			userIDs = append(userIDs, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return s.events.Publish(ctx, tx, events.TypeEnrollmentChanged, change, userIDs)
+}
@@ -0,0 +1,68 @@
+package enrollment
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/notifications"
+	"github.com/civic-weave/backend/internal/tracing"
+)
+
+// RemindInvitationsJob is the kind of background job that runs
+// RemindExpiringInvitations
+const RemindInvitationsJob = "enrollments.remind_invitations"
+
+// RemindExpiringInvitations emails volunteers whose invitations expire
+// within before, once per invitation, and returns how many were sent.
+// Invitations are marked reminded before sending, so a failed email is
+// logged rather than sent again.
+func (s *Service) RemindExpiringInvitations(ctx context.Context, before time.Duration) (int, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.RemindExpiringInvitations")
+	defer span.End()
+
+	query := `
+		UPDATE volunteer_enrollments ve
+		SET reminded_at = NOW()
+		FROM users u, projects p
+		WHERE u.id = ve.volunteer_id
+		  AND p.id = ve.project_id
+		  AND ve.status = 'invited'
+		  AND ve.reminded_at IS NULL
+		  AND ve.expires_at > NOW()
+		  AND ve.expires_at <= NOW() + $1 * INTERVAL '1 millisecond'
+		RETURNING u.email, u.name, u.locale, p.name, ve.expires_at
+	`
+
+	var reminders []notifications.InvitationReminder
+	err := database.WithWriteGuard(func() error {
+		rows, err := s.db.QueryContext(ctx, query, before.Milliseconds())
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var r notifications.InvitationReminder
+			if err := rows.Scan(&r.To, &r.VolunteerName, &r.Locale, &r.ProjectName, &r.ExpiresAt); err != nil {
+				return err
+			}
+			reminders = append(reminders, r)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, r := range reminders {
+		if err := s.mailer.Send(notifications.RenderInvitationReminder(r)); err != nil {
+			slog.Error("Invitation reminder error", "to", r.To, "error", err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
@@ -1,22 +1,80 @@
 package enrollment
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/events"
 	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/notifications"
+	"github.com/civic-weave/backend/internal/pagination"
+	"github.com/civic-weave/backend/internal/sanitize"
+	"github.com/civic-weave/backend/internal/tracing"
+	"github.com/civic-weave/backend/internal/waivers"
+	"github.com/lib/pq"
 )
 
+var (
+	ErrProjectNotFound    = errors.New("project not found")
+	ErrEnrollmentNotFound = errors.New("enrollment not found")
+	ErrNotEnrolled        = errors.New("hours can only be logged against an active or completed enrollment")
+	ErrInvalidHours       = errors.New("hours must be greater than 0 and at most 24")
+	ErrInvalidWorkDate    = errors.New("workedOn must be a YYYY-MM-DD date that is not in the future")
+	ErrAlreadyEnrolled    = errors.New("volunteer is already enrolled or has a pending enrollment for this project")
+	ErrInvalidAction      = errors.New("invalid enrollment action")
+	ErrInvalidTransition  = errors.New("the enrollment's status does not allow this action")
+	ErrActionForbidden    = errors.New("not allowed to take this action on the enrollment")
+)
+
+// uuidPattern matches enrollment IDs, so malformed ones are reported as not
+// found instead of failing their query
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ScheduleConflictError rejects an enrollment in a project that blocks
+// schedule conflicts when the volunteer has overlapping commitments
+type ScheduleConflictError struct {
+	Conflicts []models.EnrollmentConflict
+}
+
+func (e *ScheduleConflictError) Error() string {
+	return "volunteer is already enrolled in a project with overlapping dates"
+}
+
+// UnsignedWaiversError rejects enrolling a volunteer who has not signed the
+// current version of every waiver the project requires
+type UnsignedWaiversError struct {
+	Waivers []models.WaiverTemplate
+}
+
+func (e *UnsignedWaiversError) Error() string {
+	return "volunteer has not signed the waivers the project requires"
+}
+
+// ExpireStaleJob is the kind of background job that runs ExpireStale
+const ExpireStaleJob = "enrollments.expire_stale"
+
 type Service struct {
-	db *sql.DB
+	db     *sql.DB
+	mailer notifications.Mailer
+	events *events.Dispatcher
+	// invitationExpiry is how long volunteers have to answer an invitation
+	invitationExpiry time.Duration
 }
 
-func NewService(db *sql.DB) *Service {
-	return &Service{db: db}
+func NewService(db *sql.DB, mailer notifications.Mailer, dispatcher *events.Dispatcher, invitationExpiry time.Duration) *Service {
+	return &Service{db: db, mailer: mailer, events: dispatcher, invitationExpiry: invitationExpiry}
 }
 
-func (s *Service) CreateEnrollment(volunteerID, projectID, action, message, initiatedBy string) (*models.Enrollment, error) {
+// CreateEnrollment starts an enrollment; projects outside the tenant are reported as ErrProjectNotFound
+func (s *Service) CreateEnrollment(ctx context.Context, volunteerID, projectID, action, message, initiatedBy, tenantID string) (*models.Enrollment, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.CreateEnrollment")
+	defer span.End()
+
 	// Determine initial status based on action
 	var status string
 	if action == "request" {
@@ -24,88 +82,64 @@ func (s *Service) CreateEnrollment(volunteerID, projectID, action, message, init
 	} else if action == "invite" {
 		status = "invited" // TL inviting volunteer
 	} else {
-		return nil, fmt.Errorf("invalid action: %s (must be 'request' or 'invite')", action)
+		return nil, fmt.Errorf("%w: %s (must be 'request' or 'invite')", ErrInvalidAction, action)
+	}
+
+	if tenantID != "" {
+		inTenant, err := s.projectInTenant(ctx, projectID, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check project tenant: %w", err)
+		}
+		if !inTenant {
+			return nil, ErrProjectNotFound
+		}
+	}
+
+	conflicts, block, err := s.FindScheduleConflicts(ctx, volunteerID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check schedule conflicts: %w", err)
+	}
+	if block && len(conflicts) > 0 {
+		return nil, &ScheduleConflictError{Conflicts: conflicts}
 	}
 
 	query := `
-		INSERT INTO volunteer_enrollments (volunteer_id, project_id, status, initiated_by, message)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, volunteer_id, project_id, status, initiated_by, message, response_message, created_at, updated_at, approved_at, completed_at
+		INSERT INTO volunteer_enrollments (volunteer_id, project_id, status, initiated_by, message, waitlisted_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5,
+		        CASE WHEN $3 = 'waitlisted' THEN NOW() END,
+		        CASE WHEN $3 = 'invited' THEN NOW() + $6 * INTERVAL '1 millisecond' END)
+		RETURNING id, volunteer_id, project_id, status, initiated_by, message, response_message, created_at, updated_at, approved_at, completed_at, waitlisted_at, expires_at
 	`
 
 	var enrollment models.Enrollment
 	var messagePtr, responseMessagePtr *string
 	var approvedAt, completedAt *time.Time
 
-	if message != "" {
+	if message = sanitize.RichText(message); message != "" {
 		messagePtr = &message
 	}
 
-	err := s.db.QueryRow(query, volunteerID, projectID, status, initiatedBy, messagePtr).Scan(
-		&enrollment.ID,
-		&enrollment.VolunteerID,
-		&enrollment.ProjectID,
-		&enrollment.Status,
-		&enrollment.InitiatedBy,
-		&messagePtr,
-		&responseMessagePtr,
-		&enrollment.CreatedAt,
-		&enrollment.UpdatedAt,
-		&approvedAt,
-		&completedAt,
-	)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to create enrollment: %w", err)
-	}
-
-	enrollment.Message = messagePtr
-	enrollment.ResponseMessage = responseMessagePtr
-	enrollment.ApprovedAt = approvedAt
-	enrollment.CompletedAt = completedAt
-
-	return &enrollment, nil
-}
-
-func (s *Service) GetProjectEnrollments(projectID string) ([]models.EnrollmentWithDetails, error) {
-	query := `
-		SELECT
-			ve.id,
-			ve.volunteer_id,
-			ve.project_id,
-			ve.status,
-			ve.initiated_by,
-			ve.message,
-			ve.response_message,
-			ve.created_at,
-			ve.updated_at,
-			ve.approved_at,
-			ve.completed_at,
-			u.name as volunteer_name,
-			u.email as volunteer_email,
-			p.name as project_name,
-			initiator.name as initiated_by_name
-		FROM volunteer_enrollments ve
-		JOIN users u ON u.id = ve.volunteer_id
-		JOIN projects p ON p.id = ve.project_id
-		JOIN users initiator ON initiator.id = ve.initiated_by
-		WHERE ve.project_id = $1
-		ORDER BY ve.created_at DESC
-	`
-
-	rows, err := s.db.Query(query, projectID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get project enrollments: %w", err)
-	}
-	defer rows.Close()
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
 
-	var enrollments []models.EnrollmentWithDetails
-	for rows.Next() {
-		var enrollment models.EnrollmentWithDetails
-		var messagePtr, responseMessagePtr *string
-		var approvedAt, completedAt *time.Time
+		// Requests to a full project join its waitlist; invitations to one
+		// would have nowhere to go
+		full, err := lockCapacity(ctx, tx, projectID)
+		if err != nil {
+			return err
+		}
+		initial := status
+		if full && status == "requested" {
+			initial = "waitlisted"
+		} else if full {
+			return ErrProjectFull
+		}
 
-		err := rows.Scan(
+		err = tx.QueryRowContext(ctx, query, volunteerID, projectID, initial, initiatedBy, messagePtr, s.invitationExpiry.Milliseconds()).Scan(
 			&enrollment.ID,
 			&enrollment.VolunteerID,
 			&enrollment.ProjectID,
@@ -117,28 +151,123 @@ func (s *Service) GetProjectEnrollments(projectID string) ([]models.EnrollmentWi
 			&enrollment.UpdatedAt,
 			&approvedAt,
 			&completedAt,
-			&enrollment.VolunteerName,
-			&enrollment.VolunteerEmail,
-			&enrollment.ProjectName,
-			&enrollment.InitiatedByName,
+			&enrollment.WaitlistedAt,
+			&enrollment.ExpiresAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan enrollment: %w", err)
+			return err
 		}
 
-		enrollment.Message = messagePtr
-		enrollment.ResponseMessage = responseMessagePtr
-		enrollment.ApprovedAt = approvedAt
-		enrollment.CompletedAt = completedAt
+		err = s.publishChange(ctx, tx, events.EnrollmentChanged{
+			EnrollmentID: enrollment.ID,
+			ProjectID:    projectID,
+			VolunteerID:  volunteerID,
+			Status:       enrollment.Status,
+			ActorID:      initiatedBy,
+		})
+		if err != nil {
+			return err
+		}
 
-		enrollments = append(enrollments, enrollment)
+		return tx.Commit()
+	})
+
+	if isUniqueViolation(err) {
+		return nil, ErrAlreadyEnrolled
+	}
+	if err == ErrProjectFull || err == ErrProjectNotFound {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create enrollment: %w", err)
 	}
 
-	return enrollments, nil
+	enrollment.Message = messagePtr
+	enrollment.ResponseMessage = responseMessagePtr
+	enrollment.ApprovedAt = approvedAt
+	enrollment.CompletedAt = completedAt
+	enrollment.Conflicts = conflicts
+
+	return &enrollment, nil
 }
 
-func (s *Service) GetVolunteerEnrollments(volunteerID string) ([]models.EnrollmentWithDetails, error) {
+// FindScheduleConflicts lists the volunteer's active enrollments in other
+// projects whose dates overlap the project's, and reports whether the
+// project blocks such conflicts. Projects without a start date never
+// conflict; a missing end date means the project runs indefinitely.
+func (s *Service) FindScheduleConflicts(ctx context.Context, volunteerID, projectID string) ([]models.EnrollmentConflict, bool, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.FindScheduleConflicts")
+	defer span.End()
+
 	query := `
+		SELECT ve.id, op.id, op.name, op.start_date, op.end_date, op.timezone
+		FROM projects p
+		JOIN volunteer_enrollments ve
+		  ON ve.volunteer_id = $1 AND ve.status = 'enrolled' AND ve.project_id <> p.id
+		JOIN projects op ON op.id = ve.project_id
+		WHERE p.id = $2
+		  AND p.start_date < COALESCE(op.end_date, 'infinity')
+		  AND op.start_date < COALESCE(p.end_date, 'infinity')
+		ORDER BY op.start_date, op.id
+	`
+
+	var conflicts []models.EnrollmentConflict
+	var block bool
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, query, volunteerID, projectID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		conflicts = nil
+		for rows.Next() {
+			var c models.EnrollmentConflict
+			var timezone string
+			if err := rows.Scan(&c.EnrollmentID, &c.ProjectID, &c.ProjectName, &c.StartDate, &c.EndDate, &timezone); err != nil {
+				return err
+			}
+			c.StartDate = models.InTimezone(c.StartDate, timezone)
+			c.EndDate = models.InTimezone(c.EndDate, timezone)
+			conflicts = append(conflicts, c)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(conflicts) == 0 {
+			return nil
+		}
+
+		return s.db.QueryRowContext(ctx, `SELECT block_schedule_conflicts FROM projects WHERE id = $1`, projectID).Scan(&block)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return conflicts, block, nil
+}
+
+// Sorts are the fields enrollment lists can sort by, newest first by default
+var Sorts = pagination.Sorts{
+	Fields: map[string]string{
+		"createdAt":     "ve.created_at",
+		"updatedAt":     "ve.updated_at",
+		"status":        "ve.status",
+		"volunteerName": "u.name",
+		"projectName":   "p.name",
+	},
+	Default:  "-createdAt",
+	Tiebreak: "ve.id",
+}
+
+// enrollmentDetailsFrom joins enrollments to the names shown with them
+const enrollmentDetailsFrom = `
+		FROM volunteer_enrollments ve
+		JOIN users u ON u.id = ve.volunteer_id
+		JOIN projects p ON p.id = ve.project_id
+		JOIN users initiator ON initiator.id = ve.initiated_by`
+
+const enrollmentDetailsColumns = `
 		SELECT
 			ve.id,
 			ve.volunteer_id,
@@ -154,18 +283,75 @@ func (s *Service) GetVolunteerEnrollments(volunteerID string) ([]models.Enrollme
 			u.name as volunteer_name,
 			u.email as volunteer_email,
 			p.name as project_name,
-			initiator.name as initiated_by_name
-		FROM volunteer_enrollments ve
-		JOIN users u ON u.id = ve.volunteer_id
-		JOIN projects p ON p.id = ve.project_id
-		JOIN users initiator ON initiator.id = ve.initiated_by
+			initiator.name as initiated_by_name,
+			ve.waitlisted_at,
+			ve.expires_at`
+
+// GetProjectEnrollments returns a page of the project's enrollments, in one
+// status when status is set, along with how many there are across all pages
+func (s *Service) GetProjectEnrollments(ctx context.Context, projectID, tenantID, status string, page pagination.Params) ([]models.EnrollmentWithDetails, int, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.GetProjectEnrollments")
+	defer span.End()
+
+	where := `
+		WHERE ve.project_id = $1
+		  AND ($2 = '' OR p.organization_id = NULLIF($2, '')::uuid)
+		  AND ($3 = '' OR ve.status = $3)
+	`
+	enrollments, total, err := s.queryEnrollmentPage(ctx, where, page, projectID, tenantID, status)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get project enrollments: %w", err)
+	}
+
+	return enrollments, total, nil
+}
+
+// GetVolunteerEnrollments returns a page of the volunteer's enrollments, in
+// one status when status is set, along with how many there are across all
+// pages
+func (s *Service) GetVolunteerEnrollments(ctx context.Context, volunteerID, tenantID, status string, page pagination.Params) ([]models.EnrollmentWithDetails, int, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.GetVolunteerEnrollments")
+	defer span.End()
+
+	where := `
 		WHERE ve.volunteer_id = $1
-		ORDER BY ve.created_at DESC
+		  AND ($2 = '' OR p.organization_id = NULLIF($2, '')::uuid)
+		  AND ($3 = '' OR ve.status = $3)
 	`
+	enrollments, total, err := s.queryEnrollmentPage(ctx, where, page, volunteerID, tenantID, status)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get volunteer enrollments: %w", err)
+	}
+
+	return enrollments, total, nil
+}
+
+// queryEnrollmentPage counts the enrollments matching where and fetches a
+// page of them with their details
+func (s *Service) queryEnrollmentPage(ctx context.Context, where string, page pagination.Params, filters ...interface{}) ([]models.EnrollmentWithDetails, int, error) {
+	query := enrollmentDetailsColumns + enrollmentDetailsFrom + where + `
+		ORDER BY ` + page.OrderBy + `
+		` + page.Window(len(filters)+1)
+
+	var enrollments []models.EnrollmentWithDetails
+	var total int
+	err := database.WithReadRetry(func() error {
+		if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*)`+enrollmentDetailsFrom+where, filters...).Scan(&total); err != nil {
+			return err
+		}
+
+		var err error
+		enrollments, err = s.queryEnrollmentsWithDetails(ctx, query, append(filters, page.Args()...)...)
+		return err
+	})
+	return enrollments, total, err
+}
 
-	rows, err := s.db.Query(query, volunteerID)
+// queryEnrollmentsWithDetails runs a joined enrollment query and scans the rows
+func (s *Service) queryEnrollmentsWithDetails(ctx context.Context, query string, args ...interface{}) ([]models.EnrollmentWithDetails, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get volunteer enrollments: %w", err)
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -191,6 +377,8 @@ func (s *Service) GetVolunteerEnrollments(volunteerID string) ([]models.Enrollme
 			&enrollment.VolunteerEmail,
 			&enrollment.ProjectName,
 			&enrollment.InitiatedByName,
+			&enrollment.WaitlistedAt,
+			&enrollment.ExpiresAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan enrollment: %w", err)
@@ -204,44 +392,153 @@ func (s *Service) GetVolunteerEnrollments(volunteerID string) ([]models.Enrollme
 		enrollments = append(enrollments, enrollment)
 	}
 
-	return enrollments, nil
+	return enrollments, rows.Err()
 }
 
-func (s *Service) UpdateEnrollmentStatus(enrollmentID, action, responseMessage string) error {
-	// First, get current status to determine valid transitions
-	var currentStatus string
-	err := s.db.QueryRow("SELECT status FROM volunteer_enrollments WHERE id = $1", enrollmentID).Scan(&currentStatus)
+// UpdateEnrollmentStatus applies the action to the enrollment on behalf of
+// actorID and returns its new status. Volunteers answer invitations and
+// withdraw their requests; people who can manage the project answer
+// requests, complete enrollments and mark no-shows; either may cancel an
+// active enrollment. Accepting into a full project waitlists the enrollment
+// instead, and cancelling an active one promotes the oldest waitlisted
+// volunteer.
+func (s *Service) UpdateEnrollmentStatus(ctx context.Context, enrollmentID, action, responseMessage, actorID, tenantID string) (string, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.UpdateEnrollmentStatus")
+	defer span.End()
+
+	var status string
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		status, err = s.updateStatus(ctx, tx, enrollmentID, action, responseMessage, actorID, tenantID)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		if isActionError(err) {
+			return "", err
+		}
+		return "", fmt.Errorf("failed to update enrollment status: %w", err)
+	}
+
+	return status, nil
+}
+
+// isActionError reports whether err rejects the enrollment action itself,
+// rather than the database failing to carry it out
+func isActionError(err error) bool {
+	var conflictErr *ScheduleConflictError
+	var waiversErr *UnsignedWaiversError
+	return errors.Is(err, ErrEnrollmentNotFound) || errors.Is(err, ErrProjectNotFound) ||
+		errors.Is(err, ErrInvalidAction) || errors.Is(err, ErrInvalidTransition) ||
+		errors.Is(err, ErrActionForbidden) || errors.Is(err, ErrProjectFull) ||
+		errors.As(err, &conflictErr) || errors.As(err, &waiversErr)
+}
+
+// updateStatus applies the action within tx and returns the enrollment's
+// new status
+func (s *Service) updateStatus(ctx context.Context, tx *sql.Tx, enrollmentID, action, responseMessage, actorID, tenantID string) (string, error) {
+	if !uuidPattern.MatchString(enrollmentID) {
+		return "", ErrEnrollmentNotFound
+	}
+
+	// First, get current status and the actor's part in it to determine valid transitions
+	statusQuery := `
+		SELECT ve.status, ve.volunteer_id, ve.project_id,
+		       ve.volunteer_id::text = $3,
+		       EXISTS (SELECT 1 FROM users WHERE id::text = $3 AND role = 'admin')
+		       OR p.coordinator_id::text = $3
+		       OR EXISTS (
+		           SELECT 1 FROM organization_members om
+		           WHERE om.organization_id = p.organization_id
+		             AND om.user_id::text = $3
+		             AND om.status = 'active'
+		             AND om.role IN ('owner', 'admin', 'coordinator')
+		       )
+		FROM volunteer_enrollments ve
+		JOIN projects p ON p.id = ve.project_id
+		WHERE ve.id = $1
+		  AND ($2 = '' OR p.organization_id = NULLIF($2, '')::uuid)
+	`
+
+	var currentStatus, volunteerID, projectID string
+	var isVolunteer, isManager bool
+	err := tx.QueryRowContext(ctx, statusQuery, enrollmentID, tenantID, actorID).Scan(&currentStatus, &volunteerID, &projectID, &isVolunteer, &isManager)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("enrollment not found")
+			return "", ErrEnrollmentNotFound
 		}
-		return fmt.Errorf("failed to get current status: %w", err)
+		return "", fmt.Errorf("failed to get current status: %w", err)
 	}
 
-	// Determine new status based on current status and action
+	// Determine new status based on current status and action, and who may
+	// take it from there
 	var newStatus string
-	if action == "accept" {
-		if currentStatus == "requested" || currentStatus == "invited" {
-			newStatus = "enrolled"
-		} else {
-			return fmt.Errorf("cannot accept enrollment in status: %s", currentStatus)
+	var allowed bool
+	switch action {
+	case "accept":
+		switch currentStatus {
+		case "requested", "waitlisted":
+			newStatus, allowed = "enrolled", isManager // TL accepting volunteer's request
+		case "invited":
+			newStatus, allowed = "enrolled", isVolunteer // Volunteer accepting TL's invitation
 		}
-	} else if action == "reject" {
-		if currentStatus == "requested" {
-			newStatus = "tl_rejected" // TL rejecting volunteer's request
-		} else if currentStatus == "invited" {
-			newStatus = "v_rejected" // Volunteer rejecting TL's invitation
-		} else {
-			return fmt.Errorf("cannot reject enrollment in status: %s", currentStatus)
+	case "reject":
+		switch currentStatus {
+		case "requested", "waitlisted":
+			newStatus, allowed = "tl_rejected", isManager // TL rejecting volunteer's request
+		case "invited":
+			newStatus, allowed = "v_rejected", isVolunteer // Volunteer rejecting TL's invitation
 		}
-	} else if action == "withdraw" {
-		if currentStatus == "requested" {
-			newStatus = "v_rejected" // Volunteer withdrawing their own request
-		} else {
-			return fmt.Errorf("cannot withdraw enrollment in status: %s", currentStatus)
+	case "withdraw":
+		if currentStatus == "requested" || currentStatus == "waitlisted" {
+			newStatus, allowed = "v_rejected", isVolunteer // Volunteer withdrawing their own request
+		}
+	case "cancel":
+		if currentStatus == "enrolled" {
+			newStatus, allowed = "cancelled", isVolunteer || isManager
+		}
+	case "complete":
+		if currentStatus == "enrolled" {
+			newStatus, allowed = "completed", isManager
+		}
+	case "no_show":
+		if currentStatus == "enrolled" {
+			newStatus, allowed = "no_show", isManager
+		}
+	default:
+		return "", fmt.Errorf("%w: %s (must be 'accept', 'reject', 'withdraw', 'cancel', 'complete' or 'no_show')", ErrInvalidAction, action)
+	}
+	if newStatus == "" {
+		return "", fmt.Errorf("%w: cannot %s enrollment in status %s", ErrInvalidTransition, action, currentStatus)
+	}
+	if !allowed {
+		return "", fmt.Errorf("%w: %s", ErrActionForbidden, action)
+	}
+
+	if newStatus == "enrolled" {
+		conflicts, block, err := s.FindScheduleConflicts(ctx, volunteerID, projectID)
+		if err != nil {
+			return "", fmt.Errorf("failed to check schedule conflicts: %w", err)
+		}
+		if block && len(conflicts) > 0 {
+			return "", &ScheduleConflictError{Conflicts: conflicts}
+		}
+
+		unsigned, err := waivers.Unsigned(tx, volunteerID, projectID)
+		if err != nil {
+			return "", fmt.Errorf("failed to check waivers: %w", err)
+		}
+		if len(unsigned) > 0 {
+			return "", &UnsignedWaiversError{Waivers: unsigned}
 		}
-	} else {
-		return fmt.Errorf("invalid action: %s (must be 'accept' or 'reject')", action)
 	}
 
 	query := `
@@ -250,50 +547,220 @@ func (s *Service) UpdateEnrollmentStatus(enrollmentID, action, responseMessage s
 			status = $2,
 			response_message = $3,
 			updated_at = NOW(),
-			approved_at = CASE WHEN $2 = 'enrolled' THEN NOW() ELSE approved_at END
-		WHERE id = $1
+			approved_at = CASE WHEN $2 = 'enrolled' THEN NOW() ELSE approved_at END,
+			completed_at = CASE WHEN $2 = 'completed' THEN NOW() ELSE completed_at END,
+			waitlisted_at = CASE WHEN $2 = 'waitlisted' THEN NOW() END,
+			expires_at = NULL
+		WHERE id = $1 AND status = $4
 	`
 
 	// Convert empty string to NULL for response_message
 	var responseMessageParam interface{}
-	if responseMessage == "" {
+	if responseMessage = sanitize.RichText(responseMessage); responseMessage == "" {
 		responseMessageParam = nil
 	} else {
 		responseMessageParam = responseMessage
 	}
 
-	result, err := s.db.Exec(query, enrollmentID, newStatus, responseMessageParam)
+	status := newStatus
+	full, err := lockCapacity(ctx, tx, projectID)
 	if err != nil {
-		return fmt.Errorf("failed to update enrollment status: %w", err)
+		return "", err
+	}
+	if newStatus == "enrolled" && full {
+		if currentStatus == "waitlisted" {
+			return "", ErrProjectFull
+		}
+		status = "waitlisted"
 	}
 
+	result, err := tx.ExecContext(ctx, query, enrollmentID, status, responseMessageParam, currentStatus)
+	if err != nil {
+		return "", err
+	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return "", err
 	}
-
+	// Another update moved the enrollment on since its status was read
 	if rowsAffected == 0 {
-		return fmt.Errorf("enrollment not found")
+		return "", fmt.Errorf("%w: enrollment is no longer %s", ErrInvalidTransition, currentStatus)
+	}
+
+	err = s.publishChange(ctx, tx, events.EnrollmentChanged{
+		EnrollmentID: enrollmentID,
+		ProjectID:    projectID,
+		VolunteerID:  volunteerID,
+		Status:       status,
+		ActorID:      actorID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if status == "cancelled" {
+		if err := s.promoteWaitlisted(ctx, tx, projectID); err != nil {
+			return "", err
+		}
+	}
+
+	return status, nil
+}
+
+// GetEnrollment returns an enrollment; enrollments in projects outside the tenant are reported as ErrEnrollmentNotFound
+func (s *Service) GetEnrollment(ctx context.Context, enrollmentID, tenantID string) (*models.Enrollment, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.GetEnrollment")
+	defer span.End()
+
+	query := `
+		SELECT ve.id, ve.volunteer_id, ve.project_id, ve.status, ve.initiated_by, ve.message,
+		       ve.response_message, ve.created_at, ve.updated_at, ve.approved_at, ve.completed_at,
+		       ve.waitlisted_at, ve.expires_at
+		FROM volunteer_enrollments ve
+		JOIN projects p ON p.id = ve.project_id
+		WHERE ve.id = $1
+		  AND ($2 = '' OR p.organization_id = NULLIF($2, '')::uuid)
+	`
+
+	var enrollment models.Enrollment
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRowContext(ctx, query, enrollmentID, tenantID).Scan(
+			&enrollment.ID,
+			&enrollment.VolunteerID,
+			&enrollment.ProjectID,
+			&enrollment.Status,
+			&enrollment.InitiatedBy,
+			&enrollment.Message,
+			&enrollment.ResponseMessage,
+			&enrollment.CreatedAt,
+			&enrollment.UpdatedAt,
+			&enrollment.ApprovedAt,
+			&enrollment.CompletedAt,
+			&enrollment.WaitlistedAt,
+			&enrollment.ExpiresAt,
+		)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrEnrollmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enrollment: %w", err)
 	}
 
-	return nil
+	return &enrollment, nil
 }
 
-func (s *Service) IsVolunteerEnrolled(volunteerID, projectID string) (bool, error) {
+// LogHours records hours worked under an active or completed enrollment
+func (s *Service) LogHours(ctx context.Context, enrollment *models.Enrollment, req models.LogHoursRequest, loggedBy string) (*models.HoursEntry, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.LogHours")
+	defer span.End()
+
+	// Hours from the last days of a project are often logged after close-out
+	if enrollment.Status != "enrolled" && enrollment.Status != "completed" {
+		return nil, ErrNotEnrolled
+	}
+	if req.Hours <= 0 || req.Hours > 24 {
+		return nil, ErrInvalidHours
+	}
+	workedOn, err := time.Parse("2006-01-02", req.WorkedOn)
+	if err != nil || workedOn.After(time.Now()) {
+		return nil, ErrInvalidWorkDate
+	}
+
+	query := `
+		INSERT INTO volunteer_hours (enrollment_id, volunteer_id, project_id, hours, worked_on, note, logged_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, enrollment_id, volunteer_id, project_id, hours, worked_on, note, logged_by, created_at
+	`
+
+	var entry models.HoursEntry
+	var workedOnDate time.Time
+	err = database.WithWriteGuard(func() error {
+		return s.db.QueryRowContext(ctx, query, enrollment.ID, enrollment.VolunteerID, enrollment.ProjectID, req.Hours, req.WorkedOn, req.Note, loggedBy).Scan(
+			&entry.ID,
+			&entry.EnrollmentID,
+			&entry.VolunteerID,
+			&entry.ProjectID,
+			&entry.Hours,
+			&workedOnDate,
+			&entry.Note,
+			&entry.LoggedBy,
+			&entry.CreatedAt,
+		)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to log hours: %w", err)
+	}
+	entry.WorkedOn = workedOnDate.Format("2006-01-02")
+
+	return &entry, nil
+}
+
+func (s *Service) IsVolunteerEnrolled(ctx context.Context, volunteerID, projectID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.IsVolunteerEnrolled")
+	defer span.End()
+
 	query := `
 		SELECT EXISTS (
 			SELECT 1 FROM volunteer_enrollments
 			WHERE volunteer_id = $1
 			  AND project_id = $2
-			  AND status IN ('requested', 'invited', 'enrolled')
+			  AND status IN ('requested', 'invited', 'enrolled', 'waitlisted')
 		)
 	`
 
 	var enrolled bool
-	err := s.db.QueryRow(query, volunteerID, projectID).Scan(&enrolled)
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRowContext(ctx, query, volunteerID, projectID).Scan(&enrolled)
+	})
 	if err != nil {
 		return false, fmt.Errorf("failed to check enrollment status: %w", err)
 	}
 
 	return enrolled, nil
 }
+
+// projectInTenant reports whether the project belongs to the tenant's organization
+func (s *Service) projectInTenant(ctx context.Context, projectID, tenantID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM projects
+			WHERE id = $1 AND organization_id = $2
+		)
+	`
+
+	var inTenant bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRowContext(ctx, query, projectID, tenantID).Scan(&inTenant)
+	})
+	return inTenant, err
+}
+
+// ExpireStale marks requests nobody answered within olderThan, and
+// invitations past their expiry, as expired, so they stop counting as
+// pending. It returns how many were expired.
+func (s *Service) ExpireStale(ctx context.Context, olderThan time.Duration) (int64, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.ExpireStale")
+	defer span.End()
+
+	var n int64
+	err := database.WithWriteGuard(func() error {
+		result, err := s.db.ExecContext(ctx, `
+			UPDATE volunteer_enrollments
+			SET status = 'expired', updated_at = NOW(), expires_at = NULL
+			WHERE (status = 'requested' AND updated_at < NOW() - $1 * INTERVAL '1 millisecond')
+			   OR (status = 'invited' AND expires_at < NOW())
+		`, olderThan.Milliseconds())
+		if err != nil {
+			return err
+		}
+		n, err = result.RowsAffected()
+		return err
+	})
+	return n, err
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
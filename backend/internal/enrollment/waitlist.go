@@ -0,0 +1,168 @@
+package enrollment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/events"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/tracing"
+	"github.com/civic-weave/backend/internal/waivers"
+)
+
+var ErrProjectFull = errors.New("project has reached its volunteer limit")
+
+// lockCapacity locks the project within tx, so enrollments in it are
+// counted against its cap one at a time, and reports whether it is at
+// max_volunteers. Projects without a cap are never full.
+func lockCapacity(ctx context.Context, tx *sql.Tx, projectID string) (bool, error) {
+	var maxVolunteers sql.NullInt64
+	err := tx.QueryRowContext(ctx, `SELECT max_volunteers FROM projects WHERE id = $1 FOR NO KEY UPDATE`, projectID).Scan(&maxVolunteers)
+	if err == sql.ErrNoRows {
+		return false, ErrProjectNotFound
+	}
+	if err != nil || !maxVolunteers.Valid {
+		return false, err
+	}
+
+	var enrolled int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM volunteer_enrollments WHERE project_id = $1 AND status = 'enrolled'
+	`, projectID).Scan(&enrolled)
+	if err != nil {
+		return false, err
+	}
+	return enrolled >= maxVolunteers.Int64, nil
+}
+
+// promoteWaitlisted enrolls the project's waitlisted volunteers, oldest
+// first, into its open spots within tx. The project must already be locked
+// by lockCapacity. Volunteers whose schedule conflicts block them or who
+// have waivers left to sign are skipped and stay waitlisted.
+func (s *Service) promoteWaitlisted(ctx context.Context, tx *sql.Tx, projectID string) error {
+	var spots sql.NullInt64
+	err := tx.QueryRowContext(ctx, `
+		SELECT p.max_volunteers - (
+			SELECT COUNT(*) FROM volunteer_enrollments ve WHERE ve.project_id = p.id AND ve.status = 'enrolled'
+		)
+		FROM projects p
+		WHERE p.id = $1
+	`, projectID).Scan(&spots)
+	if err != nil {
+		return err
+	}
+	if spots.Valid && spots.Int64 <= 0 {
+		return nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, volunteer_id
+		FROM volunteer_enrollments
+		WHERE project_id = $1 AND status = 'waitlisted'
+		ORDER BY waitlisted_at, id
+	`, projectID)
+	if err != nil {
+		return err
+	}
+	var waiting [][2]string
+	for rows.Next() {
+		var e [2]string
+		if err := rows.Scan(&e[0], &e[1]); err != nil {
+			rows.Close()
+			return err
+		}
+		waiting = append(waiting, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, e := range waiting {
+		if spots.Valid && spots.Int64 <= 0 {
+			break
+		}
+		enrollmentID, volunteerID := e[0], e[1]
+
+		conflicts, block, err := s.FindScheduleConflicts(ctx, volunteerID, projectID)
+		if err != nil {
+			return err
+		}
+		if block && len(conflicts) > 0 {
+			continue
+		}
+		unsigned, err := waivers.Unsigned(tx, volunteerID, projectID)
+		if err != nil {
+			return err
+		}
+		if len(unsigned) > 0 {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE volunteer_enrollments
+			SET status = 'enrolled', updated_at = NOW(), approved_at = NOW(), waitlisted_at = NULL
+			WHERE id = $1
+		`, enrollmentID)
+		if err != nil {
+			return err
+		}
+		err = s.publishChange(ctx, tx, events.EnrollmentChanged{
+			EnrollmentID: enrollmentID,
+			ProjectID:    projectID,
+			VolunteerID:  volunteerID,
+			Status:       "enrolled",
+		})
+		if err != nil {
+			return err
+		}
+		spots.Int64--
+	}
+
+	return nil
+}
+
+// GetWaitlist lists the project's waitlisted enrollments in the order they
+// will be enrolled; projects outside the tenant are reported as
+// ErrProjectNotFound
+func (s *Service) GetWaitlist(ctx context.Context, projectID, tenantID string) ([]models.WaitlistEntry, error) {
+	ctx, span := tracing.Start(ctx, "enrollment.GetWaitlist")
+	defer span.End()
+
+	if tenantID != "" {
+		inTenant, err := s.projectInTenant(ctx, projectID, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check project tenant: %w", err)
+		}
+		if !inTenant {
+			return nil, ErrProjectNotFound
+		}
+	}
+
+	query := enrollmentDetailsColumns + enrollmentDetailsFrom + `
+		WHERE ve.project_id = $1 AND ve.status = 'waitlisted'
+		ORDER BY ve.waitlisted_at, ve.id
+	`
+
+	var entries []models.WaitlistEntry
+	err := database.WithReadRetry(func() error {
+		enrollments, err := s.queryEnrollmentsWithDetails(ctx, query, projectID)
+		if err != nil {
+			return err
+		}
+
+		entries = make([]models.WaitlistEntry, len(enrollments))
+		for i, e := range enrollments {
+			entries[i] = models.WaitlistEntry{EnrollmentWithDetails: e, Position: i + 1}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get waitlist: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,134 @@
+package events
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sync"
+)
+
+// subscriberBuffer is how many events a subscriber can fall behind by before
+// further events to it are dropped
+const subscriberBuffer = 32
+
+type subscriber struct {
+	userID string
+	ch     chan Event
+}
+
+// Handler reacts to an event on the instance receiving it
+type Handler func(e Event)
+
+// Bus hands the events published on any instance to the subscribers
+// connected to this one. Feed it with database.PostgresDB.Listen on
+// database.EventsChannel.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	handlers    map[string][]Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: map[*subscriber]struct{}{},
+		handlers:    map[string][]Handler{},
+	}
+}
+
+// Handle calls fn with every event of type typ this instance receives,
+// whoever it is delivered to. Every instance calls its own handlers, so use
+// it for state kept in memory; a TypeResync handler is called when events
+// may have been missed. Handlers run on the listener's goroutine and must
+// not block.
+func (b *Bus) Handle(typ string, fn Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[typ] = append(b.handlers[typ], fn)
+}
+
+// Subscribe returns a channel receiving the events delivered to userID and
+// a function that unsubscribes and closes it. Events arriving while the
+// channel is full are dropped.
+func (b *Bus) Subscribe(userID string) (<-chan Event, func()) {
+	sub := &subscriber{userID: userID, ch: make(chan Event, subscriberBuffer)}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub.ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(sub)
+	}
+}
+
+// Close unsubscribes everyone, closing their channels, so streams end when
+// the server shuts down
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+// remove closes sub's channel unless it is already gone; b.mu must be held
+func (b *Bus) remove(sub *subscriber) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.ch)
+	}
+}
+
+// HandleNotification consumes payloads from database.EventsChannel. An empty
+// payload means events may have been missed, so every subscriber is sent a
+// TypeResync event.
+func (b *Bus) HandleNotification(payload string) {
+	if payload == "" {
+		resync := Event{Type: TypeResync, Data: json.RawMessage("{}")}
+		b.mu.Lock()
+		for sub := range b.subscribers {
+			b.send(sub, resync)
+		}
+		handlers := b.handlers[TypeResync]
+		b.mu.Unlock()
+
+		for _, fn := range handlers {
+			fn(resync)
+		}
+		return
+	}
+
+	var e Event
+	if err := json.Unmarshal([]byte(payload), &e); err != nil {
+		slog.Warn("Invalid event payload", "payload", payload, "error", err)
+		return
+	}
+
+	recipients := make(map[string]bool, len(e.UserIDs))
+	for _, id := range e.UserIDs {
+		recipients[id] = true
+	}
+
+	b.mu.Lock()
+	for sub := range b.subscribers {
+		if recipients[sub.userID] {
+			b.send(sub, e)
+		}
+	}
+	handlers := b.handlers[e.Type]
+	b.mu.Unlock()
+
+	for _, fn := range handlers {
+		fn(e)
+	}
+}
+
+// send delivers e to sub without waiting; b.mu must be held
+func (b *Bus) send(sub *subscriber, e Event) {
+	select {
+	case sub.ch <- e:
+	default:
+		slog.Warn("Event subscriber is behind, dropping event", "user", sub.userID, "type", e.Type)
+	}
+}
@@ -0,0 +1,106 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/civic-weave/backend/internal/jobs"
+)
+
+// DispatchJob is the kind of background job that hands an event to one of
+// its subscribers
+const DispatchJob = "events.dispatch"
+
+// Subscriber reacts to an event once, whichever instance publishes it. An
+// error retries it according to jobs.DefaultRetry.
+type Subscriber func(ctx context.Context, e Event) error
+
+type dispatchPayload struct {
+	Subscriber string `json:"subscriber"`
+	Event      Event  `json:"event"`
+}
+
+// Dispatcher publishes events and runs their subscribers asynchronously.
+// Each subscriber of an event gets its own background job, queued in the
+// publishing transaction, so subscribers only see committed changes, run
+// once across instances and are retried on their own.
+type Dispatcher struct {
+	jobs *jobs.Service
+
+	mu          sync.RWMutex
+	subscribers map[string]map[string]Subscriber
+}
+
+func NewDispatcher(jobsService *jobs.Service) *Dispatcher {
+	d := &Dispatcher{
+		jobs:        jobsService,
+		subscribers: map[string]map[string]Subscriber{},
+	}
+	jobsService.Register(DispatchJob, jobs.Worker{Handle: d.dispatch})
+	return d
+}
+
+// Subscribe runs fn for every event of type typ published after it is
+// called. The name identifies the subscriber in queued jobs, so keep it
+// stable across releases; subscribing a name again replaces it.
+func (d *Dispatcher) Subscribe(typ, name string, fn Subscriber) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.subscribers[typ] == nil {
+		d.subscribers[typ] = map[string]Subscriber{}
+	}
+	d.subscribers[typ][name] = fn
+}
+
+// Publish sends an event of type typ with data to userIDs, as Publish does,
+// and queues a job for each of its subscribers, all within tx
+func (d *Dispatcher) Publish(ctx context.Context, tx *sql.Tx, typ string, data interface{}, userIDs []string) error {
+	if err := Publish(ctx, tx, typ, data, userIDs); err != nil {
+		return err
+	}
+
+	d.mu.RLock()
+	names := make([]string, 0, len(d.subscribers[typ]))
+	for name := range d.subscribers[typ] {
+		names = append(names, name)
+	}
+	d.mu.RUnlock()
+	if len(names) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		payload := dispatchPayload{
+			Subscriber: name,
+			Event:      Event{Type: typ, Data: raw, UserIDs: userIDs},
+		}
+		if _, err := d.jobs.EnqueueTx(tx, DispatchJob, payload); err != nil {
+			return fmt.Errorf("failed to queue %s subscriber %s: %w", typ, name, err)
+		}
+	}
+	return nil
+}
+
+// dispatch runs the subscriber named in a DispatchJob
+func (d *Dispatcher) dispatch(ctx context.Context, payload []byte) error {
+	var p dispatchPayload
+	if err := json.Unmarshal(payload, &p); err != nil || p.Subscriber == "" {
+		return fmt.Errorf("invalid payload %s", payload)
+	}
+
+	d.mu.RLock()
+	fn, ok := d.subscribers[p.Event.Type][p.Subscriber]
+	d.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no %s subscriber named %s", p.Event.Type, p.Subscriber)
+	}
+	return fn(ctx, p.Event)
+}
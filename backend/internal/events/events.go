@@ -0,0 +1,101 @@
+// Package events carries what happens in one place, such as an enrollment
+// changing status, to the signed-in users it concerns, wherever they are
+// connected. Services publish events through Postgres NOTIFY, inside the
+// transaction making the change, so an event is only seen once the change
+// is committed and reaches every instance. Each instance's Bus hands the
+// events to the subscribers connected to it, and to in-process handlers
+// keeping per-instance state such as caches up to date. Work that must
+// happen once per event, like notifying or auditing, subscribes to a
+// Dispatcher instead, so services react to each other's changes without
+// importing one another.
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/civic-weave/backend/internal/database"
+)
+
+// Event types
+const (
+	TypeEnrollmentChanged    = "enrollment.changed"
+	TypeMessageCreated       = "message.created"
+	TypeProjectStatusChanged = "project.status_changed"
+	// TypeResync tells subscribers events may have been missed, so anything
+	// they show should be fetched again
+	TypeResync = "resync"
+)
+
+// Event is something that happened, delivered to the users it concerns
+type Event struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+	// UserIDs are the users the event is delivered to
+	UserIDs []string `json:"userIds"`
+}
+
+// Decode unmarshals the event's data into v, which should be the data
+// struct of the event's type
+func (e Event) Decode(v interface{}) error {
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("invalid %s event data: %w", e.Type, err)
+	}
+	return nil
+}
+
+// EnrollmentChanged is the data of TypeEnrollmentChanged events, published
+// when an enrollment is created or its status changes
+type EnrollmentChanged struct {
+	EnrollmentID string `json:"enrollmentId"`
+	ProjectID    string `json:"projectId"`
+	VolunteerID  string `json:"volunteerId"`
+	Status       string `json:"status"`
+	// ActorID is who made the change; it is empty when the system did,
+	// e.g. when a waitlisted volunteer is given an open spot
+	ActorID string `json:"actorId,omitempty"`
+}
+
+// MessageCreated is the data of TypeMessageCreated events, published when a
+// team broadcast is sent. The body is left out; clients fetch it.
+type MessageCreated struct {
+	MessageID  string `json:"messageId"`
+	TeamID     string `json:"teamId"`
+	SenderName string `json:"senderName"`
+	Subject    string `json:"subject"`
+}
+
+// ProjectStatusChanged is the data of TypeProjectStatusChanged events
+type ProjectStatusChanged struct {
+	ProjectID string `json:"projectId"`
+	Status    string `json:"status"`
+}
+
+// Execer runs statements on a database or inside a transaction
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Publish sends an event of type typ with data to userIDs. Run it in the
+// transaction making the change; the event is dropped if it rolls back.
+// Events without users are not sent.
+func Publish(ctx context.Context, e Execer, typ string, data interface{}, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(Event{Type: typ, Data: raw, UserIDs: userIDs})
+	if err != nil {
+		return err
+	}
+
+	if _, err := e.ExecContext(ctx, `SELECT pg_notify($1, $2)`, database.EventsChannel, string(payload)); err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", typ, err)
+	}
+	return nil
+}
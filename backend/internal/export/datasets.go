@@ -0,0 +1,191 @@
+package export
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrUnknownDataset = errors.New("dataset must be one of users, projects, enrollments, hours, matches, events")
+	ErrUnknownField   = errors.New("unknown export field")
+)
+
+// Field is an exportable column and the SQL expression producing it
+type Field struct {
+	Name    string
+	Expr    string
+	Numeric bool
+}
+
+// Dataset is a result set that can be exported. From is the FROM clause and
+// TenantFilter a condition restricting rows to the organization in $1.
+type Dataset struct {
+	Name         string
+	From         string
+	TenantFilter string
+	OrderBy      string
+	Fields       []Field
+}
+
+var datasets = map[string]Dataset{
+	"users": {
+		Name: "users",
+		From: "users u",
+		TenantFilter: `volunteer_visible_to_org(u.id, $1::uuid)
+			OR EXISTS (SELECT 1 FROM organization_members om WHERE om.user_id = u.id AND om.organization_id = $1::uuid AND om.status = 'active')`,
+		OrderBy: "u.created_at",
+		Fields: []Field{
+			{Name: "id", Expr: "u.id"},
+			{Name: "email", Expr: "u.email"},
+			{Name: "name", Expr: "u.name"},
+			{Name: "role", Expr: "u.role"},
+			{Name: "location_name", Expr: "u.location_name"},
+			{Name: "latitude", Expr: "u.latitude", Numeric: true},
+			{Name: "longitude", Expr: "u.longitude", Numeric: true},
+			{Name: "timezone", Expr: "u.timezone"},
+			{Name: "created_at", Expr: "u.created_at"},
+		},
+	},
+	"projects": {
+		Name:         "projects",
+		From:         "projects p",
+		TenantFilter: "p.organization_id = $1::uuid",
+		OrderBy:      "p.created_at",
+		Fields: []Field{
+			{Name: "id", Expr: "p.id"},
+			{Name: "name", Expr: "p.name"},
+			{Name: "status", Expr: "p.status"},
+			{Name: "organization_id", Expr: "p.organization_id"},
+			{Name: "coordinator_id", Expr: "p.coordinator_id"},
+			{Name: "location_name", Expr: "p.location_name"},
+			{Name: "is_remote", Expr: "p.is_remote"},
+			{Name: "timezone", Expr: "p.timezone"},
+			{Name: "start_date", Expr: "p.start_date"},
+			{Name: "end_date", Expr: "p.end_date"},
+			{Name: "max_volunteers", Expr: "p.max_volunteers", Numeric: true},
+			{Name: "created_at", Expr: "p.created_at"},
+		},
+	},
+	"enrollments": {
+		Name:         "enrollments",
+		From:         "volunteer_enrollments ve JOIN users u ON u.id = ve.volunteer_id JOIN projects p ON p.id = ve.project_id",
+		TenantFilter: "p.organization_id = $1::uuid",
+		OrderBy:      "ve.created_at",
+		Fields: []Field{
+			{Name: "id", Expr: "ve.id"},
+			{Name: "volunteer_id", Expr: "ve.volunteer_id"},
+			{Name: "volunteer_name", Expr: "u.name"},
+			{Name: "project_id", Expr: "ve.project_id"},
+			{Name: "project_name", Expr: "p.name"},
+			{Name: "organization_id", Expr: "p.organization_id"},
+			{Name: "status", Expr: "ve.status"},
+			{Name: "enrollment_type", Expr: "ve.enrollment_type"},
+			{Name: "created_at", Expr: "ve.created_at"},
+			{Name: "approved_at", Expr: "ve.approved_at"},
+			{Name: "completed_at", Expr: "ve.completed_at"},
+		},
+	},
+	"hours": {
+		Name:         "hours",
+		From:         "volunteer_hours vh JOIN users u ON u.id = vh.volunteer_id JOIN projects p ON p.id = vh.project_id",
+		TenantFilter: "p.organization_id = $1::uuid",
+		OrderBy:      "vh.worked_on, vh.created_at",
+		Fields: []Field{
+			{Name: "id", Expr: "vh.id"},
+			{Name: "volunteer_id", Expr: "vh.volunteer_id"},
+			{Name: "volunteer_name", Expr: "u.name"},
+			{Name: "project_id", Expr: "vh.project_id"},
+			{Name: "project_name", Expr: "p.name"},
+			{Name: "organization_id", Expr: "p.organization_id"},
+			{Name: "worked_on", Expr: "vh.worked_on"},
+			{Name: "hours", Expr: "vh.hours", Numeric: true},
+			{Name: "note", Expr: "vh.note"},
+			{Name: "logged_by", Expr: "vh.logged_by"},
+			{Name: "created_at", Expr: "vh.created_at"},
+		},
+	},
+	"matches": {
+		Name:         "matches",
+		From:         "match_impressions mi JOIN projects p ON p.id = mi.project_id",
+		TenantFilter: "p.organization_id = $1::uuid",
+		OrderBy:      "mi.first_shown_at",
+		Fields: []Field{
+			{Name: "project_id", Expr: "mi.project_id"},
+			{Name: "organization_id", Expr: "p.organization_id"},
+			{Name: "volunteer_id", Expr: "mi.volunteer_id"},
+			{Name: "first_shown_at", Expr: "mi.first_shown_at"},
+			{Name: "last_shown_at", Expr: "mi.last_shown_at"},
+			{Name: "times_shown", Expr: "mi.times_shown", Numeric: true},
+			// The outcome of the match, if the pair ever enrolled
+			{Name: "enrollment_status", Expr: "(SELECT ve.status FROM volunteer_enrollments ve WHERE ve.project_id = mi.project_id AND ve.volunteer_id = mi.volunteer_id)"},
+		},
+	},
+	"events": {
+		Name:         "events",
+		From:         "client_events ce LEFT JOIN projects p ON p.id = ce.project_id",
+		TenantFilter: "p.organization_id = $1::uuid",
+		OrderBy:      "ce.occurred_at",
+		Fields: []Field{
+			{Name: "id", Expr: "ce.id"},
+			{Name: "event_type", Expr: "ce.event_type"},
+			{Name: "user_id", Expr: "ce.user_id"},
+			{Name: "project_id", Expr: "ce.project_id"},
+			{Name: "organization_id", Expr: "p.organization_id"},
+			{Name: "volunteer_id", Expr: "ce.volunteer_id"},
+			{Name: "session_id", Expr: "ce.session_id"},
+			{Name: "properties", Expr: "ce.properties"},
+			{Name: "sample_rate", Expr: "ce.sample_rate", Numeric: true},
+			{Name: "occurred_at", Expr: "ce.occurred_at"},
+			// The backend outcome for the pair the event concerns, if any
+			{Name: "enrollment_status", Expr: "(SELECT ve.status FROM volunteer_enrollments ve WHERE ve.project_id = ce.project_id AND ve.volunteer_id = ce.volunteer_id)"},
+			{Name: "enrolled_at", Expr: "(SELECT ve.created_at FROM volunteer_enrollments ve WHERE ve.project_id = ce.project_id AND ve.volunteer_id = ce.volunteer_id)"},
+		},
+	},
+}
+
+// GetDataset returns the named dataset
+func GetDataset(name string) (Dataset, error) {
+	d, ok := datasets[name]
+	if !ok {
+		return Dataset{}, ErrUnknownDataset
+	}
+	return d, nil
+}
+
+// SelectFields resolves a comma-separated field list against the dataset,
+// keeping the requested order. An empty list selects every field.
+func (d Dataset) SelectFields(raw string) ([]Field, error) {
+	if strings.TrimSpace(raw) == "" {
+		return d.Fields, nil
+	}
+
+	byName := make(map[string]Field, len(d.Fields))
+	for _, f := range d.Fields {
+		byName[f.Name] = f
+	}
+
+	var fields []Field
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		f, ok := byName[name]
+		if !ok {
+			return nil, ErrUnknownField
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// Columns describes the fields as export columns
+func Columns(fields []Field) []Column {
+	columns := make([]Column, len(fields))
+	for i, f := range fields {
+		columns[i] = Column{Name: f.Name, Numeric: f.Numeric}
+	}
+	return columns
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+)
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// Stream writes the dataset's selected fields row by row as they are read.
+// When tenantID is set only the tenant's rows are exported. Only opening the
+// query is retried; once rows are written a failure ends the export.
+func (s *Service) Stream(out RowWriter, d Dataset, fields []Field, tenantID string) error {
+	exprs := make([]string, len(fields))
+	for i, f := range fields {
+		exprs[i] = f.Expr + "::text"
+	}
+
+	query := `SELECT ` + strings.Join(exprs, ", ") + ` FROM ` + d.From
+	var args []interface{}
+	if tenantID != "" {
+		query += ` WHERE (` + d.TenantFilter + `)`
+		args = append(args, tenantID)
+	}
+	query += ` ORDER BY ` + d.OrderBy
+
+	var rows *sql.Rows
+	err := database.WithReadRetry(func() error {
+		var err error
+		rows, err = s.db.Query(query, args...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if err := out.WriteHeader(Columns(fields)); err != nil {
+		return err
+	}
+
+	values := make([]sql.NullString, len(fields))
+	dest := make([]interface{}, len(fields))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	record := make([]string, len(fields))
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		for i, v := range values {
+			record[i] = v.String
+		}
+		if err := out.WriteRow(record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return out.Close()
+}
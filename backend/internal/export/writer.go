@@ -0,0 +1,79 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+// Formats an export can be written in
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var ErrInvalidFormat = errors.New("format must be csv or xlsx")
+
+// ParseFormat validates a requested format; an empty value means CSV
+func ParseFormat(raw string) (string, error) {
+	switch raw {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	}
+	return "", ErrInvalidFormat
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv"
+}
+
+// Column describes one exported column. Numeric columns are written as
+// numbers in spreadsheets so they can be summed.
+type Column struct {
+	Name    string
+	Numeric bool
+}
+
+// RowWriter writes a header followed by rows as they are produced, so large
+// exports never have to be held in memory. Close flushes any trailing
+// output and must be called once all rows are written.
+type RowWriter interface {
+	WriteHeader(columns []Column) error
+	WriteRow(values []string) error
+	Close() error
+}
+
+// NewWriter returns a RowWriter for the format writing to w
+func NewWriter(w io.Writer, format string) RowWriter {
+	if format == FormatXLSX {
+		return newXLSXWriter(w)
+	}
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) WriteHeader(columns []Column) error {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	return c.w.Write(names)
+}
+
+func (c *csvWriter) WriteRow(values []string) error {
+	return c.w.Write(values)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
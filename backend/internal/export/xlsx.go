@@ -0,0 +1,128 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+)
+
+// The fixed parts of a single-sheet workbook. Cells are written inline so no
+// shared string table has to be built up front.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Export" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxWriter streams a workbook: the fixed parts are written first and the
+// worksheet, which must come last, grows row by row
+type xlsxWriter struct {
+	zw      *zip.Writer
+	sheet   *bufio.Writer
+	numeric []bool
+	err     error
+}
+
+func newXLSXWriter(w io.Writer) *xlsxWriter {
+	x := &xlsxWriter{zw: zip.NewWriter(w)}
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		f, err := x.zw.Create(part.name)
+		if err != nil {
+			x.err = err
+			return x
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			x.err = err
+			return x
+		}
+	}
+
+	f, err := x.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		x.err = err
+		return x
+	}
+	x.sheet = bufio.NewWriter(f)
+	_, x.err = x.sheet.WriteString(xlsxSheetStart)
+	return x
+}
+
+func (x *xlsxWriter) WriteHeader(columns []Column) error {
+	x.numeric = make([]bool, len(columns))
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		x.numeric[i] = col.Numeric
+		names[i] = col.Name
+	}
+	return x.writeRow(names, false)
+}
+
+func (x *xlsxWriter) WriteRow(values []string) error {
+	return x.writeRow(values, true)
+}
+
+func (x *xlsxWriter) writeRow(values []string, typed bool) error {
+	if x.err != nil {
+		return x.err
+	}
+
+	x.sheet.WriteString("<row>")
+	for i, v := range values {
+		if typed && i < len(x.numeric) && x.numeric[i] && v != "" {
+			x.sheet.WriteString("<c><v>")
+			xml.EscapeText(x.sheet, []byte(v))
+			x.sheet.WriteString("</v></c>")
+			continue
+		}
+		x.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		xml.EscapeText(x.sheet, []byte(v))
+		x.sheet.WriteString("</t></is></c>")
+	}
+	_, x.err = x.sheet.WriteString("</row>")
+	return x.err
+}
+
+func (x *xlsxWriter) Close() error {
+	if x.err != nil {
+		return x.err
+	}
+	if _, err := x.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
@@ -0,0 +1,394 @@
+package gallery
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/blobstore"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/images"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/scanning"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrProjectNotFound  = errors.New("project not found")
+	ErrPhotoNotFound    = errors.New("photo not found")
+	ErrUnsupportedImage = errors.New("photo must be a PNG, JPEG or GIF image")
+	ErrImageTooLarge    = errors.New("photo must be at most 10 MB")
+	ErrImageDimensions  = errors.New("photo must be at most 6000 pixels wide and high")
+	ErrCaptionTooLong   = errors.New("caption must be at most 500 characters")
+	ErrGalleryFull      = errors.New("a project gallery holds at most 100 photos")
+	ErrInvalidOrder     = errors.New("photoIds must list every photo of the project exactly once")
+)
+
+const (
+	// MaxBytes is the largest photo accepted
+	MaxBytes         = 10 << 20
+	maxDimension     = 6000
+	maxCaptionLength = 500
+	maxPhotos        = 100
+	thumbnailSize    = 400
+)
+
+// extensions are the accepted image types by sniffed content type
+var extensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+}
+
+const photoColumns = `
+	id, project_id, uploaded_by, photo_url, thumbnail_url, variants, width, height,
+	caption, position, scan_status, created_at, updated_at
+`
+
+func scanPhoto(row interface{ Scan(...interface{}) error }, p *models.ProjectPhoto) error {
+	return row.Scan(
+		&p.ID,
+		&p.ProjectID,
+		&p.UploadedBy,
+		&p.URL,
+		&p.ThumbnailURL,
+		&p.Variants,
+		&p.Width,
+		&p.Height,
+		&p.Caption,
+		&p.Position,
+		&p.ScanStatus,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+}
+
+type Service struct {
+	db              *sql.DB
+	store           blobstore.Store
+	scanningService *scanning.Service
+}
+
+func NewService(db *sql.DB, store blobstore.Store, scanningService *scanning.Service) *Service {
+	return &Service{db: db, store: store, scanningService: scanningService}
+}
+
+// GetPhotos lists the project's gallery in order. Photos awaiting the
+// malware scan are left out.
+func (s *Service) GetPhotos(projectID, tenantID string) ([]models.ProjectPhoto, error) {
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + photoColumns + `
+		FROM project_photos
+		WHERE project_id = $1 AND scan_status = 'clean'
+		ORDER BY position, created_at, id
+	`
+
+	var photos []models.ProjectPhoto
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, projectID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		photos = []models.ProjectPhoto{}
+		for rows.Next() {
+			var p models.ProjectPhoto
+			if err := scanPhoto(rows, &p); err != nil {
+				return err
+			}
+			photos = append(photos, p)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return photos, nil
+}
+
+// Upload adds a photo to the end of the project's gallery, storing it with
+// a generated thumbnail. It joins the gallery once it passes the malware
+// scan, after which larger display sizes are generated. The type is sniffed
+// from the content rather than trusted from the client.
+func (s *Service) Upload(ctx context.Context, projectID, tenantID, uploadedBy string, data []byte, caption *string) (*models.ProjectPhoto, error) {
+	if len(data) > MaxBytes {
+		return nil, ErrImageTooLarge
+	}
+	caption, err := normalizeCaption(caption)
+	if err != nil {
+		return nil, err
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedImage
+	}
+	// Check the size before decoding so huge images aren't loaded
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if config.Width > maxDimension || config.Height > maxDimension {
+		return nil, ErrImageDimensions
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	thumb, err := images.Resize(img, thumbnailSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	base := "projects/" + projectID + "/photos/" + hex.EncodeToString(suffix)
+	photoKey, thumbKey := base+ext, base+"_thumb.jpg"
+	if err := s.store.Put(ctx, photoKey, contentType, data); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, thumbKey, "image/jpeg", thumb); err != nil {
+		s.deleteBlobs(ctx, photoKey)
+		return nil, err
+	}
+
+	var photo models.ProjectPhoto
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// Lock the project so concurrent uploads get distinct positions
+		var count int
+		var next int
+		err = tx.QueryRow(`
+			SELECT (SELECT COUNT(*) FROM project_photos WHERE project_id = p.id),
+			       (SELECT COALESCE(MAX(position) + 1, 0) FROM project_photos WHERE project_id = p.id)
+			FROM projects p
+			WHERE p.id = $1
+			FOR UPDATE
+		`, projectID).Scan(&count, &next)
+		if err == sql.ErrNoRows {
+			return ErrProjectNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if count >= maxPhotos {
+			return ErrGalleryFull
+		}
+
+		err = scanPhoto(tx.QueryRow(`
+			INSERT INTO project_photos (project_id, uploaded_by, photo_key, photo_url, thumbnail_key, thumbnail_url,
+			                            width, height, caption, position)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING `+photoColumns,
+			projectID, uploadedBy, photoKey, s.store.URL(photoKey), thumbKey, s.store.URL(thumbKey),
+			config.Width, config.Height, caption, next,
+		), &photo)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		s.deleteBlobs(ctx, photoKey, thumbKey)
+		return nil, err
+	}
+	s.scanningService.Wake()
+
+	return &photo, nil
+}
+
+// UpdateCaption replaces a photo's caption; an empty caption removes it
+func (s *Service) UpdateCaption(projectID, photoID, tenantID string, caption *string) (*models.ProjectPhoto, error) {
+	caption, err := normalizeCaption(caption)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	var photo models.ProjectPhoto
+	err = database.WithWriteGuard(func() error {
+		return scanPhoto(s.db.QueryRow(`
+			UPDATE project_photos
+			SET caption = $3, updated_at = CURRENT_TIMESTAMP
+			WHERE id::text = $1 AND project_id = $2
+			RETURNING `+photoColumns,
+			photoID, projectID, caption,
+		), &photo)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrPhotoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &photo, nil
+}
+
+// Delete removes a photo from the gallery along with its files and variants
+func (s *Service) Delete(ctx context.Context, projectID, photoID, tenantID string) error {
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return err
+	}
+
+	var photoKey, thumbKey string
+	err := database.WithWriteGuard(func() error {
+		return s.db.QueryRow(`
+			DELETE FROM project_photos
+			WHERE id::text = $1 AND project_id = $2
+			RETURNING photo_key, thumbnail_key
+		`, photoID, projectID).Scan(&photoKey, &thumbKey)
+	})
+	if err == sql.ErrNoRows {
+		return ErrPhotoNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	s.deleteBlobs(ctx, photoKey, thumbKey)
+	s.deleteBlobs(ctx, images.VariantKeys(photoKey, images.PhotoVariants)...)
+	return nil
+}
+
+// Reorder puts the gallery in the order of photoIDs, which must list every
+// photo in the gallery exactly once
+func (s *Service) Reorder(projectID, tenantID string, photoIDs []string) ([]models.ProjectPhoto, error) {
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var current []string
+		err = tx.QueryRow(`
+			SELECT ARRAY(SELECT id::text FROM project_photos WHERE project_id = p.id AND scan_status = 'clean')
+			FROM projects p
+			WHERE p.id = $1
+			FOR UPDATE
+		`, projectID).Scan(pq.Array(&current))
+		if err != nil {
+			return err
+		}
+		if !samePhotos(current, photoIDs) {
+			return ErrInvalidOrder
+		}
+
+		_, err = tx.Exec(`
+			UPDATE project_photos pp
+			SET position = o.position - 1, updated_at = CURRENT_TIMESTAMP
+			FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, position)
+			WHERE pp.id = o.id AND pp.project_id = $1
+		`, projectID, pq.Array(photoIDs))
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPhotos(projectID, tenantID)
+}
+
+// samePhotos reports whether ids lists exactly the photos in current
+func samePhotos(current, ids []string) bool {
+	if len(current) != len(ids) {
+		return false
+	}
+	remaining := make(map[string]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+	for _, id := range ids {
+		if !remaining[strings.ToLower(id)] {
+			return false
+		}
+		delete(remaining, strings.ToLower(id))
+	}
+	return true
+}
+
+func normalizeCaption(caption *string) (*string, error) {
+	if caption == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimSpace(*caption)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if len([]rune(trimmed)) > maxCaptionLength {
+		return nil, ErrCaptionTooLong
+	}
+	return &trimmed, nil
+}
+
+// deleteBlobs removes files that are no longer referenced; failures only
+// leave orphaned files behind, so they are logged
+func (s *Service) deleteBlobs(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			logging.FromContext(ctx).Error("Delete photo file error", "key", key, "error", err)
+		}
+	}
+}
+
+// requireProjectInTenant reports projects that don't exist or sit outside the tenant as ErrProjectNotFound
+func (s *Service) requireProjectInTenant(projectID, tenantID string) error {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM projects
+			WHERE id = $1
+			  AND ($2 = '' OR organization_id = NULLIF($2, '')::uuid)
+		)
+	`
+
+	var exists bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, projectID, tenantID).Scan(&exists)
+	})
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrProjectNotFound
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidBBox = errors.New("bbox must be minLon,minLat,maxLon,maxLat within world bounds")
+
+// MaxZoom is the deepest zoom level clusters are computed for; beyond it
+// cells are small enough that points are effectively unclustered
+const MaxZoom = 20
+
+// cellsPerTile is how many grid cells span one map tile, so clusters end up
+// roughly 32px apart on a 256px tile
+const cellsPerTile = 8
+
+// BBox is a longitude/latitude bounding box
+type BBox struct {
+	MinLon float64
+	MinLat float64
+	MaxLon float64
+	MaxLat float64
+}
+
+// ParseBBox parses "minLon,minLat,maxLon,maxLat"
+func ParseBBox(raw string) (BBox, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return BBox{}, ErrInvalidBBox
+	}
+
+	var values [4]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return BBox{}, ErrInvalidBBox
+		}
+		values[i] = v
+	}
+
+	b := BBox{MinLon: values[0], MinLat: values[1], MaxLon: values[2], MaxLat: values[3]}
+	if b.MinLon < -180 || b.MaxLon > 180 || b.MinLat < -90 || b.MaxLat > 90 ||
+		b.MinLon >= b.MaxLon || b.MinLat >= b.MaxLat {
+		return BBox{}, ErrInvalidBBox
+	}
+	return b, nil
+}
+
+// Point is a located item to cluster
+type Point struct {
+	ID  string
+	Lat float64
+	Lon float64
+}
+
+// Cluster is a group of nearby points. ID is only set for single-point
+// clusters of items that may be identified.
+type Cluster struct {
+	Lat   float64 `json:"lat"`
+	Lon   float64 `json:"lon"`
+	Count int     `json:"count"`
+	ID    string  `json:"id,omitempty"`
+}
+
+// CellSize returns the grid cell size in degrees for a zoom level
+func CellSize(zoom int) float64 {
+	if zoom < 0 {
+		zoom = 0
+	}
+	if zoom > MaxZoom {
+		zoom = MaxZoom
+	}
+	return 360 / (math.Exp2(float64(zoom)) * cellsPerTile)
+}
+
+// GridCluster buckets points into a zoom-dependent grid. A cluster's
+// position is the centroid of its points, or the cell center when anonymize
+// is set so individual locations are never revealed.
+func GridCluster(points []Point, zoom int, anonymize bool) []Cluster {
+	size := CellSize(zoom)
+
+	type cell struct{ x, y int64 }
+	type acc struct {
+		latSum, lonSum float64
+		count          int
+		id             string
+	}
+
+	cells := make(map[cell]*acc)
+	order := make([]cell, 0)
+	for _, p := range points {
+		c := cell{int64(math.Floor((p.Lon + 180) / size)), int64(math.Floor((p.Lat + 90) / size))}
+		a, ok := cells[c]
+		if !ok {
+			a = &acc{}
+			cells[c] = a
+			order = append(order, c)
+		}
+		a.latSum += p.Lat
+		a.lonSum += p.Lon
+		a.count++
+		a.id = p.ID
+	}
+
+	clusters := make([]Cluster, 0, len(order))
+	for _, c := range order {
+		a := cells[c]
+		cluster := Cluster{Count: a.count}
+		if anonymize {
+			cluster.Lon = (float64(c.x)+0.5)*size - 180
+			cluster.Lat = (float64(c.y)+0.5)*size - 90
+		} else {
+			cluster.Lon = a.lonSum / float64(a.count)
+			cluster.Lat = a.latSum / float64(a.count)
+			if a.count == 1 {
+				cluster.ID = a.id
+			}
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters
+}
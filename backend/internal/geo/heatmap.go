@@ -0,0 +1,84 @@
+package geo
+
+import "math"
+
+// MinBucketSize is the smallest volunteer count a heatmap reports; smaller
+// counts are suppressed so sparse cells can't single out volunteers
+const MinBucketSize = 5
+
+// MaxHeatmapZoom bounds heatmap resolution to cells of roughly a kilometer
+const MaxHeatmapZoom = 12
+
+// heatmapCells is the target number of cells across a heatmap's bbox when
+// no zoom is given
+const heatmapCells = 32
+
+// HeatmapBin compares volunteer supply with active project demand in one
+// grid cell. Volunteers is nil when a nonzero count is below MinBucketSize.
+type HeatmapBin struct {
+	Lat        float64 `json:"lat"`
+	Lon        float64 `json:"lon"`
+	Volunteers *int    `json:"volunteers"`
+	Projects   int     `json:"projects"`
+	Suppressed bool    `json:"suppressed,omitempty"`
+}
+
+// FitZoom returns the deepest zoom at which the box spans at most
+// heatmapCells grid cells, capped at MaxHeatmapZoom
+func FitZoom(b BBox) int {
+	span := math.Max(b.MaxLon-b.MinLon, b.MaxLat-b.MinLat)
+	zoom := 0
+	for zoom < MaxHeatmapZoom && span/CellSize(zoom+1) <= heatmapCells {
+		zoom++
+	}
+	return zoom
+}
+
+// Heatmap bins volunteers and projects on the zoom grid, positioned at cell
+// centers. Cells holding only a suppressed volunteer count are dropped.
+func Heatmap(volunteers, projects []Point, zoom int) []HeatmapBin {
+	volunteerCells := GridCluster(volunteers, zoom, true)
+	projectCells := GridCluster(projects, zoom, true)
+
+	type key struct{ lat, lon float64 }
+	bins := make(map[key]*HeatmapBin)
+	order := make([]key, 0, len(volunteerCells)+len(projectCells))
+	bin := func(c Cluster) *HeatmapBin {
+		k := key{c.Lat, c.Lon}
+		b, ok := bins[k]
+		if !ok {
+			b = &HeatmapBin{Lat: c.Lat, Lon: c.Lon}
+			bins[k] = b
+			order = append(order, k)
+		}
+		return b
+	}
+
+	for _, c := range volunteerCells {
+		b := bin(c)
+		if c.Count >= MinBucketSize {
+			count := c.Count
+			b.Volunteers = &count
+		} else {
+			b.Suppressed = true
+		}
+	}
+	for _, c := range projectCells {
+		bin(c).Projects = c.Count
+	}
+
+	result := make([]HeatmapBin, 0, len(order))
+	for _, k := range order {
+		b := bins[k]
+		if b.Suppressed && b.Projects == 0 {
+			continue
+		}
+		if b.Volunteers == nil && !b.Suppressed {
+			zero := 0
+			b.Volunteers = &zero
+		}
+		result = append(result, *b)
+	}
+
+	return result
+}
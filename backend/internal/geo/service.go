@@ -0,0 +1,94 @@
+package geo
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/civic-weave/backend/internal/database"
+)
+
+// ErrTooManyPoints is returned when a bounding box holds more points than
+// can be clustered in one request; clients should zoom in
+var ErrTooManyPoints = errors.New("too many points in bbox")
+
+// maxClusterPoints bounds the points loaded for one clustering request
+const maxClusterPoints = 100000
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// ProjectPoints returns active projects located inside the box, limited to
+// the tenant's organization when tenantID is set
+func (s *Service) ProjectPoints(b BBox, tenantID string) ([]Point, error) {
+	query := `
+		SELECT id, latitude, longitude
+		FROM projects
+		WHERE status = 'active'
+		  AND latitude BETWEEN $1 AND $2
+		  AND longitude BETWEEN $3 AND $4
+		  AND ($5 = '' OR organization_id = NULLIF($5, '')::uuid)
+		LIMIT $6
+	`
+	return s.points(query, b.MinLat, b.MaxLat, b.MinLon, b.MaxLon, tenantID, maxClusterPoints+1)
+}
+
+// VolunteerPoints returns volunteers located inside the box. When tenantID
+// is set only volunteers visible to that organization are included.
+func (s *Service) VolunteerPoints(b BBox, tenantID string) ([]Point, error) {
+	query := `
+		SELECT u.id, u.latitude, u.longitude
+		FROM users u
+		WHERE u.role = 'volunteer'
+		  AND u.latitude BETWEEN $1 AND $2
+		  AND u.longitude BETWEEN $3 AND $4
+		  AND ($5 = '' OR volunteer_visible_to_org(u.id, NULLIF($5, '')::uuid))
+		LIMIT $6
+	`
+	return s.points(query, b.MinLat, b.MaxLat, b.MinLon, b.MaxLon, tenantID, maxClusterPoints+1)
+}
+
+// CanViewVolunteers reports whether the user may see volunteer density:
+// platform admins and coordinators
+func (s *Service) CanViewVolunteers(userID string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND role IN ('admin', 'coordinator'))`
+
+	var allowed bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, userID).Scan(&allowed)
+	})
+	return allowed, err
+}
+
+func (s *Service) points(query string, args ...interface{}) ([]Point, error) {
+	var points []Point
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		points = nil
+		for rows.Next() {
+			var p Point
+			if err := rows.Scan(&p.ID, &p.Lat, &p.Lon); err != nil {
+				return err
+			}
+			points = append(points, p)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(points) > maxClusterPoints {
+		return nil, ErrTooManyPoints
+	}
+
+	return points, nil
+}
@@ -0,0 +1,161 @@
+package i18n
+
+// en is the English bundle. Every code must be here: other bundles fall
+// back to it, and API error messages are matched to codes by their English
+// text.
+var en = map[string]string{
+	// API errors
+	"error.service_unavailable":          "Service temporarily unavailable, please retry",
+	"error.too_many_requests":            "Too many requests, please retry later",
+	"error.invalid_request_body":         "Invalid request body",
+	"error.validation_failed":            "Request validation failed",
+	"error.user_id_required":             "User ID required",
+	"error.check_permissions":            "Failed to check permissions",
+	"error.check_project_permissions":    "Failed to check project permissions",
+	"error.limit_invalid":                "limit must be a positive integer",
+	"error.offset_invalid":               "offset must be a non-negative integer",
+	"error.cursor_invalid":               "cursor is invalid",
+	"error.cursor_offset":                "cursor and offset cannot be combined",
+	"error.sort_invalid":                 "sort names a field the list cannot be sorted by",
+	"error.remote_invalid":               "remote must be true or false",
+	"error.lat_lon_required":             "Valid lat and lon are required",
+	"error.invalid_credentials":          "Invalid email or password",
+	"error.oauth_provider_not_found":     "Sign-in provider not found",
+	"error.oauth_provider_unavailable":   "Sign-in provider unavailable",
+	"error.current_password_incorrect":   "Current password is incorrect",
+	"error.team_name_required":           "Team name is required",
+	"error.account_suspended":            "Account suspended",
+	"error.user_exists":                  "User already exists",
+	"error.skill_exists":                 "Skill already exists",
+	"error.locale_unsupported":           "Unsupported locale",
+	"error.search_unavailable":           "Search is unavailable",
+	"error.user_not_found":               "User not found",
+	"error.volunteer_not_found":          "Volunteer not found",
+	"error.organization_not_found":       "Organization not found",
+	"error.project_not_found":            "Project not found",
+	"error.enrollment_not_found":         "Enrollment not found",
+	"error.team_not_found":               "Team not found",
+	"error.shift_not_found":              "Shift not found",
+	"error.shift_signup_not_found":       "Shift signup not found",
+	"error.coverage_request_not_found":   "Coverage request not found",
+	"error.availability_rule_not_found":  "Availability rule not found",
+	"error.holiday_not_found":            "Holiday not found",
+	"error.blackout_not_found":           "Blackout not found",
+	"error.location_not_found":           "Location not found",
+	"error.region_not_found":             "Region not found",
+	"error.impact_metric_not_found":      "Impact metric not found",
+	"error.evidence_not_found":           "Evidence not found",
+	"error.document_not_found":           "Document not found",
+	"error.photo_not_found":              "Photo not found",
+	"error.waiver_not_found":             "Waiver not found",
+	"error.review_not_found":             "Review not found",
+	"error.reference_not_found":          "Reference not found",
+	"error.report_not_found":             "Report not found",
+	"error.reported_content_not_found":   "Reported content not found",
+	"error.pending_invitation_not_found": "Pending invitation not found",
+	"error.notification_not_found":       "Notification not found",
+	"error.volunteer_not_enrolled":       "Volunteer is not enrolled in the project",
+	"error.fetch_users":                  "Failed to fetch users",
+	"error.fetch_profile":                "Failed to fetch profile",
+	"error.fetch_project":                "Failed to fetch project",
+	"error.login":                        "Failed to login",
+	"error.register":                     "Failed to register user",
+	"error.update_locale":                "Failed to update language",
+
+	// Dates
+	"date.long": "{month} {day}, {year}",
+	"month.1":   "January",
+	"month.2":   "February",
+	"month.3":   "March",
+	"month.4":   "April",
+	"month.5":   "May",
+	"month.6":   "June",
+	"month.7":   "July",
+	"month.8":   "August",
+	"month.9":   "September",
+	"month.10":  "October",
+	"month.11":  "November",
+	"month.12":  "December",
+
+	// Organization roles, as in "join ... as {role}"
+	"role.owner":       "an owner",
+	"role.admin":       "an admin",
+	"role.coordinator": "a coordinator",
+	"role.member":      "a member",
+
+	// Reportable content, as in "your {content}"
+	"content.project": "project",
+	"content.message": "message",
+	"content.profile": "profile",
+
+	// Emails
+	"email.greeting": "Hi {name},",
+
+	"email.invitation.subject": "You're invited to join {organization}",
+	"email.invitation.body": "Hello,\n\n{inviter} has invited you to join {organization} on Civic Weave as {role}.\n\n" +
+		"Accept the invitation here:\n{url}\n\nThis invitation expires on {expires}.",
+	"email.invitation.inviter": "A member of {organization}",
+
+	"email.team_broadcast.footer": "Sent by {sender} to the {team} team of {project}.",
+
+	"email.shift.unnamed":                    "a shift",
+	"email.shift_coverage.subject":           "Can you cover a shift for {project}?",
+	"email.shift_coverage.body":              "{requester} can no longer make {shift} for {project} on {startsAt} and is looking for someone to cover it.",
+	"email.shift_coverage.first_claim":       "The first volunteer to claim it takes the spot.",
+	"email.shift_covered.subject":            "Your shift for {project} is covered",
+	"email.shift_covered.body":               "{claimer} has taken over your place on {shift} for {project} on {startsAt}. You are no longer booked onto this shift.",
+	"email.email_verification.subject":       "Verify your email address for Civic Weave",
+	"email.email_verification.body":          "Thanks for registering as a volunteer on Civic Weave. Confirm this is your email address here:\n{url}\n\nThe link expires on {expires}. Until you confirm, you can sign in but not join projects.",
+	"email.password_reset.subject":           "Reset your Civic Weave password",
+	"email.password_reset.body":              "Someone asked to reset the password for your Civic Weave account. Choose a new password here:\n{url}\n\nThe link works once, for one hour. If you didn't ask for this, you can ignore this email; your password has not changed.",
+	"email.invitation_reminder.subject":      "Your invitation to {project} expires soon",
+	"email.invitation_reminder.body":         "You've been invited to volunteer on {project}. The invitation expires on {expires}, so sign in to Civic Weave to accept or decline it before then.",
+	"email.hour_milestone.subject":           "You've reached {hours} volunteer hours",
+	"email.hour_milestone.body":              "You've now logged {hours} volunteer hours on Civic Weave. Thank you for everything you do!",
+	"email.content_hidden.subject":           "Your {content} has been hidden",
+	"email.content_hidden.body":              "A moderator has hidden your {content} \"{title}\" after it was reported for breaking the Civic Weave community guidelines. It is no longer visible to other users.",
+	"email.account_suspended.subject":        "Your Civic Weave account has been suspended",
+	"email.account_suspended.body":           "Your Civic Weave account has been suspended after your {content} \"{title}\" was reported for breaking the community guidelines. You can't sign in while the suspension is in place.",
+	"email.report_reviewed.subject":          "Your report has been reviewed",
+	"email.report_reviewed.body":             "Thanks for reporting the {content} \"{title}\". {outcome}",
+	"email.report_reviewed.actioned":         "A moderator agreed it breaks the community guidelines and has acted on it.",
+	"email.report_reviewed.dismissed":        "A moderator found it doesn't break the community guidelines, so no action was taken.",
+	"email.quarantine.subject":               "Quarantined infected {kind} upload",
+	"email.quarantine.body":                  "The malware scanner flagged an uploaded {kind} and it has been quarantined.",
+	"email.quarantine.threat":                "Threat: {threat}",
+	"email.quarantine.id":                    "{kind} ID: {id}",
+	"email.quarantine.uploaded_by":           "Uploaded by: {uploader}",
+	"email.quarantine.key":                   "Quarantine key: {key}",
+	"email.quarantine.unknown_uploader":      "unknown",
+	"email.quarantine.action":                "The file is no longer served. Review the uploader's account for other abuse.",
+	"email.coordinator_digest.subject.one":   "1 enrollment request is waiting for you",
+	"email.coordinator_digest.subject.other": "{count} enrollment requests are waiting for you",
+	"email.coordinator_digest.intro":         "Volunteers are waiting to hear back about joining your projects:",
+	"email.coordinator_digest.project":       "- {project}: {pending} pending, oldest waiting {days} days",
+	"email.coordinator_digest.outro":         "Requests nobody answers eventually expire.",
+
+	// Project statuses, as in "{project} is now {status}"
+	"project_status.draft":   "in draft",
+	"project_status.active":  "active",
+	"project_status.retired": "retired",
+
+	// In-app notifications, also emailed
+	"notification.enrollment_requested.title":   "{volunteer} asked to join {project}",
+	"notification.enrollment_requested.body":    "{volunteer} has asked to volunteer on {project}. Sign in to Civic Weave to accept or decline the request.",
+	"notification.enrollment_invited.title":     "You're invited to volunteer on {project}",
+	"notification.enrollment_invited.body":      "You've been invited to volunteer on {project}. Sign in to Civic Weave to accept or decline the invitation.",
+	"notification.enrollment_waitlisted.title":  "You're on the waitlist for {project}",
+	"notification.enrollment_waitlisted.body":   "{project} has all the volunteers it can take for now, so you've been added to its waitlist. You'll be enrolled if a spot opens up.",
+	"notification.enrollment_accepted.title":    "You're enrolled in {project}",
+	"notification.enrollment_accepted.body":     "Your request to volunteer on {project} has been accepted. Welcome aboard!",
+	"notification.invitation_accepted.title":    "{volunteer} joined {project}",
+	"notification.invitation_accepted.body":     "{volunteer} has accepted your invitation to volunteer on {project}.",
+	"notification.enrollment_rejected.title":    "Your request to join {project} was declined",
+	"notification.enrollment_rejected.body":     "Your request to volunteer on {project} wasn't accepted this time. Other projects may still need your skills.",
+	"notification.enrollment_declined.title":    "{volunteer} won't be joining {project}",
+	"notification.enrollment_declined.body":     "{volunteer} has declined to volunteer on {project}.",
+	"notification.project_status_changed.title": "{project} is now {status}",
+	"notification.project_status_changed.body":  "{project}, which you volunteer on, is now {status}.",
+	"notification.project_match.title":          "A new project matches your skills: {project}",
+	"notification.project_match.body":           "{project} has just been published and is looking for volunteers with skills like yours. Sign in to Civic Weave to ask to join.",
+}
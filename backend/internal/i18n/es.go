@@ -0,0 +1,154 @@
+package i18n
+
+var es = map[string]string{
+	// API errors
+	"error.service_unavailable":          "Servicio no disponible temporalmente, inténtelo de nuevo",
+	"error.too_many_requests":            "Demasiadas solicitudes, inténtelo de nuevo más tarde",
+	"error.invalid_request_body":         "Cuerpo de la solicitud no válido",
+	"error.validation_failed":            "La validación de la solicitud ha fallado",
+	"error.user_id_required":             "Se requiere el ID de usuario",
+	"error.check_permissions":            "No se pudieron comprobar los permisos",
+	"error.check_project_permissions":    "No se pudieron comprobar los permisos del proyecto",
+	"error.limit_invalid":                "limit debe ser un entero positivo",
+	"error.offset_invalid":               "offset debe ser un entero no negativo",
+	"error.cursor_invalid":               "cursor no es válido",
+	"error.cursor_offset":                "cursor y offset no se pueden combinar",
+	"error.sort_invalid":                 "sort indica un campo por el que no se puede ordenar la lista",
+	"error.remote_invalid":               "remote debe ser true o false",
+	"error.lat_lon_required":             "Se requieren una latitud (lat) y una longitud (lon) válidas",
+	"error.invalid_credentials":          "Correo electrónico o contraseña no válidos",
+	"error.oauth_provider_not_found":     "Proveedor de inicio de sesión no encontrado",
+	"error.oauth_provider_unavailable":   "Proveedor de inicio de sesión no disponible",
+	"error.current_password_incorrect":   "La contraseña actual es incorrecta",
+	"error.team_name_required":           "El nombre del equipo es obligatorio",
+	"error.account_suspended":            "Cuenta suspendida",
+	"error.user_exists":                  "El usuario ya existe",
+	"error.skill_exists":                 "La habilidad ya existe",
+	"error.locale_unsupported":           "Idioma no admitido",
+	"error.search_unavailable":           "La búsqueda no está disponible",
+	"error.user_not_found":               "Usuario no encontrado",
+	"error.volunteer_not_found":          "Voluntario no encontrado",
+	"error.organization_not_found":       "Organización no encontrada",
+	"error.project_not_found":            "Proyecto no encontrado",
+	"error.enrollment_not_found":         "Inscripción no encontrada",
+	"error.team_not_found":               "Equipo no encontrado",
+	"error.shift_not_found":              "Turno no encontrado",
+	"error.shift_signup_not_found":       "Inscripción al turno no encontrada",
+	"error.coverage_request_not_found":   "Solicitud de reemplazo no encontrada",
+	"error.availability_rule_not_found":  "Regla de disponibilidad no encontrada",
+	"error.holiday_not_found":            "Día festivo no encontrado",
+	"error.blackout_not_found":           "Período de ausencia no encontrado",
+	"error.location_not_found":           "Ubicación no encontrada",
+	"error.region_not_found":             "Región no encontrada",
+	"error.impact_metric_not_found":      "Métrica de impacto no encontrada",
+	"error.evidence_not_found":           "Evidencia no encontrada",
+	"error.document_not_found":           "Documento no encontrado",
+	"error.photo_not_found":              "Foto no encontrada",
+	"error.waiver_not_found":             "Exención no encontrada",
+	"error.review_not_found":             "Reseña no encontrada",
+	"error.reference_not_found":          "Referencia no encontrada",
+	"error.report_not_found":             "Denuncia no encontrada",
+	"error.reported_content_not_found":   "Contenido denunciado no encontrado",
+	"error.pending_invitation_not_found": "Invitación pendiente no encontrada",
+	"error.notification_not_found":       "Notificación no encontrada",
+	"error.volunteer_not_enrolled":       "El voluntario no está inscrito en el proyecto",
+	"error.fetch_users":                  "No se pudieron obtener los usuarios",
+	"error.fetch_profile":                "No se pudo obtener el perfil",
+	"error.fetch_project":                "No se pudo obtener el proyecto",
+	"error.login":                        "No se pudo iniciar sesión",
+	"error.register":                     "No se pudo registrar al usuario",
+	"error.update_locale":                "No se pudo cambiar el idioma",
+
+	// Dates
+	"date.long": "{day} de {month} de {year}",
+	"month.1":   "enero",
+	"month.2":   "febrero",
+	"month.3":   "marzo",
+	"month.4":   "abril",
+	"month.5":   "mayo",
+	"month.6":   "junio",
+	"month.7":   "julio",
+	"month.8":   "agosto",
+	"month.9":   "septiembre",
+	"month.10":  "octubre",
+	"month.11":  "noviembre",
+	"month.12":  "diciembre",
+
+	"role.owner":       "propietario",
+	"role.admin":       "administrador",
+	"role.coordinator": "coordinador",
+	"role.member":      "miembro",
+
+	"content.project": "proyecto",
+	"content.message": "mensaje",
+	"content.profile": "perfil",
+
+	// Emails
+	"email.greeting": "Hola, {name}:",
+
+	"email.invitation.subject": "Te invitan a unirte a {organization}",
+	"email.invitation.body": "Hola:\n\n{inviter} te ha invitado a unirte a {organization} en Civic Weave como {role}.\n\n" +
+		"Acepta la invitación aquí:\n{url}\n\nEsta invitación vence el {expires}.",
+	"email.invitation.inviter": "Un miembro de {organization}",
+
+	"email.team_broadcast.footer": "Enviado por {sender} al equipo {team} de {project}.",
+
+	"email.shift.unnamed":                    "un turno",
+	"email.shift_coverage.subject":           "¿Puedes cubrir un turno de {project}?",
+	"email.shift_coverage.body":              "{requester} ya no puede asistir a {shift} de {project} el {startsAt} y busca a alguien que lo cubra.",
+	"email.shift_coverage.first_claim":       "El primer voluntario que lo solicite se queda con el puesto.",
+	"email.shift_covered.subject":            "Tu turno de {project} está cubierto",
+	"email.shift_covered.body":               "{claimer} ha ocupado tu lugar en {shift} de {project} el {startsAt}. Ya no estás inscrito en este turno.",
+	"email.email_verification.subject":       "Verifica tu correo electrónico para Civic Weave",
+	"email.email_verification.body":          "Gracias por registrarte como voluntario en Civic Weave. Confirma que esta es tu dirección de correo aquí:\n{url}\n\nEl enlace caduca el {expires}. Hasta que lo confirmes, puedes iniciar sesión pero no unirte a proyectos.",
+	"email.password_reset.subject":           "Restablece tu contraseña de Civic Weave",
+	"email.password_reset.body":              "Alguien ha pedido restablecer la contraseña de tu cuenta de Civic Weave. Elige una nueva contraseña aquí:\n{url}\n\nEl enlace funciona una sola vez, durante una hora. Si no lo has pedido tú, ignora este correo; tu contraseña no ha cambiado.",
+	"email.invitation_reminder.subject":      "Tu invitación a {project} vence pronto",
+	"email.invitation_reminder.body":         "Te han invitado a colaborar como voluntario en {project}. La invitación vence el {expires}, así que inicia sesión en Civic Weave para aceptarla o rechazarla antes de esa fecha.",
+	"email.hour_milestone.subject":           "Has alcanzado {hours} horas de voluntariado",
+	"email.hour_milestone.body":              "Ya has registrado {hours} horas de voluntariado en Civic Weave. ¡Gracias por todo lo que haces!",
+	"email.content_hidden.subject":           "Tu {content} ha sido ocultado",
+	"email.content_hidden.body":              "Un moderador ha ocultado tu {content} «{title}» después de que se denunciara por incumplir las normas de la comunidad de Civic Weave. Ya no es visible para otros usuarios.",
+	"email.account_suspended.subject":        "Tu cuenta de Civic Weave ha sido suspendida",
+	"email.account_suspended.body":           "Tu cuenta de Civic Weave ha sido suspendida después de que tu {content} «{title}» se denunciara por incumplir las normas de la comunidad. No puedes iniciar sesión mientras dure la suspensión.",
+	"email.report_reviewed.subject":          "Tu denuncia ha sido revisada",
+	"email.report_reviewed.body":             "Gracias por denunciar el {content} «{title}». {outcome}",
+	"email.report_reviewed.actioned":         "Un moderador ha confirmado que incumple las normas de la comunidad y ha tomado medidas.",
+	"email.report_reviewed.dismissed":        "Un moderador ha determinado que no incumple las normas de la comunidad, por lo que no se ha tomado ninguna medida.",
+	"email.quarantine.subject":               "Archivo {kind} infectado en cuarentena",
+	"email.quarantine.body":                  "El analizador de malware ha marcado un archivo {kind} subido y se ha puesto en cuarentena.",
+	"email.quarantine.threat":                "Amenaza: {threat}",
+	"email.quarantine.id":                    "ID de {kind}: {id}",
+	"email.quarantine.uploaded_by":           "Subido por: {uploader}",
+	"email.quarantine.key":                   "Clave de cuarentena: {key}",
+	"email.quarantine.unknown_uploader":      "desconocido",
+	"email.quarantine.action":                "El archivo ya no se sirve. Revisa la cuenta de quien lo subió por si hay otros abusos.",
+	"email.coordinator_digest.subject.one":   "1 solicitud de inscripción te está esperando",
+	"email.coordinator_digest.subject.other": "{count} solicitudes de inscripción te están esperando",
+	"email.coordinator_digest.intro":         "Hay voluntarios esperando respuesta para unirse a tus proyectos:",
+	"email.coordinator_digest.project":       "- {project}: {pending} pendientes, la más antigua espera desde hace {days} días",
+	"email.coordinator_digest.outro":         "Las solicitudes sin respuesta acaban venciendo.",
+
+	"project_status.draft":   "en borrador",
+	"project_status.active":  "activo",
+	"project_status.retired": "finalizado",
+
+	"notification.enrollment_requested.title":   "{volunteer} quiere unirse a {project}",
+	"notification.enrollment_requested.body":    "{volunteer} ha solicitado colaborar como voluntario en {project}. Inicia sesión en Civic Weave para aceptar o rechazar la solicitud.",
+	"notification.enrollment_invited.title":     "Te han invitado a colaborar en {project}",
+	"notification.enrollment_invited.body":      "Te han invitado a colaborar como voluntario en {project}. Inicia sesión en Civic Weave para aceptar o rechazar la invitación.",
+	"notification.enrollment_waitlisted.title":  "Estás en la lista de espera de {project}",
+	"notification.enrollment_waitlisted.body":   "{project} ya tiene todos los voluntarios que puede recibir por ahora, así que te hemos añadido a su lista de espera. Te inscribiremos si se libera una plaza.",
+	"notification.enrollment_accepted.title":    "Estás inscrito en {project}",
+	"notification.enrollment_accepted.body":     "Tu solicitud para colaborar en {project} ha sido aceptada. ¡Te damos la bienvenida!",
+	"notification.invitation_accepted.title":    "{volunteer} se ha unido a {project}",
+	"notification.invitation_accepted.body":     "{volunteer} ha aceptado tu invitación para colaborar como voluntario en {project}.",
+	"notification.enrollment_rejected.title":    "Tu solicitud para {project} ha sido rechazada",
+	"notification.enrollment_rejected.body":     "Tu solicitud para colaborar en {project} no ha sido aceptada esta vez. Puede que otros proyectos necesiten tus habilidades.",
+	"notification.enrollment_declined.title":    "{volunteer} no se unirá a {project}",
+	"notification.enrollment_declined.body":     "{volunteer} ha rechazado colaborar en {project}.",
+	"notification.project_status_changed.title": "{project} ahora está {status}",
+	"notification.project_status_changed.body":  "{project}, en el que colaboras como voluntario, ahora está {status}.",
+	"notification.project_match.title":          "Un nuevo proyecto encaja con tus habilidades: {project}",
+	"notification.project_match.body":           "{project} se acaba de publicar y busca voluntarios con habilidades como las tuyas. Inicia sesión en Civic Weave para solicitar unirte.",
+}
@@ -0,0 +1,154 @@
+package i18n
+
+var fr = map[string]string{
+	// API errors
+	"error.service_unavailable":          "Service temporairement indisponible, veuillez réessayer",
+	"error.too_many_requests":            "Trop de requêtes, veuillez réessayer plus tard",
+	"error.invalid_request_body":         "Corps de requête invalide",
+	"error.validation_failed":            "La validation de la requête a échoué",
+	"error.user_id_required":             "Identifiant utilisateur requis",
+	"error.check_permissions":            "Impossible de vérifier les autorisations",
+	"error.check_project_permissions":    "Impossible de vérifier les autorisations sur le projet",
+	"error.limit_invalid":                "limit doit être un entier positif",
+	"error.offset_invalid":               "offset doit être un entier positif ou nul",
+	"error.cursor_invalid":               "cursor est invalide",
+	"error.cursor_offset":                "cursor et offset ne peuvent pas être combinés",
+	"error.sort_invalid":                 "sort désigne un champ selon lequel la liste ne peut pas être triée",
+	"error.remote_invalid":               "remote doit valoir true ou false",
+	"error.lat_lon_required":             "Une latitude (lat) et une longitude (lon) valides sont requises",
+	"error.invalid_credentials":          "Adresse e-mail ou mot de passe invalide",
+	"error.oauth_provider_not_found":     "Fournisseur de connexion introuvable",
+	"error.oauth_provider_unavailable":   "Fournisseur de connexion indisponible",
+	"error.current_password_incorrect":   "Le mot de passe actuel est incorrect",
+	"error.team_name_required":           "Le nom de l'équipe est requis",
+	"error.account_suspended":            "Compte suspendu",
+	"error.user_exists":                  "L'utilisateur existe déjà",
+	"error.skill_exists":                 "La compétence existe déjà",
+	"error.locale_unsupported":           "Langue non prise en charge",
+	"error.search_unavailable":           "La recherche est indisponible",
+	"error.user_not_found":               "Utilisateur introuvable",
+	"error.volunteer_not_found":          "Bénévole introuvable",
+	"error.organization_not_found":       "Organisation introuvable",
+	"error.project_not_found":            "Projet introuvable",
+	"error.enrollment_not_found":         "Inscription introuvable",
+	"error.team_not_found":               "Équipe introuvable",
+	"error.shift_not_found":              "Créneau introuvable",
+	"error.shift_signup_not_found":       "Inscription au créneau introuvable",
+	"error.coverage_request_not_found":   "Demande de remplacement introuvable",
+	"error.availability_rule_not_found":  "Règle de disponibilité introuvable",
+	"error.holiday_not_found":            "Jour férié introuvable",
+	"error.blackout_not_found":           "Période d'indisponibilité introuvable",
+	"error.location_not_found":           "Lieu introuvable",
+	"error.region_not_found":             "Région introuvable",
+	"error.impact_metric_not_found":      "Indicateur d'impact introuvable",
+	"error.evidence_not_found":           "Justificatif introuvable",
+	"error.document_not_found":           "Document introuvable",
+	"error.photo_not_found":              "Photo introuvable",
+	"error.waiver_not_found":             "Décharge introuvable",
+	"error.review_not_found":             "Avis introuvable",
+	"error.reference_not_found":          "Recommandation introuvable",
+	"error.report_not_found":             "Signalement introuvable",
+	"error.reported_content_not_found":   "Contenu signalé introuvable",
+	"error.pending_invitation_not_found": "Invitation en attente introuvable",
+	"error.notification_not_found":       "Notification introuvable",
+	"error.volunteer_not_enrolled":       "Le bénévole n'est pas inscrit au projet",
+	"error.fetch_users":                  "Impossible de récupérer les utilisateurs",
+	"error.fetch_profile":                "Impossible de récupérer le profil",
+	"error.fetch_project":                "Impossible de récupérer le projet",
+	"error.login":                        "Échec de la connexion",
+	"error.register":                     "Impossible d'inscrire l'utilisateur",
+	"error.update_locale":                "Impossible de modifier la langue",
+
+	// Dates
+	"date.long": "{day} {month} {year}",
+	"month.1":   "janvier",
+	"month.2":   "février",
+	"month.3":   "mars",
+	"month.4":   "avril",
+	"month.5":   "mai",
+	"month.6":   "juin",
+	"month.7":   "juillet",
+	"month.8":   "août",
+	"month.9":   "septembre",
+	"month.10":  "octobre",
+	"month.11":  "novembre",
+	"month.12":  "décembre",
+
+	"role.owner":       "propriétaire",
+	"role.admin":       "administrateur",
+	"role.coordinator": "coordinateur",
+	"role.member":      "membre",
+
+	"content.project": "projet",
+	"content.message": "message",
+	"content.profile": "profil",
+
+	// Emails
+	"email.greeting": "Bonjour {name},",
+
+	"email.invitation.subject": "Vous êtes invité à rejoindre {organization}",
+	"email.invitation.body": "Bonjour,\n\n{inviter} vous invite à rejoindre {organization} sur Civic Weave en tant que {role}.\n\n" +
+		"Acceptez l'invitation ici :\n{url}\n\nCette invitation expire le {expires}.",
+	"email.invitation.inviter": "Un membre de {organization}",
+
+	"email.team_broadcast.footer": "Envoyé par {sender} à l'équipe {team} du projet {project}.",
+
+	"email.shift.unnamed":                    "un créneau",
+	"email.shift_coverage.subject":           "Pouvez-vous remplacer quelqu'un sur un créneau de {project} ?",
+	"email.shift_coverage.body":              "{requester} ne peut plus assurer {shift} pour {project} le {startsAt} et cherche quelqu'un pour le remplacer.",
+	"email.shift_coverage.first_claim":       "Le premier bénévole à se proposer prend la place.",
+	"email.shift_covered.subject":            "Votre créneau pour {project} est couvert",
+	"email.shift_covered.body":               "{claimer} a pris votre place sur {shift} pour {project} le {startsAt}. Vous n'êtes plus inscrit sur ce créneau.",
+	"email.email_verification.subject":       "Vérifiez votre adresse e-mail pour Civic Weave",
+	"email.email_verification.body":          "Merci de vous être inscrit comme bénévole sur Civic Weave. Confirmez qu'il s'agit bien de votre adresse e-mail ici :\n{url}\n\nLe lien expire le {expires}. Tant que vous n'avez pas confirmé, vous pouvez vous connecter mais pas rejoindre de projets.",
+	"email.password_reset.subject":           "Réinitialisez votre mot de passe Civic Weave",
+	"email.password_reset.body":              "Quelqu'un a demandé à réinitialiser le mot de passe de votre compte Civic Weave. Choisissez un nouveau mot de passe ici :\n{url}\n\nLe lien ne fonctionne qu'une fois, pendant une heure. Si vous n'êtes pas à l'origine de cette demande, ignorez cet e-mail ; votre mot de passe n'a pas changé.",
+	"email.invitation_reminder.subject":      "Votre invitation à {project} expire bientôt",
+	"email.invitation_reminder.body":         "Vous avez été invité à participer comme bénévole à {project}. L'invitation expire le {expires} : connectez-vous à Civic Weave pour l'accepter ou la refuser d'ici là.",
+	"email.hour_milestone.subject":           "Vous avez atteint {hours} heures de bénévolat",
+	"email.hour_milestone.body":              "Vous avez désormais enregistré {hours} heures de bénévolat sur Civic Weave. Merci pour tout ce que vous faites !",
+	"email.content_hidden.subject":           "Votre {content} a été masqué",
+	"email.content_hidden.body":              "Un modérateur a masqué votre {content} « {title} » après un signalement pour non-respect des règles de la communauté Civic Weave. Il n'est plus visible par les autres utilisateurs.",
+	"email.account_suspended.subject":        "Votre compte Civic Weave a été suspendu",
+	"email.account_suspended.body":           "Votre compte Civic Weave a été suspendu après le signalement de votre {content} « {title} » pour non-respect des règles de la communauté. Vous ne pouvez pas vous connecter tant que la suspension est en vigueur.",
+	"email.report_reviewed.subject":          "Votre signalement a été examiné",
+	"email.report_reviewed.body":             "Merci d'avoir signalé le {content} « {title} ». {outcome}",
+	"email.report_reviewed.actioned":         "Un modérateur a constaté qu'il enfreint les règles de la communauté et a pris des mesures.",
+	"email.report_reviewed.dismissed":        "Un modérateur a estimé qu'il n'enfreint pas les règles de la communauté ; aucune mesure n'a été prise.",
+	"email.quarantine.subject":               "Fichier {kind} infecté mis en quarantaine",
+	"email.quarantine.body":                  "L'analyseur antivirus a signalé un fichier {kind} téléversé, qui a été mis en quarantaine.",
+	"email.quarantine.threat":                "Menace : {threat}",
+	"email.quarantine.id":                    "Identifiant {kind} : {id}",
+	"email.quarantine.uploaded_by":           "Téléversé par : {uploader}",
+	"email.quarantine.key":                   "Clé de quarantaine : {key}",
+	"email.quarantine.unknown_uploader":      "inconnu",
+	"email.quarantine.action":                "Le fichier n'est plus servi. Vérifiez le compte de la personne qui l'a téléversé.",
+	"email.coordinator_digest.subject.one":   "1 demande d'inscription vous attend",
+	"email.coordinator_digest.subject.other": "{count} demandes d'inscription vous attendent",
+	"email.coordinator_digest.intro":         "Des bénévoles attendent une réponse pour rejoindre vos projets :",
+	"email.coordinator_digest.project":       "- {project} : {pending} en attente, la plus ancienne depuis {days} jours",
+	"email.coordinator_digest.outro":         "Les demandes sans réponse finissent par expirer.",
+
+	"project_status.draft":   "en brouillon",
+	"project_status.active":  "actif",
+	"project_status.retired": "terminé",
+
+	"notification.enrollment_requested.title":   "{volunteer} souhaite rejoindre {project}",
+	"notification.enrollment_requested.body":    "{volunteer} a demandé à participer comme bénévole à {project}. Connectez-vous à Civic Weave pour accepter ou refuser la demande.",
+	"notification.enrollment_invited.title":     "Vous êtes invité à participer à {project}",
+	"notification.enrollment_invited.body":      "Vous avez été invité à participer comme bénévole à {project}. Connectez-vous à Civic Weave pour accepter ou refuser l'invitation.",
+	"notification.enrollment_waitlisted.title":  "Vous êtes sur la liste d'attente de {project}",
+	"notification.enrollment_waitlisted.body":   "{project} a tous les bénévoles qu'il peut accueillir pour le moment : vous avez été ajouté à sa liste d'attente. Vous serez inscrit si une place se libère.",
+	"notification.enrollment_accepted.title":    "Vous êtes inscrit à {project}",
+	"notification.enrollment_accepted.body":     "Votre demande de participation à {project} a été acceptée. Bienvenue !",
+	"notification.invitation_accepted.title":    "{volunteer} a rejoint {project}",
+	"notification.invitation_accepted.body":     "{volunteer} a accepté votre invitation à participer comme bénévole à {project}.",
+	"notification.enrollment_rejected.title":    "Votre demande pour {project} a été refusée",
+	"notification.enrollment_rejected.body":     "Votre demande de participation à {project} n'a pas été retenue cette fois-ci. D'autres projets ont peut-être besoin de vos compétences.",
+	"notification.enrollment_declined.title":    "{volunteer} ne rejoindra pas {project}",
+	"notification.enrollment_declined.body":     "{volunteer} a décliné la participation à {project}.",
+	"notification.project_status_changed.title": "{project} est maintenant {status}",
+	"notification.project_status_changed.body":  "{project}, auquel vous participez comme bénévole, est maintenant {status}.",
+	"notification.project_match.title":          "Un nouveau projet correspond à vos compétences : {project}",
+	"notification.project_match.body":           "{project} vient d'être publié et recherche des bénévoles aux compétences comme les vôtres. Connectez-vous à Civic Weave pour demander à le rejoindre.",
+}
@@ -0,0 +1,142 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default is the locale used when a client or user names none we support,
+// and the fallback for messages missing from a bundle
+const Default = "en"
+
+// bundles maps each supported locale to its messages by code
+var bundles = map[string]map[string]string{
+	"en": en,
+	"fr": fr,
+	"es": es,
+}
+
+// errorCodes maps the English text of API error messages to their codes
+var errorCodes = map[string]string{}
+
+func init() {
+	for code, text := range en {
+		if strings.HasPrefix(code, "error.") {
+			errorCodes[text] = code
+		}
+	}
+}
+
+// Args fill the {name} placeholders of a message
+type Args map[string]interface{}
+
+// Supported lists the locales with a bundle
+func Supported() []string {
+	locales := make([]string, 0, len(bundles))
+	for locale := range bundles {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Normalize returns the supported locale for a language tag such as fr or
+// fr-CA, reporting false when there is none
+func Normalize(tag string) (string, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	base, _, _ = strings.Cut(base, "_")
+	_, ok := bundles[base]
+	return base, ok
+}
+
+// T returns the message with code in locale, with args filled in. Messages
+// missing from the locale's bundle fall back to English, then to the code.
+func T(locale, code string, args Args) string {
+	text, ok := bundles[locale][code]
+	if !ok {
+		text, ok = en[code]
+	}
+	if !ok {
+		text = code
+	}
+	for name, value := range args {
+		text = strings.ReplaceAll(text, "{"+name+"}", fmt.Sprint(value))
+	}
+	return text
+}
+
+// Error returns the code and translation of an API error message, which
+// handlers give in English. Messages without a code are returned as they
+// are, with an empty code.
+func Error(locale, message string) (code, text string) {
+	code, ok := errorCodes[message]
+	if !ok {
+		return "", message
+	}
+	return code, T(locale, code, nil)
+}
+
+// Date formats t as a date written out in locale, e.g. January 2, 2006
+func Date(locale string, t time.Time) string {
+	month := T(locale, "month."+strconv.Itoa(int(t.Month())), nil)
+	return T(locale, "date.long", Args{"day": t.Day(), "month": month, "year": t.Year()})
+}
+
+// Negotiate picks the supported locale a client prefers most from an
+// Accept-Language header, or Default when it accepts none of them
+func Negotiate(header string) string {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		// Earlier tags win ties
+		if locale, ok := Normalize(tag); ok && q > bestQ {
+			best, bestQ = locale, q
+		}
+	}
+	return best
+}
+
+type contextKey struct{}
+
+// WithLocale returns a copy of ctx carrying locale
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale the request negotiated, or Default
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(contextKey{}).(string); ok {
+		return locale
+	}
+	return Default
+}
+
+// FromRequest is shorthand for FromContext(r.Context())
+func FromRequest(r *http.Request) string {
+	return FromContext(r.Context())
+}
+
+// Middleware negotiates each request's locale from Accept-Language, adding
+// it to the request context and declaring it as the response's
+// Content-Language, which the API's error responses are written in
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := Negotiate(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", locale)
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r.WithContext(WithLocale(r.Context(), locale)))
+	})
+}
@@ -0,0 +1,282 @@
+// Package idempotency lets clients retry requests that create records
+// without creating them twice. A request sent with an Idempotency-Key
+// header runs once; retries with the same key get its stored response.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/apikeys"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/logging"
+)
+
+// Headers of idempotent requests and their replayed responses
+const (
+	Header         = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+)
+
+// PurgeJob is the kind of background job that runs Purge
+const PurgeJob = "idempotency.purge"
+
+// Consumer types keys are kept for
+const (
+	consumerAPIKey = "api_key"
+	consumerUser   = "user"
+)
+
+const (
+	maxKeyLength = 255
+	maxBodyBytes = 1 << 20
+	// abandonAfter is how long a first request may run before its key is
+	// treated as abandoned, as when its instance stopped mid-request
+	abandonAfter = time.Minute
+)
+
+var (
+	ErrKeyReused  = errors.New("idempotency key was used for a different request")
+	ErrInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// response is a stored response
+type response struct {
+	status      int
+	contentType string
+	body        []byte
+}
+
+// Service keeps the responses of idempotent requests in Postgres, so a
+// retry reaching any instance gets the original response
+type Service struct {
+	db  *sql.DB
+	ttl time.Duration
+}
+
+// NewService keeps responses for ttl
+func NewService(db *sql.DB, ttl time.Duration) *Service {
+	return &Service{db: db, ttl: ttl}
+}
+
+// Wrap makes next idempotent for requests with an Idempotency-Key header.
+// The first request with a key runs and its response is stored; retries
+// with the same key and body get that response again, marked with
+// Idempotent-Replayed. A key reused for a different request gets 422, and
+// one whose first request is still running gets 409. Server errors aren't
+// stored, so those requests can be retried. Requests without the header,
+// or without a user or API key, run as usual.
+func (s *Service) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(Header)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxKeyLength {
+			apierror.Write(w, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		var consumerType, consumerID string
+		if apiKey := apikeys.FromRequest(r); apiKey != nil {
+			consumerType, consumerID = consumerAPIKey, apiKey.ID
+		} else if userID := auth.UserID(r); userID != "" {
+			consumerType, consumerID = consumerUser, userID
+		} else {
+			next(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err != nil {
+			apierror.Write(w, http.StatusRequestEntityTooLarge, "Payload is too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256([]byte(r.Method + " " + r.URL.Path + "\n" + string(body)))
+		hash := hex.EncodeToString(sum[:])
+
+		stored, err := s.claim(consumerType, consumerID, key, r.Method, r.URL.Path, hash)
+		switch {
+		case errors.Is(err, ErrKeyReused):
+			apierror.Write(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+			return
+		case errors.Is(err, ErrInProgress):
+			w.Header().Set("Retry-After", "1")
+			apierror.Write(w, http.StatusConflict, "A request with this Idempotency-Key is still in progress")
+			return
+		case err != nil:
+			logging.FromRequest(r).Error("Idempotency key claim error", "key", key, "error", err)
+			apierror.WriteErr(w, err, http.StatusInternalServerError, "Failed to check Idempotency-Key")
+			return
+		case stored != nil:
+			if stored.contentType != "" {
+				w.Header().Set("Content-Type", stored.contentType)
+			}
+			w.Header().Set(HeaderReplayed, "true")
+			w.WriteHeader(stored.status)
+			w.Write(stored.body)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w}
+		completed := false
+		// Release the key if next fails or panics, so the request can run again
+		defer func() {
+			if completed {
+				return
+			}
+			if err := s.release(consumerType, consumerID, key); err != nil {
+				logging.FromRequest(r).Error("Idempotency key release error", "key", key, "error", err)
+			}
+		}()
+
+		next(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		if err := s.complete(consumerType, consumerID, key, status, w.Header().Get("Content-Type"), rec.body.Bytes()); err != nil {
+			logging.FromRequest(r).Error("Idempotency key store error", "key", key, "error", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// claim records that a request with hash is running under the key. It
+// returns the stored response of an earlier request with the key, or nil
+// when this request should run. Keys past their ttl, or whose first request
+// was abandoned, are claimed afresh.
+func (s *Service) claim(consumerType, consumerID, key, method, path, hash string) (*response, error) {
+	// The key may be released between the insert and the select, so try twice
+	for attempt := 0; attempt < 2; attempt++ {
+		var claimed bool
+		err := database.WithWriteGuard(func() error {
+			return s.db.QueryRow(`
+				INSERT INTO idempotency_keys (consumer_type, consumer_id, key, method, path, request_hash)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (consumer_type, consumer_id, key) DO UPDATE
+				SET method = EXCLUDED.method, path = EXCLUDED.path, request_hash = EXCLUDED.request_hash,
+				    status = NULL, content_type = NULL, body = NULL,
+				    created_at = CURRENT_TIMESTAMP, completed_at = NULL
+				WHERE idempotency_keys.created_at < CURRENT_TIMESTAMP - $7 * INTERVAL '1 second'
+				   OR (idempotency_keys.status IS NULL AND idempotency_keys.created_at < CURRENT_TIMESTAMP - $8 * INTERVAL '1 second')
+				RETURNING true
+			`, consumerType, consumerID, key, method, path, hash, s.ttl.Seconds(), abandonAfter.Seconds()).Scan(&claimed)
+		})
+		if err == nil {
+			return nil, nil
+		}
+		if err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		var storedHash string
+		var status sql.NullInt64
+		var contentType sql.NullString
+		var body []byte
+		err = database.WithReadRetry(func() error {
+			return s.db.QueryRow(`
+				SELECT request_hash, status, content_type, body
+				FROM idempotency_keys
+				WHERE consumer_type = $1 AND consumer_id = $2 AND key = $3
+			`, consumerType, consumerID, key).Scan(&storedHash, &status, &contentType, &body)
+		})
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case storedHash != hash:
+			return nil, ErrKeyReused
+		case !status.Valid:
+			return nil, ErrInProgress
+		}
+		return &response{status: int(status.Int64), contentType: contentType.String, body: body}, nil
+	}
+	return nil, ErrInProgress
+}
+
+// complete stores the response of the request running under the key
+func (s *Service) complete(consumerType, consumerID, key string, status int, contentType string, body []byte) error {
+	return database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(`
+			UPDATE idempotency_keys
+			SET status = $4, content_type = NULLIF($5, ''), body = $6, completed_at = CURRENT_TIMESTAMP
+			WHERE consumer_type = $1 AND consumer_id = $2 AND key = $3
+		`, consumerType, consumerID, key, status, contentType, body)
+		return err
+	})
+}
+
+// release forgets a key whose request failed, so a retry runs again
+func (s *Service) release(consumerType, consumerID, key string) error {
+	return database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(`
+			DELETE FROM idempotency_keys
+			WHERE consumer_type = $1 AND consumer_id = $2 AND key = $3 AND status IS NULL
+		`, consumerType, consumerID, key)
+		return err
+	})
+}
+
+// Purge deletes the keys kept longer than the ttl, returning how many
+func (s *Service) Purge(ctx context.Context) (int64, error) {
+	var n int64
+	err := database.WithWriteGuard(func() error {
+		res, err := s.db.ExecContext(ctx, `
+			DELETE FROM idempotency_keys WHERE created_at < CURRENT_TIMESTAMP - $1 * INTERVAL '1 second'
+		`, s.ttl.Seconds())
+		if err != nil {
+			return err
+		}
+		n, err = res.RowsAffected()
+		return err
+	})
+	return n, err
+}
+
+// recorder keeps a copy of the response it writes
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *recorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
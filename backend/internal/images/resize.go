@@ -0,0 +1,60 @@
+package images
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+)
+
+const (
+	quality = 80
+	// samples per axis averaged into each output pixel
+	samples = 4
+)
+
+// Resize scales img down to fit within size on both sides, keeping its
+// aspect ratio, and encodes it as JPEG. Smaller images keep their size.
+// Each pixel averages a grid of samples from the area it covers, which is
+// plenty for previews and display sizes.
+func Resize(img image.Image, size int) ([]byte, error) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		for x := 0; x < tw; x++ {
+			var r, g, b, a uint32
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					px := bounds.Min.X + (x*samples+sx)*w/(tw*samples)
+					py := bounds.Min.Y + (y*samples+sy)*h/(th*samples)
+					pr, pg, pb, pa := img.At(px, py).RGBA()
+					r, g, b, a = r+pr, g+pg, b+pb, a+pa
+				}
+			}
+			n := uint32(samples * samples)
+			dst.Set(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(b / n), uint16(a / n)})
+		}
+	}
+
+	// JPEG has no transparency; flatten onto white
+	flat := image.NewRGBA(dst.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), dst, image.Point{}, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
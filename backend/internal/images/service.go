@@ -0,0 +1,244 @@
+package images
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"time"
+
+	"github.com/civic-weave/backend/internal/blobstore"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/logging"
+)
+
+const (
+	// maxAttempts is how often an image is tried before it is left without
+	// variants
+	maxAttempts = 3
+	batchSize   = 20
+)
+
+// source is a kind of uploaded image that gets variants. Each query takes
+// the row ID and the image's key first so results for an image that has
+// since been replaced are discarded.
+type source struct {
+	name     string
+	variants []Variant
+	// pending selects the ID and key of images without variants, taking
+	// the attempt limit and batch size
+	pending string
+	// save records the variant URLs as JSON
+	save string
+	// fail counts a failed attempt
+	fail string
+}
+
+var sources = []source{
+	{
+		name:     "avatar",
+		variants: AvatarVariants,
+		pending: `
+			SELECT id::text, avatar_key FROM users
+			WHERE avatar_key IS NOT NULL AND avatar_scan_status = 'clean' AND avatar_variants IS NULL AND avatar_variant_attempts < $1
+			ORDER BY updated_at
+			LIMIT $2
+		`,
+		save: `UPDATE users SET avatar_variants = $3 WHERE id::text = $1 AND avatar_key = $2`,
+		fail: `UPDATE users SET avatar_variant_attempts = avatar_variant_attempts + 1 WHERE id::text = $1 AND avatar_key = $2`,
+	},
+	{
+		name:     "photo",
+		variants: PhotoVariants,
+		pending: `
+			SELECT id::text, photo_key FROM project_photos
+			WHERE scan_status = 'clean' AND variants IS NULL AND variant_attempts < $1
+			ORDER BY created_at
+			LIMIT $2
+		`,
+		save: `UPDATE project_photos SET variants = $3 WHERE id::text = $1 AND photo_key = $2`,
+		fail: `UPDATE project_photos SET variant_attempts = variant_attempts + 1 WHERE id::text = $1 AND photo_key = $2`,
+	},
+}
+
+// Service generates the standard sizes of uploaded avatars and project
+// photos in the background, once they pass the malware scan, so clients
+// don't download full-size originals
+type Service struct {
+	db    *sql.DB
+	store blobstore.Store
+	wake  chan struct{}
+}
+
+func NewService(db *sql.DB, store blobstore.Store) *Service {
+	return &Service{db: db, store: store, wake: make(chan struct{}, 1)}
+}
+
+// Wake asks Run to look for new images now rather than at the next interval
+func (s *Service) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run generates pending variants now, then every interval or when woken,
+// until ctx is cancelled
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.ProcessPending(ctx); err != nil {
+			logging.FromContext(ctx).Error("Image variants error", "error", err)
+		} else if n > 0 {
+			logging.FromContext(ctx).Info("Image variants generated", "images", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// ProcessPending generates variants for every image still missing them and
+// returns how many images were done. Images that fail are retried on later
+// runs up to maxAttempts times.
+func (s *Service) ProcessPending(ctx context.Context) (int, error) {
+	done := 0
+	for _, src := range sources {
+		for ctx.Err() == nil {
+			pending, err := s.pending(src)
+			if err != nil {
+				return done, err
+			}
+
+			for _, p := range pending {
+				if ctx.Err() != nil {
+					break
+				}
+				ok, err := s.process(ctx, src, p.id, p.key)
+				if err != nil {
+					return done, err
+				}
+				if ok {
+					done++
+				}
+			}
+
+			if len(pending) < batchSize {
+				break
+			}
+		}
+	}
+	return done, ctx.Err()
+}
+
+type pendingImage struct {
+	id, key string
+}
+
+func (s *Service) pending(src source) ([]pendingImage, error) {
+	var pending []pendingImage
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(src.pending, maxAttempts, batchSize)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		pending = nil
+		for rows.Next() {
+			var p pendingImage
+			if err := rows.Scan(&p.id, &p.key); err != nil {
+				return err
+			}
+			pending = append(pending, p)
+		}
+		return rows.Err()
+	})
+	return pending, err
+}
+
+// process generates and records one image's variants. A failure to generate
+// them is logged and counted against the image; only database errors are
+// returned.
+func (s *Service) process(ctx context.Context, src source, id, key string) (bool, error) {
+	urls, err := s.generate(ctx, src.variants, key)
+	if err != nil && ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Image variants error", "source", src.name, "id", id, "key", key, "error", err)
+		return false, database.WithWriteGuard(func() error {
+			_, err := s.db.Exec(src.fail, id, key)
+			return err
+		})
+	}
+
+	variants, err := json.Marshal(urls)
+	if err != nil {
+		return false, err
+	}
+
+	var result sql.Result
+	err = database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(src.save, id, key, variants)
+		return err
+	})
+	if err != nil {
+		s.deleteBlobs(ctx, VariantKeys(key, src.variants))
+		return false, err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		// The image was replaced or deleted while its variants were made
+		s.deleteBlobs(ctx, VariantKeys(key, src.variants))
+		return false, nil
+	}
+	return true, nil
+}
+
+// generate stores every variant of the image at key and returns their URLs
+// by variant name
+func (s *Service) generate(ctx context.Context, variants []Variant, key string) (map[string]string, error) {
+	r, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(r)
+	r.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make(map[string]string, len(variants))
+	for _, v := range variants {
+		data, err := Resize(img, v.Size)
+		if err != nil {
+			return nil, err
+		}
+		variantKey := VariantKey(key, v.Name)
+		if err := s.store.Put(ctx, variantKey, "image/jpeg", data); err != nil {
+			return nil, err
+		}
+		urls[v.Name] = s.store.URL(variantKey)
+	}
+	return urls, nil
+}
+
+// deleteBlobs removes variants that are no longer referenced; failures only
+// leave orphaned files behind, so they are logged
+func (s *Service) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			logging.FromContext(ctx).Error("Delete image variant error", "key", key, "error", err)
+		}
+	}
+}
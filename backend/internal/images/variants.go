@@ -0,0 +1,34 @@
+package images
+
+import (
+	"path"
+	"strings"
+)
+
+// Variant is a standard size generated for uploaded images: a JPEG scaled
+// to fit within Size pixels on both sides
+type Variant struct {
+	Name string
+	Size int
+}
+
+var (
+	AvatarVariants = []Variant{{"small", 64}, {"medium", 128}, {"large", 256}}
+	PhotoVariants  = []Variant{{"medium", 800}, {"large", 1600}}
+)
+
+// VariantKey is the blob store key of the named variant of the image stored
+// at key
+func VariantKey(key, name string) string {
+	return strings.TrimSuffix(key, path.Ext(key)) + "_" + name + ".jpg"
+}
+
+// VariantKeys lists the keys every variant of the image at key is stored
+// under, whether or not they have been generated yet
+func VariantKeys(key string, variants []Variant) []string {
+	keys := make([]string, len(variants))
+	for i, v := range variants {
+		keys[i] = VariantKey(key, v.Name)
+	}
+	return keys
+}
@@ -0,0 +1,374 @@
+package impact
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrProjectNotFound    = errors.New("project not found")
+	ErrMetricNotFound     = errors.New("impact metric not found")
+	ErrNameRequired       = errors.New("metric name is required")
+	ErrUnitRequired       = errors.New("metric unit is required")
+	ErrNameTooLong        = errors.New("metric name must be at most 100 characters and unit at most 50")
+	ErrMetricNameTaken    = errors.New("metric name already used in this project")
+	ErrInvalidValue       = errors.New("value must be zero or greater")
+	ErrInvalidRecordedOn  = errors.New("recordedOn must be a YYYY-MM-DD date that is not in the future")
+	ErrInvalidImpactRange = errors.New("from and to must be YYYY-MM-DD dates with from not after to")
+)
+
+const dateLayout = "2006-01-02"
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+const metricSelect = `
+	SELECT m.id, m.project_id, m.name, m.unit, m.description, m.created_by, m.created_at,
+	       COALESCE(SUM(e.value), 0), COUNT(e.id), to_char(MAX(e.recorded_on), 'YYYY-MM-DD')
+	FROM project_impact_metrics m
+	JOIN projects p ON p.id = m.project_id
+	LEFT JOIN project_impact_entries e ON e.metric_id = m.id
+`
+
+func scanMetric(scanner interface{ Scan(...interface{}) error }, m *models.ImpactMetric) error {
+	return scanner.Scan(
+		&m.ID,
+		&m.ProjectID,
+		&m.Name,
+		&m.Unit,
+		&m.Description,
+		&m.CreatedBy,
+		&m.CreatedAt,
+		&m.Total,
+		&m.EntryCount,
+		&m.LastRecordedOn,
+	)
+}
+
+// GetProjectMetrics lists a project's metrics with their totals; projects
+// outside the tenant are reported as ErrProjectNotFound
+func (s *Service) GetProjectMetrics(projectID, tenantID string) ([]models.ImpactMetric, error) {
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	query := metricSelect + `
+		WHERE m.project_id = $1
+		GROUP BY m.id
+		ORDER BY m.name
+	`
+
+	var metrics []models.ImpactMetric
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, projectID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		metrics = []models.ImpactMetric{}
+		for rows.Next() {
+			var m models.ImpactMetric
+			if err := scanMetric(rows, &m); err != nil {
+				return err
+			}
+			metrics = append(metrics, m)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+// GetMetric returns one of a project's metrics with its totals
+func (s *Service) GetMetric(projectID, metricID, tenantID string) (*models.ImpactMetric, error) {
+	query := metricSelect + `
+		WHERE m.id = $1
+		  AND m.project_id = $2
+		  AND ($3 = '' OR p.organization_id = NULLIF($3, '')::uuid)
+		GROUP BY m.id
+	`
+
+	var m models.ImpactMetric
+	err := database.WithReadRetry(func() error {
+		return scanMetric(s.db.QueryRow(query, metricID, projectID, tenantID), &m)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrMetricNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// CreateMetric defines a new metric for the project
+func (s *Service) CreateMetric(projectID, tenantID, createdBy string, req models.CreateImpactMetricRequest) (*models.ImpactMetric, error) {
+	name := strings.TrimSpace(req.Name)
+	unit := strings.TrimSpace(req.Unit)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+	if unit == "" {
+		return nil, ErrUnitRequired
+	}
+	if len(name) > 100 || len(unit) > 50 {
+		return nil, ErrNameTooLong
+	}
+
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO project_impact_metrics (project_id, name, unit, description, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, project_id, name, unit, description, created_by, created_at
+	`
+
+	m := models.ImpactMetric{}
+	err := database.WithWriteGuard(func() error {
+		return s.db.QueryRow(query, projectID, name, unit, req.Description, createdBy).Scan(
+			&m.ID,
+			&m.ProjectID,
+			&m.Name,
+			&m.Unit,
+			&m.Description,
+			&m.CreatedBy,
+			&m.CreatedAt,
+		)
+	})
+	if isUniqueViolation(err) {
+		return nil, ErrMetricNameTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// DeleteMetric removes a metric and all its entries
+func (s *Service) DeleteMetric(projectID, metricID, tenantID string) error {
+	query := `
+		DELETE FROM project_impact_metrics m
+		USING projects p
+		WHERE m.id = $1
+		  AND m.project_id = $2
+		  AND p.id = m.project_id
+		  AND ($3 = '' OR p.organization_id = NULLIF($3, '')::uuid)
+	`
+
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(query, metricID, projectID, tenantID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrMetricNotFound
+	}
+
+	return nil
+}
+
+// LogEntry records a value against a metric
+func (s *Service) LogEntry(projectID, metricID, tenantID, recordedBy string, req models.LogImpactEntryRequest) (*models.ImpactEntry, error) {
+	if req.Value < 0 {
+		return nil, ErrInvalidValue
+	}
+	recordedOn, err := time.Parse(dateLayout, req.RecordedOn)
+	if err != nil || recordedOn.After(time.Now()) {
+		return nil, ErrInvalidRecordedOn
+	}
+
+	if _, err := s.GetMetric(projectID, metricID, tenantID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO project_impact_entries (metric_id, value, recorded_on, note, recorded_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + entryColumns
+
+	var entry models.ImpactEntry
+	err = database.WithWriteGuard(func() error {
+		return scanEntry(s.db.QueryRow(query, metricID, req.Value, req.RecordedOn, req.Note, recordedBy), &entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+const entryColumns = `id, metric_id, value, to_char(recorded_on, 'YYYY-MM-DD'), note, recorded_by, created_at`
+
+func scanEntry(scanner interface{ Scan(...interface{}) error }, e *models.ImpactEntry) error {
+	return scanner.Scan(
+		&e.ID,
+		&e.MetricID,
+		&e.Value,
+		&e.RecordedOn,
+		&e.Note,
+		&e.RecordedBy,
+		&e.CreatedAt,
+	)
+}
+
+// GetEntries lists a metric's entries, newest first, optionally limited to
+// an inclusive date range
+func (s *Service) GetEntries(projectID, metricID, tenantID, from, to string) ([]models.ImpactEntry, error) {
+	if err := validateRange(from, to); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetMetric(projectID, metricID, tenantID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + entryColumns + `
+		FROM project_impact_entries
+		WHERE metric_id = $1
+		  AND ($2 = '' OR recorded_on >= NULLIF($2, '')::date)
+		  AND ($3 = '' OR recorded_on <= NULLIF($3, '')::date)
+		ORDER BY recorded_on DESC, created_at DESC
+	`
+
+	var entries []models.ImpactEntry
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, metricID, from, to)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		entries = []models.ImpactEntry{}
+		for rows.Next() {
+			var e models.ImpactEntry
+			if err := scanEntry(rows, &e); err != nil {
+				return err
+			}
+			entries = append(entries, e)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetOrganizationImpact totals values recorded within the inclusive range
+// across the organization's projects, combining metrics that share a name
+// (case-insensitively) and unit
+func (s *Service) GetOrganizationImpact(orgID string, from, to time.Time) (*models.OrganizationImpactReport, error) {
+	query := `
+		SELECT MIN(m.name), m.unit, COALESCE(SUM(e.value), 0), COUNT(DISTINCT m.project_id)
+		FROM project_impact_metrics m
+		JOIN projects p ON p.id = m.project_id
+		LEFT JOIN project_impact_entries e
+		       ON e.metric_id = m.id AND e.recorded_on BETWEEN $2::date AND $3::date
+		WHERE p.organization_id = $1
+		GROUP BY lower(m.name), m.unit
+		ORDER BY lower(m.name), m.unit
+	`
+
+	fromDate := from.Format(dateLayout)
+	toDate := to.Format(dateLayout)
+	report := models.OrganizationImpactReport{OrganizationID: orgID, From: fromDate, To: toDate}
+
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, orgID, fromDate, toDate)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		report.Metrics = []models.ImpactTotal{}
+		for rows.Next() {
+			var t models.ImpactTotal
+			if err := rows.Scan(&t.Name, &t.Unit, &t.Total, &t.Projects); err != nil {
+				return err
+			}
+			report.Metrics = append(report.Metrics, t)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func validateRange(from, to string) error {
+	var start, end time.Time
+	var err error
+	if from != "" {
+		if start, err = time.Parse(dateLayout, from); err != nil {
+			return ErrInvalidImpactRange
+		}
+	}
+	if to != "" {
+		if end, err = time.Parse(dateLayout, to); err != nil {
+			return ErrInvalidImpactRange
+		}
+	}
+	if from != "" && to != "" && start.After(end) {
+		return ErrInvalidImpactRange
+	}
+	return nil
+}
+
+func (s *Service) requireProjectInTenant(projectID, tenantID string) error {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM projects
+			WHERE id = $1
+			  AND ($2 = '' OR organization_id = NULLIF($2, '')::uuid)
+		)
+	`
+
+	var exists bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, projectID, tenantID).Scan(&exists)
+	})
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
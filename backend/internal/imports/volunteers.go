@@ -0,0 +1,330 @@
+package imports
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/skills"
+	"github.com/lib/pq"
+)
+
+// MaxVolunteerRows bounds how many volunteers one CSV may import
+const MaxVolunteerRows = 5000
+
+var (
+	ErrMissingHeader   = errors.New("CSV must start with a header row naming the name and email columns")
+	ErrNoRows          = errors.New("CSV contains no volunteers")
+	ErrTooManyRows     = fmt.Errorf("CSV may contain at most %d volunteers", MaxVolunteerRows)
+	ErrDuplicateColumn = errors.New("CSV header names a column more than once")
+)
+
+// InvalidCSVError reports a file that is not well-formed CSV
+type InvalidCSVError struct {
+	Err error
+}
+
+func (e *InvalidCSVError) Error() string {
+	return "invalid CSV: " + e.Err.Error()
+}
+
+// volunteerColumns are the columns a volunteer CSV may have; name and email
+// are required
+var volunteerColumns = map[string]bool{
+	"name":      true,
+	"email":     true,
+	"skills":    true,
+	"location":  true,
+	"latitude":  true,
+	"longitude": true,
+}
+
+// volunteerRow is a parsed CSV row
+type volunteerRow struct {
+	report       *models.VolunteerImportRow
+	skillIDs     []string
+	locationName *string
+	latitude     *float64
+	longitude    *float64
+}
+
+type Service struct {
+	db            *sql.DB
+	skillsService *skills.Service
+}
+
+func NewService(db *sql.DB, skillsService *skills.Service) *Service {
+	return &Service{db: db, skillsService: skillsService}
+}
+
+// ImportVolunteers creates a volunteer for each row of a CSV with name and
+// email columns and optional skills, location, latitude and longitude.
+// Skills are separated by semicolons and matched by name or alias,
+// regardless of case. Volunteers join the tenant's organization when
+// tenantID is set. Every row is validated first and all volunteers are
+// created in one transaction, so either every row is imported or none is;
+// dryRun only validates.
+func (s *Service) ImportVolunteers(ctx context.Context, r io.Reader, tenantID string, dryRun bool) (*models.VolunteerImportReport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrMissingHeader
+	}
+	if err != nil {
+		return nil, &InvalidCSVError{Err: err}
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !volunteerColumns[name] {
+			continue
+		}
+		if _, ok := columns[name]; ok {
+			return nil, ErrDuplicateColumn
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, ErrMissingHeader
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, ErrMissingHeader
+	}
+
+	var records [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &InvalidCSVError{Err: err}
+		}
+		if blank(record) {
+			continue
+		}
+		if len(records) == MaxVolunteerRows {
+			return nil, ErrTooManyRows
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+	if len(records) == 0 {
+		return nil, ErrNoRows
+	}
+
+	skillIndex, err := s.skillsService.NameIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	emails := make([]string, 0, len(records))
+	rows := make([]volunteerRow, 0, len(records))
+	report := &models.VolunteerImportReport{
+		DryRun: dryRun,
+		Total:  len(records),
+		Rows:   make([]models.VolunteerImportRow, len(records)),
+	}
+	firstRow := make(map[string]int, len(records))
+	for i, record := range records {
+		field := func(column string) string {
+			if index, ok := columns[column]; ok && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+
+		report.Rows[i] = models.VolunteerImportRow{Row: lines[i], Skills: []string{}}
+		row := volunteerRow{report: &report.Rows[i]}
+		row.report.Name = field("name")
+		row.report.Email = strings.ToLower(field("email"))
+
+		if row.report.Name == "" {
+			row.fail("name is required")
+		} else if len(row.report.Name) > 255 {
+			row.fail("name must be at most 255 characters")
+		}
+		if row.report.Email == "" {
+			row.fail("email is required")
+		} else if addr, err := mail.ParseAddress(row.report.Email); err != nil || addr.Address != row.report.Email {
+			row.fail(fmt.Sprintf("email %q is not a valid address", row.report.Email))
+		} else if first, ok := firstRow[row.report.Email]; ok {
+			row.fail(fmt.Sprintf("email is repeated from row %d", first))
+		} else {
+			firstRow[row.report.Email] = row.report.Row
+			emails = append(emails, row.report.Email)
+		}
+
+		seenSkills := make(map[string]bool)
+		for _, name := range strings.Split(field("skills"), ";") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			skill, ok := skillIndex[strings.ToLower(name)]
+			if !ok {
+				row.fail(fmt.Sprintf("unknown skill %q", name))
+				continue
+			}
+			if !seenSkills[skill.ID] {
+				seenSkills[skill.ID] = true
+				row.skillIDs = append(row.skillIDs, skill.ID)
+				row.report.Skills = append(row.report.Skills, skill.Name)
+			}
+		}
+
+		if name := field("location"); name != "" {
+			if len(name) > 255 {
+				row.fail("location must be at most 255 characters")
+			}
+			row.locationName = &name
+		}
+		lat, lon := field("latitude"), field("longitude")
+		if lat != "" || lon != "" {
+			latitude, latErr := strconv.ParseFloat(lat, 64)
+			longitude, lonErr := strconv.ParseFloat(lon, 64)
+			if latErr != nil || lonErr != nil || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+				row.fail("latitude and longitude must be valid coordinates, given together")
+			} else {
+				row.latitude, row.longitude = &latitude, &longitude
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	existing, err := s.existingEmails(emails)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if existing[row.report.Email] {
+			row.fail("a user with this email already exists")
+		}
+		if len(row.report.Errors) > 0 {
+			report.Invalid++
+		} else {
+			report.Valid++
+		}
+	}
+
+	if report.Invalid > 0 || dryRun {
+		return report, nil
+	}
+
+	err = database.WithWriteGuard(func() error {
+		return s.createVolunteers(rows, tenantID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.Imported = true
+	return report, nil
+}
+
+func (row volunteerRow) fail(message string) {
+	row.report.Errors = append(row.report.Errors, message)
+}
+
+func blank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// existingEmails reports which of the emails already belong to users
+func (s *Service) existingEmails(emails []string) (map[string]bool, error) {
+	var existing map[string]bool
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(`
+			SELECT LOWER(email) FROM users WHERE LOWER(email) = ANY($1::text[])
+		`, pq.Array(emails))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		existing = make(map[string]bool)
+		for rows.Next() {
+			var email string
+			if err := rows.Scan(&email); err != nil {
+				return err
+			}
+			existing[email] = true
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// createVolunteers creates every row's volunteer, skills and primary
+// location in one transaction
+func (s *Service) createVolunteers(rows []volunteerRow, tenantID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, row := range rows {
+		var userID string
+		err := tx.QueryRow(`
+			INSERT INTO users (email, name, role, profile_complete, location_name)
+			VALUES ($1, $2, 'volunteer', FALSE, $3)
+			RETURNING id
+		`, row.report.Email, row.report.Name, row.locationName).Scan(&userID)
+		if err != nil {
+			return err
+		}
+		row.report.UserID = userID
+
+		for _, skillID := range row.skillIDs {
+			_, err := tx.Exec(`
+				INSERT INTO volunteer_skills (volunteer_id, skill_id, claimed)
+				VALUES ($1, $2, TRUE)
+			`, userID, skillID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if row.latitude != nil {
+			_, err := tx.Exec(`
+				INSERT INTO volunteer_locations (volunteer_id, label, latitude, longitude, location_name, is_primary)
+				VALUES ($1, 'Home', $2, $3, $4, TRUE)
+			`, userID, *row.latitude, *row.longitude, row.locationName)
+			if err != nil {
+				return err
+			}
+		}
+
+		if tenantID != "" {
+			_, err := tx.Exec(`
+				INSERT INTO organization_members (organization_id, user_id, role, status, accepted_at)
+				VALUES ($1, $2, $3, 'active', CURRENT_TIMESTAMP)
+			`, tenantID, userID, models.OrgRoleMember)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,518 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrUnknownKind  = errors.New("no worker is registered for this kind of job")
+	ErrJobNotFound  = errors.New("job not found")
+	ErrJobNotFailed = errors.New("only failed jobs can be retried")
+	ErrInvalidState = errors.New("status must be queued, running, succeeded or failed")
+)
+
+const (
+	defaultTimeout = 5 * time.Minute
+	// Succeeded jobs are deleted after this long; failed jobs are kept
+	// until retried
+	retention       = 7 * 24 * time.Hour
+	janitorInterval = time.Minute
+)
+
+// Handler does the work of one job. An error fails the attempt, which is
+// retried according to the worker's RetryPolicy.
+type Handler func(ctx context.Context, payload []byte) error
+
+// RetryPolicy says how often and how soon a failing job is attempted again
+type RetryPolicy struct {
+	MaxAttempts int
+	// Backoff is the wait after the first failed attempt; it doubles after
+	// each further failure, up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetry tries a job five times over roughly half an hour
+var DefaultRetry = RetryPolicy{MaxAttempts: 5, Backoff: 30 * time.Second, MaxBackoff: 30 * time.Minute}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// Worker runs one kind of job
+type Worker struct {
+	Handle Handler
+	Retry  RetryPolicy
+	// Timeout bounds one attempt. A job still running after it, e.g.
+	// because its instance stopped, may be claimed again.
+	Timeout time.Duration
+}
+
+const jobColumns = `id, kind, payload, status, attempts, max_attempts, run_at, last_error, created_at, updated_at, finished_at`
+
+func scanJob(row interface{ Scan(...interface{}) error }, j *models.Job) error {
+	return row.Scan(&j.ID, &j.Kind, &j.Payload, &j.Status, &j.Attempts, &j.MaxAttempts, &j.RunAt, &j.LastError,
+		&j.CreatedAt, &j.UpdatedAt, &j.FinishedAt)
+}
+
+// Service is a job queue kept in Postgres. Jobs survive restarts, and every
+// instance running workers shares the queue; each job is run by one worker
+// at a time.
+type Service struct {
+	db *sql.DB
+
+	mu      sync.RWMutex
+	workers map[string]Worker
+
+	wake chan struct{}
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{
+		db:      db,
+		workers: map[string]Worker{},
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Register sets the worker for a kind of job. Register every kind before
+// Run; zero retry settings and timeout take the defaults.
+func (s *Service) Register(kind string, w Worker) {
+	if w.Retry.MaxAttempts <= 0 {
+		w.Retry.MaxAttempts = DefaultRetry.MaxAttempts
+	}
+	if w.Retry.Backoff <= 0 {
+		w.Retry.Backoff = DefaultRetry.Backoff
+	}
+	if w.Retry.MaxBackoff < w.Retry.Backoff {
+		w.Retry.MaxBackoff = w.Retry.Backoff
+	}
+	if w.Timeout <= 0 {
+		w.Timeout = defaultTimeout
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workers[kind] = w
+}
+
+func (s *Service) worker(kind string) (Worker, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	w, ok := s.workers[kind]
+	return w, ok
+}
+
+func (s *Service) kinds() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	kinds := make([]string, 0, len(s.workers))
+	for kind := range s.workers {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+// Enqueue queues a job to run as soon as a worker is free. The payload is
+// stored as JSON.
+func (s *Service) Enqueue(kind string, payload interface{}) (*models.Job, error) {
+	var job *models.Job
+	err := database.WithWriteGuard(func() error {
+		var err error
+		job, err = s.enqueue(s.db, kind, "", payload)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.Wake()
+	return job, nil
+}
+
+// EnqueueUnique queues a job unless one of the same kind and key is already
+// waiting to run, in which case that job is returned. Use it for work where
+// one run covers every request made before it starts.
+func (s *Service) EnqueueUnique(kind, key string, payload interface{}) (*models.Job, error) {
+	var job *models.Job
+	err := database.WithWriteGuard(func() error {
+		var err error
+		job, err = s.enqueue(s.db, kind, key, payload)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.Wake()
+	return job, nil
+}
+
+// EnqueueTx queues a job in tx, so it only runs if tx commits
+func (s *Service) EnqueueTx(tx *sql.Tx, kind string, payload interface{}) (*models.Job, error) {
+	return s.enqueue(tx, kind, "", payload)
+}
+
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func (s *Service) enqueue(q queryer, kind, key string, payload interface{}) (*models.Job, error) {
+	w, ok := s.worker(kind)
+	if !ok {
+		return nil, ErrUnknownKind
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s payload: %w", kind, err)
+	}
+
+	var job models.Job
+	err = scanJob(q.QueryRow(`
+		INSERT INTO jobs (kind, payload, unique_key, max_attempts)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
+		ON CONFLICT (kind, unique_key) WHERE status = 'queued' AND unique_key IS NOT NULL DO NOTHING
+		RETURNING `+jobColumns,
+		kind, raw, key, w.Retry.MaxAttempts), &job)
+	if err == sql.ErrNoRows {
+		err = scanJob(q.QueryRow(`
+			SELECT `+jobColumns+` FROM jobs
+			WHERE kind = $1 AND unique_key = $2 AND status = 'queued'
+		`, kind, key), &job)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Wake asks an idle worker to look for jobs now rather than at the next
+// poll; main calls it when any instance queues a job
+func (s *Service) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run starts concurrency workers, which look for jobs every pollInterval or
+// when woken, and blocks until ctx is cancelled. Jobs interrupted by the
+// cancellation are put back without counting the attempt.
+func (s *Service) Run(ctx context.Context, concurrency int, pollInterval time.Duration) {
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx, pollInterval)
+		}()
+	}
+
+	ticker := time.NewTicker(janitorInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.sweep(); err != nil {
+			logging.FromContext(ctx).Error("Job sweep error", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) work(ctx context.Context, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for ctx.Err() == nil {
+		job, err := s.claim()
+		if err != nil {
+			logging.FromContext(ctx).Error("Job claim error", "error", err)
+		}
+		if job != nil {
+			// There may be more; let another idle worker look
+			s.Wake()
+			s.run(ctx, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// claim takes the next due job this instance has a worker for, along with
+// running jobs whose timeout has passed
+func (s *Service) claim() (*models.Job, error) {
+	kinds := s.kinds()
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+
+	var job *models.Job
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var id, kind string
+		err = tx.QueryRow(`
+			SELECT id, kind FROM jobs
+			WHERE kind = ANY($1)
+			  AND ((status = 'queued' AND run_at <= NOW())
+			       OR (status = 'running' AND locked_until < NOW() AND attempts < max_attempts))
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		`, pq.Array(kinds)).Scan(&id, &kind)
+		if err == sql.ErrNoRows {
+			job = nil
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		w, _ := s.worker(kind)
+		var j models.Job
+		err = scanJob(tx.QueryRow(`
+			UPDATE jobs
+			SET status = 'running', attempts = attempts + 1, unique_key = NULL,
+			    locked_until = NOW() + $2 * INTERVAL '1 millisecond', updated_at = NOW()
+			WHERE id = $1
+			RETURNING `+jobColumns,
+			id, w.Timeout.Milliseconds()), &j)
+		if err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		job = &j
+		return nil
+	})
+	return job, err
+}
+
+// run attempts a claimed job and records the outcome
+func (s *Service) run(ctx context.Context, job *models.Job) {
+	w, _ := s.worker(job.Kind)
+	err := s.execute(ctx, w, job)
+
+	var result error
+	switch {
+	case err == nil:
+		result = s.exec(`
+			UPDATE jobs
+			SET status = 'succeeded', locked_until = NULL, finished_at = NOW(), updated_at = NOW()
+			WHERE id = $1 AND status = 'running' AND attempts = $2
+		`, job.ID, job.Attempts)
+	case ctx.Err() != nil:
+		// Shutting down; the attempt doesn't count
+		result = s.exec(`
+			UPDATE jobs
+			SET status = 'queued', attempts = attempts - 1, locked_until = NULL, updated_at = NOW()
+			WHERE id = $1 AND status = 'running' AND attempts = $2
+		`, job.ID, job.Attempts)
+	case job.Attempts >= job.MaxAttempts:
+		logging.FromContext(ctx).Error("Job failed", "kind", job.Kind, "id", job.ID, "attempts", job.Attempts, "error", err)
+		result = s.exec(`
+			UPDATE jobs
+			SET status = 'failed', last_error = $3, locked_until = NULL, finished_at = NOW(), updated_at = NOW()
+			WHERE id = $1 AND status = 'running' AND attempts = $2
+		`, job.ID, job.Attempts, err.Error())
+	default:
+		delay := w.Retry.delay(job.Attempts)
+		logging.FromContext(ctx).Warn("Job error, retrying", "kind", job.Kind, "id", job.ID, "attempt", job.Attempts, "maxAttempts", job.MaxAttempts, "delay", delay, "error", err)
+		result = s.exec(`
+			UPDATE jobs
+			SET status = 'queued', last_error = $3, run_at = NOW() + $4 * INTERVAL '1 millisecond',
+			    locked_until = NULL, updated_at = NOW()
+			WHERE id = $1 AND status = 'running' AND attempts = $2
+		`, job.ID, job.Attempts, err.Error(), delay.Milliseconds())
+	}
+	if result != nil {
+		logging.FromContext(ctx).Error("Job record outcome error", "kind", job.Kind, "id", job.ID, "error", result)
+	}
+}
+
+// execute calls the worker's handler, turning a panic into an error
+func (s *Service) execute(ctx context.Context, w Worker, job *models.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, w.Timeout)
+	defer cancel()
+	return w.Handle(ctx, job.Payload)
+}
+
+// sweep fails running jobs abandoned on their last attempt and deletes old
+// succeeded jobs
+func (s *Service) sweep() error {
+	err := s.exec(`
+		UPDATE jobs
+		SET status = 'failed', last_error = 'abandoned on its last attempt', locked_until = NULL,
+		    finished_at = NOW(), updated_at = NOW()
+		WHERE status = 'running' AND locked_until < NOW() AND attempts >= max_attempts
+	`)
+	if err != nil {
+		return err
+	}
+	return s.exec(`
+		DELETE FROM jobs
+		WHERE status = 'succeeded' AND finished_at < NOW() - $1 * INTERVAL '1 millisecond'
+	`, retention.Milliseconds())
+}
+
+// GetJobs lists jobs newest first, optionally only those in one status or
+// of one kind
+func (s *Service) GetJobs(status, kind string, limit int) ([]models.Job, error) {
+	switch status {
+	case "", models.JobQueued, models.JobRunning, models.JobSucceeded, models.JobFailed:
+	default:
+		return nil, ErrInvalidState
+	}
+
+	var jobs []models.Job
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(`
+			SELECT `+jobColumns+` FROM jobs
+			WHERE ($1 = '' OR status = $1)
+			  AND ($2 = '' OR kind = $2)
+			ORDER BY created_at DESC, id
+			LIMIT $3
+		`, status, kind, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		jobs = []models.Job{}
+		for rows.Next() {
+			var j models.Job
+			if err := scanJob(rows, &j); err != nil {
+				return err
+			}
+			jobs = append(jobs, j)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// GetStatus sums up the jobs of every registered kind, and of any other
+// kind still in the queue, sorted by kind
+func (s *Service) GetStatus() ([]models.JobKindStatus, error) {
+	byKind := map[string]*models.JobKindStatus{}
+	for _, kind := range s.kinds() {
+		byKind[kind] = &models.JobKindStatus{Kind: kind}
+	}
+
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(`
+			SELECT kind,
+			       COUNT(*) FILTER (WHERE status = 'queued'),
+			       COUNT(*) FILTER (WHERE status = 'running'),
+			       COUNT(*) FILTER (WHERE status = 'failed'),
+			       MIN(run_at) FILTER (WHERE status = 'queued'),
+			       MAX(finished_at) FILTER (WHERE status = 'succeeded')
+			FROM jobs
+			GROUP BY kind
+		`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var st models.JobKindStatus
+			if err := rows.Scan(&st.Kind, &st.Queued, &st.Running, &st.Failed, &st.OldestQueuedAt, &st.LastSucceededAt); err != nil {
+				return err
+			}
+			byKind[st.Kind] = &st
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]models.JobKindStatus, 0, len(byKind))
+	for _, st := range byKind {
+		status = append(status, *st)
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Kind < status[j].Kind })
+	return status, nil
+}
+
+// RetryJob queues a failed job again with a fresh set of attempts
+func (s *Service) RetryJob(jobID string) (*models.Job, error) {
+	var job models.Job
+	err := database.WithWriteGuard(func() error {
+		return scanJob(s.db.QueryRow(`
+			UPDATE jobs
+			SET status = 'queued', attempts = 0, run_at = NOW(), finished_at = NULL, updated_at = NOW()
+			WHERE id::text = $1 AND status = 'failed'
+			RETURNING `+jobColumns,
+			jobID), &job)
+	})
+	if err == sql.ErrNoRows {
+		var exists bool
+		err = database.WithReadRetry(func() error {
+			return s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM jobs WHERE id::text = $1)`, jobID).Scan(&exists)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrJobNotFound
+		}
+		return nil, ErrJobNotFailed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.Wake()
+	return &job, nil
+}
+
+func (s *Service) exec(query string, args ...interface{}) error {
+	return database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(query, args...)
+		return err
+	})
+}
@@ -0,0 +1,267 @@
+package locations
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrLocationNotFound   = errors.New("location not found")
+	ErrLabelRequired      = errors.New("location label is required")
+	ErrLabelTooLong       = errors.New("location label must be at most 50 characters")
+	ErrLabelTaken         = errors.New("a location with this label already exists")
+	ErrInvalidCoordinates = errors.New("latitude must be between -90 and 90 and longitude between -180 and 180")
+	ErrTooManyLocations   = errors.New("volunteers can save at most 10 locations")
+)
+
+// MaxLocations caps saved locations per volunteer
+const MaxLocations = 10
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+const locationColumns = `id, volunteer_id, label, latitude, longitude, location_name, is_primary, created_at, updated_at`
+
+func scanLocation(scanner interface{ Scan(...interface{}) error }, l *models.VolunteerLocation) error {
+	return scanner.Scan(
+		&l.ID,
+		&l.VolunteerID,
+		&l.Label,
+		&l.Latitude,
+		&l.Longitude,
+		&l.LocationName,
+		&l.IsPrimary,
+		&l.CreatedAt,
+		&l.UpdatedAt,
+	)
+}
+
+// GetLocations lists a volunteer's saved locations, primary first
+func (s *Service) GetLocations(volunteerID string) ([]models.VolunteerLocation, error) {
+	query := `
+		SELECT ` + locationColumns + `
+		FROM volunteer_locations
+		WHERE volunteer_id = $1
+		ORDER BY is_primary DESC, created_at
+	`
+
+	var locations []models.VolunteerLocation
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, volunteerID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		locations = nil
+		for rows.Next() {
+			var l models.VolunteerLocation
+			if err := scanLocation(rows, &l); err != nil {
+				return err
+			}
+			locations = append(locations, l)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
+
+// CreateLocation saves a new location. A volunteer's first location is
+// always primary; marking a later one primary demotes the previous primary.
+func (s *Service) CreateLocation(volunteerID string, req models.SaveLocationRequest) (*models.VolunteerLocation, error) {
+	label, err := validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var l models.VolunteerLocation
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// Lock the volunteer so concurrent saves can't exceed the cap
+		if _, err := tx.Exec(`SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, volunteerID); err != nil {
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM volunteer_locations WHERE volunteer_id = $1`, volunteerID).Scan(&count); err != nil {
+			return err
+		}
+		if count >= MaxLocations {
+			return ErrTooManyLocations
+		}
+
+		primary := req.IsPrimary || count == 0
+		if primary {
+			if err := clearPrimary(tx, volunteerID, ""); err != nil {
+				return err
+			}
+		}
+
+		row := tx.QueryRow(`
+			INSERT INTO volunteer_locations (volunteer_id, label, latitude, longitude, location_name, is_primary)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING `+locationColumns,
+			volunteerID, label, req.Latitude, req.Longitude, req.LocationName, primary,
+		)
+		if err := scanLocation(row, &l); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if isUniqueViolation(err) {
+		return nil, ErrLabelTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+// UpdateLocation replaces a location's details. Setting isPrimary moves the
+// primary flag to it; the primary can only be unset by making another
+// location primary.
+func (s *Service) UpdateLocation(volunteerID, locationID string, req models.SaveLocationRequest) (*models.VolunteerLocation, error) {
+	label, err := validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var l models.VolunteerLocation
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if req.IsPrimary {
+			if err := clearPrimary(tx, volunteerID, locationID); err != nil {
+				return err
+			}
+		}
+
+		row := tx.QueryRow(`
+			UPDATE volunteer_locations
+			SET label = $3,
+			    latitude = $4,
+			    longitude = $5,
+			    location_name = $6,
+			    is_primary = is_primary OR $7,
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND volunteer_id = $2
+			RETURNING `+locationColumns,
+			locationID, volunteerID, label, req.Latitude, req.Longitude, req.LocationName, req.IsPrimary,
+		)
+		err = scanLocation(row, &l)
+		if err == sql.ErrNoRows {
+			return ErrLocationNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if isUniqueViolation(err) {
+		return nil, ErrLabelTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+// DeleteLocation removes a saved location. Deleting the primary promotes
+// the oldest remaining location.
+func (s *Service) DeleteLocation(volunteerID, locationID string) error {
+	return database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var wasPrimary bool
+		err = tx.QueryRow(`
+			DELETE FROM volunteer_locations
+			WHERE id = $1 AND volunteer_id = $2
+			RETURNING is_primary
+		`, locationID, volunteerID).Scan(&wasPrimary)
+		if err == sql.ErrNoRows {
+			return ErrLocationNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if wasPrimary {
+			_, err := tx.Exec(`
+				UPDATE volunteer_locations
+				SET is_primary = TRUE, updated_at = CURRENT_TIMESTAMP
+				WHERE id = (
+				    SELECT id FROM volunteer_locations
+				    WHERE volunteer_id = $1
+				    ORDER BY created_at
+				    LIMIT 1
+				)
+			`, volunteerID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Commit()
+	})
+}
+
+// clearPrimary demotes the volunteer's primary location unless it is
+// exceptID, which may be empty
+func clearPrimary(tx *sql.Tx, volunteerID, exceptID string) error {
+	_, err := tx.Exec(`
+		UPDATE volunteer_locations
+		SET is_primary = FALSE, updated_at = CURRENT_TIMESTAMP
+		WHERE volunteer_id = $1 AND is_primary AND id::text <> $2
+	`, volunteerID, exceptID)
+	return err
+}
+
+func validate(req models.SaveLocationRequest) (string, error) {
+	label := strings.TrimSpace(req.Label)
+	if label == "" {
+		return "", ErrLabelRequired
+	}
+	if len([]rune(label)) > 50 {
+		return "", ErrLabelTooLong
+	}
+	if req.Latitude < -90 || req.Latitude > 90 || req.Longitude < -180 || req.Longitude > 180 {
+		return "", ErrInvalidCoordinates
+	}
+	return label, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,162 @@
+package matching
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/civic-weave/backend/internal/events"
+)
+
+const (
+	projectKeyPrefix   = "project:"
+	volunteerKeyPrefix = "volunteer:"
+)
+
+type cacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// matchCache is an in-memory TTL cache of ranked match lists. Entries are
+// also invalidated early by notifications on
+// database.MatchInvalidationChannel.
+type matchCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+func newMatchCache(ttl time.Duration) *matchCache {
+	return &matchCache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+func (c *matchCache) get(key string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *matchCache) set(key string, value interface{}) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = cacheEntry{value: value, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// deletePrefix removes every entry whose key starts with prefix
+func (c *matchCache) deletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *matchCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]cacheEntry)
+}
+
+func projectMatchKey(projectID, tenantID string, skillWeight, distanceWeight, maxDistanceKm float64, distanceDecay string, enforceRequired bool, limit int) string {
+	return fmt.Sprintf("%s%s:%s:%g:%g:%g:%s:%t:%d", projectKeyPrefix, projectID, tenantID, skillWeight, distanceWeight, maxDistanceKm, distanceDecay, enforceRequired, limit)
+}
+
+func volunteerMatchKey(volunteerID, tenantID string, remote *bool, skillWeight, distanceWeight, maxDistanceKm float64, distanceDecay string, enforceRequired bool, limit int) string {
+	remoteKey := "any"
+	if remote != nil {
+		remoteKey = fmt.Sprint(*remote)
+	}
+	return fmt.Sprintf("%s%s:%s:%s:%g:%g:%g:%s:%t:%d", volunteerKeyPrefix, volunteerID, tenantID, remoteKey, skillWeight, distanceWeight, maxDistanceKm, distanceDecay, enforceRequired, limit)
+}
+
+// InvalidateProject drops cached matches affected by a change to a project's skills.
+// The project's own volunteer ranking is stale, and so is every volunteer's
+// project ranking since the project may have moved within it.
+func (s *Service) InvalidateProject(projectID string) {
+	s.cache.deletePrefix(projectKeyPrefix + projectID + ":")
+	s.cache.deletePrefix(volunteerKeyPrefix)
+}
+
+// InvalidateVolunteer drops cached matches affected by a change to a volunteer's skills
+func (s *Service) InvalidateVolunteer(volunteerID string) {
+	s.cache.deletePrefix(volunteerKeyPrefix + volunteerID + ":")
+	s.cache.deletePrefix(projectKeyPrefix)
+}
+
+// InvalidateEnrollment drops the cached matches of a volunteer and of a
+// project between whom an enrollment changed
+func (s *Service) InvalidateEnrollment(volunteerID, projectID string) {
+	s.cache.deletePrefix(volunteerKeyPrefix + volunteerID + ":")
+	s.cache.deletePrefix(projectKeyPrefix + projectID + ":")
+}
+
+// InvalidateAll drops every cached match
+func (s *Service) InvalidateAll() {
+	s.cache.clear()
+}
+
+// HandleEvent is the events.Bus handler keeping this instance's cache in
+// step with enrollment changes, and dropping it all when events may have
+// been missed
+func (s *Service) HandleEvent(e events.Event) {
+	if e.Type == events.TypeResync {
+		s.InvalidateAll()
+		return
+	}
+
+	var c events.EnrollmentChanged
+	if err := e.Decode(&c); err != nil {
+		slog.Warn("Ignoring malformed enrollment event", "error", err)
+		s.InvalidateAll()
+		return
+	}
+	s.InvalidateEnrollment(c.VolunteerID, c.ProjectID)
+}
+
+// HandleInvalidation consumes payloads from database.MatchInvalidationChannel
+func (s *Service) HandleInvalidation(payload string) {
+	if payload == "" {
+		// Listener reconnected; events may have been missed
+		s.InvalidateAll()
+		return
+	}
+
+	var event struct {
+		Table string `json:"table"`
+		ID    string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		slog.Warn("Ignoring malformed match invalidation payload", "payload", payload, "error", err)
+		s.InvalidateAll()
+		return
+	}
+
+	switch event.Table {
+	case "volunteer_skills":
+		s.InvalidateVolunteer(event.ID)
+	case "project_skills":
+		s.InvalidateProject(event.ID)
+	default:
+		s.InvalidateAll()
+	}
+}
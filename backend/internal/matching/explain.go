@@ -0,0 +1,140 @@
+package matching
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/tracing"
+)
+
+// ExplainVolunteerMatches returns a copy of the matches found for a project
+// with the given search parameters, each with its explanation. Matches may
+// be shared with the cache, so they aren't changed.
+func (s *Service) ExplainVolunteerMatches(
+	ctx context.Context,
+	projectID string,
+	matches []models.VolunteerMatch,
+	skillWeight float64,
+	distanceWeight float64,
+	maxDistanceKm float64,
+	distanceDecay string,
+) ([]models.VolunteerMatch, error) {
+	ctx, span := tracing.Start(ctx, "matching.ExplainVolunteerMatches")
+	defer span.End()
+
+	explained := append([]models.VolunteerMatch(nil), matches...)
+	if len(explained) == 0 {
+		return explained, nil
+	}
+
+	volunteerIDs := make([]string, len(explained))
+	for i, m := range explained {
+		volunteerIDs[i] = m.VolunteerID
+	}
+	projects, volunteers, skillNames, err := s.loadPairs(ctx, []string{projectID}, volunteerIDs)
+	if err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+
+	params, err := s.explainParams(skillWeight, distanceWeight, maxDistanceKm, distanceDecay)
+	if err != nil {
+		return nil, err
+	}
+	for i := range explained {
+		p, pok := projects[projectID]
+		v, vok := volunteers[explained[i].VolunteerID]
+		if pok && vok {
+			explained[i].Explanation = explainPair(p, v, skillNames, params)
+		}
+	}
+	return explained, nil
+}
+
+// ExplainProjectMatches returns a copy of the matches found for a volunteer
+// with the given search parameters, each with its explanation. Matches may
+// be shared with the cache, so they aren't changed.
+func (s *Service) ExplainProjectMatches(
+	ctx context.Context,
+	volunteerID string,
+	matches []models.ProjectMatch,
+	skillWeight float64,
+	distanceWeight float64,
+	maxDistanceKm float64,
+	distanceDecay string,
+) ([]models.ProjectMatch, error) {
+	ctx, span := tracing.Start(ctx, "matching.ExplainProjectMatches")
+	defer span.End()
+
+	explained := append([]models.ProjectMatch(nil), matches...)
+	if len(explained) == 0 {
+		return explained, nil
+	}
+
+	projectIDs := make([]string, len(explained))
+	for i, m := range explained {
+		projectIDs[i] = m.ProjectID
+	}
+	projects, volunteers, skillNames, err := s.loadPairs(ctx, projectIDs, []string{volunteerID})
+	if err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+
+	params, err := s.explainParams(skillWeight, distanceWeight, maxDistanceKm, distanceDecay)
+	if err != nil {
+		return nil, err
+	}
+	for i := range explained {
+		p, pok := projects[explained[i].ProjectID]
+		v, vok := volunteers[volunteerID]
+		if pok && vok {
+			explained[i].Explanation = explainPair(p, v, skillNames, params)
+		}
+	}
+	return explained, nil
+}
+
+// explainParams fills in the search parameters left unset, as the search
+// being explained did
+func (s *Service) explainParams(skillWeight, distanceWeight, maxDistanceKm float64, distanceDecay string) (scoreParams, error) {
+	skillWeight, distanceWeight, maxDistanceKm, _ = s.withDefaults(skillWeight, distanceWeight, maxDistanceKm, 0)
+	distanceDecay, err := s.withDefaultDecay(distanceDecay)
+	if err != nil {
+		return scoreParams{}, err
+	}
+	return scoreParams{
+		skillWeight:    skillWeight,
+		distanceWeight: distanceWeight,
+		maxDistanceKm:  maxDistanceKm,
+		distanceDecay:  distanceDecay,
+	}, nil
+}
+
+// loadPairs loads the projects and volunteers to explain, by ID, and the
+// names of skills
+func (s *Service) loadPairs(ctx context.Context, projectIDs, volunteerIDs []string) (map[string]matchProject, map[string]matchVolunteer, map[string]string, error) {
+	projects, err := s.matchProjects(ctx, projectIDs, "", nil)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+	volunteers, err := s.matchVolunteers(ctx, volunteerIDs)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get volunteers: %w", err)
+	}
+	skillNames, err := s.skillNames(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get skills: %w", err)
+	}
+
+	projectsByID := make(map[string]matchProject, len(projects))
+	for _, p := range projects {
+		projectsByID[p.id] = p
+	}
+	volunteersByID := make(map[string]matchVolunteer, len(volunteers))
+	for _, v := range volunteers {
+		volunteersByID[v.id] = v
+	}
+	return projectsByID, volunteersByID, skillNames, nil
+}
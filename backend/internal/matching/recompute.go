@@ -0,0 +1,642 @@
+package matching
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/tracing"
+	"github.com/lib/pq"
+)
+
+// RecomputeMatchesJob is the kind of background job that runs
+// RecomputeMatches
+const RecomputeMatchesJob = "matching.recompute_matches"
+
+// Recomputation scopes
+const (
+	RecomputeAll   = "all"
+	RecomputeStale = "stale"
+)
+
+// minMatchScore is the combined score below which a project and volunteer
+// don't match, as with the SQL batch matcher this replaces
+const minMatchScore = 0.1
+
+// recomputationRetention is how long runs of the batch matcher are listed for
+const recomputationRetention = 30 * 24 * time.Hour
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type point struct{ lat, lon float64 }
+
+// volunteerLocation is a saved location of a volunteer
+type volunteerLocation struct {
+	point
+	name *string
+}
+
+type matchVolunteer struct {
+	id          string
+	maxTravelKm *float64
+	skills      SkillVector
+	// locations lists the volunteer's primary location first
+	locations []volunteerLocation
+}
+
+type matchProject struct {
+	id           string
+	name         string
+	location     *point
+	locationName *string
+	remote       bool
+	skills       SkillVector
+	required     map[string]bool
+}
+
+// scoreParams are the weights, maximum distance and distance decay a pair
+// is scored with. With enforceRequired, volunteers missing a skill the
+// project requires don't match it.
+type scoreParams struct {
+	skillWeight     float64
+	distanceWeight  float64
+	maxDistanceKm   float64
+	distanceDecay   string
+	enforceRequired bool
+}
+
+type precomputedMatch struct {
+	projectID     string
+	volunteerID   string
+	skillScore    float64
+	distanceKm    float64
+	combinedScore float64
+	matchedSkills []models.MatchedSkill
+	// hasRequiredSkills is set when the volunteer claims every skill the
+	// project requires
+	hasRequiredSkills bool
+}
+
+// RecomputeMatches scores projects against volunteers and saves the matches
+// in project_volunteer_matches, which searches read before matching on
+// demand. Scope RecomputeAll scores every active project; RecomputeStale
+// only the projects and volunteers marked stale since their last run, which
+// the triggers in migration 075 do as skills, locations, travel caps and
+// projects change. Matches are scored with the default weights, maximum
+// distance and distance decay, and saved whether or not the volunteer has the skills the
+// project requires, which searches filter on; unchanged rows are left alone. It returns how many matches were
+// saved.
+func (s *Service) RecomputeMatches(ctx context.Context, scope string) (int, error) {
+	ctx, span := tracing.Start(ctx, "matching.RecomputeMatches")
+	defer span.End()
+
+	if scope != RecomputeAll && scope != RecomputeStale {
+		return 0, fmt.Errorf("unknown recomputation scope %q", scope)
+	}
+
+	// Full runs clear the stale marks made before they start too
+	projectIDs, volunteerIDs, marks, err := s.staleMatches(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get stale matches: %w", err)
+	}
+	if scope == RecomputeAll {
+		projectIDs = nil
+	} else if len(projectIDs) == 0 && len(volunteerIDs) == 0 {
+		return 0, nil
+	}
+
+	skillNames, err := s.skillNames(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get skills: %w", err)
+	}
+
+	// Stale projects are scored against every volunteer, and stale
+	// volunteers against every active project
+	var projects []matchProject
+	var volunteers []matchVolunteer
+	if scope == RecomputeAll || len(volunteerIDs) > 0 {
+		if projects, err = s.matchProjects(ctx, nil, "", nil); err != nil {
+			return 0, fmt.Errorf("failed to get projects: %w", err)
+		}
+	}
+	if scope == RecomputeAll || len(projectIDs) > 0 {
+		if volunteers, err = s.matchVolunteers(ctx, nil); err != nil {
+			return 0, fmt.Errorf("failed to get volunteers: %w", err)
+		}
+	}
+
+	total := len(projectIDs) + len(volunteerIDs)
+	if scope == RecomputeAll {
+		total = len(projects)
+	}
+	runID, err := s.startRecomputation(ctx, scope, total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record recomputation: %w", err)
+	}
+
+	saved, err := s.recompute(ctx, runID, scope, projects, volunteers, projectIDs, volunteerIDs, marks, skillNames)
+	if ferr := s.finishRecomputation(runID, err); ferr != nil {
+		logging.FromContext(ctx).Error("Failed to record recomputation outcome", "run", runID, "error", ferr)
+	}
+	if err != nil {
+		tracing.Fail(span, err)
+		return saved, err
+	}
+
+	logging.FromContext(ctx).Info("Recomputed matches", "scope", scope, "scored", total, "matches", saved)
+	return saved, nil
+}
+
+// recompute scores and saves each project, then each volunteer, in scope,
+// recording progress in run runID as it goes
+func (s *Service) recompute(
+	ctx context.Context,
+	runID string,
+	scope string,
+	projects []matchProject,
+	volunteers []matchVolunteer,
+	projectIDs []string,
+	volunteerIDs []string,
+	marks map[string]time.Time,
+	skillNames map[string]string,
+) (int, error) {
+	saved := 0
+
+	if scope == RecomputeAll {
+		// Matches of projects no longer active and users no longer
+		// volunteering are dropped rather than rescored
+		err := database.WithWriteGuard(func() error {
+			_, err := s.db.ExecContext(ctx, `
+				DELETE FROM project_volunteer_matches m
+				WHERE NOT EXISTS (SELECT 1 FROM projects p WHERE p.id = m.project_id AND p.status = 'active')
+				   OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = m.volunteer_id AND u.role = 'volunteer')
+			`)
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, p := range projects {
+			projectIDs = append(projectIDs, p.id)
+		}
+	}
+
+	params := scoreParams{
+		skillWeight:    s.defaults.SkillWeight,
+		distanceWeight: s.defaults.DistanceWeight,
+		maxDistanceKm:  s.defaults.MaxDistanceKm,
+		distanceDecay:  s.defaults.DistanceDecay,
+	}
+	projectsByID := make(map[string]matchProject, len(projects))
+	for _, p := range projects {
+		projectsByID[p.id] = p
+	}
+	volunteersByID := make(map[string]matchVolunteer, len(volunteers))
+	for _, v := range volunteers {
+		volunteersByID[v.id] = v
+	}
+
+	processed := 0
+	for _, id := range projectIDs {
+		var matches []precomputedMatch
+		// A project that is gone or not active just loses its matches
+		if p, ok := projectsByID[id]; ok {
+			for _, v := range volunteers {
+				if m, ok := s.scorePair(p, v, skillNames, params); ok {
+					matches = append(matches, m)
+				}
+			}
+		}
+		if err := s.saveMatches(ctx, "project_id", "volunteer_id", id, matches, marks["project:"+id]); err != nil {
+			return saved, fmt.Errorf("failed to save matches of project %s: %w", id, err)
+		}
+		saved += len(matches)
+		processed++
+		s.recordProgress(ctx, runID, processed, saved)
+	}
+
+	// Every volunteer was just scored against every project
+	if scope == RecomputeAll {
+		for _, id := range volunteerIDs {
+			err := database.WithWriteGuard(func() error {
+				return s.clearStale(ctx, s.db, "volunteer", id, marks["volunteer:"+id])
+			})
+			if err != nil {
+				return saved, fmt.Errorf("failed to clear stale volunteer %s: %w", id, err)
+			}
+		}
+		return saved, nil
+	}
+
+	// Stale volunteers are looked up on their own, as they may not have
+	// been loaded with the others; those no longer volunteering aren't
+	// found and lose their matches
+	if len(volunteerIDs) > 0 {
+		stale, err := s.matchVolunteers(ctx, volunteerIDs)
+		if err != nil {
+			return saved, fmt.Errorf("failed to get volunteers: %w", err)
+		}
+		for _, v := range stale {
+			volunteersByID[v.id] = v
+		}
+	}
+	for _, id := range volunteerIDs {
+		var matches []precomputedMatch
+		if v, ok := volunteersByID[id]; ok {
+			for _, p := range projects {
+				if m, ok := s.scorePair(p, v, skillNames, params); ok {
+					matches = append(matches, m)
+				}
+			}
+		}
+		if err := s.saveMatches(ctx, "volunteer_id", "project_id", id, matches, marks["volunteer:"+id]); err != nil {
+			return saved, fmt.Errorf("failed to save matches of volunteer %s: %w", id, err)
+		}
+		saved += len(matches)
+		processed++
+		s.recordProgress(ctx, runID, processed, saved)
+	}
+
+	return saved, nil
+}
+
+// saveMatches replaces the saved matches of one project or volunteer:
+// column names the side whose matches these are and id that project or
+// volunteer, other the column of the side matched to it. Unchanged rows are
+// not rewritten. A stale mark made no later than marked is cleared with them.
+func (s *Service) saveMatches(ctx context.Context, column, other, id string, matches []precomputedMatch, marked time.Time) error {
+	kept := make([]string, len(matches))
+	for i, m := range matches {
+		kept[i] = m.volunteerID
+		if other == "project_id" {
+			kept[i] = m.projectID
+		}
+	}
+
+	return database.WithWriteGuard(func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM project_volunteer_matches
+			WHERE `+column+` = $1 AND NOT (`+other+` = ANY($2::uuid[]))
+		`, id, pq.Array(kept))
+		if err != nil {
+			return err
+		}
+
+		stmt, err := tx.PrepareContext(ctx, `
+			INSERT INTO project_volunteer_matches (project_id, volunteer_id, skill_score, distance_km, combined_score, matched_skills, has_required_skills)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (project_id, volunteer_id) DO UPDATE SET
+				skill_score = EXCLUDED.skill_score,
+				distance_km = EXCLUDED.distance_km,
+				combined_score = EXCLUDED.combined_score,
+				matched_skills = EXCLUDED.matched_skills,
+				has_required_skills = EXCLUDED.has_required_skills,
+				updated_at = NOW()
+			WHERE (project_volunteer_matches.skill_score, project_volunteer_matches.distance_km,
+			       project_volunteer_matches.combined_score, project_volunteer_matches.matched_skills,
+			       project_volunteer_matches.has_required_skills)
+			      IS DISTINCT FROM
+			      (EXCLUDED.skill_score, EXCLUDED.distance_km, EXCLUDED.combined_score, EXCLUDED.matched_skills,
+			       EXCLUDED.has_required_skills)
+		`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for _, m := range matches {
+			skills, err := json.Marshal(m.matchedSkills)
+			if err != nil {
+				return err
+			}
+			_, err = stmt.ExecContext(ctx, m.projectID, m.volunteerID, m.skillScore, m.distanceKm, m.combinedScore, skills, m.hasRequiredSkills)
+			if err != nil {
+				return err
+			}
+		}
+
+		kind := "project"
+		if column == "volunteer_id" {
+			kind = "volunteer"
+		}
+		if err := s.clearStale(ctx, tx, kind, id, marked); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+}
+
+// clearStale removes the stale mark of a project or volunteer made no later
+// than marked; a zero marked means there is none to remove
+func (s *Service) clearStale(ctx context.Context, e execer, kind, id string, marked time.Time) error {
+	if marked.IsZero() {
+		return nil
+	}
+	_, err := e.ExecContext(ctx, `DELETE FROM match_stale WHERE kind = $1 AND id = $2 AND marked_at <= $3`, kind, id, marked)
+	return err
+}
+
+// staleMatches returns the projects and volunteers marked stale, with when
+// each was marked, keyed "project:<id>" or "volunteer:<id>"
+func (s *Service) staleMatches(ctx context.Context) ([]string, []string, map[string]time.Time, error) {
+	var projectIDs, volunteerIDs []string
+	var marks map[string]time.Time
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, `SELECT kind, id, marked_at FROM match_stale ORDER BY marked_at`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		projectIDs, volunteerIDs = nil, nil
+		marks = map[string]time.Time{}
+		for rows.Next() {
+			var kind, id string
+			var markedAt time.Time
+			if err := rows.Scan(&kind, &id, &markedAt); err != nil {
+				return err
+			}
+			if kind == "project" {
+				projectIDs = append(projectIDs, id)
+			} else {
+				volunteerIDs = append(volunteerIDs, id)
+			}
+			marks[kind+":"+id] = markedAt
+		}
+		return rows.Err()
+	})
+	return projectIDs, volunteerIDs, marks, err
+}
+
+// skillNames maps skill IDs to names
+func (s *Service) skillNames(ctx context.Context) (map[string]string, error) {
+	var names map[string]string
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, `SELECT id, name FROM skills`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		names = map[string]string{}
+		for rows.Next() {
+			var id, name string
+			if err := rows.Scan(&id, &name); err != nil {
+				return err
+			}
+			names[id] = name
+		}
+		return rows.Err()
+	})
+	return names, err
+}
+
+// matchProjects loads the projects among ids, or every active project when
+// ids is nil, with their skill demand: only those of
+// organization tenantID when it is set, and only remote or only on-site
+// projects when remote is set
+func (s *Service) matchProjects(ctx context.Context, ids []string, tenantID string, remote *bool) ([]matchProject, error) {
+	var projects []matchProject
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, `
+			SELECT id, name, latitude, longitude, location_name, is_remote
+			FROM projects
+			WHERE ($3::uuid[] IS NULL AND status = 'active' OR id = ANY($3))
+			  AND ($1 = '' OR organization_id = NULLIF($1, '')::uuid)
+			  AND ($2::boolean IS NULL OR is_remote = $2)
+			ORDER BY id
+		`, tenantID, remote, pq.Array(ids))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		projects = nil
+		index := map[string]int{}
+		for rows.Next() {
+			var p matchProject
+			var lat, lon *float64
+			if err := rows.Scan(&p.id, &p.name, &lat, &lon, &p.locationName, &p.remote); err != nil {
+				return err
+			}
+			if lat != nil && lon != nil {
+				p.location = &point{lat: *lat, lon: *lon}
+			}
+			p.skills = SkillVector{}
+			p.required = map[string]bool{}
+			index[p.id] = len(projects)
+			projects = append(projects, p)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		rows, err = s.db.QueryContext(ctx, `
+			SELECT ps.project_id, ps.skill_id, ps.weight, ps.required
+			FROM project_skills ps
+			JOIN projects p ON p.id = ps.project_id
+			WHERE ($1::uuid[] IS NULL AND p.status = 'active' OR p.id = ANY($1))
+		`, pq.Array(ids))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var projectID, skillID string
+			var weight float64
+			var required bool
+			if err := rows.Scan(&projectID, &skillID, &weight, &required); err != nil {
+				return err
+			}
+			if i, ok := index[projectID]; ok {
+				projects[i].skills[skillID] = weight
+				if required {
+					projects[i].required[skillID] = true
+				}
+			}
+		}
+		return rows.Err()
+	})
+	return projects, err
+}
+
+// matchVolunteers loads the volunteers among ids, or every volunteer when
+// ids is nil, with their claimed skills and saved locations
+func (s *Service) matchVolunteers(ctx context.Context, ids []string) ([]matchVolunteer, error) {
+	var volunteers []matchVolunteer
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, `
+			SELECT id, max_travel_km
+			FROM users
+			WHERE role = 'volunteer' AND ($1::uuid[] IS NULL OR id = ANY($1))
+			ORDER BY id
+		`, pq.Array(ids))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		volunteers = nil
+		index := map[string]int{}
+		for rows.Next() {
+			var v matchVolunteer
+			if err := rows.Scan(&v.id, &v.maxTravelKm); err != nil {
+				return err
+			}
+			v.skills = SkillVector{}
+			index[v.id] = len(volunteers)
+			volunteers = append(volunteers, v)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		rows, err = s.db.QueryContext(ctx, `
+			SELECT volunteer_id, skill_id, score
+			FROM volunteer_skills
+			WHERE claimed = TRUE AND ($1::uuid[] IS NULL OR volunteer_id = ANY($1))
+		`, pq.Array(ids))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var volunteerID, skillID string
+			var score float64
+			if err := rows.Scan(&volunteerID, &skillID, &score); err != nil {
+				return err
+			}
+			if i, ok := index[volunteerID]; ok {
+				volunteers[i].skills[skillID] = score
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		rows, err = s.db.QueryContext(ctx, `
+			SELECT volunteer_id, latitude, longitude, location_name
+			FROM volunteer_locations
+			WHERE $1::uuid[] IS NULL OR volunteer_id = ANY($1)
+			ORDER BY is_primary DESC, created_at
+		`, pq.Array(ids))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var volunteerID string
+			var l volunteerLocation
+			if err := rows.Scan(&volunteerID, &l.lat, &l.lon, &l.name); err != nil {
+				return err
+			}
+			if i, ok := index[volunteerID]; ok {
+				volunteers[i].locations = append(volunteers[i].locations, l)
+			}
+		}
+		return rows.Err()
+	})
+	return volunteers, err
+}
+
+// startRecomputation records the start of a run of total projects and
+// volunteers, pruning old runs
+func (s *Service) startRecomputation(ctx context.Context, scope string, total int) (string, error) {
+	var id string
+	err := database.WithWriteGuard(func() error {
+		_, err := s.db.ExecContext(ctx, `
+			DELETE FROM match_recomputations WHERE started_at < NOW() - $1 * INTERVAL '1 millisecond'
+		`, recomputationRetention.Milliseconds())
+		if err != nil {
+			return err
+		}
+		return s.db.QueryRowContext(ctx, `
+			INSERT INTO match_recomputations (scope, total) VALUES ($1, $2) RETURNING id
+		`, scope, total).Scan(&id)
+	})
+	return id, err
+}
+
+// recordProgress updates a run's counts; a failure only costs the
+// progress report, so it is logged
+func (s *Service) recordProgress(ctx context.Context, runID string, processed, matches int) {
+	err := database.WithWriteGuard(func() error {
+		_, err := s.db.ExecContext(ctx, `
+			UPDATE match_recomputations SET processed = $2, matches = $3 WHERE id = $1
+		`, runID, processed, matches)
+		return err
+	})
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to record recomputation progress", "run", runID, "error", err)
+	}
+}
+
+// finishRecomputation records how a run ended. It doesn't take the job's
+// context, so cancelled runs are still marked failed.
+func (s *Service) finishRecomputation(runID string, runErr error) error {
+	status := "succeeded"
+	var errText *string
+	if runErr != nil {
+		status = "failed"
+		msg := runErr.Error()
+		errText = &msg
+	}
+	return database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(`
+			UPDATE match_recomputations SET status = $2, error = $3, finished_at = NOW() WHERE id = $1
+		`, runID, status, errText)
+		return err
+	})
+}
+
+// GetRecomputations lists runs of the batch matcher newest first
+func (s *Service) GetRecomputations(ctx context.Context, limit int) ([]models.MatchRecomputation, error) {
+	ctx, span := tracing.Start(ctx, "matching.GetRecomputations")
+	defer span.End()
+
+	var runs []models.MatchRecomputation
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, `
+			SELECT id, scope, status, total, processed, matches, error, started_at, finished_at
+			FROM match_recomputations
+			ORDER BY started_at DESC, id
+			LIMIT $1
+		`, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		runs = []models.MatchRecomputation{}
+		for rows.Next() {
+			var r models.MatchRecomputation
+			if err := rows.Scan(&r.ID, &r.Scope, &r.Status, &r.Total, &r.Processed, &r.Matches,
+				&r.Error, &r.StartedAt, &r.FinishedAt); err != nil {
+				return err
+			}
+			runs = append(runs, r)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
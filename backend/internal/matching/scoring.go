@@ -0,0 +1,257 @@
+package matching
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/civic-weave/backend/internal/models"
+)
+
+// Distance decays turn a volunteer's distance from a project, as a share of
+// the maximum distance, into a distance score from 1 at the project down
+// towards 0 at the maximum distance
+const (
+	// DecayLinear falls steadily to 0 at the maximum distance
+	DecayLinear = "linear"
+	// DecayExponential falls fastest close to the project, to about 0.05
+	// at the maximum distance
+	DecayExponential = "exponential"
+	// DecayStep scores each quarter of the maximum distance the same, from
+	// 1 within the first to 0.25 within the last
+	DecayStep = "step"
+)
+
+var ErrInvalidDistanceDecay = errors.New("distanceDecay must be linear, exponential or step")
+
+// exponentialDecayRate is how many times exponential decay falls by e over
+// the maximum distance
+const exponentialDecayRate = 3
+
+// decaySteps are the distance scores of step decay up to each share of the
+// maximum distance
+var decaySteps = []struct{ share, score float64 }{
+	{0.25, 1},
+	{0.5, 0.75},
+	{0.75, 0.5},
+	{1, 0.25},
+}
+
+// ValidDistanceDecay reports whether name is a distance decay
+func ValidDistanceDecay(name string) bool {
+	return name == DecayLinear || name == DecayExponential || name == DecayStep
+}
+
+// decay returns the distance score of distanceKm with the named decay. Any
+// distance scores 0 when the maximum is 0, and beyond the maximum.
+func decay(name string, distanceKm, maxDistanceKm float64) float64 {
+	if maxDistanceKm <= 0 || distanceKm > maxDistanceKm {
+		return 0
+	}
+	share := distanceKm / maxDistanceKm
+
+	switch name {
+	case DecayExponential:
+		return math.Exp(-exponentialDecayRate * share)
+	case DecayStep:
+		for _, step := range decaySteps {
+			if share <= step.share {
+				return step.score
+			}
+		}
+		return 0
+	default:
+		return 1 - share
+	}
+}
+
+// neutralDistanceScore is the distance score of pairs whose distance is
+// unknown, because the volunteer or the project has no location
+const neutralDistanceScore = 0.5
+
+// pairScore is how a volunteer scores for a project, with the parts the
+// combined score is made of
+type pairScore struct {
+	skillScore     float64
+	distanceKm     *float64 // nil for remote projects and unknown distances
+	maxDistanceKm  float64
+	distanceScore  float64
+	skillWeight    float64
+	distanceWeight float64
+	combinedScore  float64
+	// outOfReach is set when the volunteer is beyond the maximum distance
+	outOfReach bool
+	// missingRequired is set when the volunteer doesn't claim a skill the
+	// project requires
+	missingRequired bool
+}
+
+// score scores a volunteer for a project. On-site projects combine the
+// skill score with a distance score, decayed as params say, using the
+// normalized weights; the volunteer's travel cap replaces the maximum
+// distance when they have one. Remote projects are scored on skills alone.
+func score(p matchProject, v matchVolunteer, params scoreParams) pairScore {
+	ps := pairScore{
+		skillScore:  CosineSimilarity(v.skills, p.skills),
+		skillWeight: 1,
+	}
+	ps.combinedScore = ps.skillScore
+	for skillID := range p.required {
+		if _, ok := v.skills[skillID]; !ok {
+			ps.missingRequired = true
+			break
+		}
+	}
+	if p.remote {
+		return ps
+	}
+
+	ps.maxDistanceKm = params.maxDistanceKm
+	if v.maxTravelKm != nil {
+		ps.maxDistanceKm = *v.maxTravelKm
+	}
+
+	ps.distanceScore = neutralDistanceScore
+	if _, d, ok := nearestLocation(p.location, v.locations); ok {
+		ps.distanceKm = &d
+		ps.outOfReach = d > ps.maxDistanceKm
+		ps.distanceScore = decay(params.distanceDecay, d, ps.maxDistanceKm)
+	}
+
+	if total := params.skillWeight + params.distanceWeight; total > 0 {
+		ps.skillWeight, ps.distanceWeight = params.skillWeight/total, params.distanceWeight/total
+	}
+	ps.combinedScore = ps.skillWeight*ps.skillScore + ps.distanceWeight*ps.distanceScore
+	return ps
+}
+
+// scorePair scores a volunteer for a project, reporting false when they
+// don't match: when the volunteer is out of reach, scores too low to keep,
+// or misses a required skill when params enforce them
+func (s *Service) scorePair(p matchProject, v matchVolunteer, skillNames map[string]string, params scoreParams) (precomputedMatch, bool) {
+	ps := score(p, v, params)
+	if ps.outOfReach || ps.combinedScore < minMatchScore || (params.enforceRequired && ps.missingRequired) {
+		return precomputedMatch{}, false
+	}
+
+	m := precomputedMatch{
+		projectID:     p.id,
+		volunteerID:   v.id,
+		skillScore:    ps.skillScore,
+		combinedScore: ps.combinedScore,
+		matchedSkills: []models.MatchedSkill{},
+	}
+	m.hasRequiredSkills = !ps.missingRequired
+	if ps.distanceKm != nil {
+		m.distanceKm = *ps.distanceKm
+	}
+	for _, skillID := range getMatchedSkills(v.skills, p.skills) {
+		m.matchedSkills = append(m.matchedSkills, models.MatchedSkill{
+			ID:             skillID,
+			Name:           skillNames[skillID],
+			VolunteerScore: v.skills[skillID],
+			ProjectWeight:  p.skills[skillID],
+		})
+	}
+	sortMatchedSkills(m.matchedSkills)
+	return m, true
+}
+
+// explainPair breaks down how a volunteer scores for a project. Each shared
+// skill's contribution is its share of the cosine similarity, so together
+// they add up to the skill score.
+func explainPair(p matchProject, v matchVolunteer, skillNames map[string]string, params scoreParams) *models.MatchExplanation {
+	ps := score(p, v, params)
+	e := &models.MatchExplanation{
+		SkillScore:            ps.skillScore,
+		SkillWeight:           ps.skillWeight,
+		DistanceWeight:        ps.distanceWeight,
+		DistanceKm:            ps.distanceKm,
+		MaxDistanceKm:         ps.maxDistanceKm,
+		DistanceScore:         ps.distanceScore,
+		CombinedScore:         ps.combinedScore,
+		Skills:                []models.SkillContribution{},
+		MissingRequiredSkills: []models.MissingSkill{},
+	}
+	if !p.remote {
+		e.DistanceDecay = params.distanceDecay
+	}
+
+	norms := magnitude(v.skills) * magnitude(p.skills)
+	for _, skillID := range getMatchedSkills(v.skills, p.skills) {
+		c := models.SkillContribution{
+			MatchedSkill: models.MatchedSkill{
+				ID:             skillID,
+				Name:           skillNames[skillID],
+				VolunteerScore: v.skills[skillID],
+				ProjectWeight:  p.skills[skillID],
+			},
+		}
+		if norms > 0 {
+			c.Contribution = c.VolunteerScore * c.ProjectWeight / norms
+		}
+		e.Skills = append(e.Skills, c)
+	}
+	sortContributions(e.Skills)
+
+	for skillID := range p.required {
+		if _, ok := v.skills[skillID]; !ok {
+			e.MissingRequiredSkills = append(e.MissingRequiredSkills, models.MissingSkill{
+				ID:            skillID,
+				Name:          skillNames[skillID],
+				ProjectWeight: p.skills[skillID],
+			})
+		}
+	}
+	sortMissingSkills(e.MissingRequiredSkills)
+	return e
+}
+
+// magnitude returns the Euclidean length of a skill vector
+func magnitude(v SkillVector) float64 {
+	var sum float64
+	for _, score := range v {
+		sum += score * score
+	}
+	return math.Sqrt(sum)
+}
+
+// nearestLocation returns the volunteer's location nearest the project and
+// its distance, and false when either has no location. Without a project
+// location the volunteer's primary location is returned, if they have any.
+func nearestLocation(project *point, locations []volunteerLocation) (*volunteerLocation, float64, bool) {
+	if len(locations) == 0 {
+		return nil, 0, false
+	}
+	if project == nil {
+		return &locations[0], 0, false
+	}
+
+	nearest, distance := 0, math.Inf(1)
+	for i, l := range locations {
+		if d := HaversineDistance(l.lat, l.lon, project.lat, project.lon); d < distance {
+			nearest, distance = i, d
+		}
+	}
+	return &locations[nearest], distance, true
+}
+
+// sortContributions sorts skill contributions largest first, then by name
+func sortContributions(contributions []models.SkillContribution) {
+	sort.Slice(contributions, func(i, j int) bool {
+		if contributions[i].Contribution != contributions[j].Contribution {
+			return contributions[i].Contribution > contributions[j].Contribution
+		}
+		return contributions[i].Name < contributions[j].Name
+	})
+}
+
+// sortMissingSkills sorts missing skills by name, then ID
+func sortMissingSkills(skills []models.MissingSkill) {
+	sort.Slice(skills, func(i, j int) bool {
+		if skills[i].Name != skills[j].Name {
+			return skills[i].Name < skills[j].Name
+		}
+		return skills[i].ID < skills[j].ID
+	})
+}
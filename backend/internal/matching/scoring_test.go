@@ -0,0 +1,76 @@
+package matching
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecay(t *testing.T) {
+	const maxDistanceKm = 100
+
+	tests := []struct {
+		name       string
+		decay      string
+		distanceKm float64
+		want       float64
+	}{
+		{"linear at project", DecayLinear, 0, 1},
+		{"linear at quarter", DecayLinear, 25, 0.75},
+		{"linear at half", DecayLinear, 50, 0.5},
+		{"linear at three quarters", DecayLinear, 75, 0.25},
+		{"linear at max", DecayLinear, 100, 0},
+		{"linear beyond max", DecayLinear, 150, 0},
+
+		{"exponential at project", DecayExponential, 0, 1},
+		{"exponential at quarter", DecayExponential, 25, math.Exp(-0.75)},
+		{"exponential at half", DecayExponential, 50, math.Exp(-1.5)},
+		{"exponential at three quarters", DecayExponential, 75, math.Exp(-2.25)},
+		{"exponential at max", DecayExponential, 100, math.Exp(-3)},
+		{"exponential beyond max", DecayExponential, 150, 0},
+
+		{"step at project", DecayStep, 0, 1},
+		{"step at quarter", DecayStep, 25, 1},
+		{"step at half", DecayStep, 50, 0.75},
+		{"step at three quarters", DecayStep, 75, 0.5},
+		{"step at max", DecayStep, 100, 0.25},
+		{"step beyond max", DecayStep, 150, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decay(tt.decay, tt.distanceKm, maxDistanceKm); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("decay(%q, %v, %v) = %v, want %v", tt.decay, tt.distanceKm, maxDistanceKm, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecayZeroMaxDistance(t *testing.T) {
+	for _, name := range []string{DecayLinear, DecayExponential, DecayStep} {
+		for _, distanceKm := range []float64{0, 10} {
+			if got := decay(name, distanceKm, 0); got != 0 {
+				t.Errorf("decay(%q, %v, 0) = %v, want 0", name, distanceKm, got)
+			}
+		}
+	}
+}
+
+func TestValidDistanceDecay(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{DecayLinear, true},
+		{DecayExponential, true},
+		{DecayStep, true},
+		{"", false},
+		{"Linear", false},
+		{"gaussian", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidDistanceDecay(tt.name); got != tt.want {
+			t.Errorf("ValidDistanceDecay(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,21 +1,106 @@
 package matching
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"math"
+	"sort"
+	"time"
 
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/metrics"
 	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/tracing"
 	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// Where a search's matches came from: the in-memory cache, the precomputed
+// match tables, or on-demand matching when those can't be queried
+const (
+	sourceMemory      = "memory"
+	sourcePrecomputed = "precomputed"
+	sourceOnDemand    = "on_demand"
+)
+
+var searchDuration = metrics.NewHistogramVec("civicweave_matching_search_duration_seconds",
+	"Time taken to find matches, by search (volunteers or projects) and source (memory, precomputed or on_demand).",
+	metrics.DefaultBuckets, "search", "source")
+
+var ErrVolunteerNotFound = errors.New("volunteer not found")
+
 type Service struct {
-	db *sql.DB
+	db       *sql.DB
+	cache    *matchCache
+	defaults Defaults
 }
 
-func NewService(db *sql.DB) *Service {
-	return &Service{db: db}
+// Defaults are the parameters used by searches that leave them unset
+type Defaults struct {
+	SkillWeight    float64
+	DistanceWeight float64
+	MaxDistanceKm  float64
+	DistanceDecay  string
+	Limit          int
+	// CacheTTL is how long match results are kept; zero turns caching off
+	CacheTTL time.Duration
+	// NotifyScore is the combined score at which volunteers are notified of
+	// a newly published project; zero turns those notifications off
+	NotifyScore float64
+}
+
+func NewService(db *sql.DB, defaults Defaults) *Service {
+	return &Service{
+		db:       db,
+		cache:    newMatchCache(defaults.CacheTTL),
+		defaults: defaults,
+	}
+}
+
+// withDefaults fills in the search parameters left unset. The weights are
+// only defaulted together, since either may deliberately be zero.
+func (s *Service) withDefaults(skillWeight, distanceWeight, maxDistanceKm float64, limit int) (float64, float64, float64, int) {
+	if skillWeight == 0 && distanceWeight == 0 {
+		skillWeight, distanceWeight = s.defaults.SkillWeight, s.defaults.DistanceWeight
+	}
+	if maxDistanceKm == 0 {
+		maxDistanceKm = s.defaults.MaxDistanceKm
+	}
+	if limit == 0 {
+		limit = s.defaults.Limit
+	}
+	return skillWeight, distanceWeight, maxDistanceKm, limit
+}
+
+// withDefaultDecay fills in the distance decay when left unset, and checks
+// it otherwise
+func (s *Service) withDefaultDecay(distanceDecay string) (string, error) {
+	if distanceDecay == "" {
+		return s.defaults.DistanceDecay, nil
+	}
+	if !ValidDistanceDecay(distanceDecay) {
+		return "", ErrInvalidDistanceDecay
+	}
+	return distanceDecay, nil
+}
+
+// searchesSaved reports whether a search can read the matches
+// RecomputeMatches saved, which are scored with the default weights, maximum
+// distance and distance decay. Weights are compared once normalized.
+func (s *Service) searchesSaved(skillWeight, distanceWeight, maxDistanceKm float64, distanceDecay string) bool {
+	share := func(a, b float64) float64 {
+		if a+b == 0 {
+			return 1
+		}
+		return a / (a + b)
+	}
+	const epsilon = 1e-9
+	return math.Abs(share(skillWeight, distanceWeight)-share(s.defaults.SkillWeight, s.defaults.DistanceWeight)) < epsilon &&
+		maxDistanceKm == s.defaults.MaxDistanceKm && distanceDecay == s.defaults.DistanceDecay
 }
 
 // SkillVector represents a skill vector with skill IDs and their weighted scores
@@ -23,62 +108,82 @@ type SkillVector map[string]float64
 
 // GetVolunteerSkillVector returns the weighted skill vector for a volunteer
 // Vector = claimed × score (element-wise multiplication)
-func (s *Service) GetVolunteerSkillVector(volunteerID string) (SkillVector, error) {
+func (s *Service) GetVolunteerSkillVector(ctx context.Context, volunteerID string) (SkillVector, error) {
+	ctx, span := tracing.Start(ctx, "matching.GetVolunteerSkillVector")
+	defer span.End()
+
 	query := `
 		SELECT skill_id, claimed, score
 		FROM volunteer_skills
 		WHERE volunteer_id = $1 AND claimed = TRUE
 	`
 
-	rows, err := s.db.Query(query, volunteerID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	var vector SkillVector
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, query, volunteerID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
 
-	vector := make(SkillVector)
-	for rows.Next() {
-		var skillID string
-		var claimed bool
-		var score float64
+		vector = make(SkillVector)
+		for rows.Next() {
+			var skillID string
+			var claimed bool
+			var score float64
 
-		if err := rows.Scan(&skillID, &claimed, &score); err != nil {
-			return nil, err
-		}
+			if err := rows.Scan(&skillID, &claimed, &score); err != nil {
+				return err
+			}
 
-		// Weighted value: claimed (1.0) × score
-		if claimed {
-			vector[skillID] = score
+			// Weighted value: claimed (1.0) × score
+			if claimed {
+				vector[skillID] = score
+			}
 		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return vector, nil
 }
 
 // GetProjectSkillVector returns the weighted skill demand vector for a project
-func (s *Service) GetProjectSkillVector(projectID string) (SkillVector, error) {
+func (s *Service) GetProjectSkillVector(ctx context.Context, projectID string) (SkillVector, error) {
+	ctx, span := tracing.Start(ctx, "matching.GetProjectSkillVector")
+	defer span.End()
+
 	query := `
 		SELECT skill_id, weight
 		FROM project_skills
 		WHERE project_id = $1
 	`
 
-	rows, err := s.db.Query(query, projectID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	var vector SkillVector
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, query, projectID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
 
-	vector := make(SkillVector)
-	for rows.Next() {
-		var skillID string
-		var weight float64
+		vector = make(SkillVector)
+		for rows.Next() {
+			var skillID string
+			var weight float64
 
-		if err := rows.Scan(&skillID, &weight); err != nil {
-			return nil, err
-		}
+			if err := rows.Scan(&skillID, &weight); err != nil {
+				return err
+			}
 
-		vector[skillID] = weight
+			vector[skillID] = weight
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return vector, nil
@@ -148,50 +253,111 @@ func HaversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
 }
 
 // FindMatchingVolunteers finds and ranks volunteers for a project
-// Uses cached matches from project_volunteer_matches table
+// Results are kept in memory until they expire or a skill change is notified
+// When tenantID is set only members of that organization, and of
+// organizations sharing their volunteers with it, are considered, and with
+// enforceRequired only volunteers claiming every skill the project requires.
+// An unset distanceDecay is defaulted; an unknown one is
+// ErrInvalidDistanceDecay.
 func (s *Service) FindMatchingVolunteers(
+	ctx context.Context,
 	projectID string,
+	tenantID string,
 	skillWeight float64,
 	distanceWeight float64,
 	maxDistanceKm float64,
+	distanceDecay string,
+	enforceRequired bool,
 	limit int,
 ) ([]models.VolunteerMatch, error) {
-	// Default values
-	if limit == 0 {
-		limit = 20
+	skillWeight, distanceWeight, maxDistanceKm, limit = s.withDefaults(skillWeight, distanceWeight, maxDistanceKm, limit)
+	distanceDecay, err := s.withDefaultDecay(distanceDecay)
+	if err != nil {
+		return nil, err
+	}
+	ctx, span := tracing.Start(ctx, "matching.FindMatchingVolunteers",
+		attribute.String("project.id", projectID),
+		attribute.Int("matching.limit", limit),
+	)
+	defer span.End()
+	start := time.Now()
+	key := projectMatchKey(projectID, tenantID, skillWeight, distanceWeight, maxDistanceKm, distanceDecay, enforceRequired, limit)
+	if cached, ok := s.cache.get(key); ok {
+		searchDuration.Observe(time.Since(start).Seconds(), "volunteers", sourceMemory)
+		span.SetAttributes(attribute.String("matching.source", sourceMemory))
+		return cached.([]models.VolunteerMatch), nil
+	}
+
+	matches, source, err := s.findMatchingVolunteers(ctx, projectID, tenantID, skillWeight, distanceWeight, maxDistanceKm, distanceDecay, enforceRequired, limit)
+	searchDuration.Observe(time.Since(start).Seconds(), "volunteers", source)
+	span.SetAttributes(attribute.String("matching.source", source))
+	if err != nil {
+		tracing.Fail(span, err)
+		return nil, err
 	}
+	span.SetAttributes(attribute.Int("matching.results", len(matches)))
 
-	// Use cached matches from the batch processing table
+	s.cache.set(key, matches)
+	return matches, nil
+}
+
+// findMatchingVolunteers uses cached matches from project_volunteer_matches
+// table, and reports which source the matches came from
+func (s *Service) findMatchingVolunteers(
+	ctx context.Context,
+	projectID string,
+	tenantID string,
+	skillWeight float64,
+	distanceWeight float64,
+	maxDistanceKm float64,
+	distanceDecay string,
+	enforceRequired bool,
+	limit int,
+) ([]models.VolunteerMatch, string, error) {
+	if !s.searchesSaved(skillWeight, distanceWeight, maxDistanceKm, distanceDecay) {
+		matches, err := s.findMatchingVolunteersOnDemand(ctx, projectID, tenantID, skillWeight, distanceWeight, maxDistanceKm, distanceDecay, enforceRequired, limit)
+		return matches, sourceOnDemand, err
+	}
+
+	// Use the matches RecomputeMatches saved
 	query := `
 		SELECT
-			volunteer_id,
-			volunteer_name,
-			email,
-			skill_score,
-			distance_km,
-			combined_score,
-			matched_skills,
-			latitude,
-			longitude,
-			location_name
-		FROM get_project_matches($1, $2)
+			u.id,
+			u.name,
+			u.email,
+			m.skill_score,
+			m.distance_km,
+			m.combined_score,
+			m.matched_skills,
+			u.latitude,
+			u.longitude,
+			u.location_name
+		FROM project_volunteer_matches m
+		JOIN users u ON u.id = m.volunteer_id
+		WHERE m.project_id = $1
+		  AND ($3 = '' OR volunteer_visible_to_org(m.volunteer_id, NULLIF($3, '')::uuid))
+		  AND (u.max_travel_km IS NULL OR m.distance_km <= u.max_travel_km)
+		  AND (NOT $4 OR m.has_required_skills)
+		ORDER BY m.combined_score DESC
+		LIMIT $2
 	`
 
-	rows, err := s.db.Query(query, projectID, limit)
+	rows, err := s.db.QueryContext(ctx, query, projectID, limit, tenantID, enforceRequired)
 	if err != nil {
 		// Fallback to on-demand matching if cached matches are not available
-		log.Printf("Cached matches not available, falling back to on-demand matching: %v", err)
-		return s.findMatchingVolunteersOnDemand(projectID, skillWeight, distanceWeight, maxDistanceKm, limit)
+		logging.FromContext(ctx).Warn("Cached matches not available, falling back to on-demand matching", "error", err)
+		matches, err := s.findMatchingVolunteersOnDemand(ctx, projectID, tenantID, skillWeight, distanceWeight, maxDistanceKm, distanceDecay, enforceRequired, limit)
+		return matches, sourceOnDemand, err
 	}
 	defer rows.Close()
 
-	log.Printf("DEBUG: Query executed successfully for project %s with limit %d", projectID, limit)
+	logging.FromContext(ctx).Debug("Match query executed", "project", projectID, "limit", limit)
 	matches := make([]models.VolunteerMatch, 0)
 
 	for rows.Next() {
 		var match models.VolunteerMatch
 		var lat, lon *float64
-		var matchedSkills []string
+		var matchedSkills []byte
 
 		err := rows.Scan(
 			&match.VolunteerID,
@@ -200,7 +366,7 @@ func (s *Service) FindMatchingVolunteers(
 			&match.SkillScore,
 			&match.DistanceKm,
 			&match.CombinedScore,
-			pq.Array(&matchedSkills),
+			&matchedSkills,
 			&lat,
 			&lon,
 			&match.LocationName,
@@ -211,142 +377,185 @@ func (s *Service) FindMatchingVolunteers(
 
 		match.Latitude = lat
 		match.Longitude = lon
-		if matchedSkills == nil {
-			matchedSkills = []string{}
+		if err := json.Unmarshal(matchedSkills, &match.MatchedSkills); err != nil || match.MatchedSkills == nil {
+			match.MatchedSkills = []models.MatchedSkill{}
 		}
-		match.MatchedSkills = matchedSkills
 
 		matches = append(matches, match)
 	}
 
-	log.Printf("DEBUG: Found %d matches for project %s", len(matches), projectID)
-	return matches, nil
+	// Projects not yet recomputed have no saved matches
+	if len(matches) == 0 {
+		logging.FromContext(ctx).Debug("No precomputed matches, falling back to on-demand matching", "project", projectID)
+		matches, err := s.findMatchingVolunteersOnDemand(ctx, projectID, tenantID, skillWeight, distanceWeight, maxDistanceKm, distanceDecay, enforceRequired, limit)
+		return matches, sourceOnDemand, err
+	}
+
+	logging.FromContext(ctx).Debug("Found matches", "project", projectID, "count", len(matches))
+	return matches, sourcePrecomputed, nil
 }
 
-// findMatchingVolunteersOnDemand provides fallback on-demand matching
+// findMatchingVolunteersOnDemand scores every volunteer visible to the
+// tenant against the project the way RecomputeMatches does, with the
+// search's parameters
 func (s *Service) findMatchingVolunteersOnDemand(
+	ctx context.Context,
 	projectID string,
+	tenantID string,
 	skillWeight float64,
 	distanceWeight float64,
 	maxDistanceKm float64,
+	distanceDecay string,
+	enforceRequired bool,
 	limit int,
 ) ([]models.VolunteerMatch, error) {
-	// Default weights
-	if skillWeight == 0 && distanceWeight == 0 {
-		skillWeight = 0.7
-		distanceWeight = 0.3
+	projects, err := s.matchProjects(ctx, []string{projectID}, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
 	}
-
-	// Normalize weights
-	totalWeight := skillWeight + distanceWeight
-	skillWeight /= totalWeight
-	distanceWeight /= totalWeight
-
-	// Default values
-	if maxDistanceKm == 0 {
-		maxDistanceKm = 100
+	if len(projects) == 0 {
+		return []models.VolunteerMatch{}, nil
 	}
-	if limit == 0 {
-		limit = 20
+	project := projects[0]
+
+	// Volunteers outside the tenant, and organizations sharing theirs with
+	// it, aren't loaded
+	var visibleIDs []string
+	if tenantID != "" {
+		if visibleIDs, err = s.visibleVolunteers(ctx, tenantID); err != nil {
+			return nil, fmt.Errorf("failed to get visible volunteers: %w", err)
+		}
 	}
-
-	// Use PostgreSQL native function for matching
-	query := `
-		SELECT
-			volunteer_id,
-			volunteer_name,
-			email,
-			skill_score,
-			distance_km,
-			combined_score,
-			latitude,
-			longitude,
-			location_name
-		FROM find_matching_volunteers($1, $2, $3, $4, $5)
-	`
-
-	rows, err := s.db.Query(query, projectID, skillWeight, distanceWeight, maxDistanceKm, limit)
+	volunteers, err := s.matchVolunteers(ctx, visibleIDs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find matches: %w", err)
+		return nil, fmt.Errorf("failed to get volunteers: %w", err)
+	}
+	skillNames, err := s.skillNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skills: %w", err)
 	}
-	defer rows.Close()
-
-	var matches []models.VolunteerMatch
-
-	for rows.Next() {
-		var match models.VolunteerMatch
-		var lat, lon *float64
 
-		err := rows.Scan(
-			&match.VolunteerID,
-			&match.VolunteerName,
-			&match.Email,
-			&match.SkillScore,
-			&match.DistanceKm,
-			&match.CombinedScore,
-			&lat,
-			&lon,
-			&match.LocationName,
-		)
-		if err != nil {
-			continue
+	params := scoreParams{
+		skillWeight:     skillWeight,
+		distanceWeight:  distanceWeight,
+		maxDistanceKm:   maxDistanceKm,
+		distanceDecay:   distanceDecay,
+		enforceRequired: enforceRequired,
+	}
+	volunteersByID := make(map[string]matchVolunteer, len(volunteers))
+	scored := make([]precomputedMatch, 0)
+	for _, v := range volunteers {
+		if m, ok := s.scorePair(project, v, skillNames, params); ok {
+			scored = append(scored, m)
+			volunteersByID[v.id] = v
 		}
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].combinedScore > scored[j].combinedScore
+	})
+	if len(scored) > limit {
+		scored = scored[:limit]
+	}
 
-		match.Latitude = lat
-		match.Longitude = lon
+	ids := make([]string, len(scored))
+	for i, m := range scored {
+		ids[i] = m.volunteerID
+	}
+	contacts, err := s.volunteerContacts(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volunteer details: %w", err)
+	}
 
-		// Get matched skills for display
-		matchedSkills, _ := s.getMatchedSkillNames(match.VolunteerID, projectID)
-		if matchedSkills == nil {
-			matchedSkills = []string{} // Ensure it's never null
+	matches := make([]models.VolunteerMatch, 0, len(scored))
+	for _, m := range scored {
+		match := models.VolunteerMatch{
+			VolunteerID:   m.volunteerID,
+			VolunteerName: contacts[m.volunteerID].name,
+			Email:         contacts[m.volunteerID].email,
+			SkillScore:    m.skillScore,
+			DistanceKm:    m.distanceKm,
+			CombinedScore: m.combinedScore,
+			MatchedSkills: m.matchedSkills,
+		}
+		// Show the location the volunteer was scored from
+		if l, _, _ := nearestLocation(project.location, volunteersByID[m.volunteerID].locations); l != nil {
+			lat, lon := l.lat, l.lon
+			match.Latitude = &lat
+			match.Longitude = &lon
+			match.LocationName = l.name
 		}
-		match.MatchedSkills = matchedSkills
-
 		matches = append(matches, match)
 	}
 
 	return matches, nil
 }
 
-// getMatchedSkillNames returns skill IDs that exist in both volunteer and project
-func (s *Service) getMatchedSkillNames(volunteerID, projectID string) ([]string, error) {
-	query := `
-		SELECT DISTINCT s.id
-		FROM volunteer_skills vs
-		JOIN project_skills ps ON vs.skill_id = ps.skill_id
-		JOIN skills s ON vs.skill_id = s.id
-		WHERE vs.volunteer_id = $1
-		  AND ps.project_id = $2
-		  AND vs.claimed = TRUE
-		ORDER BY s.name
-	`
-
-	rows, err := s.db.Query(query, volunteerID, projectID)
-	if err != nil {
-		return []string{}, err // Return empty slice instead of nil
-	}
-	defer rows.Close()
+// visibleVolunteers returns the IDs of the volunteers organization tenantID
+// may see: its members and those of organizations sharing theirs with it
+func (s *Service) visibleVolunteers(ctx context.Context, tenantID string) ([]string, error) {
+	ids := []string{}
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, `
+			SELECT id FROM users
+			WHERE role = 'volunteer' AND volunteer_visible_to_org(id, $1::uuid)
+		`, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
 
-	var skillIDs []string
-	for rows.Next() {
-		var skillID string
-		if err := rows.Scan(&skillID); err != nil {
-			continue
+		ids = ids[:0]
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			ids = append(ids, id)
 		}
-		skillIDs = append(skillIDs, skillID)
-	}
+		return rows.Err()
+	})
+	return ids, err
+}
 
-	// Ensure we always return a slice, never nil
-	if skillIDs == nil {
-		skillIDs = []string{}
+type volunteerContact struct{ name, email string }
+
+// volunteerContacts returns the names and emails of volunteers by ID
+func (s *Service) volunteerContacts(ctx context.Context, ids []string) (map[string]volunteerContact, error) {
+	contacts := map[string]volunteerContact{}
+	if len(ids) == 0 {
+		return contacts, nil
 	}
-	return skillIDs, nil
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, `SELECT id, name, email FROM users WHERE id = ANY($1)`, pq.Array(ids))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id string
+			var c volunteerContact
+			if err := rows.Scan(&id, &c.name, &c.email); err != nil {
+				return err
+			}
+			contacts[id] = c
+		}
+		return rows.Err()
+	})
+	return contacts, err
 }
 
+// RefreshSkillVectorsJob is the kind of background job that runs
+// RefreshSkillVectors
+const RefreshSkillVectorsJob = "matching.refresh_skill_vectors"
+
 // RefreshSkillVectors refreshes the materialized view of skill vectors
 // Should be called periodically (e.g., by cron job after volunteer updates)
-func (s *Service) RefreshSkillVectors() error {
-	_, err := s.db.Exec("REFRESH MATERIALIZED VIEW volunteer_skill_vectors")
+func (s *Service) RefreshSkillVectors(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "matching.RefreshSkillVectors")
+	defer span.End()
+
+	_, err := s.db.ExecContext(ctx, "REFRESH MATERIALIZED VIEW volunteer_skill_vectors")
 	return err
 }
 
@@ -361,50 +570,117 @@ func getMatchedSkills(v1, v2 SkillVector) []string {
 	return matched
 }
 
-// sortMatchesByScore sorts matches by combined score in descending order
-func sortMatchesByScore(matches []models.VolunteerMatch) {
-	for i := 0; i < len(matches)-1; i++ {
-		for j := i + 1; j < len(matches); j++ {
-			if matches[j].CombinedScore > matches[i].CombinedScore {
-				matches[i], matches[j] = matches[j], matches[i]
-			}
+// sortMatchedSkills sorts matched skills by name, then ID
+func sortMatchedSkills(skills []models.MatchedSkill) {
+	sort.Slice(skills, func(i, j int) bool {
+		if skills[i].Name != skills[j].Name {
+			return skills[i].Name < skills[j].Name
 		}
-	}
+		return skills[i].ID < skills[j].ID
+	})
 }
 
 // FindMatchingProjects finds and ranks projects for a volunteer
-// Uses cached matches from project_volunteer_matches table
+// Results are kept in memory until they expire or a skill change is notified
+// When tenantID is set only that organization's projects are considered, and
+// when remote is set only remote or only on-site projects. With
+// enforceRequired only projects whose required skills the volunteer all
+// claims are considered. An unset distanceDecay is defaulted; an unknown one
+// is ErrInvalidDistanceDecay.
 func (s *Service) FindMatchingProjects(
+	ctx context.Context,
 	volunteerID string,
+	tenantID string,
+	remote *bool,
 	skillWeight float64,
 	distanceWeight float64,
 	maxDistanceKm float64,
+	distanceDecay string,
+	enforceRequired bool,
 	limit int,
 ) ([]models.ProjectMatch, error) {
-	if limit == 0 {
-		limit = 20
+	skillWeight, distanceWeight, maxDistanceKm, limit = s.withDefaults(skillWeight, distanceWeight, maxDistanceKm, limit)
+	distanceDecay, err := s.withDefaultDecay(distanceDecay)
+	if err != nil {
+		return nil, err
+	}
+	ctx, span := tracing.Start(ctx, "matching.FindMatchingProjects",
+		attribute.String("volunteer.id", volunteerID),
+		attribute.Int("matching.limit", limit),
+	)
+	defer span.End()
+	start := time.Now()
+	key := volunteerMatchKey(volunteerID, tenantID, remote, skillWeight, distanceWeight, maxDistanceKm, distanceDecay, enforceRequired, limit)
+	if cached, ok := s.cache.get(key); ok {
+		searchDuration.Observe(time.Since(start).Seconds(), "projects", sourceMemory)
+		span.SetAttributes(attribute.String("matching.source", sourceMemory))
+		return cached.([]models.ProjectMatch), nil
+	}
+
+	matches, source, err := s.findMatchingProjects(ctx, volunteerID, tenantID, remote, skillWeight, distanceWeight, maxDistanceKm, distanceDecay, enforceRequired, limit)
+	searchDuration.Observe(time.Since(start).Seconds(), "projects", source)
+	span.SetAttributes(attribute.String("matching.source", source))
+	if err != nil {
+		tracing.Fail(span, err)
+		return nil, err
 	}
+	span.SetAttributes(attribute.Int("matching.results", len(matches)))
+
+	s.cache.set(key, matches)
+	return matches, nil
+}
 
-	// Use cached matches from the batch processing table
+// findMatchingProjects uses cached matches from project_volunteer_matches
+// table, and reports which source the matches came from
+func (s *Service) findMatchingProjects(
+	ctx context.Context,
+	volunteerID string,
+	tenantID string,
+	remote *bool,
+	skillWeight float64,
+	distanceWeight float64,
+	maxDistanceKm float64,
+	distanceDecay string,
+	enforceRequired bool,
+	limit int,
+) ([]models.ProjectMatch, string, error) {
+	if !s.searchesSaved(skillWeight, distanceWeight, maxDistanceKm, distanceDecay) {
+		matches, err := s.findMatchingProjectsOnDemand(ctx, volunteerID, tenantID, remote, skillWeight, distanceWeight, maxDistanceKm, distanceDecay, enforceRequired, limit)
+		return matches, sourceOnDemand, err
+	}
+
+	// Use the matches RecomputeMatches saved
 	query := `
         SELECT
-            project_id,
-            project_name,
-            skill_score,
-            distance_km,
-            combined_score,
-            matched_skills,
-            latitude,
-            longitude,
-            location_name
-        FROM get_volunteer_matches($1, $2)
+            p.id,
+            p.name,
+            m.skill_score,
+            m.distance_km,
+            m.combined_score,
+            m.matched_skills,
+            p.latitude,
+            p.longitude,
+            p.location_name,
+            p.is_remote
+        FROM project_volunteer_matches m
+        JOIN projects p ON p.id = m.project_id
+        JOIN users v ON v.id = m.volunteer_id
+        WHERE m.volunteer_id = $1
+          AND p.status = 'active'
+          AND ($3 = '' OR p.organization_id = NULLIF($3, '')::uuid)
+          AND ($4::boolean IS NULL OR p.is_remote = $4)
+          AND (v.max_travel_km IS NULL OR m.distance_km <= v.max_travel_km)
+          AND (NOT $5 OR m.has_required_skills)
+        ORDER BY m.combined_score DESC
+        LIMIT $2
     `
 
-	rows, err := s.db.Query(query, volunteerID, limit)
+	rows, err := s.db.QueryContext(ctx, query, volunteerID, limit, tenantID, remote, enforceRequired)
 	if err != nil {
 		// Fallback to on-demand matching if cached matches are not available
-		log.Printf("Cached matches not available for volunteer, falling back to on-demand matching: %v", err)
-		return s.findMatchingProjectsOnDemand(volunteerID, skillWeight, distanceWeight, maxDistanceKm, limit)
+		logging.FromContext(ctx).Warn("Cached matches not available for volunteer, falling back to on-demand matching", "error", err)
+		matches, err := s.findMatchingProjectsOnDemand(ctx, volunteerID, tenantID, remote, skillWeight, distanceWeight, maxDistanceKm, distanceDecay, enforceRequired, limit)
+		return matches, sourceOnDemand, err
 	}
 	defer rows.Close()
 
@@ -412,7 +688,7 @@ func (s *Service) FindMatchingProjects(
 	for rows.Next() {
 		var match models.ProjectMatch
 		var lat, lon *float64
-		var matchedSkills []string
+		var matchedSkills []byte
 
 		err := rows.Scan(
 			&match.ProjectID,
@@ -420,10 +696,11 @@ func (s *Service) FindMatchingProjects(
 			&match.SkillScore,
 			&match.DistanceKm,
 			&match.CombinedScore,
-			pq.Array(&matchedSkills),
+			&matchedSkills,
 			&lat,
 			&lon,
 			&match.LocationName,
+			&match.IsRemote,
 		)
 		if err != nil {
 			continue
@@ -431,77 +708,91 @@ func (s *Service) FindMatchingProjects(
 
 		match.Latitude = lat
 		match.Longitude = lon
-		if matchedSkills == nil {
-			matchedSkills = []string{}
+		if err := json.Unmarshal(matchedSkills, &match.MatchedSkills); err != nil || match.MatchedSkills == nil {
+			match.MatchedSkills = []models.MatchedSkill{}
 		}
-		match.MatchedSkills = matchedSkills
 
 		matches = append(matches, match)
 	}
 
-	return matches, nil
+	// Volunteers not yet recomputed have no saved matches
+	if len(matches) == 0 {
+		logging.FromContext(ctx).Debug("No precomputed matches for volunteer, falling back to on-demand matching", "volunteer", volunteerID)
+		matches, err := s.findMatchingProjectsOnDemand(ctx, volunteerID, tenantID, remote, skillWeight, distanceWeight, maxDistanceKm, distanceDecay, enforceRequired, limit)
+		return matches, sourceOnDemand, err
+	}
+
+	return matches, sourcePrecomputed, nil
 }
 
-// findMatchingProjectsOnDemand provides fallback on-demand matching for volunteers
+// findMatchingProjectsOnDemand scores the volunteer against every active
+// project in scope the way RecomputeMatches does, with the search's weights
+// and maximum distance
 func (s *Service) findMatchingProjectsOnDemand(
+	ctx context.Context,
 	volunteerID string,
+	tenantID string,
+	remote *bool,
 	skillWeight float64,
 	distanceWeight float64,
 	maxDistanceKm float64,
+	distanceDecay string,
+	enforceRequired bool,
 	limit int,
 ) ([]models.ProjectMatch, error) {
-	if limit == 0 {
-		limit = 20
+	volunteers, err := s.matchVolunteers(ctx, []string{volunteerID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volunteer: %w", err)
 	}
-
-	// Simple fallback - return active projects
-	query := `
-        SELECT 
-            p.id,
-            p.name,
-            0.5 AS skill_score,
-            0.0 AS distance_km,
-            0.5 AS combined_score,
-            p.latitude,
-            p.longitude,
-            p.location_name
-        FROM projects p
-        WHERE p.status = 'active'
-        ORDER BY p.name
-        LIMIT $1
-    `
-
-	rows, err := s.db.Query(query, limit)
+	if len(volunteers) == 0 {
+		return nil, ErrVolunteerNotFound
+	}
+	projects, err := s.matchProjects(ctx, nil, tenantID, remote)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find project matches: %w", err)
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+	skillNames, err := s.skillNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skills: %w", err)
 	}
-	defer rows.Close()
-
-	var matches []models.ProjectMatch
-	for rows.Next() {
-		var match models.ProjectMatch
-		var lat, lon *float64
 
-		err := rows.Scan(
-			&match.ProjectID,
-			&match.ProjectName,
-			&match.SkillScore,
-			&match.DistanceKm,
-			&match.CombinedScore,
-			&lat,
-			&lon,
-			&match.LocationName,
-		)
-		if err != nil {
+	params := scoreParams{
+		skillWeight:     skillWeight,
+		distanceWeight:  distanceWeight,
+		maxDistanceKm:   maxDistanceKm,
+		distanceDecay:   distanceDecay,
+		enforceRequired: enforceRequired,
+	}
+	matches := make([]models.ProjectMatch, 0)
+	for _, p := range projects {
+		m, ok := s.scorePair(p, volunteers[0], skillNames, params)
+		if !ok {
 			continue
 		}
 
-		match.Latitude = lat
-		match.Longitude = lon
-		match.MatchedSkills = []string{}
-
+		match := models.ProjectMatch{
+			ProjectID:     p.id,
+			ProjectName:   p.name,
+			SkillScore:    m.skillScore,
+			DistanceKm:    m.distanceKm,
+			CombinedScore: m.combinedScore,
+			MatchedSkills: m.matchedSkills,
+			LocationName:  p.locationName,
+			IsRemote:      p.remote,
+		}
+		if p.location != nil {
+			lat, lon := p.location.lat, p.location.lon
+			match.Latitude = &lat
+			match.Longitude = &lon
+		}
 		matches = append(matches, match)
 	}
 
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].CombinedScore > matches[j].CombinedScore
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
 	return matches, nil
 }
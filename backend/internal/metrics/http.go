@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+var (
+	httpRequests = NewCounterVec("civicweave_http_requests_total",
+		"HTTP requests served, by method, route and status.", "method", "route", "status")
+	httpDuration = NewHistogramVec("civicweave_http_request_duration_seconds",
+		"Time taken to serve HTTP requests, by method and route.", DefaultBuckets, "method", "route")
+)
+
+// Middleware records the latency and status of each request. Requests are
+// labeled with their route template rather than their path, so IDs in
+// paths don't each get their own series.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unmatched"
+		if current := mux.CurrentRoute(r); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+
+		httpDuration.Observe(time.Since(start).Seconds(), r.Method, route)
+		httpRequests.Inc(r.Method, route, strconv.Itoa(sw.status))
+	})
+}
+
+// statusWriter records the status of a response
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status, w.wroteHeader = status, true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush passes flushes through for streamed responses
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// RegisterDBStats exports the connection pool statistics of db
+func RegisterDBStats(db *sql.DB) {
+	NewGaugeFunc("civicweave_db_max_open_connections", "Maximum number of open database connections.",
+		func() float64 { return float64(db.Stats().MaxOpenConnections) })
+	NewGaugeFunc("civicweave_db_open_connections", "Open database connections, in use or idle.",
+		func() float64 { return float64(db.Stats().OpenConnections) })
+	NewGaugeFunc("civicweave_db_in_use_connections", "Database connections in use.",
+		func() float64 { return float64(db.Stats().InUse) })
+	NewGaugeFunc("civicweave_db_idle_connections", "Idle database connections.",
+		func() float64 { return float64(db.Stats().Idle) })
+	NewCounterFunc("civicweave_db_wait_count_total", "Times a query waited for a free database connection.",
+		func() float64 { return float64(db.Stats().WaitCount) })
+	NewCounterFunc("civicweave_db_wait_duration_seconds_total", "Time spent waiting for a free database connection.",
+		func() float64 { return db.Stats().WaitDuration.Seconds() })
+}
@@ -0,0 +1,260 @@
+// Package metrics keeps counters, gauges and histograms and serves them in
+// the Prometheus text exposition format. Metrics register themselves when
+// created, so packages declare theirs as package variables.
+package metrics
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are histogram upper bounds in seconds suited to request
+// and query latencies
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// collector writes its series in the exposition format
+type collector interface {
+	name() string
+	write(w io.Writer)
+}
+
+var registry struct {
+	mu         sync.Mutex
+	collectors map[string]collector
+}
+
+func register(c collector) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if registry.collectors == nil {
+		registry.collectors = map[string]collector{}
+	}
+	if _, ok := registry.collectors[c.name()]; ok {
+		panic("metrics: " + c.name() + " registered twice")
+	}
+	registry.collectors[c.name()] = c
+}
+
+// Handler serves every registered metric. When token is set, scrapers must
+// send it as a bearer token.
+func Handler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		registry.mu.Lock()
+		collectors := make([]collector, 0, len(registry.collectors))
+		for _, c := range registry.collectors {
+			collectors = append(collectors, c)
+		}
+		registry.mu.Unlock()
+		sort.Slice(collectors, func(i, j int) bool { return collectors[i].name() < collectors[j].name() })
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		for _, c := range collectors {
+			c.write(w)
+		}
+	})
+}
+
+// family holds the series of one labeled metric
+type family struct {
+	metricName string
+	help       string
+	kind       string
+	labels     []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	// Histograms only
+	counts []uint64
+	sum    float64
+}
+
+func (f *family) name() string { return f.metricName }
+
+// get returns the series with the label values, creating it
+func (f *family) get(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.metricName, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), values...)}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (f *family) sorted() []*series {
+	all := make([]*series, 0, len(f.series))
+	for _, s := range f.series {
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return strings.Join(all[i].labelValues, "\xff") < strings.Join(all[j].labelValues, "\xff")
+	})
+	return all
+}
+
+func (f *family) header(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.metricName, f.help, f.metricName, f.kind)
+}
+
+// CounterVec counts events by label values
+type CounterVec struct {
+	family
+}
+
+// NewCounterVec registers a counter partitioned by labels
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{family{metricName: name, help: help, kind: "counter", labels: labels, series: map[string]*series{}}}
+	register(c)
+	return c
+}
+
+// Inc adds one to the series with the label values
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v, which must not be negative, to the series with the label values
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.get(labelValues).value += v
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header(w)
+	for _, s := range c.sorted() {
+		fmt.Fprintf(w, "%s%s %s\n", c.metricName, labelPairs(c.labels, s.labelValues, "", ""), formatFloat(s.value))
+	}
+}
+
+// HistogramVec observes value distributions by label values
+type HistogramVec struct {
+	family
+	buckets []float64
+}
+
+// NewHistogramVec registers a histogram with the bucket upper bounds,
+// partitioned by labels
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{
+		family:  family{metricName: name, help: help, kind: "histogram", labels: labels, series: map[string]*series{}},
+		buckets: append([]float64(nil), buckets...),
+	}
+	sort.Float64s(h.buckets)
+	register(h)
+	return h
+}
+
+// Observe records v in the series with the label values
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.get(labelValues)
+	if s.counts == nil {
+		s.counts = make([]uint64, len(h.buckets)+1)
+	}
+	i := sort.SearchFloat64s(h.buckets, v)
+	s.counts[i]++
+	s.sum += v
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header(w)
+	for _, s := range h.sorted() {
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, labelPairs(h.labels, s.labelValues, "le", formatFloat(bound)), cumulative)
+		}
+		cumulative += s.counts[len(h.buckets)]
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, labelPairs(h.labels, s.labelValues, "le", "+Inf"), cumulative)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metricName, labelPairs(h.labels, s.labelValues, "", ""), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metricName, labelPairs(h.labels, s.labelValues, "", ""), cumulative)
+	}
+}
+
+// funcMetric reads its value when scraped
+type funcMetric struct {
+	metricName string
+	help       string
+	kind       string
+	value      func() float64
+}
+
+// NewGaugeFunc registers a gauge whose value is read from fn when scraped
+func NewGaugeFunc(name, help string, fn func() float64) {
+	register(&funcMetric{metricName: name, help: help, kind: "gauge", value: fn})
+}
+
+// NewCounterFunc registers a counter whose value is read from fn when
+// scraped, for totals kept elsewhere
+func NewCounterFunc(name, help string, fn func() float64) {
+	register(&funcMetric{metricName: name, help: help, kind: "counter", value: fn})
+}
+
+func (m *funcMetric) name() string { return m.metricName }
+
+func (m *funcMetric) write(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %s\n", m.metricName, m.help, m.metricName, m.kind, m.metricName, formatFloat(m.value()))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelPairs formats the labels of a series, with an extra pair when
+// extraName is set
+func labelPairs(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, n := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, n, labelEscaper.Replace(values[i]))
+	}
+	if extraName != "" {
+		if len(names) > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, extraName, labelEscaper.Replace(extraValue))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
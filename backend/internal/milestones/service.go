@@ -0,0 +1,183 @@
+package milestones
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/notifications"
+	"github.com/lib/pq"
+)
+
+// DefaultThresholds are the logged hours milestones are awarded at unless
+// HOUR_MILESTONES says otherwise
+const DefaultThresholds = "25,100,500"
+
+// ParseThresholds reads a comma-separated list of positive whole hours,
+// returning them sorted without duplicates
+func ParseThresholds(s string) ([]int, error) {
+	seen := make(map[int]bool)
+	var thresholds []int
+	for _, field := range strings.Split(s, ",") {
+		hours, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || hours <= 0 {
+			return nil, fmt.Errorf("invalid milestone %q: must be a positive whole number of hours", field)
+		}
+		if !seen[hours] {
+			seen[hours] = true
+			thresholds = append(thresholds, hours)
+		}
+	}
+	sort.Ints(thresholds)
+	return thresholds, nil
+}
+
+// award is a milestone just reached, with who to congratulate
+type award struct {
+	VolunteerID string
+	Name        string
+	Email       string
+	Locale      string
+	Hours       int
+	AwardedAt   time.Time
+}
+
+type Service struct {
+	db         *sql.DB
+	thresholds []int
+	mailer     notifications.Mailer
+}
+
+func NewService(db *sql.DB, thresholds []int, mailer notifications.Mailer) *Service {
+	return &Service{db: db, thresholds: thresholds, mailer: mailer}
+}
+
+// GetMilestones lists the hours milestones a volunteer has reached, lowest first
+func (s *Service) GetMilestones(volunteerID string) ([]models.HourMilestone, error) {
+	query := `
+		SELECT hours, awarded_at
+		FROM volunteer_milestones
+		WHERE volunteer_id::text = $1
+		ORDER BY hours
+	`
+
+	var list []models.HourMilestone
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, volunteerID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		list = []models.HourMilestone{}
+		for rows.Next() {
+			var m models.HourMilestone
+			if err := rows.Scan(&m.Hours, &m.AwardedAt); err != nil {
+				return err
+			}
+			list = append(list, m)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// awardMilestones records every configured milestone the volunteer's logged hours have
+// reached, or checks every volunteer when volunteerID is empty. Only
+// milestones this call recorded are returned, so when several instances
+// handle the same event exactly one of them sees each award.
+func (s *Service) awardMilestones(volunteerID string) ([]award, error) {
+	query := `
+		WITH totals AS (
+			SELECT u.id, u.name, u.email, u.locale, COALESCE(SUM(vh.hours), 0) AS total
+			FROM users u
+			LEFT JOIN volunteer_hours vh ON vh.volunteer_id = u.id
+			WHERE $1 = '' OR u.id = NULLIF($1, '')::uuid
+			GROUP BY u.id
+		), awarded AS (
+			INSERT INTO volunteer_milestones (volunteer_id, hours)
+			SELECT t.id, m.hours
+			FROM totals t
+			CROSS JOIN unnest($2::int[]) AS m(hours)
+			WHERE t.total >= m.hours
+			ON CONFLICT (volunteer_id, hours) DO NOTHING
+			RETURNING volunteer_id, hours, awarded_at
+		)
+		SELECT a.volunteer_id, t.name, t.email, t.locale, a.hours, a.awarded_at
+		FROM awarded a
+		JOIN totals t ON t.id = a.volunteer_id
+		ORDER BY a.volunteer_id, a.hours
+	`
+
+	var awards []award
+	err := database.WithWriteGuard(func() error {
+		rows, err := s.db.Query(query, volunteerID, pq.Array(s.thresholds))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var a award
+			if err := rows.Scan(&a.VolunteerID, &a.Name, &a.Email, &a.Locale, &a.Hours, &a.AwardedAt); err != nil {
+				return err
+			}
+			awards = append(awards, a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return awards, nil
+}
+
+// HandleActivity consumes payloads from database.VolunteerActivityChannel,
+// awarding milestones when a volunteer logs hours and emailing them about
+// each one. An empty payload means events may have been missed, so every
+// volunteer is checked.
+func (s *Service) HandleActivity(payload string) {
+	var event struct {
+		Table       string `json:"table"`
+		VolunteerID string `json:"volunteerId"`
+	}
+	if payload != "" {
+		if err := json.Unmarshal([]byte(payload), &event); err != nil || event.VolunteerID == "" {
+			slog.Warn("Ignoring malformed volunteer activity payload", "payload", payload)
+			return
+		}
+		if event.Table != "volunteer_hours" {
+			return
+		}
+	}
+
+	awards, err := s.awardMilestones(event.VolunteerID)
+	if err != nil {
+		slog.Error("Award milestones error", "volunteer", event.VolunteerID, "error", err)
+		return
+	}
+	for _, a := range awards {
+		slog.Info("Awarded hour milestone", "hours", a.Hours, "volunteer", a.VolunteerID)
+		msg := notifications.RenderHourMilestone(notifications.HourMilestone{
+			To:            a.Email,
+			Locale:        a.Locale,
+			VolunteerName: a.Name,
+			Hours:         a.Hours,
+		})
+		if err := s.mailer.Send(msg); err != nil {
+			slog.Error("Milestone email error", "volunteer", a.VolunteerID, "hours", a.Hours, "error", err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// FunnelStages counts volunteers reaching each recruitment stage
+type FunnelStages struct {
+	MatchesShown    int `json:"matchesShown"`
+	InvitationsSent int `json:"invitationsSent"`
+	RequestsMade    int `json:"requestsMade"`
+	Accepted        int `json:"accepted"`
+	Completed       int `json:"completed"`
+}
+
+// FunnelReport breaks the recruitment funnel down over time and by project.
+// Each stage is counted when it happened, within the inclusive From-To range.
+type FunnelReport struct {
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Interval string          `json:"interval"`
+	Totals   FunnelStages    `json:"totals"`
+	Series   []FunnelPeriod  `json:"series"`
+	Projects []ProjectFunnel `json:"projects"`
+}
+
+// FunnelPeriod holds the stages reached in the period starting on Period
+type FunnelPeriod struct {
+	Period string `json:"period"` // YYYY-MM-DD
+	FunnelStages
+}
+
+type ProjectFunnel struct {
+	ProjectID   string `json:"projectId"`
+	ProjectName string `json:"projectName"`
+	FunnelStages
+}
+
+// RetentionReport follows monthly cohorts of volunteers who completed a
+// project and how many enrolled again afterwards
+type RetentionReport struct {
+	From    string            `json:"from"` // YYYY-MM
+	To      string            `json:"to"`   // YYYY-MM
+	Cohorts []RetentionCohort `json:"cohorts"`
+}
+
+// RetentionCohort counts the volunteers completing a project in Month and
+// those enrolled again within 3 and 6 months. Counts are nil until the
+// window has fully elapsed for the whole cohort.
+type RetentionCohort struct {
+	Month           string   `json:"month"` // YYYY-MM
+	Volunteers      int      `json:"volunteers"`
+	Retained3Months *int     `json:"retained3Months"`
+	Retained6Months *int     `json:"retained6Months"`
+	Rate3Months     *float64 `json:"rate3Months"`
+	Rate6Months     *float64 `json:"rate6Months"`
+}
+
+// ClientEvent is one interaction reported by the frontend
+type ClientEvent struct {
+	Type        string          `json:"type" validate:"required,max=100"`
+	OccurredAt  *time.Time      `json:"occurredAt,omitempty"` // defaults to when it was received
+	ProjectID   *string         `json:"projectId,omitempty"`
+	VolunteerID *string         `json:"volunteerId,omitempty"`
+	SessionID   *string         `json:"sessionId,omitempty" validate:"max=100"`
+	Properties  json.RawMessage `json:"properties,omitempty"`
+}
+
+type RecordEventsRequest struct {
+	Events []ClientEvent `json:"events" validate:"required"`
+}
+
+// RecordEventsResponse reports how many events were stored; the rest were
+// dropped by sampling
+type RecordEventsResponse struct {
+	Received int `json:"received"`
+	Recorded int `json:"recorded"`
+}
+
+// Leaderboards ranks opted-in volunteers over a period. From is nil for
+// all-time boards.
+type Leaderboards struct {
+	Period            string             `json:"period"`
+	From              *string            `json:"from"` // YYYY-MM-DD
+	Hours             []LeaderboardEntry `json:"hours"`
+	ProjectsCompleted []LeaderboardEntry `json:"projectsCompleted"`
+}
+
+// LeaderboardEntry is one volunteer's place on a board; ties share a rank
+type LeaderboardEntry struct {
+	Rank          int     `json:"rank"`
+	VolunteerID   string  `json:"volunteerId"`
+	VolunteerName string  `json:"volunteerName"`
+	Value         float64 `json:"value"`
+}
+
+type UpdateLeaderboardOptInRequest struct {
+	OptIn bool `json:"optIn"`
+}
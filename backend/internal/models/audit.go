@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AuditEntry records a privileged action: who did it, what it was and which
+// record it touched
+type AuditEntry struct {
+	ID         string            `json:"id"`
+	ActorID    *string           `json:"actorId,omitempty"`
+	Action     string            `json:"action"`
+	TargetType string            `json:"targetType"`
+	TargetID   string            `json:"targetId"`
+	Details    map[string]string `json:"details"`
+	CreatedAt  time.Time         `json:"createdAt"`
+}
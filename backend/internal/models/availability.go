@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// AvailabilityRule is a recurring window when a volunteer is available.
+// StartTime and EndTime are HH:MM in Timezone; an EndTime before StartTime
+// runs past midnight.
+type AvailabilityRule struct {
+	ID           string    `json:"id"`
+	VolunteerID  string    `json:"volunteerId"`
+	RRule        string    `json:"rrule"`
+	StartTime    string    `json:"startTime"`
+	EndTime      string    `json:"endTime"`
+	Timezone     string    `json:"timezone"`
+	StartsOn     string    `json:"startsOn"`    // YYYY-MM-DD
+	ExceptDates  []string  `json:"exceptDates"` // YYYY-MM-DD
+	SkipHolidays bool      `json:"skipHolidays"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type CreateAvailabilityRuleRequest struct {
+	RRule        string   `json:"rrule" validate:"required"`
+	StartTime    string   `json:"startTime" validate:"required,clock"`
+	EndTime      string   `json:"endTime" validate:"required,clock"`
+	Timezone     *string  `json:"timezone,omitempty" validate:"timezone"` // defaults to the volunteer's zone
+	StartsOn     *string  `json:"startsOn,omitempty" validate:"date"`     // defaults to today
+	ExceptDates  []string `json:"exceptDates,omitempty" validate:"max=366"`
+	SkipHolidays bool     `json:"skipHolidays"`
+}
+
+// AvailabilitySlot is one occurrence of an availability rule, or for a
+// volunteer with blackouts but no rules, a window between blackouts
+type AvailabilitySlot struct {
+	RuleID string    `json:"ruleId,omitempty"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// AvailabilityBlackout is a range of days when a volunteer is away,
+// overriding their rules. Days are in the volunteer's zone.
+type AvailabilityBlackout struct {
+	ID          string    `json:"id"`
+	VolunteerID string    `json:"volunteerId"`
+	StartsOn    string    `json:"startsOn"` // YYYY-MM-DD
+	EndsOn      string    `json:"endsOn"`   // YYYY-MM-DD, inclusive
+	Reason      *string   `json:"reason,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type CreateAvailabilityBlackoutRequest struct {
+	StartsOn string  `json:"startsOn" validate:"required,date"`
+	EndsOn   string  `json:"endsOn" validate:"required,date,notbefore=StartsOn"`
+	Reason   *string `json:"reason,omitempty" validate:"max=200"`
+}
+
+type Holiday struct {
+	Date string `json:"date" validate:"required,date"` // YYYY-MM-DD
+	Name string `json:"name" validate:"required,max=100"`
+}
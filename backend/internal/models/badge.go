@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// VolunteerBadge is a badge a volunteer has earned
+type VolunteerBadge struct {
+	Badge       string    `json:"badge"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	AwardedAt   time.Time `json:"awardedAt"`
+}
+
+// HourMilestone is a logged-hours threshold a volunteer has reached
+type HourMilestone struct {
+	Hours     int       `json:"hours"`
+	AwardedAt time.Time `json:"awardedAt"`
+}
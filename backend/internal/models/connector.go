@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// ExternalProject is a project listed on another volunteer platform, as
+// mapped from that platform's payload by a connector
+type ExternalProject struct {
+	// ExternalID identifies the project on its platform; repeated deliveries
+	// with the same ID update the same project
+	ExternalID    string
+	URL           *string
+	Name          string
+	Description   string
+	Latitude      *float64
+	Longitude     *float64
+	LocationName  *string
+	IsRemote      bool
+	Timezone      string
+	StartDate     *time.Time
+	EndDate       *time.Time
+	MaxVolunteers *int
+	// Closed is set once the platform stops listing the project, which
+	// retires it here
+	Closed bool
+}
+
+// ConnectorImport summarizes one webhook delivery from an external platform
+type ConnectorImport struct {
+	Source   string                  `json:"source"`
+	Created  int                     `json:"created"`
+	Updated  int                     `json:"updated"`
+	Projects []ConnectorImportResult `json:"projects"`
+}
+
+// ConnectorImportResult is the project an external project was imported as
+type ConnectorImportResult struct {
+	ExternalID string `json:"externalId"`
+	ProjectID  string `json:"projectId"`
+	Created    bool   `json:"created"`
+	Status     string `json:"status"`
+}
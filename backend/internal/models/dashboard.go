@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// CoordinatorDashboard gathers what a coordinator's landing page shows in
+// one response
+type CoordinatorDashboard struct {
+	CoordinatorID   string                   `json:"coordinatorId"`
+	Totals          DashboardTotals          `json:"totals"`
+	Projects        []DashboardProject       `json:"projects"`
+	PendingRequests []DashboardRequest       `json:"pendingRequests"`
+	UpcomingStarts  []DashboardUpcomingStart `json:"upcomingStarts"`
+}
+
+type DashboardTotals struct {
+	Projects        int `json:"projects"`
+	ActiveProjects  int `json:"activeProjects"`
+	Enrolled        int `json:"enrolled"`
+	PendingRequests int `json:"pendingRequests"`
+}
+
+// DashboardProject summarizes one project. UnfilledSkills lists required
+// skills no enrolled volunteer has.
+type DashboardProject struct {
+	ProjectID          string     `json:"projectId"`
+	Name               string     `json:"name"`
+	Status             string     `json:"status"`
+	Timezone           string     `json:"timezone"`
+	StartDate          *time.Time `json:"startDate,omitempty"`
+	EndDate            *time.Time `json:"endDate,omitempty"`
+	MaxVolunteers      *int       `json:"maxVolunteers,omitempty"`
+	Enrolled           int        `json:"enrolled"`
+	PendingRequests    int        `json:"pendingRequests"`
+	PendingInvitations int        `json:"pendingInvitations"`
+	UnfilledSkills     []string   `json:"unfilledSkills"`
+}
+
+// DashboardRequest is a volunteer's request awaiting the coordinator
+type DashboardRequest struct {
+	EnrollmentID  string    `json:"enrollmentId"`
+	ProjectID     string    `json:"projectId"`
+	ProjectName   string    `json:"projectName"`
+	VolunteerID   string    `json:"volunteerId"`
+	VolunteerName string    `json:"volunteerName"`
+	Message       *string   `json:"message,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type DashboardUpcomingStart struct {
+	ProjectID string    `json:"projectId"`
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"startDate"`
+}
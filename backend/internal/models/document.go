@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Kinds of document
+const (
+	DocumentCertification = "certification" // A volunteer's certification, e.g. first aid
+	DocumentWaiver        = "waiver"        // A signed waiver attached to an enrollment
+)
+
+// Document is a private file belonging to a volunteer. URL is a short-lived
+// signed link, filled in when a single document that passed the malware
+// scan is fetched.
+type Document struct {
+	ID           string     `json:"id"`
+	OwnerID      string     `json:"ownerId"`
+	Kind         string     `json:"kind"`
+	EnrollmentID *string    `json:"enrollmentId,omitempty"`
+	Title        string     `json:"title"`
+	FileName     string     `json:"fileName"`
+	ContentType  string     `json:"contentType"`
+	SizeBytes    int        `json:"sizeBytes"`
+	UploadedBy   *string    `json:"uploadedBy,omitempty"`
+	ExpiresOn    *string    `json:"expiresOn,omitempty"` // YYYY-MM-DD
+	RetainUntil  *time.Time `json:"retainUntil,omitempty"`
+	ScanStatus   string     `json:"scanStatus"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	URL          string     `json:"url,omitempty"`
+	URLExpiresAt *time.Time `json:"urlExpiresAt,omitempty"`
+}
+
+// UploadDocumentRequest describes a document being uploaded; the file
+// itself comes in the multipart body
+type UploadDocumentRequest struct {
+	Title     string
+	FileName  string
+	ExpiresOn string // YYYY-MM-DD, certifications only
+}
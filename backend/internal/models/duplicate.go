@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+const (
+	DuplicatePending   = "pending"
+	DuplicateDismissed = "dismissed"
+)
+
+// Reasons a pair of accounts is flagged as duplicates
+const (
+	DuplicateReasonEmail        = "email"
+	DuplicateReasonNameLocation = "name_location"
+)
+
+// DuplicateAccount summarizes one account of a duplicate candidate, so
+// admins can tell which to keep
+type DuplicateAccount struct {
+	ID           string    `json:"id"`
+	Email        string    `json:"email"`
+	Name         string    `json:"name"`
+	Role         string    `json:"role"`
+	LocationName *string   `json:"locationName,omitempty"`
+	Skills       int       `json:"skills"`
+	Enrollments  int       `json:"enrollments"`
+	Hours        float64   `json:"hours"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// DuplicateCandidate is a pair of accounts that look like the same person.
+// User is the older account.
+type DuplicateCandidate struct {
+	ID         string           `json:"id"`
+	User       DuplicateAccount `json:"user"`
+	Duplicate  DuplicateAccount `json:"duplicate"`
+	Reasons    []string         `json:"reasons"` // "email", "name_location"
+	Status     string           `json:"status"`  // "pending", "dismissed"
+	DetectedAt time.Time        `json:"detectedAt"`
+}
+
+type MergeAccountsRequest struct {
+	DuplicateID string `json:"duplicateId" validate:"required"`
+}
+
+// AccountMerge reports what a merge moved from the duplicate account, which
+// it then deleted, to the kept one
+type AccountMerge struct {
+	UserID         string `json:"userId"`
+	DuplicateID    string `json:"duplicateId"`
+	DuplicateEmail string `json:"duplicateEmail"`
+	Skills         int64  `json:"skills"`
+	Enrollments    int64  `json:"enrollments"`
+	Hours          int64  `json:"hours"`
+}
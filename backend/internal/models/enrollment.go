@@ -6,7 +6,7 @@ type Enrollment struct {
 	ID              string     `json:"id"`
 	VolunteerID     string     `json:"volunteerId"`
 	ProjectID       string     `json:"projectId"`
-	Status          string     `json:"status"` // "requested", "invited", "enrolled", "tl_rejected", "v_rejected"
+	Status          string     `json:"status"` // "requested", "invited", "enrolled", "waitlisted", "completed", "cancelled", "no_show", "tl_rejected", "v_rejected", "expired"
 	InitiatedBy     string     `json:"initiatedBy"`
 	Message         *string    `json:"message,omitempty"`
 	ResponseMessage *string    `json:"responseMessage,omitempty"`
@@ -14,6 +14,21 @@ type Enrollment struct {
 	UpdatedAt       time.Time  `json:"updatedAt"`
 	ApprovedAt      *time.Time `json:"approvedAt,omitempty"`
 	CompletedAt     *time.Time `json:"completedAt,omitempty"`
+	WaitlistedAt    *time.Time `json:"waitlistedAt,omitempty"` // Set while waitlisted
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`    // Set while invited
+	// Conflicts lists the volunteer's other active enrollments whose project
+	// dates overlap this one; only set when the enrollment is created
+	Conflicts []EnrollmentConflict `json:"conflicts,omitempty"`
+}
+
+// EnrollmentConflict is an active enrollment on another project whose dates
+// overlap the project being joined
+type EnrollmentConflict struct {
+	EnrollmentID string     `json:"enrollmentId"`
+	ProjectID    string     `json:"projectId"`
+	ProjectName  string     `json:"projectName"`
+	StartDate    *time.Time `json:"startDate,omitempty"`
+	EndDate      *time.Time `json:"endDate,omitempty"`
 }
 
 type EnrollmentWithDetails struct {
@@ -24,14 +39,89 @@ type EnrollmentWithDetails struct {
 	InitiatedByName string `json:"initiatedByName"`
 }
 
+// WaitlistEntry is a waitlisted enrollment; Position 1 is enrolled next
+type WaitlistEntry struct {
+	EnrollmentWithDetails
+	Position int `json:"position"`
+}
+
 type CreateEnrollmentRequest struct {
-	ProjectID   string  `json:"projectId"`
-	Action      string  `json:"action"`                // "request" (volunteer) or "invite" (TL)
-	VolunteerID *string `json:"volunteerId,omitempty"` // required for "invite" action
-	Message     *string `json:"message,omitempty"`
+	ProjectID   string  `json:"projectId" validate:"required"`
+	Action      string  `json:"action" validate:"required,oneof=request invite"` // "request" (volunteer) or "invite" (TL)
+	VolunteerID *string `json:"volunteerId,omitempty"`                           // required for "invite" action
+	Message     *string `json:"message,omitempty" validate:"max=2000"`
 }
 
 type UpdateEnrollmentRequest struct {
-	Action          string  `json:"action"` // "accept", "reject" or "withdraw"
-	ResponseMessage *string `json:"responseMessage,omitempty"`
+	Action          string  `json:"action" validate:"required,oneof=accept reject withdraw cancel complete no_show"`
+	ResponseMessage *string `json:"responseMessage,omitempty" validate:"max=2000"`
+}
+
+// BulkUpdateEnrollmentsRequest answers many enrollments with the same
+// action and response message
+type BulkUpdateEnrollmentsRequest struct {
+	EnrollmentIDs   []string `json:"enrollmentIds" validate:"required,max=100"`
+	Action          string   `json:"action" validate:"required,oneof=accept reject"`
+	ResponseMessage *string  `json:"responseMessage,omitempty" validate:"max=2000"`
+}
+
+// BulkEnrollmentReport is the outcome of a bulk action, with a result per
+// enrollment in the order they were given
+type BulkEnrollmentReport struct {
+	Action    string                 `json:"action"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []BulkEnrollmentResult `json:"results"`
+}
+
+// BulkEnrollmentResult is the enrollment's new Status, or the Error it was
+// left unchanged for along with any conflicts or unsigned waivers behind it
+type BulkEnrollmentResult struct {
+	EnrollmentID string               `json:"enrollmentId"`
+	Status       string               `json:"status,omitempty"`
+	Error        string               `json:"error,omitempty"`
+	Conflicts    []EnrollmentConflict `json:"conflicts,omitempty"`
+	Waivers      []WaiverTemplate     `json:"waivers,omitempty"`
+}
+
+type HoursEntry struct {
+	ID           string    `json:"id"`
+	EnrollmentID string    `json:"enrollmentId"`
+	VolunteerID  string    `json:"volunteerId"`
+	ProjectID    string    `json:"projectId"`
+	Hours        float64   `json:"hours"`
+	WorkedOn     string    `json:"workedOn"` // YYYY-MM-DD
+	Note         *string   `json:"note,omitempty"`
+	LoggedBy     *string   `json:"loggedBy,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type LogHoursRequest struct {
+	Hours    float64 `json:"hours" validate:"gt=0,max=24"`
+	WorkedOn string  `json:"workedOn" validate:"required,date"` // YYYY-MM-DD
+	Note     *string `json:"note,omitempty" validate:"max=1000"`
+}
+
+// Attendance is a check-in against an enrollment. Checking out logs the
+// time in between as an hours entry.
+type Attendance struct {
+	ID           string     `json:"id"`
+	EnrollmentID string     `json:"enrollmentId"`
+	VolunteerID  string     `json:"volunteerId"`
+	ProjectID    string     `json:"projectId"`
+	ShiftID      *string    `json:"shiftId,omitempty"`
+	CheckedInAt  time.Time  `json:"checkedInAt"`
+	CheckedInBy  *string    `json:"checkedInBy,omitempty"`
+	CheckedOutAt *time.Time `json:"checkedOutAt,omitempty"`
+	CheckedOutBy *string    `json:"checkedOutBy,omitempty"`
+	HoursID      *string    `json:"hoursId,omitempty"`
+	Hours        *float64   `json:"hours,omitempty"` // Set once checked out
+}
+
+type CheckInRequest struct {
+	ShiftID *string `json:"shiftId,omitempty"` // a shift of the project the volunteer is booked onto
+}
+
+type CheckOutRequest struct {
+	Note *string `json:"note,omitempty" validate:"max=1000"` // kept on the hours entry
 }
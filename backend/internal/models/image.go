@@ -0,0 +1,25 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ImageVariants maps a standard size name, such as "small", to the URL of
+// the image resized to it. It is empty until the variants are generated.
+type ImageVariants map[string]string
+
+// Scan reads variants stored as JSON, leaving them empty for NULL
+func (v *ImageVariants) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(src, v)
+	case string:
+		return json.Unmarshal([]byte(src), v)
+	default:
+		return fmt.Errorf("cannot scan %T into ImageVariants", src)
+	}
+}
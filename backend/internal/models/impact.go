@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// ImpactMetric is a KPI a coordinator tracks for a project. Total and
+// EntryCount cover every recorded entry.
+type ImpactMetric struct {
+	ID             string    `json:"id"`
+	ProjectID      string    `json:"projectId"`
+	Name           string    `json:"name"`
+	Unit           string    `json:"unit"`
+	Description    *string   `json:"description,omitempty"`
+	CreatedBy      *string   `json:"createdBy,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+	Total          float64   `json:"total"`
+	EntryCount     int       `json:"entryCount"`
+	LastRecordedOn *string   `json:"lastRecordedOn,omitempty"` // YYYY-MM-DD
+}
+
+type CreateImpactMetricRequest struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	Unit        string  `json:"unit" validate:"required,max=50"`
+	Description *string `json:"description,omitempty"`
+}
+
+type ImpactEntry struct {
+	ID         string    `json:"id"`
+	MetricID   string    `json:"metricId"`
+	Value      float64   `json:"value"`
+	RecordedOn string    `json:"recordedOn"` // YYYY-MM-DD
+	Note       *string   `json:"note,omitempty"`
+	RecordedBy *string   `json:"recordedBy,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type LogImpactEntryRequest struct {
+	Value      float64 `json:"value" validate:"min=0"`
+	RecordedOn string  `json:"recordedOn" validate:"required,date"` // YYYY-MM-DD
+	Note       *string `json:"note,omitempty"`
+}
+
+// OrganizationImpactReport totals impact across an organization's projects
+// for the inclusive From-To range. Metrics with the same name and unit are
+// combined.
+type OrganizationImpactReport struct {
+	OrganizationID string        `json:"organizationId"`
+	From           string        `json:"from"`
+	To             string        `json:"to"`
+	Metrics        []ImpactTotal `json:"metrics"`
+}
+
+type ImpactTotal struct {
+	Name     string  `json:"name"`
+	Unit     string  `json:"unit"`
+	Total    float64 `json:"total"`
+	Projects int     `json:"projects"` // projects tracking the metric
+}
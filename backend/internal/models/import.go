@@ -0,0 +1,25 @@
+package models
+
+// VolunteerImportReport is the outcome of a CSV volunteer import. Imports
+// are all or nothing: when any row is invalid, no volunteer is created and
+// the report lists every row's errors.
+type VolunteerImportReport struct {
+	DryRun   bool                 `json:"dryRun"`
+	Imported bool                 `json:"imported"`
+	Total    int                  `json:"total"`
+	Valid    int                  `json:"valid"`
+	Invalid  int                  `json:"invalid"`
+	Rows     []VolunteerImportRow `json:"rows"`
+}
+
+// VolunteerImportRow reports on one CSV row; Row is its line in the file,
+// counting the header as line 1
+type VolunteerImportRow struct {
+	Row    int      `json:"row"`
+	Email  string   `json:"email"`
+	Name   string   `json:"name"`
+	Skills []string `json:"skills"`
+	// UserID is set once the volunteer was created
+	UserID string   `json:"userId,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
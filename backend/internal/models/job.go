@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Background job states
+const (
+	JobQueued    = "queued"    // Waiting to run, possibly after a failed attempt
+	JobRunning   = "running"   // Claimed by a worker
+	JobSucceeded = "succeeded" // Finished
+	JobFailed    = "failed"    // Gave up after its last attempt
+)
+
+// Job is a unit of background work. Payloads may hold personal data such as
+// email bodies, so they are not shown.
+type Job struct {
+	ID          string     `json:"id"`
+	Kind        string     `json:"kind"`
+	Payload     []byte     `json:"-"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"maxAttempts"`
+	RunAt       time.Time  `json:"runAt"`
+	LastError   *string    `json:"lastError,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+}
+
+// JobKindStatus sums up the jobs of one kind
+type JobKindStatus struct {
+	Kind    string `json:"kind"`
+	Queued  int    `json:"queued"`
+	Running int    `json:"running"`
+	Failed  int    `json:"failed"`
+	// OldestQueuedAt is when the longest-waiting queued job became due
+	OldestQueuedAt  *time.Time `json:"oldestQueuedAt,omitempty"`
+	LastSucceededAt *time.Time `json:"lastSucceededAt,omitempty"`
+}
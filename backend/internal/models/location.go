@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// VolunteerLocation is one of a volunteer's saved places, e.g. home or work.
+// Matching measures from whichever is nearest the project.
+type VolunteerLocation struct {
+	ID           string    `json:"id"`
+	VolunteerID  string    `json:"volunteerId"`
+	Label        string    `json:"label"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	LocationName *string   `json:"locationName,omitempty"`
+	IsPrimary    bool      `json:"isPrimary"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+type SaveLocationRequest struct {
+	Label        string  `json:"label" validate:"required,max=50"`
+	Latitude     float64 `json:"latitude" validate:"lat"`
+	Longitude    float64 `json:"longitude" validate:"lon"`
+	LocationName *string `json:"locationName,omitempty" validate:"max=255"`
+	IsPrimary    bool    `json:"isPrimary"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// Content that can be reported
+const (
+	ReportTargetProject = "project" // A project's name and description
+	ReportTargetMessage = "message" // A team broadcast
+	ReportTargetProfile = "profile" // A user's profile
+)
+
+// ContentReport is a user flagging content as inappropriate. Status is open
+// until a moderator dismisses it or acts on it.
+type ContentReport struct {
+	ID         string  `json:"id"`
+	ReporterID string  `json:"reporterId"`
+	TargetType string  `json:"targetType"`
+	TargetID   string  `json:"targetId"`
+	Category   string  `json:"category"`
+	Details    *string `json:"details,omitempty"`
+	Status     string  `json:"status"`
+	// Resolution is the action taken when the report was closed
+	Resolution     *string    `json:"resolution,omitempty"`
+	ResolutionNote *string    `json:"resolutionNote,omitempty"`
+	ResolvedBy     *string    `json:"resolvedBy,omitempty"`
+	ResolvedAt     *time.Time `json:"resolvedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+type CreateReportRequest struct {
+	TargetType string  `json:"targetType" validate:"required,oneof=project message profile"`
+	TargetID   string  `json:"targetId" validate:"required"`
+	Category   string  `json:"category" validate:"required,oneof=spam harassment hate violence sexual misinformation other"`
+	Details    *string `json:"details,omitempty" validate:"max=2000"`
+}
+
+// Actions a moderator can take on a report
+const (
+	ModerationDismiss = "dismiss" // No action; the report is closed
+	ModerationHide    = "hide"    // Hide the reported content
+	ModerationSuspend = "suspend" // Suspend the content's owner
+)
+
+// ReportedContent is the content a report points at, with enough context
+// for a moderator to judge it. Deleted is set when the content no longer
+// exists.
+type ReportedContent struct {
+	Type           string            `json:"type"`
+	ID             string            `json:"id"`
+	OwnerID        *string           `json:"ownerId,omitempty"`
+	OwnerName      *string           `json:"ownerName,omitempty"`
+	Title          string            `json:"title"`
+	Body           string            `json:"body"`
+	Context        map[string]string `json:"context"`
+	Hidden         bool              `json:"hidden"`
+	OwnerSuspended bool              `json:"ownerSuspended"`
+	Deleted        bool              `json:"deleted"`
+}
+
+// ReportDetail is a report with the reported content and every other report
+// filed against the same content
+type ReportDetail struct {
+	ContentReport
+	Content      ReportedContent `json:"content"`
+	OtherReports []ContentReport `json:"otherReports"`
+}
+
+type ResolveReportRequest struct {
+	Action string  `json:"action" validate:"required,oneof=dismiss hide suspend"`
+	Note   *string `json:"note,omitempty" validate:"max=2000"`
+}
+
+// ReportResolution is a moderator's action on a report, with every open
+// report on the same content that it closed
+type ReportResolution struct {
+	Action  string          `json:"action"`
+	Content ReportedContent `json:"content"`
+	Reports []ContentReport `json:"reports"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Notification tells a user about something that happened to their
+// enrollments or projects
+type Notification struct {
+	ID           string     `json:"id"`
+	Kind         string     `json:"kind"` // "enrollment_requested", "enrollment_invited", "enrollment_accepted", "enrollment_waitlisted", "enrollment_rejected", "project_status_changed", "project_match"
+	Title        string     `json:"title"`
+	Body         string     `json:"body"`
+	ProjectID    *string    `json:"projectId,omitempty"`
+	EnrollmentID *string    `json:"enrollmentId,omitempty"`
+	ReadAt       *time.Time `json:"readAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,213 @@
+package models
+
+import "time"
+
+// Organization roles, from most to least privileged
+const (
+	OrgRoleOwner       = "owner"
+	OrgRoleAdmin       = "admin"
+	OrgRoleCoordinator = "coordinator"
+	OrgRoleMember      = "member"
+)
+
+// Organization verification statuses
+const (
+	OrgVerificationUnverified = "unverified"
+	OrgVerificationPending    = "pending"
+	OrgVerificationVerified   = "verified"
+)
+
+type Organization struct {
+	ID                 string     `json:"id"`
+	Name               string     `json:"name"`
+	Slug               string     `json:"slug"`
+	Description        string     `json:"description"`
+	VerificationStatus string     `json:"verificationStatus"` // "unverified", "pending", "verified"
+	VerificationNote   *string    `json:"verificationNote,omitempty"`
+	VerifiedAt         *time.Time `json:"verifiedAt,omitempty"`
+	CreatedBy          *string    `json:"createdBy,omitempty"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+}
+
+type OrganizationMember struct {
+	OrganizationID string     `json:"organizationId"`
+	UserID         string     `json:"userId"`
+	UserName       string     `json:"userName"`
+	UserEmail      string     `json:"userEmail"`
+	Role           string     `json:"role"`   // "owner", "admin", "coordinator", "member"
+	Status         string     `json:"status"` // "invited", "active"
+	InvitedBy      *string    `json:"invitedBy,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	AcceptedAt     *time.Time `json:"acceptedAt,omitempty"`
+}
+
+type CreateOrganizationRequest struct {
+	Name        string `json:"name" validate:"required,max=255"`
+	Slug        string `json:"slug" validate:"required,max=100"`
+	Description string `json:"description"`
+}
+
+type InviteMemberRequest struct {
+	UserID string `json:"userId" validate:"required"`
+	Role   string `json:"role" validate:"oneof=owner admin coordinator member"`
+}
+
+// OrganizationSettings are typed per-organization settings. Organizations
+// that never saved settings get DefaultOrganizationSettings.
+type OrganizationSettings struct {
+	OrganizationID string             `json:"organizationId"`
+	Matching       MatchingDefaults   `json:"matching"`
+	Enrollment     EnrollmentPolicies `json:"enrollment"`
+	Branding       Branding           `json:"branding"`
+	UpdatedAt      *time.Time         `json:"updatedAt,omitempty"`
+}
+
+type MatchingDefaults struct {
+	SkillWeight    float64 `json:"skillWeight" validate:"min=0,max=1"`
+	DistanceWeight float64 `json:"distanceWeight" validate:"min=0,max=1"`
+	MaxDistanceKm  float64 `json:"maxDistanceKm" validate:"gt=0"`
+	ShowRatings    bool    `json:"showRatings"` // Show volunteer rating scores to coordinators in matches
+}
+
+type EnrollmentPolicies struct {
+	AllowVolunteerRequests bool `json:"allowVolunteerRequests"`
+	RequireRequestMessage  bool `json:"requireRequestMessage"`
+	AllowSelfSignup        bool `json:"allowSelfSignup"` // Volunteers registering through the tenant join the organization
+}
+
+type Branding struct {
+	EmailFooter    string `json:"emailFooter" validate:"max=2000"`
+	PrimaryColor   string `json:"primaryColor" validate:"hexcolor"`
+	SecondaryColor string `json:"secondaryColor" validate:"hexcolor"`
+	LogoURL        string `json:"logoUrl" validate:"url"`
+}
+
+// DefaultOrganizationSettings mirrors the column defaults of organization_settings
+func DefaultOrganizationSettings(orgID string) OrganizationSettings {
+	return OrganizationSettings{
+		OrganizationID: orgID,
+		Matching: MatchingDefaults{
+			SkillWeight:    0.7,
+			DistanceWeight: 0.3,
+			MaxDistanceKm:  100,
+		},
+		Enrollment: EnrollmentPolicies{
+			AllowVolunteerRequests: true,
+		},
+	}
+}
+
+type UpdateOrganizationSettingsRequest struct {
+	Matching   MatchingDefaults   `json:"matching"`
+	Enrollment EnrollmentPolicies `json:"enrollment"`
+	Branding   Branding           `json:"branding"`
+}
+
+type OrganizationInvitation struct {
+	ID             string     `json:"id"`
+	OrganizationID string     `json:"organizationId"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	Status         string     `json:"status"` // "pending", "accepted", "revoked"
+	InvitedBy      *string    `json:"invitedBy,omitempty"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	AcceptedAt     *time.Time `json:"acceptedAt,omitempty"`
+}
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,email,max=254"`
+	Role  string `json:"role" validate:"oneof=owner admin coordinator member"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+	Name  string `json:"name" validate:"max=255"` // required when no account exists for the invited email
+}
+
+type AcceptInvitationResponse struct {
+	User       User               `json:"user"`
+	Membership OrganizationMember `json:"membership"`
+}
+
+// API key scopes
+const (
+	APIKeyScopeProjectsRead     = "projects:read"
+	APIKeyScopeProjectsWrite    = "projects:write"
+	APIKeyScopeEnrollmentsWrite = "enrollments:write"
+	APIKeyScopeVolunteersWrite  = "volunteers:write"
+)
+
+// APIKey is an organization-scoped key for partner integrations. The key
+// itself is only returned once, in CreateAPIKeyResponse.
+type APIKey struct {
+	ID             string   `json:"id"`
+	OrganizationID string   `json:"organizationId"`
+	Name           string   `json:"name"`
+	KeyPrefix      string   `json:"keyPrefix"`
+	Scopes         []string `json:"scopes"`
+	// DailyQuota and MonthlyQuota override the configured request quotas
+	// for API keys when set
+	DailyQuota   *int       `json:"dailyQuota,omitempty"`
+	MonthlyQuota *int       `json:"monthlyQuota,omitempty"`
+	CreatedBy    *string    `json:"createdBy,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	LastUsedAt   *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt    *time.Time `json:"revokedAt,omitempty"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name         string   `json:"name" validate:"required,max=100"`
+	Scopes       []string `json:"scopes" validate:"required"`
+	DailyQuota   *int     `json:"dailyQuota,omitempty" validate:"min=1"`
+	MonthlyQuota *int     `json:"monthlyQuota,omitempty" validate:"min=1"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// VolunteerSharingAgreement lets the recipient organization see the owner
+// organization's volunteers in match results
+type VolunteerSharingAgreement struct {
+	OwnerOrganizationID       string    `json:"ownerOrganizationId"`
+	OwnerOrganizationName     string    `json:"ownerOrganizationName"`
+	RecipientOrganizationID   string    `json:"recipientOrganizationId"`
+	RecipientOrganizationName string    `json:"recipientOrganizationName"`
+	GrantedBy                 *string   `json:"grantedBy,omitempty"`
+	CreatedAt                 time.Time `json:"createdAt"`
+}
+
+// VolunteerSharing lists an organization's active agreements in both directions
+type VolunteerSharing struct {
+	SharedWith []VolunteerSharingAgreement `json:"sharedWith"` // organizations seeing our volunteers
+	SharedBy   []VolunteerSharingAgreement `json:"sharedBy"`   // organizations whose volunteers we see
+}
+
+type ShareVolunteersRequest struct {
+	OrganizationID string `json:"organizationId" validate:"required"` // recipient organization ID or slug
+}
+
+// VerificationEvidence describes an uploaded verification document; the
+// content is only served by the download endpoint
+type VerificationEvidence struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organizationId"`
+	Filename       string    `json:"filename"`
+	ContentType    string    `json:"contentType"`
+	SizeBytes      int       `json:"sizeBytes"`
+	UploadedBy     *string   `json:"uploadedBy,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type OrganizationVerification struct {
+	Organization Organization           `json:"organization"`
+	Evidence     []VerificationEvidence `json:"evidence"`
+}
+
+type UpdateVerificationRequest struct {
+	Status string  `json:"status" validate:"required,oneof=verified unverified"` // "verified" or "unverified"
+	Note   *string `json:"note,omitempty" validate:"max=2000"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// Page is one page of a list endpoint's results. NextCursor, passed back
+// as ?cursor=, fetches the following page and is omitted on the last one.
+type Page struct {
+	Items      interface{} `json:"items"`
+	Total      int         `json:"total"`
+	Limit      int         `json:"limit"`
+	Offset     int         `json:"offset"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// ProjectPhoto is a photo in a project's gallery, with a generated thumbnail
+// and display sizes
+type ProjectPhoto struct {
+	ID           string  `json:"id"`
+	ProjectID    string  `json:"projectId"`
+	UploadedBy   *string `json:"uploadedBy,omitempty"`
+	URL          string  `json:"url"`
+	ThumbnailURL string  `json:"thumbnailUrl"`
+	// Variants are the photo's larger standard sizes, once generated
+	Variants ImageVariants `json:"variants,omitempty"`
+	Width    int           `json:"width"`
+	Height   int           `json:"height"`
+	Caption  *string       `json:"caption,omitempty"`
+	Position int           `json:"position"`
+	// ScanStatus is pending until the photo passes the malware scan
+	ScanStatus string    `json:"scanStatus"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type UpdatePhotoRequest struct {
+	Caption *string `json:"caption" validate:"max=500"`
+}
+
+// ReorderPhotosRequest lists every photo of the gallery in its new order
+type ReorderPhotosRequest struct {
+	PhotoIDs []string `json:"photoIds" validate:"required"`
+}
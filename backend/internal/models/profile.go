@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// VolunteerReference is a short reference a coordinator wrote for a volunteer
+// they worked with on a project. Status is pending (awaiting the volunteer),
+// approved (shown on the public profile), declined or removed by a moderator.
+type VolunteerReference struct {
+	ID          string     `json:"id"`
+	VolunteerID string     `json:"volunteerId"`
+	AuthorID    string     `json:"authorId"`
+	AuthorName  string     `json:"authorName"`
+	ProjectID   string     `json:"projectId"`
+	ProjectName string     `json:"projectName"`
+	Body        string     `json:"body"`
+	Status      string     `json:"status"`
+	RespondedAt *time.Time `json:"respondedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+type CreateReferenceRequest struct {
+	Body string `json:"body" validate:"required,max=1000"`
+}
+
+// RespondToReferenceRequest approves or declines a reference
+type RespondToReferenceRequest struct {
+	Action string `json:"action" validate:"required,oneof=approve decline"` // approve or decline
+}
+
+// ProfileSkill is a skill a volunteer claims on their public profile
+type ProfileSkill struct {
+	SkillID  string `json:"skillId"`
+	Name     string `json:"name"`
+	Verified bool   `json:"verified"`
+}
+
+// VolunteerProfile is what anyone can see about a volunteer: no contact or
+// location details
+type VolunteerProfile struct {
+	ID             string               `json:"id"`
+	Name           string               `json:"name"`
+	AvatarURL      *string              `json:"avatarUrl,omitempty"`
+	AvatarVariants ImageVariants        `json:"avatarVariants,omitempty"`
+	MemberSince    time.Time            `json:"memberSince"`
+	Skills         []ProfileSkill       `json:"skills"`
+	Badges         []VolunteerBadge     `json:"badges"`
+	Milestones     []HourMilestone      `json:"milestones"`
+	References     []VolunteerReference `json:"references"`
+}
+
+// ProfilePrivacy is which fields a volunteer shows on their public profile.
+// Every field is hidden until the volunteer opts in.
+type ProfilePrivacy struct {
+	Name            bool `json:"name"`
+	Badges          bool `json:"badges"`
+	SkillCategories bool `json:"skillCategories"`
+	TotalHours      bool `json:"totalHours"`
+}
+
+// PublicProfile is a volunteer's public profile, carrying only the fields
+// they opted into
+type PublicProfile struct {
+	ID              string           `json:"id"`
+	Name            *string          `json:"name,omitempty"`
+	Badges          []VolunteerBadge `json:"badges,omitempty"`
+	SkillCategories []string         `json:"skillCategories,omitempty"`
+	TotalHours      *float64         `json:"totalHours,omitempty"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// VolunteerRating is a coordinator's rating of a volunteer for a completed
+// enrollment
+type VolunteerRating struct {
+	EnrollmentID  string    `json:"enrollmentId"`
+	VolunteerID   string    `json:"volunteerId"`
+	VolunteerName string    `json:"volunteerName"`
+	ProjectID     string    `json:"projectId"`
+	Rating        int       `json:"rating"` // 1-5
+	Tags          []string  `json:"tags"`
+	RatedBy       *string   `json:"ratedBy,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+type RateVolunteerRequest struct {
+	Rating int      `json:"rating" validate:"min=1,max=5"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+// RatingScore is a volunteer's ratings averaged with older ratings counting
+// less
+type RatingScore struct {
+	Score float64 `json:"score"`
+	Count int     `json:"count"`
+}
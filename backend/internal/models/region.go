@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Region kinds
+const (
+	RegionKindCity   = "city"
+	RegionKindWard   = "ward"
+	RegionKindRegion = "region"
+)
+
+// Region is an administrative area; projects and volunteers are tagged with
+// the regions their coordinates fall in
+type Region struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	ParentID  *string   `json:"parentId,omitempty"`
+	CreatedBy *string   `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateRegionRequest struct {
+	Name     string  `json:"name" validate:"required"`
+	Kind     string  `json:"kind" validate:"required,oneof=city ward region"`
+	ParentID *string `json:"parentId,omitempty"`
+	// Boundary is a GeoJSON Polygon or MultiPolygon geometry
+	Boundary json.RawMessage `json:"boundary" validate:"required"`
+}
+
+// RegionAnalytics summarizes volunteer supply and project demand in a region
+type RegionAnalytics struct {
+	RegionID           string  `json:"regionId"`
+	Name               string  `json:"name"`
+	Kind               string  `json:"kind"`
+	Volunteers         int     `json:"volunteers"`
+	Projects           int     `json:"projects"`
+	ActiveProjects     int     `json:"activeProjects"`
+	EnrolledVolunteers int     `json:"enrolledVolunteers"`
+	LoggedHours        float64 `json:"loggedHours"`
+}
@@ -0,0 +1,43 @@
+package models
+
+// OrganizationSummaryReport aggregates activity across an organization's
+// projects. From and To are the inclusive YYYY-MM-DD range the report covers.
+type OrganizationSummaryReport struct {
+	OrganizationID     string                 `json:"organizationId"`
+	From               string                 `json:"from"`
+	To                 string                 `json:"to"`
+	ActiveProjects     int                    `json:"activeProjects"`
+	EnrolledVolunteers int                    `json:"enrolledVolunteers"` // distinct volunteers enrolled by the end of the range
+	NewEnrollments     int                    `json:"newEnrollments"`     // enrollments approved within the range
+	LoggedHours        float64                `json:"loggedHours"`
+	FillRate           *float64               `json:"fillRate,omitempty"` // enrolled / capacity over projects with a max_volunteers cap
+	Projects           []ProjectSummaryReport `json:"projects"`
+}
+
+type ProjectSummaryReport struct {
+	ProjectID     string   `json:"projectId"`
+	ProjectName   string   `json:"projectName"`
+	Status        string   `json:"status"`
+	Enrolled      int      `json:"enrolled"`
+	MaxVolunteers *int     `json:"maxVolunteers,omitempty"`
+	FillRate      *float64 `json:"fillRate,omitempty"`
+	LoggedHours   float64  `json:"loggedHours"`
+}
+
+// HoursReport totals hours worked within an inclusive YYYY-MM-DD range, one
+// row per volunteer and project, for grant reporting
+type HoursReport struct {
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	TotalHours float64          `json:"totalHours"`
+	Rows       []HoursReportRow `json:"rows"`
+}
+
+type HoursReportRow struct {
+	VolunteerID   string  `json:"volunteerId"`
+	VolunteerName string  `json:"volunteerName"`
+	ProjectID     string  `json:"projectId"`
+	ProjectName   string  `json:"projectName"`
+	Entries       int     `json:"entries"`
+	Hours         float64 `json:"hours"`
+}
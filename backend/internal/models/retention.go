@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// RetentionRule is a retention policy: records older than After are
+// anonymized or deleted
+type RetentionRule struct {
+	Name        string `json:"name"`
+	Action      string `json:"action"` // "anonymize", "delete"
+	Description string `json:"description"`
+	After       string `json:"after"` // e.g. "8760h0m0s"
+}
+
+type RetentionPolicy struct {
+	// DryRun is whether scheduled runs only report what they would purge
+	DryRun bool            `json:"dryRun"`
+	Rules  []RetentionRule `json:"rules"`
+}
+
+// RetentionResult is how many records a rule purged in a run, or would
+// have in a dry run
+type RetentionResult struct {
+	Rule   string    `json:"rule"`
+	Action string    `json:"action"`
+	Cutoff time.Time `json:"cutoff"`
+	Count  int64     `json:"count"`
+}
+
+type RetentionRun struct {
+	ID         string            `json:"id"`
+	DryRun     bool              `json:"dryRun"`
+	Results    []RetentionResult `json:"results"`
+	StartedAt  time.Time         `json:"startedAt"`
+	FinishedAt time.Time         `json:"finishedAt"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// ProjectReview is a volunteer's review of a project they completed and of
+// its hosting organization. Status is published, pending (held for
+// moderation) or removed.
+type ProjectReview struct {
+	ID                 string    `json:"id"`
+	ProjectID          string    `json:"projectId"`
+	VolunteerID        string    `json:"volunteerId,omitempty"` // Only shown to the reviewer and moderators
+	ProjectRating      int       `json:"projectRating"`
+	OrganizationRating *int      `json:"organizationRating,omitempty"`
+	Comment            *string   `json:"comment,omitempty"`
+	Status             string    `json:"status"`
+	ModerationReason   *string   `json:"moderationReason,omitempty"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+type SubmitReviewRequest struct {
+	ProjectRating      int     `json:"projectRating" validate:"min=1,max=5"`
+	OrganizationRating *int    `json:"organizationRating,omitempty" validate:"min=1,max=5"`
+	Comment            *string `json:"comment,omitempty" validate:"max=2000"`
+}
+
+// ModerateReviewRequest publishes or removes a review held for moderation
+type ModerateReviewRequest struct {
+	Action string `json:"action" validate:"required,oneof=publish remove"` // publish or remove
+}
+
+// ReviewSummary averages the ratings of a project's reviews and of all
+// reviews of its organization's projects; removed reviews don't count
+type ReviewSummary struct {
+	ProjectScore      *float64 `json:"projectScore,omitempty"`
+	ProjectCount      int      `json:"projectCount"`
+	OrganizationScore *float64 `json:"organizationScore,omitempty"`
+	OrganizationCount int      `json:"organizationCount"`
+}
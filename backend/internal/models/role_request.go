@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+const (
+	RoleRequestPending  = "pending"
+	RoleRequestApproved = "approved"
+	RoleRequestRejected = "rejected"
+)
+
+// EmailDomainRule gives users registering with an email address at Domain
+// the Role, straight away or once a platform admin approves
+type EmailDomainRule struct {
+	ID               string    `json:"id"`
+	Domain           string    `json:"domain"`
+	Role             string    `json:"role"` // "coordinator", "admin"
+	RequiresApproval bool      `json:"requiresApproval"`
+	CreatedBy        *string   `json:"createdBy,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+type CreateEmailDomainRuleRequest struct {
+	Domain string `json:"domain" validate:"required,max=253"`
+	Role   string `json:"role" validate:"required,oneof=coordinator admin"`
+	// RequiresApproval defaults to true, and must be true for admin
+	RequiresApproval *bool `json:"requiresApproval,omitempty"`
+}
+
+// RoleRequest is a role an email domain rule queued for approval when the
+// user registered
+type RoleRequest struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"userId"`
+	UserName   string     `json:"userName"`
+	UserEmail  string     `json:"userEmail"`
+	Role       string     `json:"role"`
+	RuleID     *string    `json:"ruleId,omitempty"`
+	Status     string     `json:"status"` // "pending", "approved", "rejected"
+	Note       *string    `json:"note,omitempty"`
+	ReviewedBy *string    `json:"reviewedBy,omitempty"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type ReviewRoleRequestRequest struct {
+	Status string  `json:"status" validate:"required,oneof=approved rejected"` // "approved" or "rejected"
+	Note   *string `json:"note,omitempty" validate:"max=2000"`
+}
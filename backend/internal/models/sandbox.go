@@ -0,0 +1,13 @@
+package models
+
+// SandboxSummary counts the synthetic records sandbox mode generated, or
+// that are left to purge
+type SandboxSummary struct {
+	Coordinators int `json:"coordinators"`
+	Volunteers   int `json:"volunteers"`
+	Projects     int `json:"projects"`
+	// Skills counts skills created because the catalogue was empty
+	Skills      int `json:"skills"`
+	Enrollments int `json:"enrollments"`
+	Hours       int `json:"hours"`
+}
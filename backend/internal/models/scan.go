@@ -0,0 +1,8 @@
+package models
+
+// Malware scan states of an uploaded file
+const (
+	ScanPending  = "pending"  // Not scanned yet; not shown to other users
+	ScanClean    = "clean"    // Passed the scan
+	ScanInfected = "infected" // Flagged by the scanner and quarantined
+)
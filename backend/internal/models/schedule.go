@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// ScheduledTask is recurring maintenance the scheduler queues as a job
+type ScheduledTask struct {
+	Name      string        `json:"name"`
+	Schedule  string        `json:"schedule"` // cron expression, UTC
+	Kind      string        `json:"kind"`     // kind of job queued
+	NextRunAt *time.Time    `json:"nextRunAt,omitempty"`
+	LastRun   *ScheduledRun `json:"lastRun,omitempty"`
+}
+
+// ScheduledRun is one run of a scheduled task, with the state of its job
+type ScheduledRun struct {
+	ID           string     `json:"id"`
+	Task         string     `json:"task"`
+	ScheduledFor time.Time  `json:"scheduledFor"`
+	JobID        *string    `json:"jobId,omitempty"`
+	Status       string     `json:"status"`
+	Attempts     int        `json:"attempts"`
+	LastError    *string    `json:"lastError,omitempty"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// ProjectSearchResult is a page of projects matching a search, with facet
+// counts over every match
+type ProjectSearchResult struct {
+	Total    int                 `json:"total"`
+	Projects []ProjectSearchHit  `json:"projects"`
+	Facets   ProjectSearchFacets `json:"facets"`
+}
+
+// ProjectSearchHit is a project as mirrored into the search index
+type ProjectSearchHit struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	Description    string     `json:"description"`
+	OrganizationID *string    `json:"organizationId,omitempty"`
+	LocationName   *string    `json:"locationName,omitempty"`
+	Latitude       *float64   `json:"latitude,omitempty"`
+	Longitude      *float64   `json:"longitude,omitempty"`
+	IsRemote       bool       `json:"isRemote"`
+	StartDate      *time.Time `json:"startDate,omitempty"`
+	EndDate        *time.Time `json:"endDate,omitempty"`
+	Skills         []string   `json:"skills"`
+	Categories     []string   `json:"categories"`
+	DistanceKm     *float64   `json:"distanceKm,omitempty"` // Set when searching from a point
+}
+
+// ProjectSearchFacets count the matches by skill category, by distance band
+// (only when searching from a point) and by the month projects start
+type ProjectSearchFacets struct {
+	Categories  []FacetCount `json:"categories"`
+	Distance    []FacetCount `json:"distance,omitempty"`
+	StartMonths []FacetCount `json:"startMonths"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
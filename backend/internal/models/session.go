@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Session is a sign-in on one device, kept alive by refreshing its access
+// token until it expires or is revoked
+type Session struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"userId"`
+	UserAgent  *string    `json:"userAgent,omitempty"`
+	IPAddress  *string    `json:"ipAddress,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt time.Time  `json:"lastUsedAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	// Current marks the session the request was made in
+	Current bool `json:"current"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -0,0 +1,113 @@
+package models
+
+import "time"
+
+// Shift is a time slot within a project; SignedUp counts its bookings
+type Shift struct {
+	ID        string    `json:"id"`
+	ProjectID string    `json:"projectId"`
+	Title     *string   `json:"title,omitempty"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	Capacity  int       `json:"capacity"`
+	SignedUp  int       `json:"signedUp"`
+	SkillIDs  []string  `json:"skillIds"` // Skills assigned volunteers need
+	CreatedBy *string   `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateShiftRequest struct {
+	Title    *string   `json:"title,omitempty" validate:"max=255"`
+	StartsAt time.Time `json:"startsAt" validate:"required"`
+	EndsAt   time.Time `json:"endsAt" validate:"required,after=StartsAt"`
+	Capacity int       `json:"capacity" validate:"min=1,max=1000"`
+	SkillIDs []string  `json:"skillIds,omitempty" validate:"max=20"`
+}
+
+// ShiftSignup is a volunteer booked onto a shift
+type ShiftSignup struct {
+	ShiftID        string    `json:"shiftId"`
+	VolunteerID    string    `json:"volunteerId"`
+	VolunteerName  string    `json:"volunteerName"`
+	VolunteerEmail string    `json:"volunteerEmail"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// ShiftRoster is a shift with the volunteers booked onto it
+type ShiftRoster struct {
+	Shift
+	Volunteers []ShiftSignup `json:"volunteers"`
+}
+
+// VolunteerShift is a shift a volunteer is booked onto
+type VolunteerShift struct {
+	Shift
+	ProjectName string `json:"projectName"`
+}
+
+// ShiftCoverageRequest is a booked volunteer asking someone to take their
+// place on a shift. Status is open, claimed or cancelled.
+type ShiftCoverageRequest struct {
+	ID            string     `json:"id"`
+	ShiftID       string     `json:"shiftId"`
+	ProjectID     string     `json:"projectId"`
+	ProjectName   string     `json:"projectName"`
+	ShiftTitle    *string    `json:"shiftTitle,omitempty"`
+	StartsAt      time.Time  `json:"startsAt"`
+	EndsAt        time.Time  `json:"endsAt"`
+	RequesterID   string     `json:"requesterId"`
+	RequesterName string     `json:"requesterName"`
+	Note          *string    `json:"note,omitempty"`
+	Status        string     `json:"status"`
+	ClaimedBy     *string    `json:"claimedBy,omitempty"`
+	ClaimedByName *string    `json:"claimedByName,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	ResolvedAt    *time.Time `json:"resolvedAt,omitempty"`
+}
+
+type CreateCoverageRequest struct {
+	Note *string `json:"note,omitempty" validate:"max=1000"`
+}
+
+// CoverageCandidate is an enrolled volunteer free to cover a shift
+type CoverageCandidate struct {
+	VolunteerID string
+	Name        string
+	Email       string
+	Locale      string
+}
+
+// ShiftAssignment places a volunteer on a shift
+type ShiftAssignment struct {
+	ShiftID       string    `json:"shiftId" validate:"required"`
+	VolunteerID   string    `json:"volunteerId" validate:"required"`
+	VolunteerName string    `json:"volunteerName,omitempty"`
+	StartsAt      time.Time `json:"startsAt,omitempty"`
+	EndsAt        time.Time `json:"endsAt,omitempty"`
+}
+
+// UnfilledShift is a shift with seats left after auto-assignment
+type UnfilledShift struct {
+	ShiftID   string `json:"shiftId"`
+	OpenSeats int    `json:"openSeats"`
+}
+
+// VolunteerLoad is the hours a volunteer would be booked for on a project's
+// upcoming shifts, counting proposed assignments
+type VolunteerLoad struct {
+	VolunteerID   string  `json:"volunteerId"`
+	VolunteerName string  `json:"volunteerName"`
+	Hours         float64 `json:"hours"`
+}
+
+// AssignmentProposal is an auto-assignment a coordinator can review and
+// accept; nothing is booked until they do
+type AssignmentProposal struct {
+	Assignments []ShiftAssignment `json:"assignments"`
+	Unfilled    []UnfilledShift   `json:"unfilled"`
+	Load        []VolunteerLoad   `json:"load"`
+}
+
+type AcceptAssignmentsRequest struct {
+	Assignments []ShiftAssignment `json:"assignments" validate:"required,max=500"`
+}
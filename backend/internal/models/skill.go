@@ -11,29 +11,45 @@ type Skill struct {
 }
 
 type VolunteerSkill struct {
-	VolunteerID string    `json:"volunteerId"`
-	SkillID     string    `json:"skillId"`
-	SkillName   string    `json:"skillName,omitempty"`
-	Claimed     bool      `json:"claimed"`
-	Score       float64   `json:"score"` // Proficiency score [0, 1]
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	VolunteerID string     `json:"volunteerId"`
+	SkillID     string     `json:"skillId"`
+	SkillName   string     `json:"skillName,omitempty"`
+	Claimed     bool       `json:"claimed"`
+	Score       float64    `json:"score"` // Proficiency score [0, 1]
+	VerifiedBy  *string    `json:"verifiedBy,omitempty"`
+	VerifiedAt  *time.Time `json:"verifiedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
 }
 
 type Project struct {
-	ID            string     `json:"id"`
-	Name          string     `json:"name"`
-	Description   string     `json:"description"`
-	CoordinatorID *string    `json:"coordinatorId,omitempty"`
-	Latitude      *float64   `json:"latitude,omitempty"`
-	Longitude     *float64   `json:"longitude,omitempty"`
-	LocationName  *string    `json:"locationName,omitempty"`
-	StartDate     *time.Time `json:"startDate,omitempty"`
-	EndDate       *time.Time `json:"endDate,omitempty"`
-	Status        string     `json:"status"`
-	MaxVolunteers *int       `json:"maxVolunteers,omitempty"`
-	CreatedAt     time.Time  `json:"createdAt"`
-	UpdatedAt     time.Time  `json:"updatedAt"`
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	CoordinatorID  *string  `json:"coordinatorId,omitempty"`
+	OrganizationID *string  `json:"organizationId,omitempty"`
+	Latitude       *float64 `json:"latitude,omitempty"`
+	Longitude      *float64 `json:"longitude,omitempty"`
+	LocationName   *string  `json:"locationName,omitempty"`
+	IsRemote       bool     `json:"isRemote"`
+	Timezone       string   `json:"timezone"`
+	// BlockScheduleConflicts rejects enrollments overlapping a volunteer's
+	// other commitments instead of only flagging them
+	BlockScheduleConflicts bool           `json:"blockScheduleConflicts"`
+	StartDate              *time.Time     `json:"startDate,omitempty"`
+	EndDate                *time.Time     `json:"endDate,omitempty"`
+	Status                 string         `json:"status"`
+	MaxVolunteers          *int           `json:"maxVolunteers,omitempty"`
+	Locale                 *string        `json:"locale,omitempty"`  // Set when the name or description are translated
+	Reviews                *ReviewSummary `json:"reviews,omitempty"` // Set on project listings
+	CreatedAt              time.Time      `json:"createdAt"`
+	UpdatedAt              time.Time      `json:"updatedAt"`
+}
+
+// NearbyProject is a project annotated with its distance from a search point
+type NearbyProject struct {
+	Project
+	DistanceKm float64 `json:"distanceKm"`
 }
 
 type ProjectSkill struct {
@@ -45,78 +61,159 @@ type ProjectSkill struct {
 }
 
 type VolunteerMatch struct {
-	VolunteerID   string   `json:"volunteerId"`
-	VolunteerName string   `json:"volunteerName"`
-	Email         string   `json:"email"`
-	SkillScore    float64  `json:"skillScore"`    // Cosine similarity score
-	DistanceKm    float64  `json:"distanceKm"`    // Geo distance in km
-	CombinedScore float64  `json:"combinedScore"` // Weighted combined score
-	MatchedSkills []string `json:"matchedSkills"` // List of matching skills
-	Latitude      *float64 `json:"latitude,omitempty"`
-	Longitude     *float64 `json:"longitude,omitempty"`
-	LocationName  *string  `json:"locationName,omitempty"`
+	VolunteerID         string            `json:"volunteerId"`
+	VolunteerName       string            `json:"volunteerName"`
+	Email               string            `json:"email"`
+	SkillScore          float64           `json:"skillScore"`    // Cosine similarity score
+	DistanceKm          float64           `json:"distanceKm"`    // Geo distance in km
+	CombinedScore       float64           `json:"combinedScore"` // Weighted combined score
+	MatchedSkills       []MatchedSkill    `json:"matchedSkills"` // Skills shared with the project, sorted by name
+	Latitude            *float64          `json:"latitude,omitempty"`
+	Longitude           *float64          `json:"longitude,omitempty"`
+	LocationName        *string           `json:"locationName,omitempty"`
+	Available           *bool             `json:"available,omitempty"`           // Set when the volunteer has availability rules or blackouts
+	AvailabilityOverlap *float64          `json:"availabilityOverlap,omitempty"` // Share [0, 1] of the project's shifts or days they are free for, set with Available
+	Rating              *RatingScore      `json:"rating,omitempty"`              // Set for coordinators when the organization shows ratings
+	Explanation         *MatchExplanation `json:"explanation,omitempty"`         // Set when the search asks for explanations
+}
+
+// MatchedSkill is a skill a volunteer claims that a project asks for
+type MatchedSkill struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	VolunteerScore float64 `json:"volunteerScore"` // The volunteer's proficiency [0, 1]
+	ProjectWeight  float64 `json:"projectWeight"`  // How much the project needs it [0, 1]
+}
+
+// MatchExplanation breaks down how a match's combined score is reached,
+// rescored from current skills and locations with the search's parameters
+type MatchExplanation struct {
+	SkillScore            float64             `json:"skillScore"`              // Cosine similarity of the volunteer's and project's skills
+	Skills                []SkillContribution `json:"skills"`                  // Shared skills, each with its part of skillScore
+	MissingRequiredSkills []MissingSkill      `json:"missingRequiredSkills"`   // Skills the project requires that the volunteer doesn't claim
+	DistanceKm            *float64            `json:"distanceKm"`              // From the volunteer's nearest location; null when unknown or the project is remote
+	MaxDistanceKm         float64             `json:"maxDistanceKm"`           // The volunteer's travel cap, or the search's maximum when they have none; 0 for remote projects
+	DistanceDecay         string              `json:"distanceDecay,omitempty"` // linear, exponential or step; unset for remote projects
+	DistanceScore         float64             `json:"distanceScore"`           // [0, 1] after decay; 0.5 when the distance is unknown
+	SkillWeight           float64             `json:"skillWeight"`             // Normalized; 1 for remote projects
+	DistanceWeight        float64             `json:"distanceWeight"`
+	CombinedScore         float64             `json:"combinedScore"` // skillWeight × skillScore + distanceWeight × distanceScore
+}
+
+// SkillContribution is a shared skill's part of a match's skill score
+type SkillContribution struct {
+	MatchedSkill
+	Contribution float64 `json:"contribution"`
+}
+
+// MissingSkill is a skill a project requires that a volunteer doesn't claim
+type MissingSkill struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	ProjectWeight float64 `json:"projectWeight"`
 }
 
 type ProjectMatch struct {
-	ProjectID     string   `json:"projectId"`
-	ProjectName   string   `json:"projectName"`
-	SkillScore    float64  `json:"skillScore"`
-	DistanceKm    float64  `json:"distanceKm"`
-	CombinedScore float64  `json:"combinedScore"`
-	MatchedSkills []string `json:"matchedSkills"`
-	Latitude      *float64 `json:"latitude,omitempty"`
-	Longitude     *float64 `json:"longitude,omitempty"`
-	LocationName  *string  `json:"locationName,omitempty"`
+	ProjectID     string            `json:"projectId"`
+	ProjectName   string            `json:"projectName"`
+	SkillScore    float64           `json:"skillScore"`
+	DistanceKm    float64           `json:"distanceKm"`
+	CombinedScore float64           `json:"combinedScore"`
+	MatchedSkills []MatchedSkill    `json:"matchedSkills"`
+	Latitude      *float64          `json:"latitude,omitempty"`
+	Longitude     *float64          `json:"longitude,omitempty"`
+	LocationName  *string           `json:"locationName,omitempty"`
+	IsRemote      bool              `json:"isRemote"`
+	Explanation   *MatchExplanation `json:"explanation,omitempty"` // Set when the search asks for explanations
+}
+
+// MatchRecomputation is a run of the batch matcher filling the precomputed
+// matches, of every project and volunteer (scope "all") or of those whose
+// matches went stale ("stale")
+type MatchRecomputation struct {
+	ID         string     `json:"id"`
+	Scope      string     `json:"scope"`
+	Status     string     `json:"status"`    // running, succeeded or failed
+	Total      int        `json:"total"`     // Projects and volunteers to score
+	Processed  int        `json:"processed"` // Projects and volunteers scored so far
+	Matches    int        `json:"matches"`   // Matches saved so far
+	Error      *string    `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
 }
 
 type UpdateSkillsRequest struct {
 	Skills []struct {
-		SkillID string  `json:"skillId"`
+		SkillID string  `json:"skillId" validate:"required"`
 		Claimed bool    `json:"claimed"`
-		Score   float64 `json:"score"`
+		Score   float64 `json:"score" validate:"min=0,max=1"`
 	} `json:"skills"`
 }
 
 type UpdateLocationRequest struct {
-	Latitude     float64 `json:"latitude"`
-	Longitude    float64 `json:"longitude"`
-	LocationName string  `json:"locationName"`
+	Latitude     float64 `json:"latitude" validate:"lat"`
+	Longitude    float64 `json:"longitude" validate:"lon"`
+	LocationName string  `json:"locationName" validate:"max=255"`
+	// MaxTravelKm caps match distance for the volunteer; omit to keep the
+	// current cap, 0 to clear it
+	MaxTravelKm *float64 `json:"maxTravelKm,omitempty" validate:"min=0,max=500"`
+	// Timezone is the volunteer's IANA zone; omit to keep the current one
+	Timezone *string `json:"timezone,omitempty" validate:"timezone"`
 }
 
 type CreateSkillRequest struct {
-	Name        string `json:"name"`
+	Name        string `json:"name" validate:"required,max=255"`
 	Description string `json:"description"`
-	Category    string `json:"category"`
+	Category    string `json:"category" validate:"max=100"`
 }
 
 type UpdateProjectSkillsRequest struct {
 	Skills []struct {
-		SkillID  string  `json:"skillId"`
+		SkillID  string  `json:"skillId" validate:"required"`
 		Required bool    `json:"required"`
-		Weight   float64 `json:"weight"`
+		Weight   float64 `json:"weight" validate:"min=0,max=1"`
 	} `json:"skills"`
 }
 
 type UpdateProjectRequest struct {
-	Name         string   `json:"name"`
-	Description  string   `json:"description"`
-	Latitude     *float64 `json:"latitude,omitempty"`
-	Longitude    *float64 `json:"longitude,omitempty"`
-	LocationName *string  `json:"locationName,omitempty"`
+	Name                   string   `json:"name" validate:"max=255"`
+	Description            string   `json:"description"`
+	Latitude               *float64 `json:"latitude,omitempty" validate:"lat"`
+	Longitude              *float64 `json:"longitude,omitempty" validate:"lon"`
+	LocationName           *string  `json:"locationName,omitempty" validate:"max=255"`
+	IsRemote               *bool    `json:"isRemote,omitempty"`
+	Timezone               *string  `json:"timezone,omitempty" validate:"timezone"`
+	BlockScheduleConflicts *bool    `json:"blockScheduleConflicts,omitempty"`
 }
 
 type CreateProjectRequest struct {
-	Name          string     `json:"name"`
-	Description   string     `json:"description"`
-	CoordinatorID *string    `json:"coordinatorId,omitempty"`
-	Latitude      *float64   `json:"latitude,omitempty"`
-	Longitude     *float64   `json:"longitude,omitempty"`
-	LocationName  *string    `json:"locationName,omitempty"`
-	StartDate     *time.Time `json:"startDate,omitempty"`
-	EndDate       *time.Time `json:"endDate,omitempty"`
-	MaxVolunteers *int       `json:"maxVolunteers,omitempty"`
+	Name                   string     `json:"name" validate:"required,max=255"`
+	Description            string     `json:"description"`
+	CoordinatorID          *string    `json:"coordinatorId,omitempty"`
+	OrganizationID         *string    `json:"organizationId,omitempty"`
+	Latitude               *float64   `json:"latitude,omitempty" validate:"lat"`
+	Longitude              *float64   `json:"longitude,omitempty" validate:"lon"`
+	LocationName           *string    `json:"locationName,omitempty" validate:"max=255"`
+	IsRemote               bool       `json:"isRemote"`
+	Timezone               string     `json:"timezone,omitempty" validate:"timezone"` // IANA zone; defaults to UTC
+	BlockScheduleConflicts bool       `json:"blockScheduleConflicts"`
+	StartDate              *time.Time `json:"startDate,omitempty"`
+	EndDate                *time.Time `json:"endDate,omitempty" validate:"notbefore=StartDate"`
+	MaxVolunteers          *int       `json:"maxVolunteers,omitempty" validate:"min=1"`
 }
 
 type UpdateProjectStatusRequest struct {
-	Status string `json:"status"`
+	Status string `json:"status" validate:"required"`
+}
+
+// SkillAlias is another name a skill is matched by, e.g. in volunteer imports
+type SkillAlias struct {
+	ID        string    `json:"id"`
+	SkillID   string    `json:"skillId"`
+	Alias     string    `json:"alias"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateSkillAliasRequest struct {
+	Alias string `json:"alias" validate:"required,max=255"`
 }
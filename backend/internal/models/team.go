@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Team is a group of a project's enrolled volunteers, e.g. logistics or outreach
+type Team struct {
+	ID          string    `json:"id"`
+	ProjectID   string    `json:"projectId"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	LeadID      *string   `json:"leadId,omitempty"`
+	LeadName    *string   `json:"leadName,omitempty"`
+	MemberCount int       `json:"memberCount"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type TeamMember struct {
+	TeamID         string `json:"teamId"`
+	VolunteerID    string `json:"volunteerId"`
+	VolunteerName  string `json:"volunteerName"`
+	VolunteerEmail string `json:"volunteerEmail"`
+	// VolunteerLocale is set on broadcast recipients, for their emails
+	VolunteerLocale string    `json:"-"`
+	AssignedBy      *string   `json:"assignedBy,omitempty"`
+	AssignedAt      time.Time `json:"assignedAt"`
+}
+
+type TeamWithMembers struct {
+	Team
+	Members []TeamMember `json:"members"`
+}
+
+type TeamMessage struct {
+	ID             string    `json:"id"`
+	TeamID         string    `json:"teamId"`
+	SenderID       string    `json:"senderId"`
+	SenderName     string    `json:"senderName"`
+	Subject        string    `json:"subject"`
+	Body           string    `json:"body"`
+	RecipientCount int       `json:"recipientCount"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type CreateTeamRequest struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	Description *string `json:"description,omitempty"`
+	LeadID      *string `json:"leadId,omitempty"`
+}
+
+type UpdateTeamLeadRequest struct {
+	LeadID *string `json:"leadId"` // null clears the lead
+}
+
+type BroadcastTeamMessageRequest struct {
+	Subject string `json:"subject" validate:"required,max=200"`
+	Body    string `json:"body" validate:"required"`
+}
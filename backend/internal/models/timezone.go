@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// DefaultTimezone applies to projects and users that haven't set one
+const DefaultTimezone = "UTC"
+
+// ValidTimezone reports whether name is an IANA time zone such as
+// "America/Toronto"
+func ValidTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// InTimezone converts t to the named zone so it serializes with that zone's
+// offset. Unknown zones leave t unchanged.
+func InTimezone(t *time.Time, name string) *time.Time {
+	if t == nil {
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return t
+	}
+	local := t.In(loc)
+	return &local
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// ProjectTranslation is a project's name and description in another
+// language. Clients preferring the locale see them in place of the
+// original; a nil Description shows the original description.
+type ProjectTranslation struct {
+	ProjectID   string    `json:"projectId"`
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	UpdatedBy   *string   `json:"updatedBy,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type SetProjectTranslationRequest struct {
+	Name        string  `json:"name" validate:"required,max=255"`
+	Description *string `json:"description,omitempty"`
+}
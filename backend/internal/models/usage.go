@@ -0,0 +1,21 @@
+package models
+
+// APIUsage is one consumer's API requests over a report's period
+type APIUsage struct {
+	ConsumerType string `json:"consumerType"` // "api_key", "user"
+	ConsumerID   string `json:"consumerId"`
+	// Name is the key's or the user's name, empty once it is deleted
+	Name string `json:"name"`
+	// OrganizationID is the key's organization
+	OrganizationID *string `json:"organizationId,omitempty"`
+	Requests       int64   `json:"requests"`
+	Rejected       int64   `json:"rejected"`
+}
+
+// APIUsageReport lists the consumers that made the most API requests since
+// From, the start of the current UTC day or calendar month
+type APIUsageReport struct {
+	Period    string     `json:"period"` // "day", "month"
+	From      string     `json:"from"`
+	Consumers []APIUsage `json:"consumers"`
+}
@@ -5,23 +5,75 @@ import (
 )
 
 type User struct {
-	ID              string    `json:"id"`
-	Email           string    `json:"email"`
-	Name            string    `json:"name"`
-	Role            string    `json:"role"`
-	ProfileComplete bool      `json:"profileComplete"`
-	Latitude        *float64  `json:"latitude,omitempty"`
-	Longitude       *float64  `json:"longitude,omitempty"`
-	LocationName    *string   `json:"locationName,omitempty"`
-	CreatedAt       time.Time `json:"createdAt"`
-	UpdatedAt       time.Time `json:"updatedAt"`
+	ID              string   `json:"id"`
+	Email           string   `json:"email"`
+	Name            string   `json:"name"`
+	Role            string   `json:"role"`
+	ProfileComplete bool     `json:"profileComplete"`
+	Latitude        *float64 `json:"latitude,omitempty"`
+	Longitude       *float64 `json:"longitude,omitempty"`
+	LocationName    *string  `json:"locationName,omitempty"`
+	MaxTravelKm     *float64 `json:"maxTravelKm,omitempty"`
+	Timezone        string   `json:"timezone"`
+	// Locale is the language the user's emails are written in
+	Locale string `json:"locale"`
+	// LeaderboardOptIn shows the user on volunteer leaderboards
+	LeaderboardOptIn bool    `json:"leaderboardOptIn"`
+	AvatarURL        *string `json:"avatarUrl,omitempty"`
+	// AvatarVariants are the avatar's standard sizes, once generated
+	AvatarVariants ImageVariants `json:"avatarVariants,omitempty"`
+	// SuspendedAt is set while a moderator has suspended the user
+	SuspendedAt *time.Time `json:"suspendedAt,omitempty"`
+	// EmailVerifiedAt is unset until a self-registered user verifies
+	// their email address
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt,omitempty"`
+	// PendingRole is the role an email domain rule requested for the user,
+	// while it awaits approval
+	PendingRole *string   `json:"pendingRole,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
 }
 
 type LoginRequest struct {
-	Email string `json:"email"`
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
 
 type RegisterRequest struct {
-	Email string `json:"email"`
-	Name  string `json:"name"`
+	Email    string `json:"email" validate:"required,email,max=254"`
+	Name     string `json:"name" validate:"required,max=255"`
+	Password string `json:"password" validate:"required"`
+	// Locale defaults to the language negotiated from Accept-Language
+	Locale string `json:"locale,omitempty"`
+	// InvitationToken joins the volunteer to the organization that invited
+	// their email address
+	InvitationToken string `json:"invitationToken,omitempty"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required"`
+}
+
+// AuthResponse is a signed-in user with the bearer token to send as
+// Authorization on later requests, and the refresh token that renews it
+type AuthResponse struct {
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	RefreshToken     string    `json:"refreshToken"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+	User             *User     `json:"user"`
+}
+
+type UpdateLocaleRequest struct {
+	Locale string `json:"locale" validate:"required"`
 }
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// WaiverTemplate is a waiver volunteers sign before enrolling, either in
+// every project of OrganizationID or in ProjectID. Editing it publishes a
+// new Version that volunteers must sign again.
+type WaiverTemplate struct {
+	ID             string     `json:"id"`
+	OrganizationID *string    `json:"organizationId,omitempty"`
+	ProjectID      *string    `json:"projectId,omitempty"`
+	Title          string     `json:"title"`
+	Body           string     `json:"body"`
+	Version        int        `json:"version"`
+	CreatedBy      *string    `json:"createdBy,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+	SignedAt       *time.Time `json:"signedAt,omitempty"` // When the requesting volunteer signed this version
+}
+
+type WaiverTemplateRequest struct {
+	Title string `json:"title" validate:"required,max=200"`
+	Body  string `json:"body" validate:"required"`
+}
+
+// SignWaiverRequest signs the given version of a waiver; signing a
+// superseded version is rejected so volunteers sign the text they read
+type SignWaiverRequest struct {
+	SignedName string `json:"signedName" validate:"required,max=200"`
+	Version    int    `json:"version" validate:"min=1"`
+}
+
+// WaiverSignature is a volunteer's e-signature of one version of a waiver
+type WaiverSignature struct {
+	ID            string    `json:"id"`
+	WaiverID      string    `json:"waiverId"`
+	Version       int       `json:"version"`
+	VolunteerID   string    `json:"volunteerId"`
+	VolunteerName string    `json:"volunteerName"`
+	SignedName    string    `json:"signedName"`
+	IPAddress     string    `json:"ipAddress"`
+	SignedAt      time.Time `json:"signedAt"`
+}
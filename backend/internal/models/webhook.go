@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook is a callback URL sent the events it subscribes to
+type Webhook struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Active bool     `json:"active"`
+	// Secret signs deliveries; it is only returned when the webhook is
+	// created
+	Secret    string    `json:"secret,omitempty"`
+	CreatedBy *string   `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" validate:"required,url,max=2000"`
+	Events []string `json:"events" validate:"required"`
+}
+
+type UpdateWebhookRequest struct {
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+// WebhookDelivery is one event sent to a webhook, with the outcome of its
+// latest attempt
+type WebhookDelivery struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhookId"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"` // "pending", "succeeded", "failed"
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int            `json:"responseStatus,omitempty"`
+	LastError      *string         `json:"lastError,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+}
@@ -0,0 +1,38 @@
+package moderation
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/gorilla/mux"
+)
+
+// Middleware refuses requests made by a suspended user, whose token may
+// still be valid. Requests without a signed-in user pass through untouched.
+// It must run after auth.Middleware.
+func Middleware(isSuspended func(userID string) (bool, error)) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := auth.UserID(r)
+			if userID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			suspended, err := isSuspended(userID)
+			if err != nil {
+				slog.Error("Suspension check error", "user", userID, "error", err)
+				apierror.Write(w, http.StatusInternalServerError, "Failed to check account status")
+				return
+			}
+			if suspended {
+				apierror.Write(w, http.StatusForbidden, "Account suspended")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
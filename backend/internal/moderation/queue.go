@@ -0,0 +1,428 @@
+package moderation
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/audit"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrReportNotFound     = errors.New("report not found")
+	ErrReportClosed       = errors.New("report has already been resolved")
+	ErrInvalidStatus      = errors.New("status must be open, dismissed or actioned")
+	ErrInvalidAction      = errors.New("action must be dismiss, hide or suspend")
+	ErrNoteTooLong        = errors.New("note must be at most 2000 characters")
+	ErrNoOwner            = errors.New("reported content has no owner to suspend")
+	ErrCannotSuspendAdmin = errors.New("platform admins cannot be suspended")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrNotSuspended       = errors.New("user is not suspended")
+)
+
+// Contact is a user to notify about a moderation decision
+type Contact struct {
+	ID     string
+	Name   string
+	Email  string
+	Locale string
+}
+
+// Recipients are the people to tell about a resolved report: everyone who
+// reported the content, and its owner when it was hidden or they were
+// suspended
+type Recipients struct {
+	Reporters []Contact
+	Owner     *Contact
+}
+
+// queryRower runs single-row queries on a database or inside a transaction
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// content is reported content with the owner details moderators act on
+type content struct {
+	models.ReportedContent
+	ownerEmail  string
+	ownerLocale string
+	ownerRole   string
+}
+
+// contentQueries load reported content by target type. Each selects the
+// content's id, owner, title, body and hidden flag, the owner's email, role
+// and suspension, then two context values named by contentContext.
+var contentQueries = map[string]string{
+	models.ReportTargetProject: `
+		SELECT p.id, p.coordinator_id, u.name, p.name, p.description, p.status = 'hidden',
+		       COALESCE(u.email, ''), COALESCE(u.locale, ''), COALESCE(u.role, ''), u.suspended_at IS NOT NULL,
+		       COALESCE(o.name, ''), p.status
+		FROM projects p
+		LEFT JOIN users u ON u.id = p.coordinator_id
+		LEFT JOIN organizations o ON o.id = p.organization_id
+		WHERE p.id::text = $1
+	`,
+	models.ReportTargetMessage: `
+		SELECT m.id, m.sender_id, u.name, m.subject, m.body, m.hidden_at IS NOT NULL,
+		       u.email, u.locale, u.role, u.suspended_at IS NOT NULL,
+		       p.name, t.name
+		FROM project_team_messages m
+		JOIN users u ON u.id = m.sender_id
+		JOIN project_teams t ON t.id = m.team_id
+		JOIN projects p ON p.id = t.project_id
+		WHERE m.id::text = $1
+	`,
+	models.ReportTargetProfile: `
+		SELECT u.id, u.id, u.name, u.name, '', u.profile_hidden_at IS NOT NULL,
+		       u.email, u.locale, u.role, u.suspended_at IS NOT NULL,
+		       u.role, TO_CHAR(u.created_at, 'YYYY-MM-DD')
+		FROM users u
+		WHERE u.id::text = $1
+	`,
+}
+
+var contentContext = map[string][2]string{
+	models.ReportTargetProject: {"organization", "status"},
+	models.ReportTargetMessage: {"project", "team"},
+	models.ReportTargetProfile: {"role", "memberSince"},
+}
+
+// loadContent loads reported content; content that no longer exists comes
+// back marked Deleted
+func loadContent(q queryRower, targetType, targetID string) (*content, error) {
+	c := content{ReportedContent: models.ReportedContent{Type: targetType, ID: targetID, Context: map[string]string{}}}
+	var context [2]string
+	err := q.QueryRow(contentQueries[targetType], targetID).Scan(
+		&c.ID,
+		&c.OwnerID,
+		&c.OwnerName,
+		&c.Title,
+		&c.Body,
+		&c.Hidden,
+		&c.ownerEmail,
+		&c.ownerLocale,
+		&c.ownerRole,
+		&c.OwnerSuspended,
+		&context[0],
+		&context[1],
+	)
+	if err == sql.ErrNoRows {
+		c.Deleted = true
+		return &c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := contentContext[targetType]
+	for i, name := range names {
+		if context[i] != "" {
+			c.Context[name] = context[i]
+		}
+	}
+	return &c, nil
+}
+
+// GetReports lists reports with the given status, open by default, oldest
+// first so the queue is worked in order
+func (s *Service) GetReports(status string) ([]models.ContentReport, error) {
+	if status == "" {
+		status = "open"
+	}
+	if status != "open" && status != "dismissed" && status != "actioned" {
+		return nil, ErrInvalidStatus
+	}
+
+	return s.queryReports(`
+		SELECT `+reportColumns+`
+		FROM content_reports
+		WHERE status = $1
+		ORDER BY created_at, id
+	`, status)
+}
+
+// GetReport returns a report with the reported content and the other
+// reports filed against it
+func (s *Service) GetReport(reportID string) (*models.ReportDetail, error) {
+	var detail models.ReportDetail
+	err := database.WithReadRetry(func() error {
+		return scanReport(s.db.QueryRow(`
+			SELECT `+reportColumns+` FROM content_reports WHERE id::text = $1
+		`, reportID), &detail.ContentReport)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrReportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c *content
+	err = database.WithReadRetry(func() error {
+		var err error
+		c, err = loadContent(s.db, detail.TargetType, detail.TargetID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	detail.Content = c.ReportedContent
+
+	detail.OtherReports, err = s.queryReports(`
+		SELECT `+reportColumns+`
+		FROM content_reports
+		WHERE target_type = $1 AND target_id = $2 AND id <> $3
+		ORDER BY created_at DESC, id
+	`, detail.TargetType, detail.TargetID, detail.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &detail, nil
+}
+
+// Resolve closes a report and every other open report on the same content,
+// dismissing them or hiding the content or suspending its owner. The action
+// is recorded in the audit log in the same transaction.
+func (s *Service) Resolve(reportID, moderatorID string, req models.ResolveReportRequest) (*models.ReportResolution, *Recipients, error) {
+	switch req.Action {
+	case models.ModerationDismiss, models.ModerationHide, models.ModerationSuspend:
+	default:
+		return nil, nil, ErrInvalidAction
+	}
+	var note *string
+	if req.Note != nil {
+		trimmed := strings.TrimSpace(*req.Note)
+		if len([]rune(trimmed)) > maxDetailsLength {
+			return nil, nil, ErrNoteTooLong
+		}
+		if trimmed != "" {
+			note = &trimmed
+		}
+	}
+	status := "actioned"
+	if req.Action == models.ModerationDismiss {
+		status = "dismissed"
+	}
+
+	var resolution *models.ReportResolution
+	var recipients *Recipients
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var targetType, targetID, reportStatus string
+		err = tx.QueryRow(`
+			SELECT target_type, target_id, status
+			FROM content_reports
+			WHERE id::text = $1
+			FOR UPDATE
+		`, reportID).Scan(&targetType, &targetID, &reportStatus)
+		if err == sql.ErrNoRows {
+			return ErrReportNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if reportStatus != "open" {
+			return ErrReportClosed
+		}
+
+		c, err := loadContent(tx, targetType, targetID)
+		if err != nil {
+			return err
+		}
+		if c.Deleted && req.Action != models.ModerationDismiss {
+			return ErrTargetNotFound
+		}
+
+		details := map[string]string{"report": reportID}
+		if note != nil {
+			details["note"] = *note
+		}
+		recipients = &Recipients{Reporters: []Contact{}}
+		switch req.Action {
+		case models.ModerationHide:
+			if err := hideContent(tx, c); err != nil {
+				return err
+			}
+			c.Hidden = true
+			if err := audit.Record(tx, moderatorID, "moderation.hide", targetType, targetID, details); err != nil {
+				return err
+			}
+		case models.ModerationSuspend:
+			if c.OwnerID == nil {
+				return ErrNoOwner
+			}
+			if c.ownerRole == "admin" {
+				return ErrCannotSuspendAdmin
+			}
+			if _, err := tx.Exec(`
+				UPDATE users SET suspended_at = COALESCE(suspended_at, NOW()) WHERE id = $1
+			`, *c.OwnerID); err != nil {
+				return err
+			}
+			c.OwnerSuspended = true
+			details["contentType"], details["contentId"] = targetType, targetID
+			if err := audit.Record(tx, moderatorID, "moderation.suspend", "user", *c.OwnerID, details); err != nil {
+				return err
+			}
+		default:
+			if err := audit.Record(tx, moderatorID, "moderation.dismiss", targetType, targetID, details); err != nil {
+				return err
+			}
+		}
+		if req.Action != models.ModerationDismiss && c.OwnerID != nil {
+			recipients.Owner = &Contact{ID: *c.OwnerID, Name: *c.OwnerName, Email: c.ownerEmail, Locale: c.ownerLocale}
+		}
+
+		rows, err := tx.Query(`
+			UPDATE content_reports
+			SET status = $3,
+			    resolution_action = $4,
+			    resolution_note = $5,
+			    resolved_by = $6,
+			    resolved_at = NOW()
+			WHERE target_type = $1 AND target_id = $2 AND status = 'open'
+			RETURNING `+reportColumns,
+			targetType, targetID, status, req.Action, note, moderatorID,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		resolution = &models.ReportResolution{Action: req.Action, Content: c.ReportedContent, Reports: []models.ContentReport{}}
+		var reporterIDs []string
+		for rows.Next() {
+			var r models.ContentReport
+			if err := scanReport(rows, &r); err != nil {
+				return err
+			}
+			resolution.Reports = append(resolution.Reports, r)
+			reporterIDs = append(reporterIDs, r.ReporterID)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		reporters, err := tx.Query(`
+			SELECT id, name, email, locale FROM users WHERE id = ANY($1::uuid[]) ORDER BY name, id
+		`, pq.Array(reporterIDs))
+		if err != nil {
+			return err
+		}
+		defer reporters.Close()
+
+		for reporters.Next() {
+			var contact Contact
+			if err := reporters.Scan(&contact.ID, &contact.Name, &contact.Email, &contact.Locale); err != nil {
+				return err
+			}
+			recipients.Reporters = append(recipients.Reporters, contact)
+		}
+		if err := reporters.Err(); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resolution, recipients, nil
+}
+
+// hideContent takes reported content out of public view
+func hideContent(tx *sql.Tx, c *content) error {
+	var query string
+	switch c.Type {
+	case models.ReportTargetProject:
+		query = `UPDATE projects SET status = 'hidden', updated_at = NOW() WHERE id = $1`
+	case models.ReportTargetMessage:
+		query = `UPDATE project_team_messages SET hidden_at = COALESCE(hidden_at, NOW()) WHERE id = $1`
+	case models.ReportTargetProfile:
+		query = `UPDATE users SET profile_hidden_at = COALESCE(profile_hidden_at, NOW()) WHERE id = $1`
+	}
+	_, err := tx.Exec(query, c.ID)
+	return err
+}
+
+// Unsuspend lifts a user's suspension, recording it in the audit log
+func (s *Service) Unsuspend(userID, moderatorID string) error {
+	return database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var suspended bool
+		err = tx.QueryRow(`
+			SELECT suspended_at IS NOT NULL FROM users WHERE id::text = $1 FOR UPDATE
+		`, userID).Scan(&suspended)
+		if err == sql.ErrNoRows {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if !suspended {
+			return ErrNotSuspended
+		}
+
+		if _, err := tx.Exec(`UPDATE users SET suspended_at = NULL WHERE id::text = $1`, userID); err != nil {
+			return err
+		}
+		if err := audit.Record(tx, moderatorID, "moderation.unsuspend", "user", userID, nil); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+}
+
+// IsSuspended reports whether the user is currently suspended
+func (s *Service) IsSuspended(userID string) (bool, error) {
+	var suspended bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(`
+			SELECT EXISTS (SELECT 1 FROM users WHERE id::text = $1 AND suspended_at IS NOT NULL)
+		`, userID).Scan(&suspended)
+	})
+	return suspended, err
+}
+
+func (s *Service) queryReports(query string, args ...interface{}) ([]models.ContentReport, error) {
+	var reports []models.ContentReport
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		reports = []models.ContentReport{}
+		for rows.Next() {
+			var r models.ContentReport
+			if err := scanReport(rows, &r); err != nil {
+				return err
+			}
+			reports = append(reports, r)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,127 @@
+package moderation
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrInvalidTarget   = errors.New("targetType must be project, message or profile")
+	ErrInvalidCategory = errors.New("category must be spam, harassment, hate, violence, sexual, misinformation or other")
+	ErrDetailsTooLong  = errors.New("details must be at most 2000 characters")
+	ErrTargetNotFound  = errors.New("reported content not found")
+	ErrAlreadyReported = errors.New("you have already reported this content")
+)
+
+// Categories a report can be filed under
+var Categories = []string{"spam", "harassment", "hate", "violence", "sexual", "misinformation", "other"}
+
+const maxDetailsLength = 2000
+
+// targetExists checks reported content exists, by target type
+var targetExists = map[string]string{
+	models.ReportTargetProject: `SELECT EXISTS (SELECT 1 FROM projects WHERE id::text = $1)`,
+	models.ReportTargetMessage: `SELECT EXISTS (SELECT 1 FROM project_team_messages WHERE id::text = $1)`,
+	models.ReportTargetProfile: `SELECT EXISTS (SELECT 1 FROM users WHERE id::text = $1)`,
+}
+
+const reportColumns = `
+	id, reporter_id, target_type, target_id, category, details,
+	status, resolution_action, resolution_note, resolved_by, resolved_at, created_at
+`
+
+func scanReport(row interface{ Scan(...interface{}) error }, r *models.ContentReport) error {
+	return row.Scan(
+		&r.ID,
+		&r.ReporterID,
+		&r.TargetType,
+		&r.TargetID,
+		&r.Category,
+		&r.Details,
+		&r.Status,
+		&r.Resolution,
+		&r.ResolutionNote,
+		&r.ResolvedBy,
+		&r.ResolvedAt,
+		&r.CreatedAt,
+	)
+}
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// CreateReport files a report against a project, team message or profile
+// for the moderation queue
+func (s *Service) CreateReport(reporterID string, req models.CreateReportRequest) (*models.ContentReport, error) {
+	existsQuery, ok := targetExists[req.TargetType]
+	if !ok {
+		return nil, ErrInvalidTarget
+	}
+	if !validCategory(req.Category) {
+		return nil, ErrInvalidCategory
+	}
+	details := req.Details
+	if details != nil {
+		trimmed := strings.TrimSpace(*details)
+		if len([]rune(trimmed)) > maxDetailsLength {
+			return nil, ErrDetailsTooLong
+		}
+		details = &trimmed
+		if trimmed == "" {
+			details = nil
+		}
+	}
+
+	var exists bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(existsQuery, req.TargetID).Scan(&exists)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrTargetNotFound
+	}
+
+	var report models.ContentReport
+	err = database.WithWriteGuard(func() error {
+		return scanReport(s.db.QueryRow(`
+			INSERT INTO content_reports (reporter_id, target_type, target_id, category, details)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING `+reportColumns,
+			reporterID, req.TargetType, req.TargetID, req.Category, details,
+		), &report)
+	})
+	if isUniqueViolation(err) {
+		return nil, ErrAlreadyReported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func validCategory(category string) bool {
+	for _, c := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,70 @@
+package notifications
+
+import (
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is a rendered email ready for delivery
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email messages
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// LogMailer writes messages to the log instead of sending them, for local
+// development and demos
+type LogMailer struct{}
+
+func (LogMailer) Send(msg Message) error {
+	slog.Info("Email", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// SMTPMailer sends messages as plain text through an SMTP server, using
+// STARTTLS when the server offers it. Username may be empty for relays
+// that don't require authentication.
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+func (m SMTPMailer) Send(msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", msg.To)
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", m.From)
+	fmt.Fprintf(&body, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&body, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&body, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	body.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+	if err := smtp.SendMail(addr, auth, m.From, []string{msg.To}, []byte(body.String())); err != nil {
+		return fmt.Errorf("send email to %s: %w", msg.To, err)
+	}
+	return nil
+}
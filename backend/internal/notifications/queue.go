@@ -0,0 +1,40 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/civic-weave/backend/internal/jobs"
+)
+
+// SendEmailJob is the kind of job that delivers a queued Message
+const SendEmailJob = "email.send"
+
+// QueuedMailer hands messages to the job queue, which delivers them in the
+// background and retries failed deliveries
+type QueuedMailer struct {
+	jobsService *jobs.Service
+}
+
+// NewQueuedMailer registers the worker that delivers queued messages
+// through mailer
+func NewQueuedMailer(jobsService *jobs.Service, mailer Mailer) *QueuedMailer {
+	jobsService.Register(SendEmailJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			var msg Message
+			if err := json.Unmarshal(payload, &msg); err != nil {
+				return err
+			}
+			return mailer.Send(msg)
+		},
+		Retry:   jobs.RetryPolicy{MaxAttempts: 8, Backoff: time.Minute, MaxBackoff: 2 * time.Hour},
+		Timeout: time.Minute,
+	})
+	return &QueuedMailer{jobsService: jobsService}
+}
+
+func (m *QueuedMailer) Send(msg Message) error {
+	_, err := m.jobsService.Enqueue(SendEmailJob, msg)
+	return err
+}
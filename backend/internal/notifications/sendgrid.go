@@ -0,0 +1,75 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const sendGridAPI = "https://api.sendgrid.com/v3/mail/send"
+
+// SendGridMailer sends messages as plain text through SendGrid's v3 mail
+// API
+type SendGridMailer struct {
+	APIKey string
+	From   string
+	Client *http.Client
+}
+
+func NewSendGridMailer(apiKey, from string) *SendGridMailer {
+	return &SendGridMailer{APIKey: apiKey, From: from, Client: http.DefaultClient}
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+type sendGridRequest struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+}
+
+func (m *SendGridMailer) Send(msg Message) error {
+	body, err := json.Marshal(sendGridRequest{
+		Personalizations: []sendGridPersonalization{{To: []sendGridAddress{{Email: msg.To}}}},
+		From:             sendGridAddress{Email: m.From},
+		Subject:          msg.Subject,
+		Content:          []sendGridContent{{Type: "text/plain", Value: msg.Body}},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, sendGridAPI, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+m.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send email to %s: %w", msg.To, err)
+	}
+	defer resp.Body.Close()
+
+	// SendGrid answers 202 once it has queued the message
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("send email to %s: SendGrid returned %s: %s", msg.To, resp.Status, bytes.TrimSpace(detail))
+	}
+	return nil
+}
@@ -0,0 +1,378 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/events"
+	"github.com/civic-weave/backend/internal/i18n"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/pagination"
+	"github.com/civic-weave/backend/internal/tracing"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// Notification kinds
+const (
+	KindEnrollmentRequested  = "enrollment_requested"
+	KindEnrollmentInvited    = "enrollment_invited"
+	KindEnrollmentAccepted   = "enrollment_accepted"
+	KindEnrollmentWaitlisted = "enrollment_waitlisted"
+	KindEnrollmentRejected   = "enrollment_rejected"
+	KindProjectStatusChanged = "project_status_changed"
+	KindProjectMatch         = "project_match"
+)
+
+// NotifyMatchesJob is the kind of background job that notifies volunteers
+// who match a newly published project. Its payload is the project ID.
+const NotifyMatchesJob = "notifications.notify_matches"
+
+// Sorts are the fields notification lists can sort by, newest first by
+// default
+var Sorts = pagination.Sorts{
+	Fields:   map[string]string{"createdAt": "created_at"},
+	Default:  "-createdAt",
+	Tiebreak: "id",
+}
+
+// notice is a notification for one user, before it is written in their
+// language. Message names the notification.<message>.title and .body
+// messages, which may differ between notices of the same kind.
+type notice struct {
+	UserID       string
+	Kind         string
+	Message      string
+	Args         i18n.Args
+	ProjectID    string
+	EnrollmentID string
+	// DedupeKey, when set, makes sure the user gets the notice only once
+	DedupeKey string
+}
+
+// Service records notifications and emails each one to its recipient
+type Service struct {
+	db     *sql.DB
+	mailer Mailer
+}
+
+func NewService(db *sql.DB, mailer Mailer) *Service {
+	return &Service{db: db, mailer: mailer}
+}
+
+// notify records the notice in the recipient's language and emails it to
+// them. It reports false when the notice's dedupe key shows the user was
+// already notified. A failed email is logged; the notification stays.
+func (s *Service) notify(ctx context.Context, n notice) (bool, error) {
+	var email, name, locale string
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRowContext(ctx, `SELECT email, name, locale FROM users WHERE id = $1`, n.UserID).Scan(&email, &name, &locale)
+	})
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	title := i18n.T(locale, "notification."+n.Message+".title", n.Args)
+	body := i18n.T(locale, "notification."+n.Message+".body", n.Args)
+
+	query := `
+		INSERT INTO notifications (user_id, kind, title, body, project_id, enrollment_id, dedupe_key)
+		VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, NULLIF($6, '')::uuid, NULLIF($7, ''))
+		ON CONFLICT (user_id, dedupe_key) WHERE dedupe_key IS NOT NULL DO NOTHING
+		RETURNING id
+	`
+	var id string
+	err = database.WithWriteGuard(func() error {
+		return s.db.QueryRowContext(ctx, query, n.UserID, n.Kind, title, body, n.ProjectID, n.EnrollmentID, n.DedupeKey).Scan(&id)
+	})
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	msg := Message{
+		To:      email,
+		Subject: title,
+		Body:    greeting(locale, name) + body + "\n",
+	}
+	if err := s.mailer.Send(msg); err != nil {
+		slog.Error("Notification email error", "notification", id, "user", n.UserID, "error", err)
+	}
+	return true, nil
+}
+
+// HandleEnrollmentChanged is the events.Dispatcher subscriber notifying
+// people of events.TypeEnrollmentChanged events
+func (s *Service) HandleEnrollmentChanged(ctx context.Context, e events.Event) error {
+	var c events.EnrollmentChanged
+	if err := e.Decode(&c); err != nil {
+		return err
+	}
+	return s.EnrollmentChanged(ctx, c.EnrollmentID, c.Status, c.ActorID)
+}
+
+// EnrollmentChanged notifies whoever should hear about the enrollment's
+// new status: coordinators of new requests and of volunteers answering
+// their invitations, and volunteers of invitations and of answers to their
+// requests. actorID, who made the change, is never notified of it. Other
+// statuses notify nobody.
+func (s *Service) EnrollmentChanged(ctx context.Context, enrollmentID, status, actorID string) error {
+	ctx, span := tracing.Start(ctx, "notifications.EnrollmentChanged")
+	defer span.End()
+
+	query := `
+		SELECT ve.volunteer_id, u.name, ve.project_id, p.name, p.coordinator_id
+		FROM volunteer_enrollments ve
+		JOIN users u ON u.id = ve.volunteer_id
+		JOIN projects p ON p.id = ve.project_id
+		WHERE ve.id = $1
+	`
+	var volunteerID, volunteerName, projectID, projectName string
+	var coordinatorID sql.NullString
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRowContext(ctx, query, enrollmentID).Scan(&volunteerID, &volunteerName, &projectID, &projectName, &coordinatorID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get enrollment: %w", err)
+	}
+
+	n := notice{
+		Args:         i18n.Args{"volunteer": volunteerName, "project": projectName},
+		ProjectID:    projectID,
+		EnrollmentID: enrollmentID,
+	}
+	switch {
+	case status == "requested":
+		n.UserID, n.Kind, n.Message = coordinatorID.String, KindEnrollmentRequested, "enrollment_requested"
+	case status == "invited":
+		n.UserID, n.Kind, n.Message = volunteerID, KindEnrollmentInvited, "enrollment_invited"
+	case status == "waitlisted":
+		n.UserID, n.Kind, n.Message = volunteerID, KindEnrollmentWaitlisted, "enrollment_waitlisted"
+	case status == "enrolled" && actorID == volunteerID:
+		n.UserID, n.Kind, n.Message = coordinatorID.String, KindEnrollmentAccepted, "invitation_accepted"
+	case status == "enrolled":
+		n.UserID, n.Kind, n.Message = volunteerID, KindEnrollmentAccepted, "enrollment_accepted"
+	case status == "tl_rejected":
+		n.UserID, n.Kind, n.Message = volunteerID, KindEnrollmentRejected, "enrollment_rejected"
+	case status == "v_rejected":
+		n.UserID, n.Kind, n.Message = coordinatorID.String, KindEnrollmentRejected, "enrollment_declined"
+	}
+	if n.UserID == "" || n.UserID == actorID {
+		return nil
+	}
+
+	_, err = s.notify(ctx, n)
+	return err
+}
+
+// ProjectStatusChanged notifies the project's enrolled and waitlisted
+// volunteers of its new status and returns how many were notified
+func (s *Service) ProjectStatusChanged(ctx context.Context, projectID, status string) (int, error) {
+	ctx, span := tracing.Start(ctx, "notifications.ProjectStatusChanged")
+	defer span.End()
+
+	query := `
+		SELECT ve.volunteer_id, u.locale, p.name
+		FROM volunteer_enrollments ve
+		JOIN users u ON u.id = ve.volunteer_id
+		JOIN projects p ON p.id = ve.project_id
+		WHERE ve.project_id = $1 AND ve.status IN ('enrolled', 'waitlisted')
+	`
+	type recipient struct{ userID, locale, projectName string }
+	var recipients []recipient
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, query, projectID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		recipients = nil
+		for rows.Next() {
+			var r recipient
+			if err := rows.Scan(&r.userID, &r.locale, &r.projectName); err != nil {
+				return err
+			}
+			recipients = append(recipients, r)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get project volunteers: %w", err)
+	}
+
+	sent := 0
+	for _, r := range recipients {
+		_, err := s.notify(ctx, notice{
+			UserID:    r.userID,
+			Kind:      KindProjectStatusChanged,
+			Message:   "project_status_changed",
+			Args:      i18n.Args{"project": r.projectName, "status": statusLabel(r.locale, status)},
+			ProjectID: projectID,
+		})
+		if err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// statusLabel names a project status in locale, or returns it as it is
+// when it has no translation
+func statusLabel(locale, status string) string {
+	code := "project_status." + status
+	if label := i18n.T(locale, code, nil); label != code {
+		return label
+	}
+	return status
+}
+
+// NotifyMatches notifies the volunteers among matches whose combined score
+// reaches minScore that the project is looking for people like them. Each
+// volunteer hears about a project once, however often it is matched. It
+// returns how many were newly notified.
+func (s *Service) NotifyMatches(ctx context.Context, projectID string, matches []models.VolunteerMatch, minScore float64) (int, error) {
+	ctx, span := tracing.Start(ctx, "notifications.NotifyMatches")
+	defer span.End()
+
+	var projectName string
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRowContext(ctx, `SELECT name FROM projects WHERE id = $1`, projectID).Scan(&projectName)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	sent := 0
+	for _, m := range matches {
+		if m.CombinedScore < minScore {
+			continue
+		}
+		notified, err := s.notify(ctx, notice{
+			UserID:    m.VolunteerID,
+			Kind:      KindProjectMatch,
+			Message:   "project_match",
+			Args:      i18n.Args{"project": projectName},
+			ProjectID: projectID,
+			DedupeKey: "match:" + projectID,
+		})
+		if err != nil {
+			return sent, err
+		}
+		if notified {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// GetNotifications returns a page of the user's notifications, only unread
+// ones when unreadOnly is set, along with how many there are across all
+// pages
+func (s *Service) GetNotifications(ctx context.Context, userID string, unreadOnly bool, page pagination.Params) ([]models.Notification, int, error) {
+	ctx, span := tracing.Start(ctx, "notifications.GetNotifications")
+	defer span.End()
+
+	where := `
+		FROM notifications
+		WHERE user_id::text = $1
+		  AND (NOT $2 OR read_at IS NULL)
+	`
+	query := `
+		SELECT id, kind, title, body, project_id, enrollment_id, read_at, created_at` + where + `
+		ORDER BY ` + page.OrderBy + `
+		` + page.Window(3)
+
+	var list []models.Notification
+	var total int
+	err := database.WithReadRetry(func() error {
+		if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*)`+where, userID, unreadOnly).Scan(&total); err != nil {
+			return err
+		}
+
+		rows, err := s.db.QueryContext(ctx, query, append([]interface{}{userID, unreadOnly}, page.Args()...)...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		list = nil
+		for rows.Next() {
+			var n models.Notification
+			if err := rows.Scan(&n.ID, &n.Kind, &n.Title, &n.Body, &n.ProjectID, &n.EnrollmentID, &n.ReadAt, &n.CreatedAt); err != nil {
+				return err
+			}
+			list = append(list, n)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	return list, total, nil
+}
+
+// MarkRead marks one of the user's notifications read, keeping the time it
+// was first read, and returns it
+func (s *Service) MarkRead(ctx context.Context, userID, notificationID string) (*models.Notification, error) {
+	ctx, span := tracing.Start(ctx, "notifications.MarkRead")
+	defer span.End()
+
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, NOW())
+		WHERE id::text = $1 AND user_id::text = $2
+		RETURNING id, kind, title, body, project_id, enrollment_id, read_at, created_at
+	`
+	var n models.Notification
+	err := database.WithWriteGuard(func() error {
+		return s.db.QueryRowContext(ctx, query, notificationID, userID).Scan(
+			&n.ID, &n.Kind, &n.Title, &n.Body, &n.ProjectID, &n.EnrollmentID, &n.ReadAt, &n.CreatedAt,
+		)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrNotificationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+
+	return &n, nil
+}
+
+// MarkAllRead marks every unread notification of the user read and returns
+// how many there were
+func (s *Service) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "notifications.MarkAllRead")
+	defer span.End()
+
+	var n int64
+	err := database.WithWriteGuard(func() error {
+		result, err := s.db.ExecContext(ctx, `
+			UPDATE notifications
+			SET read_at = NOW()
+			WHERE user_id::text = $1 AND read_at IS NULL
+		`, userID)
+		if err != nil {
+			return err
+		}
+		n, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+
+	return n, nil
+}
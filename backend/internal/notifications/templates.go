@@ -0,0 +1,389 @@
+package notifications
+
+import (
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/i18n"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+// OrganizationInvitation holds the data rendered into an invitation email.
+// InviterName may be empty when the inviter is unknown.
+type OrganizationInvitation struct {
+	To               string
+	Locale           string
+	InviterName      string
+	OrganizationName string
+	Role             string
+	AcceptURL        string
+	ExpiresAt        time.Time
+}
+
+// RenderOrganizationInvitation renders an invitation email with the
+// organization's branding applied
+func RenderOrganizationInvitation(data OrganizationInvitation, branding models.Branding) Message {
+	inviter := data.InviterName
+	if inviter == "" {
+		inviter = i18n.T(data.Locale, "email.invitation.inviter", i18n.Args{"organization": data.OrganizationName})
+	}
+	body := i18n.T(data.Locale, "email.invitation.body", i18n.Args{
+		"inviter":      inviter,
+		"organization": data.OrganizationName,
+		"role":         i18n.T(data.Locale, "role."+data.Role, nil),
+		"url":          data.AcceptURL,
+		"expires":      i18n.Date(data.Locale, data.ExpiresAt),
+	}) + "\n"
+
+	return Message{
+		To:      data.To,
+		Subject: i18n.T(data.Locale, "email.invitation.subject", i18n.Args{"organization": data.OrganizationName}),
+		Body:    withFooter(body, branding),
+	}
+}
+
+// withFooter appends the organization's email footer, if any
+func withFooter(body string, branding models.Branding) string {
+	footer := strings.TrimSpace(branding.EmailFooter)
+	if footer == "" {
+		return body
+	}
+	return body + "\n--\n" + footer + "\n"
+}
+
+// TeamBroadcast holds the data rendered into a team broadcast email
+type TeamBroadcast struct {
+	To          string
+	Locale      string
+	SenderName  string
+	TeamName    string
+	ProjectName string
+	Subject     string
+	Body        string
+}
+
+// RenderTeamBroadcast renders a team broadcast with the organization's
+// branding applied
+func RenderTeamBroadcast(data TeamBroadcast, branding models.Branding) Message {
+	body := data.Body + "\n\n" + i18n.T(data.Locale, "email.team_broadcast.footer", i18n.Args{
+		"sender":  data.SenderName,
+		"team":    data.TeamName,
+		"project": data.ProjectName,
+	}) + "\n"
+
+	return Message{
+		To:      data.To,
+		Subject: "[" + data.TeamName + "] " + data.Subject,
+		Body:    withFooter(body, branding),
+	}
+}
+
+// ShiftCoverage holds the data rendered into shift coverage emails.
+// ShiftName may be empty for untitled shifts.
+type ShiftCoverage struct {
+	To            string
+	Locale        string
+	ProjectName   string
+	ShiftName     string
+	StartsAt      time.Time
+	RequesterName string
+	ClaimerName   string
+	Note          string
+}
+
+func (data ShiftCoverage) args() i18n.Args {
+	shift := data.ShiftName
+	if shift == "" {
+		shift = i18n.T(data.Locale, "email.shift.unnamed", nil)
+	}
+	startsAt := data.StartsAt.UTC()
+	return i18n.Args{
+		"project":   data.ProjectName,
+		"shift":     shift,
+		"startsAt":  i18n.Date(data.Locale, startsAt) + " " + startsAt.Format("15:04 MST"),
+		"requester": data.RequesterName,
+		"claimer":   data.ClaimerName,
+	}
+}
+
+// RenderShiftCoverageRequest renders the email asking an eligible volunteer
+// to cover a shift
+func RenderShiftCoverageRequest(data ShiftCoverage, branding models.Branding) Message {
+	args := data.args()
+	body := i18n.T(data.Locale, "email.shift_coverage.body", args) + "\n"
+	if data.Note != "" {
+		body += "\n" + data.Note + "\n"
+	}
+	body += "\n" + i18n.T(data.Locale, "email.shift_coverage.first_claim", nil) + "\n"
+
+	return Message{
+		To:      data.To,
+		Subject: i18n.T(data.Locale, "email.shift_coverage.subject", args),
+		Body:    withFooter(body, branding),
+	}
+}
+
+// RenderShiftCovered renders the email telling a requester their shift has
+// been covered
+func RenderShiftCovered(data ShiftCoverage, branding models.Branding) Message {
+	args := data.args()
+	body := i18n.T(data.Locale, "email.shift_covered.body", args) + "\n"
+
+	return Message{
+		To:      data.To,
+		Subject: i18n.T(data.Locale, "email.shift_covered.subject", args),
+		Body:    withFooter(body, branding),
+	}
+}
+
+// EmailVerification holds the data rendered into the email asking a new
+// volunteer to verify their address
+type EmailVerification struct {
+	To        string
+	Locale    string
+	Name      string
+	VerifyURL string
+	ExpiresAt time.Time
+}
+
+// RenderEmailVerification renders the email with a new volunteer's
+// verification link
+func RenderEmailVerification(data EmailVerification) Message {
+	body := greeting(data.Locale, data.Name) + i18n.T(data.Locale, "email.email_verification.body", i18n.Args{
+		"url":     data.VerifyURL,
+		"expires": i18n.Date(data.Locale, data.ExpiresAt),
+	}) + "\n"
+
+	return Message{
+		To:      data.To,
+		Subject: i18n.T(data.Locale, "email.email_verification.subject", nil),
+		Body:    body,
+	}
+}
+
+// PasswordReset holds the data rendered into a password reset email
+type PasswordReset struct {
+	To       string
+	Locale   string
+	Name     string
+	ResetURL string
+}
+
+// RenderPasswordReset renders the email with a password reset link
+func RenderPasswordReset(data PasswordReset) Message {
+	body := greeting(data.Locale, data.Name) + i18n.T(data.Locale, "email.password_reset.body", i18n.Args{
+		"url": data.ResetURL,
+	}) + "\n"
+
+	return Message{
+		To:      data.To,
+		Subject: i18n.T(data.Locale, "email.password_reset.subject", nil),
+		Body:    body,
+	}
+}
+
+// HourMilestone holds the data rendered into a milestone email
+type HourMilestone struct {
+	To            string
+	Locale        string
+	VolunteerName string
+	Hours         int
+}
+
+// RenderHourMilestone renders the email congratulating a volunteer on
+// reaching an hours milestone
+func RenderHourMilestone(data HourMilestone) Message {
+	args := i18n.Args{"hours": data.Hours}
+	body := greeting(data.Locale, data.VolunteerName) +
+		i18n.T(data.Locale, "email.hour_milestone.body", args) + "\n"
+
+	return Message{
+		To:      data.To,
+		Subject: i18n.T(data.Locale, "email.hour_milestone.subject", args),
+		Body:    body,
+	}
+}
+
+// InvitationReminder holds the data rendered into the email reminding a
+// volunteer of a project invitation about to expire
+type InvitationReminder struct {
+	To            string
+	Locale        string
+	VolunteerName string
+	ProjectName   string
+	ExpiresAt     time.Time
+}
+
+// RenderInvitationReminder renders the email reminding a volunteer to
+// answer a project invitation before it expires
+func RenderInvitationReminder(data InvitationReminder) Message {
+	args := i18n.Args{"project": data.ProjectName, "expires": i18n.Date(data.Locale, data.ExpiresAt)}
+	body := greeting(data.Locale, data.VolunteerName) +
+		i18n.T(data.Locale, "email.invitation_reminder.body", args) + "\n"
+
+	return Message{
+		To:      data.To,
+		Subject: i18n.T(data.Locale, "email.invitation_reminder.subject", args),
+		Body:    body,
+	}
+}
+
+// greeting opens a personal email
+func greeting(locale, name string) string {
+	return i18n.T(locale, "email.greeting", i18n.Args{"name": name}) + "\n\n"
+}
+
+// ModerationNotice holds the data rendered into moderation emails
+type ModerationNotice struct {
+	To           string
+	Locale       string
+	Name         string
+	ContentType  string
+	ContentTitle string
+	Note         string
+	// Actioned is set when the reported content was hidden or its owner
+	// suspended
+	Actioned bool
+}
+
+func (data ModerationNotice) args() i18n.Args {
+	return i18n.Args{
+		"content": i18n.T(data.Locale, "content."+data.ContentType, nil),
+		"title":   data.ContentTitle,
+	}
+}
+
+// RenderContentHidden renders the email telling an owner a moderator hid
+// their content
+func RenderContentHidden(data ModerationNotice) Message {
+	args := data.args()
+	body := greeting(data.Locale, data.Name) + i18n.T(data.Locale, "email.content_hidden.body", args) + "\n"
+	if data.Note != "" {
+		body += "\n" + data.Note + "\n"
+	}
+
+	return Message{
+		To:      data.To,
+		Subject: i18n.T(data.Locale, "email.content_hidden.subject", args),
+		Body:    body,
+	}
+}
+
+// RenderAccountSuspended renders the email telling a user their account was
+// suspended
+func RenderAccountSuspended(data ModerationNotice) Message {
+	body := greeting(data.Locale, data.Name) + i18n.T(data.Locale, "email.account_suspended.body", data.args()) + "\n"
+	if data.Note != "" {
+		body += "\n" + data.Note + "\n"
+	}
+
+	return Message{
+		To:      data.To,
+		Subject: i18n.T(data.Locale, "email.account_suspended.subject", nil),
+		Body:    body,
+	}
+}
+
+// RenderReportReviewed renders the email thanking a reporter once a
+// moderator has reviewed their report
+func RenderReportReviewed(data ModerationNotice) Message {
+	args := data.args()
+	args["outcome"] = i18n.T(data.Locale, "email.report_reviewed.dismissed", nil)
+	if data.Actioned {
+		args["outcome"] = i18n.T(data.Locale, "email.report_reviewed.actioned", nil)
+	}
+	body := greeting(data.Locale, data.Name) + i18n.T(data.Locale, "email.report_reviewed.body", args) + "\n"
+
+	return Message{
+		To:      data.To,
+		Subject: i18n.T(data.Locale, "email.report_reviewed.subject", nil),
+		Body:    body,
+	}
+}
+
+// QuarantineAlert holds the data rendered into the email telling platform
+// admins an upload was quarantined
+type QuarantineAlert struct {
+	To         string
+	Locale     string
+	Name       string
+	Kind       string
+	SubjectID  string
+	UploadedBy string
+	Threat     string
+	Key        string
+}
+
+// RenderQuarantineAlert renders the email alerting a platform admin that
+// the malware scanner flagged an upload
+func RenderQuarantineAlert(data QuarantineAlert) Message {
+	uploader := data.UploadedBy
+	if uploader == "" {
+		uploader = i18n.T(data.Locale, "email.quarantine.unknown_uploader", nil)
+	}
+	args := i18n.Args{
+		"kind":     data.Kind,
+		"id":       data.SubjectID,
+		"threat":   data.Threat,
+		"uploader": uploader,
+		"key":      data.Key,
+	}
+	body := greeting(data.Locale, data.Name) +
+		i18n.T(data.Locale, "email.quarantine.body", args) + "\n\n" +
+		i18n.T(data.Locale, "email.quarantine.threat", args) + "\n" +
+		i18n.T(data.Locale, "email.quarantine.id", args) + "\n" +
+		i18n.T(data.Locale, "email.quarantine.uploaded_by", args) + "\n" +
+		i18n.T(data.Locale, "email.quarantine.key", args) + "\n\n" +
+		i18n.T(data.Locale, "email.quarantine.action", nil) + "\n"
+
+	return Message{
+		To:      data.To,
+		Subject: i18n.T(data.Locale, "email.quarantine.subject", args),
+		Body:    body,
+	}
+}
+
+// DigestProject is one project in a coordinator digest
+type DigestProject struct {
+	Name    string
+	Pending int
+	// OldestDays is how many days the oldest pending request has waited
+	OldestDays int
+}
+
+// CoordinatorDigest holds the data rendered into the weekly email listing
+// a coordinator's pending enrollment requests
+type CoordinatorDigest struct {
+	To       string
+	Locale   string
+	Name     string
+	Projects []DigestProject
+}
+
+// RenderCoordinatorDigest renders the digest of enrollment requests
+// waiting for a coordinator
+func RenderCoordinatorDigest(data CoordinatorDigest) Message {
+	var body strings.Builder
+	body.WriteString(greeting(data.Locale, data.Name))
+	body.WriteString(i18n.T(data.Locale, "email.coordinator_digest.intro", nil) + "\n\n")
+	total := 0
+	for _, p := range data.Projects {
+		total += p.Pending
+		body.WriteString(i18n.T(data.Locale, "email.coordinator_digest.project", i18n.Args{
+			"project": p.Name,
+			"pending": p.Pending,
+			"days":    p.OldestDays,
+		}) + "\n")
+	}
+	body.WriteString("\n" + i18n.T(data.Locale, "email.coordinator_digest.outro", nil) + "\n")
+
+	subject := i18n.T(data.Locale, "email.coordinator_digest.subject.other", i18n.Args{"count": total})
+	if total == 1 {
+		subject = i18n.T(data.Locale, "email.coordinator_digest.subject.one", nil)
+	}
+	return Message{
+		To:      data.To,
+		Subject: subject,
+		Body:    body.String(),
+	}
+}
@@ -0,0 +1,257 @@
+// Package openapi describes the API as an OpenAPI 3 document. Paths come
+// from the router, so every registered route is listed, and schemas are
+// generated from the Go types handlers decode and write, including the
+// constraints in their validate tags, so the document follows the code.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/validate"
+	"github.com/gorilla/mux"
+)
+
+// Operation describes one route. Request and Response are zero values of
+// the types the handler decodes and writes, or nil when it has no body.
+type Operation struct {
+	Summary  string
+	Request  interface{}
+	Response interface{}
+	// Status is the success status, 200 when zero
+	Status int
+	// Paged wraps Response, the type of one item, in the page envelope of
+	// list endpoints
+	Paged bool
+}
+
+// Info names the API in the document
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Document is an OpenAPI 3.0 document
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components components                       `json:"components"`
+	Security   []map[string][]string            `json:"security"`
+}
+
+type components struct {
+	Schemas         map[string]*Schema                `json:"schemas"`
+	SecuritySchemes map[string]map[string]interface{} `json:"securitySchemes"`
+}
+
+type operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags"`
+	Parameters  []parameter           `json:"parameters,omitempty"`
+	RequestBody *body                 `json:"requestBody,omitempty"`
+	Responses   map[string]*response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type body struct {
+	Required bool                          `json:"required"`
+	Content  map[string]map[string]*Schema `json:"content"`
+}
+
+type response struct {
+	Description string                        `json:"description"`
+	Content     map[string]map[string]*Schema `json:"content,omitempty"`
+}
+
+// validationBody is the body of 422 responses
+type validationBody struct {
+	apierror.Body
+	Fields validate.Errors `json:"fields"`
+}
+
+// Security schemes
+const (
+	bearerScheme = "bearerAuth"
+	apiKeyScheme = "apiKey"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// Spec builds and serves the document
+type Spec struct {
+	info       Info
+	operations map[string]Operation
+	public     func(method, template string) bool
+
+	mu  sync.RWMutex
+	doc []byte
+}
+
+// NewSpec describes routes with operations, keyed by method and route
+// template like "GET /api/users/{id}". public reports the routes callers
+// may use without credentials.
+func NewSpec(info Info, operations map[string]Operation, public func(method, template string) bool) *Spec {
+	return &Spec{info: info, operations: operations, public: public}
+}
+
+// Build generates the document from the routes registered on router under
+// prefix. Call it once every route is registered. It returns the routes
+// without an entry in operations, which are listed with only their path.
+func (s *Spec) Build(router *mux.Router, prefix string) ([]string, error) {
+	gen := newGenerator()
+	doc := Document{
+		OpenAPI: "3.0.3",
+		Info:    s.info,
+		Paths:   map[string]map[string]*operation{},
+		Components: components{
+			Schemas: gen.schemas,
+			SecuritySchemes: map[string]map[string]interface{}{
+				bearerScheme: {"type": "http", "scheme": "bearer"},
+				apiKeyScheme: {"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+		Security: []map[string][]string{{bearerScheme: {}}, {apiKeyScheme: {}}},
+	}
+	errorSchema := gen.component("Error", reflect.TypeOf(apierror.Body{}))
+	validationSchema := gen.component("ValidationError", reflect.TypeOf(validationBody{}))
+
+	var missing []string
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil || !strings.HasPrefix(template, prefix) {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		path := pathParamPattern.ReplaceAllString(template, "{$1}")
+
+		for _, method := range methods {
+			key := method + " " + template
+			op, ok := s.operations[key]
+			if !ok {
+				missing = append(missing, key)
+			}
+
+			o := &operation{
+				OperationID: operationID(method, strings.TrimPrefix(path, prefix)),
+				Summary:     op.Summary,
+				Tags:        []string{tag(path, prefix)},
+				Responses:   map[string]*response{},
+			}
+			for _, m := range pathParamPattern.FindAllStringSubmatch(template, -1) {
+				o.Parameters = append(o.Parameters, parameter{
+					Name: m[1], In: "path", Required: true, Schema: &Schema{Type: "string"},
+				})
+			}
+			if op.Paged {
+				o.Parameters = append(o.Parameters, pageParameters()...)
+			}
+			if s.public != nil && s.public(method, template) {
+				o.Security = []map[string][]string{{}}
+			}
+
+			if op.Request != nil {
+				o.RequestBody = &body{Required: true, Content: jsonContent(gen.schema(op.Request))}
+				o.Responses["422"] = &response{Description: "The body failed validation", Content: jsonContent(validationSchema)}
+			}
+
+			status := op.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			success := &response{Description: http.StatusText(status)}
+			switch {
+			case op.Paged:
+				success.Content = jsonContent(gen.page(op.Response))
+			case op.Response != nil:
+				success.Content = jsonContent(gen.schema(op.Response))
+			}
+			o.Responses[fmt.Sprint(status)] = success
+			o.Responses["default"] = &response{Description: "Error", Content: jsonContent(errorSchema)}
+
+			if doc.Paths[path] == nil {
+				doc.Paths[path] = map[string]*operation{}
+			}
+			doc.Paths[path][strings.ToLower(method)] = o
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.doc = encoded
+	s.mu.Unlock()
+
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// ServeJSON writes the document
+func (s *Spec) ServeJSON(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	doc := s.doc
+	s.mu.RUnlock()
+	if doc == nil {
+		apierror.Write(w, http.StatusServiceUnavailable, "API document is not ready")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(doc)
+}
+
+func jsonContent(schema *Schema) map[string]map[string]*Schema {
+	return map[string]map[string]*Schema{"application/json": {"schema": schema}}
+}
+
+func pageParameters() []parameter {
+	return []parameter{
+		{Name: "limit", In: "query", Description: "Page size, at most 200", Schema: &Schema{Type: "integer", Minimum: float(1), Maximum: float(200)}},
+		{Name: "cursor", In: "query", Description: "nextCursor of the previous page", Schema: &Schema{Type: "string"}},
+		{Name: "offset", In: "query", Description: "Items to skip; cannot be combined with cursor", Schema: &Schema{Type: "integer", Minimum: float(0)}},
+		{Name: "sort", In: "query", Description: "Comma-separated fields, each prefixed with - for descending", Schema: &Schema{Type: "string"}},
+	}
+}
+
+// operationID names an operation after its method and path, e.g.
+// getProjectsIdEnrollments
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-' || r == '.' || r == '_'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// tag groups operations by the first segment of their path after prefix
+func tag(path, prefix string) string {
+	first, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(path, prefix), "/"), "/")
+	return first
+}
@@ -0,0 +1,268 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Schema is an OpenAPI 3.0 schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawType       = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// generator turns Go types into schemas, collecting named structs as
+// components referenced by $ref
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	return &generator{schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+// schema returns the schema of v's type
+func (g *generator) schema(v interface{}) *Schema {
+	return g.typeSchema(reflect.TypeOf(v))
+}
+
+// page returns the schema of a page of items of item's type
+func (g *generator) page(item interface{}) *Schema {
+	items := &Schema{Type: "array", Items: &Schema{}}
+	if item != nil {
+		items.Items = g.schema(item)
+	}
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"items":      items,
+			"total":      {Type: "integer"},
+			"limit":      {Type: "integer"},
+			"offset":     {Type: "integer"},
+			"nextCursor": {Type: "string"},
+		},
+		Required: []string{"items", "total", "limit", "offset"},
+	}
+}
+
+func (g *generator) typeSchema(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawType, t.Implements(marshalerType):
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.ref(t)
+	}
+	return &Schema{}
+}
+
+// ref collects a named struct as a component and returns a reference to it
+func (g *generator) ref(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		return g.component(g.componentName(t), t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// component collects t as the component called name
+func (g *generator) component(name string, t reflect.Type) *Schema {
+	g.names[t] = name
+	// Reserve the name first so recursive types end
+	g.schemas[name] = &Schema{}
+	*g.schemas[name] = *g.structSchema(t)
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName names models after their type and other packages' types
+// after their package too, e.g. Project and AnalyticsSummary
+func (g *generator) componentName(t reflect.Type) string {
+	pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+	name := t.Name()
+	if pkg != "models" {
+		name = string(unicode.ToUpper(rune(pkg[0]))) + pkg[1:] + name
+	}
+	for base, n := name, 2; g.schemas[name] != nil; n++ {
+		name = base + strconv.Itoa(n)
+	}
+	return name
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(s, t, isRequest(t))
+	return s
+}
+
+// addFields adds t's fields to s. Fields of responses are required unless
+// they may be omitted; fields of requests when their validate tags say so.
+func (g *generator) addFields(s *Schema, t reflect.Type, request bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		// Embedded structs without a JSON name contribute their fields
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft, request)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		field := g.typeSchema(f.Type)
+		if f.Type.Kind() == reflect.Ptr && field.Ref == "" {
+			field.Nullable = true
+		}
+		required := applyRules(field, f.Tag.Get("validate"))
+		if required || (!request && f.Type.Kind() != reflect.Ptr && !strings.Contains(opts, "omitempty")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = field
+	}
+}
+
+// isRequest reports whether t is a request body
+func isRequest(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("validate") != "" {
+			return true
+		}
+	}
+	return strings.HasSuffix(t.Name(), "Request")
+}
+
+// applyRules adds the constraints of a validate tag to s, and reports
+// whether the field is required
+func applyRules(s *Schema, tag string) bool {
+	if tag == "" {
+		return false
+	}
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "min", "max", "gt":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				continue
+			}
+			applyBound(s, name, n)
+		case "email":
+			s.Format = "email"
+		case "lat":
+			s.Minimum, s.Maximum = float(-90), float(90)
+		case "lon":
+			s.Minimum, s.Maximum = float(-180), float(180)
+		case "oneof":
+			s.Enum = strings.Fields(arg)
+		case "date":
+			s.Format = "date"
+		case "clock":
+			s.Pattern = `^\d{2}:\d{2}$`
+		case "hexcolor":
+			s.Pattern = `^#[0-9a-fA-F]{6}$`
+		case "url":
+			s.Format = "uri"
+		}
+	}
+	return required
+}
+
+func applyBound(s *Schema, rule string, n float64) {
+	switch {
+	case s.Type == "string" && rule == "min":
+		s.MinLength = integer(n)
+	case s.Type == "string" && rule == "max":
+		s.MaxLength = integer(n)
+	case s.Type == "array" && rule == "min":
+		s.MinItems = integer(n)
+	case s.Type == "array" && rule == "max":
+		s.MaxItems = integer(n)
+	case rule == "min":
+		s.Minimum = float(n)
+	case rule == "max":
+		s.Maximum = float(n)
+	case rule == "gt":
+		s.Minimum, s.ExclusiveMinimum = float(n), true
+	}
+}
+
+func integer(n float64) *int {
+	i := int(n)
+	return &i
+}
+
+func float(n float64) *float64 {
+	return &n
+}
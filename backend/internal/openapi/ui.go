@@ -0,0 +1,34 @@
+package openapi
+
+import (
+	"html/template"
+	"net/http"
+)
+
+// swaggerUIVersion pins the swagger-ui-dist release the docs page loads
+const swaggerUIVersion = "5.17.14"
+
+var uiPage = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui-bundle.js"></script>
+<script>
+SwaggerUIBundle({url: {{.URL}}, dom_id: "#swagger-ui", persistAuthorization: true});
+</script>
+</body>
+</html>
+`))
+
+// UI serves Swagger UI for the document served at url
+func (s *Spec) UI(url string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		uiPage.Execute(w, struct{ Title, Version, URL string }{s.info.Title, swaggerUIVersion, url})
+	}
+}
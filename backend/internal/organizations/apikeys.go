@@ -0,0 +1,204 @@
+package organizations
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/apikeys"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrInvalidAPIKeyScope = errors.New("scopes must be one or more of projects:read, projects:write, enrollments:write, volunteers:write")
+	ErrAPIKeyNameRequired = errors.New("api key name is required")
+	ErrInvalidAPIKeyQuota = errors.New("api key quotas must be positive")
+)
+
+// apiKeyPrefix marks Civic Weave keys so they are recognizable in partner configs and leaks
+const apiKeyPrefix = "cwk_"
+
+var validAPIKeyScopes = map[string]bool{
+	models.APIKeyScopeProjectsRead:     true,
+	models.APIKeyScopeProjectsWrite:    true,
+	models.APIKeyScopeEnrollmentsWrite: true,
+	models.APIKeyScopeVolunteersWrite:  true,
+}
+
+const apiKeyColumns = `id, organization_id, name, key_prefix, scopes, daily_quota, monthly_quota, created_by, created_at, last_used_at, revoked_at`
+
+func scanAPIKey(scanner interface{ Scan(...interface{}) error }, key *models.APIKey) error {
+	return scanner.Scan(
+		&key.ID,
+		&key.OrganizationID,
+		&key.Name,
+		&key.KeyPrefix,
+		pq.Array(&key.Scopes),
+		&key.DailyQuota,
+		&key.MonthlyQuota,
+		&key.CreatedBy,
+		&key.CreatedAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+	)
+}
+
+// CreateAPIKey issues a key for the organization. The raw key is only
+// available in the response; only its hash is stored. Only admins and owners
+// can manage keys.
+func (s *Service) CreateAPIKey(orgID, createdBy string, req models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrAPIKeyNameRequired
+	}
+	if len(req.Scopes) == 0 {
+		return nil, ErrInvalidAPIKeyScope
+	}
+	for _, scope := range req.Scopes {
+		if !validAPIKeyScopes[scope] {
+			return nil, ErrInvalidAPIKeyScope
+		}
+	}
+	if (req.DailyQuota != nil && *req.DailyQuota <= 0) || (req.MonthlyQuota != nil && *req.MonthlyQuota <= 0) {
+		return nil, ErrInvalidAPIKeyQuota
+	}
+
+	if err := s.requireAdmin(orgID, createdBy); err != nil {
+		return nil, err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	raw := apiKeyPrefix + token
+
+	query := `
+		INSERT INTO organization_api_keys (organization_id, name, key_prefix, key_hash, scopes, daily_quota, monthly_quota, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + apiKeyColumns
+
+	resp := models.CreateAPIKeyResponse{Key: raw}
+	err = database.WithWriteGuard(func() error {
+		row := s.db.QueryRow(query, orgID, name, raw[:len(apiKeyPrefix)+8], hashToken(raw), pq.Array(req.Scopes), req.DailyQuota, req.MonthlyQuota, createdBy)
+		return scanAPIKey(row, &resp.APIKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetAPIKeys lists an organization's keys, including revoked ones
+func (s *Service) GetAPIKeys(orgID, requestedBy string) ([]models.APIKey, error) {
+	if err := s.requireAdmin(orgID, requestedBy); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM organization_api_keys
+		WHERE organization_id = $1
+		ORDER BY created_at DESC
+	`
+
+	var keys []models.APIKey
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, orgID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		keys = nil
+		for rows.Next() {
+			var key models.APIKey
+			if err := scanAPIKey(rows, &key); err != nil {
+				return err
+			}
+			keys = append(keys, key)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey permanently disables a key
+func (s *Service) RevokeAPIKey(orgID, keyID, revokedBy string) error {
+	if err := s.requireAdmin(orgID, revokedBy); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE organization_api_keys
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND organization_id = $2 AND revoked_at IS NULL
+	`
+
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(query, keyID, orgID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey looks up an active key and records its use.
+// It satisfies apikeys.Authenticate.
+func (s *Service) AuthenticateAPIKey(raw string) (*models.APIKey, error) {
+	if !strings.HasPrefix(raw, apiKeyPrefix) {
+		return nil, apikeys.ErrInvalidKey
+	}
+
+	query := `
+		UPDATE organization_api_keys
+		SET last_used_at = CURRENT_TIMESTAMP
+		WHERE key_hash = $1 AND revoked_at IS NULL
+		RETURNING ` + apiKeyColumns
+
+	var key models.APIKey
+	err := database.WithWriteGuard(func() error {
+		return scanAPIKey(s.db.QueryRow(query, hashToken(raw)), &key)
+	})
+	if err == sql.ErrNoRows {
+		return nil, apikeys.ErrInvalidKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// requireAdmin checks that the user is an admin or owner of the organization
+func (s *Service) requireAdmin(orgID, userID string) error {
+	role, err := s.GetMemberRole(orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !RoleAtLeast(role, models.OrgRoleAdmin) {
+		return ErrInsufficientRole
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package organizations
+
+import (
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+// hoursReportQuery totals hours per volunteer and project within the range;
+// the filter is a condition on $1 over volunteer_hours vh and projects p
+func hoursReportQuery(filter string) string {
+	return `
+		SELECT vh.volunteer_id, u.name, vh.project_id, p.name, COUNT(*), SUM(vh.hours)
+		FROM volunteer_hours vh
+		JOIN users u ON u.id = vh.volunteer_id
+		JOIN projects p ON p.id = vh.project_id
+		WHERE ` + filter + `
+		  AND vh.worked_on BETWEEN $2::date AND $3::date
+		GROUP BY vh.volunteer_id, u.name, vh.project_id, p.name
+		ORDER BY u.name, p.name
+	`
+}
+
+// GetVolunteerHoursReport totals a volunteer's hours per project. Volunteers
+// can view their own report and platform admins anyone's. When tenantID is
+// set only the tenant's projects are included.
+func (s *Service) GetVolunteerHoursReport(volunteerID, requestedBy, tenantID string, from, to time.Time) (*models.HoursReport, error) {
+	if requestedBy != volunteerID {
+		isAdmin, err := s.IsPlatformAdmin(requestedBy)
+		if err != nil {
+			return nil, err
+		}
+		if !isAdmin {
+			return nil, ErrInsufficientRole
+		}
+	}
+
+	query := hoursReportQuery(`vh.volunteer_id = $1 AND ($4 = '' OR p.organization_id = NULLIF($4, '')::uuid)`)
+	return s.hoursReport(query, from, to, volunteerID, tenantID)
+}
+
+// GetProjectHoursReport totals a project's hours per volunteer. Only people
+// who can manage the project can view it.
+func (s *Service) GetProjectHoursReport(projectID, requestedBy string, from, to time.Time) (*models.HoursReport, error) {
+	allowed, err := s.CanManageProject(requestedBy, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrInsufficientRole
+	}
+
+	return s.hoursReport(hoursReportQuery(`vh.project_id = $1`), from, to, projectID)
+}
+
+// GetOrganizationHoursReport totals hours per volunteer and project across
+// the organization. Only admins and owners can view it.
+func (s *Service) GetOrganizationHoursReport(orgID, requestedBy string, from, to time.Time) (*models.HoursReport, error) {
+	if err := s.requireAdmin(orgID, requestedBy); err != nil {
+		return nil, err
+	}
+
+	return s.hoursReport(hoursReportQuery(`p.organization_id = $1`), from, to, orgID)
+}
+
+// hoursReport runs a hoursReportQuery with the subject ID as $1, the range
+// as $2 and $3, and any further arguments after
+func (s *Service) hoursReport(query string, from, to time.Time, subjectID string, extra ...interface{}) (*models.HoursReport, error) {
+	fromDate := from.Format(reportDateLayout)
+	toDate := to.Format(reportDateLayout)
+	args := append([]interface{}{subjectID, fromDate, toDate}, extra...)
+
+	report := models.HoursReport{From: fromDate, To: toDate}
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		report.TotalHours = 0
+		report.Rows = []models.HoursReportRow{}
+		for rows.Next() {
+			var row models.HoursReportRow
+			err := rows.Scan(
+				&row.VolunteerID,
+				&row.VolunteerName,
+				&row.ProjectID,
+				&row.ProjectName,
+				&row.Entries,
+				&row.Hours,
+			)
+			if err != nil {
+				return err
+			}
+			report.TotalHours += row.Hours
+			report.Rows = append(report.Rows, row)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,351 @@
+package organizations
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/notifications"
+)
+
+var (
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationExpired  = errors.New("invitation expired")
+	ErrInvitationPending  = errors.New("a pending invitation already exists for this email")
+	ErrNameRequired       = errors.New("name is required to create an account")
+)
+
+// InvitationTTL is how long an emailed invitation stays valid
+const InvitationTTL = 7 * 24 * time.Hour
+
+const invitationColumns = `id, organization_id, email, role, status, invited_by, expires_at, created_at, accepted_at`
+
+func scanInvitation(scanner interface{ Scan(...interface{}) error }, inv *models.OrganizationInvitation) error {
+	return scanner.Scan(
+		&inv.ID,
+		&inv.OrganizationID,
+		&inv.Email,
+		&inv.Role,
+		&inv.Status,
+		&inv.InvitedBy,
+		&inv.ExpiresAt,
+		&inv.CreatedAt,
+		&inv.AcceptedAt,
+	)
+}
+
+// CreateInvitation records an emailed invitation and returns it along with
+// the raw token to put in the accept link. Only the token's hash is stored.
+// Only admins and owners can invite, and only owners can invite owners.
+func (s *Service) CreateInvitation(orgID, email, role, invitedBy string) (*models.OrganizationInvitation, string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !ValidRole(role) {
+		return nil, "", ErrInvalidRole
+	}
+
+	if err := s.requireInviter(orgID, invitedBy, role); err != nil {
+		return nil, "", err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := `
+		INSERT INTO organization_invitations (organization_id, email, role, token_hash, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + invitationColumns
+
+	var inv models.OrganizationInvitation
+	err = database.WithWriteGuard(func() error {
+		row := s.db.QueryRow(query, orgID, email, role, hashToken(token), invitedBy, time.Now().Add(InvitationTTL))
+		return scanInvitation(row, &inv)
+	})
+	if isUniqueViolation(err) {
+		return nil, "", ErrInvitationPending
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &inv, token, nil
+}
+
+// GetPendingInvitations lists invitations that can still be accepted
+func (s *Service) GetPendingInvitations(orgID string) ([]models.OrganizationInvitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM organization_invitations
+		WHERE organization_id = $1
+		  AND status = 'pending'
+		  AND expires_at > CURRENT_TIMESTAMP
+		ORDER BY created_at DESC
+	`
+
+	var invitations []models.OrganizationInvitation
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, orgID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		invitations = nil
+		for rows.Next() {
+			var inv models.OrganizationInvitation
+			if err := scanInvitation(rows, &inv); err != nil {
+				return err
+			}
+			invitations = append(invitations, inv)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// RevokeInvitation cancels a pending invitation
+func (s *Service) RevokeInvitation(orgID, invitationID, revokedBy string) error {
+	role, err := s.GetMemberRole(orgID, revokedBy)
+	if err != nil {
+		return err
+	}
+	if !RoleAtLeast(role, models.OrgRoleAdmin) {
+		return ErrInsufficientRole
+	}
+
+	query := `
+		UPDATE organization_invitations
+		SET status = 'revoked',
+		    revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND organization_id = $2 AND status = 'pending'
+	`
+
+	var result sql.Result
+	err = database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(query, invitationID, orgID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// GetInvitationByToken returns the pending invitation a token redeems,
+// ErrInvitationNotFound when there is none and ErrInvitationExpired when it
+// has run out
+func (s *Service) GetInvitationByToken(token string) (*models.OrganizationInvitation, error) {
+	var inv models.OrganizationInvitation
+	err := database.WithReadRetry(func() error {
+		row := s.db.QueryRow(`SELECT `+invitationColumns+` FROM organization_invitations WHERE token_hash = $1`, hashToken(token))
+		return scanInvitation(row, &inv)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status != "pending" {
+		return nil, ErrInvitationNotFound
+	}
+	if time.Now().After(inv.ExpiresAt) {
+		return nil, ErrInvitationExpired
+	}
+
+	return &inv, nil
+}
+
+// AcceptInvitation redeems an invitation token. In one transaction it finds
+// or creates the user for the invited email, grants the membership, and
+// marks the invitation accepted. name is only used when creating the user.
+func (s *Service) AcceptInvitation(token, name string) (*models.AcceptInvitationResponse, error) {
+	var resp models.AcceptInvitationResponse
+
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var inv models.OrganizationInvitation
+		row := tx.QueryRow(`SELECT `+invitationColumns+` FROM organization_invitations WHERE token_hash = $1 FOR UPDATE`, hashToken(token))
+		err = scanInvitation(row, &inv)
+		if err == sql.ErrNoRows {
+			return ErrInvitationNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if inv.Status != "pending" {
+			return ErrInvitationNotFound
+		}
+		if time.Now().After(inv.ExpiresAt) {
+			return ErrInvitationExpired
+		}
+
+		// Organization staff need at least the platform coordinator role
+		platformRole := "volunteer"
+		if RoleAtLeast(inv.Role, models.OrgRoleCoordinator) {
+			platformRole = "coordinator"
+		}
+
+		user := &resp.User
+		err = tx.QueryRow(`
+			SELECT id, email, name, role, profile_complete, created_at, updated_at
+			FROM users
+			WHERE LOWER(email) = $1
+			FOR UPDATE
+		`, inv.Email).Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.ProfileComplete, &user.CreatedAt, &user.UpdatedAt)
+		switch {
+		case err == sql.ErrNoRows:
+			if strings.TrimSpace(name) == "" {
+				return ErrNameRequired
+			}
+			err = tx.QueryRow(`
+				INSERT INTO users (email, name, role, profile_complete)
+				VALUES ($1, $2, $3, FALSE)
+				RETURNING id, email, name, role, profile_complete, created_at, updated_at
+			`, inv.Email, strings.TrimSpace(name), platformRole).Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.ProfileComplete, &user.CreatedAt, &user.UpdatedAt)
+			if err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case user.Role == "volunteer" && platformRole == "coordinator":
+			if _, err := tx.Exec("UPDATE users SET role = 'coordinator', updated_at = CURRENT_TIMESTAMP WHERE id = $1", user.ID); err != nil {
+				return err
+			}
+			user.Role = platformRole
+		}
+
+		m := &resp.Membership
+		err = tx.QueryRow(`
+			INSERT INTO organization_members (organization_id, user_id, role, status, invited_by, accepted_at)
+			VALUES ($1, $2, $3, 'active', $4, CURRENT_TIMESTAMP)
+			ON CONFLICT (organization_id, user_id)
+			DO UPDATE SET
+				role = EXCLUDED.role,
+				status = 'active',
+				invited_by = EXCLUDED.invited_by,
+				accepted_at = CURRENT_TIMESTAMP
+			RETURNING organization_id, user_id, role, status, invited_by, created_at, accepted_at
+		`, inv.OrganizationID, user.ID, inv.Role, inv.InvitedBy).Scan(&m.OrganizationID, &m.UserID, &m.Role, &m.Status, &m.InvitedBy, &m.CreatedAt, &m.AcceptedAt)
+		if err != nil {
+			return err
+		}
+		m.UserName = user.Name
+		m.UserEmail = user.Email
+
+		_, err = tx.Exec(`
+			UPDATE organization_invitations
+			SET status = 'accepted',
+			    accepted_by = $2,
+			    accepted_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+		`, inv.ID, user.ID)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// requireInviter checks that the inviter may grant role in the organization
+func (s *Service) requireInviter(orgID, invitedBy, role string) error {
+	inviterRole, err := s.GetMemberRole(orgID, invitedBy)
+	if err != nil {
+		return err
+	}
+	if !RoleAtLeast(inviterRole, models.OrgRoleAdmin) {
+		return ErrInsufficientRole
+	}
+	if role == models.OrgRoleOwner && inviterRole != models.OrgRoleOwner {
+		return ErrInsufficientRole
+	}
+	return nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// InvitationMessage renders the email for a freshly created invitation in
+// the invitee's language when they already have an account, and otherwise
+// in locale, the inviter's. acceptBaseURL is the frontend page that takes
+// the token as a query parameter.
+func (s *Service) InvitationMessage(inv *models.OrganizationInvitation, token, acceptBaseURL, locale string) (notifications.Message, error) {
+	org, err := s.GetOrganization(inv.OrganizationID)
+	if err != nil {
+		return notifications.Message{}, err
+	}
+	settings, err := s.GetSettings(inv.OrganizationID)
+	if err != nil {
+		return notifications.Message{}, err
+	}
+
+	var inviterName string
+	if inv.InvitedBy != nil {
+		err := database.WithReadRetry(func() error {
+			return s.db.QueryRow("SELECT name FROM users WHERE id = $1", *inv.InvitedBy).Scan(&inviterName)
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return notifications.Message{}, err
+		}
+	}
+
+	err = database.WithReadRetry(func() error {
+		return s.db.QueryRow("SELECT locale FROM users WHERE LOWER(email) = LOWER($1)", inv.Email).Scan(&locale)
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return notifications.Message{}, err
+	}
+
+	return notifications.RenderOrganizationInvitation(notifications.OrganizationInvitation{
+		To:               inv.Email,
+		Locale:           locale,
+		InviterName:      inviterName,
+		OrganizationName: org.Name,
+		Role:             inv.Role,
+		AcceptURL:        acceptBaseURL + "?token=" + url.QueryEscape(token),
+		ExpiresAt:        inv.ExpiresAt,
+	}, settings.Branding), nil
+}
@@ -0,0 +1,158 @@
+package organizations
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+var ErrInvalidReportRange = errors.New("from and to must be YYYY-MM-DD dates with from not after to")
+
+// defaultReportWindow is the range reported when no dates are given
+const defaultReportWindow = 30 * 24 * time.Hour
+
+const reportDateLayout = "2006-01-02"
+
+// ReportRange parses an inclusive YYYY-MM-DD range. A missing to defaults to
+// today and a missing from to 30 days before to.
+func ReportRange(from, to string) (time.Time, time.Time, error) {
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	if to != "" {
+		parsed, err := time.Parse(reportDateLayout, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidReportRange
+		}
+		end = parsed
+	}
+
+	start := end.Add(-defaultReportWindow)
+	if from != "" {
+		parsed, err := time.Parse(reportDateLayout, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidReportRange
+		}
+		start = parsed
+	}
+
+	if start.After(end) {
+		return time.Time{}, time.Time{}, ErrInvalidReportRange
+	}
+	return start, end, nil
+}
+
+// GetSummaryReport aggregates projects, enrollments and logged hours across
+// the organization for the inclusive date range. Only admins and owners can
+// view reports.
+func (s *Service) GetSummaryReport(orgID, requestedBy string, from, to time.Time) (*models.OrganizationSummaryReport, error) {
+	role, err := s.GetMemberRole(orgID, requestedBy)
+	if err != nil {
+		return nil, err
+	}
+	if !RoleAtLeast(role, models.OrgRoleAdmin) {
+		return nil, ErrInsufficientRole
+	}
+
+	// Projects running at any point in the range, with enrollments approved
+	// by the end of the range and hours worked within it
+	query := `
+		SELECT p.id, p.name, p.status, p.max_volunteers,
+		       (
+		           SELECT COUNT(*)
+		           FROM volunteer_enrollments ve
+		           WHERE ve.project_id = p.id
+		             AND ve.status IN ('enrolled', 'completed')
+		             AND ve.approved_at < $3::date + 1
+		       ),
+		       (
+		           SELECT COALESCE(SUM(vh.hours), 0)
+		           FROM volunteer_hours vh
+		           WHERE vh.project_id = p.id
+		             AND vh.worked_on BETWEEN $2::date AND $3::date
+		       )
+		FROM projects p
+		WHERE p.organization_id = $1
+		  AND p.status <> 'draft'
+		  AND (p.start_date IS NULL OR p.start_date <= $3::date)
+		  AND (p.end_date IS NULL OR p.end_date >= $2::date)
+		ORDER BY p.name
+	`
+
+	totalsQuery := `
+		SELECT
+			COUNT(DISTINCT ve.volunteer_id) FILTER (WHERE ve.approved_at < $3::date + 1),
+			COUNT(*) FILTER (WHERE ve.approved_at >= $2::date AND ve.approved_at < $3::date + 1)
+		FROM volunteer_enrollments ve
+		JOIN projects p ON p.id = ve.project_id
+		WHERE p.organization_id = $1
+		  AND ve.status IN ('enrolled', 'completed')
+	`
+
+	fromDate := from.Format(reportDateLayout)
+	toDate := to.Format(reportDateLayout)
+	report := models.OrganizationSummaryReport{
+		OrganizationID: orgID,
+		From:           fromDate,
+		To:             toDate,
+	}
+
+	err = database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, orgID, fromDate, toDate)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		report.Projects = nil
+		for rows.Next() {
+			var p models.ProjectSummaryReport
+			var maxVolunteers sql.NullInt64
+			if err := rows.Scan(&p.ProjectID, &p.ProjectName, &p.Status, &maxVolunteers, &p.Enrolled, &p.LoggedHours); err != nil {
+				return err
+			}
+			if maxVolunteers.Valid {
+				max := int(maxVolunteers.Int64)
+				p.MaxVolunteers = &max
+				p.FillRate = fillRate(p.Enrolled, max)
+			}
+			report.Projects = append(report.Projects, p)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		return s.db.QueryRow(totalsQuery, orgID, fromDate, toDate).Scan(&report.EnrolledVolunteers, &report.NewEnrollments)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var enrolled, capacity int
+	for _, p := range report.Projects {
+		if p.Status == "active" {
+			report.ActiveProjects++
+		}
+		report.LoggedHours += p.LoggedHours
+		if p.MaxVolunteers != nil {
+			enrolled += p.Enrolled
+			capacity += *p.MaxVolunteers
+		}
+	}
+	report.FillRate = fillRate(enrolled, capacity)
+	if report.Projects == nil {
+		report.Projects = []models.ProjectSummaryReport{}
+	}
+
+	return &report, nil
+}
+
+// fillRate is enrolled/capacity, or nil when there is no capacity to fill
+func fillRate(enrolled, capacity int) *float64 {
+	if capacity <= 0 {
+		return nil
+	}
+	rate := float64(enrolled) / float64(capacity)
+	return &rate
+}
@@ -0,0 +1,394 @@
+package organizations
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/tenant"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrSlugTaken            = errors.New("organization slug already taken")
+	ErrInvalidRole          = errors.New("invalid organization role")
+	ErrAlreadyMember        = errors.New("user is already a member or has a pending invite")
+	ErrInviteNotFound       = errors.New("invite not found")
+	ErrInsufficientRole     = errors.New("insufficient organization role")
+)
+
+// roleRank orders roles so checks can ask for "at least" a role
+var roleRank = map[string]int{
+	models.OrgRoleMember:      1,
+	models.OrgRoleCoordinator: 2,
+	models.OrgRoleAdmin:       3,
+	models.OrgRoleOwner:       4,
+}
+
+// ValidRole reports whether role is a known organization role
+func ValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// RoleAtLeast reports whether role grants the permissions of min
+func RoleAtLeast(role, min string) bool {
+	return roleRank[role] >= roleRank[min]
+}
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// CreateOrganization creates an organization owned by its creator
+func (s *Service) CreateOrganization(name, slug, description, createdBy string) (*models.Organization, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+
+	var org models.Organization
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		query := `
+			INSERT INTO organizations (name, slug, description, created_by)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, name, slug, COALESCE(description, ''), verification_status, verification_note, verified_at, created_by, created_at, updated_at
+		`
+		err = tx.QueryRow(query, name, slug, description, createdBy).Scan(
+			&org.ID,
+			&org.Name,
+			&org.Slug,
+			&org.Description,
+			&org.VerificationStatus,
+			&org.VerificationNote,
+			&org.VerifiedAt,
+			&org.CreatedBy,
+			&org.CreatedAt,
+			&org.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO organization_members (organization_id, user_id, role, status, accepted_at)
+			VALUES ($1, $2, 'owner', 'active', CURRENT_TIMESTAMP)
+		`, org.ID, createdBy)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if isUniqueViolation(err) {
+		return nil, ErrSlugTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &org, nil
+}
+
+func (s *Service) GetOrganization(orgID string) (*models.Organization, error) {
+	query := `
+		SELECT id, name, slug, COALESCE(description, ''), verification_status, verification_note, verified_at, created_by, created_at, updated_at
+		FROM organizations
+		WHERE id = $1
+	`
+
+	var org models.Organization
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, orgID).Scan(
+			&org.ID,
+			&org.Name,
+			&org.Slug,
+			&org.Description,
+			&org.VerificationStatus,
+			&org.VerificationNote,
+			&org.VerifiedAt,
+			&org.CreatedBy,
+			&org.CreatedAt,
+			&org.UpdatedAt,
+		)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrOrganizationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &org, nil
+}
+
+// ResolveTenant maps an organization ID or slug to the organization ID.
+// It satisfies tenant.Lookup.
+func (s *Service) ResolveTenant(key string) (string, error) {
+	query := `
+		SELECT id
+		FROM organizations
+		WHERE id::text = $1 OR slug = $1
+	`
+
+	var orgID string
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, key).Scan(&orgID)
+	})
+	if err == sql.ErrNoRows {
+		return "", tenant.ErrUnknownTenant
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return orgID, nil
+}
+
+// AddMember adds an active member directly, e.g. when a volunteer registers
+// through an organization's tenant. Existing memberships are left untouched.
+func (s *Service) AddMember(orgID, userID, role string) error {
+	if !ValidRole(role) {
+		return ErrInvalidRole
+	}
+
+	query := `
+		INSERT INTO organization_members (organization_id, user_id, role, status, accepted_at)
+		VALUES ($1, $2, $3, 'active', CURRENT_TIMESTAMP)
+		ON CONFLICT (organization_id, user_id) DO NOTHING
+	`
+
+	return database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(query, orgID, userID, role)
+		return err
+	})
+}
+
+// GetMembers lists active members and pending invites of an organization
+func (s *Service) GetMembers(orgID string) ([]models.OrganizationMember, error) {
+	query := `
+		SELECT om.organization_id, om.user_id, u.name, u.email, om.role, om.status,
+		       om.invited_by, om.created_at, om.accepted_at
+		FROM organization_members om
+		JOIN users u ON u.id = om.user_id
+		WHERE om.organization_id = $1
+		ORDER BY om.status, u.name
+	`
+
+	var members []models.OrganizationMember
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, orgID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		members = nil
+		for rows.Next() {
+			var m models.OrganizationMember
+			err := rows.Scan(
+				&m.OrganizationID,
+				&m.UserID,
+				&m.UserName,
+				&m.UserEmail,
+				&m.Role,
+				&m.Status,
+				&m.InvitedBy,
+				&m.CreatedAt,
+				&m.AcceptedAt,
+			)
+			if err != nil {
+				return err
+			}
+			members = append(members, m)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// GetMemberRole returns the user's role in an organization, or "" when the
+// user is not an active member
+func (s *Service) GetMemberRole(orgID, userID string) (string, error) {
+	query := `
+		SELECT role
+		FROM organization_members
+		WHERE organization_id = $1 AND user_id = $2 AND status = 'active'
+	`
+
+	var role string
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, orgID, userID).Scan(&role)
+	})
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return role, nil
+}
+
+// GetUserOrganizationIDs lists the organizations the user is an active
+// member of
+func (s *Service) GetUserOrganizationIDs(userID string) ([]string, error) {
+	query := `
+		SELECT organization_id
+		FROM organization_members
+		WHERE user_id = $1 AND status = 'active'
+		ORDER BY organization_id
+	`
+
+	var orgIDs []string
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, userID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		orgIDs = nil
+		for rows.Next() {
+			var orgID string
+			if err := rows.Scan(&orgID); err != nil {
+				return err
+			}
+			orgIDs = append(orgIDs, orgID)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return orgIDs, nil
+}
+
+// InviteMember records a pending membership for an existing user.
+// Only admins and owners can invite, and only owners can invite owners.
+func (s *Service) InviteMember(orgID, userID, role, invitedBy string) (*models.OrganizationMember, error) {
+	if !ValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+
+	if err := s.requireInviter(orgID, invitedBy, role); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO organization_members (organization_id, user_id, role, status, invited_by)
+		VALUES ($1, $2, $3, 'invited', $4)
+		RETURNING organization_id, user_id, role, status, invited_by, created_at, accepted_at
+	`
+
+	var m models.OrganizationMember
+	err := database.WithWriteGuard(func() error {
+		return s.db.QueryRow(query, orgID, userID, role, invitedBy).Scan(
+			&m.OrganizationID,
+			&m.UserID,
+			&m.Role,
+			&m.Status,
+			&m.InvitedBy,
+			&m.CreatedAt,
+			&m.AcceptedAt,
+		)
+	})
+	if isUniqueViolation(err) {
+		return nil, ErrAlreadyMember
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// AcceptInvite activates the user's pending membership
+func (s *Service) AcceptInvite(orgID, userID string) error {
+	query := `
+		UPDATE organization_members
+		SET status = 'active',
+		    accepted_at = CURRENT_TIMESTAMP
+		WHERE organization_id = $1 AND user_id = $2 AND status = 'invited'
+	`
+
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(query, orgID, userID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrInviteNotFound
+	}
+
+	return nil
+}
+
+// CanManageProject reports whether a user may edit a project and act on its
+// enrollments: platform admins, the project's coordinator, and coordinators,
+// admins and owners of the organization hosting the project
+func (s *Service) CanManageProject(userID, projectID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM users WHERE id = $1 AND role = 'admin'
+		) OR EXISTS (
+			SELECT 1 FROM projects WHERE id = $2 AND coordinator_id = $1
+		) OR EXISTS (
+			SELECT 1
+			FROM projects p
+			JOIN organization_members om ON om.organization_id = p.organization_id
+			WHERE p.id = $2
+			  AND om.user_id = $1
+			  AND om.status = 'active'
+			  AND om.role IN ('owner', 'admin', 'coordinator')
+		)
+	`
+
+	var allowed bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, userID, projectID).Scan(&allowed)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return allowed, nil
+}
+
+// CanCreateProjects reports whether a user may create projects for an organization
+func (s *Service) CanCreateProjects(userID, orgID string) (bool, error) {
+	role, err := s.GetMemberRole(orgID, userID)
+	if err != nil {
+		return false, err
+	}
+	return RoleAtLeast(role, models.OrgRoleCoordinator), nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,200 @@
+package organizations
+
+import (
+	"database/sql"
+	"errors"
+	"net/url"
+	"regexp"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+var ErrInvalidSettings = errors.New("invalid organization settings")
+
+const maxEmailFooterLength = 2000
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// SettingsError describes which setting failed validation
+type SettingsError struct {
+	Field   string
+	Message string
+}
+
+func (e *SettingsError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+func (e *SettingsError) Unwrap() error {
+	return ErrInvalidSettings
+}
+
+const settingsColumns = `
+	default_skill_weight, default_distance_weight, default_max_distance_km, show_volunteer_ratings,
+	allow_volunteer_requests, require_request_message, allow_self_signup,
+	email_footer, primary_color, secondary_color, logo_url,
+	updated_at
+`
+
+func scanSettings(row *sql.Row, settings *models.OrganizationSettings) error {
+	return row.Scan(
+		&settings.Matching.SkillWeight,
+		&settings.Matching.DistanceWeight,
+		&settings.Matching.MaxDistanceKm,
+		&settings.Matching.ShowRatings,
+		&settings.Enrollment.AllowVolunteerRequests,
+		&settings.Enrollment.RequireRequestMessage,
+		&settings.Enrollment.AllowSelfSignup,
+		&settings.Branding.EmailFooter,
+		&settings.Branding.PrimaryColor,
+		&settings.Branding.SecondaryColor,
+		&settings.Branding.LogoURL,
+		&settings.UpdatedAt,
+	)
+}
+
+// GetSettings returns an organization's settings, falling back to defaults
+// for organizations that never saved any
+func (s *Service) GetSettings(orgID string) (*models.OrganizationSettings, error) {
+	if _, err := s.GetOrganization(orgID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + settingsColumns + ` FROM organization_settings WHERE organization_id = $1`
+
+	settings := models.DefaultOrganizationSettings(orgID)
+	err := database.WithReadRetry(func() error {
+		return scanSettings(s.db.QueryRow(query, orgID), &settings)
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// GetSettingsForProject returns the settings of the organization hosting a
+// project, or defaults when the project has no organization
+func (s *Service) GetSettingsForProject(projectID string) (*models.OrganizationSettings, error) {
+	var orgID sql.NullString
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow("SELECT organization_id FROM projects WHERE id = $1", projectID).Scan(&orgID)
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	if !orgID.Valid {
+		settings := models.DefaultOrganizationSettings("")
+		return &settings, nil
+	}
+
+	return s.GetSettings(orgID.String)
+}
+
+// UpdateSettings validates and replaces an organization's settings.
+// Only organization admins and owners may change settings.
+func (s *Service) UpdateSettings(orgID, updatedBy string, req models.UpdateOrganizationSettingsRequest) (*models.OrganizationSettings, error) {
+	if err := validateSettings(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetOrganization(orgID); err != nil {
+		return nil, err
+	}
+
+	role, err := s.GetMemberRole(orgID, updatedBy)
+	if err != nil {
+		return nil, err
+	}
+	if !RoleAtLeast(role, models.OrgRoleAdmin) {
+		return nil, ErrInsufficientRole
+	}
+
+	query := `
+		INSERT INTO organization_settings (
+			organization_id,
+			default_skill_weight, default_distance_weight, default_max_distance_km, show_volunteer_ratings,
+			allow_volunteer_requests, require_request_message, allow_self_signup,
+			email_footer, primary_color, secondary_color, logo_url,
+			updated_by, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, CURRENT_TIMESTAMP)
+		ON CONFLICT (organization_id)
+		DO UPDATE SET
+			default_skill_weight = EXCLUDED.default_skill_weight,
+			default_distance_weight = EXCLUDED.default_distance_weight,
+			default_max_distance_km = EXCLUDED.default_max_distance_km,
+			show_volunteer_ratings = EXCLUDED.show_volunteer_ratings,
+			allow_volunteer_requests = EXCLUDED.allow_volunteer_requests,
+			require_request_message = EXCLUDED.require_request_message,
+			allow_self_signup = EXCLUDED.allow_self_signup,
+			email_footer = EXCLUDED.email_footer,
+			primary_color = EXCLUDED.primary_color,
+			secondary_color = EXCLUDED.secondary_color,
+			logo_url = EXCLUDED.logo_url,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING ` + settingsColumns
+
+	settings := models.OrganizationSettings{OrganizationID: orgID}
+	err = database.WithWriteGuard(func() error {
+		row := s.db.QueryRow(query,
+			orgID,
+			req.Matching.SkillWeight,
+			req.Matching.DistanceWeight,
+			req.Matching.MaxDistanceKm,
+			req.Matching.ShowRatings,
+			req.Enrollment.AllowVolunteerRequests,
+			req.Enrollment.RequireRequestMessage,
+			req.Enrollment.AllowSelfSignup,
+			req.Branding.EmailFooter,
+			req.Branding.PrimaryColor,
+			req.Branding.SecondaryColor,
+			req.Branding.LogoURL,
+			updatedBy,
+		)
+		return scanSettings(row, &settings)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func validateSettings(req models.UpdateOrganizationSettingsRequest) error {
+	m := req.Matching
+	if m.SkillWeight < 0 || m.SkillWeight > 1 {
+		return &SettingsError{"matching.skillWeight", "must be between 0 and 1"}
+	}
+	if m.DistanceWeight < 0 || m.DistanceWeight > 1 {
+		return &SettingsError{"matching.distanceWeight", "must be between 0 and 1"}
+	}
+	if m.SkillWeight+m.DistanceWeight == 0 {
+		return &SettingsError{"matching", "skillWeight and distanceWeight cannot both be 0"}
+	}
+	if m.MaxDistanceKm <= 0 {
+		return &SettingsError{"matching.maxDistanceKm", "must be greater than 0"}
+	}
+
+	b := req.Branding
+	if len(b.EmailFooter) > maxEmailFooterLength {
+		return &SettingsError{"branding.emailFooter", "must be at most 2000 characters"}
+	}
+	if b.PrimaryColor != "" && !hexColorPattern.MatchString(b.PrimaryColor) {
+		return &SettingsError{"branding.primaryColor", "must be a hex color like #1a73e8"}
+	}
+	if b.SecondaryColor != "" && !hexColorPattern.MatchString(b.SecondaryColor) {
+		return &SettingsError{"branding.secondaryColor", "must be a hex color like #1a73e8"}
+	}
+	if b.LogoURL != "" {
+		u, err := url.Parse(b.LogoURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &SettingsError{"branding.logoUrl", "must be an http(s) URL"}
+		}
+	}
+
+	return nil
+}
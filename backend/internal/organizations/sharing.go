@@ -0,0 +1,161 @@
+package organizations
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/tenant"
+)
+
+var (
+	ErrShareNotFound = errors.New("sharing agreement not found")
+	ErrShareWithSelf = errors.New("an organization cannot share volunteers with itself")
+)
+
+const sharingSelect = `
+	SELECT s.owner_organization_id, owner.name, s.recipient_organization_id, recipient.name, s.granted_by, s.created_at
+	FROM organization_volunteer_shares s
+	JOIN organizations owner ON owner.id = s.owner_organization_id
+	JOIN organizations recipient ON recipient.id = s.recipient_organization_id
+`
+
+func scanSharingAgreement(scanner interface{ Scan(...interface{}) error }, a *models.VolunteerSharingAgreement) error {
+	return scanner.Scan(
+		&a.OwnerOrganizationID,
+		&a.OwnerOrganizationName,
+		&a.RecipientOrganizationID,
+		&a.RecipientOrganizationName,
+		&a.GrantedBy,
+		&a.CreatedAt,
+	)
+}
+
+// GetVolunteerSharing lists the organization's active sharing agreements in
+// both directions. Only admins and owners can view them.
+func (s *Service) GetVolunteerSharing(orgID, requestedBy string) (*models.VolunteerSharing, error) {
+	if err := s.requireAdmin(orgID, requestedBy); err != nil {
+		return nil, err
+	}
+
+	query := sharingSelect + `
+		WHERE (s.owner_organization_id = $1 OR s.recipient_organization_id = $1)
+		  AND s.revoked_at IS NULL
+		ORDER BY s.created_at DESC
+	`
+
+	sharing := models.VolunteerSharing{
+		SharedWith: []models.VolunteerSharingAgreement{},
+		SharedBy:   []models.VolunteerSharingAgreement{},
+	}
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, orgID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		sharing.SharedWith = sharing.SharedWith[:0]
+		sharing.SharedBy = sharing.SharedBy[:0]
+		for rows.Next() {
+			var a models.VolunteerSharingAgreement
+			if err := scanSharingAgreement(rows, &a); err != nil {
+				return err
+			}
+			if a.OwnerOrganizationID == orgID {
+				sharing.SharedWith = append(sharing.SharedWith, a)
+			} else {
+				sharing.SharedBy = append(sharing.SharedBy, a)
+			}
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sharing, nil
+}
+
+// ShareVolunteers lets the recipient organization (ID or slug) see this
+// organization's volunteers in match results. Sharing again after a
+// revocation reactivates the agreement.
+func (s *Service) ShareVolunteers(orgID, recipient, grantedBy string) (*models.VolunteerSharingAgreement, error) {
+	if err := s.requireAdmin(orgID, grantedBy); err != nil {
+		return nil, err
+	}
+
+	recipientID, err := s.ResolveTenant(recipient)
+	if err == tenant.ErrUnknownTenant {
+		return nil, ErrOrganizationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if recipientID == orgID {
+		return nil, ErrShareWithSelf
+	}
+
+	query := `
+		WITH s AS (
+			INSERT INTO organization_volunteer_shares (owner_organization_id, recipient_organization_id, granted_by)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (owner_organization_id, recipient_organization_id)
+			DO UPDATE SET
+				granted_by = EXCLUDED.granted_by,
+				created_at = CURRENT_TIMESTAMP,
+				revoked_at = NULL
+			RETURNING owner_organization_id, recipient_organization_id, granted_by, created_at
+		)
+		SELECT s.owner_organization_id, owner.name, s.recipient_organization_id, recipient.name, s.granted_by, s.created_at
+		FROM s
+		JOIN organizations owner ON owner.id = s.owner_organization_id
+		JOIN organizations recipient ON recipient.id = s.recipient_organization_id
+	`
+
+	var a models.VolunteerSharingAgreement
+	err = database.WithWriteGuard(func() error {
+		return scanSharingAgreement(s.db.QueryRow(query, orgID, recipientID, grantedBy), &a)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// RevokeVolunteerSharing stops sharing this organization's volunteers with the recipient
+func (s *Service) RevokeVolunteerSharing(orgID, recipientID, revokedBy string) error {
+	if err := s.requireAdmin(orgID, revokedBy); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE organization_volunteer_shares
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE owner_organization_id = $1
+		  AND recipient_organization_id = $2
+		  AND revoked_at IS NULL
+	`
+
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(query, orgID, recipientID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrShareNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,359 @@
+package organizations
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+var (
+	ErrEvidenceNotFound       = errors.New("verification evidence not found")
+	ErrEvidenceRequired       = errors.New("upload verification evidence before requesting verification")
+	ErrEvidenceTooLarge       = errors.New("evidence files must be at most 10 MB")
+	ErrEvidenceType           = errors.New("evidence must be a PDF, PNG or JPEG file")
+	ErrInvalidVerification    = errors.New("verification status must be verified or unverified")
+	ErrVerificationNotAllowed = errors.New("organization is already verified or pending review")
+	ErrVerificationNotPending = errors.New("organization has no pending verification request")
+)
+
+// MaxEvidenceBytes caps the size of one uploaded evidence file
+const MaxEvidenceBytes = 10 << 20
+
+var evidenceContentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+}
+
+const evidenceColumns = `id, organization_id, filename, content_type, size_bytes, uploaded_by, created_at`
+
+func scanEvidence(scanner interface{ Scan(...interface{}) error }, e *models.VerificationEvidence) error {
+	return scanner.Scan(
+		&e.ID,
+		&e.OrganizationID,
+		&e.Filename,
+		&e.ContentType,
+		&e.SizeBytes,
+		&e.UploadedBy,
+		&e.CreatedAt,
+	)
+}
+
+// GetVerification returns the organization's verification status and the
+// evidence uploaded for it. Organization admins and platform admins can view it.
+func (s *Service) GetVerification(orgID, requestedBy string) (*models.OrganizationVerification, error) {
+	if err := s.requireAdminOrPlatformAdmin(orgID, requestedBy); err != nil {
+		return nil, err
+	}
+
+	org, err := s.GetOrganization(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + evidenceColumns + `
+		FROM organization_verification_evidence
+		WHERE organization_id = $1
+		ORDER BY created_at
+	`
+
+	verification := models.OrganizationVerification{Organization: *org}
+	err = database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, orgID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		verification.Evidence = []models.VerificationEvidence{}
+		for rows.Next() {
+			var e models.VerificationEvidence
+			if err := scanEvidence(rows, &e); err != nil {
+				return err
+			}
+			verification.Evidence = append(verification.Evidence, e)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &verification, nil
+}
+
+// AddVerificationEvidence stores a document supporting verification.
+// Only organization admins and owners can upload evidence.
+func (s *Service) AddVerificationEvidence(orgID, uploadedBy, filename, contentType string, content []byte) (*models.VerificationEvidence, error) {
+	if len(content) > MaxEvidenceBytes {
+		return nil, ErrEvidenceTooLarge
+	}
+	if !evidenceContentTypes[contentType] {
+		return nil, ErrEvidenceType
+	}
+
+	if err := s.requireAdmin(orgID, uploadedBy); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO organization_verification_evidence (organization_id, filename, content_type, size_bytes, content, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + evidenceColumns
+
+	var e models.VerificationEvidence
+	err := database.WithWriteGuard(func() error {
+		row := s.db.QueryRow(query, orgID, filename, contentType, len(content), content, uploadedBy)
+		return scanEvidence(row, &e)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// GetVerificationEvidenceContent returns an evidence document and its content
+func (s *Service) GetVerificationEvidenceContent(orgID, evidenceID, requestedBy string) (*models.VerificationEvidence, []byte, error) {
+	if err := s.requireAdminOrPlatformAdmin(orgID, requestedBy); err != nil {
+		return nil, nil, err
+	}
+
+	query := `
+		SELECT ` + evidenceColumns + `, content
+		FROM organization_verification_evidence
+		WHERE id = $1 AND organization_id = $2
+	`
+
+	var e models.VerificationEvidence
+	var content []byte
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, evidenceID, orgID).Scan(
+			&e.ID,
+			&e.OrganizationID,
+			&e.Filename,
+			&e.ContentType,
+			&e.SizeBytes,
+			&e.UploadedBy,
+			&e.CreatedAt,
+			&content,
+		)
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil, ErrEvidenceNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &e, content, nil
+}
+
+// RequestVerification submits an unverified organization for review.
+// At least one evidence document must have been uploaded.
+func (s *Service) RequestVerification(orgID, requestedBy string) (*models.Organization, error) {
+	if err := s.requireAdmin(orgID, requestedBy); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE organizations o
+		SET verification_status = 'pending',
+		    verification_note = NULL,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE o.id = $1
+		  AND o.verification_status = 'unverified'
+		  AND EXISTS (
+		      SELECT 1 FROM organization_verification_evidence e WHERE e.organization_id = o.id
+		  )
+	`
+
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(query, orgID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		// Work out which precondition failed
+		org, err := s.GetOrganization(orgID)
+		if err != nil {
+			return nil, err
+		}
+		if org.VerificationStatus != models.OrgVerificationUnverified {
+			return nil, ErrVerificationNotAllowed
+		}
+		return nil, ErrEvidenceRequired
+	}
+
+	return s.GetOrganization(orgID)
+}
+
+// ReviewVerification records a platform admin's decision on a pending
+// request. Admins may also revoke a verified organization's status.
+func (s *Service) ReviewVerification(orgID, reviewedBy string, req models.UpdateVerificationRequest) (*models.Organization, error) {
+	if req.Status != models.OrgVerificationVerified && req.Status != models.OrgVerificationUnverified {
+		return nil, ErrInvalidVerification
+	}
+
+	isAdmin, err := s.IsPlatformAdmin(reviewedBy)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, ErrInsufficientRole
+	}
+
+	var note *string
+	if req.Note != nil && strings.TrimSpace(*req.Note) != "" {
+		trimmed := strings.TrimSpace(*req.Note)
+		note = &trimmed
+	}
+
+	// Only pending requests can be approved; rejecting also covers revoking a verified organization
+	query := `
+		UPDATE organizations
+		SET verification_status = $2,
+		    verification_note = $3,
+		    verified_by = CASE WHEN $2 = 'verified' THEN $4::uuid ELSE NULL END,
+		    verified_at = CASE WHEN $2 = 'verified' THEN CURRENT_TIMESTAMP ELSE NULL END,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		  AND (verification_status = 'pending' OR ($2 = 'unverified' AND verification_status = 'verified'))
+	`
+
+	var result sql.Result
+	err = database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(query, orgID, req.Status, note, reviewedBy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		if _, err := s.GetOrganization(orgID); err != nil {
+			return nil, err
+		}
+		return nil, ErrVerificationNotPending
+	}
+
+	return s.GetOrganization(orgID)
+}
+
+// GetPendingVerifications lists organizations awaiting review, oldest first
+func (s *Service) GetPendingVerifications() ([]models.Organization, error) {
+	query := `
+		SELECT id, name, slug, COALESCE(description, ''), verification_status, verification_note, verified_at, created_by, created_at, updated_at
+		FROM organizations
+		WHERE verification_status = 'pending'
+		ORDER BY updated_at
+	`
+
+	var orgs []models.Organization
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		orgs = nil
+		for rows.Next() {
+			var org models.Organization
+			err := rows.Scan(
+				&org.ID,
+				&org.Name,
+				&org.Slug,
+				&org.Description,
+				&org.VerificationStatus,
+				&org.VerificationNote,
+				&org.VerifiedAt,
+				&org.CreatedBy,
+				&org.CreatedAt,
+				&org.UpdatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			orgs = append(orgs, org)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return orgs, nil
+}
+
+// CanPublishProject reports whether a project may be made public: its
+// organization must be verified. Projects without an organization predate
+// verification and are unaffected.
+func (s *Service) CanPublishProject(projectID string) (bool, error) {
+	query := `
+		SELECT COALESCE(o.verification_status = 'verified', TRUE)
+		FROM projects p
+		LEFT JOIN organizations o ON o.id = p.organization_id
+		WHERE p.id = $1
+	`
+
+	var allowed bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, projectID).Scan(&allowed)
+	})
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return allowed, nil
+}
+
+// IsPlatformAdmin reports whether the user administers the whole platform
+func (s *Service) IsPlatformAdmin(userID string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND role = 'admin')`
+
+	var isAdmin bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, userID).Scan(&isAdmin)
+	})
+	return isAdmin, err
+}
+
+// requireAdminOrPlatformAdmin checks that the user administers the
+// organization or the platform
+func (s *Service) requireAdminOrPlatformAdmin(orgID, userID string) error {
+	err := s.requireAdmin(orgID, userID)
+	if err != ErrInsufficientRole {
+		return err
+	}
+
+	isAdmin, err := s.IsPlatformAdmin(userID)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return ErrInsufficientRole
+	}
+	return nil
+}
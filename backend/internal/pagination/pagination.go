@@ -0,0 +1,157 @@
+// Package pagination reads the paging and sorting parameters of list
+// endpoints and wraps each page of results in a common envelope. Pages are
+// addressed by limit and offset; the cursor handed back for the next page
+// is opaque, so clients don't depend on how it is built.
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/models"
+)
+
+// Page sizes
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+var (
+	ErrInvalidLimit  = errors.New("limit must be a positive integer")
+	ErrInvalidOffset = errors.New("offset must be a non-negative integer")
+	ErrInvalidCursor = errors.New("cursor is invalid")
+	ErrCursorOffset  = errors.New("cursor and offset cannot be combined")
+	ErrInvalidSort   = errors.New("sort names a field the list cannot be sorted by")
+)
+
+// cursorPrefix versions cursors so their format can change later
+const cursorPrefix = "o:"
+
+// Sorts lists the fields a list can be sorted by
+type Sorts struct {
+	// Fields maps each sort name clients may use to its SQL expression
+	Fields map[string]string
+	// Default is the sort used when none is requested, written like the
+	// sort parameter; when empty the query's own order applies
+	Default string
+	// Tiebreak is a unique column sorted on last, so rows with equal sort
+	// keys keep their place between pages
+	Tiebreak string
+}
+
+// Params selects one page of a list
+type Params struct {
+	Limit  int
+	Offset int
+	// OrderBy is the SQL ORDER BY list for the requested sort, or "" when
+	// the query should use its own order
+	OrderBy string
+}
+
+// Parse reads limit, offset or cursor, and sort from q. sort is a comma-
+// separated list of field names, each prefixed with - to sort descending.
+// Limits above MaxLimit are lowered to it.
+func Parse(q url.Values, sorts Sorts) (Params, error) {
+	p := Params{Limit: DefaultLimit}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return Params{}, ErrInvalidLimit
+		}
+		p.Limit = limit
+	}
+	if p.Limit > MaxLimit {
+		p.Limit = MaxLimit
+	}
+
+	cursor, offset := q.Get("cursor"), q.Get("offset")
+	switch {
+	case cursor != "" && offset != "":
+		return Params{}, ErrCursorOffset
+	case cursor != "":
+		parsed, err := decodeCursor(cursor)
+		if err != nil {
+			return Params{}, err
+		}
+		p.Offset = parsed
+	case offset != "":
+		parsed, err := strconv.Atoi(offset)
+		if err != nil || parsed < 0 {
+			return Params{}, ErrInvalidOffset
+		}
+		p.Offset = parsed
+	}
+
+	sort := q.Get("sort")
+	if sort == "" {
+		sort = sorts.Default
+	}
+	if sort == "" {
+		return p, nil
+	}
+	var terms []string
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		direction := "ASC"
+		if strings.HasPrefix(field, "-") {
+			field, direction = field[1:], "DESC"
+		}
+		expr, ok := sorts.Fields[field]
+		if !ok {
+			return Params{}, ErrInvalidSort
+		}
+		terms = append(terms, expr+" "+direction)
+	}
+	if sorts.Tiebreak != "" {
+		terms = append(terms, sorts.Tiebreak)
+	}
+	p.OrderBy = strings.Join(terms, ", ")
+	return p, nil
+}
+
+// Window returns the LIMIT and OFFSET clauses for the page, taking them from
+// the query's parameters $n and $n+1
+func (p Params) Window(n int) string {
+	return fmt.Sprintf("LIMIT $%d OFFSET $%d", n, n+1)
+}
+
+// Args returns the values of Window's parameters
+func (p Params) Args() []interface{} {
+	return []interface{}{p.Limit, p.Offset}
+}
+
+// NewPage wraps items, a slice holding the page, with the total across all
+// pages and the cursor of the next page when there is one
+func NewPage(items interface{}, total int, p Params) models.Page {
+	if v := reflect.ValueOf(items); v.Kind() == reflect.Slice && v.IsNil() {
+		items = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	page := models.Page{Items: items, Total: total, Limit: p.Limit, Offset: p.Offset}
+	if next := p.Offset + p.Limit; next < total {
+		page.NextCursor = encodeCursor(next)
+	}
+	return page
+}
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, ErrInvalidCursor
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return offset, nil
+}
@@ -0,0 +1,121 @@
+package profiles
+
+import (
+	"database/sql"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+// GetPrivacy returns which fields the volunteer shows on their public profile
+func (s *Service) GetPrivacy(volunteerID string) (*models.ProfilePrivacy, error) {
+	var privacy models.ProfilePrivacy
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(`
+			SELECT public_name, public_badges, public_skill_categories, public_total_hours
+			FROM users
+			WHERE id::text = $1
+		`, volunteerID).Scan(&privacy.Name, &privacy.Badges, &privacy.SkillCategories, &privacy.TotalHours)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrVolunteerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &privacy, nil
+}
+
+// UpdatePrivacy replaces which fields the volunteer shows on their public
+// profile
+func (s *Service) UpdatePrivacy(volunteerID string, privacy models.ProfilePrivacy) error {
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(`
+			UPDATE users
+			SET public_name = $2,
+			    public_badges = $3,
+			    public_skill_categories = $4,
+			    public_total_hours = $5,
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE id::text = $1
+		`, volunteerID, privacy.Name, privacy.Badges, privacy.SkillCategories, privacy.TotalHours)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrVolunteerNotFound
+	}
+
+	return nil
+}
+
+// GetPublicProfile returns the fields of the volunteer's profile they opted
+// into, with their privacy settings so the caller can add badges if shown.
+// Fields that weren't opted into are never read. Profiles hidden by a
+// moderator are reported as not found.
+func (s *Service) GetPublicProfile(volunteerID string) (*models.PublicProfile, *models.ProfilePrivacy, error) {
+	var profile models.PublicProfile
+	var privacy models.ProfilePrivacy
+	err := database.WithReadRetry(func() error {
+		var name sql.NullString
+		var categories []string
+		var totalHours sql.NullFloat64
+		err := s.db.QueryRow(`
+			SELECT u.id, u.public_name, u.public_badges, u.public_skill_categories, u.public_total_hours,
+			       CASE WHEN u.public_name THEN u.name END,
+			       CASE WHEN u.public_skill_categories THEN ARRAY(
+			           SELECT DISTINCT sk.category
+			           FROM volunteer_skills vs
+			           JOIN skills sk ON sk.id = vs.skill_id
+			           WHERE vs.volunteer_id = u.id AND vs.claimed AND sk.category IS NOT NULL
+			           ORDER BY sk.category
+			       ) END,
+			       CASE WHEN u.public_total_hours THEN (
+			           SELECT COALESCE(SUM(vh.hours), 0) FROM volunteer_hours vh WHERE vh.volunteer_id = u.id
+			       ) END
+			FROM users u
+			WHERE u.id::text = $1
+			  AND u.profile_hidden_at IS NULL
+		`, volunteerID).Scan(
+			&profile.ID,
+			&privacy.Name,
+			&privacy.Badges,
+			&privacy.SkillCategories,
+			&privacy.TotalHours,
+			&name,
+			pq.Array(&categories),
+			&totalHours,
+		)
+		if err != nil {
+			return err
+		}
+
+		profile.SkillCategories = categories
+		if name.Valid {
+			profile.Name = &name.String
+		}
+		if totalHours.Valid {
+			profile.TotalHours = &totalHours.Float64
+		}
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil, ErrVolunteerNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &profile, &privacy, nil
+}
@@ -0,0 +1,279 @@
+package profiles
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrVolunteerNotFound = errors.New("volunteer not found")
+	ErrProjectNotFound   = errors.New("project not found")
+	ErrReferenceNotFound = errors.New("reference not found")
+	ErrNotEnrolled       = errors.New("volunteer is not enrolled in the project")
+	ErrOwnReference      = errors.New("volunteers cannot write their own references")
+	ErrReferenceExists   = errors.New("a reference for this volunteer and project already exists")
+	ErrInvalidBody       = errors.New("body is required and must be at most 1000 characters")
+	ErrInvalidAction     = errors.New("action must be approve or decline")
+)
+
+const maxReferenceLength = 1000
+
+const referenceSelect = `
+	SELECT r.id, r.volunteer_id, r.author_id, a.name, r.project_id, p.name,
+	       r.body, r.status, r.responded_at, r.created_at
+	FROM volunteer_references r
+	JOIN users a ON a.id = r.author_id
+	JOIN projects p ON p.id = r.project_id
+`
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// GetProfile returns the volunteer's public profile with their claimed
+// skills and approved references; badges and milestones are filled in by
+// the caller. Profiles hidden by a moderator are reported as not found.
+func (s *Service) GetProfile(volunteerID string) (*models.VolunteerProfile, error) {
+	profile := models.VolunteerProfile{
+		Skills:     []models.ProfileSkill{},
+		Badges:     []models.VolunteerBadge{},
+		Milestones: []models.HourMilestone{},
+		References: []models.VolunteerReference{},
+	}
+
+	err := database.WithReadRetry(func() error {
+		err := s.db.QueryRow(`
+			SELECT id, name, CASE WHEN avatar_scan_status = 'clean' THEN avatar_url END, avatar_variants, created_at FROM users WHERE id::text = $1 AND profile_hidden_at IS NULL
+		`, volunteerID).Scan(&profile.ID, &profile.Name, &profile.AvatarURL, &profile.AvatarVariants, &profile.MemberSince)
+		if err != nil {
+			return err
+		}
+
+		rows, err := s.db.Query(`
+			SELECT sk.id, sk.name, vs.verified_at IS NOT NULL
+			FROM volunteer_skills vs
+			JOIN skills sk ON sk.id = vs.skill_id
+			WHERE vs.volunteer_id = $1 AND vs.claimed
+			ORDER BY vs.verified_at IS NULL, sk.name
+		`, profile.ID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		profile.Skills = []models.ProfileSkill{}
+		for rows.Next() {
+			var skill models.ProfileSkill
+			if err := rows.Scan(&skill.SkillID, &skill.Name, &skill.Verified); err != nil {
+				return err
+			}
+			profile.Skills = append(profile.Skills, skill)
+		}
+		return rows.Err()
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrVolunteerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	profile.References, err = s.queryReferences(referenceSelect+`
+		WHERE r.volunteer_id = $1 AND r.status = 'approved'
+		ORDER BY r.created_at DESC, r.id
+	`, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+// CreateReference records a coordinator's reference for a volunteer enrolled
+// in one of their projects. It stays pending until the volunteer approves it.
+func (s *Service) CreateReference(projectID, volunteerID, authorID, tenantID string, req models.CreateReferenceRequest) (*models.VolunteerReference, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" || len([]rune(body)) > maxReferenceLength {
+		return nil, ErrInvalidBody
+	}
+	if volunteerID == authorID {
+		return nil, ErrOwnReference
+	}
+
+	var projectExists, enrolled bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(`
+			SELECT EXISTS (
+				SELECT 1 FROM projects
+				WHERE id = $1
+				  AND ($3 = '' OR organization_id = NULLIF($3, '')::uuid)
+			), EXISTS (
+				SELECT 1 FROM volunteer_enrollments
+				WHERE project_id = $1 AND volunteer_id = $2 AND status IN ('enrolled', 'completed')
+			)
+		`, projectID, volunteerID, tenantID).Scan(&projectExists, &enrolled)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !projectExists {
+		return nil, ErrProjectNotFound
+	}
+	if !enrolled {
+		return nil, ErrNotEnrolled
+	}
+
+	var referenceID string
+	err = database.WithWriteGuard(func() error {
+		return s.db.QueryRow(`
+			INSERT INTO volunteer_references (volunteer_id, author_id, project_id, body)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, volunteerID, authorID, projectID, body).Scan(&referenceID)
+	})
+	if isUniqueViolation(err) {
+		return nil, ErrReferenceExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getReference(referenceID)
+}
+
+// GetReferences lists every reference written for the volunteer, including
+// pending, declined and removed ones, newest first
+func (s *Service) GetReferences(volunteerID string) ([]models.VolunteerReference, error) {
+	return s.queryReferences(referenceSelect+`
+		WHERE r.volunteer_id::text = $1
+		ORDER BY r.created_at DESC, r.id
+	`, volunteerID)
+}
+
+// RespondToReference lets the volunteer approve a reference for their public
+// profile or decline it; either can be changed later. Removed references
+// are reported as not found.
+func (s *Service) RespondToReference(referenceID, volunteerID string, req models.RespondToReferenceRequest) (*models.VolunteerReference, error) {
+	var status string
+	switch req.Action {
+	case "approve":
+		status = "approved"
+	case "decline":
+		status = "declined"
+	default:
+		return nil, ErrInvalidAction
+	}
+
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(`
+			UPDATE volunteer_references
+			SET status = $3, responded_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			WHERE id::text = $1 AND volunteer_id::text = $2 AND status <> 'removed'
+		`, referenceID, volunteerID, status)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, ErrReferenceNotFound
+	}
+
+	return s.getReference(referenceID)
+}
+
+// RemoveReference takes a reference off the volunteer's profile for good
+func (s *Service) RemoveReference(referenceID, moderatorID string) error {
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(`
+			UPDATE volunteer_references
+			SET status = 'removed', moderated_by = $2, moderated_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			WHERE id::text = $1 AND status <> 'removed'
+		`, referenceID, moderatorID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrReferenceNotFound
+	}
+
+	return nil
+}
+
+func (s *Service) getReference(referenceID string) (*models.VolunteerReference, error) {
+	list, err := s.queryReferences(referenceSelect+`WHERE r.id = $1`, referenceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, ErrReferenceNotFound
+	}
+	return &list[0], nil
+}
+
+func (s *Service) queryReferences(query string, args ...interface{}) ([]models.VolunteerReference, error) {
+	var list []models.VolunteerReference
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		list = []models.VolunteerReference{}
+		for rows.Next() {
+			var r models.VolunteerReference
+			if err := rows.Scan(
+				&r.ID,
+				&r.VolunteerID,
+				&r.AuthorID,
+				&r.AuthorName,
+				&r.ProjectID,
+				&r.ProjectName,
+				&r.Body,
+				&r.Status,
+				&r.RespondedAt,
+				&r.CreatedAt,
+			); err != nil {
+				return err
+			}
+			list = append(list, r)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,150 @@
+package projects
+
+import (
+	"context"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/tracing"
+	"github.com/lib/pq"
+)
+
+// upcomingWindow is how far ahead the dashboard lists project start dates
+const upcomingWindow = 30 * 24 * time.Hour
+
+// GetCoordinatorDashboard aggregates the coordinator's projects with their
+// enrollment counts and unfilled required skills, the volunteer requests
+// awaiting review, and projects starting soon. When tenantID is set only the
+// tenant's projects are included.
+func (s *Service) GetCoordinatorDashboard(ctx context.Context, coordinatorID, tenantID string) (*models.CoordinatorDashboard, error) {
+	ctx, span := tracing.Start(ctx, "projects.GetCoordinatorDashboard")
+	defer span.End()
+
+	projectsQuery := `
+		SELECT p.id, p.name, p.status, p.timezone, p.start_date, p.end_date, p.max_volunteers,
+		       COUNT(ve.id) FILTER (WHERE ve.status = 'enrolled'),
+		       COUNT(ve.id) FILTER (WHERE ve.status = 'requested'),
+		       COUNT(ve.id) FILTER (WHERE ve.status = 'invited'),
+		       ARRAY(
+		           SELECT sk.name
+		           FROM project_skills ps
+		           JOIN skills sk ON sk.id = ps.skill_id
+		           WHERE ps.project_id = p.id
+		             AND ps.required
+		             AND NOT EXISTS (
+		                 SELECT 1
+		                 FROM volunteer_enrollments e
+		                 JOIN volunteer_skills vs ON vs.volunteer_id = e.volunteer_id AND vs.skill_id = ps.skill_id
+		                 WHERE e.project_id = p.id AND e.status = 'enrolled'
+		             )
+		           ORDER BY sk.name
+		       )
+		FROM projects p
+		LEFT JOIN volunteer_enrollments ve ON ve.project_id = p.id
+		WHERE p.coordinator_id = $1
+		  AND ($2 = '' OR p.organization_id = NULLIF($2, '')::uuid)
+		GROUP BY p.id
+		ORDER BY p.start_date NULLS LAST, p.name
+	`
+
+	requestsQuery := `
+		SELECT ve.id, p.id, p.name, u.id, u.name, ve.message, ve.created_at
+		FROM volunteer_enrollments ve
+		JOIN projects p ON p.id = ve.project_id
+		JOIN users u ON u.id = ve.volunteer_id
+		WHERE p.coordinator_id = $1
+		  AND ($2 = '' OR p.organization_id = NULLIF($2, '')::uuid)
+		  AND ve.status = 'requested'
+		ORDER BY ve.created_at
+	`
+
+	dashboard := models.CoordinatorDashboard{CoordinatorID: coordinatorID}
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, projectsQuery, coordinatorID, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		dashboard.Projects = []models.DashboardProject{}
+		for rows.Next() {
+			var p models.DashboardProject
+			err := rows.Scan(
+				&p.ProjectID,
+				&p.Name,
+				&p.Status,
+				&p.Timezone,
+				&p.StartDate,
+				&p.EndDate,
+				&p.MaxVolunteers,
+				&p.Enrolled,
+				&p.PendingRequests,
+				&p.PendingInvitations,
+				pq.Array(&p.UnfilledSkills),
+			)
+			if err != nil {
+				return err
+			}
+			p.StartDate = models.InTimezone(p.StartDate, p.Timezone)
+			p.EndDate = models.InTimezone(p.EndDate, p.Timezone)
+			if p.UnfilledSkills == nil {
+				p.UnfilledSkills = []string{}
+			}
+			dashboard.Projects = append(dashboard.Projects, p)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		requests, err := s.db.QueryContext(ctx, requestsQuery, coordinatorID, tenantID)
+		if err != nil {
+			return err
+		}
+		defer requests.Close()
+
+		dashboard.PendingRequests = []models.DashboardRequest{}
+		for requests.Next() {
+			var req models.DashboardRequest
+			err := requests.Scan(
+				&req.EnrollmentID,
+				&req.ProjectID,
+				&req.ProjectName,
+				&req.VolunteerID,
+				&req.VolunteerName,
+				&req.Message,
+				&req.CreatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			dashboard.PendingRequests = append(dashboard.PendingRequests, req)
+		}
+		return requests.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Projects are ordered by start date, so upcoming starts come out in order
+	now := time.Now()
+	dashboard.UpcomingStarts = []models.DashboardUpcomingStart{}
+	for _, p := range dashboard.Projects {
+		dashboard.Totals.Projects++
+		if p.Status == "active" {
+			dashboard.Totals.ActiveProjects++
+		}
+		dashboard.Totals.Enrolled += p.Enrolled
+		dashboard.Totals.PendingRequests += p.PendingRequests
+
+		if p.StartDate != nil && p.StartDate.After(now) && p.StartDate.Before(now.Add(upcomingWindow)) {
+			dashboard.UpcomingStarts = append(dashboard.UpcomingStarts, models.DashboardUpcomingStart{
+				ProjectID: p.ProjectID,
+				Name:      p.Name,
+				StartDate: *p.StartDate,
+			})
+		}
+	}
+
+	return &dashboard, nil
+}
@@ -0,0 +1,68 @@
+package projects
+
+import (
+	"context"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/sanitize"
+	"github.com/civic-weave/backend/internal/tracing"
+)
+
+// UpsertExternalProject creates or updates the organization's project
+// imported from source under p.ExternalID. New projects are listed as
+// active and closed ones retired, and retired projects the platform lists
+// again are reopened unless they have ended; projects hidden by a moderator
+// stay hidden. It returns the project's ID and status, and whether it was
+// created.
+func (s *Service) UpsertExternalProject(ctx context.Context, orgID, source string, p models.ExternalProject) (id, status string, created bool, err error) {
+	ctx, span := tracing.Start(ctx, "projects.UpsertExternalProject")
+	defer span.End()
+
+	if p.Timezone == "" {
+		p.Timezone = models.DefaultTimezone
+	}
+	if !models.ValidTimezone(p.Timezone) {
+		return "", "", false, ErrInvalidTimezone
+	}
+	description := sanitize.RichText(p.Description)
+
+	// xmax is only zero on rows the statement inserted
+	query := `
+		INSERT INTO projects (name, description, organization_id, latitude, longitude, location_name, is_remote, timezone,
+		                      start_date, end_date, max_volunteers, external_source, external_id, external_url, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
+		        CASE WHEN $15 THEN 'retired' ELSE 'active' END)
+		ON CONFLICT (organization_id, external_source, external_id) WHERE external_id IS NOT NULL
+		DO UPDATE SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			location_name = EXCLUDED.location_name,
+			is_remote = EXCLUDED.is_remote,
+			timezone = EXCLUDED.timezone,
+			start_date = EXCLUDED.start_date,
+			end_date = EXCLUDED.end_date,
+			max_volunteers = EXCLUDED.max_volunteers,
+			external_url = EXCLUDED.external_url,
+			status = CASE
+				WHEN projects.status = 'hidden' THEN projects.status
+				WHEN $15 THEN 'retired'
+				WHEN projects.status = 'retired' AND (EXCLUDED.end_date IS NULL OR EXCLUDED.end_date >= NOW()) THEN 'active'
+				ELSE projects.status
+			END,
+			updated_at = NOW()
+		RETURNING id, status, xmax = 0
+	`
+
+	err = database.WithWriteGuard(func() error {
+		return s.db.QueryRowContext(ctx, query, p.Name, description, orgID, p.Latitude, p.Longitude, p.LocationName, p.IsRemote, p.Timezone,
+			p.StartDate, p.EndDate, p.MaxVolunteers, source, p.ExternalID, p.URL, p.Closed,
+		).Scan(&id, &status, &created)
+	})
+	if err != nil {
+		return "", "", false, err
+	}
+	return id, status, created, nil
+}
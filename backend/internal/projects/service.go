@@ -1,17 +1,57 @@
 package projects
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"math"
 	"time"
 
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/events"
 	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/pagination"
+	"github.com/civic-weave/backend/internal/sanitize"
+	"github.com/civic-weave/backend/internal/tracing"
 )
 
 var (
 	ErrProjectNotFound = errors.New("project not found")
+	ErrInvalidTimezone = errors.New("timezone must be an IANA time zone such as America/Toronto")
+	ErrProjectHidden   = errors.New("project was hidden by a moderator")
 )
 
+// Kilometers per degree of latitude, used to bound the Haversine fallback
+const kmPerDegree = 111.045
+
+// reviewColumns aggregates the reviews of a project and of every project of
+// its organization, for selects from projects
+const reviewColumns = `
+	(SELECT AVG(r.project_rating)::float8 FROM project_reviews r
+	 WHERE r.project_id = projects.id AND r.status <> 'removed'),
+	(SELECT COUNT(*) FROM project_reviews r
+	 WHERE r.project_id = projects.id AND r.status <> 'removed'),
+	(SELECT AVG(r.organization_rating)::float8 FROM project_reviews r JOIN projects op ON op.id = r.project_id
+	 WHERE op.organization_id = projects.organization_id AND r.status <> 'removed'),
+	(SELECT COUNT(r.organization_rating) FROM project_reviews r JOIN projects op ON op.id = r.project_id
+	 WHERE op.organization_id = projects.organization_id AND r.status <> 'removed')
+`
+
+// translatedColumns selects a project's name and description, preferring
+// the translation joined by translationJoin
+const translatedColumns = `COALESCE(t.name, projects.name) AS name, COALESCE(t.description, projects.description) AS description`
+
+// translationJoin joins, as t, the translation of each project into the
+// locale given as the query's parameter param. No translation matches an
+// empty locale.
+func translationJoin(param int) string {
+	return fmt.Sprintf("LEFT JOIN project_translations t ON t.project_id = projects.id AND t.locale = $%d", param)
+}
+
+// RetireEndedJob is the kind of background job that runs RetireEnded
+const RetireEndedJob = "projects.retire_ended"
+
 type Service struct {
 	db *sql.DB
 }
@@ -20,116 +60,241 @@ func NewService(db *sql.DB) *Service {
 	return &Service{db: db}
 }
 
-func (s *Service) GetAllProjects() ([]models.Project, error) {
+// Sorts are the fields GetAllProjects can sort by, newest first by default
+var Sorts = pagination.Sorts{
+	Fields: map[string]string{
+		"createdAt": "projects.created_at",
+		"name":      "COALESCE(t.name, projects.name)",
+		"startDate": "projects.start_date",
+		"endDate":   "projects.end_date",
+	},
+	Default:  "-createdAt",
+	Tiebreak: "projects.id",
+}
+
+// GetAllProjects returns a page of projects, limited to the tenant's
+// organization when tenantID is set, to remote or on-site projects when
+// remote is set and to one status when status is set, along with how many
+// projects there are across all pages. Projects hidden by a moderator are
+// left out. Names and descriptions are translated into locale where a
+// translation exists.
+func (s *Service) GetAllProjects(ctx context.Context, tenantID string, remote *bool, regionID, status, locale string, page pagination.Params) ([]models.Project, int, error) {
+	ctx, span := tracing.Start(ctx, "projects.GetAllProjects")
+	defer span.End()
+
+	where := `
+		WHERE status <> 'hidden'
+		  AND ($1 = '' OR organization_id = NULLIF($1, '')::uuid)
+		  AND ($2::boolean IS NULL OR is_remote = $2)
+		  AND ($3 = '' OR id IN (SELECT project_id FROM project_regions WHERE region_id = NULLIF($3, '')::uuid))
+		  AND ($4 = '' OR status = $4)
+	`
 	query := `
-		SELECT id, name, description, coordinator_id, latitude, longitude,
-		       location_name, start_date, end_date, status, max_volunteers,
-		       created_at, updated_at
+		SELECT id, ` + translatedColumns + `, coordinator_id, organization_id, latitude, longitude,
+		       location_name, is_remote, timezone, block_schedule_conflicts, start_date, end_date, status, max_volunteers,
+		       t.locale, projects.created_at, projects.updated_at, ` + reviewColumns + `
 		FROM projects
-		ORDER BY created_at DESC
-	`
+		` + translationJoin(5) + where + `
+		ORDER BY ` + page.OrderBy + `
+		` + page.Window(6)
+	filters := []interface{}{tenantID, remote, regionID, status}
+
+	var projects []models.Project
+	var total int
+	err := database.WithReadRetry(func() error {
+		if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM projects`+where, filters...).Scan(&total); err != nil {
+			return err
+		}
+
+		args := append(append(filters, locale), page.Args()...)
+		rows, err := s.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
 
-	rows, err := s.db.Query(query)
+		projects = nil
+		for rows.Next() {
+			p := models.Project{Reviews: &models.ReviewSummary{}}
+			err := rows.Scan(
+				&p.ID,
+				&p.Name,
+				&p.Description,
+				&p.CoordinatorID,
+				&p.OrganizationID,
+				&p.Latitude,
+				&p.Longitude,
+				&p.LocationName,
+				&p.IsRemote,
+				&p.Timezone,
+				&p.BlockScheduleConflicts,
+				&p.StartDate,
+				&p.EndDate,
+				&p.Status,
+				&p.MaxVolunteers,
+				&p.Locale,
+				&p.CreatedAt,
+				&p.UpdatedAt,
+				&p.Reviews.ProjectScore,
+				&p.Reviews.ProjectCount,
+				&p.Reviews.OrganizationScore,
+				&p.Reviews.OrganizationCount,
+			)
+			if err != nil {
+				return err
+			}
+			localize(&p)
+			projects = append(projects, p)
+		}
+		return rows.Err()
+	})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	defer rows.Close()
 
-	var projects []models.Project
-	for rows.Next() {
-		var p models.Project
-		err := rows.Scan(
+	return projects, total, nil
+}
+
+// GetProject returns a project, translated into locale where it can be;
+// projects outside the tenant are reported as not found
+func (s *Service) GetProject(ctx context.Context, projectID, tenantID, locale string) (*models.Project, error) {
+	ctx, span := tracing.Start(ctx, "projects.GetProject")
+	defer span.End()
+
+	query := `
+		SELECT id, ` + translatedColumns + `, coordinator_id, organization_id, latitude, longitude,
+		       location_name, is_remote, timezone, block_schedule_conflicts, start_date, end_date, status, max_volunteers,
+		       t.locale, projects.created_at, projects.updated_at, ` + reviewColumns + `
+		FROM projects
+		` + translationJoin(3) + `
+		WHERE id = $1
+		  AND ($2 = '' OR organization_id = NULLIF($2, '')::uuid)
+	`
+
+	var p models.Project
+	p.Reviews = &models.ReviewSummary{}
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRowContext(ctx, query, projectID, tenantID, locale).Scan(
 			&p.ID,
 			&p.Name,
 			&p.Description,
 			&p.CoordinatorID,
+			&p.OrganizationID,
 			&p.Latitude,
 			&p.Longitude,
 			&p.LocationName,
+			&p.IsRemote,
+			&p.Timezone,
+			&p.BlockScheduleConflicts,
 			&p.StartDate,
 			&p.EndDate,
 			&p.Status,
 			&p.MaxVolunteers,
+			&p.Locale,
 			&p.CreatedAt,
 			&p.UpdatedAt,
+			&p.Reviews.ProjectScore,
+			&p.Reviews.ProjectCount,
+			&p.Reviews.OrganizationScore,
+			&p.Reviews.OrganizationCount,
 		)
-		if err != nil {
-			return nil, err
-		}
-		projects = append(projects, p)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return projects, nil
+	localize(&p)
+	return &p, nil
 }
 
-func (s *Service) GetProject(projectID string) (*models.Project, error) {
+// InTenant reports whether a project exists and belongs to the tenant.
+// Every existing project is in scope when tenantID is empty.
+func (s *Service) InTenant(ctx context.Context, projectID, tenantID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "projects.InTenant")
+	defer span.End()
+
 	query := `
-		SELECT id, name, description, coordinator_id, latitude, longitude,
-		       location_name, start_date, end_date, status, max_volunteers,
-		       created_at, updated_at
-		FROM projects
-		WHERE id = $1
+		SELECT EXISTS (
+			SELECT 1 FROM projects
+			WHERE id = $1
+			  AND ($2 = '' OR organization_id = NULLIF($2, '')::uuid)
+		)
 	`
 
-	var p models.Project
-	err := s.db.QueryRow(query, projectID).Scan(
-		&p.ID,
-		&p.Name,
-		&p.Description,
-		&p.CoordinatorID,
-		&p.Latitude,
-		&p.Longitude,
-		&p.LocationName,
-		&p.StartDate,
-		&p.EndDate,
-		&p.Status,
-		&p.MaxVolunteers,
-		&p.CreatedAt,
-		&p.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, ErrProjectNotFound
-	}
+	var exists bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRowContext(ctx, query, projectID, tenantID).Scan(&exists)
+	})
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 
-	return &p, nil
+	return exists, nil
 }
 
-func (s *Service) CreateProject(name, description string, coordinatorID *string, lat, lon *float64, locationName *string, startDate, endDate *time.Time, maxVolunteers *int) (*models.Project, error) {
+func (s *Service) CreateProject(ctx context.Context, name, description string, coordinatorID, organizationID *string, lat, lon *float64, locationName *string, isRemote bool, timezone string, blockScheduleConflicts bool, startDate, endDate *time.Time, maxVolunteers *int) (*models.Project, error) {
+	ctx, span := tracing.Start(ctx, "projects.CreateProject")
+	defer span.End()
+
+	if timezone == "" {
+		timezone = models.DefaultTimezone
+	}
+	if !models.ValidTimezone(timezone) {
+		return nil, ErrInvalidTimezone
+	}
+	description = sanitize.RichText(description)
+
 	query := `
-        INSERT INTO projects (name, description, coordinator_id, latitude, longitude, location_name, start_date, end_date, max_volunteers, status)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 'draft')
-		RETURNING id, name, description, coordinator_id, latitude, longitude, location_name, start_date, end_date, status, max_volunteers, created_at, updated_at
+        INSERT INTO projects (name, description, coordinator_id, organization_id, latitude, longitude, location_name, is_remote, timezone, block_schedule_conflicts, start_date, end_date, max_volunteers, status)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, 'draft')
+		RETURNING id, name, description, coordinator_id, organization_id, latitude, longitude, location_name, is_remote, timezone, block_schedule_conflicts, start_date, end_date, status, max_volunteers, created_at, updated_at
 	`
 
 	var p models.Project
-	err := s.db.QueryRow(query, name, description, coordinatorID, lat, lon, locationName, startDate, endDate, maxVolunteers).Scan(
-		&p.ID,
-		&p.Name,
-		&p.Description,
-		&p.CoordinatorID,
-		&p.Latitude,
-		&p.Longitude,
-		&p.LocationName,
-		&p.StartDate,
-		&p.EndDate,
-		&p.Status,
-		&p.MaxVolunteers,
-		&p.CreatedAt,
-		&p.UpdatedAt,
-	)
+	err := database.WithWriteGuard(func() error {
+		return s.db.QueryRowContext(ctx, query, name, description, coordinatorID, organizationID, lat, lon, locationName, isRemote, timezone, blockScheduleConflicts, startDate, endDate, maxVolunteers).Scan(
+			&p.ID,
+			&p.Name,
+			&p.Description,
+			&p.CoordinatorID,
+			&p.OrganizationID,
+			&p.Latitude,
+			&p.Longitude,
+			&p.LocationName,
+			&p.IsRemote,
+			&p.Timezone,
+			&p.BlockScheduleConflicts,
+			&p.StartDate,
+			&p.EndDate,
+			&p.Status,
+			&p.MaxVolunteers,
+			&p.CreatedAt,
+			&p.UpdatedAt,
+		)
+	})
 
 	if err != nil {
 		return nil, err
 	}
 
+	localize(&p)
 	return &p, nil
 }
 
-func (s *Service) GetProjectSkills(projectID string) ([]models.ProjectSkill, error) {
+// localize expresses the project's dates in its own time zone
+func localize(p *models.Project) {
+	p.StartDate = models.InTimezone(p.StartDate, p.Timezone)
+	p.EndDate = models.InTimezone(p.EndDate, p.Timezone)
+}
+
+func (s *Service) GetProjectSkills(ctx context.Context, projectID string) ([]models.ProjectSkill, error) {
+	ctx, span := tracing.Start(ctx, "projects.GetProjectSkills")
+	defer span.End()
+
 	query := `
 		SELECT ps.project_id, ps.skill_id, s.name, ps.required, ps.weight
 		FROM project_skills ps
@@ -138,44 +303,64 @@ func (s *Service) GetProjectSkills(projectID string) ([]models.ProjectSkill, err
 		ORDER BY s.name
 	`
 
-	rows, err := s.db.Query(query, projectID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
 	var projectSkills []models.ProjectSkill
-	for rows.Next() {
-		var ps models.ProjectSkill
-		err := rows.Scan(
-			&ps.ProjectID,
-			&ps.SkillID,
-			&ps.SkillName,
-			&ps.Required,
-			&ps.Weight,
-		)
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, query, projectID)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		defer rows.Close()
+
+		projectSkills = nil
+		for rows.Next() {
+			var ps models.ProjectSkill
+			err := rows.Scan(
+				&ps.ProjectID,
+				&ps.SkillID,
+				&ps.SkillName,
+				&ps.Required,
+				&ps.Weight,
+			)
+			if err != nil {
+				return err
+			}
+			projectSkills = append(projectSkills, ps)
 		}
-		projectSkills = append(projectSkills, ps)
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return projectSkills, nil
 }
 
-func (s *Service) SetProjectSkills(projectID string, skills []struct {
+func (s *Service) SetProjectSkills(ctx context.Context, projectID string, skills []struct {
 	SkillID  string
 	Required bool
 	Weight   float64
 }) error {
-	tx, err := s.db.Begin()
+	ctx, span := tracing.Start(ctx, "projects.SetProjectSkills")
+	defer span.End()
+
+	return database.WithWriteGuard(func() error {
+		return s.setProjectSkills(ctx, projectID, skills)
+	})
+}
+
+func (s *Service) setProjectSkills(ctx context.Context, projectID string, skills []struct {
+	SkillID  string
+	Required bool
+	Weight   float64
+}) error {
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
 	// Delete existing skills
-	_, err = tx.Exec("DELETE FROM project_skills WHERE project_id = $1", projectID)
+	_, err = tx.ExecContext(ctx, "DELETE FROM project_skills WHERE project_id = $1", projectID)
 	if err != nil {
 		return err
 	}
@@ -192,7 +377,7 @@ func (s *Service) SetProjectSkills(projectID string, skills []struct {
 			VALUES ($1, $2, $3, $4)
 		`
 
-		_, err := tx.Exec(query, projectID, skill.SkillID, skill.Required, skill.Weight)
+		_, err := tx.ExecContext(ctx, query, projectID, skill.SkillID, skill.Required, skill.Weight)
 		if err != nil {
 			return err
 		}
@@ -201,7 +386,15 @@ func (s *Service) SetProjectSkills(projectID string, skills []struct {
 	return tx.Commit()
 }
 
-func (s *Service) UpdateProjectDetails(projectID string, name, description string, lat, lon *float64, locationName *string) error {
+func (s *Service) UpdateProjectDetails(ctx context.Context, projectID string, name, description string, lat, lon *float64, locationName *string, isRemote *bool, timezone *string, blockScheduleConflicts *bool) error {
+	ctx, span := tracing.Start(ctx, "projects.UpdateProjectDetails")
+	defer span.End()
+
+	if timezone != nil && !models.ValidTimezone(*timezone) {
+		return ErrInvalidTimezone
+	}
+	description = sanitize.RichText(description)
+
 	query := `
         UPDATE projects
         SET
@@ -210,20 +403,229 @@ func (s *Service) UpdateProjectDetails(projectID string, name, description strin
             latitude = COALESCE($3, latitude),
             longitude = COALESCE($4, longitude),
             location_name = COALESCE($5, location_name),
+            is_remote = COALESCE($7, is_remote),
+            timezone = COALESCE($8, timezone),
+            block_schedule_conflicts = COALESCE($9, block_schedule_conflicts),
             updated_at = NOW()
         WHERE id = $6
     `
-	_, err := s.db.Exec(query, name, description, lat, lon, locationName, projectID)
-	return err
+	return database.WithWriteGuard(func() error {
+		_, err := s.db.ExecContext(ctx, query, name, description, lat, lon, locationName, projectID, isRemote, timezone, blockScheduleConflicts)
+		return err
+	})
 }
 
-func (s *Service) UpdateProjectStatus(projectID string, status string) error {
+// UpdateProjectStatus sets the project's status and publishes the change to
+// the project's managers and its enrolled and waitlisted volunteers. Only
+// moderators hide and unhide projects, so hidden projects are refused with
+// ErrProjectHidden.
+func (s *Service) UpdateProjectStatus(ctx context.Context, projectID string, status string) error {
+	ctx, span := tracing.Start(ctx, "projects.UpdateProjectStatus")
+	defer span.End()
+
+	if status == "hidden" {
+		return ErrProjectHidden
+	}
+
 	query := `
         UPDATE projects
         SET status = $1,
             updated_at = NOW()
         WHERE id = $2
+          AND status <> 'hidden'
+    `
+	recipientsQuery := `
+        SELECT coordinator_id::text FROM projects WHERE id = $1 AND coordinator_id IS NOT NULL
+        UNION
+        SELECT om.user_id::text
+        FROM projects p
+        JOIN organization_members om ON om.organization_id = p.organization_id
+        WHERE p.id = $1
+          AND om.status = 'active'
+          AND om.role IN ('owner', 'admin', 'coordinator')
+        UNION
+        SELECT volunteer_id::text
+        FROM volunteer_enrollments
+        WHERE project_id = $1 AND status IN ('enrolled', 'waitlisted')
     `
-	_, err := s.db.Exec(query, status, projectID)
-	return err
+	return database.WithWriteGuard(func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		result, err := tx.ExecContext(ctx, query, status, projectID)
+		if err != nil {
+			return err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrProjectHidden
+		}
+
+		rows, err := tx.QueryContext(ctx, recipientsQuery, projectID)
+		if err != nil {
+			return err
+		}
+		var userIDs []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			userIDs = append(userIDs, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		err = events.Publish(ctx, tx, events.TypeProjectStatusChanged, events.ProjectStatusChanged{
+			ProjectID: projectID,
+			Status:    status,
+		}, userIDs)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+}
+
+// FindProjectsNear lists active projects within radiusKm of a point, nearest
+// first, limited to the tenant's organization when tenantID is set. It uses
+// the PostGIS location_point index when available and falls back to
+// Haversine over a bounding box otherwise. Like GetAllProjects, it
+// translates names and descriptions into locale.
+func (s *Service) FindProjectsNear(ctx context.Context, lat, lon, radiusKm float64, limit int, tenantID, locale string) ([]models.NearbyProject, error) {
+	ctx, span := tracing.Start(ctx, "projects.FindProjectsNear")
+	defer span.End()
+
+	var hasPostGIS bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'postgis')").Scan(&hasPostGIS)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var query string
+	var args []interface{}
+	if hasPostGIS {
+		query = `
+			SELECT id, ` + translatedColumns + `, coordinator_id, organization_id, latitude, longitude,
+			       location_name, is_remote, timezone, block_schedule_conflicts, start_date, end_date, status, max_volunteers,
+			       t.locale, projects.created_at, projects.updated_at,
+			       ST_Distance(location_point, ST_SetSRID(ST_MakePoint($2, $1), 4326)::geography) / 1000 AS distance_km
+			FROM projects
+			` + translationJoin(6) + `
+			WHERE status = 'active'
+			  AND ST_DWithin(location_point, ST_SetSRID(ST_MakePoint($2, $1), 4326)::geography, $3 * 1000)
+			  AND ($5 = '' OR organization_id = NULLIF($5, '')::uuid)
+			ORDER BY distance_km
+			LIMIT $4
+		`
+		args = []interface{}{lat, lon, radiusKm, limit, tenantID, locale}
+	} else {
+		// Widen the longitude span toward the poles; near them, scan every longitude
+		latDelta := radiusKm / kmPerDegree
+		lonDelta := 180.0
+		if cosLat := math.Cos(lat * math.Pi / 180); cosLat > 0.01 {
+			lonDelta = math.Min(180, radiusKm/(kmPerDegree*cosLat))
+		}
+
+		query = `
+			SELECT * FROM (
+				SELECT id, ` + translatedColumns + `, coordinator_id, organization_id, latitude, longitude,
+				       location_name, is_remote, timezone, block_schedule_conflicts, start_date, end_date, status, max_volunteers,
+				       t.locale, projects.created_at, projects.updated_at,
+				       haversine_distance_km($1, $2, latitude, longitude) AS distance_km
+				FROM projects
+				` + translationJoin(8) + `
+				WHERE status = 'active'
+				  AND latitude BETWEEN $1 - $6 AND $1 + $6
+				  AND longitude BETWEEN $2 - $7 AND $2 + $7
+				  AND ($5 = '' OR organization_id = NULLIF($5, '')::uuid)
+			) nearby
+			WHERE distance_km <= $3
+			ORDER BY distance_km
+			LIMIT $4
+		`
+		args = []interface{}{lat, lon, radiusKm, limit, tenantID, latDelta, lonDelta, locale}
+	}
+
+	var projects []models.NearbyProject
+	err = database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		projects = nil
+		for rows.Next() {
+			var p models.NearbyProject
+			err := rows.Scan(
+				&p.ID,
+				&p.Name,
+				&p.Description,
+				&p.CoordinatorID,
+				&p.OrganizationID,
+				&p.Latitude,
+				&p.Longitude,
+				&p.LocationName,
+				&p.IsRemote,
+				&p.Timezone,
+				&p.BlockScheduleConflicts,
+				&p.StartDate,
+				&p.EndDate,
+				&p.Status,
+				&p.MaxVolunteers,
+				&p.Locale,
+				&p.CreatedAt,
+				&p.UpdatedAt,
+				&p.DistanceKm,
+			)
+			if err != nil {
+				return err
+			}
+			localize(&p.Project)
+			projects = append(projects, p)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// RetireEnded retires active projects that ended more than after ago,
+// taking them out of listings and matching. It returns how many were
+// retired.
+func (s *Service) RetireEnded(ctx context.Context, after time.Duration) (int64, error) {
+	ctx, span := tracing.Start(ctx, "projects.RetireEnded")
+	defer span.End()
+
+	var n int64
+	err := database.WithWriteGuard(func() error {
+		result, err := s.db.ExecContext(ctx, `
+			UPDATE projects
+			SET status = 'retired', updated_at = NOW()
+			WHERE status = 'active'
+			  AND end_date < NOW() - $1 * INTERVAL '1 millisecond'
+		`, after.Milliseconds())
+		if err != nil {
+			return err
+		}
+		n, err = result.RowsAffected()
+		return err
+	})
+	return n, err
 }
@@ -0,0 +1,106 @@
+package projects
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/sanitize"
+	"github.com/civic-weave/backend/internal/tracing"
+)
+
+var ErrTranslationNotFound = errors.New("translation not found")
+
+// GetTranslations lists a project's translations by locale
+func (s *Service) GetTranslations(ctx context.Context, projectID string) ([]models.ProjectTranslation, error) {
+	ctx, span := tracing.Start(ctx, "projects.GetTranslations")
+	defer span.End()
+
+	translations := []models.ProjectTranslation{}
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, `
+			SELECT project_id, locale, name, description, updated_by, created_at, updated_at
+			FROM project_translations
+			WHERE project_id = $1
+			ORDER BY locale
+		`, projectID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		translations = translations[:0]
+		for rows.Next() {
+			var t models.ProjectTranslation
+			if err := rows.Scan(&t.ProjectID, &t.Locale, &t.Name, &t.Description, &t.UpdatedBy, &t.CreatedAt, &t.UpdatedAt); err != nil {
+				return err
+			}
+			translations = append(translations, t)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// SetTranslation creates or replaces a project's translation into locale,
+// which must already be normalized. The description is sanitized like the
+// original's; a nil or blank one shows the original description.
+func (s *Service) SetTranslation(ctx context.Context, projectID, locale, name string, description *string, updatedBy string) (*models.ProjectTranslation, error) {
+	ctx, span := tracing.Start(ctx, "projects.SetTranslation")
+	defer span.End()
+
+	if description != nil {
+		sanitized := sanitize.RichText(*description)
+		description = &sanitized
+		if strings.TrimSpace(sanitized) == "" {
+			description = nil
+		}
+	}
+
+	var t models.ProjectTranslation
+	err := database.WithWriteGuard(func() error {
+		return s.db.QueryRowContext(ctx, `
+			INSERT INTO project_translations (project_id, locale, name, description, updated_by)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (project_id, locale) DO UPDATE
+			SET name = EXCLUDED.name,
+			    description = EXCLUDED.description,
+			    updated_by = EXCLUDED.updated_by,
+			    updated_at = CURRENT_TIMESTAMP
+			RETURNING project_id, locale, name, description, updated_by, created_at, updated_at
+		`, projectID, locale, strings.TrimSpace(name), description, updatedBy).Scan(
+			&t.ProjectID, &t.Locale, &t.Name, &t.Description, &t.UpdatedBy, &t.CreatedAt, &t.UpdatedAt,
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// DeleteTranslation removes a project's translation into locale, so clients
+// preferring it see the original again
+func (s *Service) DeleteTranslation(ctx context.Context, projectID, locale string) error {
+	ctx, span := tracing.Start(ctx, "projects.DeleteTranslation")
+	defer span.End()
+
+	var res sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		res, err = s.db.ExecContext(ctx, `DELETE FROM project_translations WHERE project_id = $1 AND locale = $2`, projectID, locale)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrTranslationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package quotas
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/apikeys"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/gorilla/mux"
+)
+
+// Headers describing the quota closest to running out
+const (
+	HeaderLimit     = "X-Quota-Limit"
+	HeaderRemaining = "X-Quota-Remaining"
+	HeaderReset     = "X-Quota-Reset"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Middleware counts requests made with an API key, or by the signed-in
+// user, and refuses them with 429 once the consumer's daily or
+// monthly quota is used up. Keys get keyLimits unless they set their own;
+// users get userLimits. Responses carry the quota closest to running out
+// in the X-Quota headers, with the reset as a Unix time. Anonymous requests
+// pass through uncounted, and so does everything when counting fails. It
+// must run after apikeys.Middleware.
+func Middleware(s *Service, keyLimits, userLimits Limits) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var consumerType, consumerID string
+			var limits Limits
+			if key := apikeys.FromRequest(r); key != nil {
+				consumerType, consumerID, limits = ConsumerAPIKey, key.ID, keyLimits
+				if key.DailyQuota != nil {
+					limits.Daily = *key.DailyQuota
+				}
+				if key.MonthlyQuota != nil {
+					limits.Monthly = *key.MonthlyQuota
+				}
+			} else if userID := auth.UserID(r); uuidPattern.MatchString(userID) {
+				consumerType, consumerID, limits = ConsumerUser, userID, userLimits
+			} else {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			now := time.Now()
+			usage, err := s.Count(consumerType, consumerID, limits, now)
+			if err != nil {
+				logging.FromRequest(r).Error("Quota count error", "consumerType", consumerType, "consumer", consumerID, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if limit, remaining, reset, ok := closest(usage, limits, now); ok {
+				w.Header().Set(HeaderLimit, strconv.Itoa(limit))
+				w.Header().Set(HeaderRemaining, strconv.FormatInt(remaining, 10))
+				w.Header().Set(HeaderReset, strconv.FormatInt(reset.Unix(), 10))
+				if !usage.Allowed {
+					w.Header().Set("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+				}
+			}
+			if !usage.Allowed {
+				apierror.Write(w, http.StatusTooManyRequests, "API quota exceeded")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// closest returns the limit, remaining requests and reset time of whichever
+// quota has the fewest requests left; when one is used up, the one that
+// resets last. It returns false when both are unlimited.
+func closest(usage *Usage, limits Limits, now time.Time) (limit int, remaining int64, reset time.Time, ok bool) {
+	day, month := periodStarts(now)
+	if limits.Daily > 0 {
+		limit, remaining, reset, ok = limits.Daily, int64(limits.Daily)-usage.Daily, day.AddDate(0, 0, 1), true
+	}
+	if limits.Monthly > 0 {
+		monthlyRemaining := int64(limits.Monthly) - usage.Monthly
+		if !ok || monthlyRemaining < remaining || (monthlyRemaining <= 0 && remaining <= 0) {
+			limit, remaining, reset, ok = limits.Monthly, monthlyRemaining, month.AddDate(0, 1, 0), true
+		}
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	return limit, remaining, reset, ok
+}
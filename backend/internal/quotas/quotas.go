@@ -0,0 +1,150 @@
+package quotas
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+// Consumer types requests are counted for
+const (
+	ConsumerAPIKey = "api_key"
+	ConsumerUser   = "user"
+)
+
+// Usage report periods
+const (
+	PeriodDay   = "day"
+	PeriodMonth = "month"
+)
+
+var ErrInvalidPeriod = errors.New("period must be day or month")
+
+// Limits caps a consumer's requests per UTC day and calendar month; 0 means
+// unlimited
+type Limits struct {
+	Daily   int
+	Monthly int
+}
+
+// Usage is a consumer's served requests in the current day and month,
+// including the request just counted when it was allowed
+type Usage struct {
+	Allowed bool
+	Daily   int64
+	Monthly int64
+}
+
+// Service counts API requests per consumer and UTC day in Postgres, so
+// every instance enforces the same quotas
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// periodStarts returns the UTC day and the first day of its month
+func periodStarts(now time.Time) (day, month time.Time) {
+	day = now.UTC().Truncate(24 * time.Hour)
+	return day, time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Count records a request by the consumer, refusing it when either quota in
+// limits is already used up. Refused requests are counted separately and
+// do not use up quota. Concurrent requests may overshoot a quota slightly.
+func (s *Service) Count(consumerType, consumerID string, limits Limits, now time.Time) (*Usage, error) {
+	day, month := periodStarts(now)
+
+	var usage Usage
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(`
+			SELECT COALESCE(SUM(requests) FILTER (WHERE day = $3::date), 0), COALESCE(SUM(requests), 0)
+			FROM api_usage
+			WHERE consumer_type = $1 AND consumer_id = $2 AND day >= $4::date
+		`, consumerType, consumerID, day.Format("2006-01-02"), month.Format("2006-01-02")).Scan(&usage.Daily, &usage.Monthly)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usage.Allowed = (limits.Daily == 0 || usage.Daily < int64(limits.Daily)) &&
+		(limits.Monthly == 0 || usage.Monthly < int64(limits.Monthly))
+	served, rejected := 0, 1
+	if usage.Allowed {
+		served, rejected = 1, 0
+	}
+
+	err = database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(`
+			INSERT INTO api_usage (consumer_type, consumer_id, day, requests, rejected)
+			VALUES ($1, $2, $3::date, $4, $5)
+			ON CONFLICT (consumer_type, consumer_id, day) DO UPDATE
+			SET requests = api_usage.requests + EXCLUDED.requests,
+			    rejected = api_usage.rejected + EXCLUDED.rejected
+		`, consumerType, consumerID, day.Format("2006-01-02"), served, rejected)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usage.Daily += int64(served)
+	usage.Monthly += int64(served)
+	return &usage, nil
+}
+
+// TopConsumers lists the consumers that made the most requests in the
+// current UTC day or calendar month, most first
+func (s *Service) TopConsumers(period string, limit int, now time.Time) (*models.APIUsageReport, error) {
+	day, month := periodStarts(now)
+	from := day
+	switch period {
+	case PeriodDay:
+	case PeriodMonth:
+		from = month
+	default:
+		return nil, ErrInvalidPeriod
+	}
+
+	report := &models.APIUsageReport{
+		Period:    period,
+		From:      from.Format("2006-01-02"),
+		Consumers: []models.APIUsage{},
+	}
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(`
+			SELECT u.consumer_type, u.consumer_id, COALESCE(k.name, us.name, ''), k.organization_id,
+			       SUM(u.requests), SUM(u.rejected)
+			FROM api_usage u
+			LEFT JOIN organization_api_keys k ON u.consumer_type = 'api_key' AND k.id = u.consumer_id
+			LEFT JOIN users us ON u.consumer_type = 'user' AND us.id = u.consumer_id
+			WHERE u.day >= $1::date
+			GROUP BY u.consumer_type, u.consumer_id, k.name, us.name, k.organization_id
+			ORDER BY SUM(u.requests) DESC, SUM(u.rejected) DESC, u.consumer_id
+			LIMIT $2
+		`, report.From, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		report.Consumers = report.Consumers[:0]
+		for rows.Next() {
+			var u models.APIUsage
+			if err := rows.Scan(&u.ConsumerType, &u.ConsumerID, &u.Name, &u.OrganizationID, &u.Requests, &u.Rejected); err != nil {
+				return err
+			}
+			report.Consumers = append(report.Consumers, u)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
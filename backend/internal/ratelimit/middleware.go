@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/civic-weave/backend/internal/apikeys"
+	"github.com/civic-weave/backend/internal/auth"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/gorilla/mux"
+)
+
+// Policy sets the limits of each kind of request
+type Policy struct {
+	// Auth limits POSTs to /api/auth/ routes, such as sign-ins and password
+	// resets, per client IP
+	Auth Limit
+	// Read limits GET and HEAD requests, and Write all others, per user,
+	// API key or, for anonymous requests, client IP
+	Read  Limit
+	Write Limit
+}
+
+// Middleware refuses requests with 429 and Retry-After once their bucket
+// under policy is empty. Requests pass when the store fails. It must run
+// after routing and after apikeys.Middleware and auth.Middleware.
+func Middleware(store Store, policy Policy) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			kind, limit := "write", policy.Write
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				kind, limit = "read", policy.Read
+			}
+
+			consumer := "ip:" + auth.ClientIP(r)
+			if authRoute(r) {
+				kind, limit = "auth", policy.Auth
+			} else if key := apikeys.FromRequest(r); key != nil {
+				consumer = "key:" + key.ID
+			} else if userID := auth.UserID(r); userID != "" {
+				consumer = "user:" + userID
+			}
+
+			if limit.Requests <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := store.Take(r.Context(), "ratelimit:"+kind+":"+consumer, limit, time.Now())
+			if err != nil {
+				logging.FromRequest(r).Error("Rate limit error", "consumer", consumer, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !result.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())+1))
+				apierror.Write(w, http.StatusTooManyRequests, "Too many requests, please retry later")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func authRoute(r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		return false
+	}
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+	template, err := route.GetPathTemplate()
+	return err == nil && strings.HasPrefix(template, "/api/auth/")
+}
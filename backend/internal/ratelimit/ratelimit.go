@@ -0,0 +1,92 @@
+// Package ratelimit throttles bursts of requests with token buckets. Each
+// bucket holds up to a limit's Requests tokens and refills at Requests per
+// Per; a request takes one token and is refused when none are left.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit allows Requests per Per, in bursts of up to Requests; 0 Requests
+// means unlimited
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Result is the outcome of taking a token
+type Result struct {
+	Allowed bool
+	// Remaining is the whole tokens left in the bucket
+	Remaining int
+	// RetryAfter is how long until a token is free, when one wasn't
+	RetryAfter time.Duration
+}
+
+// Store keeps buckets by key
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// sweepInterval is how often MemoryStore drops buckets that have refilled
+const sweepInterval = time.Minute
+
+// MemoryStore keeps buckets in this process, so each instance limits the
+// requests it serves
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	per     time.Duration
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	capacity := float64(limit.Requests)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+	b.per = limit.Per
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+capacity*elapsed.Seconds()/limit.Per.Seconds())
+		b.updated = now
+	}
+
+	if b.tokens < 1 {
+		wait := (1 - b.tokens) * limit.Per.Seconds() / capacity
+		return Result{RetryAfter: time.Duration(wait * float64(time.Second))}, nil
+	}
+	b.tokens--
+	return Result{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+// sweep drops buckets untouched long enough to have refilled, which behave
+// like new ones
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) >= b.per {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,264 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// takeScript refills and takes from the bucket at KEYS[1] in one step, so
+// instances sharing it never race. ARGV holds the capacity, the refill
+// period and the current time, both in milliseconds. It returns whether
+// the token was taken, the whole tokens left and the milliseconds until
+// one is free.
+const takeScript = `
+local capacity = tonumber(ARGV[1])
+local per = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(bucket[1]) or capacity
+local updated = tonumber(bucket[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - updated) * capacity / per)
+local allowed, wait = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) * per / capacity)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', math.max(now, updated))
+redis.call('PEXPIRE', KEYS[1], per)
+return {allowed, math.floor(tokens), wait}
+`
+
+// redisTimeout bounds each command when the context sets no deadline
+const redisTimeout = time.Second
+
+// maxIdleConns is how many connections RedisStore keeps open between requests
+const maxIdleConns = 16
+
+var takeScriptSHA = func() string {
+	sum := sha1.Sum([]byte(takeScript))
+	return hex.EncodeToString(sum[:])
+}()
+
+// RedisStore keeps buckets in Redis, so every instance shares them. It
+// speaks just enough of the Redis protocol to run takeScript.
+type RedisStore struct {
+	addr     string
+	username string
+	password string
+	db       int
+	tls      *tls.Config
+	idle     chan *redisConn
+}
+
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// redisError is an error reply from the server
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// NewRedisStore connects to the server at a redis:// or rediss:// URL like
+// redis://:password@host:6379/0 on first use
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	s := &RedisStore{addr: u.Host, idle: make(chan *redisConn, maxIdleConns)}
+	if u.Port() == "" {
+		s.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		s.username = u.User.Username()
+		s.password, _ = u.User.Password()
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if s.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid database %q", db)
+		}
+	}
+	if u.Scheme == "rediss" {
+		s.tls = &tls.Config{ServerName: u.Hostname()}
+	}
+	return s, nil
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	args := []string{
+		"1", key,
+		strconv.Itoa(limit.Requests),
+		strconv.FormatInt(limit.Per.Milliseconds(), 10),
+		strconv.FormatInt(now.UnixMilli(), 10),
+	}
+	reply, err := s.do(ctx, append([]string{"EVALSHA", takeScriptSHA}, args...)...)
+	var rerr redisError
+	if errors.As(err, &rerr) && strings.HasPrefix(string(rerr), "NOSCRIPT") {
+		reply, err = s.do(ctx, append([]string{"EVAL", takeScript}, args...)...)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 3 {
+		return Result{}, fmt.Errorf("redis: unexpected reply %v", reply)
+	}
+	var n [3]int64
+	for i, v := range values {
+		if n[i], ok = v.(int64); !ok {
+			return Result{}, fmt.Errorf("redis: unexpected reply %v", reply)
+		}
+	}
+	return Result{Allowed: n[0] == 1, Remaining: int(n[1]), RetryAfter: time.Duration(n[2]) * time.Millisecond}, nil
+}
+
+// do sends one command and reads its reply. Connections that fail are
+// closed rather than reused.
+func (s *RedisStore) do(ctx context.Context, args ...string) (interface{}, error) {
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisTimeout)
+	}
+	conn.SetDeadline(deadline)
+
+	reply, err := conn.command(args...)
+	var rerr redisError
+	if err != nil && !errors.As(err, &rerr) {
+		conn.Close()
+		return nil, err
+	}
+
+	select {
+	case s.idle <- conn:
+	default:
+		conn.Close()
+	}
+	return reply, err
+}
+
+// conn returns an idle connection or dials a new one, signing in and
+// selecting the database
+func (s *RedisStore) conn(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-s.idle:
+		return conn, nil
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+	var raw net.Conn
+	var err error
+	if s.tls != nil {
+		raw, err = (&tls.Dialer{Config: s.tls}).DialContext(ctx, "tcp", s.addr)
+	} else {
+		raw, err = (&net.Dialer{}).DialContext(ctx, "tcp", s.addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{Conn: raw, r: bufio.NewReader(raw)}
+	conn.SetDeadline(time.Now().Add(redisTimeout))
+
+	var setup [][]string
+	switch {
+	case s.username != "" && s.password != "":
+		setup = append(setup, []string{"AUTH", s.username, s.password})
+	case s.password != "":
+		setup = append(setup, []string{"AUTH", s.password})
+	}
+	if s.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(s.db)})
+	}
+	for _, args := range setup {
+		if _, err := conn.command(args...); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (c *redisConn) command(args ...string) (interface{}, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := c.Write([]byte(b.String())); err != nil {
+		return nil, err
+	}
+	return c.reply()
+}
+
+// reply reads one reply: a string, an int64, nil, a redisError or a slice
+// of those
+func (c *redisConn) reply() (interface{}, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			if values[i], err = c.reply(); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
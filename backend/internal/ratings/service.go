@@ -0,0 +1,249 @@
+package ratings
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrProjectNotFound = errors.New("project not found")
+	ErrNotEnrolled     = errors.New("volunteer is not enrolled in the project")
+	ErrNotCompleted    = errors.New("volunteer has not completed the project")
+	ErrInvalidRating   = errors.New("rating must be between 1 and 5")
+	ErrUnknownTag      = errors.New("unknown rating tag")
+)
+
+// Tags coordinators can attach to a rating
+var Tags = []string{"punctual", "reliable", "skilled", "friendly", "leadership", "communication", "team-player"}
+
+// halfLifeDays is how old a rating is when it counts half as much as a new one
+const halfLifeDays = 365
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// Rate records the rater's rating of the volunteer's enrollment in the
+// project, replacing any earlier rating of it. Only completed enrollments
+// can be rated.
+func (s *Service) Rate(projectID, volunteerID, raterID, tenantID string, req models.RateVolunteerRequest) (*models.VolunteerRating, error) {
+	if req.Rating < 1 || req.Rating > 5 {
+		return nil, ErrInvalidRating
+	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	var enrollmentID string
+	var completed bool
+	err = database.WithReadRetry(func() error {
+		return s.db.QueryRow(`
+			SELECT ve.id, COALESCE(COALESCE(ve.completed_at, p.end_date) < NOW(), FALSE)
+			FROM volunteer_enrollments ve
+			JOIN projects p ON p.id = ve.project_id
+			WHERE ve.project_id = $1
+			  AND ve.volunteer_id = $2
+			  AND ve.status IN ('enrolled', 'completed')
+		`, projectID, volunteerID).Scan(&enrollmentID, &completed)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrNotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !completed {
+		return nil, ErrNotCompleted
+	}
+
+	rating := models.VolunteerRating{EnrollmentID: enrollmentID}
+	err = database.WithWriteGuard(func() error {
+		return s.db.QueryRow(`
+			WITH rated AS (
+				INSERT INTO volunteer_ratings (enrollment_id, volunteer_id, project_id, rated_by, rating, tags)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (enrollment_id)
+				DO UPDATE SET
+					rated_by = EXCLUDED.rated_by,
+					rating = EXCLUDED.rating,
+					tags = EXCLUDED.tags,
+					updated_at = CURRENT_TIMESTAMP
+				RETURNING volunteer_id, project_id, rating, tags, rated_by, created_at, updated_at
+			)
+			SELECT r.volunteer_id, u.name, r.project_id, r.rating, r.tags, r.rated_by, r.created_at, r.updated_at
+			FROM rated r
+			JOIN users u ON u.id = r.volunteer_id
+		`, enrollmentID, volunteerID, projectID, raterID, req.Rating, pq.Array(tags)).Scan(
+			&rating.VolunteerID,
+			&rating.VolunteerName,
+			&rating.ProjectID,
+			&rating.Rating,
+			pq.Array(&rating.Tags),
+			&rating.RatedBy,
+			&rating.CreatedAt,
+			&rating.UpdatedAt,
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rating, nil
+}
+
+// GetProjectRatings lists the ratings given to the project's volunteers by
+// volunteer name
+func (s *Service) GetProjectRatings(projectID, tenantID string) ([]models.VolunteerRating, error) {
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT r.enrollment_id, r.volunteer_id, u.name, r.project_id, r.rating, r.tags,
+		       r.rated_by, r.created_at, r.updated_at
+		FROM volunteer_ratings r
+		JOIN users u ON u.id = r.volunteer_id
+		WHERE r.project_id = $1
+		ORDER BY u.name, r.volunteer_id
+	`
+
+	var list []models.VolunteerRating
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, projectID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		list = []models.VolunteerRating{}
+		for rows.Next() {
+			var r models.VolunteerRating
+			if err := rows.Scan(
+				&r.EnrollmentID,
+				&r.VolunteerID,
+				&r.VolunteerName,
+				&r.ProjectID,
+				&r.Rating,
+				pq.Array(&r.Tags),
+				&r.RatedBy,
+				&r.CreatedAt,
+				&r.UpdatedAt,
+			); err != nil {
+				return err
+			}
+			list = append(list, r)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetScores returns the rating score of each volunteer who has been rated.
+// Each rating is weighted by 0.5^(age/halfLifeDays), so a volunteer's score
+// tracks their recent work.
+func (s *Service) GetScores(volunteerIDs []string) (map[string]models.RatingScore, error) {
+	scores := make(map[string]models.RatingScore)
+	if len(volunteerIDs) == 0 {
+		return scores, nil
+	}
+
+	query := `
+		SELECT volunteer_id, SUM(rating * weight) / SUM(weight), COUNT(*)
+		FROM (
+			SELECT volunteer_id, rating,
+			       POWER(0.5, EXTRACT(EPOCH FROM (NOW() - updated_at)) / 86400 / $2) AS weight
+			FROM volunteer_ratings
+			WHERE volunteer_id = ANY($1::uuid[])
+		) weighted
+		GROUP BY volunteer_id
+	`
+
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, pq.Array(volunteerIDs), halfLifeDays)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var volunteerID string
+			var score models.RatingScore
+			if err := rows.Scan(&volunteerID, &score.Score, &score.Count); err != nil {
+				return err
+			}
+			scores[volunteerID] = score
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
+
+// normalizeTags lowercases and de-duplicates tags, rejecting any not in Tags
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if seen[tag] {
+			continue
+		}
+		known := false
+		for _, t := range Tags {
+			if t == tag {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, ErrUnknownTag
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func (s *Service) requireProjectInTenant(projectID, tenantID string) error {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM projects
+			WHERE id = $1
+			  AND ($2 = '' OR organization_id = NULLIF($2, '')::uuid)
+		)
+	`
+
+	var exists bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, projectID, tenantID).Scan(&exists)
+	})
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrProjectNotFound
+	}
+	return nil
+}
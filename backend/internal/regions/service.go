@@ -0,0 +1,348 @@
+package regions
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+var (
+	ErrRegionNotFound   = errors.New("region not found")
+	ErrNameRequired     = errors.New("region name is required")
+	ErrInvalidKind      = errors.New("region kind must be city, ward or region")
+	ErrParentNotFound   = errors.New("parent region not found")
+	ErrInvalidBoundary  = errors.New("boundary must be a GeoJSON Polygon or MultiPolygon with closed rings of valid coordinates")
+	ErrBoundaryTooLarge = errors.New("boundary has too many points")
+)
+
+// maxBoundaryPoints caps the vertices stored for one region
+const maxBoundaryPoints = 50000
+
+var validKinds = map[string]bool{
+	models.RegionKindCity:   true,
+	models.RegionKindWard:   true,
+	models.RegionKindRegion: true,
+}
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+const regionColumns = `id, name, kind, parent_id, created_by, created_at`
+
+func scanRegion(scanner interface{ Scan(...interface{}) error }, r *models.Region) error {
+	return scanner.Scan(
+		&r.ID,
+		&r.Name,
+		&r.Kind,
+		&r.ParentID,
+		&r.CreatedBy,
+		&r.CreatedAt,
+	)
+}
+
+// GetRegions lists regions, optionally limited to one kind
+func (s *Service) GetRegions(kind string) ([]models.Region, error) {
+	if kind != "" && !validKinds[kind] {
+		return nil, ErrInvalidKind
+	}
+
+	query := `
+		SELECT ` + regionColumns + `
+		FROM regions
+		WHERE ($1 = '' OR kind = $1)
+		ORDER BY kind, name
+	`
+
+	return s.queryRegions(query, kind)
+}
+
+// Lookup returns the regions containing the point, smallest area first
+func (s *Service) Lookup(lat, lon float64) ([]models.Region, error) {
+	query := `
+		SELECT r.id, r.name, r.kind, r.parent_id, r.created_by, r.created_at
+		FROM regions r
+		JOIN region_boundaries rb ON rb.region_id = r.id
+		WHERE rb.boundary @> point($2, $1)
+		GROUP BY r.id
+		ORDER BY MIN(area(box(rb.boundary)))
+	`
+
+	return s.queryRegions(query, lat, lon)
+}
+
+func (s *Service) queryRegions(query string, args ...interface{}) ([]models.Region, error) {
+	var regions []models.Region
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		regions = []models.Region{}
+		for rows.Next() {
+			var r models.Region
+			if err := scanRegion(rows, &r); err != nil {
+				return err
+			}
+			regions = append(regions, r)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return regions, nil
+}
+
+// CreateRegion stores a region and its boundary polygons. Only the outer
+// ring of each polygon is kept; holes are ignored.
+func (s *Service) CreateRegion(createdBy string, req models.CreateRegionRequest) (*models.Region, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+	if !validKinds[req.Kind] {
+		return nil, ErrInvalidKind
+	}
+
+	polygons, err := ParseBoundary(req.Boundary)
+	if err != nil {
+		return nil, err
+	}
+
+	var region models.Region
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if req.ParentID != nil {
+			var exists bool
+			err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM regions WHERE id = $1)`, *req.ParentID).Scan(&exists)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return ErrParentNotFound
+			}
+		}
+
+		err = scanRegion(tx.QueryRow(`
+			INSERT INTO regions (name, kind, parent_id, created_by)
+			VALUES ($1, $2, $3, $4)
+			RETURNING `+regionColumns,
+			name, req.Kind, req.ParentID, createdBy,
+		), &region)
+		if err != nil {
+			return err
+		}
+
+		for _, polygon := range polygons {
+			_, err := tx.Exec(`INSERT INTO region_boundaries (region_id, boundary) VALUES ($1, $2::polygon)`, region.ID, polygon)
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &region, nil
+}
+
+// DeleteRegion removes a region, its boundaries and any child regions
+func (s *Service) DeleteRegion(regionID string) error {
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(`DELETE FROM regions WHERE id = $1`, regionID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrRegionNotFound
+	}
+
+	return nil
+}
+
+// GetRegionAnalytics summarizes volunteers, projects, enrollments and logged
+// hours per region. When tenantID is set only the tenant's projects and the
+// volunteers visible to it are counted.
+func (s *Service) GetRegionAnalytics(tenantID string) ([]models.RegionAnalytics, error) {
+	query := `
+		WITH tenant_projects AS (
+			SELECT pr.region_id, p.id, p.status
+			FROM project_regions pr
+			JOIN projects p ON p.id = pr.project_id
+			WHERE $1 = '' OR p.organization_id = NULLIF($1, '')::uuid
+		)
+		SELECT r.id, r.name, r.kind,
+		       (
+		           SELECT COUNT(*)
+		           FROM volunteer_regions vr
+		           WHERE vr.region_id = r.id
+		             AND ($1 = '' OR volunteer_visible_to_org(vr.volunteer_id, NULLIF($1, '')::uuid))
+		       ),
+		       (SELECT COUNT(*) FROM tenant_projects tp WHERE tp.region_id = r.id),
+		       (SELECT COUNT(*) FROM tenant_projects tp WHERE tp.region_id = r.id AND tp.status = 'active'),
+		       (
+		           SELECT COUNT(DISTINCT ve.volunteer_id)
+		           FROM volunteer_enrollments ve
+		           JOIN tenant_projects tp ON tp.id = ve.project_id
+		           WHERE tp.region_id = r.id AND ve.status IN ('enrolled', 'completed')
+		       ),
+		       (
+		           SELECT COALESCE(SUM(vh.hours), 0)
+		           FROM volunteer_hours vh
+		           JOIN tenant_projects tp ON tp.id = vh.project_id
+		           WHERE tp.region_id = r.id
+		       )
+		FROM regions r
+		ORDER BY r.kind, r.name
+	`
+
+	var analytics []models.RegionAnalytics
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		analytics = []models.RegionAnalytics{}
+		for rows.Next() {
+			var a models.RegionAnalytics
+			err := rows.Scan(
+				&a.RegionID,
+				&a.Name,
+				&a.Kind,
+				&a.Volunteers,
+				&a.Projects,
+				&a.ActiveProjects,
+				&a.EnrolledVolunteers,
+				&a.LoggedHours,
+			)
+			if err != nil {
+				return err
+			}
+			analytics = append(analytics, a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return analytics, nil
+}
+
+// geometry is the subset of a GeoJSON geometry object regions accept
+type geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// ParseBoundary converts a GeoJSON Polygon or MultiPolygon into Postgres
+// polygon literals, one per outer ring
+func ParseBoundary(raw json.RawMessage) ([]string, error) {
+	var g geometry
+	if err := json.Unmarshal(raw, &g); err != nil {
+		return nil, ErrInvalidBoundary
+	}
+
+	var rings [][][]float64
+	switch g.Type {
+	case "Polygon":
+		var polygon [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &polygon); err != nil || len(polygon) == 0 {
+			return nil, ErrInvalidBoundary
+		}
+		rings = append(rings, polygon[0])
+	case "MultiPolygon":
+		var multi [][][][]float64
+		if err := json.Unmarshal(g.Coordinates, &multi); err != nil || len(multi) == 0 {
+			return nil, ErrInvalidBoundary
+		}
+		for _, polygon := range multi {
+			if len(polygon) == 0 {
+				return nil, ErrInvalidBoundary
+			}
+			rings = append(rings, polygon[0])
+		}
+	default:
+		return nil, ErrInvalidBoundary
+	}
+
+	var polygons []string
+	total := 0
+	for _, ring := range rings {
+		total += len(ring)
+		if total > maxBoundaryPoints {
+			return nil, ErrBoundaryTooLarge
+		}
+		polygon, err := polygonLiteral(ring)
+		if err != nil {
+			return nil, err
+		}
+		polygons = append(polygons, polygon)
+	}
+
+	return polygons, nil
+}
+
+// polygonLiteral formats a closed GeoJSON ring of [lon, lat] positions as
+// ((lon,lat),...), dropping the repeated closing position
+func polygonLiteral(ring [][]float64) (string, error) {
+	// A closed ring needs at least three distinct positions plus the closing one
+	if len(ring) < 4 {
+		return "", ErrInvalidBoundary
+	}
+	first, last := ring[0], ring[len(ring)-1]
+	if len(first) < 2 || len(last) < 2 || first[0] != last[0] || first[1] != last[1] {
+		return "", ErrInvalidBoundary
+	}
+
+	var b strings.Builder
+	b.WriteByte('(')
+	for i, pos := range ring[:len(ring)-1] {
+		if len(pos) < 2 || pos[0] < -180 || pos[0] > 180 || pos[1] < -90 || pos[1] > 90 {
+			return "", ErrInvalidBoundary
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('(')
+		b.WriteString(strconv.FormatFloat(pos[0], 'f', -1, 64))
+		b.WriteByte(',')
+		b.WriteString(strconv.FormatFloat(pos[1], 'f', -1, 64))
+		b.WriteByte(')')
+	}
+	b.WriteByte(')')
+
+	return b.String(), nil
+}
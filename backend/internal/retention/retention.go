@@ -0,0 +1,361 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/civic-weave/backend/internal/avatars"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/jobs"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+// RunJob is the kind of background job that runs the retention rules
+const RunJob = "retention.run"
+
+// Actions a rule takes on the records it selects
+const (
+	ActionAnonymize = "anonymize"
+	ActionDelete    = "delete"
+)
+
+// Policy sets how long each kind of record is kept; 0 keeps it forever
+type Policy struct {
+	// DryRun makes scheduled runs only report what they would purge
+	DryRun                 bool
+	AnonymizeInactiveAfter time.Duration
+	AuthEvents             time.Duration
+	ExpiredInvitations     time.Duration
+	EndedSessions          time.Duration
+	ClientEvents           time.Duration
+}
+
+// rule anonymizes or deletes the records older than after. count returns
+// how many records purge would change; both get the cutoff as $1.
+type rule struct {
+	name        string
+	action      string
+	description string
+	after       time.Duration
+	count       string
+	purge       func(tx *sql.Tx, cutoff time.Time) (*purged, error)
+}
+
+// purged is what a rule changed, and the avatars it left unreferenced,
+// whose files are deleted once the change commits
+type purged struct {
+	count      int64
+	avatarKeys []string
+}
+
+// Service applies the retention policy
+type Service struct {
+	db             *sql.DB
+	jobsService    *jobs.Service
+	avatarsService *avatars.Service
+	dryRun         bool
+	rules          []rule
+}
+
+type runPayload struct {
+	DryRun *bool `json:"dryRun"`
+}
+
+// NewService registers the retention job with jobsService, which must not
+// be running yet. Rules with a period of 0 are left out.
+func NewService(db *sql.DB, jobsService *jobs.Service, avatarsService *avatars.Service, policy Policy) *Service {
+	s := &Service{db: db, jobsService: jobsService, avatarsService: avatarsService, dryRun: policy.DryRun}
+	for _, r := range []rule{
+		{
+			name:        "anonymize-inactive-accounts",
+			action:      ActionAnonymize,
+			description: "Replace the name, email, locations and profile of volunteers who have not signed in, registered or logged hours since the cutoff; their hours and enrollments stay in reports",
+			after:       policy.AnonymizeInactiveAfter,
+			count:       `SELECT COUNT(*) FROM users u WHERE ` + inactiveAccounts,
+			purge:       anonymizeInactive,
+		},
+		{
+			name:        "purge-auth-events",
+			action:      ActionDelete,
+			description: "Delete sign-in, registration and password events",
+			after:       policy.AuthEvents,
+			count:       `SELECT COUNT(*) FROM auth_events WHERE occurred_at < $1`,
+			purge:       deleteWhere(`DELETE FROM auth_events WHERE occurred_at < $1`),
+		},
+		{
+			name:        "delete-expired-invitations",
+			action:      ActionDelete,
+			description: "Delete organization invitations, and their tokens, that expired or were revoked before the cutoff",
+			after:       policy.ExpiredInvitations,
+			count:       `SELECT COUNT(*) FROM organization_invitations WHERE ` + expiredInvitations,
+			purge:       deleteWhere(`DELETE FROM organization_invitations WHERE ` + expiredInvitations),
+		},
+		{
+			name:        "delete-ended-sessions",
+			action:      ActionDelete,
+			description: "Delete sign-in sessions, with their devices and addresses, that expired or were revoked before the cutoff",
+			after:       policy.EndedSessions,
+			count:       `SELECT COUNT(*) FROM auth_sessions WHERE ` + endedSessions,
+			purge:       deleteWhere(`DELETE FROM auth_sessions WHERE ` + endedSessions),
+		},
+		{
+			name:        "purge-client-events",
+			action:      ActionDelete,
+			description: "Delete frontend interaction events used for analytics",
+			after:       policy.ClientEvents,
+			count:       `SELECT COUNT(*) FROM client_events WHERE received_at < $1`,
+			purge:       deleteWhere(`DELETE FROM client_events WHERE received_at < $1`),
+		},
+	} {
+		if r.after > 0 {
+			s.rules = append(s.rules, r)
+		}
+	}
+
+	jobsService.Register(RunJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			var p runPayload
+			if err := json.Unmarshal(payload, &p); err != nil {
+				return fmt.Errorf("invalid payload %s", payload)
+			}
+			dryRun := s.dryRun
+			if p.DryRun != nil {
+				dryRun = *p.DryRun
+			}
+			run, err := s.Run(ctx, dryRun)
+			if err != nil {
+				return err
+			}
+			for _, result := range run.Results {
+				if result.Count == 0 {
+					continue
+				}
+				if run.DryRun {
+					slog.Info("Retention dry run", "rule", result.Rule, "action", result.Action, "count", result.Count)
+				} else {
+					slog.Info("Retention applied", "rule", result.Rule, "action", result.Action, "count", result.Count)
+				}
+			}
+			return nil
+		},
+		// A rerun picks up where a failed run stopped, so one attempt a
+		// day is enough
+		Retry:   jobs.RetryPolicy{MaxAttempts: 1},
+		Timeout: 30 * time.Minute,
+	})
+	return s
+}
+
+// Policy lists the rules in effect
+func (s *Service) Policy() *models.RetentionPolicy {
+	policy := &models.RetentionPolicy{DryRun: s.dryRun, Rules: []models.RetentionRule{}}
+	for _, r := range s.rules {
+		policy.Rules = append(policy.Rules, models.RetentionRule{
+			Name:        r.name,
+			Action:      r.action,
+			Description: r.description,
+			After:       r.after.String(),
+		})
+	}
+	return policy
+}
+
+// QueueRun queues a run; with a nil dryRun it runs in the configured mode
+func (s *Service) QueueRun(dryRun *bool) (*models.Job, error) {
+	return s.jobsService.Enqueue(RunJob, runPayload{DryRun: dryRun})
+}
+
+// Run applies each rule in its own transaction, or in a dry run only counts
+// what each would purge, and records the run
+func (s *Service) Run(ctx context.Context, dryRun bool) (*models.RetentionRun, error) {
+	run := &models.RetentionRun{DryRun: dryRun, Results: []models.RetentionResult{}, StartedAt: time.Now()}
+	for _, r := range s.rules {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		cutoff := run.StartedAt.Add(-r.after)
+		result := &purged{}
+		var err error
+		if dryRun {
+			err = database.WithReadRetry(func() error {
+				return s.db.QueryRowContext(ctx, r.count, cutoff).Scan(&result.count)
+			})
+		} else {
+			err = database.WithWriteGuard(func() error {
+				tx, err := s.db.BeginTx(ctx, nil)
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback()
+
+				result, err = r.purge(tx, cutoff)
+				if err != nil {
+					return err
+				}
+				return tx.Commit()
+			})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.name, err)
+		}
+		for _, key := range result.avatarKeys {
+			s.avatarsService.DeleteFiles(ctx, key)
+		}
+		run.Results = append(run.Results, models.RetentionResult{Rule: r.name, Action: r.action, Cutoff: cutoff, Count: result.count})
+	}
+
+	results, err := json.Marshal(run.Results)
+	if err != nil {
+		return nil, err
+	}
+	err = database.WithWriteGuard(func() error {
+		return s.db.QueryRow(`
+			INSERT INTO retention_runs (dry_run, results, started_at)
+			VALUES ($1, $2, $3)
+			RETURNING id, finished_at
+		`, run.DryRun, results, run.StartedAt).Scan(&run.ID, &run.FinishedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// GetRuns lists the most recent runs first
+func (s *Service) GetRuns(limit int) ([]models.RetentionRun, error) {
+	runs := []models.RetentionRun{}
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(`
+			SELECT id, dry_run, results, started_at, finished_at
+			FROM retention_runs
+			ORDER BY started_at DESC
+			LIMIT $1
+		`, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		runs = runs[:0]
+		for rows.Next() {
+			var run models.RetentionRun
+			var results []byte
+			if err := rows.Scan(&run.ID, &run.DryRun, &results, &run.StartedAt, &run.FinishedAt); err != nil {
+				return err
+			}
+			if err := json.Unmarshal(results, &run.Results); err != nil {
+				return err
+			}
+			runs = append(runs, run)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func deleteWhere(statement string) func(tx *sql.Tx, cutoff time.Time) (*purged, error) {
+	return func(tx *sql.Tx, cutoff time.Time) (*purged, error) {
+		res, err := tx.Exec(statement, cutoff)
+		if err != nil {
+			return nil, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		return &purged{count: n}, nil
+	}
+}
+
+// expiredInvitations selects invitations that expired unanswered or were
+// revoked before $1; accepted ones record how members joined and are kept
+const expiredInvitations = `
+	((status = 'pending' AND expires_at < $1) OR (status = 'revoked' AND revoked_at < $1))
+`
+
+// endedSessions selects sessions that expired or were revoked before $1
+const endedSessions = `
+	(expires_at < $1 OR revoked_at < $1)
+`
+
+// inactiveAccounts selects volunteers, aliased u, with no sign-in,
+// registration, profile change or logged hours since $1. Coordinators and
+// admins are never anonymized: projects and organizations name them.
+const inactiveAccounts = `
+	u.role = 'volunteer'
+	AND u.anonymized_at IS NULL
+	AND NOT u.sandbox
+	AND GREATEST(u.created_at, u.updated_at, COALESCE(u.last_login_at, u.created_at)) < $1
+	AND NOT EXISTS (SELECT 1 FROM volunteer_hours vh WHERE vh.volunteer_id = u.id AND vh.created_at >= $1)
+`
+
+// anonymizeInactive replaces inactive volunteers' personal details with
+// placeholders and deletes the records that only describe the person,
+// returning their avatars for deletion. Their enrollments, hours and
+// ratings stay, so organization reports still add up.
+func anonymizeInactive(tx *sql.Tx, cutoff time.Time) (*purged, error) {
+	rows, err := tx.Query(`SELECT u.id, u.avatar_key FROM users u WHERE `+inactiveAccounts+` FOR UPDATE`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	result := &purged{}
+	var ids []string
+	for rows.Next() {
+		var id string
+		var avatarKey *string
+		if err := rows.Scan(&id, &avatarKey); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+		if avatarKey != nil {
+			result.avatarKeys = append(result.avatarKeys, *avatarKey)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	// Deleting the saved locations clears the location mirrored onto users
+	statements := []string{
+		`DELETE FROM volunteer_locations WHERE volunteer_id = ANY($1::uuid[])`,
+		`DELETE FROM volunteer_availability_rules WHERE volunteer_id = ANY($1::uuid[])`,
+		`DELETE FROM volunteer_availability_blackouts WHERE volunteer_id = ANY($1::uuid[])`,
+		`DELETE FROM calendar_feed_tokens WHERE volunteer_id = ANY($1::uuid[])`,
+		`DELETE FROM auth_events WHERE user_id = ANY($1::uuid[])`,
+		`DELETE FROM auth_sessions WHERE user_id = ANY($1::uuid[])`,
+		`DELETE FROM email_verification_tokens WHERE user_id = ANY($1::uuid[])`,
+		`DELETE FROM password_reset_tokens WHERE user_id = ANY($1::uuid[])`,
+		`DELETE FROM user_identities WHERE user_id = ANY($1::uuid[])`,
+		`UPDATE users
+		 SET name = 'Anonymized volunteer',
+		     email = 'anonymized-' || id || '@anonymized.invalid',
+		     password_hash = NULL,
+		     latitude = NULL, longitude = NULL, location_name = NULL, max_travel_km = NULL,
+		     avatar_key = NULL, avatar_url = NULL, avatar_variants = NULL,
+		     leaderboard_opt_in = FALSE,
+		     public_name = FALSE, public_badges = FALSE, public_skill_categories = FALSE, public_total_hours = FALSE,
+		     anonymized_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = ANY($1::uuid[])`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement, pq.Array(ids)); err != nil {
+			return nil, err
+		}
+	}
+	result.count = int64(len(ids))
+	return result, nil
+}
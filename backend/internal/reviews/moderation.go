@@ -0,0 +1,42 @@
+package reviews
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Moderator screens review comments before they are published. A non-empty
+// reason holds the review for a moderator; an error holds it as well, so a
+// failing moderation service never lets text through unchecked.
+type Moderator interface {
+	Moderate(text string) (reason string, err error)
+}
+
+// TermModerator holds comments that contain any of its terms, matched as
+// whole words regardless of case
+type TermModerator struct {
+	terms map[string]bool
+}
+
+// NewTermModerator builds a TermModerator, ignoring blank terms
+func NewTermModerator(terms []string) *TermModerator {
+	m := &TermModerator{terms: make(map[string]bool)}
+	for _, term := range terms {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+			m.terms[term] = true
+		}
+	}
+	return m
+}
+
+func (m *TermModerator) Moderate(text string) (string, error) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, word := range words {
+		if m.terms[word] {
+			return "contains blocked term", nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,274 @@
+package reviews
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+var (
+	ErrProjectNotFound  = errors.New("project not found")
+	ErrReviewNotFound   = errors.New("review not found")
+	ErrNotEnrolled      = errors.New("volunteer is not enrolled in the project")
+	ErrNotCompleted     = errors.New("volunteer has not completed the project")
+	ErrInvalidRating    = errors.New("ratings must be between 1 and 5")
+	ErrNoOrganization   = errors.New("project has no organization to rate")
+	ErrCommentTooLong   = errors.New("comment must be at most 2000 characters")
+	ErrInvalidAction    = errors.New("action must be publish or remove")
+	ErrReviewNotPending = errors.New("review is not awaiting moderation")
+)
+
+const maxCommentLength = 2000
+
+const reviewColumns = `
+	id, project_id, volunteer_id, project_rating, organization_rating, comment,
+	status, moderation_reason, created_at, updated_at
+`
+
+func scanReview(row interface{ Scan(...interface{}) error }, r *models.ProjectReview) error {
+	return row.Scan(
+		&r.ID,
+		&r.ProjectID,
+		&r.VolunteerID,
+		&r.ProjectRating,
+		&r.OrganizationRating,
+		&r.Comment,
+		&r.Status,
+		&r.ModerationReason,
+		&r.CreatedAt,
+		&r.UpdatedAt,
+	)
+}
+
+type Service struct {
+	db        *sql.DB
+	moderator Moderator
+}
+
+func NewService(db *sql.DB, moderator Moderator) *Service {
+	return &Service{db: db, moderator: moderator}
+}
+
+// SubmitReview records the volunteer's review of a project they completed,
+// replacing any earlier review of it. Comments the moderator flags hold the
+// review as pending; its ratings still count but the comment is not shown
+// until a moderator publishes it.
+func (s *Service) SubmitReview(projectID, volunteerID, tenantID string, req models.SubmitReviewRequest) (*models.ProjectReview, error) {
+	if req.ProjectRating < 1 || req.ProjectRating > 5 {
+		return nil, ErrInvalidRating
+	}
+	if req.OrganizationRating != nil && (*req.OrganizationRating < 1 || *req.OrganizationRating > 5) {
+		return nil, ErrInvalidRating
+	}
+	comment := req.Comment
+	if comment != nil {
+		trimmed := strings.TrimSpace(*comment)
+		if len([]rune(trimmed)) > maxCommentLength {
+			return nil, ErrCommentTooLong
+		}
+		comment = &trimmed
+		if trimmed == "" {
+			comment = nil
+		}
+	}
+
+	var enrollmentID sql.NullString
+	var completed, hasOrganization bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(`
+			SELECT ve.id,
+			       COALESCE(COALESCE(ve.completed_at, p.end_date) < NOW(), FALSE),
+			       p.organization_id IS NOT NULL
+			FROM projects p
+			LEFT JOIN volunteer_enrollments ve
+			       ON ve.project_id = p.id AND ve.volunteer_id = $2 AND ve.status IN ('enrolled', 'completed')
+			WHERE p.id = $1
+			  AND ($3 = '' OR p.organization_id = NULLIF($3, '')::uuid)
+		`, projectID, volunteerID, tenantID).Scan(&enrollmentID, &completed, &hasOrganization)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !enrollmentID.Valid {
+		return nil, ErrNotEnrolled
+	}
+	if !completed {
+		return nil, ErrNotCompleted
+	}
+	if req.OrganizationRating != nil && !hasOrganization {
+		return nil, ErrNoOrganization
+	}
+
+	status := "published"
+	var reason *string
+	if comment != nil {
+		flagged, err := s.moderator.Moderate(*comment)
+		if err != nil {
+			slog.Error("Review moderation error", "project", projectID, "volunteer", volunteerID, "error", err)
+			flagged = "moderation unavailable"
+		}
+		if flagged != "" {
+			status, reason = "pending", &flagged
+		}
+	}
+
+	var review models.ProjectReview
+	err = database.WithWriteGuard(func() error {
+		return scanReview(s.db.QueryRow(`
+			INSERT INTO project_reviews (
+				enrollment_id, project_id, volunteer_id, project_rating, organization_rating,
+				comment, status, moderation_reason
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (enrollment_id)
+			DO UPDATE SET
+				project_rating = EXCLUDED.project_rating,
+				organization_rating = EXCLUDED.organization_rating,
+				comment = EXCLUDED.comment,
+				status = EXCLUDED.status,
+				moderation_reason = EXCLUDED.moderation_reason,
+				moderated_by = NULL,
+				moderated_at = NULL,
+				updated_at = CURRENT_TIMESTAMP
+			RETURNING `+reviewColumns,
+			enrollmentID.String, projectID, volunteerID, req.ProjectRating, req.OrganizationRating,
+			comment, status, reason,
+		), &review)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// GetProjectReviews lists a project's published reviews, newest first,
+// without naming the reviewers
+func (s *Service) GetProjectReviews(projectID, tenantID string) ([]models.ProjectReview, error) {
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + reviewColumns + `
+		FROM project_reviews
+		WHERE project_id = $1 AND status = 'published'
+		ORDER BY created_at DESC, id
+	`
+
+	list, err := s.queryReviews(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].VolunteerID = ""
+	}
+	return list, nil
+}
+
+// GetPendingReviews lists reviews held for moderation, oldest first
+func (s *Service) GetPendingReviews() ([]models.ProjectReview, error) {
+	query := `SELECT ` + reviewColumns + `
+		FROM project_reviews
+		WHERE status = 'pending'
+		ORDER BY created_at, id
+	`
+
+	return s.queryReviews(query)
+}
+
+// ModerateReview publishes or removes a review held for moderation
+func (s *Service) ModerateReview(reviewID, moderatorID string, req models.ModerateReviewRequest) (*models.ProjectReview, error) {
+	var status string
+	switch req.Action {
+	case "publish":
+		status = "published"
+	case "remove":
+		status = "removed"
+	default:
+		return nil, ErrInvalidAction
+	}
+
+	var review models.ProjectReview
+	err := database.WithWriteGuard(func() error {
+		return scanReview(s.db.QueryRow(`
+			UPDATE project_reviews
+			SET status = $2, moderated_by = $3, moderated_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			WHERE id::text = $1 AND status = 'pending'
+			RETURNING `+reviewColumns,
+			reviewID, status, moderatorID,
+		), &review)
+	})
+	if err == sql.ErrNoRows {
+		var exists bool
+		err = database.WithReadRetry(func() error {
+			return s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM project_reviews WHERE id::text = $1)`, reviewID).Scan(&exists)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrReviewNotPending
+		}
+		return nil, ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (s *Service) queryReviews(query string, args ...interface{}) ([]models.ProjectReview, error) {
+	var list []models.ProjectReview
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		list = []models.ProjectReview{}
+		for rows.Next() {
+			var r models.ProjectReview
+			if err := scanReview(rows, &r); err != nil {
+				return err
+			}
+			list = append(list, r)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *Service) requireProjectInTenant(projectID, tenantID string) error {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM projects
+			WHERE id = $1
+			  AND ($2 = '' OR organization_id = NULLIF($2, '')::uuid)
+		)
+	`
+
+	var exists bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, projectID, tenantID).Scan(&exists)
+	})
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrProjectNotFound
+	}
+	return nil
+}
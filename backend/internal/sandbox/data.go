@@ -0,0 +1,70 @@
+package sandbox
+
+// Word lists the generator draws from. Their order is part of what makes a
+// seed repeatable, so new entries go at the end.
+
+var firstNames = []string{
+	"Alex", "Jordan", "Taylor", "Morgan", "Casey", "Riley", "Avery", "Quinn",
+	"Blake", "Drew", "Cameron", "Skyler", "Dakota", "Reese", "Sage", "River",
+	"Maria", "James", "Emma", "Michael", "Sophia", "William", "Olivia", "David",
+	"Ava", "Joseph", "Isabella", "Daniel", "Mia", "Matthew", "Charlotte", "Noah",
+	"Priya", "Raj", "Ananya", "Vikram", "Aisha", "Omar", "Fatima", "Mohammed",
+	"Mei", "Wei", "Yuki", "Hiroshi", "Sofia", "Carlos", "Ana", "Luis",
+	"Grace", "Ethan", "Lily", "Zoe", "Lucas", "Chloe", "Amara", "Kwame",
+}
+
+var lastNames = []string{
+	"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis",
+	"Rodriguez", "Martinez", "Hernandez", "Lopez", "Wilson", "Anderson", "Thomas", "Moore",
+	"Patel", "Kumar", "Singh", "Sharma", "Khan", "Ali", "Hassan", "Ahmed",
+	"Chen", "Wang", "Zhang", "Liu", "Kim", "Park", "Nguyen", "Tran",
+	"Cohen", "Levy", "O'Brien", "Murphy", "Kelly", "Santos", "Silva", "Okafor",
+	"Mensah", "Ivanov", "Petrov", "Dubois", "Tremblay", "Roy", "Gagnon", "Leblanc",
+}
+
+// cities are listed from most to least populous; volunteers and projects
+// favour the first ones
+var cities = []struct {
+	name     string
+	lat, lon float64
+	timezone string
+}{
+	{"San Francisco, CA", 37.7749, -122.4194, "America/Los_Angeles"},
+	{"New York, NY", 40.7128, -74.0060, "America/New_York"},
+	{"Chicago, IL", 41.8781, -87.6298, "America/Chicago"},
+	{"Toronto, ON", 43.6532, -79.3832, "America/Toronto"},
+	{"Seattle, WA", 47.6062, -122.3321, "America/Los_Angeles"},
+	{"Austin, TX", 30.2672, -97.7431, "America/Chicago"},
+	{"Boston, MA", 42.3601, -71.0589, "America/New_York"},
+	{"Denver, CO", 39.7392, -104.9903, "America/Denver"},
+	{"Atlanta, GA", 33.7490, -84.3880, "America/New_York"},
+	{"Portland, OR", 45.5152, -122.6784, "America/Los_Angeles"},
+	{"Ottawa, ON", 45.4215, -75.6972, "America/Toronto"},
+	{"Oakland, CA", 37.8044, -122.2712, "America/Los_Angeles"},
+}
+
+var activities = []string{
+	"Community Garden Build", "Food Bank Sorting", "Literacy Tutoring",
+	"Park Cleanup", "Youth Coding Club", "Senior Tech Help Desk",
+	"Home Repair Day", "Youth Soccer Coaching", "Tax Prep Clinic",
+	"Shelter Meal Service", "Voter Registration Drive", "Nonprofit Website Refresh",
+	"Grant Writing Sprint", "Neighbourhood Mural", "Tree Planting",
+	"Winter Clothing Drive", "ESL Conversation Circle", "Emergency Preparedness Fair",
+}
+
+// fallbackSkills fill an empty skills catalogue, so there is something to
+// match on
+var fallbackSkills = []struct {
+	name, description, category string
+}{
+	{"Teaching", "Teaching and mentoring", "Education"},
+	{"Event Planning", "Planning and organizing events", "Coordination"},
+	{"Communication", "Effective communication skills", "Soft Skills"},
+	{"Fundraising", "Raising funds for causes", "Finance"},
+	{"Graphic Design", "Visual design and graphics", "Design"},
+	{"Carpentry", "Building and repairing with wood", "Trades"},
+	{"Gardening", "Planting and tending gardens", "Outdoors"},
+	{"Cooking", "Preparing meals for groups", "Hospitality"},
+	{"Python", "Python programming language", "Programming"},
+	{"First Aid", "Basic emergency medical care", "Healthcare"},
+}
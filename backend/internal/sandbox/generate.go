@@ -0,0 +1,698 @@
+package sandbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/matching"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+// insertBatchSize is how many rows each INSERT writes
+const insertBatchSize = 1000
+
+const day = 24 * time.Hour
+
+type skill struct {
+	id, name, description, category string
+}
+
+type user struct {
+	id, email, name, role, timezone string
+	profileComplete                 bool
+	createdAt                       time.Time
+	city                            int
+}
+
+type location struct {
+	volunteerID, name string
+	lat, lon          float64
+}
+
+type volunteerSkill struct {
+	volunteerID, skillID string
+	score                float64
+	verifiedBy           *string
+	verifiedAt           *time.Time
+}
+
+type project struct {
+	id, name, description, coordinatorID, timezone, status string
+	lat, lon                                               *float64
+	locationName                                           *string
+	remote                                                 bool
+	start, end, createdAt                                  time.Time
+	maxVolunteers                                          int64
+	city                                                   int
+}
+
+type projectSkill struct {
+	projectID, skillID string
+	required           bool
+	weight             float64
+}
+
+type enrollment struct {
+	id, volunteerID, projectID, kind, status, initiatedBy string
+	createdAt, updatedAt                                  time.Time
+	approvedAt, completedAt                               *time.Time
+}
+
+type hoursEntry struct {
+	enrollmentID, volunteerID, projectID string
+	hours                                float64
+	workedOn                             time.Time
+}
+
+// generator draws the whole dataset from one seeded source, so the same
+// params and catalogue always give the same rows
+type generator struct {
+	r   *rand.Rand
+	now time.Time
+	// skillIDs is ordered from most to least popular
+	skillIDs  []string
+	skillZipf *rand.Zipf
+	cityZipf  *rand.Zipf
+
+	skills          []skill
+	coordinators    []user
+	volunteers      []user
+	locations       []location
+	volunteerSkills []volunteerSkill
+	projects        []project
+	projectSkills   []projectSkill
+	enrollments     []enrollment
+	hours           []hoursEntry
+}
+
+// Generate creates synthetic coordinators, volunteers with skills and home
+// locations, projects needing skills, and enrollment histories with logged
+// hours, all in one transaction, and returns what it created. Skill
+// popularity and city sizes follow a Zipf distribution, so a few skills and
+// cities dominate as they do in real data. It returns ErrAlreadyGenerated
+// if sandbox users or projects exist.
+func (s *Service) Generate(ctx context.Context, p Params) (*models.SandboxSummary, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM users WHERE sandbox) OR EXISTS (SELECT 1 FROM projects WHERE sandbox)
+	`).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrAlreadyGenerated
+	}
+
+	catalogue, err := loadSkillIDs(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	g := newGenerator(p.Seed, catalogue, time.Now().UTC())
+	g.generate(p)
+	if err := g.insert(ctx, tx); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.jobsService.EnqueueUnique(matching.RefreshSkillVectorsJob, "all", nil); err != nil {
+		logging.FromContext(ctx).Error("Queue skill vector refresh error", "error", err)
+	}
+	return &models.SandboxSummary{
+		Coordinators: len(g.coordinators),
+		Volunteers:   len(g.volunteers),
+		Projects:     len(g.projects),
+		Skills:       len(g.skills),
+		Enrollments:  len(g.enrollments),
+		Hours:        len(g.hours),
+	}, nil
+}
+
+// loadSkillIDs returns the catalogue's skill IDs ordered by name
+func loadSkillIDs(ctx context.Context, tx *sql.Tx) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM skills ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func newGenerator(seed int64, catalogue []string, now time.Time) *generator {
+	g := &generator{
+		r:   rand.New(rand.NewSource(seed)),
+		now: now.Truncate(time.Second),
+	}
+	if len(catalogue) == 0 {
+		for _, s := range fallbackSkills {
+			g.skills = append(g.skills, skill{id: g.uuid(), name: s.name, description: s.description, category: s.category})
+			catalogue = append(catalogue, g.skills[len(g.skills)-1].id)
+		}
+	}
+	// Popularity should not follow the alphabet
+	for _, i := range g.r.Perm(len(catalogue)) {
+		g.skillIDs = append(g.skillIDs, catalogue[i])
+	}
+	g.skillZipf = rand.NewZipf(g.r, 1.3, 2, uint64(len(g.skillIDs)-1))
+	g.cityZipf = rand.NewZipf(g.r, 1.2, 1, uint64(len(cities)-1))
+	return g
+}
+
+func (g *generator) generate(p Params) {
+	for i := 0; i < p.Coordinators; i++ {
+		g.coordinators = append(g.coordinators, g.person("coordinator", i))
+	}
+	for i := 0; i < p.Volunteers; i++ {
+		g.volunteer(i)
+	}
+	// Every project needs a coordinator
+	for i := 0; i < p.Projects && len(g.coordinators) > 0; i++ {
+		g.project()
+	}
+	g.enroll(p.EnrollmentsPerVolunteer)
+}
+
+// uuid draws a version 4 UUID from the seeded source
+func (g *generator) uuid() string {
+	var b [16]byte
+	g.r.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// between returns a time in [from, to), or from if the range is empty
+func (g *generator) between(from, to time.Time) time.Time {
+	if !to.After(from) {
+		return from
+	}
+	return from.Add(time.Duration(g.r.Int63n(int64(to.Sub(from))))).Truncate(time.Second)
+}
+
+func (g *generator) daysAgo(newest, oldest int) time.Time {
+	return g.between(g.now.Add(-time.Duration(oldest)*day), g.now.Add(-time.Duration(newest)*day))
+}
+
+func (g *generator) person(role string, i int) user {
+	first := firstNames[g.r.Intn(len(firstNames))]
+	last := lastNames[g.r.Intn(len(lastNames))]
+	local := strings.ToLower(first + "." + strings.NewReplacer("'", "", " ", "").Replace(last))
+	if role == "coordinator" {
+		local = "coordinator." + local
+	}
+	city := int(g.cityZipf.Uint64())
+	return user{
+		id:              g.uuid(),
+		email:           fmt.Sprintf("%s.%d@%s", local, i+1, EmailDomain),
+		name:            first + " " + last,
+		role:            role,
+		timezone:        cities[city].timezone,
+		profileComplete: role == "coordinator" || g.r.Float64() < 0.85,
+		createdAt:       g.daysAgo(0, 730),
+		city:            city,
+	}
+}
+
+// jitter moves a city centre by up to about 15 km
+func (g *generator) jitter(lat, lon float64) (float64, float64) {
+	return round(lat+(g.r.Float64()-0.5)*0.27, 6), round(lon+(g.r.Float64()-0.5)*0.27, 6)
+}
+
+func (g *generator) volunteer(i int) {
+	v := g.person("volunteer", i)
+	g.volunteers = append(g.volunteers, v)
+
+	if g.r.Float64() < 0.9 {
+		lat, lon := g.jitter(cities[v.city].lat, cities[v.city].lon)
+		g.locations = append(g.locations, location{volunteerID: v.id, name: cities[v.city].name, lat: lat, lon: lon})
+	}
+
+	seen := make(map[string]bool)
+	for n := 1 + g.r.Intn(6); n > 0; n-- {
+		skillID := g.skillIDs[g.skillZipf.Uint64()]
+		if seen[skillID] {
+			continue
+		}
+		seen[skillID] = true
+
+		// 20% beginners, 60% intermediate, 20% advanced
+		var score float64
+		switch x := g.r.Float64(); {
+		case x < 0.2:
+			score = 0.3 + g.r.Float64()*0.2
+		case x < 0.8:
+			score = 0.5 + g.r.Float64()*0.2
+		default:
+			score = 0.7 + g.r.Float64()*0.3
+		}
+		vs := volunteerSkill{volunteerID: v.id, skillID: skillID, score: round(score, 2)}
+		if len(g.coordinators) > 0 && g.r.Float64() < 0.2 {
+			verifiedAt := g.between(v.createdAt, g.now)
+			vs.verifiedBy = &g.coordinators[g.r.Intn(len(g.coordinators))].id
+			vs.verifiedAt = &verifiedAt
+		}
+		g.volunteerSkills = append(g.volunteerSkills, vs)
+	}
+}
+
+func (g *generator) project() {
+	activity := activities[g.r.Intn(len(activities))]
+	city := int(g.cityZipf.Uint64())
+	p := project{
+		id:            g.uuid(),
+		description:   fmt.Sprintf("Sandbox project: %s with neighbours in %s.", strings.ToLower(activity), cities[city].name),
+		coordinatorID: g.coordinators[g.r.Intn(len(g.coordinators))].id,
+		timezone:      cities[city].timezone,
+		maxVolunteers: int64(5 + g.r.Intn(46)),
+		city:          city,
+	}
+
+	if g.r.Float64() < 0.15 {
+		p.remote = true
+		p.name = activity + " (Remote)"
+	} else {
+		lat, lon := g.jitter(cities[city].lat, cities[city].lon)
+		name := cities[city].name
+		p.lat, p.lon, p.locationName = &lat, &lon, &name
+		p.name = fmt.Sprintf("%s (%s)", activity, strings.SplitN(name, ",", 2)[0])
+	}
+
+	switch x := g.r.Float64(); {
+	case x < 0.15:
+		p.status = "retired"
+		p.end = g.daysAgo(1, 365)
+		p.start = p.end.Add(-time.Duration(14+g.r.Intn(107)) * day)
+		p.createdAt = p.start.Add(-time.Duration(7+g.r.Intn(39)) * day)
+	case x < 0.25:
+		p.status = "draft"
+		p.start = g.now.Add(time.Duration(7+g.r.Intn(54)) * day)
+		p.end = p.start.Add(time.Duration(14+g.r.Intn(107)) * day)
+		p.createdAt = g.daysAgo(0, 14)
+	default:
+		p.status = "active"
+		p.start = g.daysAgo(0, 120)
+		p.end = g.now.Add(time.Duration(14+g.r.Intn(167)) * day)
+		p.createdAt = p.start.Add(-time.Duration(7+g.r.Intn(39)) * day)
+	}
+	g.projects = append(g.projects, p)
+
+	seen := make(map[string]bool)
+	for n := 1 + g.r.Intn(4); n > 0; n-- {
+		skillID := g.skillIDs[g.skillZipf.Uint64()]
+		if seen[skillID] {
+			continue
+		}
+		seen[skillID] = true
+		g.projectSkills = append(g.projectSkills, projectSkill{
+			projectID: p.id,
+			skillID:   skillID,
+			required:  g.r.Float64() < 0.6,
+			weight:    round(0.5+g.r.Float64()*0.5, 2),
+		})
+	}
+}
+
+// enroll gives each volunteer between none and twice perVolunteer
+// enrollments on projects past the draft stage, mostly in their own city
+func (g *generator) enroll(perVolunteer int) {
+	var open []int
+	byCity := make([][]int, len(cities))
+	for i, p := range g.projects {
+		if p.status == "draft" {
+			continue
+		}
+		open = append(open, i)
+		if !p.remote {
+			byCity[p.city] = append(byCity[p.city], i)
+		}
+	}
+	if len(open) == 0 {
+		return
+	}
+
+	for _, v := range g.volunteers {
+		n := g.r.Intn(2*perVolunteer + 1)
+		if n > len(open) {
+			n = len(open)
+		}
+		seen := make(map[int]bool)
+		for attempt := 0; len(seen) < n && attempt < 4*n; attempt++ {
+			candidates := open
+			if local := byCity[v.city]; len(local) > 0 && g.r.Float64() < 0.7 {
+				candidates = local
+			}
+			i := candidates[g.r.Intn(len(candidates))]
+			if seen[i] {
+				continue
+			}
+			seen[i] = true
+			g.enrollment(v, &g.projects[i])
+		}
+	}
+}
+
+func (g *generator) enrollment(v user, p *project) {
+	from := p.createdAt
+	if v.createdAt.After(from) {
+		from = v.createdAt
+	}
+	until := p.end
+	if g.now.Before(until) {
+		until = g.now
+	}
+	if !until.After(from) {
+		// The volunteer joined after the project ended
+		return
+	}
+
+	e := enrollment{id: g.uuid(), volunteerID: v.id, projectID: p.id, createdAt: g.between(from, until)}
+	x := g.r.Float64()
+	if p.status == "retired" {
+		switch {
+		case x < 0.70:
+			e.status = "enrolled"
+		case x < 0.78:
+			e.status = "tl_rejected"
+		case x < 0.85:
+			e.status = "v_rejected"
+		default:
+			e.status = "expired"
+		}
+	} else {
+		switch {
+		case x < 0.55:
+			e.status = "enrolled"
+		case x < 0.73:
+			e.status = "requested"
+		case x < 0.85:
+			e.status = "invited"
+		case x < 0.91:
+			e.status = "tl_rejected"
+		case x < 0.95:
+			e.status = "v_rejected"
+		default:
+			e.status = "expired"
+		}
+	}
+
+	// Invitations come from the coordinator, requests from the volunteer;
+	// either may have expired
+	invited := e.status == "invited" || e.status == "v_rejected" || (e.status == "expired" && g.r.Float64() < 0.5)
+	if invited {
+		e.kind, e.initiatedBy = "tl_invitation", p.coordinatorID
+	} else {
+		e.kind, e.initiatedBy = "volunteer_request", v.id
+	}
+
+	e.updatedAt = e.createdAt
+	if e.status != "requested" && e.status != "invited" {
+		e.updatedAt = g.between(e.createdAt, minTime(e.createdAt.Add(7*day), g.now))
+	}
+	if e.status == "enrolled" {
+		approvedAt := e.updatedAt
+		e.approvedAt = &approvedAt
+		if p.status == "retired" {
+			completedAt := p.end
+			e.status, e.completedAt, e.updatedAt = "completed", &completedAt, completedAt
+		}
+		g.logHours(e, minTime(p.end, g.now))
+	}
+	g.enrollments = append(g.enrollments, e)
+}
+
+// logHours logs up to eight shifts of one to eight hours between approval
+// and until
+func (g *generator) logHours(e enrollment, until time.Time) {
+	from := e.approvedAt.Truncate(day)
+	if until.Sub(from) < day {
+		return
+	}
+	for n := g.r.Intn(9); n > 0; n-- {
+		g.hours = append(g.hours, hoursEntry{
+			enrollmentID: e.id,
+			volunteerID:  e.volunteerID,
+			projectID:    e.projectID,
+			hours:        float64(2+g.r.Intn(15)) / 2,
+			workedOn:     g.between(from, until).Truncate(day),
+		})
+	}
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func round(x float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(x*scale) / scale
+}
+
+// insert writes every generated row, parents first, through unnest so
+// each batch is one statement
+func (g *generator) insert(ctx context.Context, tx *sql.Tx) error {
+	err := insertRows(ctx, tx, len(g.skills), `
+		INSERT INTO skills (id, name, description, category, sandbox)
+		SELECT id, name, description, category, TRUE
+		FROM unnest($1::uuid[], $2::text[], $3::text[], $4::text[]) AS s(id, name, description, category)
+	`, func(from, to int) []interface{} {
+		var ids, names, descriptions, categories []string
+		for _, s := range g.skills[from:to] {
+			ids = append(ids, s.id)
+			names = append(names, s.name)
+			descriptions = append(descriptions, s.description)
+			categories = append(categories, s.category)
+		}
+		return []interface{}{pq.Array(ids), pq.Array(names), pq.Array(descriptions), pq.Array(categories)}
+	})
+	if err != nil {
+		return err
+	}
+
+	users := append(append([]user{}, g.coordinators...), g.volunteers...)
+	err = insertRows(ctx, tx, len(users), `
+		INSERT INTO users (id, email, name, role, profile_complete, timezone, created_at, updated_at, sandbox)
+		SELECT id, email, name, role, profile_complete, timezone, created_at, created_at, TRUE
+		FROM unnest($1::uuid[], $2::text[], $3::text[], $4::text[], $5::boolean[], $6::text[], $7::timestamp[])
+			AS u(id, email, name, role, profile_complete, timezone, created_at)
+	`, func(from, to int) []interface{} {
+		var ids, emails, names, roles, timezones, createdAt []string
+		var profileComplete []bool
+		for _, u := range users[from:to] {
+			ids = append(ids, u.id)
+			emails = append(emails, u.email)
+			names = append(names, u.name)
+			roles = append(roles, u.role)
+			profileComplete = append(profileComplete, u.profileComplete)
+			timezones = append(timezones, u.timezone)
+			createdAt = append(createdAt, timestamp(u.createdAt))
+		}
+		return []interface{}{pq.Array(ids), pq.Array(emails), pq.Array(names), pq.Array(roles), pq.Array(profileComplete), pq.Array(timezones), pq.Array(createdAt)}
+	})
+	if err != nil {
+		return err
+	}
+
+	// The primary location is mirrored onto users by a trigger
+	err = insertRows(ctx, tx, len(g.locations), `
+		INSERT INTO volunteer_locations (volunteer_id, label, latitude, longitude, location_name, is_primary)
+		SELECT volunteer_id, 'Home', latitude, longitude, location_name, TRUE
+		FROM unnest($1::uuid[], $2::numeric[], $3::numeric[], $4::text[]) AS l(volunteer_id, latitude, longitude, location_name)
+	`, func(from, to int) []interface{} {
+		var ids, names []string
+		var lats, lons []float64
+		for _, l := range g.locations[from:to] {
+			ids = append(ids, l.volunteerID)
+			lats = append(lats, l.lat)
+			lons = append(lons, l.lon)
+			names = append(names, l.name)
+		}
+		return []interface{}{pq.Array(ids), pq.Array(lats), pq.Array(lons), pq.Array(names)}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = insertRows(ctx, tx, len(g.volunteerSkills), `
+		INSERT INTO volunteer_skills (volunteer_id, skill_id, claimed, score, verified_by, verified_at)
+		SELECT volunteer_id, skill_id, TRUE, score, verified_by, verified_at
+		FROM unnest($1::uuid[], $2::uuid[], $3::numeric[], $4::uuid[], $5::timestamp[])
+			AS vs(volunteer_id, skill_id, score, verified_by, verified_at)
+	`, func(from, to int) []interface{} {
+		var volunteerIDs, skillIDs []string
+		var scores []float64
+		var verifiedBy, verifiedAt []*string
+		for _, vs := range g.volunteerSkills[from:to] {
+			volunteerIDs = append(volunteerIDs, vs.volunteerID)
+			skillIDs = append(skillIDs, vs.skillID)
+			scores = append(scores, vs.score)
+			verifiedBy = append(verifiedBy, vs.verifiedBy)
+			verifiedAt = append(verifiedAt, optionalTimestamp(vs.verifiedAt))
+		}
+		return []interface{}{pq.Array(volunteerIDs), pq.Array(skillIDs), pq.Array(scores), pq.Array(verifiedBy), pq.Array(verifiedAt)}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = insertRows(ctx, tx, len(g.projects), `
+		INSERT INTO projects (id, name, description, coordinator_id, latitude, longitude, location_name, is_remote, timezone,
+		                      start_date, end_date, status, max_volunteers, created_at, updated_at, sandbox)
+		SELECT id, name, description, coordinator_id, latitude, longitude, location_name, is_remote, timezone,
+		       start_date, end_date, status, max_volunteers, created_at, created_at, TRUE
+		FROM unnest($1::uuid[], $2::text[], $3::text[], $4::uuid[], $5::numeric[], $6::numeric[], $7::text[], $8::boolean[],
+		            $9::text[], $10::timestamp[], $11::timestamp[], $12::text[], $13::int[], $14::timestamp[])
+			AS p(id, name, description, coordinator_id, latitude, longitude, location_name, is_remote, timezone,
+			     start_date, end_date, status, max_volunteers, created_at)
+	`, func(from, to int) []interface{} {
+		var ids, names, descriptions, coordinatorIDs, timezones, starts, ends, statuses, createdAt []string
+		var lats, lons []*float64
+		var locationNames []*string
+		var remote []bool
+		var maxVolunteers []int64
+		for _, p := range g.projects[from:to] {
+			ids = append(ids, p.id)
+			names = append(names, p.name)
+			descriptions = append(descriptions, p.description)
+			coordinatorIDs = append(coordinatorIDs, p.coordinatorID)
+			lats = append(lats, p.lat)
+			lons = append(lons, p.lon)
+			locationNames = append(locationNames, p.locationName)
+			remote = append(remote, p.remote)
+			timezones = append(timezones, p.timezone)
+			starts = append(starts, timestamp(p.start))
+			ends = append(ends, timestamp(p.end))
+			statuses = append(statuses, p.status)
+			maxVolunteers = append(maxVolunteers, p.maxVolunteers)
+			createdAt = append(createdAt, timestamp(p.createdAt))
+		}
+		return []interface{}{pq.Array(ids), pq.Array(names), pq.Array(descriptions), pq.Array(coordinatorIDs),
+			pq.Array(lats), pq.Array(lons), pq.Array(locationNames), pq.Array(remote), pq.Array(timezones),
+			pq.Array(starts), pq.Array(ends), pq.Array(statuses), pq.Array(maxVolunteers), pq.Array(createdAt)}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = insertRows(ctx, tx, len(g.projectSkills), `
+		INSERT INTO project_skills (project_id, skill_id, required, weight)
+		SELECT project_id, skill_id, required, weight
+		FROM unnest($1::uuid[], $2::uuid[], $3::boolean[], $4::numeric[]) AS ps(project_id, skill_id, required, weight)
+	`, func(from, to int) []interface{} {
+		var projectIDs, skillIDs []string
+		var required []bool
+		var weights []float64
+		for _, ps := range g.projectSkills[from:to] {
+			projectIDs = append(projectIDs, ps.projectID)
+			skillIDs = append(skillIDs, ps.skillID)
+			required = append(required, ps.required)
+			weights = append(weights, ps.weight)
+		}
+		return []interface{}{pq.Array(projectIDs), pq.Array(skillIDs), pq.Array(required), pq.Array(weights)}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = insertRows(ctx, tx, len(g.enrollments), `
+		INSERT INTO volunteer_enrollments (id, volunteer_id, project_id, enrollment_type, status, initiated_by,
+		                                   created_at, updated_at, approved_at, completed_at)
+		SELECT id, volunteer_id, project_id, enrollment_type, status, initiated_by,
+		       created_at, updated_at, approved_at, completed_at
+		FROM unnest($1::uuid[], $2::uuid[], $3::uuid[], $4::text[], $5::text[], $6::uuid[],
+		            $7::timestamptz[], $8::timestamptz[], $9::timestamptz[], $10::timestamptz[])
+			AS e(id, volunteer_id, project_id, enrollment_type, status, initiated_by,
+			     created_at, updated_at, approved_at, completed_at)
+	`, func(from, to int) []interface{} {
+		var ids, volunteerIDs, projectIDs, kinds, statuses, initiatedBy, createdAt, updatedAt []string
+		var approvedAt, completedAt []*string
+		for _, e := range g.enrollments[from:to] {
+			ids = append(ids, e.id)
+			volunteerIDs = append(volunteerIDs, e.volunteerID)
+			projectIDs = append(projectIDs, e.projectID)
+			kinds = append(kinds, e.kind)
+			statuses = append(statuses, e.status)
+			initiatedBy = append(initiatedBy, e.initiatedBy)
+			createdAt = append(createdAt, timestamp(e.createdAt))
+			updatedAt = append(updatedAt, timestamp(e.updatedAt))
+			approvedAt = append(approvedAt, optionalTimestamp(e.approvedAt))
+			completedAt = append(completedAt, optionalTimestamp(e.completedAt))
+		}
+		return []interface{}{pq.Array(ids), pq.Array(volunteerIDs), pq.Array(projectIDs), pq.Array(kinds), pq.Array(statuses),
+			pq.Array(initiatedBy), pq.Array(createdAt), pq.Array(updatedAt), pq.Array(approvedAt), pq.Array(completedAt)}
+	})
+	if err != nil {
+		return err
+	}
+
+	return insertRows(ctx, tx, len(g.hours), `
+		INSERT INTO volunteer_hours (enrollment_id, volunteer_id, project_id, hours, worked_on, logged_by, created_at)
+		SELECT enrollment_id, volunteer_id, project_id, hours, worked_on, volunteer_id, worked_on + INTERVAL '20 hours'
+		FROM unnest($1::uuid[], $2::uuid[], $3::uuid[], $4::numeric[], $5::date[])
+			AS h(enrollment_id, volunteer_id, project_id, hours, worked_on)
+	`, func(from, to int) []interface{} {
+		var enrollmentIDs, volunteerIDs, projectIDs, workedOn []string
+		var hours []float64
+		for _, h := range g.hours[from:to] {
+			enrollmentIDs = append(enrollmentIDs, h.enrollmentID)
+			volunteerIDs = append(volunteerIDs, h.volunteerID)
+			projectIDs = append(projectIDs, h.projectID)
+			hours = append(hours, h.hours)
+			workedOn = append(workedOn, h.workedOn.Format("2006-01-02"))
+		}
+		return []interface{}{pq.Array(enrollmentIDs), pq.Array(volunteerIDs), pq.Array(projectIDs), pq.Array(hours), pq.Array(workedOn)}
+	})
+}
+
+// insertRows runs query once per batch of n rows, with the arguments args
+// builds for rows [from, to)
+func insertRows(ctx context.Context, tx *sql.Tx, n int, query string, args func(from, to int) []interface{}) error {
+	for from := 0; from < n; from += insertBatchSize {
+		to := from + insertBatchSize
+		if to > n {
+			to = n
+		}
+		if _, err := tx.ExecContext(ctx, query, args(from, to)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func timestamp(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+func optionalTimestamp(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := timestamp(*t)
+	return &s
+}
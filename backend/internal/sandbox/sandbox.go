@@ -0,0 +1,177 @@
+package sandbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/jobs"
+	"github.com/civic-weave/backend/internal/matching"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/notifications"
+)
+
+// GenerateJob is the kind of background job that runs Generate
+const GenerateJob = "sandbox.generate"
+
+// EmailDomain is the domain of every sandbox user's email address. It is
+// reserved by RFC 2606, so the addresses can never reach anyone.
+const EmailDomain = "sandbox.civicweave.invalid"
+
+// ErrAlreadyGenerated is returned by Generate while sandbox data from an
+// earlier run is still present; it must be purged first
+var ErrAlreadyGenerated = errors.New("sandbox data already exists; purge it before generating again")
+
+// Params sets how much synthetic data Generate creates. The same params
+// always give the same people, projects and enrollments, with dates
+// relative to the day they are generated.
+type Params struct {
+	Seed         int64 `json:"seed"`
+	Volunteers   int   `json:"volunteers"`
+	Coordinators int   `json:"coordinators"`
+	Projects     int   `json:"projects"`
+	// EnrollmentsPerVolunteer is the average; each volunteer gets between
+	// none and twice as many
+	EnrollmentsPerVolunteer int `json:"enrollmentsPerVolunteer"`
+}
+
+// Service generates synthetic volunteers, projects and enrollment histories
+// for load testing and demos, and purges them again. Everything generated
+// is flagged with a sandbox column or belongs to a flagged user or project.
+type Service struct {
+	db          *sql.DB
+	jobsService *jobs.Service
+}
+
+// NewService registers the generation job with jobsService, which must not
+// be running yet
+func NewService(db *sql.DB, jobsService *jobs.Service) *Service {
+	s := &Service{db: db, jobsService: jobsService}
+	jobsService.Register(GenerateJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			var p Params
+			if err := json.Unmarshal(payload, &p); err != nil {
+				return fmt.Errorf("invalid payload %s", payload)
+			}
+			summary, err := s.Generate(ctx, p)
+			if err == ErrAlreadyGenerated {
+				slog.Info("Sandbox data already present; skipping generation")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			slog.Info("Generated sandbox data", "seed", p.Seed, "volunteers", summary.Volunteers, "coordinators", summary.Coordinators,
+				"projects", summary.Projects, "enrollments", summary.Enrollments, "hours", summary.Hours)
+			return nil
+		},
+		// Generation is all or nothing, so a retry starts from scratch
+		Retry:   jobs.RetryPolicy{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: 10 * time.Minute},
+		Timeout: 30 * time.Minute,
+	})
+	return s
+}
+
+// QueueGenerate queues generation with p, unless a generation is already
+// waiting. Every instance starting in sandbox mode queues it, but only the
+// first run generates anything.
+func (s *Service) QueueGenerate(p Params) (*models.Job, error) {
+	return s.jobsService.EnqueueUnique(GenerateJob, "sandbox", p)
+}
+
+// Status counts the sandbox data currently in the database
+func (s *Service) Status() (*models.SandboxSummary, error) {
+	var summary *models.SandboxSummary
+	err := database.WithReadRetry(func() error {
+		var err error
+		summary, err = count(s.db)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func count(q queryer) (*models.SandboxSummary, error) {
+	var summary models.SandboxSummary
+	err := q.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE sandbox AND role = 'coordinator'),
+			(SELECT COUNT(*) FROM users WHERE sandbox AND role = 'volunteer'),
+			(SELECT COUNT(*) FROM projects WHERE sandbox),
+			(SELECT COUNT(*) FROM skills WHERE sandbox),
+			(SELECT COUNT(*) FROM volunteer_enrollments e JOIN projects p ON p.id = e.project_id WHERE p.sandbox),
+			(SELECT COUNT(*) FROM volunteer_hours h JOIN projects p ON p.id = h.project_id WHERE p.sandbox)
+	`).Scan(&summary.Coordinators, &summary.Volunteers, &summary.Projects, &summary.Skills, &summary.Enrollments, &summary.Hours)
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// Purge deletes every sandbox user, project and skill, along with their
+// enrollments, hours, skills and locations, and returns what was deleted.
+// Real records are never touched: it fails instead if one refers to
+// sandbox data in a way that would have to be deleted or changed.
+func (s *Service) Purge() (*models.SandboxSummary, error) {
+	var summary *models.SandboxSummary
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		summary, err = count(tx)
+		if err != nil {
+			return err
+		}
+
+		// Deleting projects and users cascades to their enrollments, hours,
+		// skills and locations
+		statements := []string{
+			`DELETE FROM projects WHERE sandbox`,
+			`DELETE FROM users WHERE sandbox`,
+			`DELETE FROM skills WHERE sandbox`,
+		}
+		for _, statement := range statements {
+			if _, err := tx.Exec(statement); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.jobsService.EnqueueUnique(matching.RefreshSkillVectorsJob, "all", nil); err != nil {
+		slog.Error("Queue skill vector refresh error", "error", err)
+	}
+	return summary, nil
+}
+
+// Mailer drops messages to sandbox users, which would otherwise pile up
+// behind milestone emails and digests for thousands of made-up people, and
+// passes the rest to Next
+type Mailer struct {
+	Next notifications.Mailer
+}
+
+func (m Mailer) Send(msg notifications.Message) error {
+	if strings.HasSuffix(strings.ToLower(msg.To), "@"+EmailDomain) {
+		return nil
+	}
+	return m.Next.Send(msg)
+}
@@ -0,0 +1,269 @@
+package sanitize
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// allowedTags is the formatting markup kept in rich text. Only links keep
+// attributes; everything else is written back bare.
+var allowedTags = map[string]bool{
+	"p": true, "br": true, "hr": true,
+	"strong": true, "em": true, "b": true, "i": true, "u": true, "s": true,
+	"ul": true, "ol": true, "li": true,
+	"a": true, "blockquote": true, "code": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+var voidTags = map[string]bool{"br": true, "hr": true}
+
+// droppedElements lose their content as well as their tags, since their
+// content is code or markup rather than text
+var droppedElements = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"template": true, "noscript": true, "textarea": true, "title": true,
+	"xmp": true, "svg": true, "math": true,
+}
+
+var allowedSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+const linkRel = "nofollow noopener noreferrer"
+
+var (
+	inlineLink    = regexp.MustCompile(`\]\(\s*<?([^\s)>]+)`)
+	referenceLink = regexp.MustCompile(`(?m)^( {0,3}\[[^\]]+\]:\s*<?)(\S+?)(>?(?:\s|$))`)
+)
+
+// RichText makes user-supplied markdown or HTML safe to store and render.
+// Allowlisted formatting tags are kept, other tags are stripped leaving their
+// text, and script-like elements, comments, event handlers and links to
+// anything but http, https, mailto or relative URLs are removed. Markdown
+// syntax passes through untouched apart from unsafe link destinations.
+func RichText(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); {
+		lt := strings.IndexByte(s[i:], '<')
+		if lt < 0 {
+			b.WriteString(markdownLinks(s[i:]))
+			break
+		}
+		b.WriteString(markdownLinks(s[i : i+lt]))
+		i += lt
+
+		next, ok := tag(&b, s, i)
+		if !ok {
+			b.WriteString("&lt;")
+			i++
+			continue
+		}
+		i = next
+	}
+	return b.String()
+}
+
+// tag handles the markup starting at s[i] == '<', writing whatever survives
+// and returning where the text resumes. It reports false when the '<' does
+// not start markup and should be escaped as text.
+func tag(b *strings.Builder, s string, i int) (int, bool) {
+	rest := s[i:]
+	switch {
+	case strings.HasPrefix(rest, "<!--"):
+		end := strings.Index(rest[4:], "-->")
+		if end < 0 {
+			return len(s), true
+		}
+		return i + 4 + end + 3, true
+	case strings.HasPrefix(rest, "<!"), strings.HasPrefix(rest, "<?"):
+		end := strings.IndexByte(rest, '>')
+		if end < 0 {
+			return len(s), true
+		}
+		return i + end + 1, true
+	}
+
+	j := 1
+	closing := false
+	if j < len(rest) && rest[j] == '/' {
+		closing = true
+		j++
+	}
+	start := j
+	for j < len(rest) && isNameChar(rest[j], j == start) {
+		j++
+	}
+	if j == start {
+		return 0, false
+	}
+	name := strings.ToLower(rest[start:j])
+
+	attrs, end, ok := parseAttrs(rest, j)
+	if !ok {
+		return 0, false
+	}
+	next := i + end
+
+	if droppedElements[name] {
+		if closing {
+			return next, true
+		}
+		return skipElement(s, next, name), true
+	}
+	if !allowedTags[name] {
+		return next, true
+	}
+
+	switch {
+	case closing:
+		if !voidTags[name] {
+			b.WriteString("</" + name + ">")
+		}
+	case name == "a":
+		writeLink(b, attrs)
+	default:
+		b.WriteString("<" + name + ">")
+	}
+	return next, true
+}
+
+// parseAttrs reads attributes from rest[j:] up to the closing '>', returning
+// them lower-cased and unescaped along with the offset just past the '>'
+func parseAttrs(rest string, j int) (map[string]string, int, bool) {
+	attrs := map[string]string{}
+	for {
+		for j < len(rest) && (isSpace(rest[j]) || rest[j] == '/') {
+			j++
+		}
+		if j >= len(rest) {
+			return nil, 0, false
+		}
+		if rest[j] == '>' {
+			return attrs, j + 1, true
+		}
+
+		start := j
+		for j < len(rest) && !isSpace(rest[j]) && rest[j] != '=' && rest[j] != '>' && rest[j] != '/' {
+			j++
+		}
+		key := strings.ToLower(rest[start:j])
+
+		for j < len(rest) && isSpace(rest[j]) {
+			j++
+		}
+		if j >= len(rest) || rest[j] != '=' {
+			attrs[key] = ""
+			continue
+		}
+		j++
+		for j < len(rest) && isSpace(rest[j]) {
+			j++
+		}
+		if j >= len(rest) {
+			return nil, 0, false
+		}
+
+		var value string
+		if q := rest[j]; q == '"' || q == '\'' {
+			end := strings.IndexByte(rest[j+1:], q)
+			if end < 0 {
+				return nil, 0, false
+			}
+			value = rest[j+1 : j+1+end]
+			j += end + 2
+		} else {
+			start := j
+			for j < len(rest) && !isSpace(rest[j]) && rest[j] != '>' {
+				j++
+			}
+			value = rest[start:j]
+		}
+		attrs[key] = html.UnescapeString(value)
+	}
+}
+
+// skipElement returns the offset just past the closing tag of the named
+// element, or the end of s when it is never closed
+func skipElement(s string, i int, name string) int {
+	lower := strings.ToLower(s)
+	for {
+		k := strings.Index(lower[i:], "</"+name)
+		if k < 0 {
+			return len(s)
+		}
+		i += k + 2 + len(name)
+		if i < len(s) && isNameChar(s[i], false) {
+			continue
+		}
+		end := strings.IndexByte(s[i:], '>')
+		if end < 0 {
+			return len(s)
+		}
+		return i + end + 1
+	}
+}
+
+func writeLink(b *strings.Builder, attrs map[string]string) {
+	b.WriteString("<a")
+	if href, ok := attrs["href"]; ok && safeURL(href) {
+		b.WriteString(` href="` + html.EscapeString(strings.TrimSpace(href)) + `"`)
+	}
+	if title, ok := attrs["title"]; ok {
+		b.WriteString(` title="` + html.EscapeString(title) + `"`)
+	}
+	b.WriteString(` rel="` + linkRel + `">`)
+}
+
+// markdownLinks replaces unsafe markdown link destinations with "#"
+func markdownLinks(text string) string {
+	if !strings.Contains(text, "](") && !strings.Contains(text, "]:") {
+		return text
+	}
+	text = inlineLink.ReplaceAllStringFunc(text, func(m string) string {
+		dest := inlineLink.FindStringSubmatch(m)[1]
+		if safeURL(dest) {
+			return m
+		}
+		return strings.TrimSuffix(m, dest) + "#"
+	})
+	return referenceLink.ReplaceAllStringFunc(text, func(m string) string {
+		parts := referenceLink.FindStringSubmatch(m)
+		if safeURL(parts[2]) {
+			return m
+		}
+		return parts[1] + "#" + parts[3]
+	})
+}
+
+// safeURL reports whether a link target is relative or uses an allowed
+// scheme. Browsers ignore whitespace and control characters inside schemes,
+// so those are removed before checking.
+func safeURL(raw string) bool {
+	u := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, html.UnescapeString(raw))
+
+	colon := strings.IndexByte(u, ':')
+	if colon < 0 {
+		return true
+	}
+	if strings.ContainsAny(u[:colon], "/?#") {
+		return true
+	}
+	return allowedSchemes[strings.ToLower(u[:colon])]
+}
+
+func isNameChar(c byte, first bool) bool {
+	if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' {
+		return true
+	}
+	return !first && '0' <= c && c <= '9'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,93 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRichText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"keeps formatting", "<p>Hi <strong>there</strong></p>", "<p>Hi <strong>there</strong></p>"},
+		{"keeps markdown", "**Bold** and [site](https://example.org)", "**Bold** and [site](https://example.org)"},
+		{"strips unknown tags keeping text", "<div><span>text</span></div>", "text"},
+
+		{"drops script", "a<script>alert(1)</script>b", "ab"},
+		{"drops script case-insensitively", "a<ScRiPt>alert(1)</sCrIpT>b", "ab"},
+		{"drops unclosed script", "a<script>alert(1)", "a"},
+		{"drops script with attributes", `a<script src="https://evil.example/x.js"></script>b`, "ab"},
+		{"drops style", "a<style>body{background:url(javascript:alert(1))}</style>b", "ab"},
+		{"drops svg", `<svg onload="alert(1)"><circle/></svg>ok`, "ok"},
+		{"drops iframe", `<iframe src="javascript:alert(1)"></iframe>ok`, "ok"},
+		{"drops comments", "a<!-- <script>alert(1)</script> -->b", "ab"},
+
+		{"strips event handlers", `<p onclick="alert(1)">x</p>`, "<p>x</p>"},
+		{"strips event handlers from links", `<a href="https://example.org" onmouseover="alert(1)">x</a>`, `<a href="https://example.org" rel="nofollow noopener noreferrer">x</a>`},
+		{"strips unquoted event handlers", `<b onmouseover=alert(1)>x</b>`, "<b>x</b>"},
+		{"strips event handlers on img", `<img src=x onerror="alert(1)">`, ""},
+
+		{"drops javascript href", `<a href="javascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"drops mixed-case javascript href", `<a href="JaVaScRiPt:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"drops javascript href with whitespace", "<a href=\" java\tscript:alert(1)\">x</a>", `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"drops data href", `<a href="data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"drops vbscript href", `<a href="vbscript:msgbox(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"keeps relative href", `<a href="/projects/1">x</a>`, `<a href="/projects/1" rel="nofollow noopener noreferrer">x</a>`},
+		{"keeps mailto href", `<a href="mailto:team@example.org">x</a>`, `<a href="mailto:team@example.org" rel="nofollow noopener noreferrer">x</a>`},
+
+		{"drops entity-encoded javascript href", `<a href="&#106;avascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"drops hex-entity-encoded javascript href", `<a href="&#x6A;&#x61;vascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"drops entity-encoded colon", `<a href="javascript&colon;alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"drops entity-encoded tab in scheme", `<a href="java&#9;script:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+
+		{"neutralizes markdown javascript link", "[x](javascript:alert`1`)", "[x](#)"},
+		{"neutralizes markdown data link", "[x](data:text/html,hi)", "[x](#)"},
+		{"neutralizes markdown reference link", "[x]: javascript:alert(1)", "[x]: #"},
+		{"neutralizes entity-encoded markdown link", "[x](&#106;avascript:alert`1`)", "[x](#)"},
+
+		{"escapes stray angle bracket", "1 < 2", "1 &lt; 2"},
+		{"escapes unterminated tag", `<a href="x`, `&lt;a href="x`},
+		{"escapes unterminated attribute quote", `<img src="x onerror=alert(1)>`, `&lt;img src="x onerror=alert(1)>`},
+		{"strips nested tag trick", "<scr<script>ipt>alert(1)</script>", "ipt>alert(1)"},
+		{"drops closing tag with junk", "<b>x</b foo>", "<b>x</b>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RichText(tt.in); got != tt.want {
+				t.Errorf("RichText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRichTextNoActiveContent checks nothing script-like survives, whatever
+// shape the rest of the output takes
+func TestRichTextNoActiveContent(t *testing.T) {
+	inputs := []string{
+		`<SCRIPT SRC=https://evil.example/xss.js></SCRIPT>`,
+		`<IMG SRC="javascript:alert('XSS');">`,
+		`<a href="&#0000106&#0000097&#0000118&#0000097&#0000115&#0000099&#0000114&#0000105&#0000112&#0000116&#0000058alert(1)">x</a>`,
+		`<a href="jav&#x0A;ascript:alert(1)">x</a>`,
+		`<a href=" &#14;  javascript:alert(1)">x</a>`,
+		`<body onload=alert(1)>`,
+		`<div style="background:url(javascript:alert(1))">x</div>`,
+		`<<script>script>alert(1)<</script>/script>`,
+		`<math><mtext><table><mglyph><style><img src=x onerror=alert(1)>`,
+		`<a href="data:text/html,<script>alert(1)</script>">x</a>`,
+		`<p/onclick=alert(1)>x</p>`,
+		"<a\nhref=\"javascript:alert(1)\"\nonclick=alert(1)>x</a>",
+		"[x](javascript:alert(1))",
+	}
+
+	for _, in := range inputs {
+		out := strings.ToLower(RichText(in))
+		for _, bad := range []string{"<script", "<style", "<img", "<svg", "<body", "<div", "javascript:", "data:", "onerror", "onload", "onclick"} {
+			if strings.Contains(out, bad) {
+				t.Errorf("RichText(%q) = %q, contains %q", in, out, bad)
+			}
+		}
+	}
+}
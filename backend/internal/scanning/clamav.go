@@ -0,0 +1,61 @@
+package scanning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ClamAVScanner scans files with a ClamAV REST service such as clamav-rest.
+// It posts the file as the multipart "file" field and reads a reply of
+// {"Status": "OK"} for clean files or {"Status": "FOUND", "Description":
+// "<signature>"} for infected ones.
+type ClamAVScanner struct {
+	URL    string
+	Client *http.Client
+}
+
+func (c *ClamAVScanner) Scan(ctx context.Context, name string, data []byte) (Verdict, error) {
+	body, contentType, err := fileForm(name, data)
+	if err != nil {
+		return Verdict{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, body)
+	if err != nil {
+		return Verdict{}, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("clamav scan: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// clamav-rest answers 406 Not Acceptable for infected files
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotAcceptable {
+		return Verdict{}, responseError("clamav scan", resp)
+	}
+
+	var result struct {
+		Status      string
+		Description string
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Verdict{}, fmt.Errorf("decode clamav result: %w", err)
+	}
+
+	switch strings.ToUpper(result.Status) {
+	case "OK":
+		return Verdict{}, nil
+	case "FOUND":
+		threat := result.Description
+		if threat == "" {
+			threat = "ClamAV signature match"
+		}
+		return Verdict{Infected: true, Threat: threat}, nil
+	}
+	return Verdict{}, fmt.Errorf("clamav scan: unexpected status %q", result.Status)
+}
@@ -0,0 +1,69 @@
+package scanning
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+var ErrInvalidScanner = errors.New("scanner must be clamav+http://host/path, clamav+https://host/path or virustotal")
+
+// Verdict is a scanner's finding about one file
+type Verdict struct {
+	Infected bool
+	// Threat names what was found in an infected file
+	Threat string
+}
+
+// Scanner checks uploaded files for malware. An error means the file could
+// not be scanned and should be tried again later.
+type Scanner interface {
+	Scan(ctx context.Context, name string, data []byte) (Verdict, error)
+}
+
+// ParseScanner picks a scanner from UPLOAD_SCANNER: clamav+http:// or
+// clamav+https:// followed by the scan endpoint of a ClamAV REST service, or
+// virustotal, which uses virusTotalAPIKey and uploads unknown files when
+// virusTotalUpload is set. An empty spec returns nil, meaning uploads are
+// not scanned.
+func ParseScanner(spec, virusTotalAPIKey string, virusTotalUpload bool) (Scanner, error) {
+	switch {
+	case spec == "":
+		return nil, nil
+	case strings.HasPrefix(spec, "clamav+http://"), strings.HasPrefix(spec, "clamav+https://"):
+		return &ClamAVScanner{URL: strings.TrimPrefix(spec, "clamav+"), Client: http.DefaultClient}, nil
+	case spec == "virustotal":
+		if virusTotalAPIKey == "" {
+			return nil, errors.New("VIRUSTOTAL_API_KEY is required to scan with VirusTotal")
+		}
+		return NewVirusTotalScanner(virusTotalAPIKey, virusTotalUpload), nil
+	}
+	return nil, ErrInvalidScanner
+}
+
+// fileForm encodes data as the "file" field of a multipart form
+func fileForm(name string, data []byte) (*bytes.Buffer, string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", name)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, "", err
+	}
+	if err := form.Close(); err != nil {
+		return nil, "", err
+	}
+	return &body, form.FormDataContentType(), nil
+}
+
+func responseError(op string, resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("%s: %s: %s", op, resp.Status, strings.TrimSpace(string(msg)))
+}
@@ -0,0 +1,339 @@
+package scanning
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"path"
+	"time"
+
+	"github.com/civic-weave/backend/internal/audit"
+	"github.com/civic-weave/backend/internal/blobstore"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/images"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/notifications"
+	"github.com/lib/pq"
+)
+
+const batchSize = 20
+
+// source is a kind of upload that is scanned. Each query takes the row ID
+// and the file's key first so verdicts about a file that has since been
+// replaced don't touch its replacement.
+type source struct {
+	kind  string
+	store blobstore.Store
+	// pending selects the ID, key, uploader and derived file keys of
+	// pending uploads with IDs after $2, taking the batch size
+	pending string
+	// clear marks the file clean
+	clear string
+	// quarantine takes the file out of use
+	quarantine string
+	// variants wakes the images job once the file is clear
+	variants bool
+}
+
+// Service scans uploads in the background. Uploads stay pending, and are
+// not shown to other users, until cleared; infected files are moved to the
+// quarantine store and platform admins alerted.
+type Service struct {
+	db            *sql.DB
+	scanner       Scanner
+	sources       []source
+	quarantine    blobstore.Store
+	imagesService *images.Service
+	mailer        notifications.Mailer
+	wake          chan struct{}
+}
+
+// NewService scans avatars and photos in blobStore and documents in
+// documentStore. A nil scanner clears every upload without scanning it.
+func NewService(db *sql.DB, scanner Scanner, blobStore, documentStore, quarantine blobstore.Store, imagesService *images.Service, mailer notifications.Mailer) *Service {
+	return &Service{
+		db:            db,
+		scanner:       scanner,
+		quarantine:    quarantine,
+		imagesService: imagesService,
+		mailer:        mailer,
+		wake:          make(chan struct{}, 1),
+		sources: []source{
+			{
+				kind:  "avatar",
+				store: blobStore,
+				pending: `
+					SELECT id::text, avatar_key, id::text, ARRAY[]::text[] FROM users
+					WHERE avatar_key IS NOT NULL AND avatar_scan_status = 'pending' AND id::text > $2
+					ORDER BY id::text
+					LIMIT $1
+				`,
+				clear: `UPDATE users SET avatar_scan_status = 'clean' WHERE id::text = $1 AND avatar_key = $2`,
+				quarantine: `
+					UPDATE users
+					SET avatar_key = NULL, avatar_url = NULL, avatar_variants = NULL, avatar_scan_status = 'infected'
+					WHERE id::text = $1 AND avatar_key = $2
+				`,
+				variants: true,
+			},
+			{
+				kind:  "photo",
+				store: blobStore,
+				pending: `
+					SELECT id::text, photo_key, COALESCE(uploaded_by::text, ''), ARRAY[thumbnail_key] FROM project_photos
+					WHERE scan_status = 'pending' AND id::text > $2
+					ORDER BY id::text
+					LIMIT $1
+				`,
+				clear:      `UPDATE project_photos SET scan_status = 'clean' WHERE id::text = $1 AND photo_key = $2`,
+				quarantine: `DELETE FROM project_photos WHERE id::text = $1 AND photo_key = $2`,
+				variants:   true,
+			},
+			{
+				kind:  "document",
+				store: documentStore,
+				pending: `
+					SELECT id::text, blob_key, COALESCE(uploaded_by::text, ''), ARRAY[]::text[] FROM documents
+					WHERE scan_status = 'pending' AND id::text > $2
+					ORDER BY id::text
+					LIMIT $1
+				`,
+				clear:      `UPDATE documents SET scan_status = 'clean' WHERE id::text = $1 AND blob_key = $2`,
+				quarantine: `UPDATE documents SET scan_status = 'infected' WHERE id::text = $1 AND blob_key = $2`,
+			},
+		},
+	}
+}
+
+// Wake asks Run to look for new uploads now rather than at the next interval
+func (s *Service) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run scans pending uploads now, then every interval or when woken, until
+// ctx is cancelled
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.ScanPending(ctx); err != nil {
+			logging.FromContext(ctx).Error("Upload scan error", "error", err)
+		} else if n > 0 {
+			logging.FromContext(ctx).Info("Upload scan checked files", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+type upload struct {
+	id, key, uploadedBy string
+	derived             []string
+}
+
+// ScanPending scans every pending upload once and returns how many were
+// cleared or quarantined. Files that can't be scanned are logged and stay
+// pending for the next run.
+func (s *Service) ScanPending(ctx context.Context) (int, error) {
+	done := 0
+	for _, src := range s.sources {
+		cleared := false
+		after := ""
+		for ctx.Err() == nil {
+			pending, err := s.pending(src, after)
+			if err != nil {
+				return done, err
+			}
+
+			for _, u := range pending {
+				if ctx.Err() != nil {
+					break
+				}
+				ok, err := s.scan(ctx, src, u)
+				if err != nil {
+					return done, err
+				}
+				if ok {
+					done++
+					cleared = cleared || src.variants
+				}
+				after = u.id
+			}
+
+			if len(pending) < batchSize {
+				break
+			}
+		}
+		if cleared {
+			s.imagesService.Wake()
+		}
+	}
+	return done, ctx.Err()
+}
+
+func (s *Service) pending(src source, after string) ([]upload, error) {
+	var pending []upload
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(src.pending, batchSize, after)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		pending = nil
+		for rows.Next() {
+			var u upload
+			if err := rows.Scan(&u.id, &u.key, &u.uploadedBy, pq.Array(&u.derived)); err != nil {
+				return err
+			}
+			pending = append(pending, u)
+		}
+		return rows.Err()
+	})
+	return pending, err
+}
+
+// scan checks one upload and clears or quarantines it. Failures to read or
+// scan the file are logged and leave it pending; only database errors are
+// returned.
+func (s *Service) scan(ctx context.Context, src source, u upload) (bool, error) {
+	if s.scanner == nil {
+		return true, s.exec(src.clear, u.id, u.key)
+	}
+
+	data, err := s.read(ctx, src.store, u.key)
+	if err == nil {
+		var verdict Verdict
+		verdict, err = s.scanner.Scan(ctx, path.Base(u.key), data)
+		if err == nil && verdict.Infected {
+			// Keep a copy before the file is taken out of use
+			err = s.quarantine.Put(ctx, u.key, "application/octet-stream", data)
+			if err == nil {
+				return true, s.quarantineFile(ctx, src, u, verdict.Threat)
+			}
+		}
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		logging.FromContext(ctx).Error("Upload scan error", "kind", src.kind, "id", u.id, "key", u.key, "error", err)
+		return false, nil
+	}
+
+	return true, s.exec(src.clear, u.id, u.key)
+}
+
+func (s *Service) read(ctx context.Context, store blobstore.Store, key string) ([]byte, error) {
+	r, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// quarantineFile takes an infected upload already copied to the quarantine
+// store out of use, deletes it from the live store, and alerts platform
+// admins
+func (s *Service) quarantineFile(ctx context.Context, src source, u upload, threat string) error {
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.Exec(src.quarantine, u.id, u.key); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+			INSERT INTO quarantined_files (kind, subject_id, uploaded_by, blob_key, threat)
+			VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5)
+		`, src.kind, u.id, u.uploadedBy, u.key, threat)
+		if err != nil {
+			return err
+		}
+		err = audit.Record(tx, "", "upload.quarantine", src.kind, u.id, map[string]string{
+			"threat":     threat,
+			"key":        u.key,
+			"uploadedBy": u.uploadedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range append([]string{u.key}, u.derived...) {
+		if err := src.store.Delete(ctx, k); err != nil {
+			logging.FromContext(ctx).Error("Delete quarantined file error", "key", k, "error", err)
+		}
+	}
+	logging.FromContext(ctx).Warn("Quarantined upload", "kind", src.kind, "id", u.id, "key", u.key, "threat", threat)
+
+	s.alertAdmins(notifications.QuarantineAlert{
+		Kind:       src.kind,
+		SubjectID:  u.id,
+		UploadedBy: u.uploadedBy,
+		Threat:     threat,
+		Key:        u.key,
+	})
+	return nil
+}
+
+// alertAdmins emails every platform admin; failures are logged since the
+// file is already quarantined and recorded
+func (s *Service) alertAdmins(alert notifications.QuarantineAlert) {
+	type admin struct{ email, name, locale string }
+	var admins []admin
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(`SELECT email, name, locale FROM users WHERE role = 'admin'`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		admins = nil
+		for rows.Next() {
+			var a admin
+			if err := rows.Scan(&a.email, &a.name, &a.locale); err != nil {
+				return err
+			}
+			admins = append(admins, a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		slog.Error("Quarantine alert error", "error", err)
+		return
+	}
+
+	for _, a := range admins {
+		alert.To, alert.Name, alert.Locale = a.email, a.name, a.locale
+		if err := s.mailer.Send(notifications.RenderQuarantineAlert(alert)); err != nil {
+			slog.Error("Quarantine alert error", "to", a.email, "error", err)
+		}
+	}
+}
+
+func (s *Service) exec(query string, args ...interface{}) error {
+	return database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(query, args...)
+		return err
+	})
+}
@@ -0,0 +1,183 @@
+package scanning
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const virusTotalAPI = "https://www.virustotal.com/api/v3"
+
+// VirusTotalScanner checks files against VirusTotal. Files are looked up by
+// hash. Files VirusTotal hasn't seen are uploaded and their analysis polled
+// until it completes when Upload is set, and otherwise count as clean;
+// uploaded files are shared with VirusTotal's users, so private documents
+// may leak. A file is infected when any engine flags it as malicious.
+type VirusTotalScanner struct {
+	APIKey string
+	Upload bool
+	Client *http.Client
+	// PollInterval is how often a pending analysis is checked; the public
+	// API allows four requests a minute
+	PollInterval time.Duration
+	// MaxWait bounds how long an analysis is waited for before the scan
+	// is given up and retried on a later run
+	MaxWait time.Duration
+}
+
+func NewVirusTotalScanner(apiKey string, upload bool) *VirusTotalScanner {
+	return &VirusTotalScanner{
+		APIKey:       apiKey,
+		Upload:       upload,
+		Client:       http.DefaultClient,
+		PollInterval: 20 * time.Second,
+		MaxWait:      10 * time.Minute,
+	}
+}
+
+type analysisStats struct {
+	Malicious int `json:"malicious"`
+}
+
+func (v *VirusTotalScanner) Scan(ctx context.Context, name string, data []byte) (Verdict, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	var report struct {
+		Data struct {
+			Attributes struct {
+				LastAnalysisStats           analysisStats `json:"last_analysis_stats"`
+				PopularThreatClassification struct {
+					SuggestedThreatLabel string `json:"suggested_threat_label"`
+				} `json:"popular_threat_classification"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	found, err := v.get(ctx, "/files/"+hash, &report)
+	if err != nil {
+		return Verdict{}, err
+	}
+	if found {
+		attrs := report.Data.Attributes
+		return verdict(attrs.LastAnalysisStats, attrs.PopularThreatClassification.SuggestedThreatLabel), nil
+	}
+	if !v.Upload {
+		return Verdict{}, nil
+	}
+
+	analysisID, err := v.upload(ctx, name, data)
+	if err != nil {
+		return Verdict{}, err
+	}
+	return v.wait(ctx, analysisID)
+}
+
+// upload submits the file for analysis and returns the analysis ID
+func (v *VirusTotalScanner) upload(ctx context.Context, name string, data []byte) (string, error) {
+	body, contentType, err := fileForm(name, data)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, virusTotalAPI+"/files", body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-apikey", v.APIKey)
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("virustotal upload: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", responseError("virustotal upload", resp)
+	}
+
+	var result struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || result.Data.ID == "" {
+		return "", fmt.Errorf("decode virustotal upload: %v", err)
+	}
+	return result.Data.ID, nil
+}
+
+// wait polls an analysis until it completes or MaxWait passes
+func (v *VirusTotalScanner) wait(ctx context.Context, analysisID string) (Verdict, error) {
+	ctx, cancel := context.WithTimeout(ctx, v.MaxWait)
+	defer cancel()
+
+	ticker := time.NewTicker(v.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return Verdict{}, fmt.Errorf("virustotal analysis %s: %w", analysisID, ctx.Err())
+		case <-ticker.C:
+		}
+
+		var analysis struct {
+			Data struct {
+				Attributes struct {
+					Status string        `json:"status"`
+					Stats  analysisStats `json:"stats"`
+				} `json:"attributes"`
+			} `json:"data"`
+		}
+		found, err := v.get(ctx, "/analyses/"+analysisID, &analysis)
+		if err != nil {
+			return Verdict{}, err
+		}
+		if !found {
+			return Verdict{}, fmt.Errorf("virustotal analysis %s not found", analysisID)
+		}
+		if attrs := analysis.Data.Attributes; attrs.Status == "completed" {
+			return verdict(attrs.Stats, ""), nil
+		}
+	}
+}
+
+// get decodes a VirusTotal resource into out, reporting false when it does
+// not exist
+func (v *VirusTotalScanner) get(ctx context.Context, path string, out interface{}) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, virusTotalAPI+path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("x-apikey", v.APIKey)
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("virustotal lookup: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, responseError("virustotal lookup", resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("decode virustotal response: %w", err)
+	}
+	return true, nil
+}
+
+func verdict(stats analysisStats, label string) Verdict {
+	if stats.Malicious == 0 {
+		return Verdict{}
+	}
+	if label == "" {
+		label = fmt.Sprintf("flagged as malicious by %d VirusTotal engines", stats.Malicious)
+	}
+	return Verdict{Infected: true, Threat: label}
+}
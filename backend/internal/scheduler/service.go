@@ -0,0 +1,327 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/civic-weave/backend/internal/cron"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/jobs"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+// leaderLockKey is the Postgres advisory lock held by the instance that
+// schedules runs
+const leaderLockKey = 7036642851
+
+// historyRetention is how long runs are listed for
+const historyRetention = 90 * 24 * time.Hour
+
+// Task is recurring work: at each time its cron schedule matches, a job of
+// Kind is queued
+type Task struct {
+	Name     string
+	Schedule string
+	Kind     string
+}
+
+type task struct {
+	Task
+	schedule *cron.Schedule
+}
+
+// Service queues scheduled tasks as background jobs. Only one instance, the
+// leader, schedules at a time; the others take over if it stops. A run missed
+// while no instance was leader is made up once, not once per missed time.
+type Service struct {
+	db          *sql.DB
+	jobsService *jobs.Service
+	tasks       []task
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func NewService(db *sql.DB, jobsService *jobs.Service) *Service {
+	return &Service{
+		db:          db,
+		jobsService: jobsService,
+		next:        map[string]time.Time{},
+	}
+}
+
+// Register adds a task; register every task before Run. The job kind must
+// be registered with the jobs service.
+func (s *Service) Register(t Task) error {
+	schedule, err := cron.Parse(t.Schedule)
+	if err != nil {
+		return err
+	}
+	s.tasks = append(s.tasks, task{Task: t, schedule: schedule})
+	return nil
+}
+
+// Run tries to become leader every interval and, while leader, queues the
+// tasks that are due, until ctx is cancelled
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lock *sql.Conn
+	defer func() {
+		if lock != nil {
+			s.resign(lock)
+		}
+	}()
+
+	for {
+		if lock == nil {
+			lock = s.elect(ctx)
+			if lock != nil {
+				logging.FromContext(ctx).Info("Scheduler: this instance is now the leader")
+				s.resume()
+			}
+		} else if err := lock.PingContext(ctx); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Warn("Scheduler: lost leadership", "error", err)
+			lock.Close()
+			lock = nil
+			s.mu.Lock()
+			s.next = map[string]time.Time{}
+			s.mu.Unlock()
+		}
+
+		if lock != nil {
+			s.tick(time.Now())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// elect takes the leader lock on a dedicated connection, which holds it
+// until released or the connection drops. It returns nil when another
+// instance leads.
+func (s *Service) elect(ctx context.Context) *sql.Conn {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Scheduler election error", "error", err)
+		return nil
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, leaderLockKey).Scan(&acquired); err != nil {
+		logging.FromContext(ctx).Error("Scheduler election error", "error", err)
+		conn.Close()
+		return nil
+	}
+	if !acquired {
+		conn.Close()
+		return nil
+	}
+	return conn
+}
+
+// resign releases the leader lock before the connection goes back to the
+// pool, where the lock would otherwise outlive the scheduler
+func (s *Service) resign(conn *sql.Conn) {
+	if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, leaderLockKey); err != nil {
+		slog.Error("Scheduler resign error", "error", err)
+		// Drop the connection so the server releases the lock
+		conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	}
+	conn.Close()
+}
+
+// resume works out each task's next run from its last one, so a run
+// missed during a change of leader still happens
+func (s *Service) resume() {
+	now := time.Now()
+	for _, t := range s.tasks {
+		var last sql.NullTime
+		err := database.WithReadRetry(func() error {
+			return s.db.QueryRow(`SELECT MAX(scheduled_for) FROM scheduled_runs WHERE task = $1`, t.Name).Scan(&last)
+		})
+		if err != nil {
+			slog.Error("Scheduler resume error", "task", t.Name, "error", err)
+		}
+
+		from := now
+		if last.Valid {
+			from = last.Time
+		}
+		s.setNext(t.Name, t.schedule.Next(from))
+	}
+}
+
+// tick queues every task that is due and prunes old history
+func (s *Service) tick(now time.Time) {
+	for _, t := range s.tasks {
+		due := s.getNext(t.Name)
+		if due.IsZero() || due.After(now) {
+			continue
+		}
+		if err := s.queue(t, due); err != nil {
+			slog.Error("Scheduler queue error", "task", t.Name, "error", err)
+			continue
+		}
+		s.setNext(t.Name, t.schedule.Next(now))
+	}
+
+	err := database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(`
+			DELETE FROM scheduled_runs WHERE scheduled_for < NOW() - $1 * INTERVAL '1 millisecond'
+		`, historyRetention.Milliseconds())
+		return err
+	})
+	if err != nil {
+		slog.Error("Scheduler prune error", "error", err)
+	}
+}
+
+// queue records the run due at due and queues its job, unless another
+// instance already did. A run due while the task's last job is still queued
+// or running is skipped, so slow tasks never overlap themselves.
+func (s *Service) queue(t task, due time.Time) error {
+	return database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var busy bool
+		err = tx.QueryRow(`
+			SELECT EXISTS (
+				SELECT 1
+				FROM scheduled_runs r
+				JOIN jobs j ON j.id = r.job_id
+				WHERE r.task = $1 AND j.status IN ('queued', 'running')
+			)
+		`, t.Name).Scan(&busy)
+		if err != nil {
+			return err
+		}
+		if busy {
+			slog.Warn("Scheduler skipped task, its last run has not finished", "task", t.Name, "due", due)
+			return nil
+		}
+
+		var runID string
+		err = tx.QueryRow(`
+			INSERT INTO scheduled_runs (task, scheduled_for)
+			VALUES ($1, $2)
+			ON CONFLICT (task, scheduled_for) DO NOTHING
+			RETURNING id
+		`, t.Name, due).Scan(&runID)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		job, err := s.jobsService.EnqueueTx(tx, t.Kind, map[string]interface{}{
+			"task":         t.Name,
+			"scheduledFor": due,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE scheduled_runs SET job_id = $2 WHERE id = $1`, runID, job.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		slog.Info("Scheduler queued task", "task", t.Name, "due", due)
+		return nil
+	})
+}
+
+func (s *Service) getNext(name string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next[name]
+}
+
+func (s *Service) setNext(name string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next[name] = at
+}
+
+// GetTasks lists the registered tasks with their next and last runs
+func (s *Service) GetTasks() ([]models.ScheduledTask, error) {
+	tasks := []models.ScheduledTask{}
+	for _, t := range s.tasks {
+		next := s.getNext(t.Name)
+		if next.IsZero() {
+			// Another instance leads; this is when it will run next
+			next = t.schedule.Next(time.Now())
+		}
+
+		runs, err := s.GetRuns(t.Name, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		task := models.ScheduledTask{Name: t.Name, Schedule: t.Schedule, Kind: t.Kind}
+		if !next.IsZero() {
+			task.NextRunAt = &next
+		}
+		if len(runs) > 0 {
+			task.LastRun = &runs[0]
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// GetRuns lists runs newest first, optionally of one task
+func (s *Service) GetRuns(taskName string, limit int) ([]models.ScheduledRun, error) {
+	// Only succeeded jobs are deleted, so a run whose job is gone succeeded
+	query := `
+		SELECT r.id, r.task, r.scheduled_for, r.job_id, COALESCE(j.status, 'succeeded'),
+		       COALESCE(j.attempts, 0), j.last_error, j.finished_at, r.created_at
+		FROM scheduled_runs r
+		LEFT JOIN jobs j ON j.id = r.job_id
+		WHERE ($1 = '' OR r.task = $1)
+		ORDER BY r.scheduled_for DESC, r.task
+		LIMIT $2
+	`
+
+	var runs []models.ScheduledRun
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, taskName, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		runs = []models.ScheduledRun{}
+		for rows.Next() {
+			var r models.ScheduledRun
+			if err := rows.Scan(&r.ID, &r.Task, &r.ScheduledFor, &r.JobID, &r.Status,
+				&r.Attempts, &r.LastError, &r.FinishedAt, &r.CreatedAt); err != nil {
+				return err
+			}
+			runs = append(runs, r)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,202 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Document is a project as stored in the index
+type Document struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	Description    string     `json:"description"`
+	OrganizationID *string    `json:"organizationId,omitempty"`
+	LocationName   *string    `json:"locationName,omitempty"`
+	Location       *GeoPoint  `json:"location,omitempty"`
+	IsRemote       bool       `json:"isRemote"`
+	StartDate      *time.Time `json:"startDate,omitempty"`
+	EndDate        *time.Time `json:"endDate,omitempty"`
+	Skills         []string   `json:"skills"`
+	Categories     []string   `json:"categories"`
+	// SyncedAt is when the document was last written, so a full reindex
+	// can delete the documents it didn't write
+	SyncedAt time.Time `json:"syncedAt"`
+}
+
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// indexMapping works with OpenSearch and with Elasticsearch 7 and later
+var indexMapping = map[string]interface{}{
+	"mappings": map[string]interface{}{
+		"properties": map[string]interface{}{
+			"id":             map[string]string{"type": "keyword"},
+			"name":           map[string]string{"type": "text"},
+			"description":    map[string]string{"type": "text"},
+			"organizationId": map[string]string{"type": "keyword"},
+			"locationName":   map[string]string{"type": "text"},
+			"location":       map[string]string{"type": "geo_point"},
+			"isRemote":       map[string]string{"type": "boolean"},
+			"startDate":      map[string]string{"type": "date"},
+			"endDate":        map[string]string{"type": "date"},
+			"skills":         map[string]string{"type": "text"},
+			"categories":     map[string]string{"type": "keyword"},
+			"syncedAt":       map[string]string{"type": "date"},
+		},
+	},
+}
+
+// Client talks to an OpenSearch or Elasticsearch cluster over its REST API,
+// keeping projects in a single index
+type Client struct {
+	URL      string
+	Index    string
+	Username string
+	Password string
+	Client   *http.Client
+}
+
+func NewClient(baseURL, index, username, password string) *Client {
+	return &Client{
+		URL:      strings.TrimRight(baseURL, "/"),
+		Index:    index,
+		Username: username,
+		Password: password,
+		Client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// EnsureIndex creates the index unless it exists
+func (c *Client) EnsureIndex(ctx context.Context) error {
+	found, err := c.do(ctx, http.MethodHead, "", nil, nil)
+	if err != nil || found {
+		return err
+	}
+	_, err = c.do(ctx, http.MethodPut, "", indexMapping, nil)
+	return err
+}
+
+// Put adds or replaces a document
+func (c *Client) Put(ctx context.Context, doc Document) error {
+	_, err := c.do(ctx, http.MethodPut, "/_doc/"+url.PathEscape(doc.ID), doc, nil)
+	return err
+}
+
+// Delete removes a document; deleting one that isn't indexed is not an
+// error
+func (c *Client) Delete(ctx context.Context, id string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/_doc/"+url.PathEscape(id), nil, nil)
+	return err
+}
+
+// Bulk adds or replaces documents in one request
+func (c *Client) Bulk(ctx context.Context, docs []Document) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, doc := range docs {
+		action := map[string]interface{}{"index": map[string]string{"_id": doc.ID}}
+		if err := enc.Encode(action); err != nil {
+			return err
+		}
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			ID    string          `json:"_id"`
+			Error json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if _, err := c.send(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", &body, &result); err != nil {
+		return err
+	}
+	if result.Errors {
+		for _, item := range result.Items {
+			for _, r := range item {
+				if len(r.Error) > 0 {
+					return fmt.Errorf("index project %s: %s", r.ID, r.Error)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteSyncedBefore removes the documents last written before t
+func (c *Client) DeleteSyncedBefore(ctx context.Context, t time.Time) error {
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"range": map[string]interface{}{
+				"syncedAt": map[string]interface{}{"lt": t},
+			},
+		},
+	}
+	_, err := c.do(ctx, http.MethodPost, "/_delete_by_query?conflicts=proceed", query, nil)
+	return err
+}
+
+// Search runs a search request body against the index, decoding the
+// response into out
+func (c *Client) Search(ctx context.Context, body interface{}, out interface{}) error {
+	_, err := c.do(ctx, http.MethodPost, "/_search", body, out)
+	return err
+}
+
+// do sends a JSON request to path below the index, reporting false when
+// the index or document does not exist
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) (bool, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return false, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	return c.send(ctx, method, path, "application/json", reader, out)
+}
+
+func (c *Client) send(ctx context.Context, method, path, contentType string, body io.Reader, out interface{}) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.URL+"/"+url.PathEscape(c.Index)+path, body)
+	if err != nil {
+		return false, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("search %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return false, fmt.Errorf("search %s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return false, fmt.Errorf("decode search response: %w", err)
+		}
+	}
+	return true, nil
+}
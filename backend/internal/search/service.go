@@ -0,0 +1,386 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/jobs"
+	"github.com/civic-weave/backend/internal/logging"
+	"github.com/civic-weave/backend/internal/matching"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+const (
+	// IndexProjectJob mirrors one project into the index
+	IndexProjectJob = "search.index_project"
+	// ReindexJob rebuilds the index from every project
+	ReindexJob = "search.reindex"
+)
+
+// reindexBatchSize is how many projects each bulk request writes
+const reindexBatchSize = 500
+
+// distanceBands are the distance facet's ranges, in kilometres
+var distanceBands = []struct {
+	key      string
+	from, to float64
+}{
+	{"0-5", 0, 5},
+	{"5-25", 5, 25},
+	{"25-50", 25, 50},
+	{"50-100", 50, 100},
+	{"100+", 100, 0},
+}
+
+// Query narrows a project search. Every field is optional.
+type Query struct {
+	Text       string
+	Categories []string // skill categories; a project matches any of them
+	// Lat and Lon set the point distances are measured from; RadiusKm,
+	// when positive, leaves out projects further away
+	Lat, Lon     *float64
+	RadiusKm     float64
+	StartsAfter  *time.Time
+	StartsBefore *time.Time
+	Remote       *bool
+	TenantID     string
+	Limit        int
+	Offset       int
+}
+
+// Service keeps active projects mirrored in a search index, where they are
+// found by text with typo tolerance and counted by facet. Changes reach the
+// index through background jobs, so search lags Postgres by a few seconds.
+type Service struct {
+	db          *sql.DB
+	client      *Client
+	jobsService *jobs.Service
+}
+
+// NewService registers the indexing jobs with jobsService, which must not
+// be running yet
+func NewService(db *sql.DB, client *Client, jobsService *jobs.Service) *Service {
+	s := &Service{
+		db:          db,
+		client:      client,
+		jobsService: jobsService,
+	}
+	jobsService.Register(IndexProjectJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			var p indexProjectPayload
+			if err := json.Unmarshal(payload, &p); err != nil || p.ProjectID == "" {
+				return fmt.Errorf("invalid payload %s", payload)
+			}
+			return s.IndexProject(ctx, p.ProjectID)
+		},
+	})
+	jobsService.Register(ReindexJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			n, err := s.Reindex(ctx)
+			if err == nil {
+				logging.FromContext(ctx).Info("Reindexed projects for search", "count", n)
+			}
+			return err
+		},
+		Timeout: 30 * time.Minute,
+	})
+	return s
+}
+
+type indexProjectPayload struct {
+	ProjectID string `json:"projectId"`
+}
+
+// HandleChange consumes payloads from database.ProjectChangedChannel,
+// queueing the changed project to be indexed. Every instance receives each
+// change, but only one job per project waits at a time. An empty payload
+// means changes may have been missed, so the whole index is rebuilt.
+func (s *Service) HandleChange(payload string) {
+	var err error
+	if payload == "" {
+		_, err = s.QueueReindex()
+	} else {
+		_, err = s.jobsService.EnqueueUnique(IndexProjectJob, payload, indexProjectPayload{ProjectID: payload})
+	}
+	if err != nil {
+		slog.Error("Queue search indexing error", "project", payload, "error", err)
+	}
+}
+
+// QueueReindex queues a rebuild of the whole index, unless one is already
+// waiting
+func (s *Service) QueueReindex() (*models.Job, error) {
+	return s.jobsService.EnqueueUnique(ReindexJob, "all", nil)
+}
+
+// IndexProject writes a project to the index, or removes it once it is no
+// longer active
+func (s *Service) IndexProject(ctx context.Context, projectID string) error {
+	docs, err := s.loadDocuments(projectID)
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return s.client.Delete(ctx, projectID)
+	}
+	if err := s.client.EnsureIndex(ctx); err != nil {
+		return err
+	}
+	return s.client.Put(ctx, docs[0])
+}
+
+// Reindex writes every active project to the index and removes the rest,
+// returning how many projects are indexed
+func (s *Service) Reindex(ctx context.Context) (int, error) {
+	if err := s.client.EnsureIndex(ctx); err != nil {
+		return 0, err
+	}
+
+	started := time.Now()
+	docs, err := s.loadDocuments("")
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(docs); i += reindexBatchSize {
+		end := i + reindexBatchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		if err := s.client.Bulk(ctx, docs[i:end]); err != nil {
+			return 0, err
+		}
+	}
+
+	// Projects indexed since started were written by IndexProject and are
+	// current too
+	if err := s.client.DeleteSyncedBefore(ctx, started); err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// loadDocuments reads active projects as index documents: one project, or
+// all of them when projectID is empty
+func (s *Service) loadDocuments(projectID string) ([]Document, error) {
+	query := `
+		SELECT p.id, p.name, p.description, p.organization_id, p.location_name,
+		       p.latitude, p.longitude, p.is_remote, p.start_date, p.end_date,
+		       COALESCE(array_agg(DISTINCT s.name) FILTER (WHERE s.id IS NOT NULL), '{}'),
+		       COALESCE(array_agg(DISTINCT s.category) FILTER (WHERE s.category IS NOT NULL), '{}')
+		FROM projects p
+		LEFT JOIN project_skills ps ON ps.project_id = p.id
+		LEFT JOIN skills s ON s.id = ps.skill_id
+		WHERE p.status = 'active'
+		  AND ($1 = '' OR p.id = NULLIF($1, '')::uuid)
+		GROUP BY p.id
+	`
+
+	var docs []Document
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, projectID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		docs = []Document{}
+		now := time.Now()
+		for rows.Next() {
+			var d Document
+			var lat, lon sql.NullFloat64
+			if err := rows.Scan(&d.ID, &d.Name, &d.Description, &d.OrganizationID, &d.LocationName,
+				&lat, &lon, &d.IsRemote, &d.StartDate, &d.EndDate,
+				pq.Array(&d.Skills), pq.Array(&d.Categories)); err != nil {
+				return err
+			}
+			if lat.Valid && lon.Valid {
+				d.Location = &GeoPoint{Lat: lat.Float64, Lon: lon.Float64}
+			}
+			d.SyncedAt = now
+			docs = append(docs, d)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+// Search finds active projects matching q, best matches first; without
+// text, nearest first when searching from a point and otherwise soonest
+// starting first. Lat and Lon must be set together.
+func (s *Service) Search(ctx context.Context, q Query) (*models.ProjectSearchResult, error) {
+	var must interface{} = map[string]interface{}{"match_all": map[string]interface{}{}}
+	if q.Text != "" {
+		must = map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":     q.Text,
+				"fields":    []string{"name^3", "skills^2", "categories^2", "description", "locationName"},
+				"fuzziness": "AUTO",
+			},
+		}
+	}
+
+	filters := []interface{}{}
+	if q.TenantID != "" {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"organizationId": q.TenantID}})
+	}
+	if len(q.Categories) > 0 {
+		filters = append(filters, map[string]interface{}{"terms": map[string]interface{}{"categories": q.Categories}})
+	}
+	if q.Remote != nil {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"isRemote": *q.Remote}})
+	}
+	if q.StartsAfter != nil || q.StartsBefore != nil {
+		dates := map[string]interface{}{}
+		if q.StartsAfter != nil {
+			dates["gte"] = q.StartsAfter
+		}
+		if q.StartsBefore != nil {
+			dates["lte"] = q.StartsBefore
+		}
+		filters = append(filters, map[string]interface{}{"range": map[string]interface{}{"startDate": dates}})
+	}
+
+	aggs := map[string]interface{}{
+		"categories": map[string]interface{}{
+			"terms": map[string]interface{}{"field": "categories", "size": 50},
+		},
+		"startMonths": map[string]interface{}{
+			"date_histogram": map[string]interface{}{
+				"field":             "startDate",
+				"calendar_interval": "month",
+				"format":            "yyyy-MM",
+				"min_doc_count":     1,
+			},
+		},
+	}
+
+	sort := []interface{}{"_score", map[string]interface{}{"startDate": map[string]string{"order": "asc", "missing": "_last"}}}
+	if q.Lat != nil {
+		origin := GeoPoint{Lat: *q.Lat, Lon: *q.Lon}
+		if q.RadiusKm > 0 {
+			filters = append(filters, map[string]interface{}{
+				"geo_distance": map[string]interface{}{
+					"distance": fmt.Sprintf("%gkm", q.RadiusKm),
+					"location": origin,
+				},
+			})
+		}
+
+		ranges := []interface{}{}
+		for _, band := range distanceBands {
+			r := map[string]interface{}{"key": band.key, "from": band.from}
+			if band.to > 0 {
+				r["to"] = band.to
+			}
+			ranges = append(ranges, r)
+		}
+		aggs["distance"] = map[string]interface{}{
+			"geo_distance": map[string]interface{}{
+				"field":  "location",
+				"origin": origin,
+				"unit":   "km",
+				"ranges": ranges,
+			},
+		}
+
+		if q.Text == "" {
+			sort = []interface{}{map[string]interface{}{
+				"_geo_distance": map[string]interface{}{
+					"location": origin,
+					"order":    "asc",
+					"unit":     "km",
+				},
+			}}
+		}
+	}
+
+	body := map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{"must": must, "filter": filters},
+		},
+		"aggs":             aggs,
+		"sort":             sort,
+		"from":             q.Offset,
+		"size":             q.Limit,
+		"track_total_hits": true,
+	}
+
+	type bucket struct {
+		Key         interface{} `json:"key"`
+		KeyAsString string      `json:"key_as_string"`
+		DocCount    int         `json:"doc_count"`
+	}
+	var resp struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Source Document `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+		Aggregations map[string]struct {
+			Buckets []bucket `json:"buckets"`
+		} `json:"aggregations"`
+	}
+	if err := s.client.Search(ctx, body, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &models.ProjectSearchResult{
+		Total:    resp.Hits.Total.Value,
+		Projects: []models.ProjectSearchHit{},
+		Facets: models.ProjectSearchFacets{
+			Categories:  []models.FacetCount{},
+			StartMonths: []models.FacetCount{},
+		},
+	}
+	for _, hit := range resp.Hits.Hits {
+		d := hit.Source
+		h := models.ProjectSearchHit{
+			ID:             d.ID,
+			Name:           d.Name,
+			Description:    d.Description,
+			OrganizationID: d.OrganizationID,
+			LocationName:   d.LocationName,
+			IsRemote:       d.IsRemote,
+			StartDate:      d.StartDate,
+			EndDate:        d.EndDate,
+			Skills:         d.Skills,
+			Categories:     d.Categories,
+		}
+		if d.Location != nil {
+			h.Latitude, h.Longitude = &d.Location.Lat, &d.Location.Lon
+			if q.Lat != nil {
+				km := matching.HaversineDistance(*q.Lat, *q.Lon, d.Location.Lat, d.Location.Lon)
+				h.DistanceKm = &km
+			}
+		}
+		result.Projects = append(result.Projects, h)
+	}
+
+	for _, b := range resp.Aggregations["categories"].Buckets {
+		result.Facets.Categories = append(result.Facets.Categories, models.FacetCount{Value: fmt.Sprint(b.Key), Count: b.DocCount})
+	}
+	for _, b := range resp.Aggregations["startMonths"].Buckets {
+		result.Facets.StartMonths = append(result.Facets.StartMonths, models.FacetCount{Value: b.KeyAsString, Count: b.DocCount})
+	}
+	if q.Lat != nil {
+		result.Facets.Distance = []models.FacetCount{}
+		for _, b := range resp.Aggregations["distance"].Buckets {
+			result.Facets.Distance = append(result.Facets.Distance, models.FacetCount{Value: fmt.Sprint(b.Key), Count: b.DocCount})
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,325 @@
+package shifts
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/civic-weave/backend/internal/availability"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrNoAssignments       = errors.New("assignments must list between 1 and 500 shift and volunteer pairs")
+	ErrDuplicateAssignment = errors.New("assignments must not repeat a shift and volunteer pair")
+)
+
+const maxAssignments = 500
+
+// AssignmentError rejects an accepted proposal when one of its assignments
+// no longer holds, e.g. because the shift filled up since it was proposed
+type AssignmentError struct {
+	ShiftID     string
+	VolunteerID string
+	Err         error
+}
+
+func (e *AssignmentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AssignmentError) Unwrap() error {
+	return e.Err
+}
+
+// openShift is an upcoming shift with seats left, as seen by the optimizer
+type openShift struct {
+	ID        string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	OpenSeats int
+	SkillIDs  []string
+	Booked    map[string]bool
+	Eligible  int
+}
+
+// candidate is an enrolled volunteer with their claimed skills and current
+// bookings
+type candidate struct {
+	ID       string
+	Name     string
+	Skills   map[string]bool
+	Bookings [][2]time.Time
+	Hours    float64
+}
+
+// ProposeAssignments fills the project's open upcoming shifts with enrolled
+// volunteers who have every skill the shift requires, no overlapping
+// booking, and availability covering the whole shift. Seats go to the
+// eligible volunteer booked for the fewest hours on the project so far,
+// filling the shifts with the fewest eligible volunteers first. Nothing is
+// booked; the coordinator accepts the proposal with AcceptAssignments.
+func (s *Service) ProposeAssignments(projectID, tenantID string) (*models.AssignmentProposal, error) {
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	var shifts []*openShift
+	var candidates []*candidate
+	var slots map[string][]models.AvailabilitySlot
+	err := database.WithReadRetry(func() error {
+		var err error
+		shifts, err = s.loadOpenShifts(projectID)
+		if err != nil {
+			return err
+		}
+		candidates, err = s.loadCandidates(projectID)
+		if err != nil || len(shifts) == 0 || len(candidates) == 0 {
+			return err
+		}
+
+		from, to := shifts[0].StartsAt, shifts[0].EndsAt
+		for _, sh := range shifts {
+			if sh.EndsAt.After(to) {
+				to = sh.EndsAt
+			}
+		}
+		ids := make([]string, len(candidates))
+		for i, c := range candidates {
+			ids[i] = c.ID
+		}
+		slots, err = availability.LoadSlots(s.db, ids, from, to)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eligible := func(sh *openShift, c *candidate) bool {
+		if sh.Booked[c.ID] {
+			return false
+		}
+		for _, skillID := range sh.SkillIDs {
+			if !c.Skills[skillID] {
+				return false
+			}
+		}
+		for _, b := range c.Bookings {
+			if b[0].Before(sh.EndsAt) && b[1].After(sh.StartsAt) {
+				return false
+			}
+		}
+		if volunteerSlots, hasRules := slots[c.ID]; hasRules && !availability.Covers(volunteerSlots, sh.StartsAt, sh.EndsAt) {
+			return false
+		}
+		return true
+	}
+
+	// Fill the hardest shifts first so flexible volunteers aren't used up
+	for _, sh := range shifts {
+		for _, c := range candidates {
+			if eligible(sh, c) {
+				sh.Eligible++
+			}
+		}
+	}
+	sort.SliceStable(shifts, func(i, j int) bool { return shifts[i].Eligible < shifts[j].Eligible })
+
+	proposal := &models.AssignmentProposal{
+		Assignments: []models.ShiftAssignment{},
+		Unfilled:    []models.UnfilledShift{},
+		Load:        []models.VolunteerLoad{},
+	}
+	for _, sh := range shifts {
+		for sh.OpenSeats > 0 {
+			var best *candidate
+			for _, c := range candidates {
+				if !eligible(sh, c) {
+					continue
+				}
+				if best == nil || c.Hours < best.Hours {
+					best = c
+				}
+			}
+			if best == nil {
+				break
+			}
+
+			sh.OpenSeats--
+			sh.Booked[best.ID] = true
+			best.Bookings = append(best.Bookings, [2]time.Time{sh.StartsAt, sh.EndsAt})
+			best.Hours += sh.EndsAt.Sub(sh.StartsAt).Hours()
+			proposal.Assignments = append(proposal.Assignments, models.ShiftAssignment{
+				ShiftID:       sh.ID,
+				VolunteerID:   best.ID,
+				VolunteerName: best.Name,
+				StartsAt:      sh.StartsAt,
+				EndsAt:        sh.EndsAt,
+			})
+		}
+		if sh.OpenSeats > 0 {
+			proposal.Unfilled = append(proposal.Unfilled, models.UnfilledShift{ShiftID: sh.ID, OpenSeats: sh.OpenSeats})
+		}
+	}
+
+	sort.SliceStable(proposal.Assignments, func(i, j int) bool {
+		return proposal.Assignments[i].StartsAt.Before(proposal.Assignments[j].StartsAt)
+	})
+	for _, c := range candidates {
+		proposal.Load = append(proposal.Load, models.VolunteerLoad{VolunteerID: c.ID, VolunteerName: c.Name, Hours: c.Hours})
+	}
+
+	return proposal, nil
+}
+
+// AcceptAssignments books every assignment in one transaction, checking each
+// as a signup. If any no longer holds, nothing is booked and an
+// *AssignmentError names it.
+func (s *Service) AcceptAssignments(projectID, tenantID string, assignments []models.ShiftAssignment) error {
+	if len(assignments) == 0 || len(assignments) > maxAssignments {
+		return ErrNoAssignments
+	}
+
+	// A consistent order keeps concurrent accepts from deadlocking
+	sorted := append([]models.ShiftAssignment(nil), assignments...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].ShiftID != sorted[j].ShiftID {
+			return sorted[i].ShiftID < sorted[j].ShiftID
+		}
+		return sorted[i].VolunteerID < sorted[j].VolunteerID
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].ShiftID == sorted[i-1].ShiftID && sorted[i].VolunteerID == sorted[i-1].VolunteerID {
+			return ErrDuplicateAssignment
+		}
+	}
+
+	return database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		for _, a := range sorted {
+			err := signUp(tx, projectID, a.ShiftID, a.VolunteerID, tenantID)
+			switch err {
+			case nil:
+			case ErrShiftNotFound, ErrShiftStarted, ErrNotEnrolled, ErrAlreadySignedUp,
+				ErrShiftConflict, ErrShiftFull, ErrUnavailable:
+				return &AssignmentError{ShiftID: a.ShiftID, VolunteerID: a.VolunteerID, Err: err}
+			default:
+				return err
+			}
+		}
+
+		return tx.Commit()
+	})
+}
+
+func (s *Service) loadOpenShifts(projectID string) ([]*openShift, error) {
+	rows, err := s.db.Query(`
+		SELECT s.id, s.starts_at, s.ends_at,
+		       s.capacity - (SELECT COUNT(*) FROM shift_signups ss WHERE ss.shift_id = s.id),
+		       ARRAY(SELECT sk.skill_id::text FROM shift_skills sk WHERE sk.shift_id = s.id),
+		       ARRAY(SELECT ss.volunteer_id::text FROM shift_signups ss WHERE ss.shift_id = s.id)
+		FROM project_shifts s
+		WHERE s.project_id = $1
+		  AND s.starts_at > NOW()
+		ORDER BY s.starts_at, s.id
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shifts []*openShift
+	for rows.Next() {
+		var sh openShift
+		var booked []string
+		if err := rows.Scan(&sh.ID, &sh.StartsAt, &sh.EndsAt, &sh.OpenSeats, pq.Array(&sh.SkillIDs), pq.Array(&booked)); err != nil {
+			return nil, err
+		}
+		if sh.OpenSeats <= 0 {
+			continue
+		}
+		sh.Booked = make(map[string]bool, len(booked))
+		for _, id := range booked {
+			sh.Booked[id] = true
+		}
+		shifts = append(shifts, &sh)
+	}
+	return shifts, rows.Err()
+}
+
+// loadCandidates lists the project's enrolled volunteers by name, with their
+// upcoming bookings on any project and the hours booked on this one
+func (s *Service) loadCandidates(projectID string) ([]*candidate, error) {
+	rows, err := s.db.Query(`
+		SELECT u.id, u.name,
+		       ARRAY(SELECT vs.skill_id::text FROM volunteer_skills vs WHERE vs.volunteer_id = u.id AND vs.claimed)
+		FROM volunteer_enrollments ve
+		JOIN users u ON u.id = ve.volunteer_id
+		WHERE ve.project_id = $1
+		  AND ve.status = 'enrolled'
+		ORDER BY u.name, u.id
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []*candidate
+	byID := make(map[string]*candidate)
+	for rows.Next() {
+		var c candidate
+		var skills []string
+		if err := rows.Scan(&c.ID, &c.Name, pq.Array(&skills)); err != nil {
+			return nil, err
+		}
+		c.Skills = make(map[string]bool, len(skills))
+		for _, id := range skills {
+			c.Skills[id] = true
+		}
+		candidates = append(candidates, &c)
+		byID[c.ID] = &c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.ID
+	}
+	bookings, err := s.db.Query(`
+		SELECT ss.volunteer_id, s.project_id, s.starts_at, s.ends_at
+		FROM shift_signups ss
+		JOIN project_shifts s ON s.id = ss.shift_id
+		WHERE ss.volunteer_id = ANY($1::uuid[])
+		  AND s.ends_at > NOW()
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer bookings.Close()
+
+	for bookings.Next() {
+		var volunteerID, bookedProjectID string
+		var b [2]time.Time
+		if err := bookings.Scan(&volunteerID, &bookedProjectID, &b[0], &b[1]); err != nil {
+			return nil, err
+		}
+		c := byID[volunteerID]
+		c.Bookings = append(c.Bookings, b)
+		if bookedProjectID == projectID {
+			c.Hours += b[1].Sub(b[0]).Hours()
+		}
+	}
+	return candidates, bookings.Err()
+}
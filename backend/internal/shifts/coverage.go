@@ -0,0 +1,423 @@
+package shifts
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/availability"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+)
+
+var (
+	ErrCoverageNotFound  = errors.New("coverage request not found")
+	ErrCoverageRequested = errors.New("coverage has already been requested for this shift")
+	ErrCoverageClosed    = errors.New("coverage request is no longer open")
+	ErrOwnCoverage       = errors.New("volunteers cannot claim their own coverage request")
+	ErrNoteTooLong       = errors.New("note must be at most 1000 characters")
+)
+
+const coverageSelect = `
+	SELECT cr.id, cr.shift_id, s.project_id, p.name, s.title, s.starts_at, s.ends_at,
+	       cr.requester_id, ru.name, cr.note, cr.status, cr.claimed_by, cu.name,
+	       cr.created_at, cr.resolved_at
+	FROM shift_coverage_requests cr
+	JOIN project_shifts s ON s.id = cr.shift_id
+	JOIN projects p ON p.id = s.project_id
+	JOIN users ru ON ru.id = cr.requester_id
+	LEFT JOIN users cu ON cu.id = cr.claimed_by
+`
+
+func scanCoverage(scanner interface{ Scan(...interface{}) error }, c *models.ShiftCoverageRequest) error {
+	return scanner.Scan(
+		&c.ID,
+		&c.ShiftID,
+		&c.ProjectID,
+		&c.ProjectName,
+		&c.ShiftTitle,
+		&c.StartsAt,
+		&c.EndsAt,
+		&c.RequesterID,
+		&c.RequesterName,
+		&c.Note,
+		&c.Status,
+		&c.ClaimedBy,
+		&c.ClaimedByName,
+		&c.CreatedAt,
+		&c.ResolvedAt,
+	)
+}
+
+// GetCoverageRequests lists open coverage requests for the project's
+// upcoming shifts, soonest first. Requests whose requester has since left
+// the shift are left out.
+func (s *Service) GetCoverageRequests(projectID, tenantID string) ([]models.ShiftCoverageRequest, error) {
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	query := coverageSelect + `
+		WHERE s.project_id = $1
+		  AND cr.status = 'open'
+		  AND s.starts_at > NOW()
+		  AND EXISTS (
+		      SELECT 1 FROM shift_signups ss
+		      WHERE ss.shift_id = cr.shift_id AND ss.volunteer_id = cr.requester_id
+		  )
+		ORDER BY s.starts_at, cr.created_at
+	`
+
+	var requests []models.ShiftCoverageRequest
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, projectID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		requests = []models.ShiftCoverageRequest{}
+		for rows.Next() {
+			var c models.ShiftCoverageRequest
+			if err := scanCoverage(rows, &c); err != nil {
+				return err
+			}
+			requests = append(requests, c)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// GetCoverageRequest fetches one of the project's coverage requests
+func (s *Service) GetCoverageRequest(projectID, requestID, tenantID string) (*models.ShiftCoverageRequest, error) {
+	query := coverageSelect + `
+		WHERE cr.id = $1
+		  AND s.project_id = $2
+		  AND ($3 = '' OR p.organization_id = NULLIF($3, '')::uuid)
+	`
+
+	var c models.ShiftCoverageRequest
+	err := database.WithReadRetry(func() error {
+		return scanCoverage(s.db.QueryRow(query, requestID, projectID, tenantID), &c)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrCoverageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// RequestCoverage opens a coverage request for a volunteer booked onto a
+// shift that has not started. It returns the enrolled volunteers who are
+// free for the whole shift, to be notified.
+func (s *Service) RequestCoverage(projectID, shiftID, volunteerID, tenantID string, req models.CreateCoverageRequest) (*models.ShiftCoverageRequest, []models.CoverageCandidate, error) {
+	var note *string
+	if req.Note != nil {
+		if trimmed := strings.TrimSpace(*req.Note); trimmed != "" {
+			if len(trimmed) > 1000 {
+				return nil, nil, ErrNoteTooLong
+			}
+			note = &trimmed
+		}
+	}
+
+	var requestID string
+	var startsAt, endsAt time.Time
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		err = tx.QueryRow(`
+			SELECT s.starts_at, s.ends_at
+			FROM project_shifts s
+			JOIN projects p ON p.id = s.project_id
+			WHERE s.id = $1
+			  AND s.project_id = $2
+			  AND ($3 = '' OR p.organization_id = NULLIF($3, '')::uuid)
+		`, shiftID, projectID, tenantID).Scan(&startsAt, &endsAt)
+		if err == sql.ErrNoRows {
+			return ErrShiftNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if !startsAt.After(time.Now()) {
+			return ErrShiftStarted
+		}
+
+		var signedUp bool
+		err = tx.QueryRow(`
+			SELECT EXISTS (SELECT 1 FROM shift_signups WHERE shift_id = $1 AND volunteer_id = $2)
+		`, shiftID, volunteerID).Scan(&signedUp)
+		if err != nil {
+			return err
+		}
+		if !signedUp {
+			return ErrSignupNotFound
+		}
+
+		err = tx.QueryRow(`
+			INSERT INTO shift_coverage_requests (shift_id, requester_id, note)
+			VALUES ($1, $2, $3)
+			RETURNING id
+		`, shiftID, volunteerID, note).Scan(&requestID)
+		if isUniqueViolation(err) {
+			return ErrCoverageRequested
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	coverage, err := s.GetCoverageRequest(projectID, requestID, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidates, err := s.coverageCandidates(projectID, volunteerID, startsAt, endsAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return coverage, candidates, nil
+}
+
+// ClaimCoverage moves the requester's place on the shift to the claiming
+// volunteer. The claimer must be eligible as for a signup; the request and
+// shift rows are locked so only one claim wins.
+func (s *Service) ClaimCoverage(projectID, requestID, volunteerID, tenantID string) (*models.ShiftCoverageRequest, error) {
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var shiftID, requesterID, status string
+		var startsAt, endsAt time.Time
+		err = tx.QueryRow(`
+			SELECT cr.shift_id, cr.requester_id, cr.status, s.starts_at, s.ends_at
+			FROM shift_coverage_requests cr
+			JOIN project_shifts s ON s.id = cr.shift_id
+			JOIN projects p ON p.id = s.project_id
+			WHERE cr.id = $1
+			  AND s.project_id = $2
+			  AND ($3 = '' OR p.organization_id = NULLIF($3, '')::uuid)
+			FOR UPDATE OF cr, s
+		`, requestID, projectID, tenantID).Scan(&shiftID, &requesterID, &status, &startsAt, &endsAt)
+		if err == sql.ErrNoRows {
+			return ErrCoverageNotFound
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case status != "open":
+			return ErrCoverageClosed
+		case requesterID == volunteerID:
+			return ErrOwnCoverage
+		case !startsAt.After(time.Now()):
+			return ErrShiftStarted
+		}
+
+		var enrolled bool
+		err = tx.QueryRow(`
+			SELECT EXISTS (
+				SELECT 1 FROM volunteer_enrollments
+				WHERE project_id = $1 AND volunteer_id = $2 AND status = 'enrolled'
+			)
+		`, projectID, volunteerID).Scan(&enrolled)
+		if err != nil {
+			return err
+		}
+		if !enrolled {
+			return ErrNotEnrolled
+		}
+
+		if _, err := tx.Exec(`SELECT 1 FROM users WHERE id = $1 FOR NO KEY UPDATE`, volunteerID); err != nil {
+			return err
+		}
+
+		var alreadySignedUp, conflict bool
+		err = tx.QueryRow(`
+			SELECT EXISTS (SELECT 1 FROM shift_signups WHERE shift_id = $1 AND volunteer_id = $2),
+			       EXISTS (
+			           SELECT 1
+			           FROM shift_signups ss
+			           JOIN project_shifts os ON os.id = ss.shift_id
+			           WHERE ss.volunteer_id = $2
+			             AND os.id <> $1
+			             AND os.starts_at < $4
+			             AND os.ends_at > $3
+			       )
+		`, shiftID, volunteerID, startsAt, endsAt).Scan(&alreadySignedUp, &conflict)
+		if err != nil {
+			return err
+		}
+		switch {
+		case alreadySignedUp:
+			return ErrAlreadySignedUp
+		case conflict:
+			return ErrShiftConflict
+		}
+
+		available, err := availability.IsAvailable(tx, volunteerID, startsAt, endsAt)
+		if err != nil {
+			return err
+		}
+		if !available {
+			return ErrUnavailable
+		}
+
+		result, err := tx.Exec(`DELETE FROM shift_signups WHERE shift_id = $1 AND volunteer_id = $2`, shiftID, requesterID)
+		if err != nil {
+			return err
+		}
+		removed, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			// The requester cancelled their signup since asking
+			return ErrCoverageClosed
+		}
+
+		if _, err := tx.Exec(`INSERT INTO shift_signups (shift_id, volunteer_id) VALUES ($1, $2)`, shiftID, volunteerID); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			UPDATE shift_coverage_requests
+			SET status = 'claimed', claimed_by = $2, resolved_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+		`, requestID, volunteerID)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCoverageRequest(projectID, requestID, tenantID)
+}
+
+// CancelCoverage withdraws an open coverage request
+func (s *Service) CancelCoverage(requestID string) error {
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(`
+			UPDATE shift_coverage_requests
+			SET status = 'cancelled', resolved_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND status = 'open'
+		`, requestID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrCoverageClosed
+	}
+
+	return nil
+}
+
+// GetVolunteerContact returns the volunteer's email address and locale, for
+// notifying requesters when their shift is covered
+func (s *Service) GetVolunteerContact(volunteerID string) (email, locale string, err error) {
+	err = database.WithReadRetry(func() error {
+		return s.db.QueryRow(`SELECT email, locale FROM users WHERE id = $1`, volunteerID).Scan(&email, &locale)
+	})
+	return email, locale, err
+}
+
+// coverageCandidates lists the project's enrolled volunteers, other than the
+// requester, who have no overlapping booking and whose availability covers
+// the shift
+func (s *Service) coverageCandidates(projectID, requesterID string, startsAt, endsAt time.Time) ([]models.CoverageCandidate, error) {
+	query := `
+		SELECT u.id, u.name, u.email, u.locale
+		FROM volunteer_enrollments ve
+		JOIN users u ON u.id = ve.volunteer_id
+		WHERE ve.project_id = $1
+		  AND ve.status = 'enrolled'
+		  AND u.id <> $2
+		  AND NOT EXISTS (
+		      SELECT 1
+		      FROM shift_signups ss
+		      JOIN project_shifts os ON os.id = ss.shift_id
+		      WHERE ss.volunteer_id = u.id
+		        AND os.starts_at < $4
+		        AND os.ends_at > $3
+		  )
+		ORDER BY u.name, u.id
+	`
+
+	var candidates []models.CoverageCandidate
+	var slots map[string][]models.AvailabilitySlot
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, projectID, requesterID, startsAt, endsAt)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		candidates = nil
+		for rows.Next() {
+			var c models.CoverageCandidate
+			if err := rows.Scan(&c.VolunteerID, &c.Name, &c.Email, &c.Locale); err != nil {
+				return err
+			}
+			candidates = append(candidates, c)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		ids := make([]string, len(candidates))
+		for i, c := range candidates {
+			ids[i] = c.VolunteerID
+		}
+		slots, err = availability.LoadSlots(s.db, ids, startsAt, endsAt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eligible := candidates[:0]
+	for _, c := range candidates {
+		if volunteerSlots, hasRules := slots[c.VolunteerID]; hasRules && !availability.Covers(volunteerSlots, startsAt, endsAt) {
+			continue
+		}
+		eligible = append(eligible, c)
+	}
+
+	return eligible, nil
+}
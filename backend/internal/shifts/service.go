@@ -0,0 +1,646 @@
+package shifts
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/civic-weave/backend/internal/availability"
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrProjectNotFound      = errors.New("project not found")
+	ErrShiftNotFound        = errors.New("shift not found")
+	ErrSignupNotFound       = errors.New("shift signup not found")
+	ErrInvalidTimes         = errors.New("endsAt must be after startsAt")
+	ErrInvalidCapacity      = errors.New("capacity must be between 1 and 1000")
+	ErrTitleTooLong         = errors.New("title must be at most 255 characters")
+	ErrTooManySkills        = errors.New("shifts can require at most 20 skills")
+	ErrUnknownSkill         = errors.New("skillIds must name existing skills")
+	ErrShiftStarted         = errors.New("shift has already started")
+	ErrNotEnrolled          = errors.New("volunteer is not enrolled in the project")
+	ErrAlreadySignedUp      = errors.New("volunteer is already signed up for this shift")
+	ErrShiftFull            = errors.New("shift is full")
+	ErrShiftConflict        = errors.New("volunteer is already booked on an overlapping shift")
+	ErrUnavailable          = errors.New("shift falls outside the volunteer's availability")
+	ErrCapacityBelowSignups = errors.New("capacity cannot be below the number of volunteers signed up")
+)
+
+const (
+	maxShiftCapacity = 1000
+	maxShiftSkills   = 20
+)
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+const shiftSelect = `
+	SELECT s.id, s.project_id, s.title, s.starts_at, s.ends_at, s.capacity,
+	       (SELECT COUNT(*) FROM shift_signups ss WHERE ss.shift_id = s.id),
+	       ARRAY(SELECT sk.skill_id::text FROM shift_skills sk WHERE sk.shift_id = s.id ORDER BY sk.skill_id),
+	       s.created_by, s.created_at
+	FROM project_shifts s
+	JOIN projects p ON p.id = s.project_id
+`
+
+func scanShift(scanner interface{ Scan(...interface{}) error }, sh *models.Shift) error {
+	return scanner.Scan(
+		&sh.ID,
+		&sh.ProjectID,
+		&sh.Title,
+		&sh.StartsAt,
+		&sh.EndsAt,
+		&sh.Capacity,
+		&sh.SignedUp,
+		pq.Array(&sh.SkillIDs),
+		&sh.CreatedBy,
+		&sh.CreatedAt,
+	)
+}
+
+// GetProjectShifts lists a project's shifts by start time. Shifts that have
+// ended are left out unless includePast is set.
+func (s *Service) GetProjectShifts(projectID, tenantID string, includePast bool) ([]models.Shift, error) {
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	query := shiftSelect + `
+		WHERE s.project_id = $1
+		  AND ($2 OR s.ends_at > NOW())
+		ORDER BY s.starts_at, s.id
+	`
+
+	var shifts []models.Shift
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, projectID, includePast)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		shifts = []models.Shift{}
+		for rows.Next() {
+			var sh models.Shift
+			if err := scanShift(rows, &sh); err != nil {
+				return err
+			}
+			shifts = append(shifts, sh)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shifts, nil
+}
+
+// CreateShift adds a shift to the project
+func (s *Service) CreateShift(projectID, tenantID, createdBy string, req models.CreateShiftRequest) (*models.Shift, error) {
+	title, skillIDs, err := checkShift(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO project_shifts (project_id, title, starts_at, ends_at, capacity, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, project_id, title, starts_at, ends_at, capacity, 0, '{}'::text[], created_by, created_at
+	`
+
+	var sh models.Shift
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		err = scanShift(tx.QueryRow(query, projectID, title, req.StartsAt, req.EndsAt, req.Capacity, createdBy), &sh)
+		if err != nil {
+			return err
+		}
+
+		if err := setSkills(tx, sh.ID, skillIDs); err != nil {
+			return err
+		}
+		sh.SkillIDs = skillIDs
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sh, nil
+}
+
+// GetShift returns one of the project's shifts
+func (s *Service) GetShift(projectID, shiftID, tenantID string) (*models.Shift, error) {
+	query := shiftSelect + `
+		WHERE s.id = $1
+		  AND s.project_id = $2
+		  AND ($3 = '' OR p.organization_id = NULLIF($3, '')::uuid)
+	`
+
+	var sh models.Shift
+	err := database.WithReadRetry(func() error {
+		return scanShift(s.db.QueryRow(query, shiftID, projectID, tenantID), &sh)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrShiftNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &sh, nil
+}
+
+// UpdateShift reschedules a shift that has not started, replacing its
+// title, times, capacity and skills. Its signups are kept, so capacity
+// cannot drop below them and the new times cannot overlap another shift a
+// booked volunteer is on.
+func (s *Service) UpdateShift(projectID, shiftID, tenantID string, req models.CreateShiftRequest) (*models.Shift, error) {
+	title, skillIDs, err := checkShift(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var sh models.Shift
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var startsAt time.Time
+		err = tx.QueryRow(`
+			SELECT s.starts_at
+			FROM project_shifts s
+			JOIN projects p ON p.id = s.project_id
+			WHERE s.id = $1
+			  AND s.project_id = $2
+			  AND ($3 = '' OR p.organization_id = NULLIF($3, '')::uuid)
+			FOR UPDATE OF s
+		`, shiftID, projectID, tenantID).Scan(&startsAt)
+		if err == sql.ErrNoRows {
+			return ErrShiftNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if !startsAt.After(time.Now()) {
+			return ErrShiftStarted
+		}
+
+		// Lock the booked volunteers like signups do, so none is booked onto
+		// an overlapping shift while this one moves
+		if _, err := tx.Exec(`
+			SELECT 1 FROM users
+			WHERE id IN (SELECT volunteer_id FROM shift_signups WHERE shift_id = $1)
+			FOR NO KEY UPDATE
+		`, shiftID); err != nil {
+			return err
+		}
+
+		var signedUp int
+		var conflict bool
+		err = tx.QueryRow(`
+			SELECT (SELECT COUNT(*) FROM shift_signups WHERE shift_id = $1),
+			       EXISTS (
+			           SELECT 1
+			           FROM shift_signups ss
+			           JOIN shift_signups os ON os.volunteer_id = ss.volunteer_id AND os.shift_id <> ss.shift_id
+			           JOIN project_shifts o ON o.id = os.shift_id
+			           WHERE ss.shift_id = $1
+			             AND o.starts_at < $3
+			             AND o.ends_at > $2
+			       )
+		`, shiftID, req.StartsAt, req.EndsAt).Scan(&signedUp, &conflict)
+		if err != nil {
+			return err
+		}
+		switch {
+		case req.Capacity < signedUp:
+			return ErrCapacityBelowSignups
+		case conflict:
+			return ErrShiftConflict
+		}
+
+		_, err = tx.Exec(`
+			UPDATE project_shifts
+			SET title = $2, starts_at = $3, ends_at = $4, capacity = $5
+			WHERE id = $1
+		`, shiftID, title, req.StartsAt, req.EndsAt, req.Capacity)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`DELETE FROM shift_skills WHERE shift_id = $1`, shiftID); err != nil {
+			return err
+		}
+		if err := setSkills(tx, shiftID, skillIDs); err != nil {
+			return err
+		}
+
+		err = scanShift(tx.QueryRow(shiftSelect+`WHERE s.id = $1`, shiftID), &sh)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sh, nil
+}
+
+// checkShift validates a shift's fields, returning its trimmed title, nil
+// when blank, and its skills without duplicates
+func checkShift(req models.CreateShiftRequest) (*string, []string, error) {
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, nil, ErrInvalidTimes
+	}
+	if req.Capacity < 1 || req.Capacity > maxShiftCapacity {
+		return nil, nil, ErrInvalidCapacity
+	}
+	var title *string
+	if req.Title != nil {
+		if trimmed := strings.TrimSpace(*req.Title); trimmed != "" {
+			if len(trimmed) > 255 {
+				return nil, nil, ErrTitleTooLong
+			}
+			title = &trimmed
+		}
+	}
+
+	skillIDs := []string{}
+	seen := make(map[string]bool)
+	for _, id := range req.SkillIDs {
+		if !seen[id] {
+			seen[id] = true
+			skillIDs = append(skillIDs, id)
+		}
+	}
+	if len(skillIDs) > maxShiftSkills {
+		return nil, nil, ErrTooManySkills
+	}
+
+	return title, skillIDs, nil
+}
+
+// setSkills records the skills the shift requires within tx
+func setSkills(tx *sql.Tx, shiftID string, skillIDs []string) error {
+	if len(skillIDs) == 0 {
+		return nil
+	}
+
+	// Matching on text skips malformed IDs so they count as unknown
+	result, err := tx.Exec(`
+		INSERT INTO shift_skills (shift_id, skill_id)
+		SELECT $1, id FROM skills WHERE id::text = ANY($2)
+	`, shiftID, pq.Array(skillIDs))
+	if err != nil {
+		return err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(inserted) != len(skillIDs) {
+		return ErrUnknownSkill
+	}
+	return nil
+}
+
+// DeleteShift removes a shift and its signups
+func (s *Service) DeleteShift(projectID, shiftID, tenantID string) error {
+	query := `
+		DELETE FROM project_shifts s
+		USING projects p
+		WHERE s.id = $1
+		  AND s.project_id = $2
+		  AND p.id = s.project_id
+		  AND ($3 = '' OR p.organization_id = NULLIF($3, '')::uuid)
+	`
+
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(query, shiftID, projectID, tenantID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrShiftNotFound
+	}
+
+	return nil
+}
+
+// SignUp books an enrolled volunteer onto a shift that has not started and
+// that their availability rules, if they have any, cover
+func (s *Service) SignUp(projectID, shiftID, volunteerID, tenantID string) (*models.Shift, error) {
+	var sh models.Shift
+	err := database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := signUp(tx, projectID, shiftID, volunteerID, tenantID); err != nil {
+			return err
+		}
+
+		err = scanShift(tx.QueryRow(shiftSelect+`WHERE s.id = $1`, shiftID), &sh)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sh, nil
+}
+
+// signUp checks and records a signup within tx. The shift row is locked so
+// capacity holds under concurrent signups, and the volunteer row so two
+// overlapping bookings cannot both pass the conflict check.
+func signUp(tx *sql.Tx, projectID, shiftID, volunteerID, tenantID string) error {
+	var startsAt, endsAt time.Time
+	var capacity int
+	err := tx.QueryRow(`
+		SELECT s.starts_at, s.ends_at, s.capacity
+		FROM project_shifts s
+		JOIN projects p ON p.id = s.project_id
+		WHERE s.id = $1
+		  AND s.project_id = $2
+		  AND ($3 = '' OR p.organization_id = NULLIF($3, '')::uuid)
+		FOR UPDATE OF s
+	`, shiftID, projectID, tenantID).Scan(&startsAt, &endsAt, &capacity)
+	if err == sql.ErrNoRows {
+		return ErrShiftNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !startsAt.After(time.Now()) {
+		return ErrShiftStarted
+	}
+
+	var enrolled bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM volunteer_enrollments
+			WHERE project_id = $1 AND volunteer_id = $2 AND status = 'enrolled'
+		)
+	`, projectID, volunteerID).Scan(&enrolled)
+	if err != nil {
+		return err
+	}
+	if !enrolled {
+		return ErrNotEnrolled
+	}
+
+	if _, err := tx.Exec(`SELECT 1 FROM users WHERE id = $1 FOR NO KEY UPDATE`, volunteerID); err != nil {
+		return err
+	}
+
+	var signedUp int
+	var alreadySignedUp, conflict bool
+	err = tx.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM shift_signups WHERE shift_id = $1),
+		       EXISTS (SELECT 1 FROM shift_signups WHERE shift_id = $1 AND volunteer_id = $2),
+		       EXISTS (
+		           SELECT 1
+		           FROM shift_signups ss
+		           JOIN project_shifts os ON os.id = ss.shift_id
+		           WHERE ss.volunteer_id = $2
+		             AND os.id <> $1
+		             AND os.starts_at < $4
+		             AND os.ends_at > $3
+		       )
+	`, shiftID, volunteerID, startsAt, endsAt).Scan(&signedUp, &alreadySignedUp, &conflict)
+	if err != nil {
+		return err
+	}
+	switch {
+	case alreadySignedUp:
+		return ErrAlreadySignedUp
+	case conflict:
+		return ErrShiftConflict
+	case signedUp >= capacity:
+		return ErrShiftFull
+	}
+
+	available, err := availability.IsAvailable(tx, volunteerID, startsAt, endsAt)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return ErrUnavailable
+	}
+
+	_, err = tx.Exec(`INSERT INTO shift_signups (shift_id, volunteer_id) VALUES ($1, $2)`, shiftID, volunteerID)
+	if isUniqueViolation(err) {
+		return ErrAlreadySignedUp
+	}
+	return err
+}
+
+// CancelSignup removes a volunteer from a shift
+func (s *Service) CancelSignup(projectID, shiftID, volunteerID, tenantID string) error {
+	query := `
+		DELETE FROM shift_signups ss
+		USING project_shifts s, projects p
+		WHERE ss.shift_id = $1
+		  AND ss.volunteer_id = $2
+		  AND s.id = ss.shift_id
+		  AND s.project_id = $3
+		  AND p.id = s.project_id
+		  AND ($4 = '' OR p.organization_id = NULLIF($4, '')::uuid)
+	`
+
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(query, shiftID, volunteerID, projectID, tenantID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrSignupNotFound
+	}
+
+	return nil
+}
+
+// GetRoster lists a project's shifts with the volunteers booked on each.
+// Shifts that have ended are left out unless includePast is set.
+func (s *Service) GetRoster(projectID, tenantID string, includePast bool) ([]models.ShiftRoster, error) {
+	shifts, err := s.GetProjectShifts(projectID, tenantID, includePast)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ss.shift_id, ss.volunteer_id, u.name, u.email, ss.created_at
+		FROM shift_signups ss
+		JOIN project_shifts s ON s.id = ss.shift_id
+		JOIN users u ON u.id = ss.volunteer_id
+		WHERE s.project_id = $1
+		  AND ($2 OR s.ends_at > NOW())
+		ORDER BY u.name, ss.volunteer_id
+	`
+
+	byShift := make(map[string][]models.ShiftSignup)
+	err = database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, projectID, includePast)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		byShift = make(map[string][]models.ShiftSignup)
+		for rows.Next() {
+			var su models.ShiftSignup
+			if err := rows.Scan(&su.ShiftID, &su.VolunteerID, &su.VolunteerName, &su.VolunteerEmail, &su.CreatedAt); err != nil {
+				return err
+			}
+			byShift[su.ShiftID] = append(byShift[su.ShiftID], su)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roster := make([]models.ShiftRoster, len(shifts))
+	for i, sh := range shifts {
+		volunteers := byShift[sh.ID]
+		if volunteers == nil {
+			volunteers = []models.ShiftSignup{}
+		}
+		roster[i] = models.ShiftRoster{Shift: sh, Volunteers: volunteers}
+	}
+
+	return roster, nil
+}
+
+// GetVolunteerShifts lists the shifts a volunteer is booked onto that have
+// not ended, limited to the tenant's projects when tenantID is set
+func (s *Service) GetVolunteerShifts(volunteerID, tenantID string) ([]models.VolunteerShift, error) {
+	query := `
+		SELECT s.id, s.project_id, s.title, s.starts_at, s.ends_at, s.capacity,
+		       (SELECT COUNT(*) FROM shift_signups c WHERE c.shift_id = s.id),
+		       ARRAY(SELECT sk.skill_id::text FROM shift_skills sk WHERE sk.shift_id = s.id ORDER BY sk.skill_id),
+		       s.created_by, s.created_at, p.name
+		FROM shift_signups ss
+		JOIN project_shifts s ON s.id = ss.shift_id
+		JOIN projects p ON p.id = s.project_id
+		WHERE ss.volunteer_id = $1
+		  AND s.ends_at > NOW()
+		  AND ($2 = '' OR p.organization_id = NULLIF($2, '')::uuid)
+		ORDER BY s.starts_at, s.id
+	`
+
+	var shifts []models.VolunteerShift
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, volunteerID, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		shifts = []models.VolunteerShift{}
+		for rows.Next() {
+			var vs models.VolunteerShift
+			err := rows.Scan(
+				&vs.ID,
+				&vs.ProjectID,
+				&vs.Title,
+				&vs.StartsAt,
+				&vs.EndsAt,
+				&vs.Capacity,
+				&vs.SignedUp,
+				pq.Array(&vs.SkillIDs),
+				&vs.CreatedBy,
+				&vs.CreatedAt,
+				&vs.ProjectName,
+			)
+			if err != nil {
+				return err
+			}
+			shifts = append(shifts, vs)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shifts, nil
+}
+
+func (s *Service) requireProjectInTenant(projectID, tenantID string) error {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM projects
+			WHERE id = $1
+			  AND ($2 = '' OR organization_id = NULLIF($2, '')::uuid)
+		)
+	`
+
+	var exists bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, projectID, tenantID).Scan(&exists)
+	})
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,99 @@
+package skills
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/tracing"
+)
+
+var (
+	ErrAliasRequired = errors.New("alias is required")
+	ErrAliasExists   = errors.New("alias is already a skill name or alias")
+)
+
+// AddAlias adds another name the skill is matched by. Aliases are unique
+// regardless of case and may not repeat a skill's name.
+func (s *Service) AddAlias(ctx context.Context, skillID, alias string) (*models.SkillAlias, error) {
+	ctx, span := tracing.Start(ctx, "skills.AddAlias")
+	defer span.End()
+
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		return nil, ErrAliasRequired
+	}
+
+	var a models.SkillAlias
+	err := database.WithWriteGuard(func() error {
+		return s.db.QueryRowContext(ctx, `
+			INSERT INTO skill_aliases (skill_id, alias)
+			SELECT id, $2 FROM skills
+			WHERE id = $1
+			  AND NOT EXISTS (SELECT 1 FROM skills WHERE LOWER(name) = LOWER($2))
+			ON CONFLICT ((LOWER(alias))) DO NOTHING
+			RETURNING id, skill_id, alias, created_at
+		`, skillID, alias).Scan(&a.ID, &a.SkillID, &a.Alias, &a.CreatedAt)
+	})
+	if err == sql.ErrNoRows {
+		var exists bool
+		err := database.WithReadRetry(func() error {
+			return s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM skills WHERE id = $1)`, skillID).Scan(&exists)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrSkillNotFound
+		}
+		return nil, ErrAliasExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// NameIndex maps every skill name and alias, lowercased, to its skill
+func (s *Service) NameIndex(ctx context.Context) (map[string]models.Skill, error) {
+	ctx, span := tracing.Start(ctx, "skills.NameIndex")
+	defer span.End()
+
+	query := `
+		SELECT LOWER(s.name), s.id, s.name, COALESCE(s.description, ''), COALESCE(s.category, ''), s.created_at
+		FROM skills s
+		UNION ALL
+		SELECT LOWER(a.alias), s.id, s.name, COALESCE(s.description, ''), COALESCE(s.category, ''), s.created_at
+		FROM skill_aliases a
+		JOIN skills s ON s.id = a.skill_id
+	`
+
+	var index map[string]models.Skill
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		index = make(map[string]models.Skill)
+		for rows.Next() {
+			var key string
+			var skill models.Skill
+			if err := rows.Scan(&key, &skill.ID, &skill.Name, &skill.Description, &skill.Category, &skill.CreatedAt); err != nil {
+				return err
+			}
+			index[key] = skill
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
@@ -1,17 +1,29 @@
 package skills
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 
+	"github.com/civic-weave/backend/internal/database"
 	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/pagination"
+	"github.com/civic-weave/backend/internal/tracing"
 )
 
 var (
-	ErrSkillNotFound = errors.New("skill not found")
-	ErrSkillExists   = errors.New("skill already exists")
+	ErrSkillNotFound    = errors.New("skill not found")
+	ErrSkillExists      = errors.New("skill already exists")
+	ErrInvalidMaxTravel = errors.New("maxTravelKm must be between 0 and 500")
+	ErrInvalidTimezone  = errors.New("timezone must be an IANA time zone such as America/Toronto")
+	ErrSkillNotClaimed  = errors.New("volunteer has not claimed this skill")
+	ErrNotEnrolled      = errors.New("volunteer is not enrolled in the project")
 )
 
+// MaxTravelKmLimit is the largest travel cap a volunteer can set; batch
+// matching never considers pairs further apart than this
+const MaxTravelKmLimit = 500
+
 type Service struct {
 	db *sql.DB
 }
@@ -20,10 +32,13 @@ func NewService(db *sql.DB) *Service {
 	return &Service{db: db}
 }
 
-func (s *Service) CreateSkill(name, description, category string) (*models.Skill, error) {
+func (s *Service) CreateSkill(ctx context.Context, name, description, category string) (*models.Skill, error) {
+	ctx, span := tracing.Start(ctx, "skills.CreateSkill")
+	defer span.End()
+
 	// Check if skill already exists
 	var existingID string
-	err := s.db.QueryRow("SELECT id FROM skills WHERE LOWER(name) = LOWER($1)", name).Scan(&existingID)
+	err := s.db.QueryRowContext(ctx, "SELECT id FROM skills WHERE LOWER(name) = LOWER($1)", name).Scan(&existingID)
 	if err == nil {
 		return nil, ErrSkillExists
 	}
@@ -39,13 +54,15 @@ func (s *Service) CreateSkill(name, description, category string) (*models.Skill
 	`
 
 	var skill models.Skill
-	err = s.db.QueryRow(query, name, description, category).Scan(
-		&skill.ID,
-		&skill.Name,
-		&skill.Description,
-		&skill.Category,
-		&skill.CreatedAt,
-	)
+	err = database.WithWriteGuard(func() error {
+		return s.db.QueryRowContext(ctx, query, name, description, category).Scan(
+			&skill.ID,
+			&skill.Name,
+			&skill.Description,
+			&skill.Category,
+			&skill.CreatedAt,
+		)
+	})
 
 	if err != nil {
 		return nil, err
@@ -54,71 +71,113 @@ func (s *Service) CreateSkill(name, description, category string) (*models.Skill
 	return &skill, nil
 }
 
-func (s *Service) SearchSkills(query string, limit int) ([]models.Skill, error) {
-	if limit == 0 {
-		limit = 10
+// Sorts are the fields skill lists can sort by. Searches rank by relevance
+// unless a sort is given; the full catalog is sorted by category and name.
+var (
+	Sorts = pagination.Sorts{
+		Fields:   sortFields,
+		Default:  "category,name",
+		Tiebreak: "id",
 	}
+	SearchSorts = pagination.Sorts{
+		Fields:   sortFields,
+		Tiebreak: "id",
+	}
+)
+
+var sortFields = map[string]string{
+	"name":      "name",
+	"category":  "category",
+	"createdAt": "created_at",
+}
+
+// SearchSkills returns a page of skills matching query, in one category
+// when category is set, along with how many match across all pages
+func (s *Service) SearchSkills(ctx context.Context, query, category string, page pagination.Params) ([]models.Skill, int, error) {
+	ctx, span := tracing.Start(ctx, "skills.SearchSkills")
+	defer span.End()
 
 	// Use PostgreSQL full-text search with ranking
 	// This is much faster and more flexible than LIKE queries
-	sqlQuery := `
-		SELECT
-			id,
-			name,
-			description,
-			category,
-			created_at,
-			ts_rank(search_vector, websearch_to_tsquery('english', $1)) as rank
-		FROM skills
-		WHERE search_vector @@ websearch_to_tsquery('english', $1)
-		   OR LOWER(name) LIKE LOWER($2)
-		ORDER BY
-			CASE WHEN LOWER(name) LIKE LOWER($3) THEN 0 ELSE 1 END,
-			rank DESC,
-			name
-		LIMIT $4
+	where := `
+		WHERE (search_vector @@ websearch_to_tsquery('english', $1)
+		   OR LOWER(name) LIKE LOWER($2))
+		  AND ($3 = '' OR category = $3)
 	`
-
 	// websearch_to_tsquery handles spaces and common operators automatically
 	searchPattern := "%" + query + "%"
-	exactPattern := query + "%"
-
-	rows, err := s.db.Query(sqlQuery, query, searchPattern, exactPattern, limit)
-	if err != nil {
-		return nil, err
+	args := []interface{}{query, searchPattern, category}
+
+	orderBy := page.OrderBy
+	if orderBy == "" {
+		// Prefix matches first, then by rank
+		args = append(args, query+"%")
+		orderBy = `
+			CASE WHEN LOWER(name) LIKE LOWER($4) THEN 0 ELSE 1 END,
+			ts_rank(search_vector, websearch_to_tsquery('english', $1)) DESC,
+			name, id`
 	}
-	defer rows.Close()
+	sqlQuery := `
+		SELECT id, name, description, category, created_at
+		FROM skills` + where + `
+		ORDER BY ` + orderBy + `
+		` + page.Window(len(args)+1)
+	args = append(args, page.Args()...)
 
 	var skills []models.Skill
-	for rows.Next() {
-		var skill models.Skill
-		var rank float64 // ignore rank in results, just use for sorting
-
-		err := rows.Scan(
-			&skill.ID,
-			&skill.Name,
-			&skill.Description,
-			&skill.Category,
-			&skill.CreatedAt,
-			&rank,
-		)
-		if err != nil {
-			return nil, err
+	var total int
+	err := database.WithReadRetry(func() error {
+		if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM skills`+where, query, searchPattern, category).Scan(&total); err != nil {
+			return err
 		}
-		skills = append(skills, skill)
+
+		var err error
+		skills, err = s.querySkills(ctx, sqlQuery, args...)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return skills, nil
+	return skills, total, nil
 }
 
-func (s *Service) GetAllSkills() ([]models.Skill, error) {
+// GetAllSkills returns a page of the skill catalog, in one category when
+// category is set, along with how many skills there are across all pages
+func (s *Service) GetAllSkills(ctx context.Context, category string, page pagination.Params) ([]models.Skill, int, error) {
+	ctx, span := tracing.Start(ctx, "skills.GetAllSkills")
+	defer span.End()
+
+	where := `
+		WHERE ($1 = '' OR category = $1)
+	`
 	query := `
 		SELECT id, name, description, category, created_at
-		FROM skills
-		ORDER BY category, name
-	`
+		FROM skills` + where + `
+		ORDER BY ` + page.OrderBy + `
+		` + page.Window(2)
 
-	rows, err := s.db.Query(query)
+	var skills []models.Skill
+	var total int
+	err := database.WithReadRetry(func() error {
+		if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM skills`+where, category).Scan(&total); err != nil {
+			return err
+		}
+
+		var err error
+		skills, err = s.querySkills(ctx, query, append([]interface{}{category}, page.Args()...)...)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return skills, total, nil
+}
+
+// querySkills runs a skill query and scans the rows
+func (s *Service) querySkills(ctx context.Context, query string, args ...interface{}) ([]models.Skill, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -139,52 +198,77 @@ func (s *Service) GetAllSkills() ([]models.Skill, error) {
 		}
 		skills = append(skills, skill)
 	}
-
-	return skills, nil
+	return skills, rows.Err()
 }
 
-func (s *Service) GetVolunteerSkills(volunteerID string) ([]models.VolunteerSkill, error) {
+func (s *Service) GetVolunteerSkills(ctx context.Context, volunteerID string) ([]models.VolunteerSkill, error) {
+	ctx, span := tracing.Start(ctx, "skills.GetVolunteerSkills")
+	defer span.End()
+
 	query := `
-		SELECT vs.volunteer_id, vs.skill_id, s.name, vs.claimed, vs.score, vs.created_at, vs.updated_at
+		SELECT vs.volunteer_id, vs.skill_id, s.name, vs.claimed, vs.score, vs.verified_by, vs.verified_at,
+		       vs.created_at, vs.updated_at
 		FROM volunteer_skills vs
 		JOIN skills s ON vs.skill_id = s.id
 		WHERE vs.volunteer_id = $1
 		ORDER BY s.name
 	`
 
-	rows, err := s.db.Query(query, volunteerID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
 	var volunteerSkills []models.VolunteerSkill
-	for rows.Next() {
-		var vs models.VolunteerSkill
-		err := rows.Scan(
-			&vs.VolunteerID,
-			&vs.SkillID,
-			&vs.SkillName,
-			&vs.Claimed,
-			&vs.Score,
-			&vs.CreatedAt,
-			&vs.UpdatedAt,
-		)
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, query, volunteerID)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		volunteerSkills = append(volunteerSkills, vs)
+		defer rows.Close()
+
+		volunteerSkills = nil
+		for rows.Next() {
+			var vs models.VolunteerSkill
+			err := rows.Scan(
+				&vs.VolunteerID,
+				&vs.SkillID,
+				&vs.SkillName,
+				&vs.Claimed,
+				&vs.Score,
+				&vs.VerifiedBy,
+				&vs.VerifiedAt,
+				&vs.CreatedAt,
+				&vs.UpdatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			volunteerSkills = append(volunteerSkills, vs)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return volunteerSkills, nil
 }
 
-func (s *Service) UpdateVolunteerSkills(volunteerID string, skills []struct {
+func (s *Service) UpdateVolunteerSkills(ctx context.Context, volunteerID string, skills []struct {
 	SkillID string
 	Claimed bool
 	Score   float64
 }) error {
-	tx, err := s.db.Begin()
+	ctx, span := tracing.Start(ctx, "skills.UpdateVolunteerSkills")
+	defer span.End()
+
+	return database.WithWriteGuard(func() error {
+		return s.updateVolunteerSkills(ctx, volunteerID, skills)
+	})
+}
+
+func (s *Service) updateVolunteerSkills(ctx context.Context, volunteerID string, skills []struct {
+	SkillID string
+	Claimed bool
+	Score   float64
+}) error {
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -206,7 +290,7 @@ func (s *Service) UpdateVolunteerSkills(volunteerID string, skills []struct {
 				updated_at = CURRENT_TIMESTAMP
 		`
 
-		_, err := tx.Exec(query, volunteerID, skill.SkillID, skill.Claimed, skill.Score)
+		_, err := tx.ExecContext(ctx, query, volunteerID, skill.SkillID, skill.Claimed, skill.Score)
 		if err != nil {
 			return err
 		}
@@ -215,38 +299,123 @@ func (s *Service) UpdateVolunteerSkills(volunteerID string, skills []struct {
 	return tx.Commit()
 }
 
-func (s *Service) UpdateVolunteerLocation(volunteerID string, lat, lon float64, locationName string) error {
-	// Check if PostGIS is available
-	var hasPostGIS bool
-	err := s.db.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'postgis')").Scan(&hasPostGIS)
+// VerifySkill records that a coordinator of a project the volunteer is
+// enrolled in vouches for one of the volunteer's claimed skills
+func (s *Service) VerifySkill(ctx context.Context, projectID, volunteerID, skillID, verifierID, tenantID string) error {
+	ctx, span := tracing.Start(ctx, "skills.VerifySkill")
+	defer span.End()
+
+	var enrolled bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRowContext(ctx, `
+			SELECT EXISTS (
+				SELECT 1
+				FROM volunteer_enrollments ve
+				JOIN projects p ON p.id = ve.project_id
+				WHERE ve.project_id = $1
+				  AND ve.volunteer_id = $2
+				  AND ve.status IN ('enrolled', 'completed')
+				  AND ($3 = '' OR p.organization_id = NULLIF($3, '')::uuid)
+			)
+		`, projectID, volunteerID, tenantID).Scan(&enrolled)
+	})
 	if err != nil {
 		return err
 	}
+	if !enrolled {
+		return ErrNotEnrolled
+	}
 
-	var query string
-	if hasPostGIS {
-		// Update both lat/lon and PostGIS geography point if available
-		query = `
-			UPDATE users
-			SET
-				latitude = $1,
-				longitude = $2,
-				location_name = $3,
-				location_point = ST_SetSRID(ST_MakePoint($2, $1), 4326)::geography
-			WHERE id = $4
-		`
-	} else {
-		// Update just lat/lon if PostGIS not available
-		query = `
-			UPDATE users
-			SET
-				latitude = $1,
-				longitude = $2,
-				location_name = $3
-			WHERE id = $4
-		`
+	var result sql.Result
+	err = database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.ExecContext(ctx, `
+			UPDATE volunteer_skills
+			SET verified_by = $3, verified_at = CURRENT_TIMESTAMP
+			WHERE volunteer_id = $1 AND skill_id = $2 AND claimed
+		`, volunteerID, skillID, verifierID)
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
-	_, err = s.db.Exec(query, lat, lon, locationName, volunteerID)
-	return err
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrSkillNotClaimed
+	}
+
+	return nil
+}
+
+// UpdateVolunteerLocation sets the volunteer's primary saved location
+// (creating a "Home" location if they have none) and, when given, their
+// travel cap (0 clears it) and time zone. users.latitude/longitude follow the
+// primary location via trigger.
+func (s *Service) UpdateVolunteerLocation(ctx context.Context, volunteerID string, req models.UpdateLocationRequest) error {
+	ctx, span := tracing.Start(ctx, "skills.UpdateVolunteerLocation")
+	defer span.End()
+
+	maxTravelKm := req.MaxTravelKm
+	if maxTravelKm != nil && (*maxTravelKm < 0 || *maxTravelKm > MaxTravelKmLimit) {
+		return ErrInvalidMaxTravel
+	}
+	if req.Timezone != nil && !models.ValidTimezone(*req.Timezone) {
+		return ErrInvalidTimezone
+	}
+
+	return database.WithWriteGuard(func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		result, err := tx.ExecContext(ctx, `
+			UPDATE volunteer_locations
+			SET latitude = $2, longitude = $3, location_name = $4, updated_at = CURRENT_TIMESTAMP
+			WHERE volunteer_id = $1 AND is_primary
+		`, volunteerID, req.Latitude, req.Longitude, req.LocationName)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO volunteer_locations (volunteer_id, label, latitude, longitude, location_name, is_primary)
+				VALUES ($1, 'Home', $2, $3, $4, TRUE)
+				ON CONFLICT (volunteer_id, label) DO UPDATE SET
+					latitude = EXCLUDED.latitude,
+					longitude = EXCLUDED.longitude,
+					location_name = EXCLUDED.location_name,
+					is_primary = TRUE,
+					updated_at = CURRENT_TIMESTAMP
+			`, volunteerID, req.Latitude, req.Longitude, req.LocationName)
+			if err != nil {
+				return err
+			}
+		}
+
+		if maxTravelKm != nil {
+			_, err := tx.ExecContext(ctx, `UPDATE users SET max_travel_km = NULLIF($2::float, 0) WHERE id = $1`, volunteerID, *maxTravelKm)
+			if err != nil {
+				return err
+			}
+		}
+
+		if req.Timezone != nil {
+			_, err := tx.ExecContext(ctx, `UPDATE users SET timezone = $2 WHERE id = $1`, volunteerID, *req.Timezone)
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Commit()
+	})
 }
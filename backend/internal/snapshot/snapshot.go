@@ -0,0 +1,491 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Format versions the archive layout
+const Format = 1
+
+// manifestName is the archive's first entry; each table follows as
+// tables/<name>.jsonl, one JSON object per row, parents before children
+const manifestName = "manifest.json"
+
+// restoreBatch is how many rows are inserted per statement on restore
+const restoreBatch = 500
+
+// skippedTables hold runtime state or the schema's migration history rather
+// than demo data; they are neither snapshotted nor cleared on restore
+var skippedTables = map[string]bool{
+	"jobs":              true,
+	"scheduled_runs":    true,
+	"schema_migrations": true,
+}
+
+var (
+	ErrInvalidArchive = errors.New("not a snapshot archive")
+	ErrFormat         = fmt.Errorf("snapshot format is not %d", Format)
+)
+
+// SchemaError reports a snapshot that does not fit the database's schema,
+// e.g. one taken before a table was dropped
+type SchemaError struct {
+	Reason string
+}
+
+func (e *SchemaError) Error() string {
+	return "snapshot does not match the database schema: " + e.Reason
+}
+
+// Manifest describes an archive
+type Manifest struct {
+	Format    int       `json:"format"`
+	CreatedAt time.Time `json:"createdAt"`
+	Tables    []Table   `json:"tables"`
+}
+
+// Table is a table in an archive
+type Table struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Rows    int64    `json:"rows"`
+}
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// Export writes every table to w as a gzipped tar archive, read in one
+// consistent snapshot of the database. Uploaded files are not included.
+func (s *Service) Export(ctx context.Context, w io.Writer) (*Manifest, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tables, err := loadTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{Format: Format, CreatedAt: time.Now().UTC(), Tables: tables}
+	for i := range manifest.Tables {
+		t := &manifest.Tables[i]
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+pq.QuoteIdentifier(t.Name)).Scan(&t.Rows); err != nil {
+			return nil, fmt.Errorf("count %s: %w", t.Name, err)
+		}
+	}
+
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeEntry(archive, manifestName, data, manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	for _, t := range manifest.Tables {
+		var rows bytes.Buffer
+		if err := exportTable(ctx, tx, t, &rows); err != nil {
+			return nil, fmt.Errorf("export %s: %w", t.Name, err)
+		}
+		if err := writeEntry(archive, "tables/"+t.Name+".jsonl", rows.Bytes(), manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func writeEntry(archive *tar.Writer, name string, data []byte, modTime time.Time) error {
+	err := archive.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = archive.Write(data)
+	return err
+}
+
+func exportTable(ctx context.Context, tx *sql.Tx, t Table, w io.Writer) error {
+	quoted := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		quoted[i] = pq.QuoteIdentifier(column)
+	}
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(
+		"SELECT row_to_json(t)::text FROM (SELECT %s FROM %s) t",
+		strings.Join(quoted, ", "), pq.QuoteIdentifier(t.Name),
+	))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, row+"\n"); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Restore replaces the contents of every table with an archive written by
+// Export, in one transaction: on any error the database is left as it was.
+// Tables the archive lacks, e.g. ones added since it was taken, are left
+// empty, and columns it lacks take their defaults.
+func (s *Service) Restore(ctx context.Context, r io.Reader) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+	archive := tar.NewReader(gz)
+
+	header, err := archive.Next()
+	if err != nil || header.Name != manifestName {
+		return nil, ErrInvalidArchive
+	}
+	var manifest Manifest
+	if err := json.NewDecoder(archive).Decode(&manifest); err != nil {
+		return nil, ErrInvalidArchive
+	}
+	if manifest.Format != Format {
+		return nil, ErrFormat
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current, err := loadTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]map[string]bool, len(current))
+	names := make([]string, 0, len(current))
+	for _, t := range current {
+		columns[t.Name] = make(map[string]bool, len(t.Columns))
+		for _, column := range t.Columns {
+			columns[t.Name][column] = true
+		}
+		names = append(names, pq.QuoteIdentifier(t.Name))
+	}
+	archived := make(map[string]Table, len(manifest.Tables))
+	for _, t := range manifest.Tables {
+		if columns[t.Name] == nil {
+			return nil, &SchemaError{Reason: fmt.Sprintf("table %s does not exist", t.Name)}
+		}
+		for _, column := range t.Columns {
+			if !columns[t.Name][column] {
+				return nil, &SchemaError{Reason: fmt.Sprintf("column %s.%s does not exist", t.Name, column)}
+			}
+		}
+		archived[t.Name] = t
+	}
+
+	if len(names) > 0 {
+		if _, err := tx.ExecContext(ctx, "TRUNCATE "+strings.Join(names, ", ")+" RESTART IDENTITY CASCADE"); err != nil {
+			return nil, err
+		}
+	}
+
+	restored := make(map[string]bool, len(archived))
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidArchive
+		}
+		name, ok := strings.CutPrefix(header.Name, "tables/")
+		name, jsonl := strings.CutSuffix(name, ".jsonl")
+		t, known := archived[name]
+		if !ok || !jsonl || !known || restored[name] {
+			return nil, ErrInvalidArchive
+		}
+		restored[name] = true
+		if err := restoreTable(ctx, tx, t, archive); err != nil {
+			return nil, fmt.Errorf("restore %s: %w", name, err)
+		}
+	}
+	if len(restored) != len(archived) {
+		return nil, ErrInvalidArchive
+	}
+
+	if err := resetSequences(ctx, tx, current); err != nil {
+		return nil, err
+	}
+	if err := refreshMaterializedViews(ctx, tx); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// restoreTable inserts the rows of a table's entry in batches, letting
+// json_populate_recordset convert each column to its type
+func restoreTable(ctx context.Context, tx *sql.Tx, t Table, r io.Reader) error {
+	quoted := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		quoted[i] = pq.QuoteIdentifier(column)
+	}
+	list := strings.Join(quoted, ", ")
+	query := fmt.Sprintf(
+		"INSERT INTO %[1]s (%[2]s) SELECT %[2]s FROM json_populate_recordset(NULL::%[1]s, $1::json)",
+		pq.QuoteIdentifier(t.Name), list,
+	)
+
+	var batch bytes.Buffer
+	count := 0
+	flush := func() error {
+		if count == 0 {
+			return nil
+		}
+		batch.WriteByte(']')
+		_, err := tx.ExecContext(ctx, query, batch.String())
+		batch.Reset()
+		count = 0
+		return err
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			return ErrInvalidArchive
+		}
+		if count == 0 {
+			batch.WriteByte('[')
+		} else {
+			batch.WriteByte(',')
+		}
+		batch.Write(line)
+		count++
+		if count == restoreBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// loadTables lists the tables to snapshot with their writable columns,
+// parents before the tables referencing them. Tables belonging to
+// extensions, such as PostGIS's spatial_ref_sys, are left out.
+func loadTables(ctx context.Context, tx *sql.Tx) ([]Table, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT c.relname, a.attname
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped AND a.attgenerated = ''
+		WHERE n.nspname = current_schema()
+		  AND c.relkind IN ('r', 'p')
+		  AND NOT c.relispartition
+		  AND NOT EXISTS (SELECT 1 FROM pg_depend d WHERE d.objid = c.oid AND d.deptype = 'e')
+		ORDER BY c.relname, a.attnum
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []Table
+	index := map[string]int{}
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return nil, err
+		}
+		if skippedTables[table] {
+			continue
+		}
+		i, ok := index[table]
+		if !ok {
+			i = len(tables)
+			index[table] = i
+			tables = append(tables, Table{Name: table})
+		}
+		tables[i].Columns = append(tables[i].Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	parents, err := loadReferences(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	return sortByReferences(tables, parents), nil
+}
+
+// loadReferences maps each table to the other tables its foreign keys
+// reference
+func loadReferences(ctx context.Context, tx *sql.Tx) (map[string][]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT DISTINCT child.relname, parent.relname
+		FROM pg_constraint con
+		JOIN pg_class child ON child.oid = con.conrelid
+		JOIN pg_class parent ON parent.oid = con.confrelid
+		JOIN pg_namespace n ON n.oid = child.relnamespace
+		WHERE con.contype = 'f'
+		  AND n.nspname = current_schema()
+		  AND child.oid <> parent.oid
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	parents := map[string][]string{}
+	for rows.Next() {
+		var child, parent string
+		if err := rows.Scan(&child, &parent); err != nil {
+			return nil, err
+		}
+		parents[child] = append(parents[child], parent)
+	}
+	return parents, rows.Err()
+}
+
+// sortByReferences orders tables so each comes after the tables it
+// references, alphabetically otherwise. Tables in a reference cycle keep
+// alphabetical order.
+func sortByReferences(tables []Table, parents map[string][]string) []Table {
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+	present := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		present[t.Name] = true
+	}
+
+	sorted := make([]Table, 0, len(tables))
+	placed := make(map[string]bool, len(tables))
+	for len(sorted) < len(tables) {
+		progress := false
+		for _, t := range tables {
+			if placed[t.Name] {
+				continue
+			}
+			ready := true
+			for _, parent := range parents[t.Name] {
+				if present[parent] && !placed[parent] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				sorted = append(sorted, t)
+				placed[t.Name] = true
+				progress = true
+			}
+		}
+		if !progress {
+			for _, t := range tables {
+				if !placed[t.Name] {
+					sorted = append(sorted, t)
+					placed[t.Name] = true
+				}
+			}
+		}
+	}
+	return sorted
+}
+
+// resetSequences moves each serial column's sequence past the restored
+// rows, so new rows don't collide with them
+func resetSequences(ctx context.Context, tx *sql.Tx, tables []Table) error {
+	for _, t := range tables {
+		for _, column := range t.Columns {
+			var sequence sql.NullString
+			err := tx.QueryRowContext(ctx, `SELECT pg_get_serial_sequence($1, $2)`, pq.QuoteIdentifier(t.Name), column).Scan(&sequence)
+			if err != nil {
+				return err
+			}
+			if !sequence.Valid {
+				continue
+			}
+			_, err = tx.ExecContext(ctx, fmt.Sprintf(
+				"SELECT setval($1, COALESCE((SELECT MAX(%s) FROM %s), 0) + 1, false)",
+				pq.QuoteIdentifier(column), pq.QuoteIdentifier(t.Name),
+			), sequence.String)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// refreshMaterializedViews rebuilds views computed from the restored
+// tables, such as volunteer_skill_vectors
+func refreshMaterializedViews(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `SELECT matviewname FROM pg_matviews WHERE schemaname = current_schema() ORDER BY matviewname`)
+	if err != nil {
+		return err
+	}
+	var views []string
+	for rows.Next() {
+		var view string
+		if err := rows.Scan(&view); err != nil {
+			rows.Close()
+			return err
+		}
+		views = append(views, view)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, view := range views {
+		if _, err := tx.ExecContext(ctx, "REFRESH MATERIALIZED VIEW "+pq.QuoteIdentifier(view)); err != nil {
+			return fmt.Errorf("refresh %s: %w", view, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,464 @@
+package teams
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/events"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrProjectNotFound  = errors.New("project not found")
+	ErrTeamNotFound     = errors.New("team not found")
+	ErrTeamNameTaken    = errors.New("team name already used in this project")
+	ErrNotEnrolled      = errors.New("volunteer is not enrolled in the project")
+	ErrMemberNotFound   = errors.New("volunteer is not on this team")
+	ErrNoTeamMembers    = errors.New("team has no enrolled members")
+	ErrEmptyTeamMessage = errors.New("subject and body are required")
+	ErrNameRequired     = errors.New("team name is required")
+	ErrSubjectTooLong   = errors.New("subject must be at most 200 characters")
+)
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+const teamSelect = `
+	SELECT t.id, t.project_id, t.name, t.description, t.lead_id, lead.name,
+	       (SELECT COUNT(*) FROM project_team_members tm WHERE tm.team_id = t.id),
+	       t.created_at, t.updated_at
+	FROM project_teams t
+	JOIN projects p ON p.id = t.project_id
+	LEFT JOIN users lead ON lead.id = t.lead_id
+`
+
+func scanTeam(scanner interface{ Scan(...interface{}) error }, t *models.Team) error {
+	return scanner.Scan(
+		&t.ID,
+		&t.ProjectID,
+		&t.Name,
+		&t.Description,
+		&t.LeadID,
+		&t.LeadName,
+		&t.MemberCount,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+}
+
+// GetProjectTeams lists a project's teams; projects outside the tenant are reported as ErrProjectNotFound
+func (s *Service) GetProjectTeams(projectID, tenantID string) ([]models.Team, error) {
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	query := teamSelect + `
+		WHERE t.project_id = $1
+		ORDER BY t.name
+	`
+
+	var teams []models.Team
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, projectID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		teams = nil
+		for rows.Next() {
+			var t models.Team
+			if err := scanTeam(rows, &t); err != nil {
+				return err
+			}
+			teams = append(teams, t)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+// GetTeam returns a team; teams of projects outside the tenant are reported as ErrTeamNotFound
+func (s *Service) GetTeam(teamID, tenantID string) (*models.Team, error) {
+	query := teamSelect + `
+		WHERE t.id = $1
+		  AND ($2 = '' OR p.organization_id = NULLIF($2, '')::uuid)
+	`
+
+	var t models.Team
+	err := database.WithReadRetry(func() error {
+		return scanTeam(s.db.QueryRow(query, teamID, tenantID), &t)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// GetTeamMembers lists the volunteers assigned to a team
+func (s *Service) GetTeamMembers(teamID string) ([]models.TeamMember, error) {
+	query := `
+		SELECT tm.team_id, tm.volunteer_id, u.name, u.email, tm.assigned_by, tm.assigned_at
+		FROM project_team_members tm
+		JOIN users u ON u.id = tm.volunteer_id
+		WHERE tm.team_id = $1
+		ORDER BY u.name
+	`
+
+	var members []models.TeamMember
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, teamID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		members = nil
+		for rows.Next() {
+			var m models.TeamMember
+			err := rows.Scan(
+				&m.TeamID,
+				&m.VolunteerID,
+				&m.VolunteerName,
+				&m.VolunteerEmail,
+				&m.AssignedBy,
+				&m.AssignedAt,
+			)
+			if err != nil {
+				return err
+			}
+			members = append(members, m)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// CreateTeam adds a team to a project. The lead, when given, must be enrolled in the project.
+func (s *Service) CreateTeam(projectID, tenantID string, req models.CreateTeamRequest) (*models.Team, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+	if req.LeadID != nil {
+		if err := s.requireEnrolled(*req.LeadID, projectID); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		INSERT INTO project_teams (project_id, name, description, lead_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	var teamID string
+	err := database.WithWriteGuard(func() error {
+		return s.db.QueryRow(query, projectID, name, req.Description, req.LeadID).Scan(&teamID)
+	})
+	if isUniqueViolation(err) {
+		return nil, ErrTeamNameTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetTeam(teamID, "")
+}
+
+// SetTeamLead sets or, when leadID is nil, clears a team's lead
+func (s *Service) SetTeamLead(team *models.Team, leadID *string) error {
+	if leadID != nil {
+		if err := s.requireEnrolled(*leadID, team.ProjectID); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE project_teams
+		SET lead_id = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+
+	return database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(query, team.ID, leadID)
+		return err
+	})
+}
+
+// AssignMember puts an enrolled volunteer on a team, moving them off any
+// other team in the same project
+func (s *Service) AssignMember(team *models.Team, volunteerID, assignedBy string) error {
+	if err := s.requireEnrolled(volunteerID, team.ProjectID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO project_team_members (team_id, project_id, volunteer_id, assigned_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (project_id, volunteer_id)
+		DO UPDATE SET
+			team_id = EXCLUDED.team_id,
+			assigned_by = EXCLUDED.assigned_by,
+			assigned_at = CURRENT_TIMESTAMP
+	`
+
+	return database.WithWriteGuard(func() error {
+		_, err := s.db.Exec(query, team.ID, team.ProjectID, volunteerID, assignedBy)
+		return err
+	})
+}
+
+// RemoveMember takes a volunteer off a team
+func (s *Service) RemoveMember(teamID, volunteerID string) error {
+	query := `DELETE FROM project_team_members WHERE team_id = $1 AND volunteer_id = $2`
+
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(query, teamID, volunteerID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// CreateBroadcast records a message to the team, publishes it to the
+// members and returns it with the members to deliver it to. Only members
+// still enrolled in the project receive it.
+func (s *Service) CreateBroadcast(ctx context.Context, team *models.Team, senderID string, req models.BroadcastTeamMessageRequest) (*models.TeamMessage, []models.TeamMember, error) {
+	subject := strings.TrimSpace(req.Subject)
+	body := strings.TrimSpace(req.Body)
+	if subject == "" || body == "" {
+		return nil, nil, ErrEmptyTeamMessage
+	}
+	if len(subject) > 200 {
+		return nil, nil, ErrSubjectTooLong
+	}
+
+	recipientsQuery := `
+		SELECT tm.team_id, tm.volunteer_id, u.name, u.email, u.locale, tm.assigned_by, tm.assigned_at
+		FROM project_team_members tm
+		JOIN users u ON u.id = tm.volunteer_id
+		JOIN volunteer_enrollments ve ON ve.volunteer_id = tm.volunteer_id AND ve.project_id = tm.project_id
+		WHERE tm.team_id = $1
+		  AND ve.status = 'enrolled'
+		  AND tm.volunteer_id <> $2
+	`
+
+	var recipients []models.TeamMember
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(recipientsQuery, team.ID, senderID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		recipients = nil
+		for rows.Next() {
+			var m models.TeamMember
+			if err := rows.Scan(&m.TeamID, &m.VolunteerID, &m.VolunteerName, &m.VolunteerEmail, &m.VolunteerLocale, &m.AssignedBy, &m.AssignedAt); err != nil {
+				return err
+			}
+			recipients = append(recipients, m)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(recipients) == 0 {
+		return nil, nil, ErrNoTeamMembers
+	}
+
+	query := `
+		WITH inserted AS (
+			INSERT INTO project_team_messages (team_id, sender_id, subject, body, recipient_count)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, team_id, sender_id, subject, body, recipient_count, created_at
+		)
+		SELECT i.id, i.team_id, i.sender_id, u.name, i.subject, i.body, i.recipient_count, i.created_at
+		FROM inserted i
+		JOIN users u ON u.id = i.sender_id
+	`
+
+	userIDs := make([]string, len(recipients))
+	for i, m := range recipients {
+		userIDs[i] = m.VolunteerID
+	}
+
+	var msg models.TeamMessage
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		err = tx.QueryRowContext(ctx, query, team.ID, senderID, subject, body, len(recipients)).Scan(
+			&msg.ID,
+			&msg.TeamID,
+			&msg.SenderID,
+			&msg.SenderName,
+			&msg.Subject,
+			&msg.Body,
+			&msg.RecipientCount,
+			&msg.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = events.Publish(ctx, tx, events.TypeMessageCreated, events.MessageCreated{
+			MessageID:  msg.ID,
+			TeamID:     msg.TeamID,
+			SenderName: msg.SenderName,
+			Subject:    msg.Subject,
+		}, userIDs)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &msg, recipients, nil
+}
+
+// GetTeamMessages lists a team's broadcasts, newest first, leaving out
+// messages hidden by a moderator
+func (s *Service) GetTeamMessages(teamID string) ([]models.TeamMessage, error) {
+	query := `
+		SELECT m.id, m.team_id, m.sender_id, u.name, m.subject, m.body, m.recipient_count, m.created_at
+		FROM project_team_messages m
+		JOIN users u ON u.id = m.sender_id
+		WHERE m.team_id = $1
+		  AND m.hidden_at IS NULL
+		ORDER BY m.created_at DESC
+	`
+
+	var messages []models.TeamMessage
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, teamID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		messages = nil
+		for rows.Next() {
+			var m models.TeamMessage
+			err := rows.Scan(
+				&m.ID,
+				&m.TeamID,
+				&m.SenderID,
+				&m.SenderName,
+				&m.Subject,
+				&m.Body,
+				&m.RecipientCount,
+				&m.CreatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, m)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// requireProjectInTenant reports projects that don't exist or sit outside the tenant as ErrProjectNotFound
+func (s *Service) requireProjectInTenant(projectID, tenantID string) error {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM projects
+			WHERE id = $1
+			  AND ($2 = '' OR organization_id = NULLIF($2, '')::uuid)
+		)
+	`
+
+	var exists bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, projectID, tenantID).Scan(&exists)
+	})
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+// requireEnrolled checks that the volunteer's enrollment in the project is active
+func (s *Service) requireEnrolled(volunteerID, projectID string) error {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM volunteer_enrollments
+			WHERE volunteer_id = $1
+			  AND project_id = $2
+			  AND status = 'enrolled'
+		)
+	`
+
+	var enrolled bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, volunteerID, projectID).Scan(&enrolled)
+	})
+	if err != nil {
+		return err
+	}
+	if !enrolled {
+		return ErrNotEnrolled
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
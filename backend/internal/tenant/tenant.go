@@ -0,0 +1,228 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/civic-weave/backend/internal/apierror"
+	"github.com/gorilla/mux"
+)
+
+// Header lets API clients select a tenant by organization ID or slug
+const Header = "X-Tenant"
+
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+type contextKey struct{}
+
+// WithTenant returns a copy of ctx scoped to the organization
+func WithTenant(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, orgID)
+}
+
+// FromContext returns the organization ID the request is scoped to, or ""
+// when the request is not scoped (single-tenant deployments, platform admins)
+func FromContext(ctx context.Context) string {
+	orgID, _ := ctx.Value(contextKey{}).(string)
+	return orgID
+}
+
+// FromRequest is shorthand for FromContext(r.Context())
+func FromRequest(r *http.Request) string {
+	return FromContext(r.Context())
+}
+
+// Source extracts a tenant key (organization ID or slug) from a request,
+// returning "" when the request doesn't name one
+type Source func(r *http.Request) string
+
+// Lookup maps a tenant key to an organization ID, returning ErrUnknownTenant
+// when no organization matches
+type Lookup func(key string) (string, error)
+
+// Caller is who a request is made by, as far as tenants are concerned
+type Caller struct {
+	// Anonymous callers haven't signed in. They only reach public routes, and
+	// may name any tenant, e.g. to register through it.
+	Anonymous bool
+	// Unrestricted callers, such as platform admins, may name any tenant or
+	// none
+	Unrestricted bool
+	// Memberships are the IDs of the organizations the caller is an active
+	// member of, the only tenants they may be scoped to
+	Memberships []string
+}
+
+// Identify tells the resolver who a request is made by
+type Identify func(r *http.Request) (Caller, error)
+
+// Resolver scopes requests to a tenant using the first source that names one
+type Resolver struct {
+	lookup   Lookup
+	identify Identify
+	sources  []Source
+
+	mu    sync.RWMutex
+	cache map[string]string
+}
+
+// NewResolver builds a resolver reading the X-Tenant header and, when
+// baseDomain is set, the subdomain of the Host header. Signed-in callers
+// may only name tenants they are members of; those naming none are scoped
+// to their organization when they belong to exactly one, and otherwise
+// rejected outside exempt routes unless they are unrestricted.
+func NewResolver(lookup Lookup, identify Identify, baseDomain string) *Resolver {
+	res := &Resolver{
+		lookup:   lookup,
+		identify: identify,
+		cache:    make(map[string]string),
+	}
+	res.AddSource(HeaderSource)
+	if baseDomain != "" {
+		res.AddSource(SubdomainSource(baseDomain))
+	}
+	return res
+}
+
+// AddSource appends a source consulted after the existing ones
+func (res *Resolver) AddSource(source Source) {
+	res.sources = append(res.sources, source)
+}
+
+// HeaderSource reads the X-Tenant header
+func HeaderSource(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get(Header))
+}
+
+// SubdomainSource reads the leftmost label of hosts under baseDomain,
+// e.g. "cityhall" for cityhall.civicweave.org
+func SubdomainSource(baseDomain string) Source {
+	suffix := "." + strings.ToLower(strings.TrimPrefix(baseDomain, "."))
+	return func(r *http.Request) string {
+		host := strings.ToLower(r.Host)
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if !strings.HasSuffix(host, suffix) {
+			return ""
+		}
+		sub := strings.TrimSuffix(host, suffix)
+		if sub == "" || sub == "www" || strings.Contains(sub, ".") {
+			return ""
+		}
+		return sub
+	}
+}
+
+// exemptPrefixes never require a tenant
+var exemptPrefixes = []string{
+	"/api/health",
+	"/api/auth/",
+}
+
+// exemptRoutes never require a tenant either, keyed by method and route
+// template: they are how users who belong to no organization yet get one
+var exemptRoutes = map[string]bool{
+	"POST /api/organizations":                     true,
+	"POST /api/organizations/{id}/invites/accept": true,
+	"POST /api/invitations/accept":                true,
+}
+
+// Middleware resolves the tenant and stores it in the request context.
+// Requests already scoped by an earlier middleware (e.g. an API key) keep
+// their tenant. It must run after auth.Middleware, so the caller is known.
+func (res *Resolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if FromContext(r.Context()) != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		caller, err := res.identify(r)
+		if err != nil {
+			slog.Error("Tenant caller error", "error", err)
+			apierror.Write(w, http.StatusInternalServerError, "Failed to resolve tenant")
+			return
+		}
+
+		var key string
+		for _, source := range res.sources {
+			if key = source(r); key != "" {
+				break
+			}
+		}
+
+		if key == "" {
+			if !caller.Anonymous && !caller.Unrestricted && len(caller.Memberships) == 1 {
+				next.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), caller.Memberships[0])))
+				return
+			}
+			if !caller.Anonymous && !caller.Unrestricted && !isExempt(r) {
+				apierror.Write(w, http.StatusBadRequest, "Tenant required (set the X-Tenant header)")
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		orgID, err := res.resolve(key)
+		if err == ErrUnknownTenant {
+			apierror.Write(w, http.StatusNotFound, "Unknown tenant")
+			return
+		}
+		if err != nil {
+			slog.Error("Tenant resolution error", "key", key, "error", err)
+			apierror.Write(w, http.StatusInternalServerError, "Failed to resolve tenant")
+			return
+		}
+
+		if !caller.Anonymous && !caller.Unrestricted && !slices.Contains(caller.Memberships, orgID) {
+			apierror.Write(w, http.StatusForbidden, "You are not a member of this tenant")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), orgID)))
+	})
+}
+
+func (res *Resolver) resolve(key string) (string, error) {
+	key = strings.ToLower(key)
+
+	res.mu.RLock()
+	orgID, ok := res.cache[key]
+	res.mu.RUnlock()
+	if ok {
+		return orgID, nil
+	}
+
+	orgID, err := res.lookup(key)
+	if err != nil {
+		return "", err
+	}
+
+	res.mu.Lock()
+	res.cache[key] = orgID
+	res.mu.Unlock()
+
+	return orgID, nil
+}
+
+func isExempt(r *http.Request) bool {
+	for _, prefix := range exemptPrefixes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return exemptRoutes[r.Method+" "+template]
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package tenant
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolverMiddleware(t *testing.T) {
+	lookup := func(key string) (string, error) {
+		switch key {
+		case "cityhall", "org-1":
+			return "org-1", nil
+		case "parks", "org-2":
+			return "org-2", nil
+		}
+		return "", ErrUnknownTenant
+	}
+
+	tests := []struct {
+		name       string
+		caller     Caller
+		header     string
+		path       string
+		wantStatus int
+		wantTenant string
+	}{
+		{"member names own tenant", Caller{Memberships: []string{"org-1"}}, "cityhall", "/api/projects", http.StatusOK, "org-1"},
+		{"member names other tenant", Caller{Memberships: []string{"org-1"}}, "parks", "/api/projects", http.StatusForbidden, ""},
+		{"non-member names tenant", Caller{}, "org-2", "/api/projects", http.StatusForbidden, ""},
+		{"single membership scopes unnamed request", Caller{Memberships: []string{"org-2"}}, "", "/api/projects", http.StatusOK, "org-2"},
+		{"several memberships and no tenant", Caller{Memberships: []string{"org-1", "org-2"}}, "", "/api/projects", http.StatusBadRequest, ""},
+		{"no memberships and no tenant", Caller{}, "", "/api/projects", http.StatusBadRequest, ""},
+		{"no tenant on exempt route", Caller{}, "", "/api/auth/logout", http.StatusOK, ""},
+		{"admin names any tenant", Caller{Unrestricted: true}, "parks", "/api/projects", http.StatusOK, "org-2"},
+		{"admin names no tenant", Caller{Unrestricted: true}, "", "/api/projects", http.StatusOK, ""},
+		{"anonymous names tenant", Caller{Anonymous: true}, "cityhall", "/api/auth/register", http.StatusOK, "org-1"},
+		{"anonymous names no tenant", Caller{Anonymous: true}, "", "/api/docs", http.StatusOK, ""},
+		{"unknown tenant", Caller{Memberships: []string{"org-1"}}, "nowhere", "/api/projects", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identify := func(*http.Request) (Caller, error) { return tt.caller, nil }
+			res := NewResolver(lookup, identify, "")
+
+			var gotTenant string
+			handler := res.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotTenant = FromRequest(r)
+			}))
+
+			r := httptest.NewRequest("GET", tt.path, nil)
+			if tt.header != "" {
+				r.Header.Set(Header, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if gotTenant != tt.wantTenant {
+				t.Errorf("tenant = %q, want %q", gotTenant, tt.wantTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Package validate checks decoded request bodies against the rules in their
+// fields' validate tags, so malformed input is refused with every invalid
+// field listed before it reaches a service:
+//
+//	Email string `json:"email" validate:"required,email,max=254"`
+//
+// Rules are separated by commas:
+//
+//	required     the value is set: non-blank strings, non-nil pointers and
+//	             non-empty slices
+//	min=n, max=n bounds on numbers, the length of strings in characters, and
+//	             the number of items in slices
+//	gt=n         numbers greater than n
+//	email        an email address
+//	lat, lon     a latitude in [-90, 90] or a longitude in [-180, 180]
+//	oneof=a b    one of the space-separated values
+//	date         a YYYY-MM-DD date
+//	clock        an HH:MM time of day
+//	timezone     an IANA time zone
+//	hexcolor     a color like #1a73e8
+//	url          an http(s) URL
+//	after=Field  a time or date after the sibling Field's value
+//	notbefore=Field
+//	             a time or date on or after the sibling Field's value
+//
+// Rules other than required pass empty strings and nil pointers, so optional
+// fields are only checked when sent. Nested structs and slices of structs
+// are checked too, and fields are named by their JSON paths, such as
+// skills[2].score.
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/civic-weave/backend/internal/models"
+)
+
+const (
+	dateLayout  = "2006-01-02"
+	clockLayout = "15:04"
+)
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// FieldError is one field that failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors lists every field of a request that failed validation
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + " " + fe.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Struct checks v, a struct or pointer to one, returning Errors when any
+// field fails its rules
+func Struct(v interface{}) error {
+	var errs Errors
+	checkStruct(reflect.Indirect(reflect.ValueOf(v)), "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func checkStruct(v reflect.Value, prefix string, errs *Errors) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := jsonName(sf)
+		if name == "-" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		fv := v.Field(i)
+
+		if tag := sf.Tag.Get("validate"); tag != "" {
+			if msg := checkField(v, fv, tag); msg != "" {
+				*errs = append(*errs, FieldError{Field: path, Message: msg})
+				continue
+			}
+		}
+		checkNested(fv, path, errs)
+	}
+}
+
+// checkNested descends into struct values and slices of structs
+func checkNested(v reflect.Value, path string, errs *Errors) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.Struct && v.Type() != timeType:
+		checkStruct(v, path, errs)
+	case v.Kind() == reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			checkNested(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// checkField applies tag's rules to fv, a field of parent, returning the
+// message of the first rule it fails
+func checkField(parent, fv reflect.Value, tag string) string {
+	rules := strings.Split(tag, ",")
+	for _, rule := range rules {
+		if rule == "required" && isEmpty(fv) {
+			return "is required"
+		}
+	}
+
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return ""
+		}
+		fv = fv.Elem()
+	}
+	if fv.Kind() == reflect.String && fv.String() == "" {
+		return ""
+	}
+
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(rule, "=")
+		if msg := checkRule(parent, fv, name, arg); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+func checkRule(parent, v reflect.Value, name, arg string) string {
+	switch name {
+	case "required":
+		return ""
+	case "min", "max", "gt":
+		return checkBound(v, name, arg)
+	case "email":
+		addr, err := mail.ParseAddress(v.String())
+		if err != nil || addr.Address != v.String() {
+			return "must be an email address"
+		}
+	case "lat":
+		if f := v.Float(); f < -90 || f > 90 {
+			return "must be between -90 and 90"
+		}
+	case "lon":
+		if f := v.Float(); f < -180 || f > 180 {
+			return "must be between -180 and 180"
+		}
+	case "oneof":
+		options := strings.Fields(arg)
+		for _, option := range options {
+			if v.String() == option {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(options, ", ")
+	case "date":
+		if _, err := time.Parse(dateLayout, v.String()); err != nil {
+			return "must be a YYYY-MM-DD date"
+		}
+	case "clock":
+		if _, err := time.Parse(clockLayout, v.String()); err != nil {
+			return "must be an HH:MM time"
+		}
+	case "timezone":
+		if !models.ValidTimezone(v.String()) {
+			return "must be an IANA time zone such as America/Toronto"
+		}
+	case "hexcolor":
+		if !hexColorPattern.MatchString(v.String()) {
+			return "must be a hex color like #1a73e8"
+		}
+	case "url":
+		u, err := url.Parse(v.String())
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "must be an http(s) URL"
+		}
+	case "after", "notbefore":
+		return checkOrder(parent, v, arg, name == "after")
+	default:
+		panic("validate: unknown rule " + name)
+	}
+	return ""
+}
+
+func checkBound(v reflect.Value, name, arg string) string {
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		panic("validate: bad bound " + name + "=" + arg)
+	}
+
+	var n float64
+	var unit string
+	switch v.Kind() {
+	case reflect.String:
+		n, unit = float64(utf8.RuneCountInString(v.String())), " characters"
+	case reflect.Slice:
+		n, unit = float64(v.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		panic("validate: " + name + " on " + v.Kind().String())
+	}
+
+	switch {
+	case name == "min" && n < bound:
+		return "must be at least " + arg + unit
+	case name == "max" && n > bound:
+		return "must be at most " + arg + unit
+	case name == "gt" && n <= bound:
+		return "must be greater than " + arg
+	}
+	return ""
+}
+
+// checkOrder compares times, or dates written as strings, with the sibling
+// field named other, which they must follow, or when strict is false may
+// equal. It passes when the sibling isn't set.
+func checkOrder(parent, v reflect.Value, other string, strict bool) string {
+	sf, ok := parent.Type().FieldByName(other)
+	if !ok {
+		panic("validate: unknown field " + other)
+	}
+	ov := parent.FieldByIndex(sf.Index)
+	if ov.Kind() == reflect.Ptr {
+		if ov.IsNil() {
+			return ""
+		}
+		ov = ov.Elem()
+	}
+
+	var this, that time.Time
+	switch {
+	case v.Type() == timeType:
+		this, that = v.Interface().(time.Time), ov.Interface().(time.Time)
+	case v.Kind() == reflect.String:
+		var err error
+		if this, err = time.Parse(dateLayout, v.String()); err != nil {
+			return ""
+		}
+		if that, err = time.Parse(dateLayout, ov.String()); err != nil {
+			return ""
+		}
+	default:
+		panic("validate: ordering on " + v.Kind().String())
+	}
+	if this.IsZero() || that.IsZero() || this.After(that) {
+		return ""
+	}
+	if !strict {
+		if this.Equal(that) {
+			return ""
+		}
+		return "must not be before " + jsonName(sf)
+	}
+	return "must be after " + jsonName(sf)
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		if v.Len() == 0 {
+			return true
+		}
+		// A json.RawMessage holding null was sent as null
+		return v.Type().Elem().Kind() == reflect.Uint8 && string(v.Bytes()) == "null"
+	}
+	return v.IsZero()
+}
+
+func jsonName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
@@ -0,0 +1,467 @@
+package waivers
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrProjectNotFound      = errors.New("project not found")
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrWaiverNotFound       = errors.New("waiver not found")
+	ErrInvalidTitle         = errors.New("title is required and must be at most 200 characters")
+	ErrInvalidBody          = errors.New("body is required")
+	ErrInvalidSignedName    = errors.New("signedName is required and must be at most 200 characters")
+	ErrStaleVersion         = errors.New("waiver has been updated; review and sign the current version")
+	ErrAlreadySigned        = errors.New("volunteer has already signed this version of the waiver")
+)
+
+const maxTitleLength = 200
+
+// Querier runs queries on a database or inside a transaction
+type Querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+const waiverColumns = `
+	w.id, w.organization_id, w.project_id, w.title, w.body, w.version,
+	w.created_by, w.created_at, w.updated_at
+`
+
+// waiverInTenant matches waivers of the tenant's organization or its
+// projects; $2 is the tenant
+const waiverInTenant = `
+	($2 = '' OR COALESCE(w.organization_id, (SELECT p.organization_id FROM projects p WHERE p.id = w.project_id)) = NULLIF($2, '')::uuid)
+`
+
+func scanWaiver(row interface{ Scan(...interface{}) error }, w *models.WaiverTemplate, extra ...interface{}) error {
+	return row.Scan(append([]interface{}{
+		&w.ID,
+		&w.OrganizationID,
+		&w.ProjectID,
+		&w.Title,
+		&w.Body,
+		&w.Version,
+		&w.CreatedBy,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	}, extra...)...)
+}
+
+type Service struct {
+	db *sql.DB
+}
+
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// GetOrganizationWaivers lists the active waivers an organization requires
+// for all of its projects
+func (s *Service) GetOrganizationWaivers(orgID, tenantID string) ([]models.WaiverTemplate, error) {
+	if tenantID != "" && tenantID != orgID {
+		return nil, ErrOrganizationNotFound
+	}
+
+	query := `SELECT ` + waiverColumns + `
+		FROM waiver_templates w
+		WHERE w.organization_id = $1 AND w.archived_at IS NULL
+		ORDER BY w.created_at, w.id
+	`
+
+	return s.queryWaivers(query, orgID)
+}
+
+// GetProjectWaivers lists the active waivers volunteers must sign to enroll
+// in the project: its own and its organization's. When volunteerID is set,
+// each carries when that volunteer signed its current version.
+func (s *Service) GetProjectWaivers(projectID, volunteerID, tenantID string) ([]models.WaiverTemplate, error) {
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + waiverColumns + `,
+		       (SELECT ws.signed_at FROM waiver_signatures ws
+		        WHERE ws.waiver_id = w.id AND ws.version = w.version AND ws.volunteer_id::text = $2)
+		FROM waiver_templates w
+		JOIN projects p ON w.project_id = p.id OR w.organization_id = p.organization_id
+		WHERE p.id = $1 AND w.archived_at IS NULL
+		ORDER BY w.organization_id IS NULL, w.created_at, w.id
+	`
+
+	var list []models.WaiverTemplate
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, projectID, volunteerID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		list = []models.WaiverTemplate{}
+		for rows.Next() {
+			var w models.WaiverTemplate
+			if err := scanWaiver(rows, &w, &w.SignedAt); err != nil {
+				return err
+			}
+			list = append(list, w)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetWaiver returns an active waiver; waivers outside the tenant are
+// reported as not found
+func (s *Service) GetWaiver(waiverID, tenantID string) (*models.WaiverTemplate, error) {
+	query := `SELECT ` + waiverColumns + `
+		FROM waiver_templates w
+		WHERE w.id::text = $1 AND w.archived_at IS NULL
+		  AND ` + waiverInTenant
+
+	var w models.WaiverTemplate
+	err := database.WithReadRetry(func() error {
+		return scanWaiver(s.db.QueryRow(query, waiverID, tenantID), &w)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrWaiverNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// CreateOrganizationWaiver publishes version 1 of a waiver required for
+// every project of the organization
+func (s *Service) CreateOrganizationWaiver(orgID, createdBy, tenantID string, req models.WaiverTemplateRequest) (*models.WaiverTemplate, error) {
+	if tenantID != "" && tenantID != orgID {
+		return nil, ErrOrganizationNotFound
+	}
+	return s.createWaiver(&orgID, nil, createdBy, req)
+}
+
+// CreateProjectWaiver publishes version 1 of a waiver required for the project
+func (s *Service) CreateProjectWaiver(projectID, createdBy, tenantID string, req models.WaiverTemplateRequest) (*models.WaiverTemplate, error) {
+	if err := s.requireProjectInTenant(projectID, tenantID); err != nil {
+		return nil, err
+	}
+	return s.createWaiver(nil, &projectID, createdBy, req)
+}
+
+func (s *Service) createWaiver(orgID, projectID *string, createdBy string, req models.WaiverTemplateRequest) (*models.WaiverTemplate, error) {
+	title, body, err := validateTemplate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var w models.WaiverTemplate
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		err = scanWaiver(tx.QueryRow(`
+			INSERT INTO waiver_templates AS w (organization_id, project_id, title, body, created_by)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING `+waiverColumns,
+			orgID, projectID, title, body, createdBy,
+		), &w)
+		if err != nil {
+			return err
+		}
+		if err := insertVersion(tx, &w, createdBy); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// UpdateWaiver publishes a new version of the waiver. Earlier signatures no
+// longer count, so volunteers enrolling from now on sign the new text.
+func (s *Service) UpdateWaiver(waiverID, updatedBy, tenantID string, req models.WaiverTemplateRequest) (*models.WaiverTemplate, error) {
+	title, body, err := validateTemplate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var w models.WaiverTemplate
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		err = scanWaiver(tx.QueryRow(`
+			UPDATE waiver_templates w
+			SET title = $3, body = $4, version = w.version + 1, updated_at = CURRENT_TIMESTAMP
+			WHERE w.id::text = $1 AND w.archived_at IS NULL
+			  AND `+waiverInTenant+`
+			RETURNING `+waiverColumns,
+			waiverID, tenantID, title, body, updatedBy,
+		), &w)
+		if err != nil {
+			return err
+		}
+		if err := insertVersion(tx, &w, updatedBy); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrWaiverNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// ArchiveWaiver stops requiring the waiver; its versions and signatures are kept
+func (s *Service) ArchiveWaiver(waiverID, tenantID string) error {
+	var result sql.Result
+	err := database.WithWriteGuard(func() error {
+		var err error
+		result, err = s.db.Exec(`
+			UPDATE waiver_templates w
+			SET archived_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			WHERE w.id::text = $1 AND w.archived_at IS NULL
+			  AND `+waiverInTenant,
+			waiverID, tenantID,
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrWaiverNotFound
+	}
+
+	return nil
+}
+
+// Sign records the volunteer's e-signature of the waiver's current version
+// with the name they typed and the IP address they signed from
+func (s *Service) Sign(waiverID, volunteerID, ipAddress, tenantID string, req models.SignWaiverRequest) (*models.WaiverSignature, error) {
+	signedName := strings.TrimSpace(req.SignedName)
+	if signedName == "" || len([]rune(signedName)) > maxTitleLength {
+		return nil, ErrInvalidSignedName
+	}
+
+	w, err := s.GetWaiver(waiverID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Version != w.Version {
+		return nil, ErrStaleVersion
+	}
+
+	var sig models.WaiverSignature
+	err = database.WithWriteGuard(func() error {
+		return s.db.QueryRow(`
+			WITH signed AS (
+				INSERT INTO waiver_signatures (waiver_id, version, volunteer_id, signed_name, ip_address)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id, waiver_id, version, volunteer_id, signed_name, ip_address, signed_at
+			)
+			SELECT s.id, s.waiver_id, s.version, s.volunteer_id, u.name, s.signed_name, s.ip_address, s.signed_at
+			FROM signed s
+			JOIN users u ON u.id = s.volunteer_id
+		`, w.ID, w.Version, volunteerID, signedName, ipAddress).Scan(
+			&sig.ID,
+			&sig.WaiverID,
+			&sig.Version,
+			&sig.VolunteerID,
+			&sig.VolunteerName,
+			&sig.SignedName,
+			&sig.IPAddress,
+			&sig.SignedAt,
+		)
+	})
+	if isUniqueViolation(err) {
+		return nil, ErrAlreadySigned
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &sig, nil
+}
+
+// GetSignatures lists every signature of the waiver across its versions,
+// newest first
+func (s *Service) GetSignatures(waiverID, tenantID string) ([]models.WaiverSignature, error) {
+	w, err := s.GetWaiver(waiverID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT s.id, s.waiver_id, s.version, s.volunteer_id, u.name, s.signed_name, s.ip_address, s.signed_at
+		FROM waiver_signatures s
+		JOIN users u ON u.id = s.volunteer_id
+		WHERE s.waiver_id = $1
+		ORDER BY s.signed_at DESC, s.id
+	`
+
+	var list []models.WaiverSignature
+	err = database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, w.ID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		list = []models.WaiverSignature{}
+		for rows.Next() {
+			var sig models.WaiverSignature
+			if err := rows.Scan(
+				&sig.ID,
+				&sig.WaiverID,
+				&sig.Version,
+				&sig.VolunteerID,
+				&sig.VolunteerName,
+				&sig.SignedName,
+				&sig.IPAddress,
+				&sig.SignedAt,
+			); err != nil {
+				return err
+			}
+			list = append(list, sig)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Unsigned lists the project's active waivers whose current version the
+// volunteer has not signed
+func Unsigned(q Querier, volunteerID, projectID string) ([]models.WaiverTemplate, error) {
+	rows, err := q.Query(`SELECT `+waiverColumns+`
+		FROM waiver_templates w
+		JOIN projects p ON w.project_id = p.id OR w.organization_id = p.organization_id
+		WHERE p.id = $1 AND w.archived_at IS NULL
+		  AND NOT EXISTS (
+		      SELECT 1 FROM waiver_signatures ws
+		      WHERE ws.waiver_id = w.id AND ws.version = w.version AND ws.volunteer_id = $2
+		  )
+		ORDER BY w.organization_id IS NULL, w.created_at, w.id
+	`, projectID, volunteerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.WaiverTemplate{}
+	for rows.Next() {
+		var w models.WaiverTemplate
+		if err := scanWaiver(rows, &w); err != nil {
+			return nil, err
+		}
+		list = append(list, w)
+	}
+	return list, rows.Err()
+}
+
+func (s *Service) queryWaivers(query string, args ...interface{}) ([]models.WaiverTemplate, error) {
+	var list []models.WaiverTemplate
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		list = []models.WaiverTemplate{}
+		for rows.Next() {
+			var w models.WaiverTemplate
+			if err := scanWaiver(rows, &w); err != nil {
+				return err
+			}
+			list = append(list, w)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func insertVersion(tx *sql.Tx, w *models.WaiverTemplate, createdBy string) error {
+	_, err := tx.Exec(`
+		INSERT INTO waiver_template_versions (waiver_id, version, title, body, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+	`, w.ID, w.Version, w.Title, w.Body, createdBy)
+	return err
+}
+
+func validateTemplate(req models.WaiverTemplateRequest) (string, string, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" || len([]rune(title)) > maxTitleLength {
+		return "", "", ErrInvalidTitle
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return "", "", ErrInvalidBody
+	}
+	return title, body, nil
+}
+
+func (s *Service) requireProjectInTenant(projectID, tenantID string) error {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM projects
+			WHERE id = $1
+			  AND ($2 = '' OR organization_id = NULLIF($2, '')::uuid)
+		)
+	`
+
+	var exists bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRow(query, projectID, tenantID).Scan(&exists)
+	})
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,92 @@
+package warehouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/civic-weave/backend/internal/export"
+	"github.com/civic-weave/backend/internal/logging"
+)
+
+// FactTables are the datasets written on every run
+var FactTables = []string{"enrollments", "hours", "matches", "events"}
+
+// Exporter periodically snapshots the fact tables to a sink as CSV, one
+// date partition per day, so analysts can work from files instead of the
+// OLTP database. Later runs on the same day replace that day's files.
+type Exporter struct {
+	exportService *export.Service
+	sink          Sink
+	interval      time.Duration
+}
+
+func NewExporter(exportService *export.Service, sink Sink, interval time.Duration) *Exporter {
+	return &Exporter{
+		exportService: exportService,
+		sink:          sink,
+		interval:      interval,
+	}
+}
+
+// Run exports immediately and then on every interval until ctx is done
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.ExportOnce(ctx); err != nil {
+			logging.FromContext(ctx).Error("Warehouse export error", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExportOnce writes every fact table, continuing past failures and
+// returning the first one
+func (e *Exporter) ExportOnce(ctx context.Context) error {
+	partition := "dt=" + time.Now().UTC().Format("2006-01-02")
+
+	var firstErr error
+	for _, table := range FactTables {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		name := fmt.Sprintf("%s/%s/%s.csv", table, partition, table)
+		if err := e.exportTable(ctx, table, name); err != nil {
+			err = fmt.Errorf("export %s: %w", table, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		logging.FromContext(ctx).Info("Warehouse export wrote file", "name", name)
+	}
+	return firstErr
+}
+
+func (e *Exporter) exportTable(ctx context.Context, table, name string) error {
+	dataset, err := export.GetDataset(table)
+	if err != nil {
+		return err
+	}
+
+	obj, err := e.sink.Create(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	// Fact tables span every organization
+	err = e.exportService.Stream(export.NewWriter(obj, export.FormatCSV), dataset, dataset.Fields, "")
+	if err != nil {
+		obj.Abort(err)
+		return err
+	}
+	return obj.Commit()
+}
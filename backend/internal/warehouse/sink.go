@@ -0,0 +1,183 @@
+package warehouse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidDestination = errors.New("warehouse destination must be a directory path, file:// URL or gs://bucket/prefix URL")
+
+// Sink stores exported files
+type Sink interface {
+	Create(ctx context.Context, name string) (Object, error)
+}
+
+// Object is a file being written to a sink. It is only published by
+// Commit; Abort discards it so readers never see a partial export.
+type Object interface {
+	io.Writer
+	Commit() error
+	Abort(err error)
+}
+
+// ParseDestination returns the sink for a destination: a local directory
+// (or file:// URL), or a Cloud Storage gs://bucket/prefix URL
+func ParseDestination(dest string) (Sink, error) {
+	switch {
+	case strings.HasPrefix(dest, "gs://"):
+		u, err := url.Parse(dest)
+		if err != nil || u.Host == "" {
+			return nil, ErrInvalidDestination
+		}
+		return &GCSSink{Bucket: u.Host, Prefix: strings.Trim(u.Path, "/"), Client: http.DefaultClient}, nil
+	case strings.HasPrefix(dest, "file://"):
+		return &DirSink{Dir: strings.TrimPrefix(dest, "file://")}, nil
+	case dest != "" && !strings.Contains(dest, "://"):
+		return &DirSink{Dir: dest}, nil
+	}
+	return nil, ErrInvalidDestination
+}
+
+// DirSink writes files under a local directory, e.g. a mounted bucket
+type DirSink struct {
+	Dir string
+}
+
+func (d *DirSink) Create(ctx context.Context, name string) (Object, error) {
+	path := filepath.Join(d.Dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".export-*")
+	if err != nil {
+		return nil, err
+	}
+	return &dirFile{File: f, path: path}, nil
+}
+
+// dirFile is written to a temporary file that Commit renames into place
+type dirFile struct {
+	*os.File
+	path string
+}
+
+func (f *dirFile) Commit() error {
+	if err := f.File.Close(); err != nil {
+		os.Remove(f.File.Name())
+		return err
+	}
+	return os.Rename(f.File.Name(), f.path)
+}
+
+func (f *dirFile) Abort(err error) {
+	f.File.Close()
+	os.Remove(f.File.Name())
+}
+
+// gcsMetadataTokenURL serves access tokens for the instance's service
+// account on Cloud Run and Compute Engine
+const gcsMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// GCSSink uploads files to a Cloud Storage bucket using the service
+// account of the environment the API runs in
+type GCSSink struct {
+	Bucket string
+	Prefix string
+	Client *http.Client
+}
+
+func (g *GCSSink) Create(ctx context.Context, name string) (Object, error) {
+	token, err := g.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	object := name
+	if g.Prefix != "" {
+		object = g.Prefix + "/" + name
+	}
+	uploadURL := fmt.Sprintf("https://storage.googleapis.com/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		url.PathEscape(g.Bucket), url.QueryEscape(object))
+
+	// Stream the body through a pipe so the export is never buffered whole
+	pr, pw := io.Pipe()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, pr)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	done := make(chan error, 1)
+	go func() {
+		resp, err := g.Client.Do(req)
+		if err != nil {
+			pr.CloseWithError(err)
+			done <- err
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			err = fmt.Errorf("upload %s: %s: %s", object, resp.Status, strings.TrimSpace(string(body)))
+			pr.CloseWithError(err)
+		}
+		done <- err
+	}()
+
+	return &gcsObject{pw: pw, done: done}, nil
+}
+
+func (g *GCSSink) token(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcsMetadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetch access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetch access token: %s", resp.Status)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decode access token: %w", err)
+	}
+	return body.AccessToken, nil
+}
+
+// gcsObject is an upload in progress; Cloud Storage only creates the object
+// once the body completes, so aborting the body discards it
+type gcsObject struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func (o *gcsObject) Write(p []byte) (int, error) {
+	return o.pw.Write(p)
+}
+
+func (o *gcsObject) Commit() error {
+	o.pw.Close()
+	return <-o.done
+}
+
+func (o *gcsObject) Abort(err error) {
+	o.pw.CloseWithError(err)
+	<-o.done
+}
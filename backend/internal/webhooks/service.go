@@ -0,0 +1,483 @@
+// Package webhooks sends events to the callback URLs platform admins
+// register. Each delivery is a signed JSON POST made by a background job,
+// retried with exponential backoff until the endpoint answers 2xx, and
+// recorded so failed deliveries can be debugged.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/civic-weave/backend/internal/database"
+	"github.com/civic-weave/backend/internal/events"
+	"github.com/civic-weave/backend/internal/jobs"
+	"github.com/civic-weave/backend/internal/models"
+	"github.com/civic-weave/backend/internal/pagination"
+	"github.com/civic-weave/backend/internal/tracing"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrInvalidEvents   = errors.New("events must list at least one of enrollment.accepted, project.published or user.registered")
+)
+
+// Events webhooks can subscribe to
+const (
+	EventEnrollmentAccepted = "enrollment.accepted"
+	EventProjectPublished   = "project.published"
+	EventUserRegistered     = "user.registered"
+)
+
+var knownEvents = map[string]bool{
+	EventEnrollmentAccepted: true,
+	EventProjectPublished:   true,
+	EventUserRegistered:     true,
+}
+
+// DeliverJob is the kind of background job that makes one delivery. Its
+// payload is the delivery ID.
+const DeliverJob = "webhooks.deliver"
+
+// deliverRetry tries a delivery eight times over roughly four hours
+var deliverRetry = jobs.RetryPolicy{MaxAttempts: 8, Backoff: 30 * time.Second, MaxBackoff: time.Hour}
+
+// Headers sent with each delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// DeliverySorts are the fields delivery logs can sort by, newest first by
+// default
+var DeliverySorts = pagination.Sorts{
+	Fields:   map[string]string{"createdAt": "created_at"},
+	Default:  "-createdAt",
+	Tiebreak: "id",
+}
+
+type Service struct {
+	db          *sql.DB
+	jobsService *jobs.Service
+	client      *http.Client
+}
+
+// NewService registers the delivery job with jobsService, which must not be
+// running yet
+func NewService(db *sql.DB, jobsService *jobs.Service) *Service {
+	s := &Service{
+		db:          db,
+		jobsService: jobsService,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+	jobsService.Register(DeliverJob, jobs.Worker{
+		Handle: func(ctx context.Context, payload []byte) error {
+			var p deliverPayload
+			if err := json.Unmarshal(payload, &p); err != nil || p.DeliveryID == "" {
+				return fmt.Errorf("invalid payload %s", payload)
+			}
+			return s.Deliver(ctx, p.DeliveryID)
+		},
+		Retry:   deliverRetry,
+		Timeout: time.Minute,
+	})
+	return s
+}
+
+type deliverPayload struct {
+	DeliveryID string `json:"deliveryId"`
+}
+
+// checkEvents makes sure events names known events, at least one
+func checkEvents(events []string) error {
+	if len(events) == 0 {
+		return ErrInvalidEvents
+	}
+	for _, e := range events {
+		if !knownEvents[e] {
+			return ErrInvalidEvents
+		}
+	}
+	return nil
+}
+
+const webhookColumns = `id, url, events, active, created_by, created_at`
+
+func scanWebhook(row interface{ Scan(...interface{}) error }, w *models.Webhook) error {
+	return row.Scan(&w.ID, &w.URL, pq.Array(&w.Events), &w.Active, &w.CreatedBy, &w.CreatedAt)
+}
+
+// CreateWebhook registers url to receive events, returning the webhook with
+// the secret its deliveries are signed with
+func (s *Service) CreateWebhook(ctx context.Context, url string, events []string, createdBy string) (*models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "webhooks.CreateWebhook")
+	defer span.End()
+
+	if err := checkEvents(events); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	secret := "whsec_" + hex.EncodeToString(b)
+
+	query := `
+		INSERT INTO webhooks (url, events, secret, created_by)
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid)
+		RETURNING ` + webhookColumns
+
+	var w models.Webhook
+	err := database.WithWriteGuard(func() error {
+		return scanWebhook(s.db.QueryRowContext(ctx, query, url, pq.Array(events), secret, createdBy), &w)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	w.Secret = secret
+	return &w, nil
+}
+
+// GetWebhooks lists every webhook, newest first, without their secrets
+func (s *Service) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "webhooks.GetWebhooks")
+	defer span.End()
+
+	var list []models.Webhook
+	err := database.WithReadRetry(func() error {
+		rows, err := s.db.QueryContext(ctx, `SELECT `+webhookColumns+` FROM webhooks ORDER BY created_at DESC, id`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		list = []models.Webhook{}
+		for rows.Next() {
+			var w models.Webhook
+			if err := scanWebhook(rows, &w); err != nil {
+				return err
+			}
+			list = append(list, w)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	return list, nil
+}
+
+// UpdateWebhook changes the events a webhook receives or pauses it; nil
+// events and active are left as they are
+func (s *Service) UpdateWebhook(ctx context.Context, id string, events []string, active *bool) (*models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "webhooks.UpdateWebhook")
+	defer span.End()
+
+	if events != nil {
+		if err := checkEvents(events); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		UPDATE webhooks
+		SET events = COALESCE($2, events),
+		    active = COALESCE($3, active)
+		WHERE id::text = $1
+		RETURNING ` + webhookColumns
+
+	var eventsParam interface{}
+	if events != nil {
+		eventsParam = pq.Array(events)
+	}
+
+	var w models.Webhook
+	err := database.WithWriteGuard(func() error {
+		return scanWebhook(s.db.QueryRowContext(ctx, query, id, eventsParam, active), &w)
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return &w, nil
+}
+
+// DeleteWebhook removes a webhook along with its delivery log. Deliveries
+// still being retried are dropped.
+func (s *Service) DeleteWebhook(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "webhooks.DeleteWebhook")
+	defer span.End()
+
+	var n int64
+	err := database.WithWriteGuard(func() error {
+		result, err := s.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id::text = $1`, id)
+		if err != nil {
+			return err
+		}
+		n, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if n == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// GetDeliveries returns a page of the webhook's deliveries along with how
+// many there are across all pages
+func (s *Service) GetDeliveries(ctx context.Context, webhookID string, page pagination.Params) ([]models.WebhookDelivery, int, error) {
+	ctx, span := tracing.Start(ctx, "webhooks.GetDeliveries")
+	defer span.End()
+
+	query := `
+		SELECT id, webhook_id, event, payload, status, attempts, response_status, last_error, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE webhook_id::text = $1
+		ORDER BY ` + page.OrderBy + `
+		` + page.Window(2)
+
+	var list []models.WebhookDelivery
+	var total int
+	err := database.WithReadRetry(func() error {
+		var exists bool
+		if err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM webhooks WHERE id::text = $1)`, webhookID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return ErrWebhookNotFound
+		}
+		if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id::text = $1`, webhookID).Scan(&total); err != nil {
+			return err
+		}
+
+		rows, err := s.db.QueryContext(ctx, query, append([]interface{}{webhookID}, page.Args()...)...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		list = nil
+		for rows.Next() {
+			var d models.WebhookDelivery
+			if err := rows.Scan(&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt); err != nil {
+				return err
+			}
+			list = append(list, d)
+		}
+		return rows.Err()
+	})
+	if err == ErrWebhookNotFound {
+		return nil, 0, err
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	return list, total, nil
+}
+
+// HandleEnrollmentChanged is the events.Dispatcher subscriber sending
+// EventEnrollmentAccepted whenever a volunteer is enrolled
+func (s *Service) HandleEnrollmentChanged(ctx context.Context, e events.Event) error {
+	var c events.EnrollmentChanged
+	if err := e.Decode(&c); err != nil {
+		return err
+	}
+	if c.Status != "enrolled" {
+		return nil
+	}
+	_, err := s.Dispatch(ctx, EventEnrollmentAccepted, c)
+	return err
+}
+
+// Dispatch queues a delivery of event with data to every active webhook
+// subscribed to it, returning how many were queued. The body each webhook
+// receives is {"event": "...", "createdAt": "...", "data": {...}}.
+func (s *Service) Dispatch(ctx context.Context, event string, data interface{}) (int, error) {
+	ctx, span := tracing.Start(ctx, "webhooks.Dispatch")
+	defer span.End()
+
+	payload, err := json.Marshal(struct {
+		Event     string      `json:"event"`
+		CreatedAt time.Time   `json:"createdAt"`
+		Data      interface{} `json:"data"`
+	}{event, time.Now().UTC(), data})
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload)
+		SELECT id, $1, $2
+		FROM webhooks
+		WHERE active AND $1 = ANY(events)
+		RETURNING id
+	`
+
+	var n int
+	err = database.WithWriteGuard(func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		rows, err := tx.QueryContext(ctx, query, event, payload)
+		if err != nil {
+			return err
+		}
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if _, err := s.jobsService.EnqueueTx(tx, DeliverJob, deliverPayload{DeliveryID: id}); err != nil {
+				return err
+			}
+		}
+		n = len(ids)
+		return tx.Commit()
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to dispatch %s: %w", event, err)
+	}
+
+	return n, nil
+}
+
+// Deliver makes one attempt at a delivery and records its outcome. It
+// returns an error, so the job is retried, unless the endpoint answered
+// 2xx, the delivery already succeeded or its webhook was paused.
+func (s *Service) Deliver(ctx context.Context, deliveryID string) error {
+	ctx, span := tracing.Start(ctx, "webhooks.Deliver")
+	defer span.End()
+
+	query := `
+		SELECT d.event, d.payload, d.status, w.url, w.secret, w.active
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.id = $1
+	`
+	var event, status, url, secret string
+	var payload []byte
+	var active bool
+	err := database.WithReadRetry(func() error {
+		return s.db.QueryRowContext(ctx, query, deliveryID).Scan(&event, &payload, &status, &url, &secret, &active)
+	})
+	// The webhook was deleted since
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if status == "succeeded" {
+		return nil
+	}
+	if !active {
+		return s.recordAttempt(ctx, deliveryID, nil, errors.New("webhook is paused"), true)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return s.recordAttempt(ctx, deliveryID, nil, err, true)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "CivicWeave-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, deliveryID)
+	req.Header.Set(HeaderSignature, Sign(secret, time.Now(), payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return s.recordAttempt(ctx, deliveryID, nil, err, false)
+	}
+	defer resp.Body.Close()
+
+	code := resp.StatusCode
+	if code/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		err = fmt.Errorf("endpoint returned %s: %s", resp.Status, bytes.TrimSpace(detail))
+	}
+	return s.recordAttempt(ctx, deliveryID, &code, err, false)
+}
+
+// recordAttempt stores the outcome of an attempt. A failed attempt leaves
+// the delivery pending, to be retried, until its attempts are used up or
+// final is set; the error is returned so the job is retried.
+func (s *Service) recordAttempt(ctx context.Context, deliveryID string, responseStatus *int, attemptErr error, final bool) error {
+	var lastError *string
+	if attemptErr != nil {
+		msg := attemptErr.Error()
+		lastError = &msg
+	}
+
+	query := `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1,
+		    response_status = $2,
+		    last_error = $3,
+		    status = CASE
+		        WHEN $3::text IS NULL THEN 'succeeded'
+		        WHEN $4 OR attempts + 1 >= $5 THEN 'failed'
+		        ELSE 'pending'
+		    END,
+		    delivered_at = CASE WHEN $3::text IS NULL THEN NOW() END
+		WHERE id = $1
+	`
+	err := database.WithWriteGuard(func() error {
+		_, err := s.db.ExecContext(ctx, query, deliveryID, responseStatus, lastError, final, deliverRetry.MaxAttempts)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+	if final {
+		return nil
+	}
+	return attemptErr
+}
+
+// Sign returns the signature header for a delivery of payload made at t:
+// t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<payload>" keyed
+// with the webhook's secret>. Receivers recompute it to check the delivery
+// came from us, and reject old timestamps to stop replays.
+func Sign(secret string, t time.Time, payload []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(payload)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -1,12 +1,14 @@
 import { useState, useEffect } from 'react'
 import { BrowserRouter, Routes, Route, Navigate } from 'react-router-dom'
 import Login from './pages/Login'
+import OAuthCallback from './pages/OAuthCallback'
 import Dashboard from './pages/Dashboard'
 import Skills from './pages/Skills'
 import Projects from './pages/Projects'
 import ProjectDetail from './pages/ProjectDetail'
 import MyEnrollments from './pages/MyEnrollments'
 import { User } from './types'
+import { logout } from './api'
 
 function App() {
   const [user, setUser] = useState<User | null>(null)
@@ -29,6 +31,7 @@ function App() {
   const handleLogout = () => {
     setUser(null)
     localStorage.removeItem('user')
+    logout()
   }
 
   if (loading) {
@@ -42,6 +45,10 @@ function App() {
           path="/login"
           element={user ? <Navigate to="/" /> : <Login onLogin={handleLogin} />}
         />
+        <Route
+          path="/oauth/callback"
+          element={<OAuthCallback onLogin={handleLogin} />}
+        />
         <Route
           path="/"
           element={
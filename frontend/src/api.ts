@@ -1,5 +1,6 @@
 import {
   User,
+  AuthResponse,
   LoginRequest,
   RegisterRequest,
   Skill,
@@ -16,32 +17,140 @@ import {
   Enrollment,
   EnrollmentWithDetails,
   CreateEnrollmentRequest,
-  UpdateEnrollmentRequest
+  UpdateEnrollmentRequest,
+  Page
 } from './types'
 
 const API_BASE = '/api'
 
+const TOKEN_KEY = 'token'
+const REFRESH_TOKEN_KEY = 'refreshToken'
+
+function storeTokens(auth: AuthResponse): void {
+  localStorage.setItem(TOKEN_KEY, auth.token)
+  localStorage.setItem(REFRESH_TOKEN_KEY, auth.refreshToken)
+}
+
+function clearTokens(): void {
+  localStorage.removeItem(TOKEN_KEY)
+  localStorage.removeItem(REFRESH_TOKEN_KEY)
+}
+
+// Concurrent requests that find the access token expired share one refresh
+let refreshing: Promise<boolean> | null = null
+
+function refreshTokens(): Promise<boolean> {
+  const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY)
+  if (!refreshToken) return Promise.resolve(false)
+  if (!refreshing) {
+    refreshing = fetch(`${API_BASE}/auth/refresh`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ refreshToken }),
+    })
+      .then(async (response) => {
+        if (!response.ok) return false
+        storeTokens(await response.json())
+        return true
+      })
+      .catch(() => false)
+      .finally(() => {
+        refreshing = null
+      })
+  }
+  return refreshing
+}
+
+// fetch with the signed-in user's bearer token, which the API requires,
+// refreshing it once when it has expired
+async function apiFetch(input: string, init: RequestInit = {}): Promise<Response> {
+  const send = () => {
+    const headers = new Headers(init.headers)
+    const token = localStorage.getItem(TOKEN_KEY)
+    if (token) headers.set('Authorization', `Bearer ${token}`)
+    return fetch(input, { ...init, headers })
+  }
+  const response = await send()
+  if (response.status === 401 && (await refreshTokens())) {
+    return send()
+  }
+  return response
+}
+
+// logout ends the session on the server and forgets its tokens
+export async function logout(): Promise<void> {
+  try {
+    await apiFetch(`${API_BASE}/auth/logout`, { method: 'POST' })
+  } catch {
+    // The tokens are forgotten regardless
+  }
+  clearTokens()
+}
+
+// FieldError is one request field the API refused
+export interface FieldError {
+  field: string
+  message: string
+}
+
+// ApiError is a failed request, carrying the kind of failure, the invalid
+// fields when validation failed, and the ID to quote when reporting it
+export class ApiError extends Error {
+  constructor(
+    message: string,
+    public status: number,
+    public type?: string,
+    public requestId?: string,
+    public fields: FieldError[] = [],
+  ) {
+    super(message)
+    this.name = 'ApiError'
+  }
+}
+
 async function handleResponse<T>(response: Response): Promise<T> {
   if (!response.ok) {
     const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-    throw new Error(error.error || 'Request failed')
+    throw new ApiError(error.error || 'Request failed', response.status, error.type, error.requestId, error.fields)
+  }
+  if (response.status === 204) {
+    return undefined as T
   }
   return response.json()
 }
 
-// Auth APIs
-export async function getExistingUsers(): Promise<User[]> {
-  const response = await fetch(`${API_BASE}/users`)
-  return handleResponse<User[]>(response)
+// idempotencyHeaders are the headers of a JSON POST that may be retried
+// safely under key
+function idempotencyHeaders(key?: string): HeadersInit {
+  const headers: Record<string, string> = { 'Content-Type': 'application/json' }
+  if (key) headers['Idempotency-Key'] = key
+  return headers
+}
+
+// fetchAllPages follows a list endpoint's cursors and returns every item
+async function fetchAllPages<T>(url: string): Promise<T[]> {
+  const items: T[] = []
+  let cursor: string | undefined
+  do {
+    const separator = url.includes('?') ? '&' : '?'
+    const pageURL = `${url}${separator}limit=200${cursor ? `&cursor=${encodeURIComponent(cursor)}` : ''}`
+    const page = await handleResponse<Page<T>>(await apiFetch(pageURL))
+    items.push(...page.items)
+    cursor = page.nextCursor
+  } while (cursor)
+  return items
 }
 
-export async function loginAsUser(request: LoginRequest): Promise<User> {
+// Auth APIs
+export async function login(request: LoginRequest): Promise<User> {
   const response = await fetch(`${API_BASE}/auth/login`, {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify(request),
   })
-  return handleResponse<User>(response)
+  const auth = await handleResponse<AuthResponse>(response)
+  storeTokens(auth)
+  return auth.user
 }
 
 export async function registerVolunteer(request: RegisterRequest): Promise<User> {
@@ -50,25 +159,51 @@ export async function registerVolunteer(request: RegisterRequest): Promise<User>
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify(request),
   })
-  return handleResponse<User>(response)
+  const auth = await handleResponse<AuthResponse>(response)
+  storeTokens(auth)
+  return auth.user
+}
+
+// getOAuthProviders lists the external providers users can sign in with
+export async function getOAuthProviders(): Promise<string[]> {
+  const response = await fetch(`${API_BASE}/auth/oauth/providers`)
+  return handleResponse<string[]>(response)
+}
+
+// oauthLoginURL is where the browser goes to sign in with provider
+export function oauthLoginURL(provider: string): string {
+  return `${API_BASE}/auth/oauth/${encodeURIComponent(provider)}/login`
+}
+
+// completeOAuthLogin redeems the refresh token an external sign-in
+// redirected back with for the session's tokens
+export async function completeOAuthLogin(refreshToken: string): Promise<User> {
+  const response = await fetch(`${API_BASE}/auth/refresh`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ refreshToken }),
+  })
+  const auth = await handleResponse<AuthResponse>(response)
+  storeTokens(auth)
+  return auth.user
 }
 
 // Skills APIs
 export async function getAllSkills(): Promise<Skill[]> {
-  const response = await fetch(`${API_BASE}/skills`)
-  return handleResponse<Skill[]>(response)
+  return fetchAllPages<Skill>(`${API_BASE}/skills`)
 }
 
 export async function searchSkills(query: string, limit?: number): Promise<Skill[]> {
   const params = new URLSearchParams({ q: query })
   if (limit) params.set('limit', limit.toString())
 
-  const response = await fetch(`${API_BASE}/skills?${params.toString()}`)
-  return handleResponse<Skill[]>(response)
+  const response = await apiFetch(`${API_BASE}/skills?${params.toString()}`)
+  const page = await handleResponse<Page<Skill>>(response)
+  return page.items
 }
 
 export async function createSkill(request: { name: string; description?: string; category?: string }): Promise<Skill> {
-  const response = await fetch(`${API_BASE}/skills`, {
+  const response = await apiFetch(`${API_BASE}/skills`, {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify({
@@ -81,12 +216,12 @@ export async function createSkill(request: { name: string; description?: string;
 }
 
 export async function getVolunteerSkills(volunteerId: string): Promise<VolunteerSkill[]> {
-  const response = await fetch(`${API_BASE}/volunteers/${volunteerId}/skills`)
+  const response = await apiFetch(`${API_BASE}/volunteers/${volunteerId}/skills`)
   return handleResponse<VolunteerSkill[]>(response)
 }
 
 export async function updateVolunteerSkills(volunteerId: string, request: UpdateSkillsRequest): Promise<void> {
-  const response = await fetch(`${API_BASE}/volunteers/${volunteerId}/skills`, {
+  const response = await apiFetch(`${API_BASE}/volunteers/${volunteerId}/skills`, {
     method: 'PUT',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify(request),
@@ -94,8 +229,15 @@ export async function updateVolunteerSkills(volunteerId: string, request: Update
   return handleResponse<void>(response)
 }
 
+export async function verifyVolunteerSkill(projectId: string, volunteerId: string, skillId: string): Promise<void> {
+  const response = await apiFetch(`${API_BASE}/projects/${projectId}/volunteers/${volunteerId}/skills/${skillId}/verification`, {
+    method: 'PUT',
+  })
+  return handleResponse<void>(response)
+}
+
 export async function updateVolunteerLocation(volunteerId: string, request: UpdateLocationRequest): Promise<void> {
-  const response = await fetch(`${API_BASE}/volunteers/${volunteerId}/location`, {
+  const response = await apiFetch(`${API_BASE}/volunteers/${volunteerId}/location`, {
     method: 'PUT',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify(request),
@@ -105,17 +247,16 @@ export async function updateVolunteerLocation(volunteerId: string, request: Upda
 
 // Projects APIs
 export async function getAllProjects(): Promise<Project[]> {
-  const response = await fetch(`${API_BASE}/projects`)
-  return handleResponse<Project[]>(response)
+  return fetchAllPages<Project>(`${API_BASE}/projects`)
 }
 
 export async function getProject(projectId: string): Promise<Project> {
-  const response = await fetch(`${API_BASE}/projects/${projectId}`)
+  const response = await apiFetch(`${API_BASE}/projects/${projectId}`)
   return handleResponse<Project>(response)
 }
 
 export async function getProjectSkills(projectId: string): Promise<ProjectSkill[]> {
-  const response = await fetch(`${API_BASE}/projects/${projectId}/skills`)
+  const response = await apiFetch(`${API_BASE}/projects/${projectId}/skills`)
   return handleResponse<ProjectSkill[]>(response)
 }
 
@@ -123,7 +264,7 @@ export async function updateProjectSkills(
   projectId: string,
   request: UpdateProjectSkillsRequest
 ): Promise<void> {
-  const response = await fetch(`${API_BASE}/projects/${projectId}/skills`, {
+  const response = await apiFetch(`${API_BASE}/projects/${projectId}/skills`, {
     method: 'PUT',
     headers: {
       'Content-Type': 'application/json',
@@ -134,7 +275,7 @@ export async function updateProjectSkills(
 }
 
 export async function updateProject(projectId: string, request: UpdateProjectRequest): Promise<void> {
-  const response = await fetch(`${API_BASE}/projects/${projectId}`, {
+  const response = await apiFetch(`${API_BASE}/projects/${projectId}`, {
     method: 'PUT',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify(request),
@@ -142,24 +283,30 @@ export async function updateProject(projectId: string, request: UpdateProjectReq
   return handleResponse<void>(response)
 }
 
-export async function createProject(request: CreateProjectRequest): Promise<Project> {
-  const response = await fetch(`${API_BASE}/projects`, {
+// Retrying with the same idempotencyKey returns the project the first attempt
+// created instead of creating another
+export async function createProject(request: CreateProjectRequest, idempotencyKey?: string): Promise<Project> {
+  const response = await apiFetch(`${API_BASE}/projects`, {
     method: 'POST',
-    headers: { 'Content-Type': 'application/json' },
+    headers: idempotencyHeaders(idempotencyKey),
     body: JSON.stringify(request),
   })
   return handleResponse<Project>(response)
 }
 
-export async function retireProject(projectId: string): Promise<void> {
-  const response = await fetch(`${API_BASE}/projects/${projectId}/status`, {
+export async function updateProjectStatus(projectId: string, status: string): Promise<void> {
+  const response = await apiFetch(`${API_BASE}/projects/${projectId}/status`, {
     method: 'PUT',
     headers: { 'Content-Type': 'application/json' },
-    body: JSON.stringify({ status: 'retired' }),
+    body: JSON.stringify({ status }),
   })
   return handleResponse<void>(response)
 }
 
+export async function retireProject(projectId: string): Promise<void> {
+  return updateProjectStatus(projectId, 'retired')
+}
+
 // Matching APIs
 export async function findMatchesForProject(
   projectId: string,
@@ -171,15 +318,13 @@ export async function findMatchesForProject(
   }
 ): Promise<VolunteerMatch[]> {
   const queryParams = new URLSearchParams()
-  // Add impersonation parameter for coordinator access
-  queryParams.set('impersonate', 'coordinator')
   if (params?.skillWeight) queryParams.set('skillWeight', params.skillWeight.toString())
   if (params?.distanceWeight) queryParams.set('distanceWeight', params.distanceWeight.toString())
   if (params?.maxDistanceKm) queryParams.set('maxDistanceKm', params.maxDistanceKm.toString())
   if (params?.limit) queryParams.set('limit', params.limit.toString())
 
   const url = `${API_BASE}/projects/${projectId}/matches?${queryParams.toString()}`
-  const response = await fetch(url)
+  const response = await apiFetch(url)
   return handleResponse<VolunteerMatch[]>(response)
 }
 
@@ -193,46 +338,41 @@ export async function findMatchesForVolunteer(
   }
 ): Promise<ProjectMatch[]> {
   const queryParams = new URLSearchParams()
-  // Impersonation parameter for volunteer access
-  queryParams.set('impersonate', 'volunteer')
   if (params?.skillWeight) queryParams.set('skillWeight', params.skillWeight.toString())
   if (params?.distanceWeight) queryParams.set('distanceWeight', params.distanceWeight.toString())
   if (params?.maxDistanceKm) queryParams.set('maxDistanceKm', params.maxDistanceKm.toString())
   if (params?.limit) queryParams.set('limit', params.limit.toString())
 
   const url = `${API_BASE}/volunteers/${volunteerId}/matches?${queryParams.toString()}`
-  const response = await fetch(url)
+  const response = await apiFetch(url)
   return handleResponse<ProjectMatch[]>(response)
 }
 
 // Enrollment APIs
-export async function createEnrollment(
-  userId: string,
-  request: CreateEnrollmentRequest
-): Promise<Enrollment> {
-  const response = await fetch(`${API_BASE}/enrollments?userId=${userId}`, {
+// Retrying with the same idempotencyKey returns the enrollment the first
+// attempt created instead of a conflict
+export async function createEnrollment(request: CreateEnrollmentRequest, idempotencyKey?: string): Promise<Enrollment> {
+  const response = await apiFetch(`${API_BASE}/enrollments`, {
     method: 'POST',
-    headers: { 'Content-Type': 'application/json' },
+    headers: idempotencyHeaders(idempotencyKey),
     body: JSON.stringify(request),
   })
   return handleResponse<Enrollment>(response)
 }
 
 export async function getProjectEnrollments(projectId: string): Promise<EnrollmentWithDetails[]> {
-  const response = await fetch(`${API_BASE}/projects/${projectId}/enrollments`)
-  return handleResponse<EnrollmentWithDetails[]>(response)
+  return fetchAllPages<EnrollmentWithDetails>(`${API_BASE}/projects/${projectId}/enrollments`)
 }
 
 export async function getVolunteerEnrollments(volunteerId: string): Promise<EnrollmentWithDetails[]> {
-  const response = await fetch(`${API_BASE}/volunteers/${volunteerId}/enrollments`)
-  return handleResponse<EnrollmentWithDetails[]>(response)
+  return fetchAllPages<EnrollmentWithDetails>(`${API_BASE}/volunteers/${volunteerId}/enrollments`)
 }
 
 export async function updateEnrollmentStatus(
   enrollmentId: string,
   request: UpdateEnrollmentRequest
 ): Promise<void> {
-  const response = await fetch(`${API_BASE}/enrollments/${enrollmentId}/status`, {
+  const response = await apiFetch(`${API_BASE}/enrollments/${enrollmentId}/status`, {
     method: 'PUT',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify(request),
@@ -244,11 +384,11 @@ export async function checkEnrollmentStatus(
   volunteerId: string,
   projectId: string
 ): Promise<{ enrolled: boolean }> {
-  const response = await fetch(`${API_BASE}/volunteers/${volunteerId}/projects/${projectId}/enrollment-status`)
+  const response = await apiFetch(`${API_BASE}/volunteers/${volunteerId}/projects/${projectId}/enrollment-status`)
   return handleResponse<{ enrolled: boolean }>(response)
 }
 
 export async function getPendingEnrollments(): Promise<EnrollmentWithDetails[]> {
-  const response = await fetch(`${API_BASE}/enrollments/pending`)
+  const response = await apiFetch(`${API_BASE}/enrollments/pending`)
   return handleResponse<EnrollmentWithDetails[]>(response)
 }
@@ -1,6 +1,6 @@
 import React, { useState } from 'react'
 import { Project } from '../types'
-import { createEnrollment, checkEnrollmentStatus } from '../api'
+import { createEnrollment, checkEnrollmentStatus, ApiError } from '../api'
 
 interface ProjectEnrollmentRequestProps {
   project: Project
@@ -18,6 +18,10 @@ export const ProjectEnrollmentRequest: React.FC<ProjectEnrollmentRequestProps> =
   const [error, setError] = useState<string | null>(null)
   const [isEnrolled, setIsEnrolled] = useState<boolean | null>(null)
   const [checkingEnrollment, setCheckingEnrollment] = useState(true)
+  // Sent with each attempt to request enrollment, so retrying after a
+  // dropped connection can't send the request twice; replaced once the
+  // server answers
+  const requestKey = React.useRef(crypto.randomUUID())
 
   React.useEffect(() => {
     checkEnrollment()
@@ -43,16 +47,18 @@ export const ProjectEnrollmentRequest: React.FC<ProjectEnrollmentRequestProps> =
       setLoading(true)
       setError(null)
       
-      await createEnrollment(volunteerId, {
+      await createEnrollment({
         projectId: project.id,
         action: 'request',
         message: message.trim()
-      })
+      }, requestKey.current)
+      requestKey.current = crypto.randomUUID()
       
       setMessage('')
       setIsEnrolled(true)
       onEnrollmentCreated?.()
     } catch (err) {
+      if (err instanceof ApiError) requestKey.current = crypto.randomUUID()
       setError(err instanceof Error ? err.message : 'Failed to create enrollment request')
     } finally {
       setLoading(false)
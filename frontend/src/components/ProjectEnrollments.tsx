@@ -143,7 +143,7 @@ export const ProjectEnrollments: React.FC<ProjectEnrollmentsProps> = ({ projectI
                 {match.matchedSkills.length > 0 && (
                   <div className="mt-2">
                     <p className="text-xs text-gray-600">
-                      Matched skills: {match.matchedSkills.join(', ')}
+                      Matched skills: {match.matchedSkills.map(s => s.name).join(', ')}
                     </p>
                   </div>
                 )}
@@ -196,7 +196,7 @@ export const ProjectEnrollments: React.FC<ProjectEnrollmentsProps> = ({ projectI
                   {matchData && matchData.matchedSkills.length > 0 && (
                     <div className="mb-3">
                       <p className="text-xs text-gray-600">
-                        <span className="font-medium">Matched skills:</span> {matchData.matchedSkills.join(', ')}
+                        <span className="font-medium">Matched skills:</span> {matchData.matchedSkills.map(s => s.name).join(', ')}
                       </p>
                     </div>
                   )}
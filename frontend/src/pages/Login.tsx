@@ -1,6 +1,6 @@
-import { useState, useEffect } from 'react'
+import { useEffect, useState } from 'react'
 import { User } from '../types'
-import { getExistingUsers, loginAsUser, registerVolunteer } from '../api'
+import { getOAuthProviders, login, oauthLoginURL, registerVolunteer } from '../api'
 
 interface LoginProps {
   onLogin: (user: User) => void
@@ -8,46 +8,34 @@ interface LoginProps {
 
 type TabType = 'existing' | 'register'
 
+const providerLabels: Record<string, string> = {
+  google: 'Google',
+  microsoft: 'Microsoft',
+}
+
 export default function Login({ onLogin }: LoginProps) {
   const [activeTab, setActiveTab] = useState<TabType>('existing')
-  const [existingUsers, setExistingUsers] = useState<User[]>([])
-  const [selectedUserId, setSelectedUserId] = useState('')
   const [email, setEmail] = useState('')
   const [name, setName] = useState('')
+  const [password, setPassword] = useState('')
   const [loading, setLoading] = useState(false)
   const [error, setError] = useState('')
+  const [providers, setProviders] = useState<string[]>([])
 
   useEffect(() => {
-    if (activeTab === 'existing') {
-      loadExistingUsers()
-    }
-  }, [activeTab])
+    getOAuthProviders()
+      .then(setProviders)
+      .catch(() => setProviders([]))
+  }, [])
 
-  const loadExistingUsers = async () => {
-    try {
-      const users = await getExistingUsers()
-      setExistingUsers(users)
-      if (users.length > 0) {
-        setSelectedUserId(users[0].id)
-      }
-    } catch (err) {
-      setError('Failed to load users. Make sure the backend is running.')
-    }
-  }
-
-  const handleExistingUserLogin = async (e: React.FormEvent) => {
+  const handleLogin = async (e: React.FormEvent) => {
     e.preventDefault()
-    if (!selectedUserId) return
-
     setLoading(true)
     setError('')
 
     try {
-      const selectedUser = existingUsers.find(u => u.id === selectedUserId)
-      if (selectedUser) {
-        const user = await loginAsUser({ email: selectedUser.email })
-        onLogin(user)
-      }
+      const user = await login({ email, password })
+      onLogin(user)
     } catch (err) {
       setError(err instanceof Error ? err.message : 'Login failed')
     } finally {
@@ -61,7 +49,7 @@ export default function Login({ onLogin }: LoginProps) {
     setError('')
 
     try {
-      const user = await registerVolunteer({ email, name })
+      const user = await registerVolunteer({ email, name, password })
       onLogin(user)
     } catch (err) {
       setError(err instanceof Error ? err.message : 'Registration failed')
@@ -70,6 +58,35 @@ export default function Login({ onLogin }: LoginProps) {
     }
   }
 
+  const emailAndPassword = (
+    <>
+      <div className="form-group">
+        <label htmlFor="email">Email:</label>
+        <input
+          id="email"
+          type="email"
+          value={email}
+          onChange={(e) => setEmail(e.target.value)}
+          placeholder="Enter your email"
+          required
+        />
+      </div>
+      <div className="form-group">
+        <label htmlFor="password">Password:</label>
+        <input
+          id="password"
+          type="password"
+          value={password}
+          onChange={(e) => setPassword(e.target.value)}
+          placeholder={activeTab === 'register' ? 'At least 8 characters' : 'Enter your password'}
+          minLength={activeTab === 'register' ? 8 : undefined}
+          autoComplete={activeTab === 'register' ? 'new-password' : 'current-password'}
+          required
+        />
+      </div>
+    </>
+  )
+
   return (
     <div className="login-container">
       <div className="login-card">
@@ -83,7 +100,7 @@ export default function Login({ onLogin }: LoginProps) {
             className={`tab-button ${activeTab === 'existing' ? 'active' : ''}`}
             onClick={() => setActiveTab('existing')}
           >
-            Select Role
+            Sign In
           </button>
           <button
             className={`tab-button ${activeTab === 'register' ? 'active' : ''}`}
@@ -96,32 +113,10 @@ export default function Login({ onLogin }: LoginProps) {
         {error && <div className="error">{error}</div>}
 
         {activeTab === 'existing' ? (
-          <form onSubmit={handleExistingUserLogin}>
-            <div className="form-group">
-              <label htmlFor="user-select">Select a role to impersonate:</label>
-              <select
-                id="user-select"
-                value={selectedUserId}
-                onChange={(e) => setSelectedUserId(e.target.value)}
-                required
-              >
-                {existingUsers.length === 0 ? (
-                  <option value="">No users available</option>
-                ) : (
-                  existingUsers.map((user) => (
-                    <option key={user.id} value={user.id}>
-                      {user.name} ({user.role})
-                    </option>
-                  ))
-                )}
-              </select>
-            </div>
-            <button
-              type="submit"
-              className="btn"
-              disabled={loading || existingUsers.length === 0}
-            >
-              {loading ? 'Logging in...' : 'Login as Selected User'}
+          <form onSubmit={handleLogin}>
+            {emailAndPassword}
+            <button type="submit" className="btn" disabled={loading}>
+              {loading ? 'Logging in...' : 'Sign In'}
             </button>
           </form>
         ) : (
@@ -137,17 +132,7 @@ export default function Login({ onLogin }: LoginProps) {
                 required
               />
             </div>
-            <div className="form-group">
-              <label htmlFor="email">Email:</label>
-              <input
-                id="email"
-                type="email"
-                value={email}
-                onChange={(e) => setEmail(e.target.value)}
-                placeholder="Enter your email"
-                required
-              />
-            </div>
+            {emailAndPassword}
             <button type="submit" className="btn" disabled={loading}>
               {loading ? 'Registering...' : 'Register as Volunteer'}
             </button>
@@ -156,6 +141,17 @@ export default function Login({ onLogin }: LoginProps) {
             </p>
           </form>
         )}
+
+        {providers.length > 0 && (
+          <div className="oauth-providers">
+            <p>Or continue with</p>
+            {providers.map((provider) => (
+              <a key={provider} href={oauthLoginURL(provider)} className="btn">
+                {providerLabels[provider] ?? provider}
+              </a>
+            ))}
+          </div>
+        )}
       </div>
     </div>
   )
@@ -0,0 +1,61 @@
+import { useEffect, useRef, useState } from 'react'
+import { Link, useNavigate } from 'react-router-dom'
+import { User } from '../types'
+import { completeOAuthLogin } from '../api'
+
+interface OAuthCallbackProps {
+  onLogin: (user: User) => void
+}
+
+const errorMessages: Record<string, string> = {
+  access_denied: 'Sign-in was cancelled.',
+  invalid_state: 'Sign-in took too long or was started in another browser. Please try again.',
+  email_not_verified: 'The provider has not verified your email address.',
+  account_suspended: 'Account suspended',
+}
+
+// OAuthCallback finishes signing in with an external provider, which
+// redirects here with the session's refresh token in the URL fragment
+export default function OAuthCallback({ onLogin }: OAuthCallbackProps) {
+  const navigate = useNavigate()
+  const [error, setError] = useState('')
+  const started = useRef(false)
+
+  useEffect(() => {
+    if (started.current) return
+    started.current = true
+
+    const params = new URLSearchParams(window.location.hash.slice(1))
+    // Keep the token out of the browser history
+    window.history.replaceState(null, '', window.location.pathname)
+
+    const refreshToken = params.get('refreshToken')
+    if (!refreshToken) {
+      setError(errorMessages[params.get('error') ?? ''] ?? 'Sign-in failed')
+      return
+    }
+    completeOAuthLogin(refreshToken)
+      .then((user) => {
+        onLogin(user)
+        navigate('/', { replace: true })
+      })
+      .catch((err) => setError(err instanceof Error ? err.message : 'Sign-in failed'))
+  }, [navigate, onLogin])
+
+  return (
+    <div className="login-container">
+      <div className="login-card">
+        {error ? (
+          <>
+            <div className="error">{error}</div>
+            <Link to="/login" className="btn">
+              Back to sign in
+            </Link>
+          </>
+        ) : (
+          <div className="loading">Signing in...</div>
+        )}
+      </div>
+    </div>
+  )
+}
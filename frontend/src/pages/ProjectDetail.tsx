@@ -1,7 +1,7 @@
 import { useState, useEffect } from 'react'
 import { useParams, useNavigate } from 'react-router-dom'
 import { Project, ProjectSkill, VolunteerMatch, Skill as SkillType, User, EnrollmentWithDetails, UpdateProjectSkillsRequest, VolunteerSkill } from '../types'
-import { getProject, getProjectSkills, findMatchesForProject, getAllSkills, createEnrollment, getProjectEnrollments, updateProjectSkills, updateProject, retireProject, updateVolunteerSkills, getVolunteerSkills } from '../api'
+import { getProject, getProjectSkills, findMatchesForProject, getAllSkills, createEnrollment, getProjectEnrollments, updateProjectSkills, updateProject, retireProject, updateProjectStatus, getVolunteerSkills, verifyVolunteerSkill, ApiError } from '../api'
 import LocationAutocomplete from '../components/LocationAutocomplete'
 import SkillAutocomplete from '../components/SkillAutocomplete'
 import { ProjectEnrollments } from '../components/ProjectEnrollments'
@@ -35,11 +35,7 @@ export default function ProjectDetail({ user }: { user?: User }) {
   const publish = async () => {
     if (!project) return
     try {
-      await fetch(`/api/projects/${project.id}/status`, {
-        method: 'PUT',
-        headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ status: 'active' }),
-      })
+      await updateProjectStatus(project.id, 'active')
       await loadProject()
     } catch (e) {
       setError(e instanceof Error ? e.message : 'Failed to publish project')
@@ -52,12 +48,12 @@ export default function ProjectDetail({ user }: { user?: User }) {
   const [editLongitude, setEditLongitude] = useState<number | undefined>(undefined)
   const [editLocationName, setEditLocationName] = useState<string | undefined>(undefined)
 
-  // Member skill editing modal
+  // Member skill verification modal
   const [showSkillModal, setShowSkillModal] = useState(false)
   const [selectedMember, setSelectedMember] = useState<EnrollmentWithDetails | null>(null)
-  const [editedMemberSkills, setEditedMemberSkills] = useState<VolunteerSkill[]>([])
+  const [memberSkills, setMemberSkills] = useState<VolunteerSkill[]>([])
   const [loadingMemberSkills, setLoadingMemberSkills] = useState(false)
-  const [savingMemberSkills, setSavingMemberSkills] = useState(false)
+  const [verifyingSkillId, setVerifyingSkillId] = useState<string | null>(null)
 
   useEffect(() => {
     if (id) {
@@ -65,6 +61,17 @@ export default function ProjectDetail({ user }: { user?: User }) {
     }
   }, [id])
 
+  // Only people managing the project may list its enrollments
+  const loadEnrollments = async (projectId: string): Promise<EnrollmentWithDetails[]> => {
+    if (user?.role === 'volunteer') return []
+    try {
+      return await getProjectEnrollments(projectId)
+    } catch (err) {
+      if (err instanceof ApiError && err.status === 403) return []
+      throw err
+    }
+  }
+
   const loadProject = async () => {
     if (!id) return
 
@@ -73,7 +80,7 @@ export default function ProjectDetail({ user }: { user?: User }) {
         getProject(id),
         getProjectSkills(id),
         getAllSkills(),
-        getProjectEnrollments(id),
+        loadEnrollments(id),
       ])
 
       console.debug('[ProjectDetail] loaded project', projectData.id, projectData.status, 'skills', skills?.length || 0, 'enrollments', enrolls?.length || 0)
@@ -161,7 +168,7 @@ export default function ProjectDetail({ user }: { user?: User }) {
     if (!user || user.role !== 'coordinator' || !project) return
     try {
       setError('')
-      await createEnrollment(user.id, {
+      await createEnrollment({
         projectId: project.id,
         action: 'invite',
         volunteerId,
@@ -241,10 +248,10 @@ export default function ProjectDetail({ user }: { user?: User }) {
     setLoadingMemberSkills(true)
     try {
       const skills = await getVolunteerSkills(member.volunteerId)
-      setEditedMemberSkills(skills || [])
+      setMemberSkills(skills || [])
     } catch (err) {
       setError(err instanceof Error ? err.message : 'Failed to load member skills')
-      setEditedMemberSkills([])
+      setMemberSkills([])
     } finally {
       setLoadingMemberSkills(false)
     }
@@ -253,34 +260,21 @@ export default function ProjectDetail({ user }: { user?: User }) {
   const closeMemberSkillModal = () => {
     setShowSkillModal(false)
     setSelectedMember(null)
-    setEditedMemberSkills([])
+    setMemberSkills([])
   }
 
-  const updateMemberSkillScore = (skillId: string, score: number) => {
-    setEditedMemberSkills(
-      editedMemberSkills.map(s =>
-        s.skillId === skillId ? { ...s, score: Math.max(0, Math.min(1, score)) } : s
-      )
-    )
-  }
-
-  const saveMemberSkills = async () => {
-    if (!selectedMember) return
-    setSavingMemberSkills(true)
+  // Volunteers own their skill claims; coordinators can only vouch for them
+  const verifyMemberSkill = async (skillId: string) => {
+    if (!project || !selectedMember) return
+    setVerifyingSkillId(skillId)
     try {
-      await updateVolunteerSkills(selectedMember.volunteerId, {
-        skills: editedMemberSkills.map(s => ({
-          skillId: s.skillId,
-          claimed: s.claimed,
-          score: s.score,
-        })),
-      })
-      closeMemberSkillModal()
+      await verifyVolunteerSkill(project.id, selectedMember.volunteerId, skillId)
+      setMemberSkills(await getVolunteerSkills(selectedMember.volunteerId) || [])
       setError('')
     } catch (e) {
-      setError(e instanceof Error ? e.message : 'Failed to update member skills')
+      setError(e instanceof Error ? e.message : 'Failed to verify skill')
     } finally {
-      setSavingMemberSkills(false)
+      setVerifyingSkillId(null)
     }
   }
 
@@ -518,12 +512,14 @@ export default function ProjectDetail({ user }: { user?: User }) {
           >
             Top Matches
           </button>
-          <button
-            className={`tab-button ${activeTab === 'requests' ? 'active' : ''}`}
-            onClick={() => setActiveTab('requests')}
-          >
-            Requests
-          </button>
+          {user?.role !== 'volunteer' && (
+            <button
+              className={`tab-button ${activeTab === 'requests' ? 'active' : ''}`}
+              onClick={() => setActiveTab('requests')}
+            >
+              Requests
+            </button>
+          )}
           <button
             className={`tab-button ${activeTab === 'tasks' ? 'active' : ''}`}
             onClick={() => setActiveTab('tasks')}
@@ -536,7 +532,9 @@ export default function ProjectDetail({ user }: { user?: User }) {
         {activeTab === 'members' && (
           <div className="project-info" style={{ marginTop: '1rem' }}>
             <h3>Active Members</h3>
-            {enrollments.filter(e => e.status === 'enrolled').length === 0 ? (
+            {user?.role === 'volunteer' ? (
+              <p className="subtitle">Members are listed for the project's coordinators.</p>
+            ) : enrollments.filter(e => e.status === 'enrolled').length === 0 ? (
               <p className="subtitle">No active members yet.</p>
             ) : (
               <div className="projects-grid">
@@ -559,7 +557,7 @@ export default function ProjectDetail({ user }: { user?: User }) {
                     </div>
                     {user?.role === 'coordinator' && (
                       <p className="subtitle" style={{ marginTop: '0.5rem', fontSize: '0.85rem' }}>
-                        Click to edit skills
+                        Click to verify skills
                       </p>
                     )}
                   </div>
@@ -616,32 +614,9 @@ export default function ProjectDetail({ user }: { user?: User }) {
                       {Array.isArray(match.matchedSkills) && (match.matchedSkills || []).length > 0 && (
                         <div className="matched-skills">
                           <strong>Matched Skills:</strong>
-                          {(Array.isArray(match.matchedSkills) ? match.matchedSkills : []).map((skillId) => (
-                            <span key={skillId} className="matched-skill" style={{ display: 'inline-flex', alignItems: 'center', gap: '0.25rem' }}>
-                              {getSkillName(skillId)}
-                              {user?.role === 'coordinator' && (
-                                <input
-                                  type="number"
-                                  min={0}
-                                  max={1}
-                                  step={0.1}
-                                  defaultValue={0.5}
-                                  style={{ width: 56, padding: '0.15rem 0.25rem', fontSize: '0.85rem' }}
-                                  onBlur={async (e) => {
-                                    const value = parseFloat(e.target.value)
-                                    if (isNaN(value)) return
-                                    try {
-                                      await updateVolunteerSkills(match.volunteerId, {
-                                        skills: [{ skillId, claimed: true, score: Math.max(0, Math.min(1, value)) }],
-                                      })
-                                    } catch (err) {
-                                      // eslint-disable-next-line no-console
-                                      console.error('Failed to update volunteer skill', err)
-                                    }
-                                  }}
-                                  title="Adjust volunteer skill score"
-                                />
-                              )}
+                          {(Array.isArray(match.matchedSkills) ? match.matchedSkills : []).map((skill) => (
+                            <span key={skill.id} className="matched-skill" style={{ display: 'inline-flex', alignItems: 'center', gap: '0.25rem' }}>
+                              {skill.name}
                             </span>
                           ))}
                         </div>
@@ -666,7 +641,7 @@ export default function ProjectDetail({ user }: { user?: User }) {
           </div>
         )}
 
-        {activeTab === 'requests' && (
+        {activeTab === 'requests' && user?.role !== 'volunteer' && (
           <div className="project-info" style={{ marginTop: '1rem' }}>
             <h3>Requests</h3>
             <ProjectEnrollments projectId={project.id} projectName={project.name} />
@@ -682,7 +657,7 @@ export default function ProjectDetail({ user }: { user?: User }) {
 
       </div>
 
-      {/* Member Skill Editing Modal */}
+      {/* Member Skill Verification Modal */}
       {showSkillModal && selectedMember && (
         <div
           style={{
@@ -713,7 +688,7 @@ export default function ProjectDetail({ user }: { user?: User }) {
             onClick={(e) => e.stopPropagation()}
           >
             <div style={{ display: 'flex', justifyContent: 'space-between', alignItems: 'center', marginBottom: '1.5rem' }}>
-              <h2 style={{ margin: 0 }}>Edit Skills: {selectedMember.volunteerName}</h2>
+              <h2 style={{ margin: 0 }}>Skills: {selectedMember.volunteerName}</h2>
               <button
                 onClick={closeMemberSkillModal}
                 style={{
@@ -732,11 +707,11 @@ export default function ProjectDetail({ user }: { user?: User }) {
               <div className="loading">Loading skills...</div>
             ) : (
               <>
-                {editedMemberSkills.length === 0 ? (
+                {memberSkills.length === 0 ? (
                   <p className="subtitle">This member has no skills defined yet.</p>
                 ) : (
                   <div style={{ display: 'flex', flexDirection: 'column', gap: '1rem' }}>
-                    {editedMemberSkills.map((skill) => (
+                    {memberSkills.map((skill) => (
                       <div
                         key={skill.skillId}
                         style={{
@@ -759,53 +734,29 @@ export default function ProjectDetail({ user }: { user?: User }) {
                             {skill.claimed ? 'Claimed' : 'Unclaimed'}
                           </span>
                         </div>
-                        <div style={{ display: 'flex', alignItems: 'center', gap: '1rem' }}>
-                          <label style={{ fontWeight: 500, color: '#374151' }}>
-                            Skill Score:
-                          </label>
-                          <input
-                            type="range"
-                            min="0"
-                            max="1"
-                            step="0.05"
-                            value={skill.score}
-                            onChange={(e) => updateMemberSkillScore(skill.skillId, parseFloat(e.target.value))}
-                            style={{ flex: 1 }}
-                          />
-                          <input
-                            type="number"
-                            min="0"
-                            max="1"
-                            step="0.05"
-                            value={skill.score.toFixed(2)}
-                            onChange={(e) => updateMemberSkillScore(skill.skillId, parseFloat(e.target.value) || 0)}
-                            style={{
-                              width: '80px',
-                              padding: '0.4rem',
-                              fontSize: '0.95rem',
-                              border: '1px solid var(--border-color)',
-                              borderRadius: '4px',
-                            }}
-                          />
-                        </div>
                         <div style={{ fontSize: '0.9rem', color: '#6b7280' }}>
                           Score: {(skill.score * 100).toFixed(0)}%
+                          {skill.verifiedAt && ` · Verified ${new Date(skill.verifiedAt).toLocaleDateString()}`}
                         </div>
+                        {skill.claimed && !skill.verifiedAt && (
+                          <div>
+                            <button
+                              className="btn btn-yes"
+                              onClick={() => verifyMemberSkill(skill.skillId)}
+                              disabled={verifyingSkillId !== null}
+                            >
+                              {verifyingSkillId === skill.skillId ? 'Verifying...' : 'Verify'}
+                            </button>
+                          </div>
+                        )}
                       </div>
                     ))}
                   </div>
                 )}
 
                 <div style={{ display: 'flex', justifyContent: 'flex-end', gap: '0.5rem', marginTop: '1.5rem' }}>
-                  <button className="btn" onClick={closeMemberSkillModal} disabled={savingMemberSkills}>
-                    Cancel
-                  </button>
-                  <button
-                    className="btn btn-yes"
-                    onClick={saveMemberSkills}
-                    disabled={savingMemberSkills || editedMemberSkills.length === 0}
-                  >
-                    {savingMemberSkills ? 'Saving...' : 'Save Changes'}
+                  <button className="btn" onClick={closeMemberSkillModal}>
+                    Close
                   </button>
                 </div>
               </>
@@ -1,7 +1,7 @@
-import { useState, useEffect } from 'react'
+import { useState, useEffect, useRef } from 'react'
 import { useNavigate } from 'react-router-dom'
 import { Project, User, ProjectMatch, CreateProjectRequest } from '../types'
-import { getAllProjects, findMatchesForVolunteer, getProjectEnrollments, createProject } from '../api'
+import { getAllProjects, findMatchesForVolunteer, getProjectEnrollments, createProject, ApiError } from '../api'
 import LocationAutocomplete from '../components/LocationAutocomplete'
 import { ProjectEnrollmentRequest } from '../components/ProjectEnrollmentRequest'
 
@@ -21,6 +21,9 @@ export default function Projects({ user }: { user?: User }) {
   const [newLat, setNewLat] = useState<number | undefined>(undefined)
   const [newLon, setNewLon] = useState<number | undefined>(undefined)
   const [newLocationName, setNewLocationName] = useState<string | undefined>(undefined)
+  // Sent with each attempt to create the project, so retrying after a
+  // dropped connection can't create it twice; replaced once the server answers
+  const createKey = useRef(crypto.randomUUID())
 
   // Coordinator filters/sorts
   const [filterStatus, setFilterStatus] = useState<string>('all')
@@ -194,7 +197,8 @@ export default function Projects({ user }: { user?: User }) {
                         longitude: newLon,
                         locationName: newLocationName,
                       }
-                      const created = await createProject(req)
+                      const created = await createProject(req, createKey.current)
+                      createKey.current = crypto.randomUUID()
                       setProjects([created, ...projects])
                       setShowCreate(false)
                       setNewName('')
@@ -203,6 +207,7 @@ export default function Projects({ user }: { user?: User }) {
                       setNewLon(undefined)
                       setNewLocationName(undefined)
                     } catch (e) {
+                      if (e instanceof ApiError) createKey.current = crypto.randomUUID()
                       setError(e instanceof Error ? e.message : 'Failed to create project')
                     } finally {
                       setCreating(false)
@@ -12,11 +12,21 @@ export interface User {
 
 export interface LoginRequest {
   email: string
+  password: string
 }
 
 export interface RegisterRequest {
   email: string
   name: string
+  password: string
+}
+
+export interface AuthResponse {
+  token: string
+  expiresAt: string
+  refreshToken: string
+  refreshExpiresAt: string
+  user: User
 }
 
 export interface ApiResponse<T> {
@@ -24,6 +34,16 @@ export interface ApiResponse<T> {
   error?: string
 }
 
+// Page is one page of a list endpoint; pass nextCursor back as ?cursor=
+// for the next one
+export interface Page<T> {
+  items: T[]
+  total: number
+  limit: number
+  offset: number
+  nextCursor?: string
+}
+
 export interface Skill {
   id: string
   name: string
@@ -38,6 +58,8 @@ export interface VolunteerSkill {
   skillName?: string
   claimed: boolean
   score: number // [0, 1]
+  verifiedBy?: string
+  verifiedAt?: string
   createdAt: string
   updatedAt: string
 }
@@ -47,6 +69,7 @@ export interface Project {
   name: string
   description: string
   coordinatorId?: string
+  organizationId?: string
   latitude?: number
   longitude?: number
   locationName?: string
@@ -66,6 +89,13 @@ export interface ProjectSkill {
   weight: number // [0, 1]
 }
 
+export interface MatchedSkill {
+  id: string
+  name: string
+  volunteerScore: number // [0, 1]
+  projectWeight: number // [0, 1]
+}
+
 export interface VolunteerMatch {
   volunteerId: string
   volunteerName: string
@@ -73,7 +103,7 @@ export interface VolunteerMatch {
   skillScore: number
   distanceKm: number
   combinedScore: number
-  matchedSkills: string[]
+  matchedSkills: MatchedSkill[]
   latitude?: number
   longitude?: number
   locationName?: string
@@ -85,7 +115,7 @@ export interface ProjectMatch {
   skillScore: number
   distanceKm: number
   combinedScore: number
-  matchedSkills: string[]
+  matchedSkills: MatchedSkill[]
   latitude?: number
   longitude?: number
   locationName?: string
@@ -123,7 +153,7 @@ export interface Enrollment {
   id: string
   volunteerId: string
   projectId: string
-  status: 'requested' | 'invited' | 'enrolled' | 'tl_rejected' | 'v_rejected'
+  status: 'requested' | 'invited' | 'enrolled' | 'waitlisted' | 'completed' | 'cancelled' | 'no_show' | 'tl_rejected' | 'v_rejected' | 'expired'
   initiatedBy: string
   message?: string
   responseMessage?: string
@@ -131,6 +161,8 @@ export interface Enrollment {
   updatedAt: string
   approvedAt?: string
   completedAt?: string
+  waitlistedAt?: string
+  expiresAt?: string
 }
 
 export interface EnrollmentWithDetails extends Enrollment {